@@ -135,6 +135,8 @@ func (c *Cache) EnsureCacheLocation(projectPath string) (string, error) {
 // If readOnly is false, opens in write mode and initializes schema.
 //
 // Database location: {cacheRoot}/{cacheKey}/branches/{branch}.db
+// In read-only mode the dependency corpus ({cacheRoot}/{cacheKey}/dependencies.db)
+// is attached as "deps" when it exists.
 //
 // This is the single source of truth for database connection management.
 // Both indexing (write mode) and MCP server (read mode) use this function.
@@ -166,13 +168,14 @@ func (c *Cache) OpenDatabase(projectPath string, branch string, readOnly bool) (
 		}
 	}
 
-	// Open database with appropriate mode
-	mode := ""
+	// Open database with appropriate mode. Readers attach the dependency
+	// corpus (if one was indexed) so searches can include dependencies.
+	var db *sql.DB
 	if readOnly {
-		mode = "?mode=ro"
+		db, err = storage.OpenWithDependencyCorpus(dbPath+"?mode=ro", storage.DependencyCorpusPath(settings.CacheLocation))
+	} else {
+		db, err = sql.Open("sqlite3", dbPath+"?"+storage.WriterDSNParams)
 	}
-
-	db, err := sql.Open("sqlite3", dbPath+mode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -185,6 +188,13 @@ func (c *Cache) OpenDatabase(projectPath string, branch string, readOnly bool) (
 
 	// Initialize schema if not read-only and schema doesn't exist
 	if !readOnly {
+		// WAL lets searches read while a reindex writes its batches
+		// (persists in the file).
+		if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to enable WAL mode: %w", err)
+		}
+
 		version, err := storage.GetSchemaVersion(db)
 		if err != nil {
 			db.Close()
@@ -196,6 +206,9 @@ func (c *Cache) OpenDatabase(projectPath string, branch string, readOnly bool) (
 				db.Close()
 				return nil, fmt.Errorf("failed to create schema: %w", err)
 			}
+		} else if err := storage.MigrateSchema(db); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to migrate schema: %w", err)
 		}
 	}
 
@@ -45,7 +45,7 @@ func TestOpenDatabase_WriteMode(t *testing.T) {
 	// Verify schema was created
 	version, err := storage.GetSchemaVersion(db)
 	require.NoError(t, err)
-	assert.Equal(t, "2.1", version, "schema should be initialized")
+	assert.Equal(t, storage.SchemaVersion, version, "schema should be initialized")
 
 	// Verify foreign keys are enabled
 	var fkEnabled int
@@ -93,7 +93,7 @@ func TestOpenDatabase_ReadMode(t *testing.T) {
 	var version string
 	err = readDB.QueryRow("SELECT value FROM cache_metadata WHERE key = 'schema_version'").Scan(&version)
 	require.NoError(t, err)
-	assert.Equal(t, "2.1", version)
+	assert.Equal(t, storage.SchemaVersion, version)
 
 	// Verify we cannot write to the database (read-only mode)
 	// Note: SQLite readonly enforcement can be platform/version specific.
@@ -222,7 +222,7 @@ func TestOpenDatabase_SchemaInitialization(t *testing.T) {
 	// Verify schema exists and is correct version
 	version, err := storage.GetSchemaVersion(db2)
 	require.NoError(t, err)
-	assert.Equal(t, "2.1", version)
+	assert.Equal(t, storage.SchemaVersion, version)
 
 	// Verify all expected tables exist
 	expectedTables := []string{
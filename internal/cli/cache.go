@@ -1,17 +1,28 @@
 package cli
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
 	"time"
 
 	"github.com/mvp-joe/project-cortex/internal/cache"
 	"github.com/mvp-joe/project-cortex/internal/config"
+	"github.com/mvp-joe/project-cortex/internal/embedcache"
 	"github.com/mvp-joe/project-cortex/internal/git"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 	"github.com/spf13/cobra"
 )
 
+var (
+	cacheStatsRecallSamples int
+)
+
+// recallK is the result count used when measuring vector search recall.
+const recallK = 10
+
 // cacheCmd represents the cache command group
 var cacheCmd = &cobra.Command{
 	Use:   "cache",
@@ -38,7 +49,8 @@ Shows:
   - Storage backend (SQLite or JSON)
   - Total cache size
   - Number of cached branches
-  - Last eviction timestamp`,
+  - Last eviction timestamp
+  - Shared embedding cache location and size`,
 	RunE: runCacheInfo,
 }
 
@@ -66,9 +78,14 @@ var cacheStatsCmd = &cobra.Command{
 Shows for each branch:
   - Branch name
   - Last accessed timestamp
-  - Database size (MB)
+  - Database size on disk (MB)
   - Number of chunks
-  - Eviction status (immortal, active, candidate)`,
+  - Vector index backend and quantization (e.g. exact/int8)
+  - Recall@10 of vector search against an exact full-precision scan, if measured
+  - Eviction status (immortal, active, candidate)
+
+Recall is only measured with --recall-samples N: N randomly chosen chunks are
+used as queries, each compared against a full scan of the branch's vectors.`,
 	RunE: runCacheStats,
 }
 
@@ -77,6 +94,8 @@ func init() {
 	cacheCmd.AddCommand(cacheInfoCmd)
 	cacheCmd.AddCommand(cacheCleanCmd)
 	cacheCmd.AddCommand(cacheStatsCmd)
+
+	cacheStatsCmd.Flags().IntVar(&cacheStatsRecallSamples, "recall-samples", 0, "Measure recall@10 with this many sample queries (each scans every vector)")
 }
 
 func runCacheInfo(cmd *cobra.Command, args []string) error {
@@ -121,6 +140,31 @@ func runCacheInfo(cmd *cobra.Command, args []string) error {
 	currentBranch := gitOps.GetCurrentBranch(projectPath)
 	fmt.Printf("\nCurrent Branch: %s\n", currentBranch)
 
+	// Shared embedding cache (machine-wide, across projects and branches)
+	globalCfg, err := config.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+	if !globalCfg.EmbeddingCache.Enabled {
+		fmt.Printf("\nEmbedding Cache: disabled\n")
+		return nil
+	}
+	embedCache, err := embedcache.NewCache(globalCfg.EmbeddingCache.Path, globalCfg.EmbeddingCache.MaxSizeMB)
+	if err != nil {
+		return fmt.Errorf("failed to open embedding cache: %w", err)
+	}
+	defer embedCache.Close()
+
+	embedStats, err := embedCache.Stats()
+	if err != nil {
+		return fmt.Errorf("failed to read embedding cache stats: %w", err)
+	}
+	fmt.Printf("\nEmbedding Cache: %s\n", embedCache.Path())
+	fmt.Printf("Embedding Cache Size: %.2f MB / %.2f MB (%d entries)\n",
+		float64(embedStats.SizeBytes)/(1024*1024),
+		float64(embedStats.MaxBytes)/(1024*1024),
+		embedStats.Entries)
+
 	return nil
 }
 
@@ -218,17 +262,31 @@ func runCacheStats(cmd *cobra.Command, args []string) error {
 		chunkCount   int
 		isImmortal   bool
 		isCurrent    bool
+		index        string
+		recall       string
 	}
 
 	branches := make([]branchStats, 0, len(metadata.Branches))
+	var totalSizeMB float64
 	for name, meta := range metadata.Branches {
+		// Prefer the actual file size; metadata may be stale after reindexing
+		sizeMB := cache.GetBranchDBSize(cachePath, name)
+		if sizeMB == 0 {
+			sizeMB = meta.SizeMB
+		}
+		totalSizeMB += sizeMB
+
+		index, recall := branchVectorIndexStats(filepath.Join(cachePath, "branches", name+".db"), cacheStatsRecallSamples)
+
 		branches = append(branches, branchStats{
 			name:         name,
 			lastAccessed: meta.LastAccessed,
-			sizeMB:       meta.SizeMB,
+			sizeMB:       sizeMB,
 			chunkCount:   meta.ChunkCount,
 			isImmortal:   meta.IsImmortal,
 			isCurrent:    name == currentBranch,
+			index:        index,
+			recall:       recall,
 		})
 	}
 
@@ -237,9 +295,9 @@ func runCacheStats(cmd *cobra.Command, args []string) error {
 	})
 
 	// Print header
-	fmt.Printf("%-25s %-20s %-10s %-10s %s\n",
-		"Branch", "Last Accessed", "Size", "Chunks", "Status")
-	fmt.Println("--------------------------------------------------------------------------------")
+	fmt.Printf("%-25s %-20s %-10s %-10s %-12s %-10s %s\n",
+		"Branch", "Last Accessed", "Size", "Chunks", "Index", "Recall@10", "Status")
+	fmt.Println("--------------------------------------------------------------------------------------------------------")
 
 	// Print each branch
 	for _, b := range branches {
@@ -257,22 +315,59 @@ func runCacheStats(cmd *cobra.Command, args []string) error {
 			status += " (current)"
 		}
 
-		fmt.Printf("%-25s %-20s %-10s %-10d %s\n",
+		fmt.Printf("%-25s %-20s %-10s %-10d %-12s %-10s %s\n",
 			truncate(b.name, 25),
 			formatRelativeTime(time.Since(b.lastAccessed)),
 			fmt.Sprintf("%.2f MB", b.sizeMB),
 			b.chunkCount,
+			b.index,
+			b.recall,
 			status)
 	}
 
 	fmt.Println()
 	fmt.Printf("Total: %.2f MB across %d branch(es)\n",
-		metadata.TotalSizeMB,
+		totalSizeMB,
 		len(branches))
 
 	return nil
 }
 
+// branchVectorIndexStats opens a branch database read-only and reports its
+// vector index ("backend/quantization") and, if samples > 0, measured recall@10.
+// Returns "-" for values that cannot be determined.
+func branchVectorIndexStats(dbPath string, samples int) (index string, recall string) {
+	index, recall = "-", "-"
+
+	if _, err := os.Stat(dbPath); err != nil {
+		return index, recall
+	}
+
+	storage.InitVectorExtension()
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	if err != nil {
+		return index, recall
+	}
+	defer db.Close()
+
+	stats, err := storage.GetVectorIndexStats(db)
+	if err != nil {
+		return index, recall
+	}
+	index = stats.Backend
+	if stats.Quantization != storage.QuantizationNone {
+		index += "/" + stats.Quantization
+	}
+
+	if samples > 0 {
+		if r, err := storage.MeasureVectorRecall(db, samples, recallK); err == nil {
+			recall = fmt.Sprintf("%.2f", r)
+		}
+	}
+
+	return index, recall
+}
+
 // formatRelativeTime formats a duration in a human-readable relative time format
 func formatRelativeTime(d time.Duration) string {
 	if d < time.Minute {
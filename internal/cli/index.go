@@ -12,8 +12,10 @@ import (
 	"github.com/mvp-joe/project-cortex/internal/cache"
 	"github.com/mvp-joe/project-cortex/internal/config"
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/embedcache"
 	"github.com/mvp-joe/project-cortex/internal/git"
 	"github.com/mvp-joe/project-cortex/internal/indexer"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 	"github.com/spf13/cobra"
 )
 
@@ -122,6 +124,11 @@ func runIndex(cmd *cobra.Command, args []string) error {
 	}
 	defer db.Close()
 
+	// Select the vector index backend (rebuilds the index when the backend changes)
+	if _, err := storage.ConfigureVectorIndex(db, cfg.Storage.VectorIndex, cfg.Storage.VectorQuantization); err != nil {
+		return fmt.Errorf("failed to configure vector index: %w", err)
+	}
+
 	if !quietFlag {
 		fmt.Println("✓ Database connection ready")
 	}
@@ -163,25 +170,69 @@ func runIndex(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create storage: %w", err)
 	}
 
-	// Create file discovery
+	// Create file discovery (project files plus content sources outside it)
 	discovery, err := indexer.NewFileDiscovery(rootDir, indexerConfig.CodePatterns, indexerConfig.DocsPatterns, indexerConfig.IgnorePatterns)
 	if err != nil {
 		return fmt.Errorf("failed to create file discovery: %w", err)
 	}
+	contentSources, err := indexer.NewContentSources(rootDir, indexerConfig.ContentSources)
+	if err != nil {
+		return fmt.Errorf("failed to configure content sources: %w", err)
+	}
+	discovery.WithContentSources(contentSources)
 
 	// Create change detector
 	changeDetector := indexer.NewChangeDetector(rootDir, storage, discovery)
 
-	// Create parser, chunker, formatter
-	parser := indexer.NewParser()
+	// Create parser (built-in and configured external languages), chunker, formatter
+	languages, err := indexer.NewLanguageRegistry(indexerConfig.Languages)
+	if err != nil {
+		return fmt.Errorf("failed to configure languages: %w", err)
+	}
+	parser := indexer.NewParser(indexer.WithLanguageRegistry(languages))
 	chunker := indexer.NewChunker(indexerConfig.DocChunkSize, indexerConfig.Overlap)
 	formatter := indexer.NewFormatter()
 
-	// Create processor
-	processor := indexer.NewProcessor(rootDir, parser, chunker, formatter, embedProvider, storage, progress)
+	// Open the shared embedding cache (optional: indexing proceeds without it)
+	processorOpts := []indexer.ProcessorOption{indexer.WithPipelineConfig(indexerConfig.Pipeline)}
+	if globalCfg.EmbeddingCache.Enabled {
+		embedCache, err := embedcache.NewCache(globalCfg.EmbeddingCache.Path, globalCfg.EmbeddingCache.MaxSizeMB)
+		if err != nil {
+			log.Printf("Warning: embedding cache unavailable: %v\n", err)
+		} else {
+			defer embedCache.Close()
+			processorOpts = append(processorOpts, indexer.WithEmbeddingCache(embedCache, cfg.Embedding.Model))
+		}
+	}
+
+	// Redact secrets before content is stored or embedded
+	var scanner *indexer.SecretScanner
+	if indexerConfig.ScanSecrets {
+		scanner, err = indexer.NewSecretScanner(indexerConfig.SecretPatterns)
+		if err != nil {
+			return fmt.Errorf("failed to configure secret scanning: %w", err)
+		}
+		processorOpts = append(processorOpts, indexer.WithSecretScanner(scanner))
+	}
 
-	// Create v2 indexer
-	idx := indexer.NewIndexerV2(rootDir, changeDetector, processor, storage, db)
+	// Create processor
+	processor := indexer.NewProcessor(rootDir, parser, chunker, formatter, embedProvider, storage, progress,
+		append(processorOpts, indexer.WithContentSourcePaths(contentSources))...)
+
+	// Create v2 indexer (optionally indexing imported dependencies into the dependency corpus)
+	indexerOpts := []indexer.IndexerV2Option{
+		indexer.WithTypedCallGraph(indexerConfig.TypedCallGraph),
+		indexer.WithContentSources(contentSources),
+		indexer.WithLanguages(languages),
+		indexer.WithSecretRedaction(scanner),
+	}
+	if indexerConfig.DependencyCorpus {
+		corpus := indexer.NewDependencyCorpus(cacheSettings.CacheLocation, rootDir, func(corpusRoot string, s indexer.Storage) indexer.Processor {
+			return indexer.NewProcessor(corpusRoot, parser, chunker, formatter, embedProvider, s, progress, processorOpts...)
+		})
+		indexerOpts = append(indexerOpts, indexer.WithDependencyCorpus(corpus))
+	}
+	idx := indexer.NewIndexerV2(rootDir, changeDetector, processor, storage, db, indexerOpts...)
 
 	// Check if watch mode is enabled
 	if watchFlag {
@@ -209,6 +260,7 @@ func runIndex(cmd *cobra.Command, args []string) error {
 			stats.FilesAdded, stats.FilesModified, stats.FilesDeleted, stats.FilesUnchanged)
 		fmt.Printf("  Chunks: %d code + %d docs = %d total\n",
 			stats.TotalCodeChunks, stats.TotalDocChunks, stats.TotalCodeChunks+stats.TotalDocChunks)
+		fmt.Printf("  Generation: %d\n", stats.Generation)
 		fmt.Printf("  Time: %v\n", stats.IndexingTime)
 	} else {
 		fmt.Printf("Indexing complete: %d chunks in %v\n",
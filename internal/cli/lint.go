@@ -0,0 +1,178 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mvp-joe/project-cortex/internal/cache"
+	"github.com/mvp-joe/project-cortex/internal/config"
+	"github.com/mvp-joe/project-cortex/internal/git"
+	"github.com/mvp-joe/project-cortex/internal/indexer"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintFormat   string
+	lintRule     string
+	lintSeverity string
+	lintNoStore  bool
+)
+
+// lintCmd runs the project's ast-grep lint rules
+var lintCmd = &cobra.Command{
+	Use:   "lint [paths...]",
+	Short: "Check the project against its .cortex/rules lint rules",
+	Long: `Run the ast-grep YAML rules in .cortex/rules/ over the project (or the
+given files, directories and globs) and report violations. Once the project
+is indexed, the content sources configured in paths.sources are checked
+too; their files are reported as @<label>/<path>.
+
+Each rule file is a standard ast-grep rule with an id, language, severity,
+message and optional fix. Results are stored for the current branch so the
+cortex_lint MCP tool can answer questions about them; pass --no-store to
+skip this. Exits non-zero when any error-severity violation is found.
+
+Formats: text (default), json, sarif
+
+Examples:
+  cortex lint
+  cortex lint internal/ --severity error
+  cortex lint --format sarif > cortex-lint.sarif`,
+	RunE: runLint,
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVarP(&lintFormat, "format", "f", "text", "Output format: text, json, sarif")
+	lintCmd.Flags().StringVar(&lintRule, "rule", "", "Only run the rule with this id")
+	lintCmd.Flags().StringVar(&lintSeverity, "severity", "", "Only report this severity (error, warning, info, hint)")
+	lintCmd.Flags().BoolVar(&lintNoStore, "no-store", false, "Don't store results in the branch database")
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if lintFormat != "text" && lintFormat != "json" && lintFormat != "sarif" {
+		return fmt.Errorf("invalid format: %s (valid: text, json, sarif)", lintFormat)
+	}
+
+	projectPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	req := &pattern.LintRequest{
+		FilePaths: args,
+		RuleID:    lintRule,
+		Severity:  lintSeverity,
+	}
+	// Once the project is indexed, its content sources are linted too
+	provider := pattern.NewAstGrepProvider()
+	currentBranch := git.NewOperations().GetCurrentBranch(projectPath)
+	if db, err := cache.NewCache("").OpenDatabase(projectPath, currentBranch, true); err == nil {
+		defer db.Close()
+		provider = provider.WithIndex(db)
+	}
+	result, err := provider.Lint(ctx, req, projectPath)
+	if err != nil {
+		return err
+	}
+
+	// Severity-filtered runs are partial: storing them would drop the other severities
+	if !lintNoStore && lintSeverity == "" {
+		if err := storeLintResult(projectPath, req, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to store lint results: %v\n", err)
+		}
+	}
+
+	switch lintFormat {
+	case "json":
+		jsonBytes, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+	case "sarif":
+		sarifBytes, err := pattern.FormatSARIF(result.Violations)
+		if err != nil {
+			return fmt.Errorf("failed to marshal SARIF: %w", err)
+		}
+		fmt.Println(string(sarifBytes))
+	default:
+		printLintText(result)
+	}
+
+	if result.Summary.Errors > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("lint failed: %d errors", result.Summary.Errors)
+	}
+	return nil
+}
+
+// storeLintResult replaces the current branch's stored violations for the linted paths.
+// A rule-filtered run only replaces that rule's violations.
+func storeLintResult(projectPath string, req *pattern.LintRequest, result *pattern.LintResult) error {
+	gitOps := git.NewOperations()
+	currentBranch := gitOps.GetCurrentBranch(projectPath)
+
+	c := cache.NewCache("")
+	db, err := c.OpenDatabase(projectPath, currentBranch, false) // false = write mode
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	var inScope func(string) bool
+	if len(req.FilePaths) > 0 {
+		inScope = pattern.LintPathMatcher(req.FilePaths)
+	}
+
+	violations := result.Violations
+	if req.RuleID != "" {
+		// Keep other rules' violations for the linted paths
+		stored, err := storage.QueryLintViolations(db, storage.LintViolationFilter{})
+		if err != nil {
+			return err
+		}
+		for _, v := range pattern.FromStoredViolations(stored) {
+			if v.RuleID != req.RuleID && (inScope == nil || inScope(v.FilePath)) {
+				violations = append(violations, v)
+			}
+		}
+	}
+
+	// Matched text is stored with its secrets redacted, like indexed content
+	cfg, err := config.LoadConfigFromDir(projectPath)
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	var secrets storage.SecretRedactor
+	if indexerConfig := cfg.ToIndexerConfig(projectPath); indexerConfig.ScanSecrets {
+		scanner, err := indexer.NewSecretScanner(indexerConfig.SecretPatterns)
+		if err != nil {
+			return fmt.Errorf("failed to configure secret scanning: %w", err)
+		}
+		secrets = scanner
+	}
+	return storage.ReplaceLintViolations(db, pattern.ToStoredViolations(violations), inScope, secrets)
+}
+
+// printLintText prints violations one per line, compiler style.
+func printLintText(result *pattern.LintResult) {
+	for _, v := range result.Violations {
+		fmt.Printf("%s:%d:%d: %s[%s] %s\n", v.FilePath, v.StartLine, v.StartColumn, v.Severity, v.RuleID, v.Message)
+		if v.Note != "" {
+			fmt.Println(indentLines(v.Note, "  "))
+		}
+		if v.Fix != "" {
+			fmt.Println(indentLines("fix: "+v.Fix, "  "))
+		}
+	}
+
+	s := result.Summary
+	fmt.Printf("%d errors, %d warnings, %d info (%d rules, %dms)\n", s.Errors, s.Warnings, s.Info, s.RulesLoaded, s.TookMs)
+}
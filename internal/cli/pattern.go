@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/spf13/cobra"
+)
+
+// patternCmd represents the pattern command group
+var patternCmd = &cobra.Command{
+	Use:   "pattern",
+	Short: "Structural pattern tools",
+	Long: `Tools for structural (ast-grep) pattern search and rewrite.
+
+Available commands:
+  apply  - Apply a rewrite diff produced by cortex_pattern`,
+}
+
+// patternApplyCmd applies a unified diff produced by cortex_pattern rewrite mode
+var patternApplyCmd = &cobra.Command{
+	Use:   "apply [patch-file]",
+	Short: "Apply a cortex_pattern rewrite diff",
+	Long: `Apply a unified diff produced by cortex_pattern's rewrite mode to the
+files in the current project.
+
+The patch is applied all-or-nothing: every path must stay inside the project
+and every hunk must match the current file contents, otherwise no file is
+changed. Reads the patch from stdin when no file is given (or the file is "-").
+
+Example:
+  cortex pattern apply rewrite.diff`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runPatternApply,
+}
+
+func init() {
+	rootCmd.AddCommand(patternCmd)
+	patternCmd.AddCommand(patternApplyCmd)
+}
+
+func runPatternApply(cmd *cobra.Command, args []string) error {
+	var data []byte
+	var err error
+	if len(args) == 0 || args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read patch: %w", err)
+	}
+
+	projectPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	result, err := pattern.ApplyPatch(projectPath, string(data))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range result.FilesChanged {
+		fmt.Printf("  M %s\n", path)
+	}
+	fmt.Printf("✓ Applied %d hunks to %d files\n", result.HunksApplied, len(result.FilesChanged))
+	return nil
+}
@@ -117,6 +117,18 @@ func (c *CLIProgressReporter) OnWritingChunks() {
 	log.Println("Writing chunk files...")
 }
 
+func (c *CLIProgressReporter) OnPipelineProgress(stages []indexer.StageStats) {
+	if c.quiet || c.fileBar == nil {
+		return
+	}
+	// Show live per-stage throughput next to the file bar
+	desc := "Indexing files"
+	for _, stage := range stages {
+		desc += fmt.Sprintf(" | %s %.0f %s/s", stage.Name, stage.Throughput(), stage.Unit)
+	}
+	c.fileBar.Describe(desc)
+}
+
 func (c *CLIProgressReporter) OnComplete(stats *indexer.ProcessingStats) {
 	if c.quiet {
 		return
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/cache"
+	"github.com/mvp-joe/project-cortex/internal/git"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/spf13/cobra"
+)
+
+var (
+	queryLanguage     string
+	queryFile         string
+	queryPaths        []string
+	queryContextLines int
+	queryLimit        int
+	queryJSON         bool
+)
+
+// queryCmd runs a tree-sitter query over the indexed files
+var queryCmd = &cobra.Command{
+	Use:   "query [query]",
+	Short: "Run a tree-sitter query over the indexed code",
+	Long: `Run a tree-sitter S-expression query over the files in the current
+branch's index. Queries run in-process using the grammars cortex indexes
+with, so no ast-grep download is needed.
+
+Captures are printed after each match. Captures whose name starts with "_"
+are used for predicates only and are not printed.
+
+Languages: typescript, tsx, javascript, jsx, python, rust, c, cpp, java, php, ruby
+
+Examples:
+  cortex query --lang python '(function_definition name: (identifier) @name (#match? @name "^test_"))'
+  cortex query --lang typescript --file queries/any-casts.scm --path 'src/**'`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runQuery,
+}
+
+func init() {
+	rootCmd.AddCommand(queryCmd)
+	queryCmd.Flags().StringVarP(&queryLanguage, "lang", "l", "", "Target language (required)")
+	queryCmd.Flags().StringVarP(&queryFile, "file", "f", "", "Read the query from a file instead of the argument")
+	queryCmd.Flags().StringSliceVarP(&queryPaths, "path", "p", nil, "File/glob filters (repeatable)")
+	queryCmd.Flags().IntVarP(&queryContextLines, "context", "C", pattern.DefaultContextLines, "Lines of context before/after each match (0-10)")
+	queryCmd.Flags().IntVar(&queryLimit, "limit", pattern.DefaultLimit, "Maximum matches to print (1-100)")
+	queryCmd.Flags().BoolVar(&queryJSON, "json", false, "Output as JSON")
+	queryCmd.MarkFlagRequired("lang")
+}
+
+func runQuery(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	source, err := loadQuerySource(args)
+	if err != nil {
+		return err
+	}
+
+	projectPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	gitOps := git.NewOperations()
+	currentBranch := gitOps.GetCurrentBranch(projectPath)
+
+	c := cache.NewCache("")
+	db, err := c.OpenDatabase(projectPath, currentBranch, true) // true = read-only mode
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	resp, err := pattern.NewTreeSitterQuerier(db).Query(ctx, &pattern.QueryRequest{
+		Query:        source,
+		Language:     queryLanguage,
+		FilePaths:    queryPaths,
+		ContextLines: &queryContextLines,
+		Limit:        &queryLimit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if queryJSON {
+		jsonBytes, err := json.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	for _, match := range resp.Matches {
+		fmt.Printf("%s:%d-%d\n", match.FilePath, match.StartLine, match.EndLine)
+		fmt.Println(indentLines(match.Context, "  "))
+
+		names := make([]string, 0, len(match.Metavars))
+		for name := range match.Metavars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  @%s = %s\n", name, strings.ReplaceAll(match.Metavars[name], "\n", "\\n"))
+		}
+		fmt.Println()
+	}
+
+	if resp.Total > len(resp.Matches) {
+		fmt.Printf("%d matches in %d files (showing first %d)\n", resp.Total, resp.Metadata.FilesScanned, len(resp.Matches))
+	} else {
+		fmt.Printf("%d matches in %d files\n", resp.Total, resp.Metadata.FilesScanned)
+	}
+	return nil
+}
+
+// loadQuerySource returns the query from --file or the positional argument.
+func loadQuerySource(args []string) (string, error) {
+	if queryFile != "" {
+		if len(args) > 0 {
+			return "", fmt.Errorf("pass the query either as an argument or with --file, not both")
+		}
+		data, err := os.ReadFile(queryFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read query file: %w", err)
+		}
+		return string(data), nil
+	}
+	if len(args) == 0 {
+		return "", fmt.Errorf("query is required (argument or --file)")
+	}
+	return args[0], nil
+}
+
+// indentLines prefixes every line of s with indent.
+func indentLines(s, indent string) string {
+	return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
+}
@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mvp-joe/project-cortex/internal/cache"
+	"github.com/mvp-joe/project-cortex/internal/git"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/spf13/cobra"
+)
+
+var (
+	secretsDetector string
+	secretsPath     string
+	secretsJSON     bool
+)
+
+// secretsCmd lists the secrets redacted while indexing
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "List where secrets were found and redacted while indexing",
+	Long: `List the secrets found in the current branch's index. While indexing,
+file content and chunk text are scanned for secrets (cloud and service keys,
+private keys, JWTs, credentials in URLs, high-entropy values and the custom
+patterns under secrets.patterns in .cortex/config.yml). Secrets are redacted
+before content is stored or sent to the embedding provider, and recorded
+here with a masked preview.
+
+Examples:
+  cortex secrets
+  cortex secrets --detector aws-access-key
+  cortex secrets --path config/ --json`,
+	Args: cobra.NoArgs,
+	RunE: runSecrets,
+}
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.Flags().StringVar(&secretsDetector, "detector", "", "Only list secrets found by this detector")
+	secretsCmd.Flags().StringVar(&secretsPath, "path", "", "Only list secrets in files under this path prefix")
+	secretsCmd.Flags().BoolVar(&secretsJSON, "json", false, "Output as JSON")
+}
+
+func runSecrets(cmd *cobra.Command, args []string) error {
+	projectPath, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	gitOps := git.NewOperations()
+	currentBranch := gitOps.GetCurrentBranch(projectPath)
+
+	c := cache.NewCache("")
+	db, err := c.OpenDatabase(projectPath, currentBranch, true) // true = read-only mode
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	findings, err := storage.QuerySecretFindings(db, storage.SecretFindingFilter{
+		Detector:   secretsDetector,
+		PathPrefix: secretsPath,
+	})
+	if err != nil {
+		return err
+	}
+
+	if secretsJSON {
+		out := make([]secretFindingJSON, len(findings))
+		for i, f := range findings {
+			out[i] = secretFindingJSON(f)
+		}
+		jsonBytes, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(jsonBytes))
+		return nil
+	}
+
+	printSecretsText(findings)
+	return nil
+}
+
+// secretFindingJSON is a finding in --json output.
+type secretFindingJSON struct {
+	FilePath string `json:"file_path"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Detector string `json:"detector"`
+	Preview  string `json:"preview"`
+}
+
+// printSecretsText prints findings one per line, compiler style.
+func printSecretsText(findings []storage.SecretFinding) {
+	files := make(map[string]bool)
+	for _, f := range findings {
+		fmt.Printf("%s:%d:%d: %s %s\n", f.FilePath, f.Line, f.Column, f.Detector, f.Preview)
+		files[f.FilePath] = true
+	}
+	fmt.Printf("%d secrets in %d files\n", len(findings), len(files))
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mvp-joe/project-cortex/internal/embed"
 )
@@ -13,6 +14,13 @@ type Config struct {
 	Paths     PathsConfig     `yaml:"paths" mapstructure:"paths"`
 	Chunking  ChunkingConfig  `yaml:"chunking" mapstructure:"chunking"`
 	Storage   StorageConfig   `yaml:"storage" mapstructure:"storage"`
+	Indexing  IndexingConfig  `yaml:"indexing" mapstructure:"indexing"`
+
+	// Languages declares external extractors by language name
+	Languages map[string]LanguageConfig `yaml:"languages" mapstructure:"languages"`
+
+	// Secrets configures redaction of secrets before content is stored or embedded
+	Secrets SecretsConfig `yaml:"secrets" mapstructure:"secrets"`
 }
 
 // EmbeddingConfig configures the embedding provider.
@@ -25,14 +33,26 @@ type EmbeddingConfig struct {
 
 // PathsConfig defines which files to index and which to ignore.
 type PathsConfig struct {
-	Code   []string `yaml:"code" mapstructure:"code"`     // glob patterns for code files
-	Docs   []string `yaml:"docs" mapstructure:"docs"`     // glob patterns for documentation
-	Ignore []string `yaml:"ignore" mapstructure:"ignore"` // glob patterns to ignore
+	Code    []string       `yaml:"code" mapstructure:"code"`       // glob patterns for code files
+	Docs    []string       `yaml:"docs" mapstructure:"docs"`       // glob patterns for documentation
+	Ignore  []string       `yaml:"ignore" mapstructure:"ignore"`   // glob patterns to ignore
+	Sources []SourceConfig `yaml:"sources" mapstructure:"sources"` // content outside the project root
+}
+
+// SourceConfig is a directory outside the project root (a wiki checkout, a
+// design-doc repository, API specs in a sibling repository) indexed into the
+// same database. Its files are stored as "@<label>/<path>".
+type SourceConfig struct {
+	Label   string   `yaml:"label" mapstructure:"label"`     // lowercase name used in file paths and MCP label filters
+	Root    string   `yaml:"root" mapstructure:"root"`       // directory to index (relative paths are resolved against the project root)
+	Include []string `yaml:"include" mapstructure:"include"` // glob patterns to index (default: paths.code and paths.docs)
+	Ignore  []string `yaml:"ignore" mapstructure:"ignore"`   // glob patterns to ignore
+	Watch   bool     `yaml:"watch" mapstructure:"watch"`     // re-index on change while the daemon runs
 }
 
 // ChunkingConfig defines how content is chunked for indexing.
 type ChunkingConfig struct {
-	Strategies    []string `yaml:"strategies" mapstructure:"strategies"`           // e.g., ["symbols", "definitions", "data"]
+	Strategies    []string `yaml:"strategies" mapstructure:"strategies"`           // e.g., ["symbols", "definitions", "data", "docstring"]
 	DocChunkSize  int      `yaml:"doc_chunk_size" mapstructure:"doc_chunk_size"`   // max tokens per doc chunk
 	CodeChunkSize int      `yaml:"code_chunk_size" mapstructure:"code_chunk_size"` // max characters per code chunk
 	Overlap       int      `yaml:"overlap" mapstructure:"overlap"`                 // token overlap between chunks
@@ -45,6 +65,45 @@ type StorageConfig struct {
 	BranchCacheEnabled bool    `yaml:"branch_cache_enabled" mapstructure:"branch_cache_enabled"` // Enable branch optimization
 	CacheMaxAgeDays    int     `yaml:"cache_max_age_days" mapstructure:"cache_max_age_days"`     // Delete branches older than this
 	CacheMaxSizeMB     float64 `yaml:"cache_max_size_mb" mapstructure:"cache_max_size_mb"`       // Max cache size per project
+	VectorIndex        string  `yaml:"vector_index" mapstructure:"vector_index"`                 // "exact" (sqlite-vec) or "ivf" (approximate, for very large repos)
+	VectorQuantization string  `yaml:"vector_quantization" mapstructure:"vector_quantization"`   // "none", "int8" or "binary" (exact backend only, rescored at full precision)
+}
+
+// IndexingConfig controls concurrency of the indexing pipeline and optional
+// analysis passes. Zero values select defaults (parse_workers defaults to the
+// number of CPUs).
+type IndexingConfig struct {
+	ParseWorkers     int  `yaml:"parse_workers" mapstructure:"parse_workers"`         // parallel parser workers
+	EmbedWorkers     int  `yaml:"embed_workers" mapstructure:"embed_workers"`         // concurrent embedding requests
+	EmbedBatchSize   int  `yaml:"embed_batch_size" mapstructure:"embed_batch_size"`   // chunks per embedding request
+	WriteBatchSize   int  `yaml:"write_batch_size" mapstructure:"write_batch_size"`   // chunks per database transaction
+	TypedCallGraph   bool `yaml:"typed_call_graph" mapstructure:"typed_call_graph"`   // resolve Go calls with go/types (slower, precise)
+	DependencyCorpus bool `yaml:"dependency_corpus" mapstructure:"dependency_corpus"` // index sources of imported third-party packages found on disk
+}
+
+// LanguageConfig declares a language extracted by an external command that
+// speaks the extractor protocol (JSON over stdin/stdout, see
+// docs/languages.md). Entries without a command are ignored.
+type LanguageConfig struct {
+	Extensions []string `yaml:"extensions" mapstructure:"extensions"` // file extensions with leading dot, e.g. ".kt"
+	Filenames  []string `yaml:"filenames" mapstructure:"filenames"`   // base name patterns, e.g. "BUILD"
+	Shebangs   []string `yaml:"shebangs" mapstructure:"shebangs"`     // interpreters named on a "#!" line
+	Command    []string `yaml:"command" mapstructure:"command"`       // extractor executable and arguments (relative paths resolve against the project root)
+	Graph      bool     `yaml:"graph" mapstructure:"graph"`           // the extractor returns graph data (types, functions, calls)
+}
+
+// SecretsConfig controls secret scanning. Secrets found by the built-in
+// detectors and the custom patterns are redacted from stored file content
+// and chunk text before they reach the cache or the embedding provider.
+type SecretsConfig struct {
+	Enabled  bool                  `yaml:"enabled" mapstructure:"enabled"`   // scan and redact (default: true)
+	Patterns []SecretPatternConfig `yaml:"patterns" mapstructure:"patterns"` // custom detectors
+}
+
+// SecretPatternConfig is a custom secret detector.
+type SecretPatternConfig struct {
+	Name    string `yaml:"name" mapstructure:"name"`       // reported as the finding's detector
+	Pattern string `yaml:"pattern" mapstructure:"pattern"` // Go regular expression; only the first capture group is redacted if it has one
 }
 
 // Default returns a configuration with sensible defaults.
@@ -68,11 +127,26 @@ func Default() *Config {
 				"**/*.c",
 				"**/*.cpp",
 				"**/*.cc",
+				"**/*.cxx",
 				"**/*.h",
 				"**/*.hpp",
+				"**/*.hh",
+				"**/*.hxx",
 				"**/*.php",
 				"**/*.rb",
 				"**/*.java",
+				"**/*.ipynb",
+				"**/*.vue",
+				"**/*.svelte",
+				"**/*.proto",
+				"**/*.graphql",
+				"**/*.graphqls",
+				"**/*.gql",
+				"**/*.yaml",
+				"**/*.yml",
+				"**/*.json",
+				"**/*.toml",
+				"**/.env.{example,sample,template}",
 			},
 			Docs: []string{
 				"**/*.md",
@@ -88,10 +162,17 @@ func Default() *Config {
 				"__pycache__/**",
 				"*.test",
 				"*.pyc",
+				"**/package-lock.json",
+				"**/npm-shrinkwrap.json",
+				"**/pnpm-lock.yaml",
+				"**/*.min.json",
+				"**/testdata/**",
+				"**/fixtures/**",
+				"**/.ipynb_checkpoints/**",
 			},
 		},
 		Chunking: ChunkingConfig{
-			Strategies:    []string{"symbols", "definitions", "data"},
+			Strategies:    []string{"symbols", "definitions", "data", "docstring"},
 			DocChunkSize:  800,
 			CodeChunkSize: 2000,
 			Overlap:       100,
@@ -101,29 +182,61 @@ func Default() *Config {
 			BranchCacheEnabled: true,
 			CacheMaxAgeDays:    30,
 			CacheMaxSizeMB:     500,
+			VectorIndex:        "exact",
+			VectorQuantization: "none",
+		},
+		Indexing: IndexingConfig{
+			ParseWorkers:     0, // 0 means runtime.NumCPU()
+			EmbedWorkers:     2,
+			EmbedBatchSize:   50,
+			WriteBatchSize:   1000,
+			TypedCallGraph:   false, // Syntactic call extraction
+			DependencyCorpus: false, // Project sources only
+		},
+		Secrets: SecretsConfig{
+			Enabled: true,
 		},
 	}
 }
 
-// GetSourceExtensions extracts unique file extensions from code and docs patterns.
+// GetSourceExtensions extracts unique file extensions from code, docs and content source patterns.
 // Returns extensions with leading dot (e.g., []string{".go", ".ts", ".md"}).
 func (c *Config) GetSourceExtensions() []string {
 	extMap := make(map[string]bool)
 
 	// Extract extensions from code patterns
 	for _, pattern := range c.Paths.Code {
-		if ext := extractExtension(pattern); ext != "" {
+		for _, ext := range extractExtensions(pattern) {
 			extMap[ext] = true
 		}
 	}
 
 	// Extract extensions from docs patterns
 	for _, pattern := range c.Paths.Docs {
-		if ext := extractExtension(pattern); ext != "" {
+		for _, ext := range extractExtensions(pattern) {
 			extMap[ext] = true
 		}
 	}
 
+	// Extract extensions from content source include patterns
+	for _, src := range c.Paths.Sources {
+		for _, pattern := range src.Include {
+			for _, ext := range extractExtensions(pattern) {
+				extMap[ext] = true
+			}
+		}
+	}
+
+	// Add extensions of external languages
+	for _, lang := range c.Languages {
+		if len(lang.Command) == 0 {
+			continue
+		}
+		for _, ext := range lang.Extensions {
+			extMap[strings.ToLower(ext)] = true
+		}
+	}
+
 	// Convert map to slice
 	extensions := make([]string, 0, len(extMap))
 	for ext := range extMap {
@@ -133,16 +246,30 @@ func (c *Config) GetSourceExtensions() []string {
 	return extensions
 }
 
-// extractExtension extracts the file extension from a glob pattern.
-// Returns empty string if pattern doesn't match a simple extension pattern.
-// Examples: "**/*.go" -> ".go", "*.ts" -> ".ts", "**/*.tsx" -> ".tsx"
-func extractExtension(pattern string) string {
-	// Find the last occurrence of *.ext pattern
-	for i := len(pattern) - 1; i >= 1; i-- {
-		if pattern[i] == '.' && pattern[i-1] == '*' {
-			// Extract from the dot to the end
-			return pattern[i:]
+// extractExtensions extracts the file extensions a glob pattern matches,
+// expanding {a,b} alternatives. Patterns naming files ("**/.env.example")
+// yield their extension.
+// Examples: "**/*.go" -> ".go", "**/*.{yaml,yml}" -> ".yaml", ".yml", "**/*" -> none
+func extractExtensions(pattern string) []string {
+	var extensions []string
+	for _, p := range expandBraces(pattern[strings.LastIndex(pattern, "/")+1:]) {
+		if i := strings.LastIndex(p, "."); i >= 0 && i < len(p)-1 && !strings.ContainsAny(p[i:], "*?[") {
+			extensions = append(extensions, p[i:])
 		}
 	}
-	return ""
+	return extensions
+}
+
+// expandBraces expands the {a,b} alternatives of a glob pattern (not nested).
+func expandBraces(pattern string) []string {
+	start := strings.Index(pattern, "{")
+	end := strings.Index(pattern, "}")
+	if start < 0 || end < start {
+		return []string{pattern}
+	}
+	var expanded []string
+	for _, alt := range strings.Split(pattern[start+1:end], ",") {
+		expanded = append(expanded, expandBraces(pattern[:start]+alt+pattern[end+1:])...)
+	}
+	return expanded
 }
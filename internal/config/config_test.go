@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/indexer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -31,7 +32,11 @@ import (
 // - Validate() rejects overlap >= doc_chunk_size
 // - Validate() rejects empty strategies list
 // - Validate() rejects unknown strategy names
+// - Validate() rejects invalid content sources and external languages
+// - ToIndexerConfig() passes external languages on and discovers their files
+// - LoadConfig() reads custom secret patterns; Validate() rejects unnamed or invalid ones
 // - Validate() returns multiple errors for multiple invalid fields
+// - GetSourceExtensions() expands brace alternatives and file name patterns
 
 func TestDefault_ReturnsValidConfiguration(t *testing.T) {
 	// Test: Default() returns valid configuration
@@ -46,7 +51,7 @@ func TestDefault_ReturnsValidConfiguration(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("http://%s:%d/embed", embed.DefaultEmbedServerHost, embed.DefaultEmbedServerPort), cfg.Embedding.Endpoint)
 
 	// Verify chunking defaults
-	assert.Equal(t, []string{"symbols", "definitions", "data"}, cfg.Chunking.Strategies)
+	assert.Equal(t, []string{"symbols", "definitions", "data", "docstring"}, cfg.Chunking.Strategies)
 	assert.Equal(t, 800, cfg.Chunking.DocChunkSize)
 	assert.Equal(t, 2000, cfg.Chunking.CodeChunkSize)
 	assert.Equal(t, 100, cfg.Chunking.Overlap)
@@ -56,6 +61,14 @@ func TestDefault_ReturnsValidConfiguration(t *testing.T) {
 	assert.True(t, cfg.Storage.BranchCacheEnabled)
 	assert.Equal(t, 30, cfg.Storage.CacheMaxAgeDays)
 	assert.Equal(t, 500.0, cfg.Storage.CacheMaxSizeMB)
+	assert.Equal(t, "exact", cfg.Storage.VectorIndex)
+	assert.Equal(t, "none", cfg.Storage.VectorQuantization)
+
+	// Verify indexing pipeline defaults (0 parse workers = number of CPUs)
+	assert.Equal(t, 0, cfg.Indexing.ParseWorkers)
+	assert.Equal(t, 2, cfg.Indexing.EmbedWorkers)
+	assert.Equal(t, 50, cfg.Indexing.EmbedBatchSize)
+	assert.Equal(t, 1000, cfg.Indexing.WriteBatchSize)
 
 	// Verify paths have reasonable defaults
 	assert.NotEmpty(t, cfg.Paths.Code)
@@ -312,6 +325,7 @@ storage:
   branch_cache_enabled: false
   cache_max_age_days: 45
   cache_max_size_mb: 750.5
+  vector_index: ivf
 `
 
 	configPath := filepath.Join(cortexDir, "config.yml")
@@ -328,6 +342,7 @@ storage:
 	assert.False(t, cfg.Storage.BranchCacheEnabled)
 	assert.Equal(t, 45, cfg.Storage.CacheMaxAgeDays)
 	assert.Equal(t, 750.5, cfg.Storage.CacheMaxSizeMB)
+	assert.Equal(t, "ivf", cfg.Storage.VectorIndex)
 }
 
 func TestLoadConfig_ReturnsErrorForMalformedYaml(t *testing.T) {
@@ -515,6 +530,141 @@ func TestValidate_RejectsUnknownStrategy(t *testing.T) {
 	assert.Contains(t, err.Error(), "unknown-strategy")
 }
 
+func TestValidate_RejectsUnknownVectorIndex(t *testing.T) {
+	// Test: Unknown vector index backend is rejected
+	cfg := Default()
+	cfg.Storage.VectorIndex = "hnsw"
+
+	err := Validate(cfg)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidVectorIndex)
+}
+
+func TestValidate_VectorQuantization(t *testing.T) {
+	// Test: Quantization accepts none/int8/binary on exact, rejects unknown modes and ivf
+	cfg := Default()
+	for _, q := range []string{"none", "int8", "binary"} {
+		cfg.Storage.VectorQuantization = q
+		assert.NoError(t, Validate(cfg), q)
+	}
+
+	cfg.Storage.VectorQuantization = "fp16"
+	assert.ErrorIs(t, Validate(cfg), ErrInvalidVectorIndex)
+
+	cfg.Storage.VectorIndex = "ivf"
+	cfg.Storage.VectorQuantization = "int8"
+	assert.ErrorIs(t, Validate(cfg), ErrInvalidVectorIndex)
+}
+
+func TestValidate_RejectsNegativeIndexingValues(t *testing.T) {
+	// Test: Negative pipeline worker counts and batch sizes are rejected
+	for _, mutate := range []func(*IndexingConfig){
+		func(c *IndexingConfig) { c.ParseWorkers = -1 },
+		func(c *IndexingConfig) { c.EmbedWorkers = -1 },
+		func(c *IndexingConfig) { c.EmbedBatchSize = -1 },
+		func(c *IndexingConfig) { c.WriteBatchSize = -1 },
+	} {
+		cfg := Default()
+		mutate(&cfg.Indexing)
+		assert.ErrorIs(t, Validate(cfg), ErrInvalidIndexing)
+	}
+}
+
+func TestValidate_RejectsInvalidSources(t *testing.T) {
+	// Test: Content sources need a valid, unique label and a root
+	for _, sources := range [][]SourceConfig{
+		{{Label: "project", Root: "../wiki"}},
+		{{Label: "Wiki", Root: "../wiki"}},
+		{{Label: "wiki", Root: "../wiki"}, {Label: "wiki", Root: "../wiki2"}},
+		{{Label: "wiki"}},
+	} {
+		cfg := Default()
+		cfg.Paths.Sources = sources
+		assert.ErrorIs(t, Validate(cfg), ErrInvalidSource)
+	}
+
+	cfg := Default()
+	cfg.Paths.Sources = []SourceConfig{{Label: "wiki", Root: "../wiki"}, {Label: "api-specs.v2", Root: "/srv/specs"}}
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestValidate_Languages(t *testing.T) {
+	// Test: External languages need a command and a way to detect their files;
+	// entries without a command are settings and ignored
+	for _, lang := range []LanguageConfig{
+		{Extensions: []string{".kt"}},
+		{Command: []string{"cortex-kotlin"}},
+		{Extensions: []string{"kt"}, Command: []string{"cortex-kotlin"}},
+		{Extensions: []string{".kt"}, Command: []string{""}},
+	} {
+		cfg := Default()
+		cfg.Languages = map[string]LanguageConfig{"kotlin": lang}
+		assert.ErrorIs(t, Validate(cfg), ErrInvalidLanguage)
+	}
+
+	cfg := Default()
+	cfg.Languages = map[string]LanguageConfig{
+		"kotlin": {Extensions: []string{".kt"}, Command: []string{"cortex-kotlin"}},
+		"php":    {Command: []string{"cortex-php"}}, // Built-in detection
+		"go":     {},
+	}
+	assert.NoError(t, Validate(cfg))
+}
+
+func TestToIndexerConfig_Languages(t *testing.T) {
+	// Test: External languages are passed on sorted, their files discovered,
+	// and relative command paths resolved against the project root
+	cfg := Default()
+	cfg.Languages = map[string]LanguageConfig{
+		"kotlin": {Extensions: []string{".kt", ".kts"}, Command: []string{"./tools/kotlin-extractor", "--json"}, Graph: true},
+		"bazel":  {Filenames: []string{"BUILD"}, Command: []string{"cortex-bazel"}},
+		"go":     {},
+	}
+
+	indexerCfg := cfg.ToIndexerConfig("/src/app")
+	require.Len(t, indexerCfg.Languages, 2)
+	assert.Equal(t, "bazel", indexerCfg.Languages[0].Name)
+	assert.Equal(t, []string{"cortex-bazel"}, indexerCfg.Languages[0].Command)
+	assert.Equal(t, []string{filepath.Join("/src/app", "tools/kotlin-extractor"), "--json"}, indexerCfg.Languages[1].Command)
+	assert.True(t, indexerCfg.Languages[1].Graph)
+	assert.Subset(t, indexerCfg.CodePatterns, []string{"**/*.go", "**/*.kt", "**/*.kts", "**/BUILD"})
+	assert.Subset(t, cfg.GetSourceExtensions(), []string{".kt", ".kts"})
+}
+
+func TestLoadConfig_Secrets(t *testing.T) {
+	// Test: Secret scanning is on by default and reads custom patterns, which
+	// are passed on to the indexer; unnamed, duplicate and invalid patterns are rejected
+	tempDir := t.TempDir()
+	cortexDir := filepath.Join(tempDir, ".cortex")
+	require.NoError(t, os.MkdirAll(cortexDir, 0755))
+
+	configContent := `
+secrets:
+  patterns:
+    - name: internal-token
+      pattern: 'itk_[a-z0-9]{32}'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(cortexDir, "config.yml"), []byte(configContent), 0644))
+
+	cfg, err := NewLoader(tempDir).Load()
+	require.NoError(t, err)
+	assert.True(t, cfg.Secrets.Enabled)
+
+	indexerCfg := cfg.ToIndexerConfig(tempDir)
+	assert.True(t, indexerCfg.ScanSecrets)
+	assert.Equal(t, []indexer.SecretPatternConfig{{Name: "internal-token", Pattern: "itk_[a-z0-9]{32}"}}, indexerCfg.SecretPatterns)
+
+	for _, patterns := range [][]SecretPatternConfig{
+		{{Pattern: "itk_[a-z0-9]{32}"}},
+		{{Name: "internal-token", Pattern: "itk_("}},
+		{{Name: "internal-token", Pattern: "itk_[a-z]+"}, {Name: "internal-token", Pattern: "itk2_[a-z]+"}},
+	} {
+		cfg := Default()
+		cfg.Secrets.Patterns = patterns
+		assert.ErrorIs(t, Validate(cfg), ErrInvalidSecretPattern)
+	}
+}
+
 func TestValidate_ReturnsMultipleErrorsForMultipleInvalidFields(t *testing.T) {
 	// Test: Multiple validation errors are all reported
 	cfg := &Config{
@@ -545,3 +695,13 @@ func TestValidate_ReturnsMultipleErrorsForMultipleInvalidFields(t *testing.T) {
 }
 
 // Backend validation tests removed - SQLite is now the only storage backend
+
+func TestGetSourceExtensions_Patterns(t *testing.T) {
+	// Test: Brace alternatives and file name patterns yield the extensions
+	// the file watcher listens for
+	cfg := &Config{Paths: PathsConfig{
+		Code: []string{"**/*.go", "**/openapi.{yaml,json}", "**/.env.{example,sample}", "**/Rakefile", "**/*"},
+		Docs: []string{"docs/*.md"},
+	}}
+	assert.ElementsMatch(t, []string{".go", ".yaml", ".json", ".example", ".sample", ".md"}, cfg.GetSourceExtensions())
+}
@@ -51,9 +51,10 @@ package config
 // This configuration is separate from per-project settings and controls
 // daemon behavior across all projects on the machine.
 type GlobalConfig struct {
-	IndexerDaemon IndexerDaemonConfig `yaml:"indexer_daemon" mapstructure:"indexer_daemon"`
-	EmbedDaemon   EmbedDaemonConfig   `yaml:"embed_daemon" mapstructure:"embed_daemon"`
-	Cache         GlobalCacheConfig   `yaml:"cache" mapstructure:"cache"`
+	IndexerDaemon  IndexerDaemonConfig  `yaml:"indexer_daemon" mapstructure:"indexer_daemon"`
+	EmbedDaemon    EmbedDaemonConfig    `yaml:"embed_daemon" mapstructure:"embed_daemon"`
+	Cache          GlobalCacheConfig    `yaml:"cache" mapstructure:"cache"`
+	EmbeddingCache EmbeddingCacheConfig `yaml:"embedding_cache" mapstructure:"embedding_cache"`
 }
 
 // IndexerDaemonConfig holds indexer daemon settings.
@@ -74,3 +75,11 @@ type EmbedDaemonConfig struct {
 type GlobalCacheConfig struct {
 	BaseDir string `yaml:"base_dir" mapstructure:"base_dir"` // Base directory for cache (~/.cortex/cache)
 }
+
+// EmbeddingCacheConfig holds settings for the content-addressed embedding cache
+// shared by all projects, branches and worktrees on the machine.
+type EmbeddingCacheConfig struct {
+	Enabled   bool    `yaml:"enabled" mapstructure:"enabled"`         // Consult the cache before embedding chunks
+	Path      string  `yaml:"path" mapstructure:"path"`               // SQLite database path (~/.cortex/embeddings.db)
+	MaxSizeMB float64 `yaml:"max_size_mb" mapstructure:"max_size_mb"` // Size cap; least recently used entries are evicted beyond it
+}
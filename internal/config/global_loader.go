@@ -66,6 +66,11 @@ func bindGlobalEnvVars(v *viper.Viper) {
 
 	// Cache configuration
 	v.BindEnv("cache.base_dir")
+
+	// Embedding cache configuration
+	v.BindEnv("embedding_cache.enabled")
+	v.BindEnv("embedding_cache.path")
+	v.BindEnv("embedding_cache.max_size_mb")
 }
 
 // setGlobalDefaults configures viper with default values for global config.
@@ -82,4 +87,9 @@ func setGlobalDefaults(v *viper.Viper, cortexDir string) {
 
 	// Cache defaults
 	v.SetDefault("cache.base_dir", filepath.Join(cortexDir, "cache"))
+
+	// Embedding cache defaults
+	v.SetDefault("embedding_cache.enabled", true)
+	v.SetDefault("embedding_cache.path", filepath.Join(cortexDir, "embeddings.db"))
+	v.SetDefault("embedding_cache.max_size_mb", 1024)
 }
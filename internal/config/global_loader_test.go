@@ -36,6 +36,9 @@ func TestLoadGlobalConfig_MissingFile(t *testing.T) {
 	assert.Equal(t, 600, cfg.EmbedDaemon.IdleTimeout)
 	assert.Equal(t, filepath.Join(cortexDir, "models"), cfg.EmbedDaemon.ModelDir)
 	assert.Equal(t, filepath.Join(cortexDir, "cache"), cfg.Cache.BaseDir)
+	assert.True(t, cfg.EmbeddingCache.Enabled)
+	assert.Equal(t, filepath.Join(cortexDir, "embeddings.db"), cfg.EmbeddingCache.Path)
+	assert.Equal(t, 1024.0, cfg.EmbeddingCache.MaxSizeMB)
 }
 
 func TestLoadGlobalConfig_WithFile(t *testing.T) {
@@ -60,6 +63,11 @@ embed_daemon:
 
 cache:
   base_dir: /custom/cache
+
+embedding_cache:
+  enabled: false
+  path: /custom/embeddings.db
+  max_size_mb: 256
 `
 
 	configPath := filepath.Join(cortexDir, "config.yml")
@@ -71,6 +79,9 @@ cache:
 	require.NotNil(t, cfg)
 
 	// Verify loaded values
+	assert.False(t, cfg.EmbeddingCache.Enabled)
+	assert.Equal(t, "/custom/embeddings.db", cfg.EmbeddingCache.Path)
+	assert.Equal(t, 256.0, cfg.EmbeddingCache.MaxSizeMB)
 	assert.Equal(t, "/custom/indexer.sock", cfg.IndexerDaemon.SocketPath)
 	assert.Equal(t, 60, cfg.IndexerDaemon.StartupTimeout)
 	assert.Equal(t, "/custom/embed.sock", cfg.EmbedDaemon.SocketPath)
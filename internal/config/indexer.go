@@ -1,6 +1,10 @@
 package config
 
 import (
+	"path/filepath"
+	"sort"
+	"strings"
+
 	"github.com/mvp-joe/project-cortex/internal/indexer"
 )
 
@@ -9,9 +13,10 @@ import (
 func (c *Config) ToIndexerConfig(rootDir string) *indexer.Config {
 	return &indexer.Config{
 		RootDir:           rootDir,
-		CodePatterns:      c.Paths.Code,
+		CodePatterns:      c.codePatterns(),
 		DocsPatterns:      c.Paths.Docs,
 		IgnorePatterns:    c.Paths.Ignore,
+		ContentSources:    c.contentSources(rootDir),
 		ChunkStrategies:   c.Chunking.Strategies,
 		DocChunkSize:      c.Chunking.DocChunkSize,
 		CodeChunkSize:     c.Chunking.CodeChunkSize,
@@ -22,5 +27,112 @@ func (c *Config) ToIndexerConfig(rootDir string) *indexer.Config {
 		EmbeddingDims:     c.Embedding.Dimensions,
 		EmbeddingEndpoint: c.Embedding.Endpoint,
 		EmbeddingBinary:   "cortex-embed",
+		Pipeline: indexer.PipelineConfig{
+			ParseWorkers:   c.Indexing.ParseWorkers,
+			EmbedWorkers:   c.Indexing.EmbedWorkers,
+			EmbedBatchSize: c.Indexing.EmbedBatchSize,
+			WriteBatchSize: c.Indexing.WriteBatchSize,
+		},
+		TypedCallGraph:   c.Indexing.TypedCallGraph,
+		DependencyCorpus: c.Indexing.DependencyCorpus,
+		Languages:        c.externalLanguages(rootDir),
+		ScanSecrets:      c.Secrets.Enabled,
+		SecretPatterns:   c.secretPatterns(),
+	}
+}
+
+// secretPatterns converts the custom secret detectors.
+func (c *Config) secretPatterns() []indexer.SecretPatternConfig {
+	patterns := make([]indexer.SecretPatternConfig, 0, len(c.Secrets.Patterns))
+	for _, p := range c.Secrets.Patterns {
+		patterns = append(patterns, indexer.SecretPatternConfig{Name: p.Name, Pattern: p.Pattern})
+	}
+	return patterns
+}
+
+// codePatterns returns paths.code plus patterns for the extensions and
+// filenames of external languages, so their files are discovered without
+// listing them twice.
+func (c *Config) codePatterns() []string {
+	patterns := append([]string{}, c.Paths.Code...)
+	seen := make(map[string]bool, len(patterns))
+	for _, p := range patterns {
+		seen[p] = true
+	}
+	for _, name := range c.languageNames() {
+		lang := c.Languages[name]
+		var add []string
+		for _, ext := range lang.Extensions {
+			add = append(add, "**/*"+ext)
+		}
+		for _, filename := range lang.Filenames {
+			add = append(add, "**/"+filename)
+		}
+		for _, p := range add {
+			if !seen[p] {
+				seen[p] = true
+				patterns = append(patterns, p)
+			}
+		}
+	}
+	return patterns
+}
+
+// externalLanguages converts the languages with an extractor command,
+// sorted by name. Relative command paths resolve against rootDir.
+func (c *Config) externalLanguages(rootDir string) []indexer.ExternalLanguageConfig {
+	var languages []indexer.ExternalLanguageConfig
+	for _, name := range c.languageNames() {
+		lang := c.Languages[name]
+		command := append([]string{}, lang.Command...)
+		if !filepath.IsAbs(command[0]) && strings.ContainsRune(command[0], filepath.Separator) {
+			command[0] = filepath.Join(rootDir, command[0])
+		}
+		languages = append(languages, indexer.ExternalLanguageConfig{
+			Name:       name,
+			Extensions: lang.Extensions,
+			Filenames:  lang.Filenames,
+			Shebangs:   lang.Shebangs,
+			Command:    command,
+			Graph:      lang.Graph,
+		})
+	}
+	return languages
+}
+
+// languageNames returns the names of the languages with an extractor
+// command, sorted.
+func (c *Config) languageNames() []string {
+	var names []string
+	for name, lang := range c.Languages {
+		if len(lang.Command) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// contentSources converts paths.sources, resolving relative roots against
+// rootDir. Sources without include patterns index what the project does.
+func (c *Config) contentSources(rootDir string) []indexer.ContentSourceConfig {
+	sources := make([]indexer.ContentSourceConfig, 0, len(c.Paths.Sources))
+	for _, src := range c.Paths.Sources {
+		root := src.Root
+		if !filepath.IsAbs(root) {
+			root = filepath.Join(rootDir, root)
+		}
+		include := src.Include
+		if len(include) == 0 {
+			include = append(append([]string{}, c.Paths.Code...), c.Paths.Docs...)
+		}
+		sources = append(sources, indexer.ContentSourceConfig{
+			Label:   src.Label,
+			Root:    root,
+			Include: include,
+			Ignore:  src.Ignore,
+			Watch:   src.Watch,
+		})
 	}
+	return sources
 }
@@ -65,6 +65,17 @@ func (l *loader) Load() (*Config, error) {
 	v.BindEnv("storage.branch_cache_enabled")
 	v.BindEnv("storage.cache_max_age_days")
 	v.BindEnv("storage.cache_max_size_mb")
+	v.BindEnv("storage.vector_index")
+	v.BindEnv("storage.vector_quantization")
+
+	// Indexing pipeline configuration
+	v.BindEnv("indexing.parse_workers")
+	v.BindEnv("indexing.embed_workers")
+	v.BindEnv("indexing.embed_batch_size")
+	v.BindEnv("indexing.write_batch_size")
+
+	// Secret scanning configuration
+	v.BindEnv("secrets.enabled")
 
 	// Set defaults in viper
 	setDefaults(v)
@@ -118,6 +129,19 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("storage.branch_cache_enabled", defaults.Storage.BranchCacheEnabled)
 	v.SetDefault("storage.cache_max_age_days", defaults.Storage.CacheMaxAgeDays)
 	v.SetDefault("storage.cache_max_size_mb", defaults.Storage.CacheMaxSizeMB)
+	v.SetDefault("storage.vector_index", defaults.Storage.VectorIndex)
+	v.SetDefault("storage.vector_quantization", defaults.Storage.VectorQuantization)
+
+	// Indexing pipeline defaults
+	v.SetDefault("indexing.parse_workers", defaults.Indexing.ParseWorkers)
+	v.SetDefault("indexing.embed_workers", defaults.Indexing.EmbedWorkers)
+	v.SetDefault("indexing.embed_batch_size", defaults.Indexing.EmbedBatchSize)
+	v.SetDefault("indexing.write_batch_size", defaults.Indexing.WriteBatchSize)
+	v.SetDefault("indexing.typed_call_graph", defaults.Indexing.TypedCallGraph)
+	v.SetDefault("indexing.dependency_corpus", defaults.Indexing.DependencyCorpus)
+
+	// Secret scanning defaults
+	v.SetDefault("secrets.enabled", defaults.Secrets.Enabled)
 }
 
 // LoadConfig is a convenience function that creates a loader and loads config.
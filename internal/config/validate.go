@@ -3,7 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/labels"
+	"github.com/mvp-joe/project-cortex/internal/languages"
 )
 
 var (
@@ -36,6 +41,21 @@ var (
 
 	// ErrInvalidCacheSettings indicates invalid cache configuration
 	ErrInvalidCacheSettings = errors.New("invalid cache settings")
+
+	// ErrInvalidVectorIndex indicates an unsupported vector index backend
+	ErrInvalidVectorIndex = errors.New("invalid vector index")
+
+	// ErrInvalidIndexing indicates invalid indexing pipeline settings
+	ErrInvalidIndexing = errors.New("invalid indexing settings")
+
+	// ErrInvalidSource indicates an invalid paths.sources entry
+	ErrInvalidSource = errors.New("invalid content source")
+
+	// ErrInvalidLanguage indicates an invalid languages entry
+	ErrInvalidLanguage = errors.New("invalid language")
+
+	// ErrInvalidSecretPattern indicates an invalid secrets.patterns entry
+	ErrInvalidSecretPattern = errors.New("invalid secret pattern")
 )
 
 // Validate checks that the configuration is valid and complete.
@@ -62,6 +82,21 @@ func Validate(cfg *Config) error {
 		errs = append(errs, err)
 	}
 
+	// Validate indexing pipeline configuration
+	if err := validateIndexing(&cfg.Indexing); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Validate external languages
+	if err := validateLanguages(cfg.Languages); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Validate custom secret detectors
+	if err := validateSecrets(&cfg.Secrets); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return joinErrors(errs)
 	}
@@ -103,6 +138,29 @@ func validateEmbedding(cfg *EmbeddingConfig) error {
 func validatePaths(cfg *PathsConfig) error {
 	// Paths can be empty - validation is lenient here
 	// The indexer will handle empty patterns gracefully
+	var errs []error
+
+	// Content sources need a unique label (it becomes part of file paths) and a root
+	seen := make(map[string]bool, len(cfg.Sources))
+	for i, src := range cfg.Sources {
+		switch {
+		case !labels.ValidSource(src.Label):
+			errs = append(errs, fmt.Errorf("%w: sources[%d]: label must be lowercase letters, digits, '.' or '-' (and not %q), got '%s'",
+				ErrInvalidSource, i, labels.Project, src.Label))
+		case seen[src.Label]:
+			errs = append(errs, fmt.Errorf("%w: sources[%d]: duplicate label '%s'", ErrInvalidSource, i, src.Label))
+		}
+		seen[src.Label] = true
+
+		if strings.TrimSpace(src.Root) == "" {
+			errs = append(errs, fmt.Errorf("%w: sources[%d]: root is required", ErrInvalidSource, i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return joinErrors(errs)
+	}
+
 	return nil
 }
 
@@ -119,11 +177,12 @@ func validateChunking(cfg *ChunkingConfig) error {
 		"symbols":     true,
 		"definitions": true,
 		"data":        true,
+		"docstring":   true,
 	}
 
 	for _, strategy := range cfg.Strategies {
 		if !validStrategies[strategy] {
-			errs = append(errs, fmt.Errorf("unknown chunking strategy: %s (valid: symbols, definitions, data)", strategy))
+			errs = append(errs, fmt.Errorf("unknown chunking strategy: %s (valid: symbols, definitions, data, docstring)", strategy))
 		}
 	}
 
@@ -169,6 +228,49 @@ func validateStorage(cfg *StorageConfig) error {
 		errs = append(errs, fmt.Errorf("%w: cache_max_size_mb cannot be negative, got %.2f", ErrInvalidCacheSettings, cfg.CacheMaxSizeMB))
 	}
 
+	// Validate vector index backend (empty means default exact backend)
+	switch cfg.VectorIndex {
+	case "", "exact", "ivf":
+	default:
+		errs = append(errs, fmt.Errorf("%w: must be 'exact' or 'ivf', got '%s'", ErrInvalidVectorIndex, cfg.VectorIndex))
+	}
+
+	// Validate vector quantization (only the exact backend supports it)
+	switch cfg.VectorQuantization {
+	case "", "none":
+	case "int8", "binary":
+		if cfg.VectorIndex == "ivf" {
+			errs = append(errs, fmt.Errorf("%w: vector_quantization '%s' requires vector_index 'exact'", ErrInvalidVectorIndex, cfg.VectorQuantization))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%w: vector_quantization must be 'none', 'int8' or 'binary', got '%s'", ErrInvalidVectorIndex, cfg.VectorQuantization))
+	}
+
+	if len(errs) > 0 {
+		return joinErrors(errs)
+	}
+
+	return nil
+}
+
+// validateIndexing validates indexing pipeline settings (zero means default).
+func validateIndexing(cfg *IndexingConfig) error {
+	var errs []error
+
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"parse_workers", cfg.ParseWorkers},
+		{"embed_workers", cfg.EmbedWorkers},
+		{"embed_batch_size", cfg.EmbedBatchSize},
+		{"write_batch_size", cfg.WriteBatchSize},
+	} {
+		if field.value < 0 {
+			errs = append(errs, fmt.Errorf("%w: %s cannot be negative, got %d", ErrInvalidIndexing, field.name, field.value))
+		}
+	}
+
 	if len(errs) > 0 {
 		return joinErrors(errs)
 	}
@@ -193,3 +295,70 @@ func joinErrors(errs []error) error {
 
 	return fmt.Errorf("validation failed:\n  - %s", strings.Join(msgs, "\n  - "))
 }
+
+func validateLanguages(langs map[string]LanguageConfig) error {
+	var errs []error
+
+	names := make([]string, 0, len(langs))
+	for name := range langs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Only entries with an extractor command declare a language
+	for _, name := range names {
+		lang := langs[name]
+		detected := len(lang.Extensions)+len(lang.Filenames)+len(lang.Shebangs) > 0
+		if len(lang.Command) == 0 {
+			if detected {
+				errs = append(errs, fmt.Errorf("%w: %s: command is required", ErrInvalidLanguage, name))
+			}
+			continue
+		}
+		if strings.TrimSpace(lang.Command[0]) == "" {
+			errs = append(errs, fmt.Errorf("%w: %s: command is empty", ErrInvalidLanguage, name))
+		}
+		if !languages.IsBuiltin(name) && !detected {
+			errs = append(errs, fmt.Errorf("%w: %s: extensions, filenames or shebangs are required", ErrInvalidLanguage, name))
+		}
+		for _, ext := range lang.Extensions {
+			if !strings.HasPrefix(ext, ".") || len(ext) < 2 {
+				errs = append(errs, fmt.Errorf("%w: %s: extension must start with a dot, got '%s'", ErrInvalidLanguage, name, ext))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return joinErrors(errs)
+	}
+
+	return nil
+}
+
+// validateSecrets checks that custom secret detectors are named and compile.
+func validateSecrets(cfg *SecretsConfig) error {
+	var errs []error
+
+	names := make(map[string]bool, len(cfg.Patterns))
+	for i, p := range cfg.Patterns {
+		switch {
+		case strings.TrimSpace(p.Name) == "":
+			errs = append(errs, fmt.Errorf("%w: patterns[%d]: name is required", ErrInvalidSecretPattern, i))
+		case names[p.Name]:
+			errs = append(errs, fmt.Errorf("%w: patterns[%d]: duplicate name '%s'", ErrInvalidSecretPattern, i, p.Name))
+		}
+		names[p.Name] = true
+
+		if strings.TrimSpace(p.Pattern) == "" {
+			errs = append(errs, fmt.Errorf("%w: patterns[%d]: pattern is required", ErrInvalidSecretPattern, i))
+		} else if _, err := regexp.Compile(p.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("%w: patterns[%d]: %v", ErrInvalidSecretPattern, i, err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return joinErrors(errs)
+	}
+
+	return nil
+}
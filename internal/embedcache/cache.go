@@ -0,0 +1,333 @@
+// Package embedcache provides a persistent, content-addressed embedding cache
+// shared by every project and branch on the machine.
+//
+// Entries are keyed by (model, dimensions, mode, SHA-256 of the text), so the
+// same chunk text is embedded at most once no matter which worktree, branch or
+// project produced it. The cache lives in a single SQLite database
+// (~/.cortex/embeddings.db by default) that several indexers may open
+// concurrently; it is bounded by a size cap and evicts least recently used
+// entries when the cap is exceeded.
+package embedcache
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// evictionTargetRatio is the fraction of the cap eviction shrinks to,
+	// so a full cache doesn't evict on every write.
+	evictionTargetRatio = 0.9
+
+	// touchInterval limits last_accessed updates so hot entries don't turn
+	// every lookup into a write.
+	touchInterval = time.Minute
+
+	// maxQueryParams bounds the number of hashes per IN (...) clause.
+	maxQueryParams = 500
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS embeddings (
+	model         TEXT    NOT NULL,
+	dimensions    INTEGER NOT NULL,
+	mode          TEXT    NOT NULL,
+	text_hash     TEXT    NOT NULL,
+	embedding     BLOB    NOT NULL,
+	last_accessed INTEGER NOT NULL,
+	PRIMARY KEY (model, dimensions, mode, text_hash)
+);
+CREATE INDEX IF NOT EXISTS idx_embeddings_last_accessed ON embeddings(last_accessed);
+`
+
+// Namespace identifies the embedding space an entry belongs to.
+// Embeddings from different models, dimensions or modes never collide.
+type Namespace struct {
+	Model      string
+	Dimensions int
+	Mode       embed.EmbedMode
+}
+
+// Stats describes the cache's current size.
+type Stats struct {
+	Entries   int
+	SizeBytes int64 // Bytes in use by the database (excluding free pages)
+	MaxBytes  int64 // Size cap (0 = unbounded)
+}
+
+// Cache is a persistent embedding cache. Safe for concurrent use.
+type Cache struct {
+	db       *sql.DB
+	path     string
+	maxBytes int64
+	now      func() time.Time
+
+	mu sync.Mutex // Serializes writes within this process
+}
+
+// NewCache opens (creating if needed) the cache database at path.
+// maxSizeMB caps the database size; values <= 0 disable eviction.
+func NewCache(path string, maxSizeMB float64) (*Cache, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create embedding cache directory: %w", err)
+	}
+
+	// WAL + busy timeout: several indexers (worktrees, daemon actors) share this file
+	db, err := sql.Open("sqlite3", path+"?_journal_mode=WAL&_busy_timeout=5000&_synchronous=NORMAL")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open embedding cache: %w", err)
+	}
+
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create embedding cache schema: %w", err)
+	}
+
+	maxBytes := int64(0)
+	if maxSizeMB > 0 {
+		maxBytes = int64(maxSizeMB * 1024 * 1024)
+	}
+
+	return &Cache{
+		db:       db,
+		path:     path,
+		maxBytes: maxBytes,
+		now:      time.Now,
+	}, nil
+}
+
+// HashText returns the content hash used as the cache key for text.
+func HashText(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the database file path.
+func (c *Cache) Path() string {
+	return c.path
+}
+
+// Get returns cached embeddings for the given text hashes, keyed by hash.
+// Missing hashes are absent from the result. Hits are marked as recently used.
+func (c *Cache) Get(ns Namespace, hashes []string) (map[string][]float32, error) {
+	result := make(map[string][]float32, len(hashes))
+	if len(hashes) == 0 {
+		return result, nil
+	}
+
+	var hits []string
+	for start := 0; start < len(hashes); start += maxQueryParams {
+		batch := hashes[start:min(start+maxQueryParams, len(hashes))]
+
+		rows, err := sq.Select("text_hash", "embedding").
+			From("embeddings").
+			Where(namespaceWhere(ns)).
+			Where(sq.Eq{"text_hash": batch}).
+			RunWith(c.db).
+			Query()
+		if err != nil {
+			return nil, fmt.Errorf("failed to query embedding cache: %w", err)
+		}
+		for rows.Next() {
+			var (
+				hash     string
+				embBytes []byte
+			)
+			if err := rows.Scan(&hash, &embBytes); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("failed to scan cached embedding: %w", err)
+			}
+			emb, err := storage.DeserializeEmbedding(embBytes)
+			if err != nil || len(emb) != ns.Dimensions {
+				continue // Corrupt or mismatched entry: treat as a miss, Put overwrites it
+			}
+			result[hash] = emb
+			hits = append(hits, hash)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("error iterating cached embeddings: %w", err)
+		}
+	}
+
+	if err := c.touch(ns, hits); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Put stores embeddings keyed by text hash, then evicts least recently used
+// entries if the cache exceeds its size cap.
+func (c *Cache) Put(ns Namespace, entries map[string][]float32) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT OR REPLACE INTO embeddings (model, dimensions, mode, text_hash, embedding, last_accessed)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare embedding cache insert: %w", err)
+	}
+	defer stmt.Close()
+
+	now := c.now().Unix()
+	for hash, emb := range entries {
+		if _, err := stmt.Exec(ns.Model, ns.Dimensions, string(ns.Mode), hash, storage.SerializeEmbedding(emb), now); err != nil {
+			return fmt.Errorf("failed to insert cached embedding: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit embedding cache: %w", err)
+	}
+
+	if _, err := c.evictLocked(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Evict removes least recently used entries until the cache is below its cap.
+// Returns the number of entries removed.
+func (c *Cache) Evict() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.evictLocked()
+}
+
+// Stats returns the number of entries and the database size.
+func (c *Cache) Stats() (*Stats, error) {
+	stats := &Stats{MaxBytes: c.maxBytes}
+
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM embeddings").Scan(&stats.Entries); err != nil {
+		return nil, fmt.Errorf("failed to count cached embeddings: %w", err)
+	}
+
+	size, err := c.usedBytes()
+	if err != nil {
+		return nil, err
+	}
+	stats.SizeBytes = size
+
+	return stats, nil
+}
+
+// Close closes the cache database.
+func (c *Cache) Close() error {
+	return c.db.Close()
+}
+
+// evictLocked deletes the oldest entries when the used size exceeds the cap,
+// shrinking to evictionTargetRatio of the cap. Caller must hold c.mu.
+func (c *Cache) evictLocked() (int, error) {
+	if c.maxBytes <= 0 {
+		return 0, nil
+	}
+
+	used, err := c.usedBytes()
+	if err != nil {
+		return 0, err
+	}
+	if used <= c.maxBytes {
+		return 0, nil
+	}
+
+	var entries int
+	if err := c.db.QueryRow("SELECT COUNT(*) FROM embeddings").Scan(&entries); err != nil {
+		return 0, fmt.Errorf("failed to count cached embeddings: %w", err)
+	}
+	if entries == 0 {
+		return 0, nil
+	}
+
+	// Estimate rows to drop from the average row footprint
+	avgRow := used / int64(entries)
+	excess := used - int64(float64(c.maxBytes)*evictionTargetRatio)
+	toDelete := int((excess + avgRow - 1) / avgRow)
+
+	res, err := c.db.Exec(`
+		DELETE FROM embeddings WHERE rowid IN (
+			SELECT rowid FROM embeddings ORDER BY last_accessed ASC LIMIT ?
+		)
+	`, toDelete)
+	if err != nil {
+		return 0, fmt.Errorf("failed to evict cached embeddings: %w", err)
+	}
+	deleted, _ := res.RowsAffected()
+
+	return int(deleted), nil
+}
+
+// usedBytes returns the bytes occupied by live pages. Freed pages are reused
+// by later inserts, so this (not the file size) is what the cap applies to.
+func (c *Cache) usedBytes() (int64, error) {
+	var pageCount, freeCount, pageSize int64
+	if err := c.db.QueryRow("PRAGMA page_count").Scan(&pageCount); err != nil {
+		return 0, fmt.Errorf("failed to read page count: %w", err)
+	}
+	if err := c.db.QueryRow("PRAGMA freelist_count").Scan(&freeCount); err != nil {
+		return 0, fmt.Errorf("failed to read freelist count: %w", err)
+	}
+	if err := c.db.QueryRow("PRAGMA page_size").Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("failed to read page size: %w", err)
+	}
+	return (pageCount - freeCount) * pageSize, nil
+}
+
+// touch marks hits as recently used, skipping entries touched within touchInterval.
+func (c *Cache) touch(ns Namespace, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for start := 0; start < len(hashes); start += maxQueryParams {
+		batch := hashes[start:min(start+maxQueryParams, len(hashes))]
+
+		_, err := sq.Update("embeddings").
+			Set("last_accessed", now.Unix()).
+			Where(namespaceWhere(ns)).
+			Where(sq.Eq{"text_hash": batch}).
+			Where(sq.Lt{"last_accessed": now.Add(-touchInterval).Unix()}).
+			RunWith(c.db).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to update embedding cache access time: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// namespaceWhere matches entries in ns.
+func namespaceWhere(ns Namespace) sq.Eq {
+	return sq.Eq{"model": ns.Model, "dimensions": ns.Dimensions, "mode": string(ns.Mode)}
+}
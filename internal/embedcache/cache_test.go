@@ -0,0 +1,213 @@
+package embedcache
+
+// Test Plan for Embedding Cache:
+// - Put then Get round-trips embeddings by text hash
+// - Entries are isolated by model, dimensions and mode
+// - Get marks hits as recently used (touch respects touchInterval)
+// - Entries with mismatched dimensions are treated as misses
+// - Eviction removes least recently used entries once the cap is exceeded
+// - Unbounded caches (maxSizeMB <= 0) never evict
+// - Cache persists across reopen (shared by separate processes/worktrees)
+// - Large lookups are batched across the parameter limit
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNS = Namespace{Model: "test-model", Dimensions: 4, Mode: embed.EmbedModePassage}
+
+func newTestCache(t *testing.T, maxSizeMB float64) *Cache {
+	t.Helper()
+	c, err := NewCache(filepath.Join(t.TempDir(), "embeddings.db"), maxSizeMB)
+	require.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+	return c
+}
+
+func TestHashText(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, HashText("func main() {}"), HashText("func main() {}"))
+	assert.NotEqual(t, HashText("func main() {}"), HashText("func main() { }"))
+	assert.Len(t, HashText(""), 64)
+}
+
+func TestCache_PutGet(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	h1, h2, h3 := HashText("a"), HashText("b"), HashText("c")
+	require.NoError(t, c.Put(testNS, map[string][]float32{
+		h1: {1, 0, 0, 0},
+		h2: {0, 1, 0, 0},
+	}))
+
+	got, err := c.Get(testNS, []string{h1, h2, h3})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, []float32{1, 0, 0, 0}, got[h1])
+	assert.Equal(t, []float32{0, 1, 0, 0}, got[h2])
+	assert.NotContains(t, got, h3)
+
+	empty, err := c.Get(testNS, nil)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
+
+func TestCache_NamespaceIsolation(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	h := HashText("shared text")
+	require.NoError(t, c.Put(testNS, map[string][]float32{h: {1, 2, 3, 4}}))
+
+	for _, ns := range []Namespace{
+		{Model: "other-model", Dimensions: 4, Mode: embed.EmbedModePassage},
+		{Model: "test-model", Dimensions: 4, Mode: embed.EmbedModeQuery},
+		{Model: "test-model", Dimensions: 8, Mode: embed.EmbedModePassage},
+	} {
+		got, err := c.Get(ns, []string{h})
+		require.NoError(t, err)
+		assert.Empty(t, got, "namespace %+v should not see entry", ns)
+	}
+}
+
+func TestCache_DimensionMismatchIsMiss(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	h := HashText("x")
+	require.NoError(t, c.Put(testNS, map[string][]float32{h: {1, 2}}))
+
+	got, err := c.Get(testNS, []string{h})
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+func TestCache_TouchOnGet(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	base := time.Unix(1_700_000_000, 0)
+	c.now = func() time.Time { return base }
+
+	h := HashText("x")
+	require.NoError(t, c.Put(testNS, map[string][]float32{h: {1, 2, 3, 4}}))
+
+	lastAccessed := func() int64 {
+		var ts int64
+		require.NoError(t, c.db.QueryRow("SELECT last_accessed FROM embeddings WHERE text_hash = ?", h).Scan(&ts))
+		return ts
+	}
+
+	// Within touchInterval: no write
+	c.now = func() time.Time { return base.Add(touchInterval / 2) }
+	_, err := c.Get(testNS, []string{h})
+	require.NoError(t, err)
+	assert.Equal(t, base.Unix(), lastAccessed())
+
+	// After touchInterval: access time updated
+	later := base.Add(2 * touchInterval)
+	c.now = func() time.Time { return later }
+	_, err = c.Get(testNS, []string{h})
+	require.NoError(t, err)
+	assert.Equal(t, later.Unix(), lastAccessed())
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+
+	// ~1536 bytes per entry; a 1 MB cap holds a few hundred entries
+	ns := Namespace{Model: "test-model", Dimensions: 384, Mode: embed.EmbedModePassage}
+	c := newTestCache(t, 1)
+
+	base := time.Unix(1_700_000_000, 0)
+	var hashes []string
+	for i := 0; i < 2000; i++ {
+		ts := base.Add(time.Duration(i) * time.Second)
+		c.now = func() time.Time { return ts }
+
+		h := HashText(fmt.Sprintf("chunk-%d", i))
+		hashes = append(hashes, h)
+		require.NoError(t, c.Put(ns, map[string][]float32{h: make([]float32, 384)}))
+	}
+
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	assert.LessOrEqual(t, stats.SizeBytes, stats.MaxBytes)
+	assert.Less(t, stats.Entries, 2000)
+	assert.Greater(t, stats.Entries, 0)
+
+	// Oldest entries are gone, newest survive
+	got, err := c.Get(ns, []string{hashes[0], hashes[len(hashes)-1]})
+	require.NoError(t, err)
+	assert.NotContains(t, got, hashes[0])
+	assert.Contains(t, got, hashes[len(hashes)-1])
+}
+
+func TestCache_UnboundedNeverEvicts(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	entries := make(map[string][]float32)
+	for i := 0; i < 100; i++ {
+		entries[HashText(fmt.Sprintf("chunk-%d", i))] = []float32{1, 2, 3, 4}
+	}
+	require.NoError(t, c.Put(testNS, entries))
+
+	evicted, err := c.Evict()
+	require.NoError(t, err)
+	assert.Equal(t, 0, evicted)
+
+	stats, err := c.Stats()
+	require.NoError(t, err)
+	assert.Equal(t, 100, stats.Entries)
+	assert.Equal(t, int64(0), stats.MaxBytes)
+}
+
+func TestCache_PersistsAcrossReopen(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "nested", "embeddings.db")
+
+	first, err := NewCache(path, 0)
+	require.NoError(t, err)
+	h := HashText("persisted")
+	require.NoError(t, first.Put(testNS, map[string][]float32{h: {4, 3, 2, 1}}))
+
+	// A second handle (e.g., another worktree's indexer) sees the entry
+	second, err := NewCache(path, 0)
+	require.NoError(t, err)
+	defer second.Close()
+
+	got, err := second.Get(testNS, []string{h})
+	require.NoError(t, err)
+	assert.Equal(t, []float32{4, 3, 2, 1}, got[h])
+
+	require.NoError(t, first.Close())
+}
+
+func TestCache_BatchesLargeLookups(t *testing.T) {
+	t.Parallel()
+	c := newTestCache(t, 0)
+
+	n := maxQueryParams*2 + 7
+	entries := make(map[string][]float32, n)
+	hashes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		h := HashText(fmt.Sprintf("chunk-%d", i))
+		entries[h] = []float32{float32(i), 0, 0, 0}
+		hashes = append(hashes, h)
+	}
+	require.NoError(t, c.Put(testNS, entries))
+
+	got, err := c.Get(testNS, hashes)
+	require.NoError(t, err)
+	assert.Len(t, got, n)
+}
@@ -82,7 +82,8 @@ func ExampleExecutor_Execute() {
 	//   "metadata": {
 	//     "took_ms": 0,
 	//     "query": "SELECT file_path, line_count_code FROM files WHERE language = ? ORDER BY line_count_code DESC LIMIT 2",
-	//     "source": "files"
+	//     "source": "files",
+	//     "generation": 0
 	//   }
 	// }
 }
@@ -167,7 +168,8 @@ func ExampleExecutor_Execute_aggregation() {
 	//   "metadata": {
 	//     "took_ms": 0,
 	//     "query": "SELECT language, COUNT(*) AS file_count, SUM(line_count_code) AS total_lines FROM files GROUP BY language ORDER BY total_lines DESC",
-	//     "source": "files"
+	//     "source": "files",
+	//     "generation": 0
 	//   }
 	// }
 }
@@ -1,10 +1,13 @@
 package files
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // Executor executes QueryDefinitions against a SQLite database.
@@ -31,8 +34,15 @@ func (e *Executor) Execute(qd *QueryDefinition) (*QueryResult, error) {
 	// Measure execution time
 	start := time.Now()
 
+	// Read from a snapshot so the reported generation matches the rows
+	tx, generation, err := storage.BeginSnapshot(context.Background(), e.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	// Execute query
-	rows, err := e.db.Query(sqlQuery, args...)
+	rows, err := tx.Query(sqlQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query execution failed: %w", err)
 	}
@@ -83,9 +93,10 @@ func (e *Executor) Execute(qd *QueryDefinition) (*QueryResult, error) {
 		Rows:     rowData,
 		RowCount: len(rowData),
 		Metadata: QueryMetadata{
-			TookMs: tookMs,
-			Query:  sqlQuery,
-			Source: "files",
+			TookMs:     tookMs,
+			Query:      sqlQuery,
+			Source:     "files",
+			Generation: generation,
 		},
 	}
 
@@ -102,9 +113,10 @@ type QueryResult struct {
 
 // QueryMetadata contains execution metadata for a query result.
 type QueryMetadata struct {
-	TookMs int64  `json:"took_ms"` // Execution time in milliseconds
-	Query  string `json:"query"`   // Generated SQL (for debugging)
-	Source string `json:"source"`  // Always "files"
+	TookMs     int64  `json:"took_ms"`    // Execution time in milliseconds
+	Query      string `json:"query"`      // Generated SQL (for debugging)
+	Source     string `json:"source"`     // Always "files"
+	Generation int64  `json:"generation"` // Index generation the rows were read from
 }
 
 // MarshalJSON implements custom JSON marshaling for QueryResult.
@@ -8,6 +8,7 @@ import (
 
 	"github.com/mark3labs/mcp-go/mcp"
 	mcputils "github.com/mvp-joe/project-cortex/internal/mcp-utils"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // FilesToolRequest represents the request structure for the cortex_files MCP tool.
@@ -15,6 +16,7 @@ import (
 type FilesToolRequest struct {
 	Operation string          `json:"operation"`
 	Query     json.RawMessage `json:"query"`
+	Label     string          `json:"label"` // Optional: content source label, or "project"
 }
 
 // CreateFilesToolHandler creates an MCP tool handler for cortex_files queries.
@@ -54,6 +56,13 @@ func CreateFilesToolHandler(db *sql.DB) func(context.Context, mcp.CallToolReques
 			return mcp.NewToolResultError(fmt.Sprintf("invalid query definition: %v", err)), nil
 		}
 
+		// Restrict the queried table's files to one content source
+		if req.Label != "" {
+			if err := applyLabelFilter(&queryDef, req.Label); err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
 		// Create executor and execute query
 		executor := NewExecutor(db)
 		result, err := executor.Execute(&queryDef)
@@ -73,3 +82,36 @@ func CreateFilesToolHandler(db *sql.DB) func(context.Context, mcp.CallToolReques
 		return mcp.NewToolResultText(string(jsonData)), nil
 	}
 }
+
+// applyLabelFilter ANDs a filter on the file path column of the query's FROM
+// table onto its WHERE clause: files of the content source labelled label
+// (stored as "@<label>/..."), or the repository's own files for "project".
+func applyLabelFilter(qd *QueryDefinition, label string) error {
+	if label != storage.ProjectLabel && !storage.ValidContentSourceLabel(label) {
+		return fmt.Errorf("invalid label %q: must be a content source label or %s", label, storage.ProjectLabel)
+	}
+
+	table, ok := NewSchemaRegistry().GetTable(qd.From)
+	if !ok {
+		return nil // Reported by validation
+	}
+	var column string
+	switch {
+	case table.HasColumn("file_path"):
+		column = "file_path"
+	case table.HasColumn("source_file_path"):
+		column = "source_file_path"
+	default:
+		return fmt.Errorf("label filter requires a table with file paths, %s has none", qd.From)
+	}
+
+	filter := NewFieldFilter(FieldFilter{Field: column, Operator: OpLike, Value: storage.ContentSourcePrefix(label) + "%"})
+	if label == storage.ProjectLabel {
+		filter = NewFieldFilter(FieldFilter{Field: column, Operator: OpNotLike, Value: "@%"})
+	}
+	if qd.Where != nil {
+		filter = NewAndFilter(AndFilter{And: []Filter{filter, *qd.Where}})
+	}
+	qd.Where = &filter
+	return nil
+}
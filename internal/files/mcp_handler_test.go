@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"path/filepath"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -329,6 +330,54 @@ func TestCreateFilesToolHandler_ComplexQuery(t *testing.T) {
 	assert.Contains(t, queryResult.Columns, "type_count")
 }
 
+// TestCreateFilesToolHandler_Label tests restricting a query to a content source.
+func TestCreateFilesToolHandler_Label(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "label.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Exec(`CREATE TABLE files (file_path TEXT PRIMARY KEY, language TEXT NOT NULL);
+		INSERT INTO files VALUES ('main.go', 'go'), ('README.md', 'markdown'),
+			('@wiki/onboarding.md', 'markdown'), ('@specs/api.md', 'markdown');`)
+	require.NoError(t, err)
+
+	handler := CreateFilesToolHandler(db)
+	query := func(label string, where *Filter) *mcp.CallToolResult {
+		queryJSON, err := json.Marshal(&QueryDefinition{Fields: []string{"file_path"}, From: "files", Where: where,
+			OrderBy: []OrderBy{{Field: "file_path", Direction: SortAsc}}})
+		require.NoError(t, err)
+		result, err := handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{
+				Name:      "cortex_files",
+				Arguments: map[string]interface{}{"operation": "query", "query": json.RawMessage(queryJSON), "label": label},
+			},
+		})
+		require.NoError(t, err)
+		return result
+	}
+	paths := func(result *mcp.CallToolResult) []interface{} {
+		require.False(t, result.IsError)
+		var queryResult QueryResult
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].(mcp.TextContent).Text), &queryResult))
+		var paths []interface{}
+		for _, row := range queryResult.Rows {
+			paths = append(paths, row[0])
+		}
+		return paths
+	}
+
+	assert.Equal(t, []interface{}{"@wiki/onboarding.md"}, paths(query("wiki", nil)))
+	assert.Equal(t, []interface{}{"README.md", "main.go"}, paths(query("project", nil)))
+
+	// Combined with the query's own filter
+	markdown := NewFieldFilter(FieldFilter{Field: "language", Operator: OpEqual, Value: "markdown"})
+	assert.Equal(t, []interface{}{"README.md"}, paths(query("project", &markdown)))
+
+	// Invalid labels are tool errors
+	assert.True(t, query("Wiki", nil).IsError)
+}
+
 // setupTestDBForMCP creates an in-memory SQLite database with test data for MCP handler tests.
 func setupTestDBForMCP(t *testing.T) *sql.DB {
 	t.Helper()
@@ -1,5 +1,10 @@
 package files
 
+import (
+	"sort"
+	"strings"
+)
+
 // TableSchema defines the columns available in a database table.
 type TableSchema struct {
 	Name    string
@@ -58,6 +63,7 @@ func NewSchemaRegistry() *SchemaRegistry {
 				"is_exported",
 				"field_count",
 				"method_count",
+				"doc",
 			),
 			"type_fields": NewTableSchema("type_fields",
 				"field_id",
@@ -85,6 +91,7 @@ func NewSchemaRegistry() *SchemaRegistry {
 				"param_count",
 				"return_count",
 				"cyclomatic_complexity",
+				"doc",
 			),
 			"function_parameters": NewTableSchema("function_parameters",
 				"param_id",
@@ -133,6 +140,134 @@ func NewSchemaRegistry() *SchemaRegistry {
 				"created_at",
 				"updated_at",
 			),
+			"workspace_modules": NewTableSchema("workspace_modules",
+				"module_name",
+				"kind",
+				"root_path",
+				"manifest_path",
+			),
+			"file_modules": NewTableSchema("file_modules",
+				"file_path",
+				"module_name",
+			),
+			"import_resolutions": NewTableSchema("import_resolutions",
+				"import_id",
+				"resolution",
+				"resolved_path",
+				"module_name",
+				"package_name",
+			),
+			"header_implementations": NewTableSchema("header_implementations",
+				"header_path",
+				"source_path",
+			),
+			"dependencies": NewTableSchema("dependencies",
+				"ecosystem",
+				"name",
+				"import_name",
+				"version",
+				"version_constraint",
+				"scope",
+				"direct",
+				"manifest_path",
+				"module_name",
+			),
+			"dependency_usages": NewTableSchema("dependency_usages",
+				"file_path",
+				"import_path",
+				"import_line",
+				"ecosystem",
+				"dependency_name",
+				"version",
+				"scope",
+				"direct",
+				"manifest_path",
+				"module_name",
+			),
+			"content_sources": NewTableSchema("content_sources",
+				"label",
+				"root",
+				"watch",
+			),
+			"contract_symbols": NewTableSchema("contract_symbols",
+				"symbol_id",
+				"file_path",
+				"protocol",
+				"kind",
+				"qualified_name",
+				"go_package",
+			),
+			"contract_links": NewTableSchema("contract_links",
+				"contract_symbol_id",
+				"generated_id",
+				"generated_file_path",
+				"link_kind",
+			),
+			"config_keys": NewTableSchema("config_keys",
+				"file_path",
+				"document",
+				"key_path",
+				"value",
+				"line",
+			),
+			"config_usages": NewTableSchema("config_usages",
+				"usage_id",
+				"file_path",
+				"line",
+				"kind",
+				"key",
+				"source",
+				"function",
+				"function_id",
+			),
+			"config_key_definitions": NewTableSchema("config_key_definitions",
+				"key",
+				"kind",
+				"file_path",
+				"key_path",
+				"value",
+				"line",
+			),
+			"undocumented_config_keys": NewTableSchema("undocumented_config_keys",
+				"file_path",
+				"line",
+				"kind",
+				"key",
+				"source",
+				"function",
+				"function_id",
+			),
+			"unused_config_keys": NewTableSchema("unused_config_keys",
+				"file_path",
+				"line",
+				"key",
+				"value",
+			),
+			"endpoints": NewTableSchema("endpoints",
+				"endpoint_id",
+				"file_path",
+				"line",
+				"framework",
+				"kind",
+				"method",
+				"path",
+				"handler",
+				"handler_name",
+				"handler_scope",
+				"handler_function_id",
+			),
+			"table_usages": NewTableSchema("table_usages",
+				"usage_id",
+				"file_path",
+				"line",
+				"table_name",
+				"access",
+				"operation",
+				"columns",
+				"source",
+				"function",
+				"function_id",
+			),
 			"cache_metadata": NewTableSchema("cache_metadata",
 				"key",
 				"value",
@@ -154,6 +289,21 @@ func (sr *SchemaRegistry) HasTable(name string) bool {
 	return ok
 }
 
+// TableNames returns the names of the queryable tables, sorted.
+func (sr *SchemaRegistry) TableNames() []string {
+	names := make([]string, 0, len(sr.tables))
+	for name := range sr.tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// TablesHint returns the hint listing the queryable tables.
+func (sr *SchemaRegistry) TablesHint() string {
+	return "Valid tables: " + strings.Join(sr.TableNames(), ", ")
+}
+
 // ValidateTableAndColumn checks if a table exists and has the specified column.
 func (sr *SchemaRegistry) ValidateTableAndColumn(table, column string) error {
 	tableSchema, ok := sr.GetTable(table)
@@ -162,7 +312,7 @@ func (sr *SchemaRegistry) ValidateTableAndColumn(table, column string) error {
 			Field:   "table",
 			Value:   table,
 			Message: "unknown table",
-			Hint:    "Valid tables: files, types, type_fields, functions, function_parameters, type_relationships, function_calls, imports, chunks, cache_metadata",
+			Hint:    sr.TablesHint(),
 		}
 	}
 
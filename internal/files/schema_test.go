@@ -171,6 +171,29 @@ func TestSchemaRegistry_ImportsTable(t *testing.T) {
 	}
 }
 
+func TestSchemaRegistry_WorkspaceTables(t *testing.T) {
+	t.Parallel()
+
+	registry := NewSchemaRegistry()
+
+	expected := map[string][]string{
+		"workspace_modules":      {"module_name", "kind", "root_path", "manifest_path"},
+		"file_modules":           {"file_path", "module_name"},
+		"import_resolutions":     {"import_id", "resolution", "resolved_path", "module_name", "package_name"},
+		"header_implementations": {"header_path", "source_path"},
+		"dependencies":           {"ecosystem", "name", "import_name", "version", "version_constraint", "scope", "direct", "manifest_path", "module_name"},
+		"dependency_usages":      {"file_path", "import_path", "import_line", "dependency_name", "version", "direct"},
+	}
+
+	for tableName, columns := range expected {
+		table, ok := registry.GetTable(tableName)
+		require.True(t, ok, "table %s should exist in registry", tableName)
+		for _, col := range columns {
+			assert.True(t, table.HasColumn(col), "%s table should have column %s", tableName, col)
+		}
+	}
+}
+
 func TestSchemaRegistry_ValidateTableAndColumn_ValidCases(t *testing.T) {
 	t.Parallel()
 
@@ -209,6 +232,10 @@ func TestSchemaRegistry_ValidateTableAndColumn_InvalidTable(t *testing.T) {
 	assert.Equal(t, "table", validationErr.Field)
 	assert.Equal(t, "nonexistent", validationErr.Value)
 	assert.Contains(t, validationErr.Message, "unknown table")
+	for _, table := range registry.TableNames() {
+		assert.Contains(t, validationErr.Hint, table)
+	}
+	assert.Contains(t, validationErr.Hint, "table_usages")
 }
 
 func TestSchemaRegistry_ValidateTableAndColumn_InvalidColumn(t *testing.T) {
@@ -231,7 +258,7 @@ func TestSchemaRegistry_AllTables(t *testing.T) {
 
 	registry := NewSchemaRegistry()
 
-	// Verify all 10 tables exist
+	// Verify all 26 tables exist
 	tables := []string{
 		"files",
 		"types",
@@ -242,6 +269,22 @@ func TestSchemaRegistry_AllTables(t *testing.T) {
 		"function_calls",
 		"imports",
 		"chunks",
+		"workspace_modules",
+		"file_modules",
+		"import_resolutions",
+		"header_implementations",
+		"dependencies",
+		"dependency_usages",
+		"content_sources",
+		"contract_symbols",
+		"contract_links",
+		"config_keys",
+		"config_usages",
+		"config_key_definitions",
+		"undocumented_config_keys",
+		"unused_config_keys",
+		"endpoints",
+		"table_usages",
 		"cache_metadata",
 	}
 
@@ -78,7 +78,7 @@ func (v *Validator) Validate(q *QueryDefinition) error {
 		// Return early if FROM is missing - can't validate other fields without it
 		return errors
 	} else if !v.registry.HasTable(q.From) {
-		errors.Add("from", q.From, "unknown table", "Valid tables: files, types, type_fields, functions, function_parameters, type_relationships, function_calls, imports, chunks, modules, cache_metadata")
+		errors.Add("from", q.From, "unknown table", v.registry.TablesHint())
 		// Return early if FROM is invalid - can't validate other fields without valid table
 		return errors
 	}
@@ -107,7 +107,7 @@ func (v *Validator) Validate(q *QueryDefinition) error {
 			errors.Add(fmt.Sprintf("joins[%d].type", i), string(join.Type), "invalid join type", "Valid types: INNER, LEFT, RIGHT, FULL")
 		}
 		if !v.registry.HasTable(join.Table) {
-			errors.Add(fmt.Sprintf("joins[%d].table", i), join.Table, "unknown table", "Valid tables: files, types, type_fields, functions, function_parameters, type_relationships, function_calls, imports, chunks, modules, cache_metadata")
+			errors.Add(fmt.Sprintf("joins[%d].table", i), join.Table, "unknown table", v.registry.TablesHint())
 		}
 		// Validate ON condition (need to check both tables)
 		v.validateJoinFilter(q.From, join.Table, join.On, i, &errors)
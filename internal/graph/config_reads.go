@@ -0,0 +1,226 @@
+package graph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// envFuncs are the standard library functions reading the environment
+// variable named by their first argument, by package.
+var envFuncs = map[string]map[string]bool{
+	"os":      {"Getenv": true, "LookupEnv": true},
+	"syscall": {"Getenv": true},
+}
+
+// viperImport is the import path of viper, whose Get* functions read
+// configuration keys.
+const viperImport = "github.com/spf13/viper"
+
+// configReader finds the environment variables and configuration keys a Go
+// file reads.
+type configReader struct {
+	fset       *token.FileSet
+	relPath    string
+	packages   map[string]string // Import path of each imported package, by local name
+	consts     map[string]string // File-level string constants, for keys
+	vipers     map[string]bool   // Variables holding a viper instance (viper.New())
+	function   string            // Function being walked (Recv.Name)
+	functionID string
+	usages     []ConfigUsage
+}
+
+// extractConfigReads returns the environment variables and configuration
+// keys a Go file reads: os.Getenv and os.LookupEnv, viper's Get* and IsSet
+// (on the package or an instance from viper.New), the variables bound by
+// viper.BindEnv, and the env struct tags of caarlos0/env and
+// sethvargo/go-envconfig. Keys that aren't string literals or file
+// constants are skipped.
+func extractConfigReads(file *ast.File, fset *token.FileSet, relPath string) []ConfigUsage {
+	r := &configReader{
+		fset:     fset,
+		relPath:  relPath,
+		packages: make(map[string]string),
+		consts:   make(map[string]string),
+		vipers:   make(map[string]bool),
+	}
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		r.packages[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					if value, ok := r.stringValue(vs.Values[i]); ok {
+						r.consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			r.function = d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				r.function = extractReceiverType(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+			r.functionID = fmt.Sprintf("%s::%s", relPath, r.function)
+			if d.Body != nil {
+				r.walk(d.Body)
+			}
+		case *ast.GenDecl:
+			r.function, r.functionID = "", ""
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					if st, ok := s.Type.(*ast.StructType); ok {
+						r.readEnvTags(s.Name.Name, st)
+					}
+				case *ast.ValueSpec:
+					r.walk(s)
+				}
+			}
+		}
+	}
+	return r.usages
+}
+
+// walk records the reads in node, including those of nested function
+// literals, which belong to the enclosing function.
+func (r *configReader) walk(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// v := viper.New()
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) && r.isViperConstructor(rhs) {
+					if ident, ok := n.Lhs[i].(*ast.Ident); ok {
+						r.vipers[ident.Name] = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			r.visitCall(n)
+		}
+		return true
+	})
+}
+
+// visitCall records the keys a call reads.
+func (r *configReader) visitCall(call *ast.CallExpr) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || len(call.Args) == 0 {
+		return
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return
+	}
+	name := sel.Sel.Name
+
+	importPath := r.packages[x.Name]
+	switch {
+	case envFuncs[importPath][name]:
+		r.addArg(call.Args[0], ConfigKindEnv, importPath+"."+name)
+	case importPath == viperImport || r.vipers[x.Name]:
+		switch {
+		case name == "BindEnv":
+			// viper.BindEnv("port", "PORT", "APP_PORT"): the variables
+			// after the key; with the key alone, it depends on the prefix
+			for _, arg := range call.Args[1:] {
+				r.addArg(arg, ConfigKindEnv, "viper.BindEnv")
+			}
+		case strings.HasPrefix(name, "Get") || name == "IsSet":
+			r.addArg(call.Args[0], ConfigKindKey, "viper")
+		}
+	}
+}
+
+// isViperConstructor reports whether expr returns a viper instance.
+func (r *configReader) isViperConstructor(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && r.packages[x.Name] == viperImport && (sel.Sel.Name == "New" || sel.Sel.Name == "GetViper")
+}
+
+// readEnvTags records the variables named by the env tags of a struct's
+// fields (`env:"PORT,required"`), read by env-parsing libraries.
+func (r *configReader) readEnvTags(typeName string, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		tag, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		key, _, _ := strings.Cut(reflect.StructTag(tag).Get("env"), ",")
+		if key == "" || key == "-" {
+			continue
+		}
+		r.usages = append(r.usages, ConfigUsage{
+			FilePath: r.relPath,
+			Line:     r.fset.Position(field.Pos()).Line,
+			Kind:     ConfigKindEnv,
+			Key:      key,
+			Source:   "env tag",
+			Function: typeName,
+		})
+	}
+}
+
+// addArg records a read of the key an argument holds.
+func (r *configReader) addArg(arg ast.Expr, kind, source string) {
+	key, ok := r.stringValue(arg)
+	if !ok || key == "" {
+		return
+	}
+	r.usages = append(r.usages, ConfigUsage{
+		FilePath:   r.relPath,
+		Line:       r.fset.Position(arg.Pos()).Line,
+		Kind:       kind,
+		Key:        key,
+		Source:     source,
+		Function:   r.function,
+		FunctionID: r.functionID,
+	})
+}
+
+// stringValue returns the value of a string literal or file constant.
+func (r *configReader) stringValue(expr ast.Expr) (string, bool) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(v.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := r.consts[v.Name]
+		return s, ok
+	}
+	return "", false
+}
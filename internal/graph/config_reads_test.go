@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for configuration read extraction:
+// - os.Getenv, os.LookupEnv and syscall.Getenv read environment variables,
+//   with keys from literals or file constants, attributed to the enclosing function
+// - viper Get*/IsSet read config keys on the package and viper.New instances;
+//   BindEnv binds the variables after the key
+// - env struct tags are reads by the struct type
+// - Package-level initializers have no function; test files have no reads
+
+func extractTestConfigReads(t *testing.T, relPath, source string) []ConfigUsage {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := NewExtractor(dir).ExtractCodeStructure(path)
+	require.NoError(t, err)
+	return result.ConfigUsages
+}
+
+// configReads summarizes usages as "kind key (source) in function @line".
+func configReads(usages []ConfigUsage) []string {
+	var out []string
+	for _, u := range usages {
+		out = append(out, fmt.Sprintf("%s %s (%s) in %s @%d", u.Kind, u.Key, u.Source, u.Function, u.Line))
+	}
+	return out
+}
+
+func TestExtractConfigReads_Env(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestConfigReads(t, "server/config.go", `package server
+
+import (
+	"os"
+	sys "syscall"
+)
+
+const portVar = "PORT"
+
+var debug = os.Getenv("DEBUG")
+
+func (s *Server) load() {
+	s.dsn = os.Getenv("DATABASE_URL")
+	if v, ok := os.LookupEnv(portVar); ok {
+		s.port = v
+	}
+	go func() {
+		_, _ = sys.Getenv("HOME")
+	}()
+	_ = os.Getenv(dynamicName())
+	_ = os.Setenv("IGNORED", "1")
+}
+`)
+
+	assert.Equal(t, []string{
+		"env DEBUG (os.Getenv) in  @10",
+		"env DATABASE_URL (os.Getenv) in Server.load @13",
+		"env PORT (os.LookupEnv) in Server.load @14",
+		"env HOME (syscall.Getenv) in Server.load @18",
+	}, configReads(usages))
+	assert.Empty(t, usages[0].FunctionID)
+	assert.Equal(t, "server/config.go::Server.load", usages[1].FunctionID)
+}
+
+func TestExtractConfigReads_Viper(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestConfigReads(t, "config.go", `package main
+
+import "github.com/spf13/viper"
+
+func load() {
+	viper.BindEnv("database.url", "DATABASE_URL", "DB_URL")
+	viper.BindEnv("port")
+	v := viper.New()
+	_ = viper.GetString("database.url")
+	_ = v.GetInt("server.port")
+	_ = v.IsSet("server.tls")
+	viper.SetDefault("server.port", 8080)
+}
+`)
+
+	assert.Equal(t, []string{
+		"env DATABASE_URL (viper.BindEnv) in load @6",
+		"env DB_URL (viper.BindEnv) in load @6",
+		"config database.url (viper) in load @9",
+		"config server.port (viper) in load @10",
+		"config server.tls (viper) in load @11",
+	}, configReads(usages))
+}
+
+func TestExtractConfigReads_EnvTags(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestConfigReads(t, "config/config.go", "package config\n\n"+
+		"type Config struct {\n"+
+		"\tPort  int    `env:\"PORT\" envDefault:\"8080\"`\n"+
+		"\tDSN   string `env:\"DATABASE_URL,required\"`\n"+
+		"\tDebug bool   `json:\"debug\"`\n"+
+		"\tSkip  string `env:\"-\"`\n"+
+		"}\n")
+
+	assert.Equal(t, []string{
+		"env PORT (env tag) in Config @4",
+		"env DATABASE_URL (env tag) in Config @5",
+	}, configReads(usages))
+}
+
+func TestExtractConfigReads_TestFilesSkipped(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestConfigReads(t, "server/config_test.go", `package server
+
+import "os"
+
+func TestLoad(t *testing.T) {
+	_ = os.Getenv("DATABASE_URL")
+}
+`)
+	assert.Empty(t, usages)
+}
@@ -0,0 +1,465 @@
+package graph
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// routerImports maps the import paths of Go routers to their framework name.
+// Versioned paths (github.com/go-chi/chi/v5) match by prefix.
+var routerImports = map[string]string{
+	"net/http":                 "net/http",
+	"github.com/go-chi/chi":    "chi",
+	"github.com/gin-gonic/gin": "gin",
+	"github.com/labstack/echo": "echo",
+	"github.com/gorilla/mux":   "gorilla",
+}
+
+// httpMethods are the HTTP methods routers register by name: chi as Get,
+// gin and echo as GET.
+var httpMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE"}
+
+// handlerAdapters wrap a handler in another: the index of the wrapped
+// handler among their arguments.
+var handlerAdapters = map[string]int{
+	"HandlerFunc":          0, // http.HandlerFunc(h)
+	"StripPrefix":          1, // http.StripPrefix("/static", h)
+	"TimeoutHandler":       0,
+	"MaxBytesHandler":      0,
+	"AllowQuerySemicolons": 0,
+	"WrapF":                0, // gin.WrapF(h)
+	"WrapH":                0, // gin.WrapH(h)
+	"WrapHandler":          0, // echo.WrapHandler(h)
+}
+
+// procedurePattern matches the RPC paths generated code declares:
+// /package.Service/Method.
+var procedurePattern = regexp.MustCompile(`^/(?:[\w.]+\.)?(\w+)/(\w+)$`)
+
+// routeExtractor finds the routes a Go file registers.
+type routeExtractor struct {
+	fset       *token.FileSet
+	relPath    string
+	frameworks map[string]bool   // Router frameworks the file imports
+	consts     map[string]string // File-level string constants, for paths
+	prefixes   map[string]string // Path prefix of each router group variable
+	handled    map[*ast.CallExpr]bool
+	endpoints  []Endpoint
+}
+
+// extractEndpoints returns the routes a Go file registers with net/http,
+// chi, gin, echo or gorilla/mux, with group prefixes (r.Route, r.Group,
+// PathPrefix subrouters) applied, and the RPC procedures connect-go and
+// grpc-go generated code declares. Routes whose path isn't a string literal
+// or file constant are skipped.
+func extractEndpoints(file *ast.File, fset *token.FileSet, relPath string) []Endpoint {
+	e := &routeExtractor{
+		fset:       fset,
+		relPath:    relPath,
+		frameworks: make(map[string]bool),
+		consts:     make(map[string]string),
+		handled:    make(map[*ast.CallExpr]bool),
+	}
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		for prefix, framework := range routerImports {
+			if importPath == prefix || strings.HasPrefix(importPath, prefix+"/") {
+				e.frameworks[framework] = true
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			e.readConst(spec.(*ast.ValueSpec), file.Name.Name)
+		}
+	}
+	if len(e.frameworks) == 0 {
+		return e.endpoints
+	}
+
+	for _, decl := range file.Decls {
+		e.prefixes = make(map[string]string)
+		e.walk(decl)
+	}
+	return e.endpoints
+}
+
+// readConst records a string constant, and the RPC procedure it declares in
+// generated code: connect-go's ServiceMethodProcedure constants and
+// grpc-go's Service_Method_FullMethodName constants.
+func (e *routeExtractor) readConst(spec *ast.ValueSpec, pkgName string) {
+	for i, name := range spec.Names {
+		if i >= len(spec.Values) {
+			return
+		}
+		lit, ok := spec.Values[i].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			continue
+		}
+		value, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			continue
+		}
+		e.consts[name.Name] = value
+
+		m := procedurePattern.FindStringSubmatch(value)
+		if m == nil {
+			continue
+		}
+		service, method := m[1], m[2]
+		switch {
+		case strings.HasSuffix(pkgName, "connect") && name.Name == service+method+"Procedure":
+			e.add(name, "connect", EndpointRPC, "POST", value, service+"Handler."+method, method, service+"Handler")
+		case name.Name == service+"_"+method+"_FullMethodName":
+			e.add(name, "grpc", EndpointRPC, "POST", value, service+"Server."+method, method, service+"Server")
+		}
+	}
+}
+
+// walk visits node in source order, tracking router group variables.
+func (e *routeExtractor) walk(node ast.Node) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			// v1 := r.Group("/v1"), api := r.PathPrefix("/api").Subrouter()
+			if len(n.Lhs) == 1 && len(n.Rhs) == 1 {
+				if ident, ok := n.Lhs[0].(*ast.Ident); ok {
+					if prefix, ok := e.prefixOf(n.Rhs[0]); ok {
+						e.prefixes[ident.Name] = prefix
+					}
+				}
+			}
+		case *ast.CallExpr:
+			return e.visitCall(n)
+		}
+		return true
+	})
+}
+
+// visitCall records the route a call registers. Returns false when the call
+// was walked already (a chi sub-router function).
+func (e *routeExtractor) visitCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || e.handled[call] {
+		return true
+	}
+	name := sel.Sel.Name
+
+	switch {
+	case name == "Methods":
+		// gorilla: r.HandleFunc("/x", h).Methods("GET", "POST")
+		if inner, ok := sel.X.(*ast.CallExpr); ok && e.frameworks["gorilla"] {
+			if methods := e.stringArgs(call.Args); len(methods) > 0 {
+				e.handled[inner] = true
+				e.addRoute(inner, methods)
+			}
+		}
+	case (name == "Route" || name == "Group") && len(call.Args) > 0 && e.frameworks["chi"]:
+		// chi: r.Route("/orders", func(r chi.Router) {...}), r.Group(func(r chi.Router) {...})
+		fn, ok := call.Args[len(call.Args)-1].(*ast.FuncLit)
+		if !ok {
+			return true
+		}
+		prefix := e.receiverPrefix(sel.X)
+		if name == "Route" {
+			path, ok := e.stringValue(call.Args[0])
+			if !ok {
+				return true
+			}
+			prefix = joinRoutePath(prefix, path)
+		}
+		e.walkRouter(fn, prefix)
+		return false
+	default:
+		e.addRoute(call, nil)
+	}
+	return true
+}
+
+// walkRouter walks the body of a chi sub-router function with the prefix of
+// its router parameter, restoring the enclosing binding afterwards.
+func (e *routeExtractor) walkRouter(fn *ast.FuncLit, prefix string) {
+	if fn.Type.Params == nil || len(fn.Type.Params.List) == 0 || len(fn.Type.Params.List[0].Names) == 0 {
+		e.walk(fn.Body)
+		return
+	}
+	param := fn.Type.Params.List[0].Names[0].Name
+	saved, had := e.prefixes[param]
+	e.prefixes[param] = prefix
+	e.walk(fn.Body)
+	if had {
+		e.prefixes[param] = saved
+	} else {
+		delete(e.prefixes, param)
+	}
+}
+
+// addRoute records the route call registers, if it is a route registration.
+// methods overrides the registered methods (gorilla's Methods).
+func (e *routeExtractor) addRoute(call *ast.CallExpr, methods []string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	name := sel.Sel.Name
+	args := call.Args
+
+	var framework, pattern string
+	var handler ast.Expr
+	switch {
+	case name == "Handle" || name == "HandleFunc":
+		if len(args) != 2 {
+			return
+		}
+		framework = "net/http"
+		switch {
+		case e.frameworks["gorilla"]:
+			framework = "gorilla"
+		case e.frameworks["chi"]:
+			framework = "chi"
+		case !e.frameworks["net/http"]:
+			return
+		}
+		var ok bool
+		if pattern, ok = e.stringValue(args[0]); !ok {
+			return
+		}
+		handler = args[1]
+		if methods == nil {
+			methods = []string{"ANY"}
+			if framework == "net/http" {
+				// Go 1.22 patterns: "GET /orders/{id}", with an optional host
+				if method, rest, ok := strings.Cut(pattern, " "); ok && isHTTPMethod(method) {
+					methods, pattern = []string{method}, strings.TrimSpace(rest)
+				}
+				if i := strings.Index(pattern, "/"); i > 0 {
+					pattern = pattern[i:]
+				}
+			}
+		}
+	case (name == "Method" || name == "MethodFunc") && e.frameworks["chi"]:
+		// chi: r.Method("GET", "/x", h)
+		if len(args) != 3 {
+			return
+		}
+		method, ok := e.stringValue(args[0])
+		if !ok {
+			return
+		}
+		if pattern, ok = e.stringValue(args[1]); !ok {
+			return
+		}
+		framework, methods, handler = "chi", []string{strings.ToUpper(method)}, args[2]
+	case name == "Match" && (e.frameworks["gin"] || e.frameworks["echo"]):
+		// gin and echo: r.Match([]string{"GET", "POST"}, "/x", h)
+		if len(args) < 3 {
+			return
+		}
+		list, ok := args[0].(*ast.CompositeLit)
+		if !ok {
+			return
+		}
+		if methods = e.stringArgs(list.Elts); len(methods) == 0 {
+			return
+		}
+		if pattern, ok = e.stringValue(args[1]); !ok {
+			return
+		}
+		framework, handler = e.ginOrEcho(), e.ginOrEchoHandler(args[2:])
+	case isHTTPMethod(name) || name == "Any":
+		// gin and echo: r.GET("/x", h)
+		if len(args) < 2 || !(e.frameworks["gin"] || e.frameworks["echo"]) {
+			return
+		}
+		var ok bool
+		if pattern, ok = e.stringValue(args[0]); !ok {
+			return
+		}
+		framework, methods, handler = e.ginOrEcho(), []string{strings.ToUpper(name)}, e.ginOrEchoHandler(args[1:])
+	case isHTTPMethod(strings.ToUpper(name)) && name != strings.ToUpper(name) && e.frameworks["chi"]:
+		// chi: r.Get("/x", h)
+		if len(args) != 2 {
+			return
+		}
+		var ok bool
+		if pattern, ok = e.stringValue(args[0]); !ok {
+			return
+		}
+		framework, methods, handler = "chi", []string{strings.ToUpper(name)}, args[1]
+	default:
+		return
+	}
+
+	path := joinRoutePath(e.receiverPrefix(sel.X), pattern)
+	if !strings.HasPrefix(path, "/") {
+		return
+	}
+	written, handlerName, scope := describeHandler(handler)
+	for _, method := range methods {
+		e.add(call, framework, EndpointHTTP, method, path, written, handlerName, scope)
+	}
+}
+
+// ginOrEcho returns the framework of GET-style registrations.
+func (e *routeExtractor) ginOrEcho() string {
+	if e.frameworks["echo"] {
+		return "echo"
+	}
+	return "gin"
+}
+
+// ginOrEchoHandler returns the handler among the arguments following the
+// path: gin takes middleware first and the handler last, echo the handler
+// first and middleware after it.
+func (e *routeExtractor) ginOrEchoHandler(args []ast.Expr) ast.Expr {
+	if e.frameworks["echo"] {
+		return args[0]
+	}
+	return args[len(args)-1]
+}
+
+// add records an endpoint at node's line.
+func (e *routeExtractor) add(node ast.Node, framework, kind, method, path, handler, handlerName, scope string) {
+	e.endpoints = append(e.endpoints, Endpoint{
+		FilePath:     e.relPath,
+		Line:         e.fset.Position(node.Pos()).Line,
+		Framework:    framework,
+		Kind:         kind,
+		Method:       method,
+		Path:         path,
+		Handler:      handler,
+		HandlerName:  handlerName,
+		HandlerScope: scope,
+	})
+}
+
+// prefixOf returns the path prefix of a router group expression:
+// r.Group("/v1"), r.PathPrefix("/api").Subrouter(), r.With(mw). ok is false
+// for expressions that aren't router groups.
+func (e *routeExtractor) prefixOf(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	switch sel.Sel.Name {
+	case "Group", "PathPrefix":
+		if len(call.Args) == 0 {
+			return "", false
+		}
+		path, ok := e.stringValue(call.Args[0])
+		if !ok {
+			return "", false
+		}
+		return joinRoutePath(e.receiverPrefix(sel.X), path), true
+	case "Subrouter", "With":
+		return e.receiverPrefix(sel.X), true
+	}
+	return "", false
+}
+
+// receiverPrefix returns the path prefix of the router a route is
+// registered on: a group variable, or a group expression.
+func (e *routeExtractor) receiverPrefix(expr ast.Expr) string {
+	if ident, ok := expr.(*ast.Ident); ok {
+		return e.prefixes[ident.Name]
+	}
+	prefix, _ := e.prefixOf(expr)
+	return prefix
+}
+
+// stringValue returns the value of a string literal or file constant.
+func (e *routeExtractor) stringValue(expr ast.Expr) (string, bool) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(v.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := e.consts[v.Name]
+		return s, ok
+	}
+	return "", false
+}
+
+// stringArgs returns the string values among exprs, upper-cased (HTTP
+// methods); http.MethodGet-style constants are read by name.
+func (e *routeExtractor) stringArgs(exprs []ast.Expr) []string {
+	var values []string
+	for _, expr := range exprs {
+		if sel, ok := expr.(*ast.SelectorExpr); ok && strings.HasPrefix(sel.Sel.Name, "Method") {
+			values = append(values, strings.ToUpper(strings.TrimPrefix(sel.Sel.Name, "Method")))
+			continue
+		}
+		if s, ok := e.stringValue(expr); ok {
+			values = append(values, strings.ToUpper(s))
+		}
+	}
+	return values
+}
+
+// describeHandler returns a handler expression as written, and the name and
+// scope (package, receiver variable or field) its function resolves by.
+// Adapters such as http.HandlerFunc(h) are unwrapped; function literals and
+// constructed handlers have no name.
+func describeHandler(expr ast.Expr) (written, name, scope string) {
+	switch h := expr.(type) {
+	case *ast.Ident:
+		return h.Name, h.Name, ""
+	case *ast.SelectorExpr:
+		switch x := h.X.(type) {
+		case *ast.Ident:
+			scope = x.Name
+		case *ast.SelectorExpr:
+			scope = x.Sel.Name
+		}
+		return types.ExprString(h), h.Sel.Name, scope
+	case *ast.CallExpr:
+		if sel, ok := h.Fun.(*ast.SelectorExpr); ok {
+			if i, ok := handlerAdapters[sel.Sel.Name]; ok && i < len(h.Args) {
+				return describeHandler(h.Args[i])
+			}
+		}
+		return types.ExprString(h), "", ""
+	case *ast.FuncLit:
+		return "", "", ""
+	}
+	return types.ExprString(expr), "", ""
+}
+
+// isHTTPMethod reports whether name is an upper-case HTTP method.
+func isHTTPMethod(name string) bool {
+	for _, m := range httpMethods {
+		if name == m {
+			return true
+		}
+	}
+	return false
+}
+
+// joinRoutePath appends a route path to a group prefix.
+func joinRoutePath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	case !strings.HasPrefix(path, "/"):
+		path = "/" + path
+	}
+	return strings.TrimSuffix(prefix, "/") + path
+}
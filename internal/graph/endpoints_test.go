@@ -0,0 +1,234 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for route extraction:
+// - net/http Go 1.22 patterns carry their method; plain patterns accept ANY
+// - chi Route/Group nesting, Get/Method registrations and handler adapters
+// - gin and echo groups, GET-style registrations and where each takes its handler
+// - gorilla PathPrefix subrouters and Methods chains
+// - connect-go and grpc-go procedure constants become rpc endpoints
+// - Test files and files without a router import have no endpoints
+
+func extractTestEndpoints(t *testing.T, relPath, source string) []Endpoint {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := NewExtractor(dir).ExtractCodeStructure(path)
+	require.NoError(t, err)
+	return result.Endpoints
+}
+
+// routes summarizes endpoints as "METHOD path -> handler (name, scope)".
+func routes(endpoints []Endpoint) []string {
+	var out []string
+	for _, ep := range endpoints {
+		out = append(out, fmt.Sprintf("%s %s -> %s (%s, %s)", ep.Method, ep.Path, ep.Handler, ep.HandlerName, ep.HandlerScope))
+	}
+	return out
+}
+
+func TestExtractEndpoints_NetHTTP(t *testing.T) {
+	t.Parallel()
+
+	endpoints := extractTestEndpoints(t, "server/routes.go", `package server
+
+import "net/http"
+
+const healthPath = "/healthz"
+
+func (s *Server) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /orders/{id}", s.getOrder)
+	mux.Handle("POST example.com/orders", http.HandlerFunc(s.createOrder))
+	mux.HandleFunc(healthPath, health)
+	http.Handle("/static/", http.StripPrefix("/static/", files))
+	mux.HandleFunc("/inline", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc(dynamicPath(), health)
+}
+`)
+
+	assert.Equal(t, []string{
+		"GET /orders/{id} -> s.getOrder (getOrder, s)",
+		"POST /orders -> s.createOrder (createOrder, s)",
+		"ANY /healthz -> health (health, )",
+		"ANY /static/ -> files (files, )",
+		"ANY /inline ->  (, )",
+	}, routes(endpoints))
+	for _, ep := range endpoints {
+		assert.Equal(t, "net/http", ep.Framework)
+		assert.Equal(t, EndpointHTTP, ep.Kind)
+		assert.Equal(t, "server/routes.go", ep.FilePath)
+	}
+	assert.Equal(t, 8, endpoints[0].Line)
+}
+
+func TestExtractEndpoints_Chi(t *testing.T) {
+	t.Parallel()
+
+	endpoints := extractTestEndpoints(t, "api/router.go", `package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func NewRouter(h *handlers.Orders) http.Handler {
+	r := chi.NewRouter()
+	r.Get("/health", health)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/", h.List)
+			r.Post("/", h.Create)
+			r.Get("/{id}", h.Get)
+		})
+		r.Group(func(r chi.Router) {
+			r.Method("DELETE", "/sessions/{id}", http.HandlerFunc(deleteSession))
+		})
+	})
+	r.With(auth).Put("/me", updateMe)
+	return r
+}
+`)
+
+	assert.Equal(t, []string{
+		"GET /health -> health (health, )",
+		"GET /api/v1/orders/ -> h.List (List, h)",
+		"POST /api/v1/orders/ -> h.Create (Create, h)",
+		"GET /api/v1/orders/{id} -> h.Get (Get, h)",
+		"DELETE /api/v1/sessions/{id} -> deleteSession (deleteSession, )",
+		"PUT /me -> updateMe (updateMe, )",
+	}, routes(endpoints))
+	assert.Equal(t, "chi", endpoints[0].Framework)
+}
+
+func TestExtractEndpoints_GinAndEcho(t *testing.T) {
+	t.Parallel()
+
+	gin := extractTestEndpoints(t, "web/gin.go", `package web
+
+import "github.com/gin-gonic/gin"
+
+func Register(r *gin.Engine, ctl *Controller) {
+	v1 := r.Group("/v1")
+	users := v1.Group("/users")
+	users.GET("/:id", auth, ctl.GetUser)
+	users.POST("", ctl.CreateUser)
+	r.Any("/ping", gin.WrapF(ping))
+}
+`)
+	assert.Equal(t, []string{
+		"GET /v1/users/:id -> ctl.GetUser (GetUser, ctl)",
+		"POST /v1/users -> ctl.CreateUser (CreateUser, ctl)",
+		"ANY /ping -> ping (ping, )",
+	}, routes(gin))
+	assert.Equal(t, "gin", gin[0].Framework)
+
+	echo := extractTestEndpoints(t, "web/echo.go", `package web
+
+import "github.com/labstack/echo/v4"
+
+func Register(e *echo.Echo) {
+	admin := e.Group("/admin")
+	admin.GET("/stats", stats, requireAdmin)
+	e.Match([]string{"GET", "POST"}, "/search", search)
+}
+`)
+	assert.Equal(t, []string{
+		"GET /admin/stats -> stats (stats, )",
+		"GET /search -> search (search, )",
+		"POST /search -> search (search, )",
+	}, routes(echo))
+	assert.Equal(t, "echo", echo[0].Framework)
+}
+
+func TestExtractEndpoints_Gorilla(t *testing.T) {
+	t.Parallel()
+
+	endpoints := extractTestEndpoints(t, "web/mux.go", `package web
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func Routes(r *mux.Router) {
+	api := r.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/items/{id}", getItem).Methods("GET", http.MethodHead)
+	api.HandleFunc("/items", listItems)
+}
+`)
+
+	assert.Equal(t, []string{
+		"GET /api/items/{id} -> getItem (getItem, )",
+		"HEAD /api/items/{id} -> getItem (getItem, )",
+		"ANY /api/items -> listItems (listItems, )",
+	}, routes(endpoints))
+	assert.Equal(t, "gorilla", endpoints[0].Framework)
+}
+
+func TestExtractEndpoints_RPC(t *testing.T) {
+	t.Parallel()
+
+	connect := extractTestEndpoints(t, "gen/indexerv1connect/indexer.connect.go", `package indexerv1connect
+
+const (
+	IndexerServiceName = "cortex.v1.IndexerService"
+	IndexerServiceIndexProcedure = "/cortex.v1.IndexerService/Index"
+)
+`)
+	require.Len(t, connect, 1)
+	assert.Equal(t, Endpoint{
+		FilePath:     "gen/indexerv1connect/indexer.connect.go",
+		Line:         5,
+		Framework:    "connect",
+		Kind:         EndpointRPC,
+		Method:       "POST",
+		Path:         "/cortex.v1.IndexerService/Index",
+		Handler:      "IndexerServiceHandler.Index",
+		HandlerName:  "Index",
+		HandlerScope: "IndexerServiceHandler",
+	}, connect[0])
+
+	grpc := extractTestEndpoints(t, "gen/indexerv1/indexer_grpc.pb.go", `package indexerv1
+
+const (
+	IndexerService_Index_FullMethodName = "/cortex.v1.IndexerService/Index"
+)
+`)
+	assert.Equal(t, []string{"POST /cortex.v1.IndexerService/Index -> IndexerServiceServer.Index (Index, IndexerServiceServer)"}, routes(grpc))
+	assert.Equal(t, "grpc", grpc[0].Framework)
+}
+
+func TestExtractEndpoints_Skipped(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, extractTestEndpoints(t, "server/routes_test.go", `package server
+
+import "net/http"
+
+func setup(mux *http.ServeMux) {
+	mux.HandleFunc("/fake", fake)
+}
+`))
+	assert.Empty(t, extractTestEndpoints(t, "cache/cache.go", `package cache
+
+func (c *Cache) Get(path string, v any) {}
+
+func warm(c *Cache) {
+	c.Get("/orders", nil)
+}
+`))
+}
@@ -23,7 +23,8 @@ type Extractor interface {
 
 // goExtractor implements Extractor for Go files using go/ast.
 type goExtractor struct {
-	rootDir string // Project root directory for relative paths
+	rootDir    string // Project root directory for relative paths
+	pathPrefix string // Prepended to relative paths (content sources: "@<label>/")
 }
 
 // NewExtractor creates a new graph extractor for Go files.
@@ -31,13 +32,26 @@ func NewExtractor(rootDir string) Extractor {
 	return &goExtractor{rootDir: rootDir}
 }
 
-// ExtractFile extracts nodes and edges from a Go source file.
-func (e *goExtractor) ExtractFile(filePath string) (*FileGraphData, error) {
-	// Get relative path for consistency
+// NewPrefixedExtractor creates a graph extractor for Go files stored under
+// prefix followed by their path relative to rootDir, such as the files of a
+// content source ("@wiki/").
+func NewPrefixedExtractor(rootDir, prefix string) Extractor {
+	return &goExtractor{rootDir: rootDir, pathPrefix: prefix}
+}
+
+// relPath returns the stored path of filePath.
+func (e *goExtractor) relPath(filePath string) string {
 	relPath, err := filepath.Rel(e.rootDir, filePath)
 	if err != nil {
-		relPath = filePath
+		return filePath
 	}
+	return e.pathPrefix + filepath.ToSlash(relPath)
+}
+
+// ExtractFile extracts nodes and edges from a Go source file.
+func (e *goExtractor) ExtractFile(filePath string) (*FileGraphData, error) {
+	// Get relative path for consistency
+	relPath := e.relPath(filePath)
 
 	// Parse file
 	fset := token.NewFileSet()
@@ -111,10 +125,7 @@ func (e *goExtractor) ExtractFile(filePath string) (*FileGraphData, error) {
 // This is the new extraction method that outputs domain structs directly.
 func (e *goExtractor) ExtractCodeStructure(filePath string) (*CodeStructure, error) {
 	// Get relative path for consistency
-	relPath, err := filepath.Rel(e.rootDir, filePath)
-	if err != nil {
-		relPath = filePath
-	}
+	relPath := e.relPath(filePath)
 
 	// Parse file
 	fset := token.NewFileSet()
@@ -181,6 +192,14 @@ func (e *goExtractor) ExtractCodeStructure(filePath string) (*CodeStructure, err
 		}
 	}
 
+	// Routes registered, variables read and tables queried by tests are
+	// fixtures, not part of the service
+	if !isTestFile(relPath) {
+		result.Endpoints = extractEndpoints(node, fset, relPath)
+		result.ConfigUsages = extractConfigReads(node, fset, relPath)
+		result.TableUsages = extractTableUsages(node, fset, relPath)
+	}
+
 	return result, nil
 }
 
@@ -205,6 +224,12 @@ func (e *goExtractor) extractTypeToCodeStructure(
 	endByteOffset := endPos.Offset
 	typeID := fmt.Sprintf("%s::%s", pkgPath, typeName)
 	isExported := len(typeName) > 0 && typeName[0] >= 'A' && typeName[0] <= 'Z'
+	// An ungrouped declaration keeps its doc comment on genDecl
+	docGroup := typeSpec.Doc
+	if docGroup == nil && !genDecl.Lparen.IsValid() {
+		docGroup = genDecl.Doc
+	}
+	doc := strings.TrimSpace(docGroup.Text())
 
 	switch typeExpr := typeSpec.Type.(type) {
 	case *ast.InterfaceType:
@@ -222,6 +247,7 @@ func (e *goExtractor) extractTypeToCodeStructure(
 			StartPos:    startByteOffset,
 			EndPos:      endByteOffset,
 			IsExported:  isExported,
+			Doc:         doc,
 			FieldCount:  0,                // Interfaces don't have fields
 			MethodCount: len(methods),
 			Methods:     methods,
@@ -243,6 +269,7 @@ func (e *goExtractor) extractTypeToCodeStructure(
 			StartPos:    startByteOffset,
 			EndPos:      endByteOffset,
 			IsExported:  isExported,
+			Doc:         doc,
 			FieldCount:  len(fields),
 			MethodCount: 0, // Methods added separately during function extraction
 			Fields:      fields,
@@ -263,6 +290,7 @@ func (e *goExtractor) extractTypeToCodeStructure(
 			StartPos:    startByteOffset,
 			EndPos:      endByteOffset,
 			IsExported:  isExported,
+			Doc:         doc,
 			FieldCount:  0,
 			MethodCount: 0, // Methods added separately during function extraction
 		})
@@ -512,6 +540,7 @@ func (e *goExtractor) extractFunctionToCodeStructure(
 		ReceiverTypeName: receiverTypeName,
 		ParamCount:       paramCount,
 		ReturnCount:      returnCount,
+		Doc:              strings.TrimSpace(decl.Doc.Text()),
 		Parameters:       params,
 		ReturnValues:     returns,
 	})
@@ -913,9 +942,13 @@ func extractReceiverType(expr ast.Expr) string {
 		return t.Name
 	case *ast.StarExpr:
 		// (*T) receiver
-		if ident, ok := t.X.(*ast.Ident); ok {
-			return ident.Name
-		}
+		return extractReceiverType(t.X)
+	case *ast.IndexExpr:
+		// (T[K]) receiver of a generic type
+		return extractReceiverType(t.X)
+	case *ast.IndexListExpr:
+		// (T[K, V]) receiver of a generic type
+		return extractReceiverType(t.X)
 	}
 	return "unknown"
 }
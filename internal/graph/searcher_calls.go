@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// confidenceRank orders call confidence levels; when a function calls the
+// target several ways, its result reports the most trustworthy one.
+var confidenceRank = map[string]int{
+	ConfidenceExact:      4,
+	ConfidenceDynamic:    3,
+	ConfidenceUnresolved: 2,
+	ConfidenceSyntactic:  1,
+}
+
+// functionNodeID returns the ID results use for a function row: methods are
+// shown as Receiver.Name, functions by their function ID.
+func functionNodeID(functionID, name string, isMethod bool, receiverName sql.NullString) string {
+	if isMethod && receiverName.Valid {
+		return receiverName.String + "." + name
+	}
+	return functionID
+}
+
+// annotateCallConfidence sets Confidence on the direct (depth 1) callers or
+// callees in resp. Calls without a recorded resolution were extracted
+// syntactically.
+func (s *sqlSearcher) annotateCallConfidence(ctx context.Context, tx *sql.Tx, resp *QueryResponse, target string, callers bool) error {
+	if len(resp.Results) == 0 {
+		return nil
+	}
+
+	confidence := "COALESCE(cr.confidence, '" + ConfidenceSyntactic + "')"
+	join := "LEFT JOIN call_resolutions cr ON cr.call_id = fc.call_id"
+
+	query := `
+		SELECT f.function_id, f.name, f.is_method, f.receiver_type_name, ` + confidence + `
+		FROM function_calls fc
+		JOIN functions f ON f.function_id = fc.callee_function_id
+		` + join + `
+		WHERE fc.caller_function_id = ?
+	`
+	args := []interface{}{target}
+	if callers {
+		query = `
+			SELECT f.function_id, f.name, f.is_method, f.receiver_type_name, ` + confidence + `
+			FROM function_calls fc
+			JOIN functions f ON f.function_id = fc.caller_function_id
+			` + join + `
+			WHERE fc.callee_function_id = ? OR fc.callee_name = ?
+		`
+		args = append(args, target)
+	}
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("query call confidence: %w", err)
+	}
+	defer rows.Close()
+
+	best := make(map[string]string)
+	for rows.Next() {
+		var functionID, name, level string
+		var isMethod bool
+		var receiverName sql.NullString
+		if err := rows.Scan(&functionID, &name, &isMethod, &receiverName, &level); err != nil {
+			return fmt.Errorf("scan call confidence: %w", err)
+		}
+		id := functionNodeID(functionID, name, isMethod, receiverName)
+		if confidenceRank[level] > confidenceRank[best[id]] {
+			best[id] = level
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	for i := range resp.Results {
+		if resp.Results[i].Depth == 1 {
+			resp.Results[i].Confidence = best[resp.Results[i].Node.ID]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package graph
+
+// Test Plan for call confidence:
+// - Without call resolutions, direct callers and callees are "syntactic"
+// - Recorded resolutions set the confidence of direct callers and callees
+// - A function calling the target several ways reports the strongest confidence
+// - Transitive (depth > 1) results carry no confidence
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCallConfidenceData indexes Run -> Get (exact and dynamic), Serve -> Run,
+// and Main -> Serve; Main's call has no resolution.
+func setupCallConfidenceData(t *testing.T, db *sql.DB, resolved bool) {
+	t.Helper()
+	for _, fn := range []*Node{
+		{ID: "app/app.go::Run", Kind: NodeFunction, File: "app/app.go", StartLine: 1, EndLine: 5},
+		{ID: "app/app.go::Serve", Kind: NodeFunction, File: "app/app.go", StartLine: 7, EndLine: 9},
+		{ID: "app/app.go::Main", Kind: NodeFunction, File: "app/app.go", StartLine: 11, EndLine: 13},
+		{ID: "store/store.go::Get", Kind: NodeFunction, File: "store/store.go", StartLine: 1, EndLine: 3},
+	} {
+		insertTestFunction(t, db, fn)
+	}
+
+	for _, call := range []struct{ id, caller, callee string }{
+		{"c0", "app/app.go::Run", "store/store.go::Get"},
+		{"c1", "app/app.go::Run", "store/store.go::Get"},
+		{"c2", "app/app.go::Serve", "app/app.go::Run"},
+		{"c3", "app/app.go::Main", "app/app.go::Serve"},
+	} {
+		_, err := db.Exec(`INSERT INTO function_calls (call_id, caller_function_id, callee_function_id, callee_name)
+			VALUES (?, ?, ?, ?)`, call.id, call.caller, call.callee, call.callee)
+		require.NoError(t, err)
+	}
+
+	if !resolved {
+		return
+	}
+	_, err := db.Exec(`
+		INSERT INTO call_resolutions VALUES
+			('c0', 'store/store.go::Get', 'dynamic'),
+			('c1', 'store/store.go::Get', 'exact'),
+			('c2', 'app/app.go::Run', 'exact');
+	`)
+	require.NoError(t, err)
+}
+
+// confidences maps result node IDs to their confidence.
+func confidences(t *testing.T, searcher Searcher, op QueryOperation, target string, depth int) map[string]string {
+	t.Helper()
+	resp, err := searcher.Query(context.Background(), &QueryRequest{
+		Operation: op, Target: target, Depth: depth, MaxResults: DefaultMaxResults,
+	})
+	require.NoError(t, err)
+
+	result := make(map[string]string)
+	for _, r := range resp.Results {
+		result[r.Node.ID] = r.Confidence
+	}
+	return result
+}
+
+func TestSQLSearcher_CallConfidence_Syntactic(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupCallConfidenceData(t, db, false)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"app/app.go::Run": ConfidenceSyntactic},
+		confidences(t, searcher, OperationCallers, "store/store.go::Get", 1))
+	assert.Equal(t, map[string]string{"store/store.go::Get": ConfidenceSyntactic},
+		confidences(t, searcher, OperationCallees, "app/app.go::Run", 1))
+}
+
+func TestSQLSearcher_CallConfidence(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupCallConfidenceData(t, db, true)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	// Exact beats dynamic for the two calls from Run
+	assert.Equal(t, map[string]string{"app/app.go::Run": ConfidenceExact},
+		confidences(t, searcher, OperationCallers, "store/store.go::Get", 1))
+
+	// Transitive callers carry no confidence
+	assert.Equal(t, map[string]string{
+		"app/app.go::Run":   ConfidenceExact,
+		"app/app.go::Serve": "",
+		"app/app.go::Main":  "",
+	}, confidences(t, searcher, OperationCallers, "store/store.go::Get", 3))
+
+	// Unresolved-by-type-checker calls fall back to syntactic
+	assert.Equal(t, map[string]string{"app/app.go::Serve": ConfidenceSyntactic},
+		confidences(t, searcher, OperationCallees, "app/app.go::Main", 1))
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Config key statuses
+const (
+	configDocumented   = "documented"
+	configUndocumented = "undocumented"
+	configUnused       = "unused"
+)
+
+// configUsageRow is a row of the config_usages table.
+type configUsageRow struct {
+	info       ConfigUsageInfo
+	function   string
+	functionID sql.NullString
+}
+
+// queryConfigUsages finds the reads of the environment variables and
+// configuration keys matching the target, each returned as the function
+// reading the key with the key in Config, followed by the .env template
+// variables matching the target that no code reads.
+//
+// The target is a key (matched case-insensitively), a prefix ending in "*"
+// ("DATABASE_*", "server.*"), or "*" for every key read in the project.
+// Reads outside indexed functions (top-level code, other languages' methods)
+// are returned as config nodes at the read.
+func (s *sqlSearcher) queryConfigUsages(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	resp := &QueryResponse{
+		Operation: string(req.Operation),
+		Target:    req.Target,
+		Results:   []QueryResult{},
+	}
+
+	definitions, err := s.configDefinitions(ctx, tx, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	usages, err := s.matchConfigUsages(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	unused, err := s.unusedConfigKeys(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(usages) == 0 && len(unused) == 0 {
+		resp.Suggestion = fmt.Sprintf(`No read of %q (use a key such as DATABASE_URL or server.port, a prefix such as "DATABASE_*", or "*")`, req.Target)
+		return resp, nil
+	}
+
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultMaxResults
+	}
+	for _, u := range usages {
+		if len(resp.Results) >= maxResults {
+			resp.Truncated = true
+			break
+		}
+		info := u.info
+		info.DefinedIn = definitions[info.Kind+":"+info.Key]
+		info.Status = configUndocumented
+		if len(info.DefinedIn) > 0 {
+			info.Status = configDocumented
+		}
+
+		var node *Node
+		if u.functionID.Valid {
+			if node, err = s.functionNode(ctx, tx, u.functionID.String); err != nil {
+				return nil, err
+			}
+		}
+		if node == nil {
+			id := info.File
+			if u.function != "" {
+				id = info.File + "::" + u.function
+			}
+			node = &Node{ID: id, Kind: NodeConfig, File: info.File, StartLine: info.Line, EndLine: info.Line}
+		}
+		result := s.hierarchyResult(node, 0, req)
+		result.Config = &info
+		resp.Results = append(resp.Results, result)
+	}
+	for _, info := range unused {
+		if len(resp.Results) >= maxResults {
+			resp.Truncated = true
+			break
+		}
+		info := info
+		node := &Node{ID: info.Key, Kind: NodeConfigKey, File: info.File, StartLine: info.Line, EndLine: info.Line}
+		result := s.hierarchyResult(node, 0, req)
+		result.Config = &info
+		resp.Results = append(resp.Results, result)
+	}
+
+	resp.TotalFound = len(resp.Results)
+	resp.TotalReturned = len(resp.Results)
+	return resp, nil
+}
+
+// matchConfigUsages returns the reads of the keys matching req.Target,
+// ordered by key and location.
+func (s *sqlSearcher) matchConfigUsages(ctx context.Context, tx *sql.Tx, req *QueryRequest) ([]configUsageRow, error) {
+	where, args := nameFilter("key", req.Target)
+	query := `
+		SELECT key, kind, source, file_path, line, function, function_id
+		FROM config_usages
+		WHERE ` + where
+	query = s.applyFilters(query, req, &args)
+	query += " ORDER BY key, file_path, line"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []configUsageRow
+	for rows.Next() {
+		var u configUsageRow
+		err := rows.Scan(&u.info.Key, &u.info.Kind, &u.info.Source, &u.info.File, &u.info.Line, &u.function, &u.functionID)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		usages = append(usages, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return usages, nil
+}
+
+// configDefinitions returns where the keys matching target are defined, as
+// file:line by "kind:key".
+func (s *sqlSearcher) configDefinitions(ctx context.Context, tx *sql.Tx, target string) (map[string][]string, error) {
+	where, args := nameFilter("key", target)
+	rows, err := tx.QueryContext(ctx, `
+		SELECT key, kind, file_path, line
+		FROM config_key_definitions
+		WHERE `+where+`
+		ORDER BY file_path, line
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	definitions := make(map[string][]string)
+	for rows.Next() {
+		var key, kind, file string
+		var line int
+		if err := rows.Scan(&key, &kind, &file, &line); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		definitions[kind+":"+key] = append(definitions[kind+":"+key], fmt.Sprintf("%s:%d", file, line))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return definitions, nil
+}
+
+// unusedConfigKeys returns the .env template variables matching req.Target
+// that no code reads.
+func (s *sqlSearcher) unusedConfigKeys(ctx context.Context, tx *sql.Tx, req *QueryRequest) ([]ConfigUsageInfo, error) {
+	where, args := nameFilter("key", req.Target)
+	query := `
+		SELECT key, file_path, line
+		FROM unused_config_keys
+		WHERE ` + where
+	query = s.applyFilters(query, req, &args)
+	query += " ORDER BY key, file_path, line"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var unused []ConfigUsageInfo
+	for rows.Next() {
+		info := ConfigUsageInfo{Kind: ConfigKindEnv, Status: configUnused}
+		if err := rows.Scan(&info.Key, &info.File, &info.Line); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		info.DefinedIn = []string{fmt.Sprintf("%s:%d", info.File, info.Line)}
+		unused = append(unused, info)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return unused, nil
+}
+
+// nameFilter returns the condition on a name column a target selects:
+// every name for "*", names starting with a prefix ending in "*", else the
+// name, case-insensitively.
+func nameFilter(column, target string) (string, []interface{}) {
+	target = strings.TrimSpace(target)
+	switch {
+	case target == "" || target == "*":
+		return "1 = 1", []interface{}{}
+	case strings.HasSuffix(target, "*"):
+		prefix := strings.TrimSuffix(target, "*")
+		return "substr(" + column + ", 1, ?) = ? COLLATE NOCASE", []interface{}{utf8.RuneCountInString(prefix), prefix}
+	}
+	return column + " = ? COLLATE NOCASE", []interface{}{target}
+}
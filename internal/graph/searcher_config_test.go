@@ -0,0 +1,97 @@
+package graph
+
+// Test Plan for the config_usages operation:
+// - Without reads, any key returns the no-read suggestion
+// - A key (any case) returns its reads: the reading function, or a config node
+//   outside indexed functions, with where the key is defined
+// - Prefix targets ("DATABASE_*") and "*" select several keys; unused .env
+//   template variables follow the reads as config_key nodes
+// - Scope filters by file; unknown keys return a suggestion
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupConfigUsageData indexes reads of DATABASE_URL (in main, documented
+// in .env.example), DATABASE_POOL (top-level, undocumented) and PORT (in a
+// TypeScript function), and an unused DATABASE_REPLICA variable.
+func setupConfigUsageData(t *testing.T, db *sql.DB) {
+	t.Helper()
+	insertTestFunction(t, db, &Node{ID: "cmd/api/main.go::main", Kind: NodeFunction, File: "cmd/api/main.go", StartLine: 5, EndLine: 12})
+
+	_, err := db.Exec(`
+		INSERT INTO config_usages (file_path, line, kind, key, source, function, function_id) VALUES
+			('cmd/api/main.go', 7, 'env', 'DATABASE_URL', 'os.Getenv', 'main', 'cmd/api/main.go::main'),
+			('cmd/api/config.go', 3, 'env', 'DATABASE_POOL', 'os.Getenv', '', NULL),
+			('web/server.ts', 2, 'env', 'PORT', 'process.env', 'listen', NULL);
+
+		INSERT INTO config_key_definitions VALUES
+			('DATABASE_URL', 'env', '.env.example', 'DATABASE_URL', 'postgres://localhost', 1),
+			('DATABASE_URL', 'env', 'deploy/api.yaml', 'env[0].name', 'DATABASE_URL', 12),
+			('PORT', 'env', '.env.example', 'PORT', '3000', 2);
+
+		INSERT INTO unused_config_keys VALUES ('.env.example', 3, 'DATABASE_REPLICA', '');
+	`)
+	require.NoError(t, err)
+}
+
+// configResults summarizes results as "kind node-id key status defined-in".
+func configResults(resp *QueryResponse) []string {
+	var out []string
+	for _, r := range resp.Results {
+		out = append(out, fmt.Sprintf("%s %s %s %s %v", r.Node.Kind, r.Node.ID, r.Config.Key, r.Config.Status, r.Config.DefinedIn))
+	}
+	return out
+}
+
+func TestSQLSearcher_ConfigUsages(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+	query := func(target, scope string) *QueryResponse {
+		t.Helper()
+		resp, err := searcher.Query(context.Background(), &QueryRequest{
+			Operation: OperationConfigUsages, Target: target, Scope: scope, MaxResults: DefaultMaxResults,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := query("DATABASE_URL", "")
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, `No read of "DATABASE_URL"`)
+
+	setupConfigUsageData(t, db)
+
+	resp = query("database_url", "")
+	assert.Equal(t, []string{
+		"function cmd/api/main.go::main DATABASE_URL documented [.env.example:1 deploy/api.yaml:12]",
+	}, configResults(resp))
+	assert.Equal(t, "os.Getenv", resp.Results[0].Config.Source)
+	assert.Equal(t, "cmd/api/main.go", resp.Results[0].Config.File)
+	assert.Equal(t, 7, resp.Results[0].Config.Line)
+
+	assert.Equal(t, []string{
+		"config cmd/api/config.go DATABASE_POOL undocumented []",
+		"function cmd/api/main.go::main DATABASE_URL documented [.env.example:1 deploy/api.yaml:12]",
+		"config_key DATABASE_REPLICA DATABASE_REPLICA unused [.env.example:3]",
+	}, configResults(query("DATABASE_*", "")))
+
+	assert.Equal(t, []string{
+		"config web/server.ts::listen PORT documented [.env.example:2]",
+	}, configResults(query("*", "web/%")))
+	assert.Len(t, query("*", "").Results, 4)
+
+	resp = query("REDIS_URL", "")
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, `No read of "REDIS_URL"`)
+}
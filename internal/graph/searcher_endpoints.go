@@ -0,0 +1,214 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// endpointRow is a row of the endpoints table.
+type endpointRow struct {
+	info      EndpointInfo
+	handlerID sql.NullString
+}
+
+// queryEndpoint finds the routes matching the target and returns, for each,
+// its handler (depth 0, with the route in Endpoint) followed by the
+// handler's callees up to req.Depth, whose Parent names the route.
+//
+// The target is "METHOD /path", a path, or text found in the path or
+// handler ("OrderService", "orders#index"). Paths match route patterns
+// segment by segment, with parameters ({id}, :id, <int:id>) matching any
+// segment; routes matching literally take precedence. Routes whose handler
+// isn't an indexed function are returned as endpoint nodes at their
+// registration.
+func (s *sqlSearcher) queryEndpoint(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	resp := &QueryResponse{
+		Operation: string(req.Operation),
+		Target:    req.Target,
+		Results:   []QueryResult{},
+	}
+
+	endpoints, err := s.matchEndpoints(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	if len(endpoints) == 0 {
+		resp.Suggestion = fmt.Sprintf(`No endpoint matches %q (use "METHOD /path", a path such as /api/orders/{id}, or a handler name)`, req.Target)
+		return resp, nil
+	}
+
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultMaxResults
+	}
+	for _, e := range endpoints {
+		if len(resp.Results) >= maxResults {
+			resp.Truncated = true
+			break
+		}
+		info := e.info
+		info.Resolved = e.handlerID.Valid
+
+		node, err := s.endpointHandler(ctx, tx, e)
+		if err != nil {
+			return nil, err
+		}
+		result := s.hierarchyResult(node, 0, req)
+		result.Endpoint = &info
+		resp.Results = append(resp.Results, result)
+		if !e.handlerID.Valid {
+			continue
+		}
+
+		query, args := s.buildCalleesSQL(e.handlerID.String, req.Depth, maxResults-len(resp.Results), req)
+		callees, err := s.executeFunctionQuery(ctx, tx, query, args, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, callee := range callees.Results {
+			callee.Parent = info.Method + " " + info.Path
+			resp.Results = append(resp.Results, callee)
+		}
+		if callees.Truncated {
+			resp.Truncated = true
+			resp.TruncatedAt = req.Depth
+		}
+	}
+
+	resp.TotalFound = len(resp.Results)
+	resp.TotalReturned = len(resp.Results)
+	return resp, nil
+}
+
+// matchEndpoints returns the routes matching req.Target, ordered by path
+// and method.
+func (s *sqlSearcher) matchEndpoints(ctx context.Context, tx *sql.Tx, req *QueryRequest) ([]endpointRow, error) {
+	method, target := "", strings.TrimSpace(req.Target)
+	if m, rest, ok := strings.Cut(target, " "); ok && (isHTTPMethod(strings.ToUpper(m)) || strings.EqualFold(m, "ANY")) {
+		method, target = strings.ToUpper(m), strings.TrimSpace(rest)
+	}
+
+	query := `
+		SELECT method, path, kind, framework, handler, file_path, line, handler_function_id
+		FROM endpoints
+		WHERE 1 = 1
+	`
+	args := []interface{}{}
+	query = s.applyFilters(query, req, &args)
+	query += " ORDER BY path, method, file_path, line"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var exact, matched []endpointRow
+	for rows.Next() {
+		var e endpointRow
+		err := rows.Scan(&e.info.Method, &e.info.Path, &e.info.Kind, &e.info.Framework,
+			&e.info.Handler, &e.info.File, &e.info.Line, &e.handlerID)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if method != "" && e.info.Method != method && e.info.Method != "ANY" {
+			continue
+		}
+
+		switch {
+		case target == "" || e.info.Path == target:
+			exact = append(exact, e)
+		case strings.HasPrefix(target, "/"):
+			if routeMatches(e.info.Path, target) {
+				matched = append(matched, e)
+			}
+		case strings.Contains(strings.ToLower(e.info.Path), strings.ToLower(target)) ||
+			strings.Contains(strings.ToLower(e.info.Handler), strings.ToLower(target)):
+			matched = append(matched, e)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(exact) > 0 {
+		return exact, nil
+	}
+	return matched, nil
+}
+
+// endpointHandler returns the node of a route's handler function, or an
+// endpoint node at the route's registration if it has none.
+func (s *sqlSearcher) endpointHandler(ctx context.Context, tx *sql.Tx, e endpointRow) (*Node, error) {
+	if e.handlerID.Valid {
+		node, err := s.functionNode(ctx, tx, e.handlerID.String)
+		if err != nil || node != nil {
+			return node, err
+		}
+	}
+
+	return &Node{
+		ID:        e.info.Method + " " + e.info.Path,
+		Kind:      NodeEndpoint,
+		File:      e.info.File,
+		StartLine: e.info.Line,
+		EndLine:   e.info.Line,
+	}, nil
+}
+
+// functionNode returns the node of an indexed function, or nil if there is
+// no function with that ID.
+func (s *sqlSearcher) functionNode(ctx context.Context, tx *sql.Tx, functionID string) (*Node, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT
+			f.function_id, f.file_path, f.start_line, f.end_line,
+			f.start_pos, f.end_pos,
+			f.name, f.module_path, f.is_method, f.receiver_type_name,
+			0 as depth
+		FROM functions f
+		WHERE f.function_id = ?
+	`, functionID)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+	if rows.Next() {
+		node, _, err := s.scanFunctionRow(rows)
+		return node, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return nil, nil
+}
+
+// routeMatches reports whether a request path matches a route pattern:
+// segments are equal, or the route's segment is a parameter ({id}, :id,
+// <int:id>, *). A trailing wildcard ({path...}, *) matches the rest.
+func routeMatches(pattern, path string) bool {
+	routeSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range routeSegments {
+		wildcard := segment == "*" || strings.HasSuffix(segment, "...}") || strings.HasPrefix(segment, "*")
+		if wildcard && i == len(routeSegments)-1 {
+			return len(pathSegments) >= i
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if segment != pathSegments[i] && !isRouteParam(segment) && !isRouteParam(pathSegments[i]) {
+			return false
+		}
+	}
+	return len(routeSegments) == len(pathSegments)
+}
+
+// isRouteParam reports whether a path segment is a route parameter in the
+// syntax of any supported framework.
+func isRouteParam(segment string) bool {
+	return strings.HasPrefix(segment, ":") ||
+		(strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")) ||
+		(strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">"))
+}
@@ -0,0 +1,127 @@
+package graph
+
+// Test Plan for the endpoint operation:
+// - Without endpoints, any target returns the no-match suggestion
+// - "METHOD /path" returns the handler (depth 0) with its route, then its callees
+// - Concrete paths match parameterized routes; literal routes take precedence
+// - Text targets match paths and handlers; unresolved handlers are endpoint nodes
+// - Unknown targets return a suggestion
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupEndpointData indexes routes served by Orders.Get (which calls
+// store Get), Orders.Search and an inline handler.
+func setupEndpointData(t *testing.T, db *sql.DB) {
+	t.Helper()
+	setupCallConfidenceData(t, db, false)
+	for _, fn := range []*Node{
+		{ID: "api/orders.go::Get", Kind: NodeFunction, File: "api/orders.go", StartLine: 10, EndLine: 20},
+		{ID: "api/orders.go::Search", Kind: NodeFunction, File: "api/orders.go", StartLine: 22, EndLine: 30},
+	} {
+		insertTestFunction(t, db, fn)
+	}
+	_, err := db.Exec(`INSERT INTO function_calls (call_id, caller_function_id, callee_function_id, callee_name)
+		VALUES ('e0', 'api/orders.go::Get', 'app/app.go::Run', 'Run')`)
+	require.NoError(t, err)
+
+	_, err = db.Exec(`
+		INSERT INTO endpoints (file_path, line, framework, kind, method, path, handler, handler_function_id) VALUES
+			('api/router.go', 5, 'chi', 'http', 'GET', '/orders/{id}', 'h.Get', 'api/orders.go::Get'),
+			('api/router.go', 6, 'chi', 'http', 'GET', '/orders/search', 'h.Search', 'api/orders.go::Search'),
+			('api/router.go', 7, 'chi', 'http', 'POST', '/orders', '', NULL);
+	`)
+	require.NoError(t, err)
+}
+
+// endpointResults summarizes results as "depth node-id parent".
+func endpointResults(resp *QueryResponse) []string {
+	var out []string
+	for _, r := range resp.Results {
+		out = append(out, fmt.Sprintf("%d %s %s", r.Depth, r.Node.ID, r.Parent))
+	}
+	return out
+}
+
+func TestSQLSearcher_Endpoint(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+	query := func(target string, depth int) *QueryResponse {
+		t.Helper()
+		resp, err := searcher.Query(context.Background(), &QueryRequest{
+			Operation: OperationEndpoint, Target: target, Depth: depth, MaxResults: DefaultMaxResults,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := query("GET /orders/{id}", 1)
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, "No endpoint matches")
+
+	setupEndpointData(t, db)
+
+	// Route pattern, with callees two levels deep
+	resp = query("GET /orders/{id}", 2)
+	assert.Equal(t, []string{
+		"0 api/orders.go::Get ",
+		"1 app/app.go::Run GET /orders/{id}",
+		"2 store/store.go::Get GET /orders/{id}",
+	}, endpointResults(resp))
+	require.NotNil(t, resp.Results[0].Endpoint)
+	assert.Equal(t, EndpointInfo{
+		Method: "GET", Path: "/orders/{id}", Kind: EndpointHTTP, Framework: "chi",
+		Handler: "h.Get", File: "api/router.go", Line: 5, Resolved: true,
+	}, *resp.Results[0].Endpoint)
+	assert.Nil(t, resp.Results[1].Endpoint)
+
+	// A concrete path matches the parameterized route; a literal route wins
+	assert.Equal(t, []string{"0 api/orders.go::Get ", "1 app/app.go::Run GET /orders/{id}"},
+		endpointResults(query("/orders/42", 1)))
+	assert.Equal(t, []string{"0 api/orders.go::Search "}, endpointResults(query("get /orders/search", 1)))
+
+	// Text matches handlers; inline handlers are endpoint nodes
+	assert.Equal(t, []string{"0 api/orders.go::Search "}, endpointResults(query("h.Search", 1)))
+	resp = query("POST /orders", 1)
+	require.Len(t, resp.Results, 1)
+	assert.Equal(t, NodeEndpoint, resp.Results[0].Node.Kind)
+	assert.Equal(t, "POST /orders", resp.Results[0].Node.ID)
+	assert.Equal(t, "api/router.go", resp.Results[0].Node.File)
+	assert.False(t, resp.Results[0].Endpoint.Resolved)
+
+	resp = query("DELETE /orders/1", 1)
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, "No endpoint matches")
+}
+
+func TestRouteMatches(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		route, path string
+		want        bool
+	}{
+		{"/orders/{id}", "/orders/42", true},
+		{"/orders/:id", "/orders/42", true},
+		{"/orders/<int:pk>/", "/orders/42", true},
+		{"/orders/{id}", "/orders/42/items", false},
+		{"/orders", "/users", false},
+		{"/static/{path...}", "/static/css/site.css", true},
+		{"/files/*", "/files/a/b", true},
+		{"/orders/{id}", "/orders/:id", true},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, routeMatches(tt.route, tt.path), "%s ~ %s", tt.route, tt.path)
+	}
+}
@@ -0,0 +1,235 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// hierarchyRelationships are the type_relationships edges that make up a type
+// hierarchy: inferred Go implements/embeds and declared extends/implements/mixin.
+var hierarchyRelationships = []string{
+	string(EdgeExtends), string(EdgeImplements), string(EdgeEmbeds), string(EdgeMixin),
+}
+
+// maxAmbiguousSuggestions caps the candidates listed for an ambiguous type name.
+const maxAmbiguousSuggestions = 10
+
+// querySupertypes walks up from the target type: what it extends, implements, embeds or mixes in.
+func (s *sqlSearcher) querySupertypes(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	return s.queryHierarchy(ctx, tx, req, true, false)
+}
+
+// querySubtypes walks down from the target type: what extends, implements, embeds or mixes it in.
+func (s *sqlSearcher) querySubtypes(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	return s.queryHierarchy(ctx, tx, req, false, true)
+}
+
+// queryTypeHierarchy returns the target type (depth 0) with its supertypes and subtypes.
+func (s *sqlSearcher) queryTypeHierarchy(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	return s.queryHierarchy(ctx, tx, req, true, true)
+}
+
+// queryHierarchy walks type_relationships breadth-first from the target type,
+// up to req.Depth levels in each requested direction.
+//
+// Each type appears once, at its shallowest depth, with Parent naming the
+// type it was reached from, so results form a tree even across diamonds.
+// Scope and ExcludePatterns prune the walk at filtered-out types.
+func (s *sqlSearcher) queryHierarchy(ctx context.Context, tx *sql.Tx, req *QueryRequest, up, down bool) (*QueryResponse, error) {
+	resp := &QueryResponse{
+		Operation: string(req.Operation),
+		Target:    req.Target,
+		Results:   []QueryResult{},
+	}
+
+	root, suggestion, err := s.resolveTypeTarget(ctx, tx, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		resp.Suggestion = suggestion
+		return resp, nil
+	}
+
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultMaxResults
+	}
+
+	if up && down {
+		resp.Results = append(resp.Results, s.hierarchyResult(root, 0, req))
+	}
+
+	visited := map[string]bool{root.ID: true}
+	for _, direction := range []struct {
+		enabled bool
+		name    string
+		up      bool
+	}{{up, "supertype", true}, {down, "subtype", false}} {
+		if !direction.enabled {
+			continue
+		}
+
+		frontier := []string{root.ID}
+		for depth := 1; depth <= req.Depth && len(frontier) > 0; depth++ {
+			var next []string
+			for _, parent := range frontier {
+				results, err := s.loadHierarchyEdges(ctx, tx, parent, direction.up, req)
+				if err != nil {
+					return nil, err
+				}
+				for _, result := range results {
+					if visited[result.Node.ID] {
+						continue
+					}
+					if len(resp.Results) >= maxResults {
+						if !resp.Truncated {
+							resp.Truncated = true
+							resp.TruncatedAt = depth
+						}
+						break
+					}
+					visited[result.Node.ID] = true
+
+					result.Depth = depth
+					result.Direction = direction.name
+					resp.Results = append(resp.Results, result)
+					next = append(next, result.Node.ID)
+				}
+			}
+			frontier = next
+		}
+	}
+
+	resp.TotalFound = len(resp.Results)
+	resp.TotalReturned = len(resp.Results)
+	return resp, nil
+}
+
+// loadHierarchyEdges returns the direct supertypes (up) or subtypes of typeID,
+// each with the relationship and where it is declared.
+func (s *sqlSearcher) loadHierarchyEdges(ctx context.Context, tx *sql.Tx, typeID string, up bool, req *QueryRequest) ([]QueryResult, error) {
+	joinColumn, matchColumn := "tr.from_type_id", "tr.to_type_id"
+	if up {
+		joinColumn, matchColumn = "tr.to_type_id", "tr.from_type_id"
+	}
+
+	query := `
+		SELECT
+			t.type_id, t.file_path, t.start_line, t.end_line,
+			t.start_pos, t.end_pos,
+			t.name, t.module_path, t.kind,
+			tr.relationship_type, tr.source_file_path, tr.source_line
+		FROM type_relationships tr
+		JOIN types t ON t.type_id = ` + joinColumn + `
+		WHERE ` + matchColumn + ` = ?
+		  AND tr.relationship_type IN (?` + strings.Repeat(", ?", len(hierarchyRelationships)-1) + `)
+	`
+	args := []interface{}{typeID}
+	for _, rel := range hierarchyRelationships {
+		args = append(args, rel)
+	}
+	query = s.applyFilters(query, req, &args)
+	query += " ORDER BY t.type_id, tr.relationship_type"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var results []QueryResult
+	for rows.Next() {
+		var node Node
+		var kind, name, modulePath string
+		var declaredAt Location
+		var relationship string
+		err := rows.Scan(
+			&node.ID, &node.File, &node.StartLine, &node.EndLine,
+			&node.StartPos, &node.EndPos,
+			&name, &modulePath, &kind,
+			&relationship, &declaredAt.File, &declaredAt.Line,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		node.Kind = NodeKind(kind)
+
+		result := s.hierarchyResult(&node, 0, req)
+		result.Parent = typeID
+		result.Relationship = relationship
+		result.DeclaredAt = &declaredAt
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+	return results, nil
+}
+
+// hierarchyResult wraps a type node in a result, adding code context if requested.
+func (s *sqlSearcher) hierarchyResult(node *Node, depth int, req *QueryRequest) QueryResult {
+	result := QueryResult{Node: node, Depth: depth}
+	if req.IncludeContext {
+		contextStr, err := s.context.ExtractContext(
+			node.File,
+			LineRange{Start: node.StartLine, End: node.EndLine},
+			ByteRange{Start: node.StartPos, End: node.EndPos},
+			req.ContextLines,
+		)
+		if err == nil {
+			result.Context = contextStr
+		}
+	}
+	return result
+}
+
+// resolveTypeTarget finds the type a hierarchy query starts from, by type ID
+// or by name. Returns a nil node and a suggestion when the target is unknown
+// or the name is ambiguous.
+func (s *sqlSearcher) resolveTypeTarget(ctx context.Context, tx *sql.Tx, target string) (*Node, string, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT type_id, file_path, start_line, end_line,
+			start_pos, end_pos,
+			name, module_path, kind
+		FROM types
+		WHERE type_id = ? OR name = ?
+		ORDER BY type_id = ? DESC, type_id
+		LIMIT ?
+	`, target, target, target, maxAmbiguousSuggestions+1)
+	if err != nil {
+		return nil, "", fmt.Errorf("query error: %w", err)
+	}
+	defer rows.Close()
+
+	var nodes []*Node
+	for rows.Next() {
+		node, err := s.scanTypeRow(rows)
+		if err != nil {
+			return nil, "", err
+		}
+		nodes = append(nodes, node)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	switch {
+	case len(nodes) == 0:
+		return nil, fmt.Sprintf("No type found for %q (use a type name or type ID)", target), nil
+	case len(nodes) == 1 || nodes[0].ID == target:
+		return nodes[0], "", nil
+	}
+
+	ids := make([]string, 0, len(nodes))
+	for i, node := range nodes {
+		if i == maxAmbiguousSuggestions {
+			ids = append(ids, "...")
+			break
+		}
+		ids = append(ids, node.ID)
+	}
+	return nil, fmt.Sprintf("Type name %q is ambiguous; use a type ID: %s", target, strings.Join(ids, ", ")), nil
+}
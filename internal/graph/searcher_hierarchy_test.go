@@ -0,0 +1,186 @@
+package graph
+
+// Test Plan for Type Hierarchy Operations:
+// - supertypes walks up extends/implements/embeds/mixin edges with depth, parent and declaration site
+// - subtypes walks down, limited by depth
+// - type_hierarchy returns the target at depth 0 plus both directions
+// - Diamonds list each type once; max_results truncates
+// - Targets resolve by type ID or unique name; unknown and ambiguous names get a suggestion
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupHierarchyTestData builds a small TypeScript-style hierarchy:
+//
+//	Named   Shape
+//	    \   /   \
+//	    Base     Drawable (mixin)
+//	    /  \    /
+//	Circle  Square
+func setupHierarchyTestData(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	for i, name := range []string{"Named", "Shape", "Base", "Drawable", "Circle", "Square"} {
+		kind := "class"
+		if name == "Named" || name == "Shape" {
+			kind = "interface"
+		}
+		_, err := db.Exec(`INSERT INTO types (type_id, file_path, start_line, end_line, name, module_path, kind)
+			VALUES (?, 'web/shapes.ts', ?, ?, ?, 'web', ?)`, "web/shapes.ts::"+name, i*10+1, i*10+5, name, kind)
+		require.NoError(t, err)
+	}
+	// A same-named type elsewhere makes "Base" ambiguous
+	_, err := db.Exec(`INSERT INTO types (type_id, file_path, start_line, end_line, name, module_path, kind)
+		VALUES ('api/base.ts::Base', 'api/base.ts', 1, 3, 'Base', 'api', 'class')`)
+	require.NoError(t, err)
+
+	for _, rel := range []struct{ from, to, kind string }{
+		{"Base", "Named", "implements"},
+		{"Base", "Shape", "implements"},
+		{"Drawable", "Shape", "extends"},
+		{"Circle", "Base", "extends"},
+		{"Square", "Base", "extends"},
+		{"Square", "Drawable", "mixin"},
+	} {
+		_, err := db.Exec(`INSERT INTO type_relationships (from_type_id, to_type_id, relationship_type, source_file_path, source_line)
+			VALUES (?, ?, ?, 'web/shapes.ts', 42)`, "web/shapes.ts::"+rel.from, "web/shapes.ts::"+rel.to, rel.kind)
+		require.NoError(t, err)
+	}
+}
+
+// hierarchyEdges summarizes results as "node direction of parent (relationship, depth)".
+func hierarchyEdges(resp *QueryResponse) []string {
+	var edges []string
+	for _, r := range resp.Results {
+		if r.Parent == "" {
+			edges = append(edges, fmt.Sprintf("%s (root)", r.Node.ID))
+			continue
+		}
+		edges = append(edges, fmt.Sprintf("%s %s of %s (%s, %d)", r.Node.ID, r.Direction, r.Parent, r.Relationship, r.Depth))
+	}
+	return edges
+}
+
+func TestSQLSearcher_Supertypes(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupHierarchyTestData(t, db)
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+
+	resp, err := searcher.Query(context.Background(), &QueryRequest{
+		Operation: OperationSupertypes,
+		Target:    "Square",
+		Depth:     2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"web/shapes.ts::Base supertype of web/shapes.ts::Square (extends, 1)",
+		"web/shapes.ts::Drawable supertype of web/shapes.ts::Square (mixin, 1)",
+		"web/shapes.ts::Named supertype of web/shapes.ts::Base (implements, 2)",
+		"web/shapes.ts::Shape supertype of web/shapes.ts::Base (implements, 2)",
+	}, hierarchyEdges(resp), "Shape is listed once although both Base and Drawable reach it")
+
+	first := resp.Results[0]
+	assert.Equal(t, NodeKind("class"), first.Node.Kind)
+	assert.Equal(t, "web/shapes.ts", first.Node.File)
+	assert.Equal(t, 21, first.Node.StartLine)
+	require.NotNil(t, first.DeclaredAt)
+	assert.Equal(t, Location{File: "web/shapes.ts", Line: 42}, *first.DeclaredAt)
+	assert.Equal(t, 4, resp.TotalFound)
+}
+
+func TestSQLSearcher_Subtypes(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupHierarchyTestData(t, db)
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+
+	resp, err := searcher.Query(context.Background(), &QueryRequest{
+		Operation: OperationSubtypes,
+		Target:    "web/shapes.ts::Shape",
+		Depth:     1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"web/shapes.ts::Base subtype of web/shapes.ts::Shape (implements, 1)",
+		"web/shapes.ts::Drawable subtype of web/shapes.ts::Shape (extends, 1)",
+	}, hierarchyEdges(resp))
+
+	// Deeper walks reach Square once, through whichever parent comes first
+	resp, err = searcher.Query(context.Background(), &QueryRequest{
+		Operation:  OperationSubtypes,
+		Target:     "Shape",
+		Depth:      2,
+		MaxResults: 3,
+	})
+	require.NoError(t, err)
+	assert.Len(t, resp.Results, 3)
+	assert.True(t, resp.Truncated)
+	assert.Equal(t, 2, resp.TruncatedAt)
+}
+
+func TestSQLSearcher_TypeHierarchy(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupHierarchyTestData(t, db)
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+
+	resp, err := searcher.Query(context.Background(), &QueryRequest{
+		Operation: OperationTypeHierarchy,
+		Target:    "web/shapes.ts::Base",
+		Depth:     1,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Results)
+
+	root := resp.Results[0]
+	assert.Equal(t, "web/shapes.ts::Base", root.Node.ID)
+	assert.Equal(t, 0, root.Depth)
+	assert.Empty(t, root.Parent)
+
+	assert.Equal(t, []string{
+		"web/shapes.ts::Base (root)",
+		"web/shapes.ts::Named supertype of web/shapes.ts::Base (implements, 1)",
+		"web/shapes.ts::Shape supertype of web/shapes.ts::Base (implements, 1)",
+		"web/shapes.ts::Circle subtype of web/shapes.ts::Base (extends, 1)",
+		"web/shapes.ts::Square subtype of web/shapes.ts::Base (extends, 1)",
+	}, hierarchyEdges(resp))
+}
+
+func TestSQLSearcher_HierarchyTargetResolution(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupHierarchyTestData(t, db)
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+
+	resp, err := searcher.Query(context.Background(), &QueryRequest{Operation: OperationSupertypes, Target: "Base"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, "ambiguous")
+	assert.Contains(t, resp.Suggestion, "api/base.ts::Base")
+	assert.Contains(t, resp.Suggestion, "web/shapes.ts::Base")
+
+	resp, err = searcher.Query(context.Background(), &QueryRequest{Operation: OperationSubtypes, Target: "Missing"})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, "No type found")
+}
@@ -0,0 +1,30 @@
+package graph
+
+import "database/sql"
+
+// dependencyVersionSQL selects the version of an external import from the
+// dependency inventory, preferring the importing module's direct dependency.
+const dependencyVersionSQL = `(SELECT d.version FROM dependencies d
+	WHERE r.resolution = 'external' AND d.import_name = r.package_name AND d.module_name IS fm.module_name
+	ORDER BY d.direct DESC LIMIT 1)`
+
+// implementationSQL selects the source file implementing the C/C++ header
+// an import resolves to.
+const implementationSQL = `(SELECT h.source_path FROM header_implementations h WHERE h.header_path = r.resolved_path)`
+
+// scanImportResolution builds a result's import info from the resolution
+// columns; nil when the import has no resolution.
+func scanImportResolution(importPath string, resolution, resolvedPath, module, pkg, version, implementation sql.NullString) *ImportInfo {
+	if !resolution.Valid {
+		return nil
+	}
+	return &ImportInfo{
+		Path:           importPath,
+		Resolution:     resolution.String,
+		ResolvedPath:   resolvedPath.String,
+		Module:         module.String,
+		Package:        pkg.String,
+		Version:        version.String,
+		Implementation: implementation.String,
+	}
+}
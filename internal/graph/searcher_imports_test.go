@@ -0,0 +1,153 @@
+package graph
+
+// Test Plan for resolved dependencies:
+// - Without import resolutions, dependency results carry no import info
+// - Resolved dependencies report where each import points
+// - Dependencies of a workspace module cover all its files
+// - Dependents match the import path, the resolved file or package directory,
+//   and external package names
+// - Dependents of a workspace module exclude imports from inside the module
+// - With a dependency inventory, external imports carry the importing module's
+//   version and dependents match dependency names
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupResolvedImportData indexes a web app importing a UI package and react,
+// and a UI file importing a sibling.
+func setupResolvedImportData(t *testing.T, db *sql.DB, resolved bool) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO files (file_path, module_path, language) VALUES
+			('web/src/app.ts', 'web/src', 'typescript'),
+			('ui/src/index.ts', 'ui/src', 'typescript'),
+			('ui/src/button.ts', 'ui/src', 'typescript');
+		INSERT INTO imports (import_id, file_path, import_path, import_line) VALUES
+			('web/src/app.ts::@acme/ui', 'web/src/app.ts', '@acme/ui', 1),
+			('web/src/app.ts::react/jsx-runtime', 'web/src/app.ts', 'react/jsx-runtime', 2),
+			('ui/src/index.ts::./button', 'ui/src/index.ts', './button', 1);
+	`)
+	require.NoError(t, err)
+
+	if !resolved {
+		return
+	}
+	_, err = db.Exec(`
+		INSERT INTO file_modules VALUES
+			('web/src/app.ts', 'web'),
+			('ui/src/index.ts', '@acme/ui'),
+			('ui/src/button.ts', '@acme/ui');
+		INSERT INTO import_resolutions VALUES
+			('web/src/app.ts::@acme/ui', 'internal', 'ui/src/index.ts', '@acme/ui', NULL),
+			('web/src/app.ts::react/jsx-runtime', 'external', NULL, NULL, 'react'),
+			('ui/src/index.ts::./button', 'internal', 'ui/src/button.ts', '@acme/ui', NULL);
+	`)
+	require.NoError(t, err)
+}
+
+// dependencyResults renders results as "node file" and their import info.
+func dependencyResults(t *testing.T, searcher Searcher, op QueryOperation, target string) ([]string, []*ImportInfo) {
+	t.Helper()
+	resp, err := searcher.Query(context.Background(), &QueryRequest{
+		Operation: op, Target: target, MaxResults: DefaultMaxResults,
+	})
+	require.NoError(t, err)
+
+	sort.Slice(resp.Results, func(i, j int) bool {
+		return resp.Results[i].Node.ID+resp.Results[i].Node.File < resp.Results[j].Node.ID+resp.Results[j].Node.File
+	})
+	var nodes []string
+	var imports []*ImportInfo
+	for _, r := range resp.Results {
+		nodes = append(nodes, r.Node.ID+" "+r.Node.File)
+		imports = append(imports, r.Import)
+	}
+	return nodes, imports
+}
+
+func TestSQLSearcher_Dependencies_Unresolved(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupResolvedImportData(t, db, false)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	nodes, imports := dependencyResults(t, searcher, OperationDependencies, "web/src")
+	assert.Equal(t, []string{"@acme/ui web/src/app.ts", "react/jsx-runtime web/src/app.ts"}, nodes)
+	assert.Equal(t, []*ImportInfo{nil, nil}, imports)
+}
+
+func TestSQLSearcher_Dependencies_Resolved(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupResolvedImportData(t, db, true)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	nodes, imports := dependencyResults(t, searcher, OperationDependencies, "web/src/app.ts")
+	assert.Equal(t, []string{"@acme/ui web/src/app.ts", "react/jsx-runtime web/src/app.ts"}, nodes)
+	assert.Equal(t, []*ImportInfo{
+		{Path: "@acme/ui", Resolution: "internal", ResolvedPath: "ui/src/index.ts", Module: "@acme/ui"},
+		{Path: "react/jsx-runtime", Resolution: "external", Package: "react"},
+	}, imports)
+
+	// A workspace module's dependencies cover all its files
+	nodes, _ = dependencyResults(t, searcher, OperationDependencies, "@acme/ui")
+	assert.Equal(t, []string{"./button ui/src/index.ts"}, nodes)
+}
+
+func TestSQLSearcher_Dependents_Resolved(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupResolvedImportData(t, db, true)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	// By import path, resolved file and package name
+	nodes, imports := dependencyResults(t, searcher, OperationDependents, "@acme/ui")
+	assert.Equal(t, []string{"web/src web/src/app.ts"}, nodes, "module dependents exclude the module's own files")
+	assert.Equal(t, "ui/src/index.ts", imports[0].ResolvedPath)
+
+	nodes, _ = dependencyResults(t, searcher, OperationDependents, "ui/src/button.ts")
+	assert.Equal(t, []string{"ui/src ui/src/index.ts"}, nodes)
+
+	nodes, _ = dependencyResults(t, searcher, OperationDependents, "react")
+	assert.Equal(t, []string{"web/src web/src/app.ts"}, nodes)
+}
+
+func TestSQLSearcher_Dependents_DependencyInventory(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+	setupResolvedImportData(t, db, true)
+	_, err := db.Exec(`
+		INSERT INTO dependencies VALUES
+			('npm', 'react', 'react', '18.2.0', '^18', 'runtime', 1, 'web/package.json', 'web'),
+			('npm', 'react', 'react', '17.0.2', '^17', 'runtime', 1, 'ui/package.json', '@acme/ui');
+	`)
+	require.NoError(t, err)
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+
+	nodes, imports := dependencyResults(t, searcher, OperationDependents, "react")
+	assert.Equal(t, []string{"web/src web/src/app.ts"}, nodes)
+	assert.Equal(t, &ImportInfo{Path: "react/jsx-runtime", Resolution: "external", Package: "react", Version: "18.2.0"}, imports[0])
+
+	_, imports = dependencyResults(t, searcher, OperationDependencies, "web/src/app.ts")
+	assert.Empty(t, imports[0].Version, "internal imports have no version")
+	assert.Equal(t, "18.2.0", imports[1].Version)
+}
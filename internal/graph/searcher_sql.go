@@ -39,6 +39,11 @@ func (s *sqlSearcher) Query(ctx context.Context, req *QueryRequest) (*QueryRespo
 	}
 	defer tx.Rollback() // Safe to call even after commit
 
+	generation, err := committedGeneration(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Sanitize and enforce depth limits
 	const DefaultDepth = 3
 	const MaxDepth = 6
@@ -70,6 +75,18 @@ func (s *sqlSearcher) Query(ctx context.Context, req *QueryRequest) (*QueryRespo
 		resp, err = s.queryPath(ctx, tx, req)
 	case OperationImpact:
 		resp, err = s.queryImpact(ctx, tx, req)
+	case OperationSupertypes:
+		resp, err = s.querySupertypes(ctx, tx, req)
+	case OperationSubtypes:
+		resp, err = s.querySubtypes(ctx, tx, req)
+	case OperationTypeHierarchy:
+		resp, err = s.queryTypeHierarchy(ctx, tx, req)
+	case OperationEndpoint:
+		resp, err = s.queryEndpoint(ctx, tx, req)
+	case OperationConfigUsages:
+		resp, err = s.queryConfigUsages(ctx, tx, req)
+	case OperationTableUsages:
+		resp, err = s.queryTableUsages(ctx, tx, req)
 	default:
 		return nil, fmt.Errorf("unsupported operation: %s", req.Operation)
 	}
@@ -80,9 +97,32 @@ func (s *sqlSearcher) Query(ctx context.Context, req *QueryRequest) (*QueryRespo
 
 	resp.Metadata.TookMs = int(time.Since(start).Milliseconds())
 	resp.Metadata.Source = "graph"
+	resp.Metadata.Generation = generation
 	return resp, nil
 }
 
+// committedGeneration returns the index generation tx reads, as
+// storage.CommittedGeneration does (graph can't import storage): 0 for
+// databases without one.
+func committedGeneration(ctx context.Context, tx *sql.Tx) (int64, error) {
+	var exists bool
+	err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'cache_metadata')").
+		Scan(&exists)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	var generation int64
+	err = tx.QueryRowContext(ctx,
+		"SELECT COALESCE((SELECT CAST(value AS INTEGER) FROM cache_metadata WHERE key = 'index_generation'), 0)").
+		Scan(&generation)
+	if err != nil {
+		return 0, fmt.Errorf("read index generation: %w", err)
+	}
+	return generation, nil
+}
+
 // Reload reloads the graph from storage.
 // No-op for SQL searcher since database is always current.
 func (s *sqlSearcher) Reload(ctx context.Context) error {
@@ -100,13 +140,21 @@ func (s *sqlSearcher) Close() error {
 // queryCallers finds all functions that call the target.
 func (s *sqlSearcher) queryCallers(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
 	sql, args := s.buildCallersSQL(req.Target, req.Depth, req.MaxResults, req)
-	return s.executeFunctionQuery(ctx, tx, sql, args, req)
+	resp, err := s.executeFunctionQuery(ctx, tx, sql, args, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, s.annotateCallConfidence(ctx, tx, resp, req.Target, true)
 }
 
 // queryCallees finds all functions called by the target.
 func (s *sqlSearcher) queryCallees(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
 	sql, args := s.buildCalleesSQL(req.Target, req.Depth, req.MaxResults, req)
-	return s.executeFunctionQuery(ctx, tx, sql, args, req)
+	resp, err := s.executeFunctionQuery(ctx, tx, sql, args, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, s.annotateCallConfidence(ctx, tx, resp, req.Target, false)
 }
 
 // queryDependencies finds all packages imported by the target package.
@@ -484,32 +532,50 @@ func (s *sqlSearcher) buildCalleesSQL(target string, depth int, limit int, req *
 	return sql, args
 }
 
-// buildDependenciesSQL constructs the SQL query for finding package dependencies.
-// Direct lookup (depth 1 only) - returns all imports from the target package.
+// buildDependenciesSQL constructs the SQL query for finding package
+// dependencies. The target may be a package (module path), a file or a
+// workspace module name. Each import carries its resolution against the
+// workspace, if resolved, with its version from the dependency inventory and
+// the implementation of included C/C++ headers.
 func (s *sqlSearcher) buildDependenciesSQL(target string, limit int) (string, []interface{}) {
 	query := `
-		SELECT DISTINCT i.import_path, i.file_path, i.import_line
+		SELECT DISTINCT i.import_path, i.file_path, i.import_line, i.import_path,
+			r.resolution, r.resolved_path, r.module_name, r.package_name, ` + dependencyVersionSQL + `, ` + implementationSQL + `
 		FROM imports i
 		JOIN files f ON i.file_path = f.file_path
-		WHERE f.module_path = ? OR i.file_path = ?
+		LEFT JOIN import_resolutions r ON r.import_id = i.import_id
+		LEFT JOIN file_modules fm ON fm.file_path = i.file_path
+		WHERE f.module_path = ? OR i.file_path = ? OR fm.module_name = ?
 		ORDER BY i.import_path
 		LIMIT ?
 	`
-	return query, []interface{}{target, target, limit}
+	return query, []interface{}{target, target, target, limit}
 }
 
-// buildDependentsSQL constructs the SQL query for finding package dependents.
-// Reverse lookup (depth 1 only) - returns all packages that import the target.
+// buildDependentsSQL constructs the SQL query for finding package dependents
+// (depth 1 only). The target may be an import path, the file or Go package
+// directory an import resolves to, an external package or dependency name,
+// or a workspace module name (matching imports from other modules only). A
+// C/C++ source file is also depended on by the files including the headers
+// it implements.
 func (s *sqlSearcher) buildDependentsSQL(target string, limit int) (string, []interface{}) {
 	query := `
-		SELECT DISTINCT f.module_path, i.file_path, i.import_line
+		SELECT DISTINCT f.module_path, i.file_path, i.import_line, i.import_path,
+			r.resolution, r.resolved_path, r.module_name, r.package_name, ` + dependencyVersionSQL + `, ` + implementationSQL + `
 		FROM imports i
 		JOIN files f ON i.file_path = f.file_path
+		LEFT JOIN import_resolutions r ON r.import_id = i.import_id
+		LEFT JOIN file_modules fm ON fm.file_path = i.file_path
 		WHERE i.import_path = ?
+		   OR r.resolved_path = ?
+		   OR r.package_name = ?
+		   OR (r.module_name = ? AND COALESCE(fm.module_name, '') <> r.module_name)
+		   OR r.package_name IN (SELECT import_name FROM dependencies WHERE name = ?)
+		   OR r.resolved_path IN (SELECT header_path FROM header_implementations WHERE source_path = ?)
 		ORDER BY f.module_path
 		LIMIT ?
 	`
-	return query, []interface{}{target, limit}
+	return query, []interface{}{target, target, target, target, target, target, limit}
 }
 
 // buildImplementationsSQL constructs the SQL query for finding type implementations.
@@ -646,14 +712,11 @@ func (s *sqlSearcher) scanFunctionRow(rows *sql.Rows) (*Node, int, error) {
 		return nil, 0, fmt.Errorf("scan row: %w", err)
 	}
 
+	node.Kind = NodeFunction
 	if isMethod {
 		node.Kind = NodeMethod
-		if receiverName.Valid {
-			node.ID = receiverName.String + "." + name
-		}
-	} else {
-		node.Kind = NodeFunction
 	}
+	node.ID = functionNodeID(node.ID, name, isMethod, receiverName)
 
 	return &node, depth, nil
 }
@@ -707,7 +770,7 @@ func (s *sqlSearcher) executeFunctionQuery(ctx context.Context, tx *sql.Tx, sql
 }
 
 // applyFilters adds file path filtering to SQL queries.
-// Appends WHERE clauses for Scope and ExcludePatterns to the given SQL string.
+// Appends WHERE clauses for Scope, ExcludePatterns and Label to the given SQL string.
 // Modifies the args slice in place to include filter parameters.
 func (s *sqlSearcher) applyFilters(sql string, req *QueryRequest, args *[]interface{}) string {
 	if req.Scope != "" {
@@ -720,11 +783,25 @@ func (s *sqlSearcher) applyFilters(sql string, req *QueryRequest, args *[]interf
 		*args = append(*args, pattern)
 	}
 
+	// Content source files are stored as "@<label>/<path>" (see storage.LabelFilter)
+	if req.Label == projectLabel {
+		sql += " AND substr(file_path, 1, 1) <> '@'"
+	} else if req.Label != "" {
+		prefix := "@" + req.Label + "/"
+		sql += " AND substr(file_path, 1, ?) = ?"
+		*args = append(*args, len(prefix), prefix)
+	}
+
 	return sql
 }
 
+// projectLabel selects the project's own files in label filters
+// (storage.ProjectLabel).
+const projectLabel = "project"
+
 // executeDependencyQuery executes SQL for dependency/dependent queries.
-// Returns package nodes with import information.
+// Returns package nodes with import information; queries built for resolved
+// imports also return where each import points.
 func (s *sqlSearcher) executeDependencyQuery(ctx context.Context, tx *sql.Tx, query string, args []interface{}, req *QueryRequest) (*QueryResponse, error) {
 	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -734,11 +811,12 @@ func (s *sqlSearcher) executeDependencyQuery(ctx context.Context, tx *sql.Tx, qu
 
 	results := []QueryResult{}
 	for rows.Next() {
-		var importPath, filePath string
+		var importPath, filePath, written string
 		var importLine int
+		var resolution, resolvedPath, module, pkg, version, implementation sql.NullString
 
-		err := rows.Scan(&importPath, &filePath, &importLine)
-		if err != nil {
+		if err := rows.Scan(&importPath, &filePath, &importLine,
+			&written, &resolution, &resolvedPath, &module, &pkg, &version, &implementation); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
 
@@ -751,8 +829,11 @@ func (s *sqlSearcher) executeDependencyQuery(ctx context.Context, tx *sql.Tx, qu
 			Kind:      NodePackage,
 		}
 
-		result := QueryResult{Node: node, Depth: 1}
-		results = append(results, result)
+		results = append(results, QueryResult{
+			Node:   node,
+			Depth:  1,
+			Import: scanImportResolution(written, resolution, resolvedPath, module, pkg, version, implementation),
+		})
 	}
 
 	if err := rows.Err(); err != nil {
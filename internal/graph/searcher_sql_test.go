@@ -211,6 +211,9 @@ func TestSQLSearcher_OperationRouting(t *testing.T) {
 		{OperationImplementations, "TestInterface", ""},
 		{OperationPath, "funcA", "funcB"},
 		{OperationImpact, "criticalFunc", ""},
+		{OperationSupertypes, "TestType", ""},
+		{OperationSubtypes, "TestInterface", ""},
+		{OperationTypeHierarchy, "TestType", ""},
 	}
 
 	for _, tc := range testCases {
@@ -376,12 +379,14 @@ func setupTestDB(t *testing.T) *sql.DB {
 		);
 
 		CREATE TABLE IF NOT EXISTS function_calls (
+			call_id TEXT,
 			caller_function_id TEXT NOT NULL,
 			callee_function_id TEXT,
 			callee_name TEXT NOT NULL
 		);
 
 		CREATE TABLE IF NOT EXISTS imports (
+			import_id TEXT,
 			file_path TEXT NOT NULL,
 			import_path TEXT NOT NULL,
 			import_line INTEGER NOT NULL,
@@ -411,9 +416,71 @@ func setupTestDB(t *testing.T) *sql.DB {
 			from_type_id TEXT NOT NULL,
 			to_type_id TEXT NOT NULL,
 			relationship_type TEXT NOT NULL,
+			source_file_path TEXT NOT NULL DEFAULT '',
+			source_line INTEGER NOT NULL DEFAULT 0,
 			PRIMARY KEY (from_type_id, to_type_id, relationship_type)
 		);
 
+		CREATE TABLE IF NOT EXISTS call_resolutions (
+			call_id TEXT PRIMARY KEY,
+			callee_function_id TEXT,
+			confidence TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS file_modules (
+			file_path TEXT PRIMARY KEY,
+			module_name TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS import_resolutions (
+			import_id TEXT PRIMARY KEY,
+			resolution TEXT NOT NULL,
+			resolved_path TEXT,
+			module_name TEXT,
+			package_name TEXT
+		);
+
+		CREATE TABLE IF NOT EXISTS header_implementations (
+			header_path TEXT PRIMARY KEY,
+			source_path TEXT NOT NULL
+		);
+
+		CREATE TABLE IF NOT EXISTS dependencies (
+			ecosystem TEXT NOT NULL,
+			name TEXT NOT NULL,
+			import_name TEXT NOT NULL,
+			version TEXT NOT NULL DEFAULT '',
+			version_constraint TEXT,
+			scope TEXT NOT NULL,
+			direct INTEGER NOT NULL,
+			manifest_path TEXT NOT NULL,
+			module_name TEXT
+		);
+
+		CREATE TABLE IF NOT EXISTS endpoints (
+			endpoint_id INTEGER PRIMARY KEY, file_path TEXT NOT NULL, line INTEGER NOT NULL,
+			framework TEXT NOT NULL, kind TEXT NOT NULL, method TEXT NOT NULL, path TEXT NOT NULL,
+			handler TEXT NOT NULL DEFAULT '', handler_name TEXT NOT NULL DEFAULT '',
+			handler_scope TEXT NOT NULL DEFAULT '', handler_function_id TEXT
+		);
+
+		CREATE TABLE IF NOT EXISTS config_usages (
+			usage_id INTEGER PRIMARY KEY, file_path TEXT NOT NULL, line INTEGER NOT NULL,
+			kind TEXT NOT NULL, key TEXT NOT NULL, source TEXT NOT NULL,
+			function TEXT NOT NULL DEFAULT '', function_id TEXT
+		);
+
+		CREATE TABLE IF NOT EXISTS table_usages (
+			usage_id INTEGER PRIMARY KEY, file_path TEXT NOT NULL, line INTEGER NOT NULL,
+			table_name TEXT NOT NULL, access TEXT NOT NULL, operation TEXT NOT NULL,
+			columns TEXT NOT NULL DEFAULT '', source TEXT NOT NULL,
+			function TEXT NOT NULL DEFAULT '', function_id TEXT
+		);
+
+		-- Stand-ins for the views the storage package creates
+		CREATE TABLE IF NOT EXISTS config_key_definitions (key TEXT, kind TEXT, file_path TEXT, key_path TEXT, value TEXT, line INTEGER);
+		CREATE TABLE IF NOT EXISTS unused_config_keys (file_path TEXT, line INTEGER, key TEXT, value TEXT);
+
 		CREATE INDEX idx_function_calls_caller ON function_calls(caller_function_id);
 		CREATE INDEX idx_function_calls_callee ON function_calls(callee_function_id);
 		CREATE INDEX idx_function_calls_callee_name ON function_calls(callee_name);
@@ -811,15 +878,17 @@ func TestBuildDependenciesSQL(t *testing.T) {
 	assert.Contains(t, sql, "SELECT DISTINCT i.import_path, i.file_path, i.import_line")
 	assert.Contains(t, sql, "FROM imports i")
 	assert.Contains(t, sql, "JOIN files f ON i.file_path = f.file_path")
-	assert.Contains(t, sql, "WHERE f.module_path = ? OR i.file_path = ?")
+	assert.Contains(t, sql, "LEFT JOIN import_resolutions r ON r.import_id = i.import_id")
+	assert.Contains(t, sql, "WHERE f.module_path = ? OR i.file_path = ? OR fm.module_name = ?")
 	assert.Contains(t, sql, "ORDER BY i.import_path")
 	assert.Contains(t, sql, "LIMIT ?")
 
 	// Verify args
-	require.Len(t, args, 3)
+	require.Len(t, args, 4)
 	assert.Equal(t, "internal/graph", args[0])
 	assert.Equal(t, "internal/graph", args[1])
-	assert.Equal(t, 100, args[2])
+	assert.Equal(t, "internal/graph", args[2])
+	assert.Equal(t, 100, args[3])
 }
 
 // TestBuildDependentsSQL verifies the dependents query structure.
@@ -840,14 +909,17 @@ func TestBuildDependentsSQL(t *testing.T) {
 	assert.Contains(t, sql, "SELECT DISTINCT f.module_path, i.file_path, i.import_line")
 	assert.Contains(t, sql, "FROM imports i")
 	assert.Contains(t, sql, "JOIN files f ON i.file_path = f.file_path")
+	assert.Contains(t, sql, "LEFT JOIN import_resolutions r ON r.import_id = i.import_id")
 	assert.Contains(t, sql, "WHERE i.import_path = ?")
 	assert.Contains(t, sql, "ORDER BY f.module_path")
 	assert.Contains(t, sql, "LIMIT ?")
 
 	// Verify args
-	require.Len(t, args, 2)
-	assert.Equal(t, "github.com/example/pkg", args[0])
-	assert.Equal(t, 100, args[1])
+	require.Len(t, args, 7)
+	for _, arg := range args[:6] {
+		assert.Equal(t, "github.com/example/pkg", arg)
+	}
+	assert.Equal(t, 100, args[6])
 }
 
 // TestBuildImplementationsSQL verifies the implementations query structure.
@@ -1995,6 +2067,54 @@ func TestQueryCallers_WithScopeFilter(t *testing.T) {
 	assert.Equal(t, "internalFunc", resp.Results[0].Node.ID)
 }
 
+// TestQueryCallers_WithLabelFilter tests callers query with content source label filtering.
+func TestQueryCallers_WithLabelFilter(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	insertTestFunction(t, db, &Node{
+		ID: "projectFunc", File: "internal/test.go", StartLine: 1, EndLine: 5,
+		StartPos: 0, EndPos: 50, Kind: NodeFunction,
+	})
+	insertTestFunction(t, db, &Node{
+		ID: "libFunc", File: "@lib/client/client.go", StartLine: 1, EndLine: 5,
+		StartPos: 0, EndPos: 50, Kind: NodeFunction,
+	})
+	insertTestFunction(t, db, &Node{
+		ID: "target", File: "main.go", StartLine: 1, EndLine: 5,
+		StartPos: 0, EndPos: 50, Kind: NodeFunction,
+	})
+
+	insertTestCall(t, db, "projectFunc", "target", false)
+	insertTestCall(t, db, "libFunc", "target", false)
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+	defer searcher.Close()
+
+	callers := func(label string) []string {
+		resp, err := searcher.Query(context.Background(), &QueryRequest{
+			Operation:  OperationCallers,
+			Target:     "target",
+			Depth:      1,
+			MaxResults: 100,
+			Label:      label,
+		})
+		require.NoError(t, err)
+		var ids []string
+		for _, r := range resp.Results {
+			ids = append(ids, r.Node.ID)
+		}
+		return ids
+	}
+
+	assert.ElementsMatch(t, []string{"projectFunc", "libFunc"}, callers(""))
+	assert.Equal(t, []string{"projectFunc"}, callers("project"))
+	assert.Equal(t, []string{"libFunc"}, callers("lib"))
+	assert.Empty(t, callers("wiki"))
+}
+
 // TestQueryCallees_WithExcludeFilter tests callees query with exclude filtering.
 func TestQueryCallees_WithExcludeFilter(t *testing.T) {
 	t.Parallel()
@@ -2207,3 +2327,28 @@ func funcC() {
 		})
 	}
 }
+
+func TestSQLSearcher_ReportsGeneration(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	searcher, err := NewSQLSearcher(db, "/test/root")
+	require.NoError(t, err)
+	req := &QueryRequest{Operation: OperationCallers, Target: "funcA", MaxResults: 10}
+
+	// Databases without metadata report generation 0
+	resp, err := searcher.Query(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), resp.Metadata.Generation)
+
+	_, err = db.Exec(`
+		CREATE TABLE cache_metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL, updated_at TEXT NOT NULL);
+		INSERT INTO cache_metadata VALUES ('index_generation', '7', '');
+	`)
+	require.NoError(t, err)
+
+	resp, err = searcher.Query(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), resp.Metadata.Generation)
+}
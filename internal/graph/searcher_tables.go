@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// queryTableUsages finds the statements touching the database tables
+// matching the target, each returned as the function running the statement
+// with the table, access and columns in Table.
+//
+// The target is a table (matched case-insensitively, with or without its
+// schema), a prefix ending in "*" ("config_*"), or "*" for every table,
+// optionally followed by ":read", ":write" or ":schema" to keep one access
+// ("chunks:write"). Statements outside indexed functions (constants,
+// top-level code, other languages' methods) are returned as query nodes at
+// the statement.
+func (s *sqlSearcher) queryTableUsages(ctx context.Context, tx *sql.Tx, req *QueryRequest) (*QueryResponse, error) {
+	resp := &QueryResponse{
+		Operation: string(req.Operation),
+		Target:    req.Target,
+		Results:   []QueryResult{},
+	}
+
+	table, access := splitTableTarget(req.Target)
+	where, args := nameFilter("table_name", table)
+	if table != "" && !strings.HasSuffix(table, "*") && !strings.Contains(table, ".") {
+		// users matches public.users
+		where = "(" + where + " OR substr(table_name, -length(?) - 1) = ('.' || ?) COLLATE NOCASE)"
+		args = append(args, table, table)
+	}
+	query := `
+		SELECT table_name, access, operation, columns, source, file_path, line, function, function_id
+		FROM table_usages
+		WHERE ` + where
+	if access != "" {
+		query += " AND access = ?"
+		args = append(args, access)
+	}
+	query = s.applyFilters(query, req, &args)
+	query += " ORDER BY table_name COLLATE NOCASE, file_path, line"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query error: %w", err)
+	}
+	type usageRow struct {
+		info       TableUsageInfo
+		function   string
+		functionID sql.NullString
+	}
+	var usages []usageRow
+	for rows.Next() {
+		var u usageRow
+		var columns string
+		err := rows.Scan(&u.info.Table, &u.info.Access, &u.info.Operation, &columns, &u.info.Source,
+			&u.info.File, &u.info.Line, &u.function, &u.functionID)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		if columns != "" {
+			u.info.Columns = strings.Split(columns, ",")
+		}
+		usages = append(usages, u)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	if len(usages) == 0 {
+		resp.Suggestion = fmt.Sprintf(`No usage of %q (use a table such as chunks, a prefix such as "config_*", or "*", optionally followed by ":read", ":write" or ":schema")`, req.Target)
+		return resp, nil
+	}
+
+	maxResults := req.MaxResults
+	if maxResults <= 0 {
+		maxResults = DefaultMaxResults
+	}
+	for _, u := range usages {
+		if len(resp.Results) >= maxResults {
+			resp.Truncated = true
+			break
+		}
+		info := u.info
+
+		var node *Node
+		if u.functionID.Valid {
+			if node, err = s.functionNode(ctx, tx, u.functionID.String); err != nil {
+				return nil, err
+			}
+		}
+		if node == nil {
+			id := info.File
+			if u.function != "" {
+				id = info.File + "::" + u.function
+			}
+			node = &Node{ID: id, Kind: NodeQuery, File: info.File, StartLine: info.Line, EndLine: info.Line}
+		}
+		result := s.hierarchyResult(node, 0, req)
+		result.Table = &info
+		resp.Results = append(resp.Results, result)
+	}
+
+	resp.TotalFound = len(usages)
+	resp.TotalReturned = len(resp.Results)
+	return resp, nil
+}
+
+// splitTableTarget splits an access suffix off a table_usages target:
+// "chunks:write" is chunks written.
+func splitTableTarget(target string) (table, access string) {
+	target = strings.TrimSpace(target)
+	if i := strings.LastIndex(target, ":"); i >= 0 {
+		switch suffix := strings.ToLower(target[i+1:]); suffix {
+		case TableAccessRead, TableAccessWrite, TableAccessSchema:
+			return target[:i], suffix
+		}
+	}
+	return target, ""
+}
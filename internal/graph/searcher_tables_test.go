@@ -0,0 +1,82 @@
+package graph
+
+// Test Plan for the table_usages operation:
+// - Without usages, any table returns the no-usage suggestion
+// - A table (any case, with or without its schema) returns the statements touching it:
+//   the Go function running them, or a query node outside indexed functions
+// - ":write", ":read" and ":schema" keep one access; prefixes and "*" select several tables
+// - Scope filters by file; unknown tables return a suggestion
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLSearcher_TableUsages(t *testing.T) {
+	t.Parallel()
+	db := setupTestDB(t)
+	defer db.Close()
+
+	searcher, err := NewSQLSearcher(db, t.TempDir())
+	require.NoError(t, err)
+	query := func(target, scope string) *QueryResponse {
+		t.Helper()
+		resp, err := searcher.Query(context.Background(), &QueryRequest{
+			Operation: OperationTableUsages, Target: target, Scope: scope, MaxResults: DefaultMaxResults,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+	summarize := func(resp *QueryResponse) []string {
+		var out []string
+		for _, r := range resp.Results {
+			out = append(out, fmt.Sprintf("%s %s %s %s [%s] @%d", r.Node.Kind, r.Node.ID, r.Table.Access, r.Table.Operation,
+				strings.Join(r.Table.Columns, " "), r.Table.Line))
+		}
+		return out
+	}
+
+	resp := query("chunks", "")
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, `No usage of "chunks"`)
+
+	insertTestFunction(t, db, &Node{ID: "storage/chunks.go::WriteChunks", Kind: NodeFunction, File: "storage/chunks.go", StartLine: 10, EndLine: 30})
+	_, err = db.Exec(`
+		INSERT INTO table_usages (file_path, line, table_name, access, operation, columns, source, function, function_id) VALUES
+			('storage/chunks.go', 4, 'chunks', 'schema', 'create', 'chunk_id,file_path', 'sql', '', NULL),
+			('storage/chunks.go', 12, 'chunks', 'write', 'insert', 'chunk_id', 'squirrel', 'WriteChunks', 'storage/chunks.go::WriteChunks'),
+			('jobs/cleanup.py', 3, 'public.Chunks', 'write', 'delete', '', 'sql', 'cleanup', NULL),
+			('web/search.ts', 8, 'chunks', 'read', 'select', 'text', 'knex', 'Search.run', NULL),
+			('storage/config.go', 2, 'config_keys', 'read', 'select', '', 'sql', 'Load', NULL);
+	`)
+	require.NoError(t, err)
+
+	resp = query("CHUNKS:write", "")
+	assert.Equal(t, []string{
+		"function storage/chunks.go::WriteChunks write insert [chunk_id] @12",
+		"query jobs/cleanup.py::cleanup write delete [] @3",
+	}, summarize(resp))
+	assert.Equal(t, "squirrel", resp.Results[0].Table.Source)
+	assert.Equal(t, "storage/chunks.go", resp.Results[0].Table.File)
+
+	assert.Equal(t, []string{
+		"query storage/chunks.go schema create [chunk_id file_path] @4",
+	}, summarize(query("chunks:schema", "")))
+	assert.Equal(t, []string{
+		"query web/search.ts::Search.run read select [text] @8",
+		"query storage/config.go::Load read select [] @2",
+	}, summarize(query("*:read", "")))
+	assert.Len(t, query("c*", "").Results, 4)
+	assert.Len(t, query("*", "").Results, 5)
+	assert.Len(t, query("public.chunks", "").Results, 1)
+	assert.Len(t, query("chunks", "storage/%").Results, 2)
+
+	resp = query("orders", "")
+	assert.Empty(t, resp.Results)
+	assert.Contains(t, resp.Suggestion, `No usage of "orders"`)
+}
@@ -14,6 +14,12 @@ const (
 	OperationTypeUsages      QueryOperation = "type_usages"
 	OperationPath            QueryOperation = "path"
 	OperationImpact          QueryOperation = "impact"
+	OperationSupertypes      QueryOperation = "supertypes"
+	OperationSubtypes        QueryOperation = "subtypes"
+	OperationTypeHierarchy   QueryOperation = "type_hierarchy"
+	OperationEndpoint        QueryOperation = "endpoint"
+	OperationConfigUsages    QueryOperation = "config_usages"
+	OperationTableUsages     QueryOperation = "table_usages"
 )
 
 // Query defaults and limits
@@ -38,6 +44,7 @@ type QueryRequest struct {
 	MaxPerLevel     int            // Maximum results per depth level (default: 50)
 	Scope           string         // SQL LIKE pattern to filter results by file path (e.g., "internal/%", "%_test.go") (not supported for path operation)
 	ExcludePatterns []string       // SQL LIKE patterns to exclude from results (e.g., "%_test.go", "vendor/%") (not supported for path operation)
+	Label           string         // Content source label, or "project" for the project's own files (not supported for path operation)
 }
 
 // QueryResponse represents the response to a graph query.
@@ -61,6 +68,79 @@ type QueryResult struct {
 	Depth      int    `json:"depth,omitempty"`       // Depth in traversal (for recursive queries)
 	ImpactType string `json:"impact_type,omitempty"` // For impact operation: "implementation", "direct_caller", "transitive"
 	Severity   string `json:"severity,omitempty"`    // For impact operation: "must_update", "review_needed"
+	Confidence string `json:"confidence,omitempty"`  // For direct callers/callees: "exact", "dynamic", "unresolved", or "syntactic"
+
+	// Dependency operations: where the import points, once resolved against the workspace
+	Import *ImportInfo `json:"import,omitempty"`
+
+	// Endpoint operation: the route a handler (depth 0) serves
+	Endpoint *EndpointInfo `json:"endpoint,omitempty"`
+
+	// Config usages operation: the key read and where it is defined
+	Config *ConfigUsageInfo `json:"config,omitempty"`
+
+	// Table usages operation: the table the statement reads, writes or defines
+	Table *TableUsageInfo `json:"table,omitempty"`
+
+	// Type hierarchy operations: each result hangs from Parent, forming a tree
+	// (endpoint operation: the "METHOD /path" a callee is reached from)
+	Parent       string    `json:"parent,omitempty"`       // Type ID this result is a supertype/subtype of
+	Relationship string    `json:"relationship,omitempty"` // "extends", "implements", "embeds", or "mixin"
+	Direction    string    `json:"direction,omitempty"`    // "supertype" or "subtype"
+	DeclaredAt   *Location `json:"declared_at,omitempty"`  // Where the relationship is declared
+}
+
+// ImportInfo describes where an import resolves: an indexed file (or Go
+// package directory), a named external or standard library package, or
+// nothing (an internal-looking import matching no indexed file).
+type ImportInfo struct {
+	Path         string `json:"path"`                    // Import as written
+	Resolution   string `json:"resolution"`              // "internal", "external", "stdlib", or "unresolved"
+	ResolvedPath string `json:"resolved_path,omitempty"` // Internal: imported file or Go package directory
+	Module       string `json:"module,omitempty"`        // Workspace module of the imported file
+	Package      string `json:"package,omitempty"`       // External and stdlib: package name
+	Version      string `json:"version,omitempty"`       // External: version from the dependency inventory
+
+	// Implementation is the source file implementing an included C/C++
+	// header (e.g. src/server.cpp for include/server.h)
+	Implementation string `json:"implementation,omitempty"`
+}
+
+// EndpointInfo describes an HTTP route or RPC procedure and where it is
+// registered.
+type EndpointInfo struct {
+	Method    string `json:"method"`            // GET, POST, ...; ANY when every method is accepted
+	Path      string `json:"path"`              // Route pattern or /package.Service/Method
+	Kind      string `json:"kind"`              // "http" or "rpc"
+	Framework string `json:"framework"`         // net/http, chi, gin, connect, express, flask, spring, rails, ...
+	Handler   string `json:"handler,omitempty"` // Handler as written; empty for inline handlers
+	File      string `json:"file"`              // File registering the route
+	Line      int    `json:"line"`
+	Resolved  bool   `json:"resolved"` // Whether the handler resolved to an indexed function
+}
+
+// ConfigUsageInfo describes a read of an environment variable or
+// configuration key, or a .env template variable no code reads.
+type ConfigUsageInfo struct {
+	Key       string   `json:"key"`
+	Kind      string   `json:"kind"`                 // "env" or "config"
+	Source    string   `json:"source,omitempty"`     // API the key is read through: os.Getenv, viper, process.env, ...
+	File      string   `json:"file"`                 // File reading the key (or defining it, when unused)
+	Line      int      `json:"line"`
+	Status    string   `json:"status"`               // "documented", "undocumented" (defined by no config file) or "unused" (read by no code)
+	DefinedIn []string `json:"defined_in,omitempty"` // file:line of the config files and .env templates defining the key
+}
+
+// TableUsageInfo describes a database table read, written or defined by SQL
+// embedded in code.
+type TableUsageInfo struct {
+	Table     string   `json:"table"`
+	Access    string   `json:"access"`            // "read", "write" or "schema"
+	Operation string   `json:"operation"`         // select, insert, update, delete, create, alter, drop, ...
+	Columns   []string `json:"columns,omitempty"` // Columns selected, inserted, set or defined, when known
+	Source    string   `json:"source,omitempty"`  // "sql" (string literal) or the query builder: squirrel, knex
+	File      string   `json:"file"`
+	Line      int      `json:"line"`
 }
 
 // ImpactSummary provides aggregate statistics for impact analysis.
@@ -73,8 +153,9 @@ type ImpactSummary struct {
 
 // ResponseMeta contains metadata about the query execution.
 type ResponseMeta struct {
-	TookMs int    `json:"took_ms"`
-	Source string `json:"source"` // Always "graph"
+	TookMs     int    `json:"took_ms"`
+	Source     string `json:"source"`     // Always "graph"
+	Generation int64  `json:"generation"` // Index generation the results were read from
 }
 
 // Searcher provides graph query capabilities with reverse indexes.
@@ -0,0 +1,735 @@
+package graph
+
+import (
+	"regexp"
+	"strings"
+)
+
+// SQLTableAccess is a table a SQL statement reads, writes or defines.
+type SQLTableAccess struct {
+	Table     string   // As written, unquoted, schema-qualified if it is
+	Access    string   // read, write or schema
+	Operation string   // select, insert, update, delete, replace, merge, truncate, create, alter or drop
+	Columns   []string // Columns selected, inserted, set or defined, when known
+	Line      int      // Line of the table in the statement (1-indexed)
+}
+
+// sqlStatement matches the start of a SQL statement: a leading keyword in
+// upper or lower case (not "Select a file" prose), after whitespace and
+// comments.
+var sqlStatement = regexp.MustCompile(`^(?:\s|--[^\n]*\n|/\*(?s:.*?)\*/)*(?:SELECT|INSERT|UPDATE|DELETE|REPLACE|MERGE|TRUNCATE|WITH|CREATE|ALTER|DROP|select|insert|update|delete|replace|merge|truncate|with|create|alter|drop)\s`)
+
+// sqlKeywords are the words that can't be table names, aliases or columns.
+var sqlKeywords = map[string]bool{
+	"ALL": true, "ALTER": true, "AND": true, "AS": true, "ASC": true, "BETWEEN": true, "BY": true,
+	"CASE": true, "CAST": true, "COLLATE": true, "CONFLICT": true, "CREATE": true, "CROSS": true,
+	"DEFAULT": true, "DELETE": true, "DESC": true, "DISTINCT": true, "DO": true, "DROP": true,
+	"ELSE": true, "END": true, "ESCAPE": true, "EXCEPT": true, "EXISTS": true, "FALSE": true,
+	"FETCH": true, "FILTER": true, "FOR": true, "FROM": true, "FULL": true, "GLOB": true, "GROUP": true,
+	"HAVING": true, "IF": true, "IN": true, "INDEX": true, "INNER": true, "INSERT": true,
+	"INTERSECT": true, "INTO": true, "IS": true, "JOIN": true, "LATERAL": true, "LEFT": true,
+	"LIKE": true, "LIMIT": true, "MATCH": true, "NATURAL": true, "NOT": true, "NOTHING": true,
+	"NULL": true, "OFFSET": true, "ON": true, "OR": true, "ORDER": true, "OUTER": true, "OVER": true,
+	"PARTITION": true, "RECURSIVE": true, "REGEXP": true, "RETURNING": true, "RIGHT": true,
+	"SELECT": true, "SET": true, "TABLE": true, "THEN": true, "TRUE": true, "UNION": true,
+	"UPDATE": true, "USING": true, "VALUES": true, "VIEW": true, "WHEN": true, "WHERE": true,
+	"WINDOW": true, "WITH": true,
+}
+
+// sqlDeterminers can't be unquoted table names: they are the "from the
+// list" of prose that starts like SQL.
+var sqlDeterminers = map[string]bool{
+	"A": true, "AN": true, "THE": true, "THIS": true, "THAT": true, "THESE": true, "THOSE": true,
+	"MY": true, "YOUR": true, "OUR": true, "THEIR": true, "ITS": true, "HIS": true, "HER": true,
+	"IT": true, "THEM": true, "EACH": true, "EVERY": true, "SOME": true, "ANY": true,
+}
+
+// sqlConstraints start the table constraints of a CREATE TABLE column list.
+var sqlConstraints = map[string]bool{
+	"CONSTRAINT": true, "PRIMARY": true, "FOREIGN": true, "UNIQUE": true, "CHECK": true, "KEY": true, "INDEX": true,
+}
+
+// Token kinds
+const (
+	sqlWord   = iota // Keyword or identifier
+	sqlQuoted        // Quoted identifier: "name", `name`, [name]
+	sqlValue         // String or number literal, placeholder
+	sqlPunct
+)
+
+type sqlToken struct {
+	kind int
+	text string
+	line int
+}
+
+// word reports whether the token is the keyword kw.
+func (t sqlToken) word(kw string) bool {
+	return t.kind == sqlWord && strings.EqualFold(t.text, kw)
+}
+
+// ident reports whether the token can be a name: a quoted identifier or a
+// word that isn't a keyword.
+func (t sqlToken) ident() bool {
+	return t.kind == sqlQuoted || (t.kind == sqlWord && !sqlKeywords[strings.ToUpper(t.text)])
+}
+
+// IsSQL reports whether s starts like a SQL statement.
+func IsSQL(s string) bool {
+	return sqlStatement.MatchString(s)
+}
+
+// ParseSQL returns the tables the SQL statements in query read, write or
+// define, each once per access and operation. Tables are read by FROM and
+// JOIN clauses; written by INSERT, REPLACE, UPDATE, DELETE, MERGE and
+// TRUNCATE; defined by CREATE, ALTER and DROP of tables, views, indexes
+// and triggers. Common table expressions aren't tables. Strings that don't
+// start like SQL return nothing.
+//
+// The parse is lexical and forgiving: placeholders (?, $1, :name, %s) may
+// stand anywhere, and unknown syntax is skipped rather than rejected.
+func ParseSQL(query string) []SQLTableAccess {
+	if !IsSQL(query) {
+		return nil
+	}
+	p := &sqlParser{tokens: tokenizeSQL(query), ctes: make(map[string]bool)}
+	p.findCTEs()
+	p.parse()
+	return p.result
+}
+
+type sqlParser struct {
+	tokens []sqlToken
+	pos    int
+	ctes   map[string]bool
+	scopes []*selectScope // Open SELECTs, innermost last
+	from   bool           // In the FROM clause of an UPDATE
+	result []SQLTableAccess
+}
+
+// selectScope collects the columns and tables of a SELECT until it closes,
+// to attribute the columns.
+type selectScope struct {
+	depth   int
+	columns []sqlColumn
+	tables  []sqlTableRef
+}
+
+type sqlColumn struct {
+	qualifier, name string
+}
+
+type sqlTableRef struct {
+	name, alias string
+	line        int
+}
+
+// parse walks the tokens, recording the tables of each clause.
+func (p *sqlParser) parse() {
+	depth := 0
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		p.pos++
+		switch {
+		case t.text == "(":
+			depth++
+			continue
+		case t.text == ")":
+			p.closeScopes(depth)
+			depth--
+			continue
+		case t.text == ";":
+			p.closeScopes(0)
+			p.from = false
+			continue
+		case t.kind != sqlWord:
+			continue
+		}
+
+		switch strings.ToUpper(t.text) {
+		case "SELECT":
+			p.closeScopes(depth)
+			p.parseSelect(depth)
+		case "UNION", "INTERSECT", "EXCEPT":
+			p.closeScopes(depth)
+		case "FROM", "JOIN":
+			// Only a SELECT's or UPDATE's: not EXTRACT(YEAR FROM t), IS
+			// DISTINCT FROM, or the "from" of prose that looked like SQL
+			if p.prevWord("DISTINCT") || (p.scope(depth) == nil && !p.from) {
+				continue
+			}
+			p.parseTableList(depth)
+		case "INSERT", "REPLACE":
+			if p.optional("OR") {
+				p.pos++ // INSERT OR REPLACE, OR IGNORE
+			}
+			if p.optional("INTO") {
+				p.parseWrite(strings.ToLower(t.text))
+			}
+		case "MERGE":
+			if p.optional("INTO") {
+				p.parseWrite("merge")
+			}
+		case "UPDATE":
+			if p.optional("OR") {
+				p.pos++
+			}
+			p.optional("ONLY")
+			p.parseUpdate()
+		case "DELETE":
+			if p.optional("FROM") {
+				p.optional("ONLY")
+				p.parseWrite("delete")
+			}
+		case "TRUNCATE":
+			p.optional("TABLE")
+			for {
+				if name, line, ok := p.name(); ok {
+					p.add(name, TableAccessWrite, "truncate", nil, line)
+				}
+				if !p.optional(",") {
+					break
+				}
+			}
+		case "CREATE":
+			p.parseCreate()
+		case "ALTER":
+			if p.optional("TABLE") {
+				p.parseAlter()
+			}
+		case "DROP":
+			if p.optional("TABLE") || p.optional("VIEW") {
+				p.optionalSeq("IF", "EXISTS")
+				if name, line, ok := p.name(); ok {
+					p.add(name, TableAccessSchema, "drop", nil, line)
+				}
+			}
+		}
+	}
+	p.closeScopes(0)
+}
+
+// parseSelect opens the scope of a SELECT and collects the columns of its
+// select list, up to its FROM.
+func (p *sqlParser) parseSelect(depth int) {
+	scope := &selectScope{depth: depth}
+	p.scopes = append(p.scopes, scope)
+	p.optional("DISTINCT")
+	p.optional("ALL")
+
+	level := 0
+	for i := p.pos; i < len(p.tokens); i++ {
+		t := p.tokens[i]
+		switch {
+		case t.text == "(":
+			if i+1 < len(p.tokens) && p.tokens[i+1].word("SELECT") {
+				return // Subquery: its own scope
+			}
+			level++
+		case t.text == ")":
+			if level == 0 {
+				return
+			}
+			level--
+		case t.text == ";":
+			return
+		case level == 0 && (t.word("FROM") || t.word("INTO") || t.word("WHERE") || t.word("UNION")):
+			return
+		case t.ident() && !p.nextIs(i, "(") && !p.prevIs(i, "AS") && !p.prevIs(i, "."):
+			if level == 0 && i > p.pos {
+				if prev := p.tokens[i-1]; prev.ident() || prev.kind == sqlValue || prev.text == ")" || prev.word("END") {
+					continue // Alias without AS: SELECT count(*) total
+				}
+			}
+			column := sqlColumn{name: unquoteSQL(t)}
+			if p.nextIs(i, ".") && i+2 < len(p.tokens) {
+				if p.tokens[i+2].text == "*" {
+					continue
+				}
+				column = sqlColumn{qualifier: column.name, name: unquoteSQL(p.tokens[i+2])}
+			}
+			scope.columns = append(scope.columns, column)
+		}
+	}
+}
+
+// parseTableList records the tables of a FROM or JOIN clause: names with
+// optional aliases, separated by commas, in the SELECT at depth (read
+// directly in an UPDATE). Subqueries and table functions are skipped.
+func (p *sqlParser) parseTableList(depth int) {
+	for {
+		if p.pos < len(p.tokens) && p.tokens[p.pos].text == "(" {
+			return
+		}
+		p.optional("LATERAL")
+		name, line, ok := p.name()
+		if !ok {
+			return
+		}
+		if p.pos < len(p.tokens) && p.tokens[p.pos].text == "(" {
+			return // Table function: json_each(x), generate_series(1, 5)
+		}
+		ref := sqlTableRef{name: name, alias: p.alias(), line: line}
+		if !p.ctes[strings.ToLower(name)] {
+			if scope := p.scope(depth); scope != nil {
+				scope.tables = append(scope.tables, ref)
+			} else {
+				p.add(name, TableAccessRead, "select", nil, line)
+			}
+		}
+		if !p.optional(",") {
+			return
+		}
+	}
+}
+
+// parseWrite records the table an INSERT, REPLACE, MERGE or DELETE writes,
+// with the columns of an INSERT column list.
+func (p *sqlParser) parseWrite(operation string) {
+	name, line, ok := p.name()
+	if !ok {
+		return
+	}
+	p.alias()
+	var columns []string
+	if operation != "delete" && p.pos < len(p.tokens) && p.tokens[p.pos].text == "(" &&
+		!(p.pos+1 < len(p.tokens) && p.tokens[p.pos+1].word("SELECT")) {
+		columns = p.columnList()
+	}
+	p.add(name, TableAccessWrite, operation, columns, line)
+}
+
+// parseUpdate records the table an UPDATE writes with the columns it sets.
+// An upsert's DO UPDATE SET names no table and is skipped, as is an
+// "update" without SET, which is prose.
+func (p *sqlParser) parseUpdate() {
+	if p.pos < len(p.tokens) && p.tokens[p.pos].word("SET") {
+		return
+	}
+	name, line, ok := p.name()
+	if !ok {
+		return
+	}
+	p.alias()
+	if !p.optional("SET") {
+		return
+	}
+
+	var columns []string
+	level := 0
+	expectColumn := true
+	for ; p.pos < len(p.tokens); p.pos++ {
+		t := p.tokens[p.pos]
+		switch {
+		case t.text == "(":
+			level++
+		case t.text == ")":
+			if level == 0 {
+				p.add(name, TableAccessWrite, "update", columns, line)
+				return
+			}
+			level--
+		case t.text == ";":
+			p.add(name, TableAccessWrite, "update", columns, line)
+			return
+		case level > 0:
+		case t.word("WHERE") || t.word("FROM") || t.word("RETURNING"):
+			p.from = t.word("FROM")
+			p.add(name, TableAccessWrite, "update", columns, line)
+			return
+		case t.text == ",":
+			expectColumn = true
+		case expectColumn && t.ident():
+			column := unquoteSQL(t)
+			if p.nextIs(p.pos, ".") && p.pos+2 < len(p.tokens) {
+				column = unquoteSQL(p.tokens[p.pos+2]) // SET t.col = ...
+				p.pos += 2
+			}
+			columns = append(columns, column)
+			expectColumn = false
+		default:
+			expectColumn = false
+		}
+	}
+	p.add(name, TableAccessWrite, "update", columns, line)
+}
+
+// parseCreate records the table, view, index or trigger a CREATE defines,
+// with the columns of a table or index.
+func (p *sqlParser) parseCreate() {
+	for p.pos < len(p.tokens) {
+		switch strings.ToUpper(p.tokens[p.pos].text) {
+		case "OR", "REPLACE", "TEMP", "TEMPORARY", "UNIQUE", "MATERIALIZED", "VIRTUAL", "UNLOGGED", "GLOBAL", "LOCAL":
+			p.pos++
+			continue
+		case "TABLE":
+			p.pos++
+			p.optionalSeq("IF", "NOT", "EXISTS")
+			name, line, ok := p.name()
+			if !ok {
+				return
+			}
+			p.optional("USING")
+			if p.pos < len(p.tokens) && p.tokens[p.pos].kind == sqlWord && p.nextIs(p.pos, "(") {
+				p.pos++ // Virtual table module: USING fts5(...)
+			}
+			var columns []string
+			if p.pos < len(p.tokens) && p.tokens[p.pos].text == "(" {
+				columns = p.columnDefinitions()
+			}
+			p.add(name, TableAccessSchema, "create", columns, line)
+		case "VIEW":
+			p.pos++
+			p.optionalSeq("IF", "NOT", "EXISTS")
+			if name, line, ok := p.name(); ok {
+				p.add(name, TableAccessSchema, "create", nil, line)
+			}
+		case "INDEX":
+			p.pos++
+			p.optionalSeq("IF", "NOT", "EXISTS")
+			if p.pos < len(p.tokens) && !p.tokens[p.pos].word("ON") {
+				p.name() // Index name
+			}
+			if !p.optional("ON") {
+				return
+			}
+			name, line, ok := p.name()
+			if !ok {
+				return
+			}
+			var columns []string
+			if p.pos < len(p.tokens) && p.tokens[p.pos].text == "(" {
+				columns = p.columnList()
+			}
+			p.add(name, TableAccessSchema, "create", columns, line)
+		case "TRIGGER":
+			// The header names events (UPDATE OF col) that aren't
+			// statements; the body is parsed as usual
+			for p.pos < len(p.tokens) && !p.tokens[p.pos].word("ON") {
+				p.pos++
+			}
+			if p.optional("ON") {
+				if name, line, ok := p.name(); ok {
+					p.add(name, TableAccessSchema, "create", nil, line)
+				}
+			}
+		}
+		return
+	}
+}
+
+// parseAlter records the table an ALTER TABLE changes, with the column it
+// adds, renames or drops.
+func (p *sqlParser) parseAlter() {
+	p.optionalSeq("IF", "EXISTS")
+	p.optional("ONLY")
+	name, line, ok := p.name()
+	if !ok {
+		return
+	}
+	var columns []string
+	if p.optional("ADD") || p.optional("DROP") || p.optional("RENAME") || p.optional("ALTER") {
+		p.optional("COLUMN")
+		p.optionalSeq("IF", "NOT", "EXISTS")
+		p.optionalSeq("IF", "EXISTS")
+		if t := p.tokens[min(p.pos, len(p.tokens)-1)]; p.pos < len(p.tokens) && t.ident() && !t.word("TO") && !sqlConstraints[strings.ToUpper(t.text)] {
+			columns = []string{unquoteSQL(p.tokens[p.pos])}
+		}
+	}
+	p.add(name, TableAccessSchema, "alter", columns, line)
+}
+
+// columnList returns the names of a parenthesized column list: (a, b DESC).
+func (p *sqlParser) columnList() []string {
+	var columns []string
+	expect := true
+	for p.pos++; p.pos < len(p.tokens) && p.tokens[p.pos].text != ")"; p.pos++ {
+		t := p.tokens[p.pos]
+		switch {
+		case t.text == ",":
+			expect = true
+		case expect && t.ident():
+			columns = append(columns, unquoteSQL(t))
+			expect = false
+		default:
+			expect = false
+		}
+	}
+	p.pos++
+	return columns
+}
+
+// columnDefinitions returns the columns a CREATE TABLE defines: the first
+// name of each entry of its column list, except table constraints (MySQL's
+// KEY idx (col) too, not a column named key) and virtual table options
+// (content='chunks').
+func (p *sqlParser) columnDefinitions() []string {
+	var columns []string
+	level := 0
+	expect := true
+	for p.pos++; p.pos < len(p.tokens); p.pos++ {
+		t := p.tokens[p.pos]
+		switch {
+		case t.text == "(":
+			level++
+		case t.text == ")":
+			if level == 0 {
+				p.pos++
+				return columns
+			}
+			level--
+		case level > 0:
+		case t.text == ",":
+			expect = true
+		case expect:
+			expect = false
+			switch upper := strings.ToUpper(t.text); {
+			case t.kind != sqlWord && t.kind != sqlQuoted, p.nextIs(p.pos, "="):
+			case upper == "KEY" || upper == "INDEX":
+				if !p.nextIs(p.pos, "(") && !(p.pos+2 < len(p.tokens) && p.tokens[p.pos+1].kind == sqlWord && p.nextIs(p.pos+1, "(")) {
+					columns = append(columns, unquoteSQL(t))
+				}
+			case !sqlConstraints[upper]:
+				columns = append(columns, unquoteSQL(t))
+			}
+		}
+	}
+	return columns
+}
+
+// name consumes a table name, schema-qualified or not.
+func (p *sqlParser) name() (string, int, bool) {
+	if p.pos >= len(p.tokens) || !p.tokens[p.pos].ident() {
+		return "", 0, false
+	}
+	t := p.tokens[p.pos]
+	if t.kind == sqlWord && sqlDeterminers[strings.ToUpper(t.text)] {
+		return "", 0, false
+	}
+	name := unquoteSQL(t)
+	p.pos++
+	for p.pos+1 < len(p.tokens) && p.tokens[p.pos].text == "." && p.tokens[p.pos+1].ident() {
+		name += "." + unquoteSQL(p.tokens[p.pos+1])
+		p.pos += 2
+	}
+	return name, t.line, true
+}
+
+// alias consumes the alias following a table name, if any.
+func (p *sqlParser) alias() string {
+	p.optional("AS")
+	if p.pos < len(p.tokens) && p.tokens[p.pos].ident() {
+		p.pos++
+		return unquoteSQL(p.tokens[p.pos-1])
+	}
+	return ""
+}
+
+// findCTEs collects the names of common table expressions: the names
+// followed by AS ( (with an optional column list) after WITH or a comma.
+func (p *sqlParser) findCTEs() {
+	for i := 1; i+2 < len(p.tokens); i++ {
+		prev := p.tokens[i-1]
+		if !(prev.word("WITH") || prev.word("RECURSIVE") || prev.text == ",") || !p.tokens[i].ident() {
+			continue
+		}
+		j := i + 1
+		if p.tokens[j].text == "(" {
+			for j < len(p.tokens) && p.tokens[j].text != ")" {
+				j++
+			}
+			j++
+		}
+		if j+1 < len(p.tokens) && p.tokens[j].word("AS") && (p.tokens[j+1].text == "(" || p.tokens[j+1].word("NOT") || p.tokens[j+1].word("MATERIALIZED")) {
+			p.ctes[strings.ToLower(unquoteSQL(p.tokens[i]))] = true
+		}
+	}
+}
+
+// scope returns the innermost open SELECT at depth.
+func (p *sqlParser) scope(depth int) *selectScope {
+	if n := len(p.scopes); n > 0 && p.scopes[n-1].depth == depth {
+		return p.scopes[n-1]
+	}
+	return nil
+}
+
+// closeScopes closes the SELECTs at depth or deeper, recording their tables
+// with the columns selected from each: qualified columns by table or alias,
+// unqualified ones when a single table is selected from.
+func (p *sqlParser) closeScopes(depth int) {
+	for len(p.scopes) > 0 && p.scopes[len(p.scopes)-1].depth >= depth {
+		scope := p.scopes[len(p.scopes)-1]
+		p.scopes = p.scopes[:len(p.scopes)-1]
+		for _, table := range scope.tables {
+			var columns []string
+			for _, c := range scope.columns {
+				switch {
+				case c.qualifier == "" && len(scope.tables) == 1,
+					c.qualifier != "" && (strings.EqualFold(c.qualifier, table.alias) || strings.EqualFold(c.qualifier, table.name)):
+					columns = append(columns, c.name)
+				}
+			}
+			p.add(table.name, TableAccessRead, "select", columns, table.line)
+		}
+	}
+}
+
+// add records an access, merging it into an earlier one of the same table,
+// access and operation.
+func (p *sqlParser) add(table, access, operation string, columns []string, line int) {
+	for i := range p.result {
+		r := &p.result[i]
+		if strings.EqualFold(r.Table, table) && r.Access == access && r.Operation == operation {
+			for _, c := range columns {
+				if !containsFold(r.Columns, c) {
+					r.Columns = append(r.Columns, c)
+				}
+			}
+			return
+		}
+	}
+	var unique []string
+	for _, c := range columns {
+		if !containsFold(unique, c) {
+			unique = append(unique, c)
+		}
+	}
+	p.result = append(p.result, SQLTableAccess{Table: table, Access: access, Operation: operation, Columns: unique, Line: line})
+}
+
+// optional consumes the keyword or punctuation kw if it comes next.
+func (p *sqlParser) optional(kw string) bool {
+	if p.pos < len(p.tokens) && (p.tokens[p.pos].word(kw) || (p.tokens[p.pos].kind == sqlPunct && p.tokens[p.pos].text == kw)) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// optionalSeq consumes the keywords kws if they all come next.
+func (p *sqlParser) optionalSeq(kws ...string) bool {
+	if p.pos+len(kws) > len(p.tokens) {
+		return false
+	}
+	for i, kw := range kws {
+		if !p.tokens[p.pos+i].word(kw) {
+			return false
+		}
+	}
+	p.pos += len(kws)
+	return true
+}
+
+// prevWord reports whether the token before the one just consumed is kw.
+func (p *sqlParser) prevWord(kw string) bool {
+	return p.pos >= 2 && p.tokens[p.pos-2].word(kw)
+}
+
+// nextIs reports whether the token after i is the punctuation s.
+func (p *sqlParser) nextIs(i int, s string) bool {
+	return i+1 < len(p.tokens) && p.tokens[i+1].kind == sqlPunct && p.tokens[i+1].text == s
+}
+
+// prevIs reports whether the token before i is the keyword or punctuation s.
+func (p *sqlParser) prevIs(i int, s string) bool {
+	return i > 0 && (p.tokens[i-1].word(s) || (p.tokens[i-1].kind == sqlPunct && p.tokens[i-1].text == s))
+}
+
+// tokenizeSQL splits a query into tokens, dropping comments and the text of
+// string literals.
+func tokenizeSQL(query string) []sqlToken {
+	var tokens []sqlToken
+	line := 1
+	for i := 0; i < len(query); {
+		c := query[i]
+		start := i
+		switch {
+		case c == '\n':
+			line++
+			i++
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			continue
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return tokens
+			}
+			line += strings.Count(query[i:i+2+end], "\n")
+			i += end + 4
+			continue
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closing := c
+			if c == '[' {
+				closing = ']'
+			}
+			i++
+			for i < len(query) && query[i] != closing {
+				i++
+			}
+			i++
+			if i > len(query) {
+				i = len(query)
+			}
+			kind := sqlQuoted
+			if c == '\'' {
+				kind = sqlValue
+			}
+			tokens = append(tokens, sqlToken{kind: kind, text: query[start:i], line: line})
+			line += strings.Count(query[start:i], "\n")
+			continue
+		case isSQLWordByte(c) && c != '$' && !(c >= '0' && c <= '9'):
+			for i < len(query) && isSQLWordByte(query[i]) {
+				i++
+			}
+			tokens = append(tokens, sqlToken{kind: sqlWord, text: query[start:i], line: line})
+			continue
+		case c >= '0' && c <= '9', c == '?':
+			i++
+			for i < len(query) && isSQLWordByte(query[i]) {
+				i++
+			}
+		case (c == '$' || c == ':' || c == '@' || c == '%') && i+1 < len(query) && isSQLWordByte(query[i+1]):
+			// Placeholders: $1, :name, @p1, %s; a PostgreSQL cast (::text)
+			// is punctuation followed by a type
+			i++
+			for i < len(query) && isSQLWordByte(query[i]) {
+				i++
+			}
+		default:
+			tokens = append(tokens, sqlToken{kind: sqlPunct, text: string(c), line: line})
+			i++
+			continue
+		}
+		tokens = append(tokens, sqlToken{kind: sqlValue, text: query[start:i], line: line})
+	}
+	return tokens
+}
+
+func isSQLWordByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
+
+// unquoteSQL returns the name a token holds, without identifier quotes.
+func unquoteSQL(t sqlToken) string {
+	if t.kind == sqlQuoted && len(t.text) >= 2 {
+		return t.text[1 : len(t.text)-1]
+	}
+	return t.text
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
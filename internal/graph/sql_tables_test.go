@@ -0,0 +1,198 @@
+package graph
+
+// Test Plan for ParseSQL:
+// - Strings that don't start like a statement, and prose that does, return nothing
+// - SELECT reads FROM and JOIN tables, attributing selected columns by alias or to
+//   a single table; subqueries, CTEs, table functions and EXTRACT(... FROM) are not tables
+// - INSERT, REPLACE, UPDATE (with its FROM), DELETE, TRUNCATE and upserts write tables
+//   with the columns inserted or set
+// - CREATE TABLE/VIRTUAL TABLE/INDEX/VIEW/TRIGGER, ALTER and DROP define tables;
+//   trigger bodies are parsed as statements
+// - Quoted identifiers, schema-qualified names, comments and placeholders; lines of
+//   tables in multi-line statements
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sqlAccesses summarizes ParseSQL as "access operation table [columns] @line".
+func sqlAccesses(query string) []string {
+	var out []string
+	for _, a := range ParseSQL(query) {
+		out = append(out, fmt.Sprintf("%s %s %s [%s] @%d", a.Access, a.Operation, a.Table, strings.Join(a.Columns, " "), a.Line))
+	}
+	return out
+}
+
+func TestParseSQL_NotSQL(t *testing.T) {
+	t.Parallel()
+
+	for _, s := range []string{
+		"",
+		"file_path = ?",
+		"Select a file from the list",
+		"delete the file from disk",
+		"update failed: %w",
+		"SELECTED",
+	} {
+		assert.Empty(t, sqlAccesses(s), s)
+	}
+}
+
+func TestParseSQL_Select(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "single table columns",
+			query: "SELECT file_path, COUNT(*) AS n, max(line) last FROM chunks WHERE kind = ?",
+			want:  []string{"read select chunks [file_path line] @1"},
+		},
+		{
+			name:  "join with aliases",
+			query: "select f.name, c.callee_name from functions f LEFT JOIN function_calls AS c ON c.caller_function_id = f.function_id",
+			want: []string{
+				"read select functions [name] @1",
+				"read select function_calls [callee_name] @1",
+			},
+		},
+		{
+			name:  "comma list and schema",
+			query: `SELECT * FROM main.files, "types" t WHERE t.file_path = files.file_path`,
+			want: []string{
+				"read select main.files [] @1",
+				"read select types [] @1",
+			},
+		},
+		{
+			name: "cte, subquery and table function",
+			query: `WITH RECURSIVE callers(id) AS (
+				SELECT caller_function_id FROM function_calls WHERE callee_function_id = $1
+			)
+			SELECT f.name FROM functions f
+			WHERE f.function_id IN (SELECT id FROM callers)
+			  AND EXISTS (SELECT 1 FROM json_each(f.tags))
+			  AND EXTRACT(YEAR FROM f.indexed_at) > 2020
+			  AND f.a IS DISTINCT FROM f.b`,
+			want: []string{
+				"read select function_calls [caller_function_id] @2",
+				"read select functions [name] @4",
+			},
+		},
+		{
+			name:  "union",
+			query: "SELECT id FROM users UNION ALL SELECT id FROM admins",
+			want:  []string{"read select users [id] @1", "read select admins [id] @1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sqlAccesses(tt.query))
+		})
+	}
+}
+
+func TestParseSQL_Writes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "insert with select",
+			query: "INSERT INTO archive (id, body) SELECT id, body FROM chunks WHERE stale",
+			want: []string{
+				"write insert archive [id body] @1",
+				"read select chunks [id body] @1",
+			},
+		},
+		{
+			name:  "insert or replace and upsert",
+			query: "INSERT OR REPLACE INTO `files` (file_path, hash) VALUES (?, ?) ON CONFLICT (file_path) DO UPDATE SET hash = excluded.hash",
+			want:  []string{"write insert files [file_path hash] @1"},
+		},
+		{
+			name:  "replace",
+			query: "REPLACE INTO cache VALUES (:key, :value)",
+			want:  []string{"write replace cache [] @1"},
+		},
+		{
+			name:  "update from",
+			query: "UPDATE files AS f SET indexed_at = now(), f.hash = %s FROM staging s WHERE s.path = f.file_path",
+			want: []string{
+				"write update files [indexed_at hash] @1",
+				"read select staging [] @1",
+			},
+		},
+		{
+			name:  "delete and truncate",
+			query: "DELETE FROM chunks WHERE file_path IN (SELECT file_path FROM files WHERE deleted); TRUNCATE TABLE cache, sessions",
+			want: []string{
+				"write delete chunks [] @1",
+				"read select files [file_path] @1",
+				"write truncate cache [] @1",
+				"write truncate sessions [] @1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, sqlAccesses(tt.query))
+		})
+	}
+}
+
+func TestParseSQL_Schema(t *testing.T) {
+	t.Parallel()
+
+	query := `
+-- Chunks of indexed files
+CREATE TABLE IF NOT EXISTS chunks (
+    chunk_id TEXT PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    key TEXT,
+    embedding BLOB,
+    KEY idx_key (key),
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_chunks_file ON chunks(file_path, chunk_id DESC);
+CREATE VIRTUAL TABLE chunks_fts USING fts5(text, file_path UNINDEXED, content='chunks');
+CREATE VIEW IF NOT EXISTS stale_chunks AS
+SELECT c.chunk_id FROM chunks c JOIN files f ON f.file_path = c.file_path;
+CREATE TRIGGER chunks_ad AFTER DELETE ON chunks BEGIN
+    INSERT INTO chunks_fts(chunks_fts, rowid) VALUES ('delete', old.rowid);
+END;
+ALTER TABLE chunks ADD COLUMN language TEXT;
+DROP TABLE IF EXISTS legacy_chunks;
+DROP INDEX idx_old;
+`
+	assert.Equal(t, []string{
+		"schema create chunks [chunk_id file_path key embedding] @3",
+		"schema create chunks_fts [text file_path] @12",
+		"schema create stale_chunks [] @13",
+		"read select chunks [chunk_id] @14",
+		"read select files [] @14",
+		"write insert chunks_fts [chunks_fts rowid] @16",
+		"schema alter chunks [language] @18",
+		"schema drop legacy_chunks [] @19",
+	}, sqlAccesses(query))
+}
+
+func TestParseSQL_Merges(t *testing.T) {
+	t.Parallel()
+
+	// One access per table, access and operation, with the columns of each
+	assert.Equal(t, []string{
+		"read select files [file_path language] @1",
+	}, sqlAccesses("SELECT file_path FROM files; SELECT language, file_path FROM FILES"))
+}
@@ -0,0 +1,349 @@
+package graph
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+)
+
+// squirrelImport is the import path of squirrel, whose statement builders
+// name the tables they query.
+const squirrelImport = "github.com/Masterminds/squirrel"
+
+// tableReader finds the tables the SQL embedded in a Go file touches.
+type tableReader struct {
+	fset       *token.FileSet
+	relPath    string
+	packages   map[string]string      // Import path of each imported package, by local name
+	consts     map[string]string      // File-level string constants, for SQL and table names
+	builders   map[string]bool        // Variables holding a squirrel StatementBuilder
+	chains     map[*ast.CallExpr]bool // Root calls of the builder chains recorded
+	function   string                 // Function being walked (Recv.Name)
+	functionID string
+	usages     []TableUsage
+}
+
+// extractTableUsages returns the tables read, written or defined by the SQL
+// a Go file embeds: string literals and constant concatenations that start
+// like a statement, file-level SQL constants (at their declaration and
+// where a function uses them), and squirrel builder chains
+// (sq.Select(...).From("t"), sq.Insert("t").Columns(...), sq.Update("t").Set(...),
+// sq.Delete("t")). Queries built at run time (fmt.Sprintf, table names in
+// variables) are read as far as their literal parts go.
+func extractTableUsages(file *ast.File, fset *token.FileSet, relPath string) []TableUsage {
+	r := &tableReader{
+		fset:     fset,
+		relPath:  relPath,
+		packages: make(map[string]string),
+		consts:   make(map[string]string),
+		builders: make(map[string]bool),
+		chains:   make(map[*ast.CallExpr]bool),
+	}
+	for _, imp := range file.Imports {
+		importPath := strings.Trim(imp.Path.Value, `"`)
+		name := importPath[strings.LastIndex(importPath, "/")+1:]
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		r.packages[name] = importPath
+	}
+
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, name := range vs.Names {
+				if i < len(vs.Values) {
+					if value, ok := r.stringValue(vs.Values[i]); ok {
+						r.consts[name.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			r.function = d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				r.function = extractReceiverType(d.Recv.List[0].Type) + "." + d.Name.Name
+			}
+			r.functionID = fmt.Sprintf("%s::%s", relPath, r.function)
+			if d.Body != nil {
+				r.walk(d.Body, true)
+			}
+		case *ast.GenDecl:
+			r.function, r.functionID = "", ""
+			for _, spec := range d.Specs {
+				if vs, ok := spec.(*ast.ValueSpec); ok {
+					for i, value := range vs.Values {
+						if i < len(vs.Names) && r.isBuilder(value) {
+							r.builders[vs.Names[i].Name] = true
+						}
+						r.walk(value, false)
+					}
+				}
+			}
+		}
+	}
+	return r.usages
+}
+
+// walk records the statements in node. In function bodies, uses of SQL
+// constants count as statements of the function.
+func (r *tableReader) walk(node ast.Node, inFunction bool) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BasicLit:
+			if value, ok := r.stringValue(n); ok {
+				r.addSQL(value, "sql", n.Pos(), true)
+			}
+		case *ast.BinaryExpr:
+			// "SELECT ... FROM " + "chunks": the whole constant; else its
+			// literal parts
+			if value, ok := r.stringValue(n); ok && n.Op == token.ADD {
+				r.addSQL(value, "sql", n.Pos(), true)
+				return false
+			}
+		case *ast.Ident:
+			if query, ok := r.consts[n.Name]; ok && inFunction && IsSQL(query) {
+				r.addSQL(query, "sql", n.Pos(), false)
+			}
+		case *ast.SelectorExpr:
+			r.walk(n.X, inFunction) // Not the selected name: x.createTable
+			return false
+		case *ast.AssignStmt:
+			// psql := sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+			for i, rhs := range n.Rhs {
+				if i < len(n.Lhs) && r.isBuilder(rhs) {
+					if ident, ok := n.Lhs[i].(*ast.Ident); ok {
+						r.builders[ident.Name] = true
+					}
+				}
+			}
+		case *ast.CallExpr:
+			r.visitChain(n)
+		}
+		return true
+	})
+}
+
+// chainCall is a method call of a builder chain.
+type chainCall struct {
+	name string
+	args []ast.Expr
+}
+
+// visitChain records the statement a squirrel builder chain builds, once
+// per chain: from its outermost call, which is visited first.
+func (r *tableReader) visitChain(call *ast.CallExpr) {
+	calls, root := r.squirrelChain(call)
+	if root == nil || r.chains[root] {
+		return
+	}
+	r.chains[root] = true
+
+	if query := r.squirrelSQL(calls); query != "" {
+		r.addSQL(query, "squirrel", call.Pos(), false)
+	}
+}
+
+// squirrelChain returns the calls of the squirrel builder chain ending in
+// call, innermost first, with its root call: on the package
+// (sq.Select(...)), its StatementBuilder, or a variable holding one. It
+// returns nil for other calls.
+func (r *tableReader) squirrelChain(call *ast.CallExpr) ([]chainCall, *ast.CallExpr) {
+	var calls []chainCall
+	for c := call; ; {
+		sel, ok := c.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil, nil
+		}
+		calls = append([]chainCall{{name: sel.Sel.Name, args: c.Args}}, calls...)
+		next, ok := sel.X.(*ast.CallExpr)
+		if ok {
+			c = next
+			continue
+		}
+
+		x := sel.X
+		if inner, ok := x.(*ast.SelectorExpr); ok && inner.Sel.Name == "StatementBuilder" {
+			x = inner.X
+		}
+		ident, ok := x.(*ast.Ident)
+		if !ok || (r.packages[ident.Name] != squirrelImport && !r.builders[ident.Name]) {
+			return nil, nil
+		}
+		return calls, c
+	}
+}
+
+// isBuilder reports whether expr is a squirrel builder chain that hasn't
+// started a statement: a configured StatementBuilder.
+func (r *tableReader) isBuilder(expr ast.Expr) bool {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+	calls, root := r.squirrelChain(call)
+	if root == nil {
+		return false
+	}
+	for _, c := range calls {
+		switch c.name {
+		case "Select", "Insert", "Replace", "Update", "Delete":
+			return false
+		}
+	}
+	return true
+}
+
+// squirrelSQL returns the statement a squirrel chain builds, with the
+// tables and columns it names: "SELECT a, b FROM t JOIN u ON ...",
+// "INSERT INTO t (a, b)", "UPDATE t SET a = ?", "DELETE FROM t". Arguments
+// that aren't strings or SQL constants are left out.
+func (r *tableReader) squirrelSQL(calls []chainCall) string {
+	var verb, table string
+	var columns, joins []string
+	for _, c := range calls {
+		strs := r.stringArgs(c.args)
+		switch c.name {
+		case "Select", "Columns", "Column":
+			if verb == "" {
+				verb = "SELECT"
+			}
+			columns = append(columns, strs...)
+		case "Insert", "Replace", "Update", "Delete":
+			verb = strings.ToUpper(c.name)
+			if len(strs) > 0 {
+				table = strs[0]
+			}
+		case "From", "Into", "Table":
+			if len(strs) > 0 {
+				table = strs[0]
+			}
+		case "Join", "LeftJoin", "RightJoin", "InnerJoin", "CrossJoin", "FullJoin":
+			if len(strs) > 0 {
+				joins = append(joins, strs[0])
+			}
+		case "Set":
+			if len(c.args) > 0 {
+				if column, ok := r.stringValue(c.args[0]); ok {
+					columns = append(columns, column)
+				}
+			}
+		case "SetMap":
+			if len(c.args) > 0 {
+				if lit, ok := c.args[0].(*ast.CompositeLit); ok {
+					for _, elt := range lit.Elts {
+						if kv, ok := elt.(*ast.KeyValueExpr); ok {
+							if key, ok := r.stringValue(kv.Key); ok {
+								columns = append(columns, key)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	if table == "" {
+		return ""
+	}
+
+	switch verb {
+	case "SELECT":
+		if len(columns) == 0 {
+			columns = []string{"*"}
+		}
+		query := "SELECT " + strings.Join(columns, ", ") + " FROM " + table
+		for _, join := range joins {
+			query += " JOIN " + join
+		}
+		return query
+	case "INSERT", "REPLACE":
+		query := verb + " INTO " + table
+		if len(columns) > 0 {
+			query += " (" + strings.Join(columns, ", ") + ")"
+		}
+		return query
+	case "UPDATE":
+		sets := make([]string, len(columns))
+		for i, c := range columns {
+			sets[i] = c + " = ?"
+		}
+		return "UPDATE " + table + " SET " + strings.Join(sets, ", ")
+	case "DELETE":
+		return "DELETE FROM " + table
+	}
+	return ""
+}
+
+// stringArgs returns the values of the arguments that are strings or
+// constants.
+func (r *tableReader) stringArgs(args []ast.Expr) []string {
+	var strs []string
+	for _, arg := range args {
+		if s, ok := r.stringValue(arg); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// addSQL records the tables a statement at pos touches; for literals, on
+// the lines of the tables within it.
+func (r *tableReader) addSQL(query, source string, pos token.Pos, literal bool) {
+	line := r.fset.Position(pos).Line
+	for _, a := range ParseSQL(query) {
+		tableLine := line
+		if literal {
+			tableLine += a.Line - 1
+		}
+		r.usages = append(r.usages, TableUsage{
+			FilePath:   r.relPath,
+			Line:       tableLine,
+			Table:      a.Table,
+			Access:     a.Access,
+			Operation:  a.Operation,
+			Columns:    a.Columns,
+			Source:     source,
+			Function:   r.function,
+			FunctionID: r.functionID,
+		})
+	}
+}
+
+// stringValue returns the value of a string literal, a file constant, or a
+// concatenation of them.
+func (r *tableReader) stringValue(expr ast.Expr) (string, bool) {
+	switch v := expr.(type) {
+	case *ast.BasicLit:
+		if v.Kind != token.STRING {
+			return "", false
+		}
+		s, err := strconv.Unquote(v.Value)
+		return s, err == nil
+	case *ast.Ident:
+		s, ok := r.consts[v.Name]
+		return s, ok
+	case *ast.ParenExpr:
+		return r.stringValue(v.X)
+	case *ast.BinaryExpr:
+		if v.Op != token.ADD {
+			return "", false
+		}
+		x, ok := r.stringValue(v.X)
+		if !ok {
+			return "", false
+		}
+		y, ok := r.stringValue(v.Y)
+		return x + y, ok
+	}
+	return "", false
+}
@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for embedded SQL extraction:
+// - String literals and constant concatenations that start like a statement are
+//   parsed, attributed to the enclosing function, on the line of each table
+// - SQL constants count at their declaration (no function) and where a function uses them
+// - squirrel Select/Insert/Update/Delete chains, on the package, its StatementBuilder
+//   or a variable holding one, with table names from literals or constants
+// - Prose, non-SQL strings and test files yield nothing
+
+func extractTestTableUsages(t *testing.T, relPath, source string) []string {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := NewExtractor(dir).ExtractCodeStructure(path)
+	require.NoError(t, err)
+
+	var out []string
+	for _, u := range result.TableUsages {
+		out = append(out, fmt.Sprintf("%s %s %s [%s] (%s) in %s @%d",
+			u.Access, u.Operation, u.Table, strings.Join(u.Columns, " "), u.Source, u.Function, u.Line))
+	}
+	return out
+}
+
+func TestExtractTableUsages_Literals(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestTableUsages(t, "storage/chunks.go", `package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const chunksTable = "chunks"
+
+const createChunksTable = `+"`"+`
+CREATE TABLE IF NOT EXISTS chunks (
+    chunk_id TEXT PRIMARY KEY,
+    file_path TEXT NOT NULL
+);`+"`"+`
+
+func CreateSchema(tx *sql.Tx) error {
+	_, err := tx.Exec(createChunksTable)
+	return err
+}
+
+func (r *Reader) Files(tx *sql.Tx, where string) error {
+	query := "SELECT DISTINCT file_path FROM " + chunksTable + " WHERE " + where
+	_, err := tx.Exec("DELETE FROM chunks WHERE file_path = ?", "x")
+	_, err = tx.Query(fmt.Sprintf("SELECT * FROM %s", chunksTable))
+	return fmt.Errorf("select a file from the list: %w", err)
+}
+`)
+
+	assert.Equal(t, []string{
+		"schema create chunks [chunk_id file_path] (sql) in  @11",
+		"schema create chunks [chunk_id file_path] (sql) in CreateSchema @17",
+		"read select chunks [file_path] (sql) in Reader.Files @22",
+		"write delete chunks [] (sql) in Reader.Files @23",
+	}, usages)
+}
+
+func TestExtractTableUsages_Squirrel(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestTableUsages(t, "storage/files.go", `package storage
+
+import sq "github.com/Masterminds/squirrel"
+
+const filesTable = "files"
+
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+func Load(tx *sql.Tx) {
+	sq.Select("f.file_path", "COUNT(c.chunk_id)").
+		From(filesTable + " f").
+		LeftJoin("chunks c ON c.file_path = f.file_path").
+		Where(sq.Eq{"f.language": "go"}).
+		RunWith(tx).Query()
+}
+
+func Save(tx *sql.Tx, path string) {
+	sq.Insert("files").Columns("file_path", "language").Values(path, "go").RunWith(tx).Exec()
+	psql.Update(filesTable).Set("indexed_at", now).SetMap(map[string]interface{}{"hash": h}).Exec()
+	q := sq.StatementBuilder.Delete("chunks").Where("file_path = ?", path)
+	_ = q
+}
+`)
+
+	assert.Equal(t, []string{
+		"read select files [file_path] (squirrel) in Load @10",
+		"read select chunks [chunk_id] (squirrel) in Load @10",
+		"write insert files [file_path language] (squirrel) in Save @18",
+		"write update files [indexed_at hash] (squirrel) in Save @19",
+		"write delete chunks [] (squirrel) in Save @20",
+	}, usages)
+}
+
+func TestExtractTableUsages_TestFiles(t *testing.T) {
+	t.Parallel()
+
+	usages := extractTestTableUsages(t, "storage/files_test.go", `package storage
+
+func setup(db *sql.DB) {
+	db.Exec("INSERT INTO files (file_path) VALUES ('a.go')")
+}
+`)
+	assert.Empty(t, usages)
+}
@@ -0,0 +1,457 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ErrNotInBuild is returned by CallResolver.ResolveFile for files the Go build
+// excludes (build constraints, ignored files). Keep their syntactic calls.
+var ErrNotInBuild = errors.New("file is excluded from the Go build")
+
+// maxDispatchTargets caps the implementations recorded for one interface call.
+// Beyond it (error.Error, fmt.Stringer.String) the edge set is noise; the call
+// is recorded once with the interface method as callee_name.
+const maxDispatchTargets = 32
+
+// CallResolver resolves Go call sites with go/types instead of call syntax.
+//
+// Packages inside the module (per go.mod) are parsed and type-checked from the
+// working tree; everything else goes through the go/importer source importer,
+// rooted at the module so module-aware lookup finds dependencies. Type errors
+// are tolerated: whatever the checker could resolve is used.
+//
+// Static callees resolve to the exact function ID. Interface method calls
+// resolve to every implementation among the module's named types, which
+// requires type-checking the whole module once per Reset.
+type CallResolver struct {
+	rootDir    string
+	modulePath string // From go.mod; empty if there is none
+	fset       *token.FileSet
+	external   types.ImporterFrom // Source importer; keeps its cache across Reset
+
+	packages map[packageKey]*typedPackage
+	loading  map[packageKey]bool
+	funcIDs  map[*types.Func]string // Module functions → graph function IDs
+	named    []*types.Named         // Module types, candidates for interface dispatch
+	loaded   bool                   // Whole module type-checked
+}
+
+// packageVariant selects which files of a directory make up a package.
+type packageVariant int
+
+const (
+	variantLib   packageVariant = iota // Non-test files (what importers see)
+	variantTest                        // Non-test files plus in-package _test.go files
+	variantXTest                       // External _test package
+)
+
+type packageKey struct {
+	dir     string
+	variant packageVariant
+}
+
+// typedPackage is a type-checked package and its parsed files.
+type typedPackage struct {
+	pkg   *types.Package
+	info  *types.Info
+	files map[string]*ast.File // Absolute path → file
+}
+
+// NewCallResolver creates a call resolver for the Go module at rootDir.
+func NewCallResolver(rootDir string) *CallResolver {
+	fset := token.NewFileSet()
+	r := &CallResolver{
+		rootDir:    rootDir,
+		modulePath: readModulePath(rootDir),
+		fset:       fset,
+		external:   importer.ForCompiler(fset, "source", nil).(types.ImporterFrom),
+	}
+	r.Reset()
+	return r
+}
+
+// Reset drops type-checked module packages so changed files are re-read.
+// Packages outside the module stay cached.
+func (r *CallResolver) Reset() {
+	r.packages = make(map[packageKey]*typedPackage)
+	r.loading = make(map[packageKey]bool)
+	r.funcIDs = make(map[*types.Func]string)
+	r.named = nil
+	r.loaded = false
+}
+
+// ResolveFile returns the calls made by the functions declared in the Go file
+// at absPath, in the same shape (IDs, caller IDs) as ExtractCodeStructure, with
+// Confidence set on every call.
+func (r *CallResolver) ResolveFile(absPath string) ([]FunctionCall, error) {
+	dir, base := filepath.Dir(absPath), filepath.Base(absPath)
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil && bp == nil {
+		return nil, fmt.Errorf("load package: %w", err)
+	}
+
+	var variant packageVariant
+	switch {
+	case slices.Contains(bp.GoFiles, base) || slices.Contains(bp.CgoFiles, base):
+		variant = variantLib
+	case slices.Contains(bp.TestGoFiles, base):
+		variant = variantTest
+	case slices.Contains(bp.XTestGoFiles, base):
+		variant = variantXTest
+	default:
+		return nil, ErrNotInBuild
+	}
+
+	tp, err := r.checkPackage(dir, variant)
+	if err != nil {
+		return nil, err
+	}
+	file := tp.files[absPath]
+	if file == nil {
+		return nil, ErrNotInBuild
+	}
+
+	relPath, err := filepath.Rel(r.rootDir, absPath)
+	if err != nil {
+		relPath = absPath
+	}
+
+	calls := []FunctionCall{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		callerID := fmt.Sprintf("%s::%s", relPath, fn.Name.Name)
+		if fn.Recv != nil && len(fn.Recv.List) > 0 {
+			callerID = fmt.Sprintf("%s::%s.%s", relPath, extractReceiverType(fn.Recv.List[0].Type), fn.Name.Name)
+		}
+
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+
+			name, confidence, targets := r.resolveCall(tp, call)
+			if name == "" {
+				return true
+			}
+
+			pos := r.fset.Position(call.Pos())
+			column := pos.Column
+			if len(targets) == 0 {
+				targets = []string{""}
+			}
+			for _, target := range targets {
+				call := FunctionCall{
+					ID:               fmt.Sprintf("%s::call%d", callerID, len(calls)),
+					CallerFunctionID: callerID,
+					CalleeName:       name,
+					SourceFilePath:   relPath,
+					CallLine:         pos.Line,
+					CallColumn:       &column,
+					Confidence:       confidence,
+				}
+				if target != "" {
+					calleeID := target
+					call.CalleeFunctionID = &calleeID
+				}
+				calls = append(calls, call)
+			}
+			return true
+		})
+	}
+	return calls, nil
+}
+
+// resolveCall returns the callee name, confidence and callee function IDs
+// (none for callees outside the module) of a call. An empty name means the
+// expression is not a function call: a conversion, builtin, or call of an
+// anonymous function.
+func (r *CallResolver) resolveCall(tp *typedPackage, call *ast.CallExpr) (string, string, []string) {
+	fun := ast.Unparen(call.Fun)
+	if tv, ok := tp.info.Types[fun]; ok && (tv.IsType() || tv.IsBuiltin()) {
+		return "", "", nil
+	}
+
+	// Strip explicit instantiation: Map[int, string](xs, f)
+	for {
+		switch f := fun.(type) {
+		case *ast.IndexExpr:
+			fun = f.X
+			continue
+		case *ast.IndexListExpr:
+			fun = f.X
+			continue
+		}
+		break
+	}
+
+	var obj types.Object
+	switch f := fun.(type) {
+	case *ast.Ident:
+		obj = tp.info.Uses[f]
+	case *ast.SelectorExpr:
+		if sel, ok := tp.info.Selections[f]; ok {
+			if sel.Kind() == types.FieldVal {
+				break // Func-typed field
+			}
+			obj = sel.Obj()
+		} else {
+			obj = tp.info.Uses[f.Sel] // Qualified identifier: pkg.Func
+		}
+	}
+
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		if _, builtin := obj.(*types.Builtin); builtin {
+			return "", "", nil
+		}
+		if _, typeName := obj.(*types.TypeName); typeName {
+			return "", "", nil
+		}
+		// Func value: variable, field, closure, call result
+		return extractCalleeID(call.Fun, tp.pkg.Name()), ConfidenceUnresolved, nil
+	}
+
+	fn = fn.Origin()
+	if recv := fn.Signature().Recv(); recv != nil && types.IsInterface(recv.Type()) {
+		iface, _ := recv.Type().Underlying().(*types.Interface)
+		return fn.FullName(), ConfidenceDynamic, r.implementations(iface, fn)
+	}
+
+	if id := r.funcIDs[fn]; id != "" {
+		return fn.FullName(), ConfidenceExact, []string{id}
+	}
+	return fn.FullName(), ConfidenceExact, nil
+}
+
+// implementations returns the IDs of the module methods an interface method
+// call can dispatch to, or none when there are more than maxDispatchTargets.
+func (r *CallResolver) implementations(iface *types.Interface, method *types.Func) []string {
+	if iface == nil {
+		return nil
+	}
+	r.loadModule()
+
+	seen := make(map[string]bool)
+	var ids []string
+	for _, named := range r.named {
+		for _, typ := range []types.Type{named, types.NewPointer(named)} {
+			if !types.Implements(typ, iface) {
+				continue
+			}
+			obj, _, _ := types.LookupFieldOrMethod(typ, false, method.Pkg(), method.Name())
+			if impl, ok := obj.(*types.Func); ok {
+				if id := r.funcIDs[impl.Origin()]; id != "" && !seen[id] {
+					seen[id] = true
+					ids = append(ids, id)
+				}
+			}
+			break
+		}
+	}
+
+	if len(ids) > maxDispatchTargets {
+		return nil
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// loadModule type-checks every package in the module, so interface calls see
+// all implementations. Nested modules, vendor and testdata are skipped.
+func (r *CallResolver) loadModule() {
+	if r.loaded {
+		return
+	}
+	r.loaded = true
+
+	_ = filepath.WalkDir(r.rootDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if path != r.rootDir {
+			name := d.Name()
+			if name == "vendor" || name == "testdata" || name == "node_modules" ||
+				strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_") {
+				return filepath.SkipDir
+			}
+			if _, err := os.Stat(filepath.Join(path, "go.mod")); err == nil {
+				return filepath.SkipDir
+			}
+		}
+		// Directories without Go files fail to load; nothing to record
+		_, _ = r.checkPackage(path, variantLib)
+		return nil
+	})
+}
+
+// checkPackage parses and type-checks the package variant in dir (cached).
+func (r *CallResolver) checkPackage(dir string, variant packageVariant) (*typedPackage, error) {
+	key := packageKey{dir: dir, variant: variant}
+	if tp, ok := r.packages[key]; ok {
+		return tp, nil
+	}
+	if r.loading[key] {
+		return nil, fmt.Errorf("import cycle through %s", dir)
+	}
+	r.loading[key] = true
+	defer delete(r.loading, key)
+
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, fmt.Errorf("load package %s: %w", dir, err)
+	}
+
+	var names []string
+	pkgPath := r.importPath(dir)
+	switch variant {
+	case variantLib:
+		names = append(append(names, bp.GoFiles...), bp.CgoFiles...)
+	case variantTest:
+		names = append(append(append(names, bp.GoFiles...), bp.CgoFiles...), bp.TestGoFiles...)
+	case variantXTest:
+		names = bp.XTestGoFiles
+		pkgPath += "_test"
+	}
+
+	tp := &typedPackage{
+		info: &types.Info{
+			Types:      make(map[ast.Expr]types.TypeAndValue),
+			Defs:       make(map[*ast.Ident]types.Object),
+			Uses:       make(map[*ast.Ident]types.Object),
+			Selections: make(map[*ast.SelectorExpr]*types.Selection),
+		},
+		files: make(map[string]*ast.File),
+	}
+	var files []*ast.File
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		file, err := parser.ParseFile(r.fset, path, nil, parser.SkipObjectResolution)
+		if file == nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		tp.files[path] = file
+		files = append(files, file)
+	}
+
+	conf := types.Config{
+		Importer:    r,
+		Error:       func(error) {}, // Tolerate type errors; keep what resolved
+		FakeImportC: true,
+	}
+	tp.pkg, _ = conf.Check(pkgPath, r.fset, files, tp.info)
+	r.packages[key] = tp
+
+	r.recordFunctions(tp)
+	if variant == variantLib {
+		r.recordNamedTypes(tp.pkg)
+	}
+	return tp, nil
+}
+
+// recordFunctions maps the package's function objects to graph function IDs,
+// built exactly as ExtractCodeStructure builds them.
+func (r *CallResolver) recordFunctions(tp *typedPackage) {
+	for path, file := range tp.files {
+		relPath, err := filepath.Rel(r.rootDir, path)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			obj, ok := tp.info.Defs[fn.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			id := fmt.Sprintf("%s::%s", relPath, fn.Name.Name)
+			if fn.Recv != nil && len(fn.Recv.List) > 0 {
+				id = fmt.Sprintf("%s::%s.%s", relPath, extractReceiverType(fn.Recv.List[0].Type), fn.Name.Name)
+			}
+			r.funcIDs[obj] = id
+		}
+	}
+}
+
+// recordNamedTypes collects the package's concrete, non-generic named types.
+func (r *CallResolver) recordNamedTypes(pkg *types.Package) {
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		obj, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || obj.IsAlias() {
+			continue
+		}
+		named, ok := obj.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 || types.IsInterface(named) {
+			continue
+		}
+		r.named = append(r.named, named)
+	}
+}
+
+// Import implements types.Importer.
+func (r *CallResolver) Import(path string) (*types.Package, error) {
+	return r.ImportFrom(path, r.rootDir, 0)
+}
+
+// ImportFrom implements types.ImporterFrom: module packages are type-checked
+// from the working tree, everything else by the source importer.
+func (r *CallResolver) ImportFrom(path, _ string, mode types.ImportMode) (*types.Package, error) {
+	if r.modulePath != "" && (path == r.modulePath || strings.HasPrefix(path, r.modulePath+"/")) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(path, r.modulePath), "/")
+		tp, err := r.checkPackage(filepath.Join(r.rootDir, filepath.FromSlash(rel)), variantLib)
+		if err != nil {
+			return nil, err
+		}
+		return tp.pkg, nil
+	}
+	return r.external.ImportFrom(path, r.rootDir, mode)
+}
+
+// importPath returns the import path of the package in dir.
+func (r *CallResolver) importPath(dir string) string {
+	rel, err := filepath.Rel(r.rootDir, dir)
+	if err != nil || rel == "." {
+		if r.modulePath == "" {
+			return "main"
+		}
+		return r.modulePath
+	}
+	if r.modulePath == "" {
+		return filepath.ToSlash(rel)
+	}
+	return r.modulePath + "/" + filepath.ToSlash(rel)
+}
+
+// readModulePath returns the module path declared in rootDir/go.mod, or "".
+func readModulePath(rootDir string) string {
+	data, err := os.ReadFile(filepath.Join(rootDir, "go.mod"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "module"); ok && rest != "" && (rest[0] == ' ' || rest[0] == '\t') {
+			return strings.Trim(strings.TrimSpace(rest), `"`)
+		}
+	}
+	return ""
+}
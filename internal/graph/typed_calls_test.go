@@ -0,0 +1,196 @@
+package graph
+
+// Test Plan for CallResolver:
+// - Static calls resolve to exact function IDs, across packages and through aliased imports
+// - Methods on concrete types (including promoted and generic ones) resolve exactly
+// - Interface method calls record one dynamic edge per implementation in the module
+// - Calls through func values are recorded as unresolved
+// - Builtins and conversions are skipped
+// - Calls outside the module keep their qualified name without a callee ID
+// - Test files type-check with their package; excluded files return ErrNotInBuild
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeModule writes files (relative path → source) under a temp module root.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	files["go.mod"] = "module example.com/shop\n\ngo 1.22\n"
+	for path, source := range files {
+		full := filepath.Join(root, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(source), 0644))
+	}
+	return root
+}
+
+// callEdges renders calls as "caller -> callee_id|callee_name (confidence)".
+func callEdges(calls []FunctionCall) []string {
+	edges := make([]string, 0, len(calls))
+	for _, call := range calls {
+		callee := call.CalleeName
+		if call.CalleeFunctionID != nil {
+			callee = *call.CalleeFunctionID
+		}
+		edges = append(edges, call.CallerFunctionID+" -> "+callee+" ("+call.Confidence+")")
+	}
+	return edges
+}
+
+func TestCallResolver_ResolveFile(t *testing.T) {
+	t.Parallel()
+
+	root := writeModule(t, map[string]string{
+		"store/store.go": `package store
+
+type Store interface {
+	Get(key string) string
+}
+
+type Memory struct{ base }
+
+func (m *Memory) Get(key string) string { return m.prefix() + key }
+
+type Disk struct{}
+
+func (Disk) Get(key string) string { return key }
+
+type base struct{}
+
+func (base) prefix() string { return "" }
+
+type List[T any] struct{ items []T }
+
+func (l *List[T]) Len() int { return len(l.items) }
+
+func New() Store { return &Memory{} }
+`,
+		"app/app.go": `package app
+
+import (
+	"strings"
+
+	st "example.com/shop/store"
+)
+
+type handler func(string) string
+
+func Run(s st.Store, h handler) string {
+	m := &st.Memory{}
+	m.Get("a")
+	s.Get("b")
+	h("c")
+	st.New()
+	var l st.List[int]
+	l.Len()
+	_ = len("x")
+	_ = handler(strings.ToUpper)
+	return strings.ToLower("D")
+}
+`,
+	})
+
+	resolver := NewCallResolver(root)
+	calls, err := resolver.ResolveFile(filepath.Join(root, "app/app.go"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"app/app.go::Run -> store/store.go::Memory.Get (exact)",
+		"app/app.go::Run -> store/store.go::Disk.Get (dynamic)",
+		"app/app.go::Run -> store/store.go::Memory.Get (dynamic)",
+		"app/app.go::Run -> app.h (unresolved)",
+		"app/app.go::Run -> store/store.go::New (exact)",
+		"app/app.go::Run -> store/store.go::List.Len (exact)",
+		"app/app.go::Run -> strings.ToLower (exact)",
+	}, callEdges(calls))
+
+	// Dynamic edges are named after the interface method
+	assert.Equal(t, "(example.com/shop/store.Store).Get", calls[1].CalleeName)
+
+	// Call IDs follow the syntactic extractor's format
+	assert.Equal(t, "app/app.go::Run::call0", calls[0].ID)
+	assert.Equal(t, 13, calls[0].CallLine)
+
+	// Promoted method through an embedded struct
+	calls, err = resolver.ResolveFile(filepath.Join(root, "store/store.go"))
+	require.NoError(t, err)
+	assert.Contains(t, callEdges(calls), "store/store.go::Memory.Get -> store/store.go::base.prefix (exact)")
+}
+
+func TestCallResolver_ResolveFile_TestFiles(t *testing.T) {
+	t.Parallel()
+
+	root := writeModule(t, map[string]string{
+		"calc/calc.go": `package calc
+
+func Add(a, b int) int { return a + b }
+`,
+		"calc/calc_test.go": `package calc
+
+func helper() int { return Add(1, 2) }
+`,
+		"calc/ext_test.go": `package calc_test
+
+import "example.com/shop/calc"
+
+func use() int { return calc.Add(3, 4) }
+`,
+		"calc/calc_windows.go": `//go:build ignore
+
+package calc
+
+func Sub(a, b int) int { return Add(a, -b) }
+`,
+	})
+
+	resolver := NewCallResolver(root)
+
+	calls, err := resolver.ResolveFile(filepath.Join(root, "calc/calc_test.go"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"calc/calc_test.go::helper -> calc/calc.go::Add (exact)"}, callEdges(calls))
+
+	calls, err = resolver.ResolveFile(filepath.Join(root, "calc/ext_test.go"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"calc/ext_test.go::use -> calc/calc.go::Add (exact)"}, callEdges(calls))
+
+	_, err = resolver.ResolveFile(filepath.Join(root, "calc/calc_windows.go"))
+	assert.True(t, errors.Is(err, ErrNotInBuild))
+}
+
+func TestCallResolver_Reset(t *testing.T) {
+	t.Parallel()
+
+	root := writeModule(t, map[string]string{
+		"svc/svc.go": `package svc
+
+type Greeter interface{ Greet() }
+
+func Call(g Greeter) { g.Greet() }
+`,
+	})
+
+	resolver := NewCallResolver(root)
+	calls, err := resolver.ResolveFile(filepath.Join(root, "svc/svc.go"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc/svc.go::Call -> (example.com/shop/svc.Greeter).Greet (dynamic)"}, callEdges(calls))
+
+	// A new implementation shows up after Reset
+	require.NoError(t, os.WriteFile(filepath.Join(root, "svc/english.go"), []byte(`package svc
+
+type English struct{}
+
+func (English) Greet() {}
+`), 0644))
+	resolver.Reset()
+	calls, err = resolver.ResolveFile(filepath.Join(root, "svc/svc.go"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"svc/svc.go::Call -> svc/english.go::English.Greet (dynamic)"}, callEdges(calls))
+}
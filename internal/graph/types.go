@@ -11,6 +11,10 @@ const (
 	NodeFunction  NodeKind = "function"
 	NodeMethod    NodeKind = "method"
 	NodePackage   NodeKind = "package"
+	NodeEndpoint  NodeKind = "endpoint"   // A route whose handler isn't an indexed function
+	NodeConfig    NodeKind = "config"     // A configuration read outside an indexed function
+	NodeConfigKey NodeKind = "config_key" // A .env template variable no code reads
+	NodeQuery     NodeKind = "query"      // A SQL statement outside an indexed function
 )
 
 // Node represents a code entity with its source location.
@@ -55,6 +59,8 @@ type EdgeType string
 const (
 	EdgeImplements EdgeType = "implements" // struct -> interface
 	EdgeEmbeds     EdgeType = "embeds"     // struct -> struct/interface (embedding)
+	EdgeExtends    EdgeType = "extends"    // class -> class, interface -> interface, trait -> supertrait
+	EdgeMixin      EdgeType = "mixin"      // class/trait -> used trait (PHP)
 	EdgeCalls      EdgeType = "calls"      // function -> function
 	EdgeImports    EdgeType = "imports"    // package -> package
 	EdgeUsesType   EdgeType = "uses_type"  // function/struct -> type (parameters, returns, fields)
@@ -108,6 +114,9 @@ type CodeStructure struct {
 	FunctionParams []FunctionParameter  // Maps to function_parameters table
 	FunctionCalls  []FunctionCall       // Maps to function_calls table
 	Imports        []Import             // Maps to imports table
+	Endpoints      []Endpoint           // Maps to endpoints table
+	ConfigUsages   []ConfigUsage        // Maps to config_usages table
+	TableUsages    []TableUsage         // Maps to table_usages table
 }
 
 // Domain model structs (schema-aligned)
@@ -127,6 +136,7 @@ type Type struct {
 	IsExported  bool        // is_exported: uppercase first letter (Go)
 	FieldCount  int         // field_count: denormalized count
 	MethodCount int         // method_count: denormalized count
+	Doc         string      // doc: doc comment, markers stripped
 	Fields      []TypeField // Joined: type fields (is_method=0)
 	Methods     []TypeField // Joined: type methods (is_method=1)
 }
@@ -162,6 +172,7 @@ type Function struct {
 	ParamCount           int                  // param_count: number of parameters
 	ReturnCount          int                  // return_count: number of return values
 	CyclomaticComplexity *int                 // cyclomatic_complexity: optional metric (nullable)
+	Doc                  string               // doc: doc comment, markers stripped
 	Parameters           []FunctionParameter  // Joined: function parameters (is_return=0)
 	ReturnValues         []FunctionParameter  // Joined: return values (is_return=1)
 }
@@ -188,6 +199,64 @@ type Import struct {
 	ImportLine    int    // import_line: line number
 }
 
+// Endpoint represents an HTTP route or RPC procedure registered in code.
+type Endpoint struct {
+	FilePath     string // file_path: where the route is registered
+	Line         int    // line: line of the registration
+	Framework    string // framework: net/http, chi, gin, echo, connect, grpc, express, flask, spring, ...
+	Kind         string // kind: http or rpc
+	Method       string // method: GET, POST, ...; ANY when every method is accepted
+	Path         string // path: route pattern with group prefixes applied, or /package.Service/Method
+	Handler      string // handler: handler as written (empty for inline handlers)
+	HandlerName  string // handler_name: function or method name the handler resolves by
+	HandlerScope string // handler_scope: receiver, class, package qualifier or RPC service interface
+}
+
+// Endpoint kinds
+const (
+	EndpointHTTP = "http"
+	EndpointRPC  = "rpc"
+)
+
+// ConfigUsage represents a read of an environment variable or configuration
+// key in code.
+type ConfigUsage struct {
+	FilePath   string // file_path: where the key is read
+	Line       int    // line: line of the read
+	Kind       string // kind: env or config
+	Key        string // key: DATABASE_URL, server.port
+	Source     string // source: API the key is read through: os.Getenv, viper, process.env, ENV, ...
+	Function   string // function: enclosing function (Recv.Name, Class.method), type for struct tags; empty at top level
+	FunctionID string // function_id: enclosing Go function; empty elsewhere
+}
+
+// Config usage kinds
+const (
+	ConfigKindEnv = "env"    // Environment variable
+	ConfigKindKey = "config" // Key of a configuration file or settings store (viper, Spring, node-config)
+)
+
+// TableUsage represents a database table read, written or defined by SQL
+// embedded in code: a string literal or a query builder chain.
+type TableUsage struct {
+	FilePath   string   // file_path: where the statement is
+	Line       int      // line: line of the table in the statement
+	Table      string   // table_name: as written, schema-qualified if it is
+	Access     string   // access: read, write or schema
+	Operation  string   // operation: select, insert, update, delete, create, alter, drop, ...
+	Columns    []string // columns: columns selected, inserted, set or defined, when known
+	Source     string   // source: sql (string literal) or the query builder (squirrel, knex, ...)
+	Function   string   // function: enclosing function (Recv.Name, Class.method); empty at top level
+	FunctionID string   // function_id: enclosing Go function; empty elsewhere
+}
+
+// Table access kinds
+const (
+	TableAccessRead   = "read"
+	TableAccessWrite  = "write"
+	TableAccessSchema = "schema" // DDL: CREATE, ALTER, DROP
+)
+
 // FunctionCall represents a function call relationship.
 type FunctionCall struct {
 	ID               string  // call_id: UUID
@@ -197,4 +266,13 @@ type FunctionCall struct {
 	SourceFilePath   string  // source_file_path: where call occurs
 	CallLine         int     // call_line: line number
 	CallColumn       *int    // call_column: optional column number (nullable)
+	Confidence       string  // How the callee was resolved; empty for syntactic extraction
 }
+
+// Call confidence levels, from most to least trustworthy.
+const (
+	ConfidenceExact      = "exact"      // Static callee resolved by the type checker
+	ConfidenceDynamic    = "dynamic"    // Interface method call; one edge per possible implementation
+	ConfidenceUnresolved = "unresolved" // Call through a func value (variable, field, closure)
+	ConfidenceSyntactic  = "syntactic"  // Callee name guessed from call syntax
+)
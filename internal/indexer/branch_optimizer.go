@@ -100,7 +100,7 @@ func (bo *BranchOptimizer) CopyUnchangedChunks(currentFiles map[string]FileInfo)
 
 	// Open current branch database (read-write)
 	currentDBPath := filepath.Join(bo.cachePath, "branches", fmt.Sprintf("%s.db", bo.currentBranch))
-	currentDB, err := sql.Open("sqlite3", currentDBPath)
+	currentDB, err := sql.Open("sqlite3", currentDBPath+"?"+storage.WriterDSNParams)
 	if err != nil {
 		return 0, nil, fmt.Errorf("failed to open current database: %w", err)
 	}
@@ -121,6 +121,8 @@ func (bo *BranchOptimizer) CopyUnchangedChunks(currentFiles map[string]FileInfo)
 		if err := storage.CreateSchema(currentDB); err != nil {
 			return 0, nil, fmt.Errorf("failed to create schema: %w", err)
 		}
+	} else if err := storage.MigrateSchema(currentDB); err != nil {
+		return 0, nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	// Load file hashes from ancestor database
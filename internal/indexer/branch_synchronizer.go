@@ -166,7 +166,7 @@ func (bs *branchSynchronizer) createDatabaseWithSchema(dbPath string) (*sql.DB,
 	}
 
 	// Create database
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+"?"+storage.WriterDSNParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create database: %w", err)
 	}
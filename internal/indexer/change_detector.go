@@ -80,14 +80,19 @@ func (cd *changeDetector) DetectChanges(ctx context.Context, hint []string) (*Ch
 	}
 
 	// Convert absolute paths to relative for DB lookup
+	// (content source files to their "@<label>/" paths)
+	roots := cd.roots()
 	relativeFiles := make([]string, 0, len(filesToCheck))
 	for _, file := range filesToCheck {
 		var relPath string
 		if filepath.IsAbs(file) {
-			rel, err := filepath.Rel(cd.rootDir, file)
+			rel, ok, err := roots.filePath(file)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get relative path for %s: %w", file, err)
 			}
+			if !ok {
+				continue // Excluded by its content source's patterns
+			}
 			relPath = rel
 		} else {
 			relPath = file
@@ -117,7 +122,7 @@ func (cd *changeDetector) DetectChanges(ctx context.Context, hint []string) (*Ch
 		default:
 		}
 
-		absPath := filepath.Join(cd.rootDir, relPath)
+		absPath := roots.absPath(relPath)
 
 		// Check if file exists on disk
 		fileInfo, err := os.Stat(absPath)
@@ -179,6 +184,15 @@ func (cd *changeDetector) DetectChanges(ctx context.Context, hint []string) (*Ch
 	return changes, nil
 }
 
+// roots returns the path mapping of the project and its content sources.
+func (cd *changeDetector) roots() contentSourceRoots {
+	roots := contentSourceRoots{rootDir: cd.rootDir}
+	if cd.discovery != nil {
+		roots.sources = cd.discovery.sources
+	}
+	return roots
+}
+
 // calculateHashForFile calculates SHA-256 hash of a file.
 func calculateHashForFile(path string) (string, error) {
 	data, err := os.ReadFile(path)
@@ -0,0 +1,528 @@
+package indexer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"github.com/mvp-joe/project-cortex/internal/languages"
+	"github.com/pelletier/go-toml/v2/unstable"
+	"go.yaml.in/yaml/v3"
+)
+
+// Configuration file languages. Their extractor flattens a file into key
+// paths with values ("spec.template.spec.containers[0].image"), returned as
+// constants (one data chunk per top-level section, or per document in
+// multi-document YAML) and as the config keys of the graph data.
+const (
+	configYAML   = languages.YAML
+	configJSON   = languages.JSON
+	configTOML   = languages.TOML
+	configDotenv = languages.Dotenv
+)
+
+// configLanguages are the languages extracted by configExtractor.
+var configLanguages = map[string]bool{configYAML: true, configJSON: true, configTOML: true, configDotenv: true}
+
+// dotenvFilenames are the names .env files are recognized by. Only
+// templates (.env.example and the like) are discovered by default.
+var dotenvFilenames = []string{".env", ".env.*", "*.env"}
+
+const (
+	maxConfigFileSize = 256 << 10 // Larger files are data rather than configuration: only searchable as text
+	maxConfigKeys     = 5000      // Keys extracted per file; the rest is only searchable as text
+	maxConfigValueLen = 200       // Longer values are truncated
+	maskedConfigValue = "****"
+)
+
+// configExtractor extracts the key paths of YAML, JSON, TOML and .env
+// files. Secret-looking values are masked. Files that don't parse (e.g.
+// Helm templates), files over maxConfigFileSize and compilation databases
+// (compile_commands.json, read for C/C++ include paths) are not extracted.
+type configExtractor struct {
+	language string
+}
+
+// ParseFile implements LanguageExtractor.
+func (e configExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	if filepath.Base(filePath) == "compile_commands.json" {
+		return nil, nil
+	}
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > maxConfigFileSize {
+		return nil, nil
+	}
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &configWalker{}
+	switch e.language {
+	case configYAML, configJSON:
+		err = w.yamlDocuments(content)
+	case configTOML:
+		err = w.tomlDocument(content)
+	case configDotenv:
+		w.dotenvDocument(content)
+	default:
+		return nil, fmt.Errorf("unsupported configuration language %s", e.language)
+	}
+	if err != nil {
+		return nil, nil // Not valid YAML, JSON or TOML
+	}
+
+	lines := splitLines(content)
+	return &CodeExtraction{
+		Language:  e.language,
+		FilePath:  filePath,
+		StartLine: 1,
+		EndLine:   len(lines),
+		Symbols: &extraction.SymbolsData{
+			Types:     []extraction.SymbolInfo{},
+			Functions: []extraction.SymbolInfo{},
+		},
+		Definitions: &extraction.DefinitionsData{
+			Definitions: []extraction.Definition{},
+		},
+		Data: &extraction.DataData{
+			Constants: w.constants,
+			Variables: []extraction.VariableInfo{},
+		},
+		Graph: &ExtractedGraph{ConfigKeys: w.keys},
+	}, nil
+}
+
+// configWalker collects the flattened keys of a configuration file.
+type configWalker struct {
+	constants []extraction.ConstantInfo
+	keys      []ExtractedConfigKey
+	document  int // Index of the current YAML document
+}
+
+// add records a key path with its value, masking secrets.
+func (w *configWalker) add(path, value, typ string, line int, section, doc string) {
+	if path == "" || len(w.keys) >= maxConfigKeys {
+		return
+	}
+	value = truncateConfigValue(maskConfigValue(path, value))
+	w.keys = append(w.keys, ExtractedConfigKey{Document: w.document, Path: path, Value: value, Line: line})
+	w.constants = append(w.constants, extraction.ConstantInfo{
+		Name: path, Value: value, Type: typ, StartLine: line, EndLine: line, Doc: doc, Section: section,
+	})
+}
+
+// yamlDocuments flattens the documents of a YAML (or JSON) file. The
+// top-level keys of a single document that hold mappings or sequences are
+// its sections; in multi-document files (Kubernetes manifests) each
+// document is a section, named by its kind and metadata.name when it has
+// them.
+func (w *configWalker) yamlDocuments(content []byte) error {
+	var docs []*yaml.Node
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	for {
+		var doc yaml.Node
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		docs = append(docs, &doc)
+	}
+
+	for i, doc := range docs {
+		if len(doc.Content) == 0 {
+			continue
+		}
+		root := doc.Content[0]
+		w.document = i
+		section := ""
+		if len(docs) > 1 {
+			section = fmt.Sprintf("document %d", i)
+			if kind := yamlString(root, "kind"); kind != "" {
+				section = kind
+				if name := yamlString(yamlValue(root, "metadata"), "name"); name != "" {
+					section += "/" + name
+				}
+			}
+		}
+		w.yamlNode(root, "", root.Line, section, "", 0)
+	}
+	return nil
+}
+
+// yamlNode flattens node under path. Sequence items are indexed, merge
+// keys ("<<") and aliases are followed, and empty mappings and sequences are
+// recorded as "{}" and "[]".
+func (w *configWalker) yamlNode(node *yaml.Node, path string, line int, section, doc string, depth int) {
+	if depth > 64 {
+		return // Alias cycles
+	}
+	switch node.Kind {
+	case yaml.MappingNode:
+		if len(node.Content) == 0 {
+			w.add(path, "{}", "map", line, section, doc)
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "<<" {
+				w.yamlNode(value, path, key.Line, section, doc, depth+1)
+				continue
+			}
+			childPath := joinConfigKey(path, key.Value)
+			childSection := section
+			if path == "" && section == "" && isYAMLCollection(value) {
+				childSection = childPath
+			}
+			w.yamlNode(value, childPath, key.Line, childSection, hashCommentDoc(key.HeadComment), depth+1)
+		}
+	case yaml.SequenceNode:
+		if len(node.Content) == 0 {
+			w.add(path, "[]", "seq", line, section, doc)
+		}
+		for i, item := range node.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			itemSection := section
+			if path == "" && section == "" && isYAMLCollection(item) {
+				itemSection = itemPath
+			}
+			w.yamlNode(item, itemPath, item.Line, itemSection, hashCommentDoc(item.HeadComment), depth+1)
+		}
+	case yaml.AliasNode:
+		if node.Alias != nil {
+			w.yamlNode(node.Alias, path, line, section, doc, depth+1)
+		}
+	case yaml.ScalarNode:
+		w.add(path, node.Value, strings.TrimPrefix(node.ShortTag(), "!!"), line, section, doc)
+	}
+}
+
+// isYAMLCollection reports whether a node is a mapping or sequence
+// (following aliases).
+func isYAMLCollection(node *yaml.Node) bool {
+	if node.Kind == yaml.AliasNode && node.Alias != nil {
+		node = node.Alias
+	}
+	return node.Kind == yaml.MappingNode || node.Kind == yaml.SequenceNode
+}
+
+// tomlDocument flattens a TOML file. Tables are sections, named by their
+// first key; the keys before the first table have no section.
+// Arrays of tables are indexed like sequences ("servers[1].port").
+func (w *configWalker) tomlDocument(content []byte) error {
+	p := &unstable.Parser{KeepComments: true}
+	p.Reset(content)
+
+	var (
+		table       []string           // Key parts of the current table
+		tablePath   string             // Flattened path of the current table
+		arrayIndex  = map[string]int{} // Last index of each array of tables
+		comments    []string
+		commentLine int
+	)
+	line := func(n *unstable.Node) int {
+		if n == nil || n.Raw.Length == 0 {
+			return 0
+		}
+		return p.Shape(n.Raw).Start.Line
+	}
+	// flatten builds the path of a table key, indexing arrays of tables
+	flatten := func(parts []string) string {
+		path := ""
+		for i, part := range parts {
+			path = joinConfigKey(path, part)
+			if index, ok := arrayIndex[strings.Join(parts[:i+1], "\x00")]; ok {
+				path = fmt.Sprintf("%s[%d]", path, index)
+			}
+		}
+		return path
+	}
+
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Comment:
+			n := line(expr)
+			if n != commentLine+1 {
+				comments = nil
+			}
+			comments = append(comments, string(expr.Data))
+			commentLine = n
+		case unstable.Table, unstable.ArrayTable:
+			table = tomlKeyParts(expr.Key())
+			if expr.Kind == unstable.ArrayTable {
+				key := strings.Join(table, "\x00")
+				if index, ok := arrayIndex[key]; ok {
+					arrayIndex[key] = index + 1
+				} else {
+					arrayIndex[key] = 0
+				}
+			}
+			tablePath = flatten(table)
+			comments = nil
+		case unstable.KeyValue:
+			keyIt := expr.Key()
+			keyIt.Next()
+			n := line(keyIt.Node())
+			doc := ""
+			if len(comments) > 0 && commentLine == n-1 {
+				doc = hashCommentDoc(strings.Join(comments, "\n"))
+			}
+			comments = nil
+
+			path := tablePath
+			for _, part := range tomlKeyParts(expr.Key()) {
+				path = joinConfigKey(path, part)
+			}
+			section := ""
+			if len(table) > 0 {
+				section = joinConfigKey("", table[0])
+			}
+			w.tomlValue(expr.Value(), path, n, section, doc, line)
+		}
+	}
+	return p.Error()
+}
+
+// tomlValue flattens a TOML value under path.
+func (w *configWalker) tomlValue(node *unstable.Node, path string, line int, section, doc string, nodeLine func(*unstable.Node) int) {
+	switch node.Kind {
+	case unstable.Array:
+		it := node.Children()
+		i := 0
+		for it.Next() {
+			item := it.Node()
+			itemLine := line
+			if n := nodeLine(item); n > 0 {
+				itemLine = n
+			}
+			w.tomlValue(item, fmt.Sprintf("%s[%d]", path, i), itemLine, section, doc, nodeLine)
+			i++
+		}
+		if i == 0 {
+			w.add(path, "[]", "seq", line, section, doc)
+		}
+	case unstable.InlineTable:
+		it := node.Children()
+		empty := true
+		for it.Next() {
+			kv := it.Node()
+			childPath := path
+			for _, part := range tomlKeyParts(kv.Key()) {
+				childPath = joinConfigKey(childPath, part)
+			}
+			w.tomlValue(kv.Value(), childPath, line, section, doc, nodeLine)
+			empty = false
+		}
+		if empty {
+			w.add(path, "{}", "map", line, section, doc)
+		}
+	case unstable.String:
+		w.add(path, string(node.Data), "str", line, section, doc)
+	case unstable.Bool:
+		w.add(path, string(node.Data), "bool", line, section, doc)
+	case unstable.Integer:
+		w.add(path, string(node.Data), "int", line, section, doc)
+	case unstable.Float:
+		w.add(path, string(node.Data), "float", line, section, doc)
+	default: // Dates and times
+		w.add(path, string(node.Data), "timestamp", line, section, doc)
+	}
+}
+
+// tomlKeyParts returns the parts of a (possibly dotted) TOML key.
+func tomlKeyParts(it unstable.Iterator) []string {
+	var parts []string
+	for it.Next() {
+		parts = append(parts, string(it.Node().Data))
+	}
+	return parts
+}
+
+// dotenvVarPattern matches the variable names of .env files.
+var dotenvVarPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// dotenvDocument reads the KEY=value lines of a .env file ("export KEY=value"
+// too). Comment lines directly above a variable document it. Lines that
+// aren't assignments are skipped.
+func (w *configWalker) dotenvDocument(content []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var comments []string
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			comments = append(comments, line)
+			continue
+		}
+		doc := hashCommentDoc(strings.Join(comments, "\n"))
+		comments = nil
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		key = strings.TrimSpace(key)
+		if !ok || !dotenvVarPattern.MatchString(key) {
+			continue
+		}
+		w.add(key, dotenvValue(strings.TrimSpace(value)), "str", n, "", doc)
+	}
+}
+
+// dotenvValue unquotes a .env value or strips its trailing comment.
+func dotenvValue(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return value[1 : end+1]
+		}
+	}
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = value[:i]
+	}
+	return strings.TrimSpace(value)
+}
+
+// joinConfigKey appends a key to a path. Keys that can't be written plainly
+// in a dotted path are quoted: metadata.labels["app.kubernetes.io/name"].
+func joinConfigKey(path, key string) string {
+	if key == "" || strings.ContainsAny(key, ".[]\"' \t\n") {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// hashCommentDoc strips the "#" markers of a comment block.
+func hashCommentDoc(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	lines := strings.Split(comment, "\n")
+	for i, l := range lines {
+		l = strings.TrimLeft(strings.TrimSpace(l), "#")
+		lines[i] = strings.TrimPrefix(l, " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// truncateConfigValue cuts values longer than maxConfigValueLen bytes (at a
+// rune boundary).
+func truncateConfigValue(value string) string {
+	if len(value) <= maxConfigValueLen {
+		return value
+	}
+	cut := maxConfigValueLen
+	for cut > 0 && !utf8.RuneStart(value[cut]) {
+		cut--
+	}
+	return value[:cut] + "..."
+}
+
+// secretKeyWords are the last words of key names whose values are secrets
+// ("password", "db_password", "apiToken"). "key" only counts after a word
+// of secretKeyQualifiers ("api_key", "privateKey").
+var (
+	secretKeyWords      = map[string]bool{"password": true, "passwd": true, "pwd": true, "secret": true, "token": true, "apikey": true, "credential": true, "credentials": true, "passphrase": true}
+	secretKeyQualifiers = map[string]bool{"api": true, "access": true, "private": true, "secret": true, "signing": true, "encryption": true, "master": true}
+)
+
+// configSecrets finds values that are secrets whatever their key: private
+// keys, cloud and service tokens, JWTs. Custom patterns apply when the keys
+// are written to the graph (see GraphUpdater.WithSecretScanner).
+var configSecrets = &SecretScanner{detectors: append(append([]secretDetector{}, builtinSecretDetectors...), genericSecretDetectors...)}
+
+// urlCredentialsPattern matches the password of a URL with credentials.
+var urlCredentialsPattern = regexp.MustCompile(`(://[^/:@\s]+:)([^@\s]+)(@)`)
+
+// maskConfigValue masks the value of a key path if the key names a secret
+// or the value looks like one. References to secrets kept elsewhere
+// ("${DB_PASSWORD}", "{{ .Values.token }}") and empty values are kept, and
+// only the password of a URL with credentials is masked.
+func maskConfigValue(path, value string) string {
+	if value == "" || isConfigReference(value) {
+		return value
+	}
+	if isSecretKey(path) {
+		return maskedConfigValue
+	}
+	value = urlCredentialsPattern.ReplaceAllString(value, "${1}"+maskedConfigValue+"${3}")
+	if configSecrets.contains(value) {
+		return maskedConfigValue
+	}
+	return value
+}
+
+// isConfigReference reports whether a value refers to a variable or
+// template value instead of holding one.
+func isConfigReference(value string) bool {
+	for _, prefix := range []string{"${", "$(", "{{", "%(", "#{"} {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return len(value) > 1 && value[0] == '$' && dotenvVarPattern.MatchString(value[1:])
+}
+
+// isSecretKey reports whether the last key of a path names a secret.
+func isSecretKey(path string) bool {
+	key := path
+	if i := strings.LastIndexAny(key, ".["); i >= 0 {
+		key = key[i+1:]
+	}
+	key = strings.Trim(key, `"]`)
+
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return false
+	}
+	last := words[len(words)-1]
+	if secretKeyWords[last] {
+		return true
+	}
+	return last == "key" && len(words) > 1 && secretKeyQualifiers[words[len(words)-2]]
+}
+
+// splitKeyWords splits a key into lowercase words at separators and
+// camelCase boundaries: "apiToken" and "API_TOKEN" are "api", "token".
+func splitKeyWords(key string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	runes := []rune(key)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+			continue
+		case unicode.IsUpper(r) && len(word) > 0:
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				flush()
+			}
+		}
+		word = append(word, r)
+	}
+	flush()
+	return words
+}
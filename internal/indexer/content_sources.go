@@ -0,0 +1,203 @@
+package indexer
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// ContentSourceConfig configures a directory outside the project root to
+// index alongside it (see config.SourceConfig).
+type ContentSourceConfig struct {
+	Label   string
+	Root    string   // Absolute directory
+	Include []string // Glob patterns to index, relative to Root
+	Ignore  []string // Glob patterns to skip, relative to Root
+	Watch   bool     // Re-index on change while the daemon runs
+}
+
+// contentSource is a configured content source with its compiled patterns.
+type contentSource struct {
+	storage.ContentSource
+	discovery *FileDiscovery
+}
+
+// ContentSources maps the files of content sources between disk and the
+// paths they are stored under ("@<label>/<path relative to the root>").
+// A nil *ContentSources has no sources.
+type ContentSources struct {
+	sources []*contentSource
+}
+
+// NewContentSources compiles the configured content sources of the project
+// at projectRoot. A source root may neither lie inside the project nor
+// contain it, since its files would be indexed twice.
+func NewContentSources(projectRoot string, configs []ContentSourceConfig) (*ContentSources, error) {
+	projectRoot, err := filepath.Abs(projectRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve project root: %w", err)
+	}
+
+	cs := &ContentSources{}
+	for _, cfg := range configs {
+		if !storage.ValidContentSourceLabel(cfg.Label) {
+			return nil, fmt.Errorf("invalid content source label %q", cfg.Label)
+		}
+		root, err := filepath.Abs(cfg.Root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve root of content source %s: %w", cfg.Label, err)
+		}
+		if isWithin(root, projectRoot) || isWithin(projectRoot, root) {
+			return nil, fmt.Errorf("content source %s: root %s overlaps the project root", cfg.Label, root)
+		}
+
+		ignore := append([]string{".git/**"}, cfg.Ignore...)
+		discovery, err := NewFileDiscovery(root, cfg.Include, nil, ignore)
+		if err != nil {
+			return nil, fmt.Errorf("content source %s: %w", cfg.Label, err)
+		}
+		cs.sources = append(cs.sources, &contentSource{
+			ContentSource: storage.ContentSource{Label: cfg.Label, Root: root, Watch: cfg.Watch},
+			discovery:     discovery,
+		})
+	}
+	return cs, nil
+}
+
+// Records returns the sources as recorded in the database.
+func (cs *ContentSources) Records() []storage.ContentSource {
+	if cs == nil {
+		return nil
+	}
+	records := make([]storage.ContentSource, len(cs.sources))
+	for i, src := range cs.sources {
+		records[i] = src.ContentSource
+	}
+	return records
+}
+
+// WatchDirs returns the roots of the sources the daemon should watch.
+func (cs *ContentSources) WatchDirs() []string {
+	if cs == nil {
+		return nil
+	}
+	var dirs []string
+	for _, src := range cs.sources {
+		if src.Watch && isDir(src.Root) {
+			dirs = append(dirs, src.Root)
+		}
+	}
+	return dirs
+}
+
+// DiscoverFiles returns the absolute paths of every source's files. Sources
+// whose root doesn't exist (e.g., a checkout missing on this machine) are
+// skipped with a warning, so their files are removed from the index.
+func (cs *ContentSources) DiscoverFiles() ([]string, error) {
+	if cs == nil {
+		return nil, nil
+	}
+	var files []string
+	for _, src := range cs.sources {
+		if !isDir(src.Root) {
+			log.Printf("Warning: content source %s: root %s not found, skipping\n", src.Label, src.Root)
+			continue
+		}
+		codeFiles, _, err := src.discovery.DiscoverFiles()
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover files of content source %s: %w", src.Label, err)
+		}
+		files = append(files, codeFiles...)
+	}
+	return files, nil
+}
+
+// FilePath returns the stored path of absPath. inSource reports whether
+// absPath lies under a source root; filePath is empty if it does but the
+// source's patterns exclude it.
+func (cs *ContentSources) FilePath(absPath string) (filePath string, inSource bool) {
+	if cs == nil {
+		return "", false
+	}
+	for _, src := range cs.sources {
+		if !isWithin(absPath, src.Root) {
+			continue
+		}
+		rel, err := filepath.Rel(src.Root, absPath)
+		if err != nil {
+			return "", true
+		}
+		rel = filepath.ToSlash(rel)
+		if src.discovery.shouldIgnore(rel) || !src.discovery.matchesAnyPattern(rel, src.discovery.codePatterns) {
+			return "", true
+		}
+		return storage.ContentSourcePrefix(src.Label) + rel, true
+	}
+	return "", false
+}
+
+// AbsPath returns the file on disk of a stored content source path. ok is
+// false for project files and files of sources no longer configured.
+func (cs *ContentSources) AbsPath(filePath string) (absPath string, ok bool) {
+	if cs == nil || !storage.IsContentSourcePath(filePath) {
+		return "", false
+	}
+	label := storage.ContentSourceLabel(filePath)
+	root, ok := cs.root(label)
+	if !ok {
+		return "", false
+	}
+	rel := strings.TrimPrefix(filePath, storage.ContentSourcePrefix(label))
+	return filepath.Join(root, filepath.FromSlash(rel)), true
+}
+
+// root returns the root directory of the source labelled label.
+func (cs *ContentSources) root(label string) (string, bool) {
+	if cs == nil {
+		return "", false
+	}
+	for _, src := range cs.sources {
+		if src.Label == label {
+			return src.Root, true
+		}
+	}
+	return "", false
+}
+
+// isWithin reports whether path is dir or lies under it.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// contentSourceRoots maps stored paths to files on disk for the processor
+// and change detector: content source paths resolve through sources,
+// everything else relative to rootDir.
+type contentSourceRoots struct {
+	rootDir string
+	sources *ContentSources
+}
+
+// filePath returns the stored path of absPath (see ContentSources.FilePath).
+// ok is false for files a content source excludes.
+func (r contentSourceRoots) filePath(absPath string) (filePath string, ok bool, err error) {
+	if filePath, inSource := r.sources.FilePath(absPath); inSource {
+		return filePath, filePath != "", nil
+	}
+	rel, err := filepath.Rel(r.rootDir, absPath)
+	if err != nil {
+		return "", false, err
+	}
+	return rel, true, nil
+}
+
+// absPath returns the file on disk of a stored path.
+func (r contentSourceRoots) absPath(filePath string) string {
+	if abs, ok := r.sources.AbsPath(filePath); ok {
+		return abs
+	}
+	return filepath.Join(r.rootDir, filePath)
+}
@@ -0,0 +1,171 @@
+package indexer
+
+// Test Plan for Content Sources:
+// - A full index stores source files as "@<label>/<path>" next to the project's files
+// - Include and ignore patterns apply relative to the source root
+// - The sources are recorded in content_sources
+// - Source code joins the graph under its "@<label>/" paths
+// - A watcher hint under a source root re-indexes the file; excluded files are skipped
+// - Files of a source whose root disappears are deleted on the next full index
+// - Roots overlapping the project and invalid labels are rejected
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexerV2_ContentSources(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rootDir := t.TempDir()
+	wikiDir := t.TempDir()
+	createTestGoFile(t, rootDir, "main.go", "package main\n\nfunc main() {}\n")
+	createTestMarkdownFile(t, wikiDir, "onboarding.md", "# Onboarding\n\nRun make setup first.\n")
+	createTestMarkdownFile(t, wikiDir, "drafts/todo.md", "# Todo\n\nNot ready.\n")
+	createTestGoFile(t, wikiDir, "tools/gen.go", "package tools\n")
+
+	sources, err := NewContentSources(rootDir, []ContentSourceConfig{{
+		Label:   "wiki",
+		Root:    wikiDir,
+		Include: []string{"**/*.md"},
+		Ignore:  []string{"drafts/**"},
+		Watch:   true,
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{wikiDir}, sources.WatchDirs())
+
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupIntegrationTestStorage(t, db, rootDir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	discovery, err := NewFileDiscovery(rootDir, []string{"**/*.go"}, []string{"**/*.md"}, nil)
+	require.NoError(t, err)
+	discovery.WithContentSources(sources)
+	processor := NewProcessor(rootDir, NewParser(), NewChunker(500, 50), NewFormatter(), &mockEmbedProvider{}, storage,
+		&NoOpProgressReporter{}, WithContentSourcePaths(sources))
+	idx := NewIndexerV2(rootDir, NewChangeDetector(rootDir, storage, discovery), processor, storage, db,
+		WithContentSources(sources))
+
+	indexedFiles := func() []string {
+		files, err := storagepkg.NewFileReader(db).GetAllFiles()
+		require.NoError(t, err)
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.FilePath)
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	stats, err := idx.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.FilesAdded)
+	assert.Equal(t, 1, stats.DocsProcessed)
+	assert.Equal(t, []string{"@wiki/onboarding.md", "main.go"}, indexedFiles())
+
+	var chunks int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM chunks WHERE file_path = '@wiki/onboarding.md'").Scan(&chunks))
+	assert.Positive(t, chunks)
+
+	recorded, err := storagepkg.ListContentSources(db)
+	require.NoError(t, err)
+	assert.Equal(t, []storagepkg.ContentSource{{Label: "wiki", Root: wikiDir, Watch: true}}, recorded)
+
+	// Watcher hints: a changed source file is re-indexed, an ignored one skipped
+	require.NoError(t, os.WriteFile(filepath.Join(wikiDir, "onboarding.md"), []byte("# Onboarding\n\nRun make bootstrap first.\n"), 0644))
+	stats, err = idx.Index(ctx, []string{filepath.Join(wikiDir, "onboarding.md"), filepath.Join(wikiDir, "drafts", "todo.md")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FilesModified)
+	assert.Equal(t, 0, stats.FilesAdded)
+
+	// The root disappears: its files are removed
+	require.NoError(t, os.RemoveAll(wikiDir))
+	stats, err = idx.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FilesDeleted)
+	assert.Equal(t, []string{"main.go"}, indexedFiles())
+}
+
+func TestIndexerV2_ContentSourcesGraph(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rootDir := t.TempDir()
+	libDir := t.TempDir()
+	createTestGoFile(t, rootDir, "main.go", "package main\n\nfunc main() {}\n")
+	createTestGoFile(t, libDir, "client/client.go", "package client\n\ntype Client struct{}\n\nfunc (c *Client) Fetch() error { return nil }\n")
+
+	sources, err := NewContentSources(rootDir, []ContentSourceConfig{{Label: "lib", Root: libDir, Include: []string{"**/*.go"}}})
+	require.NoError(t, err)
+
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupIntegrationTestStorage(t, db, rootDir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	discovery, err := NewFileDiscovery(rootDir, []string{"**/*.go"}, nil, nil)
+	require.NoError(t, err)
+	discovery.WithContentSources(sources)
+	processor := NewProcessor(rootDir, NewParser(), NewChunker(500, 50), NewFormatter(), &mockEmbedProvider{}, storage,
+		&NoOpProgressReporter{}, WithContentSourcePaths(sources))
+	idx := NewIndexerV2(rootDir, NewChangeDetector(rootDir, storage, discovery), processor, storage, db,
+		WithContentSources(sources))
+
+	_, err = idx.Index(ctx, nil)
+	require.NoError(t, err)
+
+	var typeFile, typeModule string
+	require.NoError(t, db.QueryRow("SELECT file_path, module_path FROM types WHERE name = 'Client'").Scan(&typeFile, &typeModule))
+	assert.Equal(t, "@lib/client/client.go", typeFile)
+	assert.Equal(t, "@lib/client", typeModule)
+
+	var functionFile string
+	require.NoError(t, db.QueryRow("SELECT file_path FROM functions WHERE name = 'Fetch'").Scan(&functionFile))
+	assert.Equal(t, "@lib/client/client.go", functionFile)
+
+	var modules int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM file_modules WHERE file_path LIKE '@%'").Scan(&modules))
+	assert.Zero(t, modules)
+}
+
+func TestNewContentSources(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+
+	_, err := NewContentSources(rootDir, []ContentSourceConfig{{Label: "docs", Root: filepath.Join(rootDir, "docs")}})
+	assert.ErrorContains(t, err, "overlaps the project root")
+	_, err = NewContentSources(filepath.Join(rootDir, "app"), []ContentSourceConfig{{Label: "mono", Root: rootDir}})
+	assert.ErrorContains(t, err, "overlaps the project root")
+	_, err = NewContentSources(rootDir, []ContentSourceConfig{{Label: "project", Root: t.TempDir()}})
+	assert.ErrorContains(t, err, "invalid content source label")
+
+	// Stored paths map back to the files on disk
+	specsDir := t.TempDir()
+	sources, err := NewContentSources(rootDir, []ContentSourceConfig{{Label: "specs", Root: specsDir, Include: []string{"**/*.yaml"}}})
+	require.NoError(t, err)
+	filePath, inSource := sources.FilePath(filepath.Join(specsDir, "v1", "api.yaml"))
+	assert.True(t, inSource)
+	assert.Equal(t, "@specs/v1/api.yaml", filePath)
+	filePath, inSource = sources.FilePath(filepath.Join(specsDir, "README.md"))
+	assert.True(t, inSource)
+	assert.Empty(t, filePath)
+	_, inSource = sources.FilePath(filepath.Join(rootDir, "main.go"))
+	assert.False(t, inSource)
+
+	absPath, ok := sources.AbsPath("@specs/v1/api.yaml")
+	assert.True(t, ok)
+	assert.Equal(t, filepath.Join(specsDir, "v1", "api.yaml"), absPath)
+	_, ok = sources.AbsPath("@wiki/index.md")
+	assert.False(t, ok)
+	assert.Empty(t, sources.WatchDirs())
+}
@@ -0,0 +1,456 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+)
+
+// graphqlExtractor extracts the type system definitions of GraphQL SDL files:
+// object, interface, input, enum, union and scalar types, and the fields of
+// the root operation types as queries, mutations and subscriptions.
+// Descriptions become docs. Executable definitions (operations, fragments)
+// are skipped.
+type graphqlExtractor struct{}
+
+// graphqlToken is a token of a GraphQL document: a name, a punctuator, a
+// number or a string (unquoted; block strings dedented).
+type graphqlToken struct {
+	text string
+	line int
+	str  bool
+}
+
+// graphqlTypeKinds maps type definition keywords to type kinds.
+var graphqlTypeKinds = map[string]string{
+	"type":      "object",
+	"interface": "interface",
+	"input":     "input",
+	"enum":      "enum",
+	"union":     "union",
+	"scalar":    "scalar",
+}
+
+// ParseFile implements LanguageExtractor.
+func (graphqlExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitLines(content)
+	p := &graphqlParser{
+		toks:   tokenizeGraphQL(string(content)),
+		lines:  lines,
+		result: newContractExtraction(protocolGraphQL, filePath, lines),
+		roots:  map[string]string{"Query": "query", "Mutation": "mutation", "Subscription": "subscription"},
+	}
+	p.parseDocument()
+	p.tagRootFields()
+	return p.result, nil
+}
+
+// tokenizeGraphQL splits a GraphQL document into tokens, dropping comments
+// and commas (which are insignificant in GraphQL).
+func tokenizeGraphQL(src string) []graphqlToken {
+	var toks []graphqlToken
+	line := 1
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				end = len(src) - i - 3
+			}
+			text := src[i+3 : i+3+end]
+			toks = append(toks, graphqlToken{text: dedentBlockString(text), line: line, str: true})
+			line += strings.Count(text, "\n")
+			i += end + 6
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(src))
+			toks = append(toks, graphqlToken{text: src[i+1 : j], line: line, str: true})
+			i = j + 1
+		case strings.HasPrefix(src[i:], "..."):
+			toks = append(toks, graphqlToken{text: "...", line: line})
+			i += 3
+		case c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(src) && (src[j] == '_' || src[j] == '.' || src[j] >= 'a' && src[j] <= 'z' ||
+				src[j] >= 'A' && src[j] <= 'Z' || src[j] >= '0' && src[j] <= '9') {
+				j++
+			}
+			toks = append(toks, graphqlToken{text: src[i:j], line: line})
+			i = j
+		default:
+			toks = append(toks, graphqlToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return toks
+}
+
+// dedentBlockString strips the common indentation and surrounding blank
+// lines of a block string.
+func dedentBlockString(s string) string {
+	lines := strings.Split(s, "\n")
+	indent := -1
+	for _, l := range lines[1:] {
+		if trimmed := strings.TrimLeft(l, " \t"); trimmed != "" {
+			if n := len(l) - len(trimmed); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// graphqlParser walks the tokens of a GraphQL document.
+type graphqlParser struct {
+	toks   []graphqlToken
+	pos    int
+	lines  []string
+	result *CodeExtraction
+	roots  map[string]string // Root operation type name → operation
+}
+
+func (p *graphqlParser) peek() graphqlToken {
+	if p.pos >= len(p.toks) {
+		return graphqlToken{line: len(p.lines)}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *graphqlParser) next() graphqlToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *graphqlParser) done() bool {
+	return p.pos >= len(p.toks)
+}
+
+// accept consumes the next token if it is the punctuator or keyword text.
+func (p *graphqlParser) accept(text string) bool {
+	if !p.done() && p.peek().text == text && !p.peek().str {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipGroup skips past the closer matching an already consumed opener and
+// returns its line.
+func (p *graphqlParser) skipGroup(open, close string) int {
+	for depth := 1; !p.done(); {
+		t := p.next()
+		if t.str {
+			continue
+		}
+		switch t.text {
+		case open:
+			depth++
+		case close:
+			if depth--; depth == 0 {
+				return t.line
+			}
+		}
+	}
+	return len(p.lines)
+}
+
+// skipDirectives skips "@name(args)" directive applications.
+func (p *graphqlParser) skipDirectives() {
+	for p.accept("@") {
+		p.next()
+		if p.accept("(") {
+			p.skipGroup("(", ")")
+		}
+	}
+}
+
+// parseTypeRef parses a type reference like "[User!]!" and returns it as
+// written.
+func (p *graphqlParser) parseTypeRef() string {
+	var ref string
+	if p.accept("[") {
+		ref = "[" + p.parseTypeRef() + "]"
+		p.accept("]")
+	} else {
+		ref = p.next().text
+	}
+	if p.accept("!") {
+		ref += "!"
+	}
+	return ref
+}
+
+// isGraphQLName reports whether s is a name.
+func isGraphQLName(s string) bool {
+	return s != "" && (s[0] == '_' || s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z')
+}
+
+func (p *graphqlParser) parseDocument() {
+	for !p.done() {
+		var description string
+		start := p.peek()
+		if start.str {
+			description = p.next().text
+		}
+
+		keyword := p.next()
+		if keyword.text == "extend" {
+			keyword = p.next()
+		}
+
+		switch {
+		case keyword.text == "schema":
+			p.parseSchema()
+		case graphqlTypeKinds[keyword.text] != "":
+			p.parseTypeDefinition(start, keyword, description)
+		case keyword.text == "directive":
+			p.skipDirectiveDefinition()
+		case keyword.text == "{":
+			p.skipGroup("{", "}") // Anonymous query
+		default:
+			// Operations, fragments and anything unknown: skip to the
+			// end of the selection set
+			for !p.done() && !p.accept("{") {
+				p.next()
+			}
+			p.skipGroup("{", "}")
+		}
+	}
+}
+
+// parseSchema parses "schema { query: Q mutation: M }", renaming the roots.
+func (p *graphqlParser) parseSchema() {
+	p.skipDirectives()
+	if !p.accept("{") {
+		return
+	}
+	roots := make(map[string]string)
+	for !p.done() && !p.accept("}") {
+		operation := p.next().text
+		if p.accept(":") {
+			roots[p.next().text] = operation
+		}
+	}
+	if len(roots) > 0 {
+		p.roots = roots
+	}
+}
+
+// skipDirectiveDefinition skips "directive @name(args) on LOCATION | ...".
+func (p *graphqlParser) skipDirectiveDefinition() {
+	p.accept("@")
+	p.next()
+	if p.accept("(") {
+		p.skipGroup("(", ")")
+	}
+	p.accept("repeatable")
+	if p.accept("on") {
+		p.accept("|")
+		for !p.done() {
+			p.next()
+			if !p.accept("|") {
+				return
+			}
+		}
+	}
+}
+
+// parseTypeDefinition parses a type definition (or extension) after its
+// keyword. start is its first token, the description if it has one. Type
+// extensions are parsed like definitions; the first one in a file wins in
+// the graph.
+func (p *graphqlParser) parseTypeDefinition(start, keyword graphqlToken, description string) {
+	name := p.next().text
+	kind := graphqlTypeKinds[keyword.text]
+	typ := ExtractedType{
+		Name: name, Kind: kind, StartLine: start.line, Exported: true, Doc: description,
+		Contract: &ExtractedContract{Protocol: protocolGraphQL, Kind: kind, Name: name},
+	}
+	endLine := keyword.line
+
+	if p.accept("implements") {
+		p.accept("&")
+		for !p.done() && isGraphQLName(p.peek().text) && !p.peek().str {
+			iface := p.next()
+			p.result.Symbols.Relations = append(p.result.Symbols.Relations, extraction.TypeRelation{
+				Type: name, Supertype: iface.text, Kind: "implements", Line: iface.line,
+			})
+			if !p.accept("&") {
+				break
+			}
+		}
+	}
+	p.skipDirectives()
+
+	switch kind {
+	case "union":
+		if p.accept("=") {
+			p.accept("|")
+			for !p.done() {
+				member := p.next()
+				endLine = member.line
+				typ.Fields = append(typ.Fields, ExtractedField{Name: member.text, Type: member.text, Exported: true})
+				if !p.accept("|") {
+					break
+				}
+			}
+		}
+	case "enum":
+		if p.accept("{") {
+			for !p.done() {
+				var doc string
+				if p.peek().str {
+					doc = p.next().text
+				}
+				if t := p.peek(); t.text == "}" {
+					endLine = p.next().line
+					break
+				}
+				value := p.next()
+				p.skipDirectives()
+				typ.Fields = append(typ.Fields, ExtractedField{Name: value.text, Type: name, Exported: true})
+				p.result.Data.Constants = append(p.result.Data.Constants, extraction.ConstantInfo{
+					Name: value.text, Value: value.text, Type: name, StartLine: value.line, EndLine: value.line, Doc: doc,
+				})
+			}
+		}
+	case "scalar":
+	default:
+		if p.accept("{") {
+			endLine = p.parseFields(name, &typ)
+		}
+	}
+
+	typ.EndLine = max(endLine, start.line)
+	p.result.Graph.Types = append(p.result.Graph.Types, typ)
+	p.result.Symbols.Types = append(p.result.Symbols.Types, extraction.SymbolInfo{
+		Name: name, Type: kind, StartLine: typ.StartLine, EndLine: typ.EndLine, Doc: description,
+	})
+	p.result.Definitions.Definitions = append(p.result.Definitions.Definitions, extraction.Definition{
+		Name: name, Type: kind, Code: extractLines(p.lines, typ.StartLine, typ.EndLine),
+		StartLine: typ.StartLine, EndLine: typ.EndLine,
+	})
+}
+
+// parseFields parses the fields of an object, interface or input type up to
+// its closing brace, whose line is returned. Fields are also recorded as
+// functions of the type until tagRootFields keeps those of root types.
+func (p *graphqlParser) parseFields(typeName string, typ *ExtractedType) int {
+	for !p.done() {
+		var doc string
+		if p.peek().str {
+			doc = p.next().text
+		}
+		field := p.next()
+		if field.text == "}" {
+			return field.line
+		}
+
+		var args []string
+		if p.accept("(") {
+			for !p.done() && !p.accept(")") {
+				if p.peek().str {
+					p.next() // Argument description
+					continue
+				}
+				arg := p.next().text
+				if !p.accept(":") {
+					continue
+				}
+				arg += ": " + p.parseTypeRef()
+				if p.accept("=") {
+					p.skipValue()
+				}
+				p.skipDirectives()
+				args = append(args, arg)
+			}
+		}
+		var fieldType string
+		if p.accept(":") {
+			fieldType = p.parseTypeRef()
+		}
+		if p.accept("=") { // Input field default
+			p.skipValue()
+		}
+		p.skipDirectives()
+
+		typ.Fields = append(typ.Fields, ExtractedField{Name: field.text, Type: fieldType, Exported: true})
+		signature := field.text
+		if len(args) > 0 {
+			signature += "(" + strings.Join(args, ", ") + ")"
+		}
+		endLine := p.toks[p.pos-1].line
+		p.result.Graph.Functions = append(p.result.Graph.Functions, ExtractedFunction{
+			Name: field.text, Receiver: typeName, StartLine: field.line, EndLine: endLine, Exported: true, Doc: doc,
+		})
+		p.result.Symbols.Functions = append(p.result.Symbols.Functions, extraction.SymbolInfo{
+			Name: field.text, Type: "field", StartLine: field.line, EndLine: endLine,
+			Signature: typeName + "." + signature + ": " + fieldType, Doc: doc,
+		})
+	}
+	return len(p.lines)
+}
+
+// skipValue skips a default value: a scalar, a list or an input object.
+func (p *graphqlParser) skipValue() {
+	switch t := p.next(); t.text {
+	case "[":
+		p.skipGroup("[", "]")
+	case "{":
+		p.skipGroup("{", "}")
+	case "-":
+		p.next()
+	}
+}
+
+// tagRootFields keeps the fields of root operation types as functions,
+// tagged as queries, mutations or subscriptions; fields of other types are
+// only type fields.
+func (p *graphqlParser) tagRootFields() {
+	graph := p.result.Graph
+	symbols := p.result.Symbols
+	var functions []ExtractedFunction
+	var infos []extraction.SymbolInfo
+	for i, fn := range graph.Functions {
+		operation, ok := p.roots[fn.Receiver]
+		if !ok {
+			continue
+		}
+		fn.Contract = &ExtractedContract{Protocol: protocolGraphQL, Kind: operation, Name: fn.Receiver + "." + fn.Name}
+		functions = append(functions, fn)
+		info := symbols.Functions[i]
+		info.Type = operation
+		infos = append(infos, info)
+	}
+	graph.Functions = functions
+	symbols.Functions = append([]extraction.SymbolInfo{}, infos...)
+}
@@ -0,0 +1,213 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"go.yaml.in/yaml/v3"
+)
+
+// openapiExtractor extracts the operations and schemas of OpenAPI 3 and
+// Swagger 2 documents, in YAML or JSON. Summaries and descriptions become
+// docs. Files that aren't OpenAPI documents are not parsed.
+type openapiExtractor struct{}
+
+// openapiFilenames are the names OpenAPI documents are recognized by.
+var openapiFilenames = []string{
+	"openapi.yaml", "openapi.yml", "openapi.json", "*.openapi.yaml", "*.openapi.yml", "*.openapi.json",
+	"swagger.yaml", "swagger.yml", "swagger.json", "*.swagger.yaml", "*.swagger.yml", "*.swagger.json",
+}
+
+// openapiMethods are the HTTP methods of a path item.
+var openapiMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// ParseFile implements LanguageExtractor.
+func (openapiExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if yamlValue(root, "openapi") == nil && yamlValue(root, "swagger") == nil {
+		return nil, nil
+	}
+
+	lines := splitLines(content)
+	result := newContractExtraction(protocolOpenAPI, filePath, lines)
+	if info := yamlValue(root, "info"); info != nil {
+		result.Symbols.PackageName = yamlString(info, "title")
+		if version := yamlString(info, "version"); version != "" {
+			result.Data.Constants = append(result.Data.Constants, extraction.ConstantInfo{
+				Name: "version", Value: version, Type: "string",
+				StartLine: yamlValue(info, "version").Line, EndLine: yamlValue(info, "version").Line,
+			})
+		}
+	}
+
+	if paths := yamlValue(root, "paths"); paths != nil {
+		for i := 0; i+1 < len(paths.Content); i += 2 {
+			extractOpenAPIPath(result, lines, paths.Content[i].Value, paths.Content[i+1])
+		}
+	}
+
+	schemas := yamlValue(yamlValue(root, "components"), "schemas")
+	if schemas == nil {
+		schemas = yamlValue(root, "definitions") // Swagger 2
+	}
+	if schemas != nil {
+		for i := 0; i+1 < len(schemas.Content); i += 2 {
+			extractOpenAPISchema(result, lines, schemas.Content[i], schemas.Content[i+1])
+		}
+	}
+	return result, nil
+}
+
+// extractOpenAPIPath adds the operations of a path item.
+func extractOpenAPIPath(result *CodeExtraction, lines []string, path string, item *yaml.Node) {
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		method := strings.ToLower(item.Content[i].Value)
+		if !slices.Contains(openapiMethods, method) {
+			continue
+		}
+		op := item.Content[i+1]
+		route := strings.ToUpper(method) + " " + path
+
+		name := yamlString(op, "operationId")
+		signature := route
+		if name == "" {
+			name = route
+		} else {
+			signature += " (" + name + ")"
+		}
+		doc := joinDoc(yamlString(op, "summary"), yamlString(op, "description"))
+		startLine, endLine := item.Content[i].Line, yamlEndLine(op)
+
+		result.Symbols.Functions = append(result.Symbols.Functions, extraction.SymbolInfo{
+			Name: name, Type: "operation", StartLine: startLine, EndLine: endLine, Signature: signature, Doc: doc,
+		})
+		result.Definitions.Definitions = append(result.Definitions.Definitions, extraction.Definition{
+			Name: name, Type: "operation", Code: extractLines(lines, startLine, endLine), StartLine: startLine, EndLine: endLine,
+		})
+		result.Graph.Functions = append(result.Graph.Functions, ExtractedFunction{
+			Name: name, StartLine: startLine, EndLine: endLine, Exported: true, Doc: doc,
+			Contract: &ExtractedContract{Protocol: protocolOpenAPI, Kind: "operation", Name: route},
+		})
+	}
+}
+
+// extractOpenAPISchema adds a named schema as a type whose fields are its
+// properties. Schemas composed with allOf extend the schemas they reference.
+func extractOpenAPISchema(result *CodeExtraction, lines []string, key, schema *yaml.Node) {
+	name := key.Value
+	startLine, endLine := key.Line, yamlEndLine(schema)
+	doc := joinDoc(yamlString(schema, "title"), yamlString(schema, "description"))
+
+	typ := ExtractedType{
+		Name: name, Kind: "schema", StartLine: startLine, EndLine: endLine, Exported: true, Doc: doc,
+		Contract: &ExtractedContract{Protocol: protocolOpenAPI, Kind: "schema", Name: name},
+	}
+	addProperties := func(schema *yaml.Node) {
+		properties := yamlValue(schema, "properties")
+		if properties == nil {
+			return
+		}
+		for i := 0; i+1 < len(properties.Content); i += 2 {
+			typ.Fields = append(typ.Fields, ExtractedField{
+				Name: properties.Content[i].Value, Type: openapiSchemaType(properties.Content[i+1]), Exported: true,
+			})
+		}
+	}
+	addProperties(schema)
+	if allOf := yamlValue(schema, "allOf"); allOf != nil {
+		for _, part := range allOf.Content {
+			if ref := yamlString(part, "$ref"); ref != "" {
+				result.Symbols.Relations = append(result.Symbols.Relations, extraction.TypeRelation{
+					Type: name, Supertype: refName(ref), Kind: "extends", Line: part.Line,
+				})
+			}
+			addProperties(part)
+		}
+	}
+
+	result.Graph.Types = append(result.Graph.Types, typ)
+	result.Symbols.Types = append(result.Symbols.Types, extraction.SymbolInfo{
+		Name: name, Type: "schema", StartLine: startLine, EndLine: endLine, Doc: doc,
+	})
+	result.Definitions.Definitions = append(result.Definitions.Definitions, extraction.Definition{
+		Name: name, Type: "schema", Code: extractLines(lines, startLine, endLine), StartLine: startLine, EndLine: endLine,
+	})
+}
+
+// openapiSchemaType describes the type of a property schema: the referenced
+// schema, "[]item" for arrays, or the type (and format).
+func openapiSchemaType(schema *yaml.Node) string {
+	if ref := yamlString(schema, "$ref"); ref != "" {
+		return refName(ref)
+	}
+	typ := yamlString(schema, "type")
+	if typ == "array" {
+		if items := yamlValue(schema, "items"); items != nil {
+			return "[]" + openapiSchemaType(items)
+		}
+	}
+	if format := yamlString(schema, "format"); format != "" {
+		return typ + " (" + format + ")"
+	}
+	return typ
+}
+
+// refName returns the schema name of a local reference like
+// "#/components/schemas/User".
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// joinDoc joins a summary and a description into a doc.
+func joinDoc(summary, description string) string {
+	return strings.TrimSpace(strings.TrimSpace(summary) + "\n\n" + strings.TrimSpace(description))
+}
+
+// yamlValue returns the value of key in a mapping node, or nil.
+func yamlValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// yamlString returns the scalar value of key in a mapping node, or "".
+func yamlString(node *yaml.Node, key string) string {
+	if value := yamlValue(node, key); value != nil && value.Kind == yaml.ScalarNode {
+		return value.Value
+	}
+	return ""
+}
+
+// yamlEndLine returns the last line of a node.
+func yamlEndLine(node *yaml.Node) int {
+	end := node.Line
+	if node.Kind == yaml.ScalarNode && (node.Style&(yaml.LiteralStyle|yaml.FoldedStyle)) != 0 {
+		end += strings.Count(strings.TrimSuffix(node.Value, "\n"), "\n") + 1
+	}
+	for _, child := range node.Content {
+		end = max(end, yamlEndLine(child))
+	}
+	return end
+}
@@ -0,0 +1,500 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+)
+
+// protobufExtractor extracts the packages, imports, messages, enums and
+// services of .proto files. Leading comments become docs.
+type protobufExtractor struct{}
+
+// protoToken is a token of a .proto file: an identifier (possibly dotted),
+// a number, a string literal (unquoted) or a punctuation character.
+type protoToken struct {
+	text string
+	line int
+	str  bool   // String literal
+	doc  string // Comment block directly above the token
+}
+
+// ParseFile implements LanguageExtractor.
+func (protobufExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &protoParser{
+		toks:   tokenizeProto(string(content)),
+		lines:  splitLines(content),
+		result: newContractExtraction(protocolProtobuf, filePath, splitLines(content)),
+	}
+	p.parseFile()
+
+	// go_package may follow the declarations
+	for i := range p.result.Graph.Types {
+		p.result.Graph.Types[i].Contract.GoPackage = p.goPackage
+	}
+	for i := range p.result.Graph.Functions {
+		p.result.Graph.Functions[i].Contract.GoPackage = p.goPackage
+	}
+	return p.result, nil
+}
+
+// tokenizeProto splits a .proto file into tokens. Comments are dropped,
+// except that a block of comments ending on the line above a token (with no
+// blank line in between) becomes the token's doc. Comments following a token
+// on the same line are trailing comments and are ignored.
+func tokenizeProto(src string) []protoToken {
+	var toks []protoToken
+	var pending []string
+	pendingEnd := 0 // Line the pending comments end on
+	lastTokenLine := 0
+	line := 1
+
+	comment := func(text string, startLine int) {
+		if startLine == lastTokenLine {
+			return // Trailing comment
+		}
+		if len(pending) > 0 && startLine > pendingEnd+1 {
+			pending = nil
+		}
+		pending = append(pending, text)
+		pendingEnd = line
+	}
+	emit := func(t protoToken) {
+		if len(pending) > 0 && pendingEnd >= t.line-1 {
+			t.doc = stripCommentBlock(pending)
+		}
+		pending = nil
+		lastTokenLine = t.line
+		toks = append(toks, t)
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			comment(src[i:i+end], line)
+			i += end
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i:], "*/")
+			if end < 0 {
+				end = len(src) - i
+			} else {
+				end += 2
+			}
+			startLine := line
+			line += strings.Count(src[i:i+end], "\n")
+			comment(src[i:i+end], startLine)
+			i += end
+		case c == '"' || c == '\'':
+			j := i + 1
+			for j < len(src) && src[j] != c && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			j = min(j, len(src))
+			emit(protoToken{text: src[i+1 : j], line: line, str: true})
+			i = j + 1
+		case isProtoIdentChar(c):
+			j := i
+			for j < len(src) && isProtoIdentChar(src[j]) {
+				j++
+			}
+			emit(protoToken{text: src[i:j], line: line})
+			i = j
+		default:
+			emit(protoToken{text: string(c), line: line})
+			i++
+		}
+	}
+	return toks
+}
+
+// isProtoIdentChar reports whether c can be part of an identifier, a dotted
+// name or a number.
+func isProtoIdentChar(c byte) bool {
+	return c == '_' || c == '.' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// stripCommentBlock joins comments and strips their markers.
+func stripCommentBlock(comments []string) string {
+	var lines []string
+	for _, c := range comments {
+		c = strings.TrimSuffix(strings.TrimPrefix(c, "/*"), "*/")
+		for _, l := range strings.Split(c, "\n") {
+			l = strings.TrimSpace(l)
+			l = strings.TrimPrefix(l, "//")
+			l = strings.TrimPrefix(l, "*")
+			lines = append(lines, strings.TrimPrefix(l, " "))
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// protoParser walks the tokens of a .proto file, skipping what it doesn't
+// understand (options, extensions, reserved ranges) statement by statement.
+type protoParser struct {
+	toks      []protoToken
+	pos       int
+	lines     []string
+	pkg       string
+	goPackage string
+	result    *CodeExtraction
+}
+
+func (p *protoParser) peek() protoToken {
+	if p.pos >= len(p.toks) {
+		return protoToken{line: len(p.lines)}
+	}
+	return p.toks[p.pos]
+}
+
+func (p *protoParser) next() protoToken {
+	t := p.peek()
+	p.pos++
+	return t
+}
+
+func (p *protoParser) done() bool {
+	return p.pos >= len(p.toks)
+}
+
+// accept consumes the next token if it is text.
+func (p *protoParser) accept(text string) bool {
+	if !p.done() && p.peek().text == text && !p.peek().str {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// skipStatement skips to the end of the current statement: past the next
+// ";" or the matching "}" of a block.
+func (p *protoParser) skipStatement() {
+	for !p.done() {
+		t := p.next()
+		if t.str {
+			continue
+		}
+		switch t.text {
+		case ";":
+			return
+		case "{":
+			p.skipBlock()
+			return
+		case "}":
+			p.pos-- // End of the enclosing block
+			return
+		}
+	}
+}
+
+// skipBlock skips past the "}" matching an already consumed "{" and returns
+// its line.
+func (p *protoParser) skipBlock() int {
+	for depth := 1; !p.done(); {
+		t := p.next()
+		if t.str {
+			continue
+		}
+		switch t.text {
+		case "{":
+			depth++
+		case "}":
+			if depth--; depth == 0 {
+				return t.line
+			}
+		}
+	}
+	return len(p.lines)
+}
+
+// qualify returns the fully qualified name of a declaration.
+func (p *protoParser) qualify(name string) string {
+	if p.pkg == "" {
+		return name
+	}
+	return p.pkg + "." + name
+}
+
+func (p *protoParser) parseFile() {
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "package":
+			p.pkg = p.next().text
+			p.result.Symbols.PackageName = p.pkg
+			p.skipStatement()
+		case "import":
+			if p.peek().text == "public" || p.peek().text == "weak" {
+				p.next()
+			}
+			if path := p.next(); path.str {
+				p.result.Symbols.Imports = append(p.result.Symbols.Imports, extraction.ImportRef{Path: path.text, Line: path.line})
+				p.result.Symbols.ImportsCount++
+			}
+			p.skipStatement()
+		case "option":
+			name := p.next()
+			if p.accept("=") && name.text == "go_package" && p.peek().str {
+				p.goPackage = p.next().text
+			}
+			p.skipStatement()
+		case "message":
+			p.parseMessage(t, "", true)
+		case "enum":
+			p.parseEnum(t, "", true)
+		case "service":
+			p.parseService(t)
+		case ";":
+		default:
+			p.skipStatement()
+		}
+	}
+}
+
+// parseMessage parses a message after its keyword; nested messages and enums
+// are named Outer.Inner.
+func (p *protoParser) parseMessage(keyword protoToken, parent string, topLevel bool) {
+	name := p.next().text
+	if parent != "" {
+		name = parent + "." + name
+	}
+	if !p.accept("{") {
+		p.skipStatement()
+		return
+	}
+
+	typ := ExtractedType{
+		Name: name, Kind: "message", StartLine: keyword.line, Exported: true, Doc: keyword.doc,
+		Contract: &ExtractedContract{Protocol: protocolProtobuf, Kind: "message", Name: p.qualify(name)},
+	}
+	index := len(p.result.Graph.Types)
+	p.result.Graph.Types = append(p.result.Graph.Types, typ)
+
+	var fields []ExtractedField
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			p.finishType(index, fields, t.line, topLevel)
+			return
+		case "message":
+			p.parseMessage(t, name, false)
+		case "enum":
+			p.parseEnum(t, name, false)
+		case "oneof":
+			p.next() // Name
+			if p.accept("{") {
+				for !p.done() && !p.accept("}") {
+					if p.peek().text == "option" {
+						p.skipStatement()
+						continue
+					}
+					if field, ok := p.parseField(); ok {
+						fields = append(fields, field)
+					}
+				}
+			}
+		case "option", "reserved", "extensions", "extend", ";":
+			if t.text != ";" {
+				p.skipStatement()
+			}
+		default:
+			p.pos--
+			if field, ok := p.parseField(); ok {
+				fields = append(fields, field)
+			}
+		}
+	}
+	p.finishType(index, fields, len(p.lines), topLevel)
+}
+
+// parseField parses "[label] type name = number [options];" and
+// "map<key, value> name = number;".
+func (p *protoParser) parseField() (ExtractedField, bool) {
+	start := p.pos
+	if t := p.peek().text; t == "optional" || t == "repeated" || t == "required" {
+		p.next()
+	}
+
+	fieldType := p.next().text
+	if fieldType == "map" && p.accept("<") {
+		key := p.next().text
+		p.accept(",")
+		value := p.next().text
+		p.accept(">")
+		fieldType = fmt.Sprintf("map<%s, %s>", key, value)
+	}
+	if label := p.toks[start].text; label == "repeated" {
+		fieldType = "repeated " + fieldType
+	}
+	name := p.next()
+	ok := p.accept("=")
+	p.skipStatement()
+	if !ok || name.str {
+		return ExtractedField{}, false
+	}
+	return ExtractedField{Name: name.text, Type: fieldType, Exported: true}, true
+}
+
+// parseEnum parses an enum after its keyword. Values become constants.
+func (p *protoParser) parseEnum(keyword protoToken, parent string, topLevel bool) {
+	name := p.next().text
+	if parent != "" {
+		name = parent + "." + name
+	}
+	if !p.accept("{") {
+		p.skipStatement()
+		return
+	}
+
+	index := len(p.result.Graph.Types)
+	p.result.Graph.Types = append(p.result.Graph.Types, ExtractedType{
+		Name: name, Kind: "enum", StartLine: keyword.line, Exported: true, Doc: keyword.doc,
+		Contract: &ExtractedContract{Protocol: protocolProtobuf, Kind: "enum", Name: p.qualify(name)},
+	})
+
+	var values []ExtractedField
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			p.finishType(index, values, t.line, topLevel)
+			return
+		case "option", "reserved", ";":
+			if t.text != ";" {
+				p.skipStatement()
+			}
+		default:
+			var value string
+			if p.accept("=") {
+				value = p.next().text
+				if value == "-" {
+					value += p.next().text
+				}
+			}
+			p.skipStatement()
+			values = append(values, ExtractedField{Name: t.text, Type: name, Exported: true})
+			p.result.Data.Constants = append(p.result.Data.Constants, extraction.ConstantInfo{
+				Name: t.text, Value: value, Type: name, StartLine: t.line, EndLine: t.line, Doc: t.doc,
+			})
+		}
+	}
+	p.finishType(index, values, len(p.lines), topLevel)
+}
+
+// finishType completes the graph type at index once its closing brace is
+// found, and adds its symbol (and, at the top level, its definition).
+func (p *protoParser) finishType(index int, fields []ExtractedField, endLine int, topLevel bool) {
+	typ := &p.result.Graph.Types[index]
+	typ.EndLine = endLine
+	typ.Fields = fields
+
+	p.result.Symbols.Types = append(p.result.Symbols.Types, extraction.SymbolInfo{
+		Name: typ.Name, Type: typ.Kind, StartLine: typ.StartLine, EndLine: endLine, Doc: typ.Doc,
+	})
+	if topLevel {
+		p.result.Definitions.Definitions = append(p.result.Definitions.Definitions, extraction.Definition{
+			Name: typ.Name, Type: typ.Kind, Code: extractLines(p.lines, typ.StartLine, endLine),
+			StartLine: typ.StartLine, EndLine: endLine,
+		})
+	}
+}
+
+// parseService parses a service after its keyword. RPCs become methods of
+// the service.
+func (p *protoParser) parseService(keyword protoToken) {
+	name := p.next().text
+	if !p.accept("{") {
+		p.skipStatement()
+		return
+	}
+
+	index := len(p.result.Graph.Types)
+	p.result.Graph.Types = append(p.result.Graph.Types, ExtractedType{
+		Name: name, Kind: "service", StartLine: keyword.line, Exported: true, Doc: keyword.doc,
+		Contract: &ExtractedContract{Protocol: protocolProtobuf, Kind: "service", Name: p.qualify(name)},
+	})
+
+	var methods []ExtractedField
+	for !p.done() {
+		t := p.next()
+		switch t.text {
+		case "}":
+			p.finishType(index, methods, t.line, true)
+			return
+		case "rpc":
+			if method, ok := p.parseRPC(t, name); ok {
+				methods = append(methods, method)
+			}
+		case ";":
+		default:
+			p.skipStatement()
+		}
+	}
+	p.finishType(index, methods, len(p.lines), true)
+}
+
+// parseRPC parses "rpc Name(stream Req) returns (stream Resp)" followed by
+// ";" or an options block.
+func (p *protoParser) parseRPC(keyword protoToken, service string) (ExtractedField, bool) {
+	name := p.next().text
+	request, ok := p.parseRPCType()
+	if !ok || !p.accept("returns") {
+		p.skipStatement()
+		return ExtractedField{}, false
+	}
+	response, ok := p.parseRPCType()
+	if !ok {
+		p.skipStatement()
+		return ExtractedField{}, false
+	}
+
+	endLine := p.peek().line
+	if p.accept("{") {
+		endLine = p.skipBlock()
+	} else {
+		p.accept(";")
+	}
+
+	signature := fmt.Sprintf("rpc %s.%s(%s) returns (%s)", service, name, request, response)
+	p.result.Symbols.Functions = append(p.result.Symbols.Functions, extraction.SymbolInfo{
+		Name: name, Type: "rpc", StartLine: keyword.line, EndLine: endLine, Signature: signature, Doc: keyword.doc,
+	})
+	p.result.Graph.Functions = append(p.result.Graph.Functions, ExtractedFunction{
+		Name: name, Receiver: service, StartLine: keyword.line, EndLine: endLine, Exported: true, Doc: keyword.doc,
+		Contract: &ExtractedContract{Protocol: protocolProtobuf, Kind: "rpc", Name: p.qualify(service) + "/" + name},
+	})
+	return ExtractedField{Name: name, Type: signature, Method: true, Exported: true}, true
+}
+
+// parseRPCType parses "(Type)" or "(stream Type)".
+func (p *protoParser) parseRPCType() (string, bool) {
+	if !p.accept("(") {
+		return "", false
+	}
+	typ := p.next().text
+	if typ == "stream" && p.peek().text != ")" {
+		typ = "stream " + p.next().text
+	}
+	return typ, p.accept(")")
+}
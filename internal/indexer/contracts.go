@@ -0,0 +1,46 @@
+package indexer
+
+import (
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"github.com/mvp-joe/project-cortex/internal/languages"
+)
+
+// API contract languages. Their extractors are built in and return graph
+// data whose types and functions are tagged as contracts: Protobuf messages,
+// enums, services and RPCs; OpenAPI schemas and operations; GraphQL types and
+// root fields (queries, mutations, subscriptions).
+const (
+	protocolProtobuf = languages.Protobuf
+	protocolOpenAPI  = languages.OpenAPI
+	protocolGraphQL  = languages.GraphQL
+)
+
+// newContractExtraction returns an empty extraction of a contract file with
+// graph data.
+func newContractExtraction(language, filePath string, lines []string) *CodeExtraction {
+	return &CodeExtraction{
+		Language:  language,
+		FilePath:  filePath,
+		StartLine: 1,
+		EndLine:   len(lines),
+		Symbols: &extraction.SymbolsData{
+			Types:     []extraction.SymbolInfo{},
+			Functions: []extraction.SymbolInfo{},
+		},
+		Definitions: &extraction.DefinitionsData{
+			Definitions: []extraction.Definition{},
+		},
+		Data: &extraction.DataData{
+			Constants: []extraction.ConstantInfo{},
+			Variables: []extraction.VariableInfo{},
+		},
+		Graph: &ExtractedGraph{},
+	}
+}
+
+// splitLines splits file content into lines for extractLines.
+func splitLines(content []byte) []string {
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
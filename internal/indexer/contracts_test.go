@@ -0,0 +1,445 @@
+package indexer
+
+// Test Plan for API Contract Extractors:
+// - Protobuf: package, imports, go_package (even after declarations), messages with nested
+//   messages and enums, oneofs, maps, enum values as constants, services and RPCs
+//   with streaming and option blocks; leading comments become docs, trailing ones don't
+// - The repository's own indexer.proto yields its service and RPCs
+// - GraphQL: descriptions, implements relations, enums, unions, inputs, root fields as
+//   queries and mutations (renamed by a schema block); executable definitions are skipped
+// - OpenAPI: operations (by operationId or route), schemas with properties, allOf relations;
+//   Swagger 2 JSON documents; files that aren't OpenAPI documents aren't parsed
+// - Contract files are detected by extension and filename
+// - An indexing run tags contract symbols and links the generated Go code of a proto file
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testProto = `syntax = "proto3";
+
+package shop.v1;
+
+import "google/protobuf/timestamp.proto";
+
+// Order is a placed order.
+// It is immutable.
+message Order {
+  string id = 1; // Trailing comment, not a doc
+  repeated Item items = 2;
+  map<string, string> labels = 3;
+  oneof payment {
+    Card card = 4;
+    string voucher = 5;
+  }
+  reserved 6, 7;
+
+  // Item is an order line.
+  message Item {
+    string sku = 1;
+    int32 quantity = 2 [deprecated = true];
+  }
+
+  enum Status {
+    STATUS_UNSPECIFIED = 0;
+    // Paid orders ship.
+    STATUS_PAID = 1;
+  }
+}
+
+message Card { string number = 1; }
+
+// OrderService places orders.
+service OrderService {
+  // Place places an order.
+  rpc Place(Order) returns (Order);
+
+  rpc Watch(WatchRequest) returns (stream Order) {
+    option idempotency_level = NO_SIDE_EFFECTS;
+  }
+}
+
+option go_package = "example.com/shop/gen/shop/v1;shopv1";
+`
+
+func writeContractFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestProtobufExtractor(t *testing.T) {
+	t.Parallel()
+
+	ext, err := protobufExtractor{}.ParseFile(context.Background(), writeContractFile(t, "shop.proto", testProto))
+	require.NoError(t, err)
+
+	assert.Equal(t, "protobuf", ext.Language)
+	assert.Equal(t, "shop.v1", ext.Symbols.PackageName)
+	assert.Equal(t, []extraction.ImportRef{{Path: "google/protobuf/timestamp.proto", Line: 5}}, ext.Symbols.Imports)
+
+	types := make(map[string]ExtractedType)
+	for _, typ := range ext.Graph.Types {
+		types[typ.Name] = typ
+		assert.Equal(t, "example.com/shop/gen/shop/v1;shopv1", typ.Contract.GoPackage, typ.Name)
+	}
+	require.Len(t, types, 5)
+
+	order := types["Order"]
+	assert.Equal(t, "message", order.Kind)
+	assert.Equal(t, "Order is a placed order.\nIt is immutable.", order.Doc)
+	assert.Equal(t, 9, order.StartLine)
+	assert.Equal(t, 30, order.EndLine)
+	assert.Equal(t, "shop.v1.Order", order.Contract.Name)
+	assert.Equal(t, []ExtractedField{
+		{Name: "id", Type: "string", Exported: true},
+		{Name: "items", Type: "repeated Item", Exported: true},
+		{Name: "labels", Type: "map<string, string>", Exported: true},
+		{Name: "card", Type: "Card", Exported: true},
+		{Name: "voucher", Type: "string", Exported: true},
+	}, order.Fields)
+
+	assert.Equal(t, "Item is an order line.", types["Order.Item"].Doc)
+	assert.Len(t, types["Order.Item"].Fields, 2)
+	assert.Equal(t, "shop.v1.Order.Status", types["Order.Status"].Contract.Name)
+	assert.Equal(t, "enum", types["Order.Status"].Kind)
+	assert.Len(t, types["Card"].Fields, 1)
+
+	service := types["OrderService"]
+	assert.Equal(t, "service", service.Kind)
+	assert.Equal(t, "OrderService places orders.", service.Doc)
+	assert.Len(t, service.Fields, 2)
+
+	require.Len(t, ext.Graph.Functions, 2)
+	place, watch := ext.Graph.Functions[0], ext.Graph.Functions[1]
+	assert.Equal(t, "OrderService", place.Receiver)
+	assert.Equal(t, "Place places an order.", place.Doc)
+	assert.Equal(t, &ExtractedContract{
+		Protocol: "protobuf", Kind: "rpc", Name: "shop.v1.OrderService/Place", GoPackage: "example.com/shop/gen/shop/v1;shopv1",
+	}, place.Contract)
+	assert.Equal(t, 39, watch.StartLine)
+	assert.Equal(t, 41, watch.EndLine)
+	assert.Equal(t, "rpc OrderService.Watch(WatchRequest) returns (stream Order)", ext.Symbols.Functions[1].Signature)
+
+	require.Len(t, ext.Data.Constants, 2)
+	assert.Equal(t, extraction.ConstantInfo{
+		Name: "STATUS_PAID", Value: "1", Type: "Order.Status", StartLine: 28, EndLine: 28, Doc: "Paid orders ship.",
+	}, ext.Data.Constants[1])
+
+	// Top-level declarations only: nested ones are part of their parent
+	var definitions []string
+	for _, d := range ext.Definitions.Definitions {
+		definitions = append(definitions, d.Name)
+	}
+	assert.Equal(t, []string{"Order", "Card", "OrderService"}, definitions)
+}
+
+func TestProtobufExtractor_IndexerAPI(t *testing.T) {
+	t.Parallel()
+
+	ext, err := protobufExtractor{}.ParseFile(context.Background(), "../../api/indexer/v1/indexer.proto")
+	require.NoError(t, err)
+
+	var rpcs []string
+	for _, fn := range ext.Graph.Functions {
+		rpcs = append(rpcs, fn.Contract.Name)
+		assert.NotEmpty(t, fn.Doc, fn.Name)
+	}
+	assert.Equal(t, []string{
+		"indexer.v1.IndexerService/Index",
+		"indexer.v1.IndexerService/GetStatus",
+		"indexer.v1.IndexerService/StreamLogs",
+		"indexer.v1.IndexerService/UnregisterProject",
+		"indexer.v1.IndexerService/Shutdown",
+	}, rpcs)
+	assert.Equal(t, "github.com/mvp-joe/project-cortex/gen/indexer/v1;indexerv1", ext.Graph.Functions[0].Contract.GoPackage)
+}
+
+const testGraphQL = `"""
+A registered user.
+"""
+type User implements Node & Entity @key(fields: "id") {
+  id: ID!
+  "Display name"
+  name: String
+  posts(first: Int = 10, after: String): [Post!]!
+}
+
+enum Role {
+  ADMIN
+  "Can only read"
+  VIEWER
+}
+
+union SearchResult = User | Post
+
+input NewPost { title: String!, tags: [String!] = [] }
+
+schema { query: RootQuery mutation: RootMutation }
+
+type RootQuery {
+  "Finds a user by ID."
+  user(id: ID!): User
+}
+
+type RootMutation {
+  createPost(input: NewPost!): Post
+}
+
+# Client operations are not part of the schema
+query GetUser($id: ID!) { user(id: $id) { name } }
+
+directive @key(fields: String!) repeatable on OBJECT | INTERFACE
+scalar DateTime
+`
+
+func TestGraphQLExtractor(t *testing.T) {
+	t.Parallel()
+
+	ext, err := graphqlExtractor{}.ParseFile(context.Background(), writeContractFile(t, "schema.graphql", testGraphQL))
+	require.NoError(t, err)
+
+	kinds := make(map[string]string)
+	types := make(map[string]ExtractedType)
+	for _, typ := range ext.Graph.Types {
+		kinds[typ.Name] = typ.Kind
+		types[typ.Name] = typ
+	}
+	assert.Equal(t, map[string]string{
+		"User": "object", "Role": "enum", "SearchResult": "union", "NewPost": "input",
+		"RootQuery": "object", "RootMutation": "object", "DateTime": "scalar",
+	}, kinds)
+
+	user := types["User"]
+	assert.Equal(t, "A registered user.", user.Doc)
+	assert.Equal(t, 1, user.StartLine)
+	assert.Equal(t, 9, user.EndLine)
+	assert.Equal(t, []ExtractedField{
+		{Name: "id", Type: "ID!", Exported: true},
+		{Name: "name", Type: "String", Exported: true},
+		{Name: "posts", Type: "[Post!]!", Exported: true},
+	}, user.Fields)
+	assert.Equal(t, []extraction.TypeRelation{
+		{Type: "User", Supertype: "Node", Kind: "implements", Line: 4},
+		{Type: "User", Supertype: "Entity", Kind: "implements", Line: 4},
+	}, ext.Symbols.Relations)
+	assert.Len(t, types["SearchResult"].Fields, 2)
+	assert.Len(t, types["NewPost"].Fields, 2)
+
+	require.Len(t, ext.Data.Constants, 2)
+	assert.Equal(t, "Can only read", ext.Data.Constants[1].Doc)
+
+	// Only root fields are operations
+	require.Len(t, ext.Graph.Functions, 2)
+	assert.Equal(t, "user", ext.Graph.Functions[0].Name)
+	assert.Equal(t, "Finds a user by ID.", ext.Graph.Functions[0].Doc)
+	assert.Equal(t, &ExtractedContract{Protocol: "graphql", Kind: "query", Name: "RootQuery.user"}, ext.Graph.Functions[0].Contract)
+	assert.Equal(t, "mutation", ext.Graph.Functions[1].Contract.Kind)
+	require.Len(t, ext.Symbols.Functions, 2)
+	assert.Equal(t, "RootMutation.createPost(input: NewPost!): Post", ext.Symbols.Functions[1].Signature)
+}
+
+const testOpenAPI = `openapi: 3.0.3
+info:
+  title: Pet Store
+  version: 1.2.0
+paths:
+  /pets/{id}:
+    parameters:
+      - name: id
+        in: path
+    get:
+      operationId: getPet
+      summary: Get a pet
+      description: |
+        Returns a single pet.
+        Requires a token.
+      responses:
+        "200":
+          description: The pet
+    delete:
+      responses:
+        "204":
+          description: Deleted
+components:
+  schemas:
+    Pet:
+      description: A pet.
+      properties:
+        id: {type: integer, format: int64}
+        tags: {type: array, items: {type: string}}
+        owner: {$ref: "#/components/schemas/Owner"}
+    Dog:
+      allOf:
+        - $ref: "#/components/schemas/Pet"
+        - properties:
+            breed: {type: string}
+`
+
+func TestOpenAPIExtractor(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	ext, err := openapiExtractor{}.ParseFile(ctx, writeContractFile(t, "openapi.yaml", testOpenAPI))
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+
+	assert.Equal(t, "Pet Store", ext.Symbols.PackageName)
+	assert.Equal(t, "1.2.0", ext.Data.Constants[0].Value)
+
+	require.Len(t, ext.Graph.Functions, 2)
+	get := ext.Graph.Functions[0]
+	assert.Equal(t, "getPet", get.Name)
+	assert.Equal(t, "Get a pet\n\nReturns a single pet.\nRequires a token.", get.Doc)
+	assert.Equal(t, 10, get.StartLine)
+	assert.Equal(t, 18, get.EndLine)
+	assert.Equal(t, &ExtractedContract{Protocol: "openapi", Kind: "operation", Name: "GET /pets/{id}"}, get.Contract)
+	assert.Equal(t, "DELETE /pets/{id}", ext.Graph.Functions[1].Name)
+	assert.Equal(t, "GET /pets/{id} (getPet)", ext.Symbols.Functions[0].Signature)
+
+	require.Len(t, ext.Graph.Types, 2)
+	assert.Equal(t, "A pet.", ext.Graph.Types[0].Doc)
+	assert.Equal(t, []ExtractedField{
+		{Name: "id", Type: "integer (int64)", Exported: true},
+		{Name: "tags", Type: "[]string", Exported: true},
+		{Name: "owner", Type: "Owner", Exported: true},
+	}, ext.Graph.Types[0].Fields)
+	assert.Equal(t, []ExtractedField{{Name: "breed", Type: "string", Exported: true}}, ext.Graph.Types[1].Fields)
+	assert.Equal(t, []extraction.TypeRelation{{Type: "Dog", Supertype: "Pet", Kind: "extends", Line: 33}}, ext.Symbols.Relations)
+
+	// Swagger 2, JSON
+	ext, err = openapiExtractor{}.ParseFile(ctx, writeContractFile(t, "swagger.json",
+		`{"swagger": "2.0", "paths": {"/users": {"post": {"operationId": "createUser"}}},
+		  "definitions": {"User": {"properties": {"name": {"type": "string"}}}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "createUser", ext.Graph.Functions[0].Name)
+	assert.Equal(t, "User", ext.Graph.Types[0].Name)
+
+	// Not an OpenAPI document
+	ext, err = openapiExtractor{}.ParseFile(ctx, writeContractFile(t, "openapi.yaml", "name: app\n"))
+	require.NoError(t, err)
+	assert.Nil(t, ext)
+}
+
+func TestLanguageRegistry_ContractLanguages(t *testing.T) {
+	t.Parallel()
+
+	languages := DefaultLanguageRegistry()
+	assert.Equal(t, "protobuf", languages.Detect("api/indexer/v1/indexer.proto"))
+	assert.Equal(t, "graphql", languages.Detect("schema.graphql"))
+	assert.Equal(t, "graphql", languages.Detect("queries/user.gql"))
+	assert.Equal(t, "openapi", languages.Detect("api/openapi.yaml"))
+	assert.Equal(t, "openapi", languages.Detect("specs/billing.openapi.json"))
+	assert.Equal(t, "openapi", languages.Detect("swagger.json"))
+	assert.Equal(t, "yaml", languages.Detect("config.yaml"), "not an OpenAPI document name")
+
+	for _, name := range []string{"protobuf", "graphql", "openapi"} {
+		lang, ok := languages.Lookup(name)
+		require.True(t, ok)
+		assert.True(t, lang.Graph, name)
+	}
+}
+
+func TestIndexerV2_ContractLinks(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rootDir := t.TempDir()
+	createTestGoFile(t, rootDir, "api/shop/v1/shop.proto", testProto)
+	createTestGoFile(t, rootDir, "gen/shop/v1/shop.pb.go", `package shopv1
+
+type Order struct{ Id string }
+
+type Order_Item struct{ Sku string }
+
+type Order_Status int32
+`)
+	createTestGoFile(t, rootDir, "gen/shop/v1/shopv1connect/shop.connect.go", `package shopv1connect
+
+type OrderServiceClient interface {
+	Place() error
+}
+
+type orderServiceClient struct{}
+
+func (c *orderServiceClient) Place() error { return nil }
+
+type OrderServiceHandler interface {
+	Place() error
+}
+
+type UnimplementedOrderServiceHandler struct{}
+
+func (UnimplementedOrderServiceHandler) Place() error { return nil }
+`)
+
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupIntegrationTestStorage(t, db, rootDir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	discovery, err := NewFileDiscovery(rootDir, []string{"**/*.go", "**/*.proto"}, nil, nil)
+	require.NoError(t, err)
+	processor := NewProcessor(rootDir, NewParser(), NewChunker(500, 50), NewFormatter(),
+		&mockEmbedProvider{}, storage, &NoOpProgressReporter{})
+	idx := NewIndexerV2(rootDir, NewChangeDetector(rootDir, storage, discovery), processor, storage, db)
+
+	_, err = idx.Index(ctx, nil)
+	require.NoError(t, err)
+
+	count := func(query string, args ...any) int {
+		var n int
+		require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+		return n
+	}
+	proto := "api/shop/v1/shop.proto"
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM files WHERE file_path = ? AND language = 'protobuf'", proto))
+	assert.Positive(t, count("SELECT COUNT(*) FROM chunks WHERE file_path = ?", proto))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM functions WHERE function_id = ?", proto+"::OrderService.Place"))
+	assert.Equal(t, 7, count("SELECT COUNT(*) FROM contract_symbols WHERE file_path = ? AND protocol = 'protobuf'", proto))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM contract_symbols WHERE qualified_name = 'shop.v1.OrderService/Place' AND kind = 'rpc'"))
+
+	links := func(symbol string) map[string]string {
+		rows, err := db.Query("SELECT generated_id, link_kind FROM contract_links WHERE contract_symbol_id = ?", proto+"::"+symbol)
+		require.NoError(t, err)
+		defer rows.Close()
+		result := make(map[string]string)
+		for rows.Next() {
+			var id, kind string
+			require.NoError(t, rows.Scan(&id, &kind))
+			result[id] = kind
+		}
+		return result
+	}
+	assert.Equal(t, map[string]string{"gen/shop/v1::Order": "type"}, links("Order"))
+	assert.Equal(t, map[string]string{"gen/shop/v1::Order_Item": "type"}, links("Order.Item"))
+	assert.Equal(t, map[string]string{"gen/shop/v1::Order_Status": "type"}, links("Order.Status"))
+	assert.Empty(t, links("Card"), "no generated type")
+	assert.Equal(t, map[string]string{
+		"gen/shop/v1/shopv1connect::OrderServiceClient":               "client",
+		"gen/shop/v1/shopv1connect::OrderServiceHandler":              "handler",
+		"gen/shop/v1/shopv1connect::UnimplementedOrderServiceHandler": "handler",
+	}, links("OrderService"))
+	connect := "gen/shop/v1/shopv1connect/shop.connect.go"
+	assert.Equal(t, map[string]string{
+		connect + "::orderServiceClient.Place":               "client",
+		connect + "::UnimplementedOrderServiceHandler.Place": "handler",
+	}, links("OrderService.Place"))
+
+	// Deleting the proto file (and regenerating) removes its contract symbols and links
+	require.NoError(t, os.Remove(filepath.Join(rootDir, proto)))
+	createTestGoFile(t, rootDir, "gen/shop/v1/shop.pb.go", "package shopv1\n\ntype Order struct{}\n")
+	_, err = idx.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count("SELECT COUNT(*) FROM contract_symbols"))
+	assert.Equal(t, 0, count("SELECT COUNT(*) FROM contract_links"))
+}
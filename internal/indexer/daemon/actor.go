@@ -14,8 +14,10 @@ import (
 	"github.com/mvp-joe/project-cortex/internal/cache"
 	"github.com/mvp-joe/project-cortex/internal/config"
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/embedcache"
 	"github.com/mvp-joe/project-cortex/internal/git"
 	"github.com/mvp-joe/project-cortex/internal/indexer"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 	"github.com/mvp-joe/project-cortex/internal/watcher"
 )
 
@@ -52,6 +54,7 @@ type Actor struct {
 
 	// Cleanup resources
 	embedProvider embed.Provider
+	embedCache    *embedcache.Cache
 	db            *sql.DB
 }
 
@@ -111,6 +114,13 @@ func NewActor(ctx context.Context, projectPath string, embedProvider embed.Provi
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
+	// Select the vector index backend (rebuilds the index when the backend changes)
+	if _, err := storage.ConfigureVectorIndex(db, cfg.Storage.VectorIndex, cfg.Storage.VectorQuantization); err != nil {
+		cancel()
+		db.Close()
+		return nil, fmt.Errorf("failed to configure vector index: %w", err)
+	}
+
 	// Note: embedProvider is passed in as a parameter (dependency injection)
 
 	// Create indexer components (v2 architecture)
@@ -122,7 +132,7 @@ func NewActor(ctx context.Context, projectPath string, embedProvider embed.Provi
 		return nil, fmt.Errorf("failed to create storage: %w", err)
 	}
 
-	// Create file discovery
+	// Create file discovery (project files plus content sources outside it)
 	discovery, err := indexer.NewFileDiscovery(projectPath, indexerCfg.CodePatterns, indexerCfg.DocsPatterns, indexerCfg.IgnorePatterns)
 	if err != nil {
 		cancel()
@@ -130,20 +140,79 @@ func NewActor(ctx context.Context, projectPath string, embedProvider embed.Provi
 		db.Close()
 		return nil, fmt.Errorf("failed to create file discovery: %w", err)
 	}
+	contentSources, err := indexer.NewContentSources(projectPath, indexerCfg.ContentSources)
+	if err != nil {
+		cancel()
+		embedProvider.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to configure content sources: %w", err)
+	}
+	discovery.WithContentSources(contentSources)
 
 	// Create change detector
 	changeDetector := indexer.NewChangeDetector(projectPath, storage, discovery)
 
-	// Create parser, chunker, formatter
-	parser := indexer.NewParser()
+	// Create parser (built-in and configured external languages), chunker, formatter
+	languages, err := indexer.NewLanguageRegistry(indexerCfg.Languages)
+	if err != nil {
+		cancel()
+		embedProvider.Close()
+		db.Close()
+		return nil, fmt.Errorf("failed to configure languages: %w", err)
+	}
+	parser := indexer.NewParser(indexer.WithLanguageRegistry(languages))
 	chunker := indexer.NewChunker(indexerCfg.DocChunkSize, indexerCfg.Overlap)
 	formatter := indexer.NewFormatter()
 
-	// Create processor (no progress reporter - we'll handle progress internally)
-	processor := indexer.NewProcessor(projectPath, parser, chunker, formatter, embedProvider, storage, nil)
+	// Open the shared embedding cache (optional: indexing proceeds without it)
+	processorOpts := []indexer.ProcessorOption{indexer.WithPipelineConfig(indexerCfg.Pipeline)}
+	var embedCache *embedcache.Cache
+	if globalCfg, err := config.LoadGlobalConfig(); err != nil {
+		log.Printf("[%s] Warning: failed to load global config, embedding cache disabled: %v", filepath.Base(projectPath), err)
+	} else if globalCfg.EmbeddingCache.Enabled {
+		embedCache, err = embedcache.NewCache(globalCfg.EmbeddingCache.Path, globalCfg.EmbeddingCache.MaxSizeMB)
+		if err != nil {
+			log.Printf("[%s] Warning: embedding cache unavailable: %v", filepath.Base(projectPath), err)
+			embedCache = nil
+		} else {
+			processorOpts = append(processorOpts, indexer.WithEmbeddingCache(embedCache, cfg.Embedding.Model))
+		}
+	}
 
-	// Create v2 indexer
-	idx := indexer.NewIndexerV2(projectPath, changeDetector, processor, storage, db)
+	// Redact secrets before content is stored or embedded
+	var scanner *indexer.SecretScanner
+	if indexerCfg.ScanSecrets {
+		scanner, err = indexer.NewSecretScanner(indexerCfg.SecretPatterns)
+		if err != nil {
+			cancel()
+			embedProvider.Close()
+			db.Close()
+			if embedCache != nil {
+				embedCache.Close()
+			}
+			return nil, fmt.Errorf("failed to configure secret scanning: %w", err)
+		}
+		processorOpts = append(processorOpts, indexer.WithSecretScanner(scanner))
+	}
+
+	// Create processor (no progress reporter - we'll handle progress internally)
+	processor := indexer.NewProcessor(projectPath, parser, chunker, formatter, embedProvider, storage, nil,
+		append(processorOpts, indexer.WithContentSourcePaths(contentSources))...)
+
+	// Create v2 indexer (optionally indexing imported dependencies into the dependency corpus)
+	indexerOpts := []indexer.IndexerV2Option{
+		indexer.WithTypedCallGraph(indexerCfg.TypedCallGraph),
+		indexer.WithContentSources(contentSources),
+		indexer.WithLanguages(languages),
+		indexer.WithSecretRedaction(scanner),
+	}
+	if indexerCfg.DependencyCorpus {
+		corpus := indexer.NewDependencyCorpus(cacheSettings.CacheLocation, projectPath, func(rootDir string, s indexer.Storage) indexer.Processor {
+			return indexer.NewProcessor(rootDir, parser, chunker, formatter, embedProvider, s, nil, processorOpts...)
+		})
+		indexerOpts = append(indexerOpts, indexer.WithDependencyCorpus(corpus))
+	}
+	idx := indexer.NewIndexerV2(projectPath, changeDetector, processor, storage, db, indexerOpts...)
 
 	// Create Actor struct first (BranchWatcher needs a.handleBranchSwitch callback)
 	a := &Actor{
@@ -158,6 +227,7 @@ func NewActor(ctx context.Context, projectPath string, embedProvider embed.Provi
 		stopCh:        make(chan struct{}),
 		db:            db,
 		embedProvider: embedProvider,
+		embedCache:    embedCache,
 	}
 
 	// Create branch watcher with Actor's callback method
@@ -166,18 +236,25 @@ func NewActor(ctx context.Context, projectPath string, embedProvider embed.Provi
 		cancel()
 		embedProvider.Close()
 		db.Close()
+		if embedCache != nil {
+			embedCache.Close()
+		}
 		return nil, fmt.Errorf("failed to create branch watcher: %w", err)
 	}
 	a.branchWatcher = branchWatcher
 
-	// Create file watcher
+	// Create file watcher (the project and content sources with watch: true)
 	extensions := cfg.GetSourceExtensions()
-	fileWatcher, err := watcher.NewFileWatcher([]string{projectPath}, extensions)
+	watchDirs := append([]string{projectPath}, contentSources.WatchDirs()...)
+	fileWatcher, err := watcher.NewFileWatcher(watchDirs, extensions)
 	if err != nil {
 		cancel()
 		branchWatcher.Close()
 		embedProvider.Close()
 		db.Close()
+		if embedCache != nil {
+			embedCache.Close()
+		}
 		return nil, fmt.Errorf("failed to create file watcher: %w", err)
 	}
 	a.fileWatcher = fileWatcher
@@ -260,9 +337,15 @@ func (a *Actor) Stop() {
 	<-a.doneCh
 
 	// Clean up resources
+	if a.indexer != nil {
+		a.indexer.Close() // Closes the dependency corpus, if any
+	}
 	if a.embedProvider != nil {
 		a.embedProvider.Close()
 	}
+	if a.embedCache != nil {
+		a.embedCache.Close()
+	}
 	if a.db != nil {
 		a.db.Close()
 	}
@@ -368,9 +451,9 @@ func (a *Actor) handleFileChanges(files []string) {
 		return
 	}
 
-	log.Printf("[%s] Indexed %d files",
+	log.Printf("[%s] Indexed %d files (generation %d)",
 		filepath.Base(a.projectPath),
-		stats.CodeFilesProcessed+stats.DocsProcessed)
+		stats.CodeFilesProcessed+stats.DocsProcessed, stats.Generation)
 
 	// Broadcast progress
 	progress := statsToProgress(stats)
@@ -0,0 +1,213 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// DependencyCorpus indexes the sources of third-party packages the project
+// imports into a database separate from the branch database (see
+// storage.DependencyCorpusPath). Readers attach it to search dependencies
+// alongside the project.
+//
+// Files are stored under "<ecosystem>/<name>@<version>/", e.g.
+// "go/github.com/pkg/errors@v0.9.1/errors.go". They are read through
+// symlinks of the same names in a staging directory, so the regular
+// processor indexes them.
+type DependencyCorpus struct {
+	dbPath       string
+	stageDir     string
+	projectPath  string
+	newProcessor func(rootDir string, storage Storage) Processor
+
+	db      *sql.DB
+	storage Storage
+}
+
+// DependencyCorpusStats reports what a sync changed.
+type DependencyCorpusStats struct {
+	PackagesIndexed int
+	PackagesRemoved int
+	FilesIndexed    int
+}
+
+// NewDependencyCorpus creates a dependency corpus in a project's cache
+// location. newProcessor builds the processor that indexes staged package
+// files into the corpus storage.
+func NewDependencyCorpus(cacheLocation, projectPath string, newProcessor func(rootDir string, storage Storage) Processor) *DependencyCorpus {
+	return &DependencyCorpus{
+		dbPath:       storage.DependencyCorpusPath(cacheLocation),
+		stageDir:     filepath.Join(cacheLocation, "dependencies"),
+		projectPath:  projectPath,
+		newProcessor: newProcessor,
+	}
+}
+
+// Sync brings the corpus in line with the dependencies the project's code
+// imports, according to the committed dependency inventory in projectDB.
+// Packages whose sources are not on disk are left out; packages no longer
+// imported are removed. Changes are committed as one corpus generation.
+func (c *DependencyCorpus) Sync(ctx context.Context, projectDB *sql.DB) (*DependencyCorpusStats, error) {
+	sources, err := findDependencySources(projectDB, c.projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate dependency sources: %w", err)
+	}
+	if err := c.open(); err != nil {
+		return nil, err
+	}
+
+	stored, err := storage.ListCorpusPackages(c.db)
+	if err != nil {
+		return nil, err
+	}
+	storedByKey := make(map[string]storage.CorpusPackage, len(stored))
+	for _, p := range stored {
+		storedByKey[p.Ecosystem+"/"+p.Name] = p
+	}
+
+	// Packages to (re)index, and stored packages to drop first
+	var toIndex []dependencySource
+	var toRemove []storage.CorpusPackage
+	wanted := make(map[string]bool, len(sources))
+	for _, src := range sources {
+		key := src.Ecosystem + "/" + src.Name
+		wanted[key] = true
+		p, ok := storedByKey[key]
+		if ok && p.PathPrefix == src.pathPrefix() && p.Fingerprint == src.fingerprint() {
+			continue
+		}
+		if ok {
+			toRemove = append(toRemove, p)
+		}
+		toIndex = append(toIndex, src)
+	}
+	for _, p := range stored {
+		if !wanted[p.Ecosystem+"/"+p.Name] {
+			toRemove = append(toRemove, p)
+		}
+	}
+
+	stats := &DependencyCorpusStats{}
+	if len(toIndex) == 0 && len(toRemove) == 0 {
+		return stats, nil
+	}
+
+	gen, err := c.storage.BeginGeneration(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin corpus generation: %w", err)
+	}
+	defer gen.Close() // No-op once committed
+
+	for _, p := range toRemove {
+		if err := storage.WithTx(c.db, func(tx *sql.Tx) error {
+			return storage.DeleteCorpusPackage(tx, p)
+		}); err != nil {
+			return nil, err
+		}
+		if !wanted[p.Ecosystem+"/"+p.Name] {
+			stats.PackagesRemoved++
+		}
+	}
+
+	var files []string
+	for _, src := range toIndex {
+		staged, err := c.stage(src)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range src.Files {
+			files = append(files, filepath.Join(staged, filepath.FromSlash(f)))
+		}
+	}
+	if _, err := c.newProcessor(c.stageDir, c.storage).ProcessFiles(ctx, files); err != nil {
+		return nil, fmt.Errorf("failed to index dependency sources: %w", err)
+	}
+
+	for _, src := range toIndex {
+		p := storage.CorpusPackage{
+			Ecosystem:   src.Ecosystem,
+			Name:        src.Name,
+			Version:     src.Version,
+			PathPrefix:  src.pathPrefix(),
+			SourceDir:   src.Dir,
+			Fingerprint: src.fingerprint(),
+			FileCount:   len(src.Files),
+		}
+		if err := storage.WithTx(c.db, func(tx *sql.Tx) error {
+			return storage.PutCorpusPackage(tx, p)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := gen.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit corpus generation: %w", err)
+	}
+	stats.PackagesIndexed = len(toIndex)
+	stats.FilesIndexed = len(files)
+	return stats, nil
+}
+
+// open opens the corpus database on first use, creating its schema.
+func (c *DependencyCorpus) open() error {
+	if c.db != nil {
+		return nil
+	}
+
+	storage.InitVectorExtension()
+	if err := os.MkdirAll(filepath.Dir(c.dbPath), 0755); err != nil {
+		return fmt.Errorf("failed to create dependency corpus directory: %w", err)
+	}
+	db, err := sql.Open("sqlite3", c.dbPath+"?_foreign_keys=on&"+storage.WriterDSNParams)
+	if err != nil {
+		return fmt.Errorf("failed to open dependency corpus: %w", err)
+	}
+	// WAL lets attached readers keep reading while a sync writes
+	if _, err := db.Exec("PRAGMA journal_mode = WAL"); err != nil {
+		db.Close()
+		return fmt.Errorf("failed to enable WAL mode: %w", err)
+	}
+	st, err := NewSQLiteStorage(db, filepath.Dir(c.dbPath), c.projectPath)
+	if err != nil {
+		db.Close()
+		return err
+	}
+	c.db, c.storage = db, st
+	return nil
+}
+
+// stage links a package's directory into the staging directory under its
+// path prefix and returns the link.
+func (c *DependencyCorpus) stage(src dependencySource) (string, error) {
+	link := filepath.Join(c.stageDir, filepath.FromSlash(strings.TrimSuffix(src.pathPrefix(), "/")))
+	if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+		return "", fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	if target, err := os.Readlink(link); err == nil && target == src.Dir {
+		return link, nil
+	}
+	os.Remove(link)
+	if err := os.Symlink(src.Dir, link); err != nil {
+		return "", fmt.Errorf("failed to stage %s: %w", src.Name, err)
+	}
+	return link, nil
+}
+
+// Close closes the corpus database.
+func (c *DependencyCorpus) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	if err := c.storage.Close(); err != nil {
+		return err
+	}
+	err := c.db.Close()
+	c.db, c.storage = nil, nil
+	return err
+}
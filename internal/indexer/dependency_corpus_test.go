@@ -0,0 +1,129 @@
+package indexer
+
+// Test Plan for DependencyCorpus:
+// - Sync indexes the imported packages found in the Go module cache, node_modules and a virtual environment
+// - Go sources are limited to imported packages (no tests), npm sources to type declarations plus the README
+// - The installed npm version wins; files are stored under <ecosystem>/<name>@<version>/
+// - A second sync with nothing changed indexes nothing
+// - Packages whose sources disappear are removed with their files
+// - Module cache paths escape upper-case letters
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDependencyCorpus_Sync(t *testing.T) {
+	// Not parallel: sets GOMODCACHE and VIRTUAL_ENV
+	modCache := t.TempDir()
+	t.Setenv("GOMODCACHE", modCache)
+	t.Setenv("VIRTUAL_ENV", "")
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/app\n\ngo 1.22\n\nrequire github.com/google/uuid v1.6.0\n",
+		"main.go":               "package main\n\nimport \"github.com/google/uuid\"\n\nfunc main() { _ = uuid.New() }\n",
+		"web/package.json":      `{"name": "web", "dependencies": {"react": "^18.2.0"}}`,
+		"web/package-lock.json": `{"lockfileVersion": 3, "packages": {"": {}, "node_modules/react": {"version": "18.2.0"}}}`,
+		"web/src/app.ts":        "import React from \"react\";\n",
+		"py/pyproject.toml":     "[project]\nname = \"tools\"\ndependencies = [\"requests>=2\"]\n",
+		"py/tools/cli.py":       "import requests\n",
+
+		// Sources already on disk
+		"web/node_modules/react/package.json":                           `{"name": "react", "version": "18.3.1"}`,
+		"web/node_modules/react/README.md":                              "# React\n\nA library for building user interfaces.\n",
+		"web/node_modules/react/index.d.ts":                             "export declare function createElement(type: string): unknown;\n",
+		"web/node_modules/react/cjs/react.js":                           "module.exports = {};\n",
+		".venv/lib/python3.12/site-packages/requests/__init__.py":       "def get(url):\n    \"\"\"Send a GET request.\"\"\"\n    return None\n",
+		".venv/lib/python3.12/site-packages/requests/tests/test_get.py": "def test_get():\n    pass\n",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+	uuidDir := filepath.Join(modCache, "github.com", "google", "uuid@v1.6.0")
+	writeGoFile(t, filepath.Join(uuidDir, "uuid.go"), "package uuid\n\n// New creates a new random UUID.\nfunc New() string { return \"\" }\n")
+	writeGoFile(t, filepath.Join(uuidDir, "uuid_test.go"), "package uuid\n")
+	writeGoFile(t, filepath.Join(uuidDir, "README.md"), "# uuid\n\nGenerates and inspects UUIDs.\n")
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"main.go", "web/src/app.ts", "py/tools/cli.py"}}))
+
+	cacheDir := t.TempDir()
+	corpus := NewDependencyCorpus(cacheDir, rootDir, func(corpusRoot string, s Storage) Processor {
+		return createTestProcessor(t, corpusRoot, s)
+	})
+	defer corpus.Close()
+
+	stats, err := corpus.Sync(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.PackagesIndexed)
+	assert.Equal(t, 0, stats.PackagesRemoved)
+
+	packages, err := storage.ListCorpusPackages(corpus.db)
+	require.NoError(t, err)
+	prefixes := make([]string, 0, len(packages))
+	for _, p := range packages {
+		prefixes = append(prefixes, p.PathPrefix)
+	}
+	assert.Equal(t, []string{
+		"go/github.com/google/uuid@v1.6.0/",
+		"npm/react@18.3.1/",
+		"python/requests/",
+	}, prefixes)
+
+	indexedFiles := func() []string {
+		rows, err := corpus.db.Query("SELECT file_path FROM files ORDER BY file_path")
+		require.NoError(t, err)
+		defer rows.Close()
+		var paths []string
+		for rows.Next() {
+			var p string
+			require.NoError(t, rows.Scan(&p))
+			paths = append(paths, p)
+		}
+		require.NoError(t, rows.Err())
+		return paths
+	}
+	assert.Equal(t, []string{
+		"go/github.com/google/uuid@v1.6.0/README.md",
+		"go/github.com/google/uuid@v1.6.0/uuid.go",
+		"npm/react@18.3.1/README.md",
+		"npm/react@18.3.1/index.d.ts",
+		"python/requests/__init__.py",
+	}, indexedFiles())
+
+	var chunks int
+	require.NoError(t, corpus.db.QueryRow("SELECT COUNT(*) FROM chunks WHERE file_path LIKE 'go/%'").Scan(&chunks))
+	assert.Positive(t, chunks)
+
+	// Nothing changed
+	stats, err = corpus.Sync(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, &DependencyCorpusStats{}, stats)
+
+	// Uninstalled packages are removed
+	require.NoError(t, os.RemoveAll(filepath.Join(rootDir, "web", "node_modules")))
+	stats, err = corpus.Sync(context.Background(), db)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.PackagesRemoved)
+	assert.NotContains(t, indexedFiles(), "npm/react@18.3.1/index.d.ts")
+	require.NoError(t, corpus.db.QueryRow("SELECT COUNT(*) FROM chunks WHERE file_path LIKE 'npm/%'").Scan(&chunks))
+	assert.Zero(t, chunks)
+}
+
+func TestEscapeModulePath(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "github.com/!burnt!sushi/toml", escapeModulePath("github.com/BurntSushi/toml"))
+	assert.Equal(t, "golang.org/x/mod", escapeModulePath("golang.org/x/mod"))
+}
@@ -0,0 +1,357 @@
+package indexer
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// maxDependencySourceFiles caps the files indexed per dependency package.
+const maxDependencySourceFiles = 500
+
+// dependencySource is the on-disk source of a dependency package the
+// project imports.
+type dependencySource struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	Dir       string   // Package root on disk
+	Files     []string // Files to index, slash-separated and relative to Dir
+}
+
+// pathPrefix returns the corpus path the package's files are stored under.
+func (s dependencySource) pathPrefix() string {
+	if s.Version == "" {
+		return s.Ecosystem + "/" + s.Name + "/"
+	}
+	return s.Ecosystem + "/" + s.Name + "@" + s.Version + "/"
+}
+
+// fingerprint identifies the package's directory and file set.
+func (s dependencySource) fingerprint() string {
+	h := sha256.New()
+	h.Write([]byte(s.Dir))
+	for _, f := range s.Files {
+		h.Write([]byte{0})
+		h.Write([]byte(f))
+	}
+	return hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// findDependencySources locates the sources of the dependencies the
+// project's code imports, from the dependency inventory and what is already
+// on disk: the Go module cache, node_modules and Python virtual
+// environments. Dependencies without local sources are skipped.
+func findDependencySources(db *sql.DB, rootDir string) ([]dependencySource, error) {
+	imports, err := storage.ListDependencyImports(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group import paths and manifests by dependency
+	type dependencyKey struct{ ecosystem, name, version string }
+	var keys []dependencyKey
+	importPaths := make(map[dependencyKey][]string)
+	manifests := make(map[dependencyKey][]string)
+	for _, imp := range imports {
+		key := dependencyKey{imp.Ecosystem, imp.Name, imp.Version}
+		if _, ok := importPaths[key]; !ok {
+			keys = append(keys, key)
+		}
+		importPaths[key] = appendUnique(importPaths[key], imp.ImportPath)
+		manifests[key] = appendUnique(manifests[key], imp.ManifestPath)
+	}
+
+	var sources []dependencySource
+	seen := make(map[string]bool) // ecosystem/name: one version per package
+	for _, key := range keys {
+		if seen[key.ecosystem+"/"+key.name] {
+			continue
+		}
+		var (
+			src dependencySource
+			ok  bool
+		)
+		switch key.ecosystem {
+		case "go":
+			src, ok = goModuleSource(key.name, key.version, importPaths[key])
+		case "npm":
+			src, ok = npmPackageSource(rootDir, key.name, key.version, manifests[key])
+		case "python":
+			src, ok = pythonPackageSource(rootDir, key.name, key.version, importPaths[key], manifests[key])
+		}
+		if !ok || len(src.Files) == 0 {
+			continue
+		}
+		seen[key.ecosystem+"/"+key.name] = true
+		sources = append(sources, src)
+	}
+	return sources, nil
+}
+
+// goModuleSource returns the imported packages of a module in the Go module
+// cache: their non-test Go files and the module's README.
+func goModuleSource(module, version string, importPaths []string) (dependencySource, bool) {
+	if version == "" {
+		return dependencySource{}, false
+	}
+	dir := filepath.Join(goModCache(), escapeModulePath(module)+"@"+escapeModulePath(version))
+	if !isDir(dir) {
+		return dependencySource{}, false
+	}
+
+	files := readmeFiles(dir)
+	for _, imp := range importPaths {
+		sub := strings.TrimPrefix(strings.TrimPrefix(imp, module), "/")
+		entries, err := os.ReadDir(filepath.Join(dir, filepath.FromSlash(sub)))
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name := e.Name()
+			if e.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+				continue
+			}
+			files = append(files, path.Join(sub, name))
+		}
+	}
+	return dependencySource{Ecosystem: "go", Name: module, Version: version, Dir: dir, Files: capFiles(files)}, true
+}
+
+// goModCache returns the Go module cache directory ($GOMODCACHE, else the
+// first $GOPATH entry's pkg/mod, else ~/go/pkg/mod).
+func goModCache() string {
+	if dir := os.Getenv("GOMODCACHE"); dir != "" {
+		return dir
+	}
+	gopath := os.Getenv("GOPATH")
+	if gopath == "" {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, "go", "pkg", "mod")
+	}
+	return filepath.Join(filepath.SplitList(gopath)[0], "pkg", "mod")
+}
+
+// escapeModulePath applies the module cache's case encoding: each upper-case
+// letter becomes '!' followed by its lower-case form.
+func escapeModulePath(p string) string {
+	var b strings.Builder
+	for _, r := range p {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			b.WriteRune(unicode.ToLower(r))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// npmPackageSource returns the type declarations and README of a package
+// installed in the nearest node_modules of its manifests, falling back to
+// its DefinitelyTyped @types package when it ships no declarations. The
+// installed version wins over the inventory's.
+func npmPackageSource(rootDir, name, version string, manifests []string) (dependencySource, bool) {
+	for _, manifest := range manifests {
+		for _, dir := range ancestorDirs(rootDir, manifest) {
+			modules := filepath.Join(dir, "node_modules")
+			pkgDir := filepath.Join(modules, filepath.FromSlash(name))
+			if !isDir(pkgDir) {
+				continue
+			}
+			src := dependencySource{Ecosystem: "npm", Name: name, Version: version, Dir: pkgDir}
+			src.Files = collectFiles(pkgDir, func(rel string) bool { return strings.HasSuffix(rel, ".d.ts") })
+			if len(src.Files) == 0 {
+				typesDir := filepath.Join(modules, "@types", typesPackageName(name))
+				if !isDir(typesDir) {
+					return dependencySource{}, false
+				}
+				src.Dir = typesDir
+				src.Files = collectFiles(typesDir, func(rel string) bool { return strings.HasSuffix(rel, ".d.ts") })
+			}
+			if installed := packageJSONVersion(pkgDir); installed != "" {
+				src.Version = installed
+			}
+			src.Files = capFiles(append(readmeFiles(src.Dir), src.Files...))
+			return src, true
+		}
+	}
+	return dependencySource{}, false
+}
+
+// typesPackageName maps a package to its DefinitelyTyped name
+// ("@scope/pkg" → "scope__pkg").
+func typesPackageName(name string) string {
+	if scope, pkg, ok := strings.Cut(strings.TrimPrefix(name, "@"), "/"); ok && strings.HasPrefix(name, "@") {
+		return scope + "__" + pkg
+	}
+	return name
+}
+
+// packageJSONVersion reads the version of an installed npm package.
+func packageJSONVersion(dir string) string {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return ""
+	}
+	var pkg struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(data, &pkg) != nil {
+		return ""
+	}
+	return pkg.Version
+}
+
+// pythonPackageSource returns the Python sources and stubs of a
+// distribution's top-level import package in the site-packages of a virtual
+// environment: $VIRTUAL_ENV, or .venv, venv or env next to a manifest or in
+// a directory above it.
+func pythonPackageSource(rootDir, name, version string, importPaths, manifests []string) (dependencySource, bool) {
+	var packages []string
+	for _, imp := range importPaths {
+		top, _, _ := strings.Cut(imp, ".")
+		packages = appendUnique(packages, top)
+	}
+
+	for _, site := range sitePackagesDirs(rootDir, manifests) {
+		for _, pkg := range packages {
+			pkgDir := filepath.Join(site, pkg)
+			if isDir(pkgDir) {
+				files := collectFiles(pkgDir, func(rel string) bool {
+					return (strings.HasSuffix(rel, ".py") || strings.HasSuffix(rel, ".pyi")) && !isPythonTestPath(rel)
+				})
+				return dependencySource{Ecosystem: "python", Name: name, Version: version, Dir: pkgDir, Files: capFiles(files)}, true
+			}
+			for _, ext := range []string{".py", ".pyi"} {
+				if _, err := os.Stat(filepath.Join(site, pkg+ext)); err == nil {
+					return dependencySource{Ecosystem: "python", Name: name, Version: version, Dir: site, Files: []string{pkg + ext}}, true
+				}
+			}
+		}
+	}
+	return dependencySource{}, false
+}
+
+// sitePackagesDirs returns the site-packages directories of the virtual
+// environments a project's Python manifests may use.
+func sitePackagesDirs(rootDir string, manifests []string) []string {
+	var envs []string
+	if env := os.Getenv("VIRTUAL_ENV"); env != "" {
+		envs = append(envs, env)
+	}
+	for _, manifest := range manifests {
+		for _, dir := range ancestorDirs(rootDir, manifest) {
+			for _, name := range []string{".venv", "venv", "env"} {
+				envs = appendUnique(envs, filepath.Join(dir, name))
+			}
+		}
+	}
+
+	var dirs []string
+	for _, env := range envs {
+		matches, _ := filepath.Glob(filepath.Join(env, "lib", "python*", "site-packages"))
+		sort.Sort(sort.Reverse(sort.StringSlice(matches))) // Newest Python first
+		matches = append(matches, filepath.Join(env, "Lib", "site-packages"))
+		for _, m := range matches {
+			if isDir(m) {
+				dirs = append(dirs, m)
+			}
+		}
+	}
+	return dirs
+}
+
+// isPythonTestPath reports whether a file belongs to a package's tests.
+func isPythonTestPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if part == "tests" || part == "test" || strings.HasPrefix(part, "test_") {
+			return true
+		}
+	}
+	return false
+}
+
+// ancestorDirs returns the directory of a manifest (relative to rootDir) and
+// each directory above it up to rootDir, nearest first.
+func ancestorDirs(rootDir, manifest string) []string {
+	var dirs []string
+	for dir := path.Dir(manifest); ; dir = path.Dir(dir) {
+		dirs = append(dirs, filepath.Join(rootDir, filepath.FromSlash(dir)))
+		if dir == "." || dir == "/" {
+			return dirs
+		}
+	}
+}
+
+// collectFiles walks a package directory for matching files, skipping
+// hidden directories and nested node_modules.
+func collectFiles(dir string, match func(rel string) bool) []string {
+	var files []string
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != dir && (strings.HasPrefix(d.Name(), ".") || d.Name() == "node_modules" || d.Name() == "__pycache__") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return nil
+		}
+		rel = filepath.ToSlash(rel)
+		if match(rel) {
+			files = append(files, rel)
+		}
+		if len(files) >= maxDependencySourceFiles {
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return files
+}
+
+// readmeFiles returns the Markdown README at the root of a package.
+func readmeFiles(dir string) []string {
+	for _, name := range []string{"README.md", "readme.md", "Readme.md", "README.markdown"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return []string{name}
+		}
+	}
+	return nil
+}
+
+// capFiles limits a package to maxDependencySourceFiles files.
+func capFiles(files []string) []string {
+	if len(files) > maxDependencySourceFiles {
+		return files[:maxDependencySourceFiles]
+	}
+	return files
+}
+
+func isDir(p string) bool {
+	info, err := os.Stat(p)
+	return err == nil && info.IsDir()
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, existing := range list {
+		if existing == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
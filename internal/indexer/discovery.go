@@ -20,6 +20,7 @@ type FileDiscovery struct {
 	codePatterns   []compiledPattern
 	docsPatterns   []compiledPattern
 	ignorePatterns []compiledPattern
+	sources        *ContentSources // Optional content sources outside rootDir
 }
 
 // NewFileDiscovery creates a new file discovery instance.
@@ -56,7 +57,16 @@ func NewFileDiscovery(rootDir string, codePatterns, docsPatterns, ignorePatterns
 	return fd, nil
 }
 
+// WithContentSources adds the files of content sources outside the root
+// directory to DiscoverFiles.
+func (fd *FileDiscovery) WithContentSources(sources *ContentSources) *FileDiscovery {
+	fd.sources = sources
+	return fd
+}
+
 // DiscoverFiles walks the directory tree and returns code and doc files.
+// Files of content sources are returned with the code files (the processor
+// tells documentation apart by extension).
 func (fd *FileDiscovery) DiscoverFiles() (codeFiles []string, docFiles []string, err error) {
 	codeFiles = []string{}
 	docFiles = []string{}
@@ -99,7 +109,12 @@ func (fd *FileDiscovery) DiscoverFiles() (codeFiles []string, docFiles []string,
 
 		return nil
 	})
+	if err != nil {
+		return codeFiles, docFiles, err
+	}
 
+	sourceFiles, err := fd.sources.DiscoverFiles()
+	codeFiles = append(codeFiles, sourceFiles...)
 	return codeFiles, docFiles, err
 }
 
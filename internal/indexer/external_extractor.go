@@ -0,0 +1,346 @@
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+)
+
+// ExternalProtocolVersion is the version of the external extractor protocol,
+// sent with every request.
+const ExternalProtocolVersion = 1
+
+// externalExtractorTimeout bounds a single extractor run.
+const externalExtractorTimeout = 30 * time.Second
+
+// ExternalLanguageConfig configures a language extracted by an external
+// command (see config.LanguageConfig).
+type ExternalLanguageConfig struct {
+	Name       string
+	Extensions []string // File extensions with leading dot
+	Filenames  []string // Base name patterns
+	Shebangs   []string // Interpreters named on a "#!" line
+	Command    []string // Extractor executable and arguments
+	Graph      bool     // The extractor returns graph data
+}
+
+// externalExtractor runs an external command per file: the request is
+// written to its stdin as JSON and the extraction read from its stdout.
+//
+// Files of graph languages are parsed twice per index run, by the processor
+// and by the graph updater (both use the same registry). The extraction of
+// the first parse is kept until the second, keyed by content hash, so the
+// command runs once per file. Extractions the graph updater didn't ask for
+// are dropped at the end of the run (see endRun).
+type externalExtractor struct {
+	language string
+	command  []string
+	graph    bool
+
+	mu     sync.Mutex
+	cached map[string]cachedExtraction // File path → extraction not yet reused
+}
+
+// cachedExtraction is an extraction of a file's content.
+type cachedExtraction struct {
+	hash       [sha256.Size]byte
+	extraction *CodeExtraction
+}
+
+// NewExternalExtractor returns an extractor that runs command for each file
+// of language. With graph, the extractor is asked for graph data too.
+func NewExternalExtractor(language string, command []string, graph bool) LanguageExtractor {
+	return &externalExtractor{language: language, command: command, graph: graph, cached: make(map[string]cachedExtraction)}
+}
+
+// externalRequest is written to the extractor's stdin.
+type externalRequest struct {
+	Protocol int    `json:"protocol"`
+	Language string `json:"language"`
+	Path     string `json:"path"`    // Absolute path of the file
+	Content  string `json:"content"` // File content
+	Graph    bool   `json:"graph"`   // Graph data is wanted
+}
+
+// externalResponse is read from the extractor's stdout.
+type externalResponse struct {
+	Error       string               `json:"error,omitempty"` // Extraction failed
+	Package     string               `json:"package"`
+	StartLine   int                  `json:"start_line"`
+	EndLine     int                  `json:"end_line"`
+	Types       []externalSymbol     `json:"types"`
+	Functions   []externalSymbol     `json:"functions"`
+	Imports     []externalImport     `json:"imports"`
+	Relations   []externalRelation   `json:"relations"`
+	Definitions []externalDefinition `json:"definitions"`
+	Constants   []externalValue      `json:"constants"`
+	Variables   []externalValue      `json:"variables"`
+	Graph       *ExtractedGraph      `json:"graph,omitempty"`
+}
+
+type externalSymbol struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Signature string `json:"signature,omitempty"`
+	Doc       string `json:"doc,omitempty"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+type externalImport struct {
+	Path string `json:"path"`
+	Line int    `json:"line"`
+}
+
+type externalRelation struct {
+	Type      string `json:"type"`
+	Supertype string `json:"supertype"`
+	Kind      string `json:"kind"` // "extends", "implements" or "mixin"
+	Line      int    `json:"line"`
+}
+
+type externalDefinition struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Code      string `json:"code"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+type externalValue struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Type      string `json:"type"`
+	Doc       string `json:"doc,omitempty"`     // Constants only
+	Section   string `json:"section,omitempty"` // Constants only
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// ExtractedGraph is the code graph data of a file, as returned by external
+// extractors. Methods are functions with a receiver; calls name their
+// caller the way functions are named ("Name" or "Receiver.Name").
+type ExtractedGraph struct {
+	Types      []ExtractedType      `json:"types"`
+	Functions  []ExtractedFunction  `json:"functions"`
+	Calls      []ExtractedCall      `json:"calls"`
+	ConfigKeys []ExtractedConfigKey `json:"config_keys,omitempty"` // Configuration files only
+}
+
+// ExtractedType is a type (class, interface, struct, ...) of ExtractedGraph.
+type ExtractedType struct {
+	Name      string             `json:"name"`
+	Kind      string             `json:"kind"`
+	StartLine int                `json:"start_line"`
+	EndLine   int                `json:"end_line"`
+	Exported  bool               `json:"exported"`
+	Doc       string             `json:"doc,omitempty"`
+	Fields    []ExtractedField   `json:"fields,omitempty"`
+	Contract  *ExtractedContract `json:"contract,omitempty"` // Set for API contract types
+}
+
+// ExtractedField is a field or method of an ExtractedType.
+type ExtractedField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type,omitempty"`
+	Method   bool   `json:"method,omitempty"`
+	Exported bool   `json:"exported,omitempty"`
+}
+
+// ExtractedFunction is a function or method of ExtractedGraph.
+type ExtractedFunction struct {
+	Name      string             `json:"name"`
+	Receiver  string             `json:"receiver,omitempty"` // Declaring type of a method
+	StartLine int                `json:"start_line"`
+	EndLine   int                `json:"end_line"`
+	Exported  bool               `json:"exported"`
+	Doc       string             `json:"doc,omitempty"`
+	Contract  *ExtractedContract `json:"contract,omitempty"` // Set for API contract operations
+}
+
+// ExtractedContract tags an ExtractedType or ExtractedFunction as part of an
+// API contract (see storage.ContractSymbol).
+type ExtractedContract struct {
+	Protocol  string `json:"protocol"`             // "protobuf", "openapi", "graphql", ...
+	Kind      string `json:"kind"`                 // "message", "rpc", "operation", "query", ...
+	Name      string `json:"name"`                 // Qualified name, e.g. "indexer.v1.IndexerService/Index"
+	GoPackage string `json:"go_package,omitempty"` // Import path of generated Go code
+}
+
+// ExtractedConfigKey is a flattened key path of a configuration file (see
+// storage.ConfigKey). Values are stored as returned, so secrets should be
+// masked by the extractor.
+type ExtractedConfigKey struct {
+	Document int    `json:"document,omitempty"` // Index of the document in multi-document files
+	Path     string `json:"path"`               // e.g. "spec.template.spec.containers[0].image"
+	Value    string `json:"value"`
+	Line     int    `json:"line"`
+}
+
+// ExtractedCall is a call from a function of ExtractedGraph.
+type ExtractedCall struct {
+	Caller string `json:"caller"` // "Name" or "Receiver.Name" of the calling function
+	Callee string `json:"callee"` // Called name, as written
+	Line   int    `json:"line"`
+}
+
+// ParseFile implements LanguageExtractor.
+func (e *externalExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return e.ParseSource(ctx, filePath, content)
+}
+
+// ParseSource implements sourceExtractor.
+func (e *externalExtractor) ParseSource(ctx context.Context, filePath string, content []byte) (*CodeExtraction, error) {
+	if len(e.command) == 0 {
+		return nil, fmt.Errorf("no extractor command for %s", e.language)
+	}
+
+	hash := sha256.Sum256(content)
+	if ext, ok := e.reuse(filePath, hash); ok {
+		return ext, nil
+	}
+
+	request, err := json.Marshal(externalRequest{
+		Protocol: ExternalProtocolVersion,
+		Language: e.language,
+		Path:     filePath,
+		Content:  string(content),
+		Graph:    e.graph,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode extractor request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, externalExtractorTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.command[0], e.command[1:]...)
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%s extractor failed: %w: %s", e.language, err, msg)
+		}
+		return nil, fmt.Errorf("%s extractor failed: %w", e.language, err)
+	}
+
+	var response externalResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, fmt.Errorf("%s extractor returned invalid JSON: %w", e.language, err)
+	}
+	if response.Error != "" {
+		return nil, fmt.Errorf("%s extractor: %s", e.language, response.Error)
+	}
+	result := response.codeExtraction(e.language, filePath, e.graph)
+	if e.graph {
+		e.mu.Lock()
+		e.cached[filePath] = cachedExtraction{hash: hash, extraction: result}
+		e.mu.Unlock()
+	}
+	return result, nil
+}
+
+// endRun implements runExtractor.
+func (e *externalExtractor) endRun() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clear(e.cached)
+}
+
+// reuse returns (and forgets) the cached extraction of the file if its
+// content hasn't changed since.
+func (e *externalExtractor) reuse(filePath string, hash [sha256.Size]byte) (*CodeExtraction, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	cached, ok := e.cached[filePath]
+	if !ok {
+		return nil, false
+	}
+	delete(e.cached, filePath)
+	return cached.extraction, cached.hash == hash
+}
+
+// codeExtraction converts the response to a CodeExtraction. Graph data is
+// dropped unless it was requested.
+func (r *externalResponse) codeExtraction(language, filePath string, graph bool) *CodeExtraction {
+	result := &CodeExtraction{
+		Language:  language,
+		FilePath:  filePath,
+		StartLine: r.StartLine,
+		EndLine:   r.EndLine,
+		Symbols: &extraction.SymbolsData{
+			PackageName:  r.Package,
+			ImportsCount: len(r.Imports),
+			Types:        []extraction.SymbolInfo{},
+			Functions:    []extraction.SymbolInfo{},
+		},
+		Definitions: &extraction.DefinitionsData{
+			Definitions: []extraction.Definition{},
+		},
+		Data: &extraction.DataData{
+			Constants: []extraction.ConstantInfo{},
+			Variables: []extraction.VariableInfo{},
+		},
+	}
+
+	for _, t := range r.Types {
+		result.Symbols.Types = append(result.Symbols.Types, extraction.SymbolInfo{
+			Name: t.Name, Type: t.Kind, StartLine: t.StartLine, EndLine: t.EndLine, Signature: t.Signature, Doc: t.Doc,
+		})
+	}
+	for _, f := range r.Functions {
+		kind := f.Kind
+		if kind == "" {
+			kind = "function"
+		}
+		result.Symbols.Functions = append(result.Symbols.Functions, extraction.SymbolInfo{
+			Name: f.Name, Type: kind, StartLine: f.StartLine, EndLine: f.EndLine, Signature: f.Signature, Doc: f.Doc,
+		})
+	}
+	for _, imp := range r.Imports {
+		result.Symbols.Imports = append(result.Symbols.Imports, extraction.ImportRef{Path: imp.Path, Line: imp.Line})
+	}
+	for _, rel := range r.Relations {
+		result.Symbols.Relations = append(result.Symbols.Relations, extraction.TypeRelation{
+			Type: rel.Type, Supertype: rel.Supertype, Kind: rel.Kind, Line: rel.Line,
+		})
+	}
+	for _, d := range r.Definitions {
+		result.Definitions.Definitions = append(result.Definitions.Definitions, extraction.Definition{
+			Name: d.Name, Type: d.Kind, Code: d.Code, StartLine: d.StartLine, EndLine: d.EndLine,
+		})
+	}
+	for _, c := range r.Constants {
+		result.Data.Constants = append(result.Data.Constants, extraction.ConstantInfo{
+			Name: c.Name, Value: c.Value, Type: c.Type, StartLine: c.StartLine, EndLine: c.EndLine, Doc: c.Doc, Section: c.Section,
+		})
+	}
+	for _, v := range r.Variables {
+		result.Data.Variables = append(result.Data.Variables, extraction.VariableInfo{
+			Name: v.Name, Value: v.Value, Type: v.Type, StartLine: v.StartLine, EndLine: v.EndLine,
+		})
+	}
+
+	if graph {
+		result.Graph = r.Graph
+		if result.Graph == nil {
+			result.Graph = &ExtractedGraph{}
+		}
+	}
+	return result
+}
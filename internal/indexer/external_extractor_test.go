@@ -0,0 +1,241 @@
+package indexer
+
+// Test Plan for External Extractors:
+// - The request carries protocol version, language, path, content and whether graph data is wanted
+// - The response's symbols, definitions and data become the three-tier extraction
+// - Graph data is returned only when requested
+// - Graph extractions are reused once for unchanged content, so each file is extracted once per run
+// - Extractions not reused by the end of the run are dropped
+// - Failing commands report their stderr; error responses and invalid JSON are errors
+// - An indexing run with a graph-enabled external language writes its chunks and its types,
+//   functions, calls and imports to the graph, running the extractor once
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kotlinExtraction = `{
+  "package": "com.example.users",
+  "start_line": 1,
+  "end_line": 12,
+  "types": [{"name": "UserService", "kind": "class", "start_line": 5, "end_line": 12}],
+  "functions": [{"name": "find", "kind": "method", "signature": "fun find(id: Int): User", "doc": "Finds a user.", "start_line": 6, "end_line": 9}],
+  "imports": [{"path": "com.example.db", "line": 3}],
+  "relations": [{"type": "UserService", "supertype": "Service", "kind": "implements", "line": 5}],
+  "definitions": [{"name": "UserService", "kind": "class", "code": "class UserService : Service { ... }", "start_line": 5, "end_line": 12}],
+  "constants": [{"name": "MAX_USERS", "value": "100", "type": "Int", "start_line": 3, "end_line": 3}],
+  "graph": {
+    "types": [{"name": "UserService", "kind": "class", "start_line": 5, "end_line": 12, "exported": true,
+               "fields": [{"name": "db", "type": "Database"}, {"name": "find", "method": true, "exported": true}]}],
+    "functions": [{"name": "find", "receiver": "UserService", "start_line": 6, "end_line": 9, "exported": true}],
+    "calls": [{"caller": "UserService.find", "callee": "query", "line": 7}, {"caller": "main", "callee": "run", "line": 11}]
+  }
+}`
+
+// writeExtractor writes a shell script extractor that saves its request to
+// request.json next to it, adds a line to runs and prints output.
+func writeExtractor(t *testing.T, output string, exitCode int) (command []string, requestPath string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("extractor scripts need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	requestPath = filepath.Join(dir, "request.json")
+	outputPath := filepath.Join(dir, "output")
+	require.NoError(t, os.WriteFile(outputPath, []byte(output), 0644))
+	runsPath := filepath.Join(dir, "runs")
+	script := "#!/bin/sh\ncat > '" + requestPath + "'\necho run >> '" + runsPath + "'\ncat '" + outputPath + "'\n"
+	if exitCode != 0 {
+		script = "#!/bin/sh\ncat > /dev/null\necho 'parse error at line 2' >&2\nexit 3\n"
+	}
+	scriptPath := filepath.Join(dir, "extractor")
+	require.NoError(t, os.WriteFile(scriptPath, []byte(script), 0755))
+	return []string{scriptPath}, requestPath
+}
+
+func TestExternalExtractor_ParseFile(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := filepath.Join(t.TempDir(), "UserService.kt")
+	require.NoError(t, os.WriteFile(source, []byte("package com.example.users\n"), 0644))
+	command, requestPath := writeExtractor(t, kotlinExtraction, 0)
+
+	ext, err := NewExternalExtractor("kotlin", command, true).ParseFile(ctx, source)
+	require.NoError(t, err)
+
+	var request map[string]any
+	data, err := os.ReadFile(requestPath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &request))
+	assert.Equal(t, map[string]any{
+		"protocol": float64(ExternalProtocolVersion),
+		"language": "kotlin",
+		"path":     source,
+		"content":  "package com.example.users\n",
+		"graph":    true,
+	}, request)
+
+	assert.Equal(t, "kotlin", ext.Language)
+	assert.Equal(t, 12, ext.EndLine)
+	assert.Equal(t, "com.example.users", ext.Symbols.PackageName)
+	assert.Equal(t, 1, ext.Symbols.ImportsCount)
+	assert.Equal(t, "UserService", ext.Symbols.Types[0].Name)
+	assert.Equal(t, "fun find(id: Int): User", ext.Symbols.Functions[0].Signature)
+	assert.Equal(t, "Finds a user.", ext.Symbols.Functions[0].Doc)
+	assert.Equal(t, "Service", ext.Symbols.Relations[0].Supertype)
+	assert.Equal(t, "class UserService : Service { ... }", ext.Definitions.Definitions[0].Code)
+	assert.Equal(t, "100", ext.Data.Constants[0].Value)
+	require.NotNil(t, ext.Graph)
+	assert.Len(t, ext.Graph.Calls, 2)
+
+	// Graph data not requested
+	ext, err = NewExternalExtractor("kotlin", command, false).ParseFile(ctx, source)
+	require.NoError(t, err)
+	assert.Nil(t, ext.Graph)
+}
+
+func TestExternalExtractor_ReusesGraphExtraction(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := filepath.Join(t.TempDir(), "UserService.kt")
+	require.NoError(t, os.WriteFile(source, []byte("package com.example.users\n"), 0644))
+	command, requestPath := writeExtractor(t, kotlinExtraction, 0)
+	runs := func() int {
+		data, err := os.ReadFile(filepath.Join(filepath.Dir(requestPath), "runs"))
+		require.NoError(t, err)
+		return strings.Count(string(data), "\n")
+	}
+
+	extractor := NewExternalExtractor("kotlin", command, true)
+	first, err := extractor.ParseFile(ctx, source)
+	require.NoError(t, err)
+	second, err := extractor.ParseFile(ctx, source)
+	require.NoError(t, err)
+	assert.Same(t, first, second)
+	assert.Equal(t, 1, runs())
+
+	// Reused once only
+	_, err = extractor.ParseFile(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs())
+
+	// Changed content is extracted again
+	require.NoError(t, os.WriteFile(source, []byte("package com.example.accounts\n"), 0644))
+	_, err = extractor.ParseFile(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, 3, runs())
+}
+
+func TestExternalExtractor_EndRunDropsExtractions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := filepath.Join(t.TempDir(), "UserService.kt")
+	require.NoError(t, os.WriteFile(source, []byte("package com.example.users\n"), 0644))
+	command, _ := writeExtractor(t, kotlinExtraction, 0)
+
+	languages, err := NewLanguageRegistry([]ExternalLanguageConfig{
+		{Name: "kotlin", Extensions: []string{".kt"}, Command: command, Graph: true},
+	})
+	require.NoError(t, err)
+	kotlin, ok := languages.Lookup("kotlin")
+	require.True(t, ok)
+	extractor := kotlin.Extractor.(*externalExtractor)
+
+	_, err = extractor.ParseFile(ctx, source)
+	require.NoError(t, err)
+	assert.Len(t, extractor.cached, 1)
+
+	languages.endRun()
+	assert.Empty(t, extractor.cached)
+}
+
+func TestExternalExtractor_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	source := filepath.Join(t.TempDir(), "main.dsl")
+	require.NoError(t, os.WriteFile(source, []byte("rule main\n"), 0644))
+
+	command, _ := writeExtractor(t, "", 3)
+	_, err := NewExternalExtractor("dsl", command, false).ParseFile(ctx, source)
+	assert.ErrorContains(t, err, "parse error at line 2")
+
+	command, _ = writeExtractor(t, `{"error": "unsupported syntax"}`, 0)
+	_, err = NewExternalExtractor("dsl", command, false).ParseFile(ctx, source)
+	assert.ErrorContains(t, err, "dsl extractor: unsupported syntax")
+
+	command, _ = writeExtractor(t, "not json", 0)
+	_, err = NewExternalExtractor("dsl", command, false).ParseFile(ctx, source)
+	assert.ErrorContains(t, err, "invalid JSON")
+
+	_, err = NewExternalExtractor("dsl", []string{filepath.Join(t.TempDir(), "missing")}, false).ParseFile(ctx, source)
+	assert.Error(t, err)
+}
+
+func TestIndexerV2_ExternalLanguageGraph(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	rootDir := t.TempDir()
+	createTestGoFile(t, rootDir, "src/UserService.kt", "package com.example.users\n")
+	command, requestPath := writeExtractor(t, kotlinExtraction, 0)
+
+	languages, err := NewLanguageRegistry([]ExternalLanguageConfig{
+		{Name: "kotlin", Extensions: []string{".kt"}, Command: command, Graph: true},
+	})
+	require.NoError(t, err)
+
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupIntegrationTestStorage(t, db, rootDir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	discovery, err := NewFileDiscovery(rootDir, []string{"**/*.kt"}, nil, nil)
+	require.NoError(t, err)
+	processor := NewProcessor(rootDir, NewParser(WithLanguageRegistry(languages)), NewChunker(500, 50), NewFormatter(),
+		&mockEmbedProvider{}, storage, &NoOpProgressReporter{})
+	idx := NewIndexerV2(rootDir, NewChangeDetector(rootDir, storage, discovery), processor, storage, db,
+		WithLanguages(languages))
+
+	stats, err := idx.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.CodeFilesProcessed)
+
+	count := func(query string, args ...any) int {
+		var n int
+		require.NoError(t, db.QueryRow(query, args...).Scan(&n))
+		return n
+	}
+	file := "src/UserService.kt"
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM files WHERE file_path = ? AND language = 'kotlin'", file))
+	assert.Positive(t, count("SELECT COUNT(*) FROM chunks WHERE file_path = ?", file))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM types WHERE type_id = ? AND field_count = 1 AND method_count = 1", file+"::UserService"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM functions WHERE function_id = ? AND receiver_type_id = ?",
+		file+"::UserService.find", file+"::UserService"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM function_calls WHERE caller_function_id = ? AND callee_name = 'query'",
+		file+"::UserService.find"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM function_calls"), "calls from undeclared functions are dropped")
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM imports WHERE file_path = ? AND import_path = 'com.example.db'", file))
+
+	runs, err := os.ReadFile(filepath.Join(filepath.Dir(requestPath), "runs"))
+	require.NoError(t, err)
+	assert.Equal(t, "run\n", string(runs), "the processor and the graph updater share one extraction")
+
+	kotlin, ok := languages.Lookup("kotlin")
+	require.True(t, ok)
+	assert.Empty(t, kotlin.Extractor.(*externalExtractor).cached, "no extraction is kept after the run")
+}
@@ -6,6 +6,11 @@ type SymbolsData struct {
 	ImportsCount int
 	Types        []SymbolInfo
 	Functions    []SymbolInfo
+	Relations    []TypeRelation // Declared supertypes (extends, implements, mixins)
+	Imports      []ImportRef    // Imported modules (TypeScript/JavaScript, Python, Rust)
+	Endpoints    []EndpointRef  // Registered HTTP routes (Express, Flask, Spring, Rails, ...)
+	ConfigReads  []ConfigRead   // Environment variables and configuration keys read
+	SQLQueries   []SQLQuery     // Embedded SQL statements and query builder chains
 }
 
 // SymbolInfo represents a symbol with its location.
@@ -15,6 +20,58 @@ type SymbolInfo struct {
 	StartLine int
 	EndLine   int
 	Signature string // For functions/methods
+	Doc       string // Doc comment or docstring, markers stripped
+	Cell      int    // Notebook cell (1-based) the lines are relative to; 0 outside notebooks
+}
+
+// TypeRelation is a supertype declared in source, e.g. "class A extends B".
+// Names are as written, with generic arguments and qualifiers stripped.
+type TypeRelation struct {
+	Type      string // Declaring type
+	Supertype string // Declared supertype
+	Kind      string // "extends", "implements", or "mixin"
+	Line      int    // Line of the supertype reference
+}
+
+// ImportRef is a module imported by a file, as written: "./util", "react",
+// "os.path", "..models", "crate::db::Pool". Each path appears once per file.
+type ImportRef struct {
+	Path string
+	Line int // Line of the first import of Path
+}
+
+// EndpointRef is an HTTP route registered in source: "app.get('/x', h)",
+// "@app.route('/x')", "@GetMapping("/x")", "get '/x', to: 'c#a'". Paths
+// have group prefixes (class mappings, Rails scopes) applied.
+type EndpointRef struct {
+	Framework    string // "express", "fastify", "flask", "fastapi", "django", "spring", "rails"
+	Method       string // GET, POST, ...; ANY when every method is accepted
+	Path         string
+	Handler      string // Handler as written: "users.list", "orders#index"
+	HandlerName  string // Function or method the handler resolves by
+	HandlerScope string // Object, class or controller the handler belongs to
+	Line         int
+}
+
+// ConfigRead is a read of an environment variable or configuration key:
+// "process.env.PORT", "os.environ['DATABASE_URL']", "@Value("${app.name}")".
+type ConfigRead struct {
+	Kind     string // "env" or "config"
+	Key      string
+	Source   string // API the key is read through: "process.env", "os.getenv", "ENV", ...
+	Function string // Enclosing function ("Class.method"), class for fields; empty at top level
+	Line     int
+}
+
+// SQLQuery is SQL embedded in code: a string literal that starts like a
+// statement, or the statement a query builder chain builds
+// ("knex('users').where(...)" as "SELECT * FROM users"). Interpolations
+// are replaced by "?".
+type SQLQuery struct {
+	Query    string
+	Source   string // "sql" for literals, else the query builder: "knex"
+	Function string // Enclosing function ("Class.method"), class for fields; empty at top level
+	Line     int    // Line the query starts on
 }
 
 // DefinitionsData represents type definitions and function signatures.
@@ -29,6 +86,7 @@ type Definition struct {
 	Code      string // The actual code
 	StartLine int
 	EndLine   int
+	Cell      int // Notebook cell (1-based) the lines are relative to; 0 outside notebooks
 }
 
 // DataData represents constants and configuration values.
@@ -44,6 +102,9 @@ type ConstantInfo struct {
 	Type      string
 	StartLine int
 	EndLine   int
+	Doc       string // Doc comment, markers stripped
+	Section   string // Top-level section of a configuration file; each has its own data chunk
+	Cell      int    // Notebook cell (1-based) the lines are relative to; 0 outside notebooks
 }
 
 // VariableInfo represents a global variable.
@@ -53,4 +114,5 @@ type VariableInfo struct {
 	Type      string
 	StartLine int
 	EndLine   int
+	Cell      int // Notebook cell (1-based) the lines are relative to; 0 outside notebooks
 }
@@ -33,7 +33,7 @@ func (f *formatter) FormatSymbols(data *extraction.SymbolsData, language string)
 	if len(data.Types) > 0 {
 		sb.WriteString("Types:\n")
 		for _, typ := range data.Types {
-			lineRange := formatLineRange(typ.StartLine, typ.EndLine)
+			lineRange := formatLocation(typ.Cell, typ.StartLine, typ.EndLine)
 			sb.WriteString(fmt.Sprintf("  - %s (%s) %s\n", typ.Name, typ.Type, lineRange))
 		}
 		sb.WriteString("\n")
@@ -43,7 +43,7 @@ func (f *formatter) FormatSymbols(data *extraction.SymbolsData, language string)
 	if len(data.Functions) > 0 {
 		sb.WriteString("Functions:\n")
 		for _, fn := range data.Functions {
-			lineRange := formatLineRange(fn.StartLine, fn.EndLine)
+			lineRange := formatLocation(fn.Cell, fn.StartLine, fn.EndLine)
 			if fn.Signature != "" {
 				sb.WriteString(fmt.Sprintf("  - %s %s\n", fn.Signature, lineRange))
 			} else {
@@ -65,7 +65,7 @@ func (f *formatter) FormatDefinitions(data *extraction.DefinitionsData, language
 		}
 
 		// Add line comment
-		lineRange := formatLineRange(def.StartLine, def.EndLine)
+		lineRange := formatLocation(def.Cell, def.StartLine, def.EndLine)
 		sb.WriteString(fmt.Sprintf("// %s\n", lineRange))
 
 		// Add code
@@ -86,7 +86,7 @@ func (f *formatter) FormatData(data *extraction.DataData, language string) strin
 				sb.WriteString("\n\n")
 			}
 
-			lineRange := formatLineRange(constant.StartLine, constant.EndLine)
+			lineRange := formatLocation(constant.Cell, constant.StartLine, constant.EndLine)
 			sb.WriteString(fmt.Sprintf("// %s\n", lineRange))
 
 			// Format based on language
@@ -105,7 +105,7 @@ func (f *formatter) FormatData(data *extraction.DataData, language string) strin
 				sb.WriteString("\n\n")
 			}
 
-			lineRange := formatLineRange(variable.StartLine, variable.EndLine)
+			lineRange := formatLocation(variable.Cell, variable.StartLine, variable.EndLine)
 			sb.WriteString(fmt.Sprintf("// %s\n", lineRange))
 
 			// Format based on language
@@ -123,6 +123,17 @@ func (f *formatter) FormatDocumentation(chunk *DocumentationChunk) string {
 	return strings.TrimSpace(chunk.Text)
 }
 
+// FormatDocstring formats a docstring below the signature of its symbol, so
+// that prose queries match the documentation and results show what it
+// documents.
+func (f *formatter) FormatDocstring(doc *Docstring, language string) string {
+	signature := doc.Signature
+	if signature == "" {
+		signature = fmt.Sprintf("%s (%s)", doc.Name, doc.Kind)
+	}
+	return fmt.Sprintf("%s %s\n\n%s", signature, formatLocation(doc.Cell, doc.StartLine, doc.EndLine), strings.TrimSpace(doc.Doc))
+}
+
 // formatLineRange formats line numbers into a human-readable range.
 func formatLineRange(start, end int) string {
 	if start == end {
@@ -131,6 +142,16 @@ func formatLineRange(start, end int) string {
 	return fmt.Sprintf("(lines %d-%d)", start, end)
 }
 
+// formatLocation formats line numbers, prefixed by the notebook cell they
+// are relative to if cell is not 0: "(cell 3, lines 2-5)".
+func formatLocation(cell, start, end int) string {
+	lineRange := formatLineRange(start, end)
+	if cell == 0 {
+		return lineRange
+	}
+	return fmt.Sprintf("(cell %d, %s", cell, lineRange[1:])
+}
+
 // formatConstant formats a constant based on the language.
 func formatConstant(c extraction.ConstantInfo, language string) string {
 	switch language {
@@ -14,6 +14,7 @@ import (
 // - FormatDefinitions creates code with line comments
 // - FormatData creates code with line comments for constants and variables
 // - FormatDocumentation returns markdown text as-is
+// - FormatDocstring puts the signature (or name and kind) above the doc
 // - Handles empty data gracefully
 // - Formats line ranges correctly (single line vs range)
 // - Language-specific formatting for constants and variables
@@ -271,3 +272,21 @@ func TestFormatter_FormatData_VariablesOnly(t *testing.T) {
 	require.NotEmpty(t, result)
 	assert.Contains(t, result, "var globalCache map[string]string = make(map[string]string)")
 }
+
+func TestFormatter_FormatDocstring(t *testing.T) {
+	t.Parallel()
+
+	formatter := NewFormatter()
+
+	// Test: Functions and constants show their signature, types their name and kind
+	result := formatter.FormatDocstring(&Docstring{
+		Name: "Do", Kind: "function", Signature: "func Do(fn func() error) error",
+		Doc: "Do calls fn until it succeeds.", StartLine: 20, EndLine: 24,
+	}, "go")
+	assert.Equal(t, "func Do(fn func() error) error (lines 20-24)\n\nDo calls fn until it succeeds.", result)
+
+	result = formatter.FormatDocstring(&Docstring{
+		Name: "Policy", Kind: "struct", Doc: "Policy configures retries.", StartLine: 3, EndLine: 3,
+	}, "go")
+	assert.Equal(t, "Policy (struct) (line 3)\n\nPolicy configures retries.", result)
+}
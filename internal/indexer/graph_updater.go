@@ -3,6 +3,7 @@ package indexer
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log"
 	"path/filepath"
@@ -13,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/mvp-joe/project-cortex/internal/graph"
 	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/mvp-joe/project-cortex/internal/workspace"
 )
 
 // GraphUpdater coordinates incremental graph updates based on file changes.
@@ -21,7 +23,59 @@ type GraphUpdater struct {
 	db         *sql.DB
 	extractor  graph.Extractor
 	inferencer *storage.InterfaceInferencer
+	parser     Parser              // Extracts declared type hierarchies from non-Go files
+	languages  *LanguageRegistry   // Languages of parser
+	calls      *graph.CallResolver // Optional type-checked Go call graph (nil: syntactic calls)
 	rootDir    string
+	roots      contentSourceRoots // Files on disk of project and content source paths
+	secrets    *SecretScanner     // Optional: redacts docs and config values (nil: stored as extracted)
+}
+
+// hierarchyLanguages are the non-Go languages whose types and declared
+// supertypes (extends, implements, trait impls, mixins) are added to the graph.
+var hierarchyLanguages = map[string]bool{
+	"typescript": true,
+	"java":       true,
+	"php":        true,
+	"rust":       true,
+	"cpp":        true,
+}
+
+// importLanguages are the non-Go languages whose imports are added to the
+// graph (Go imports come from the graph extractor).
+var importLanguages = map[string]bool{
+	"typescript":   true,
+	"javascript":   true,
+	"python":       true,
+	"rust":         true,
+	"c":            true,
+	"cpp":          true,
+	languageVue:    true,
+	languageSvelte: true,
+}
+
+// endpointLanguages are the non-Go languages whose registered routes
+// (Express, Flask, Django, Spring, Rails) are added to the graph.
+var endpointLanguages = map[string]bool{
+	"typescript": true,
+	"javascript": true,
+	"python":     true,
+	"java":       true,
+	"ruby":       true,
+}
+
+// manifestFiles are the files workspace detection reads; changing one can
+// change any file's module or import resolution.
+var manifestFiles = map[string]bool{
+	"go.mod":                true,
+	"go.work":               true,
+	"package.json":          true,
+	"tsconfig.json":         true,
+	"jsconfig.json":         true,
+	"pyproject.toml":        true,
+	"setup.cfg":             true,
+	"Cargo.toml":            true,
+	"compile_commands.json": true,
 }
 
 // NewGraphUpdater creates a new graph update coordinator.
@@ -31,21 +85,76 @@ func NewGraphUpdater(db *sql.DB, rootDir string) *GraphUpdater {
 		db:         db,
 		extractor:  graph.NewExtractor(rootDir),
 		inferencer: storage.NewInterfaceInferencer(db),
+		parser:     NewParser(),
+		languages:  DefaultLanguageRegistry(),
 		rootDir:    rootDir,
+		roots:      contentSourceRoots{rootDir: rootDir},
 	}
 }
 
+// WithLanguages returns an updater that parses non-Go files with the
+// languages of registry. Languages whose extractor returns graph data
+// (external extractors with graph enabled) contribute types, functions,
+// calls, imports and declared supertypes.
+func (g *GraphUpdater) WithLanguages(registry *LanguageRegistry) *GraphUpdater {
+	updater := *g
+	updater.languages = registry
+	updater.parser = NewParser(WithLanguageRegistry(registry))
+	return &updater
+}
+
+// WithContentSources returns an updater that reads the files of content
+// sources from their roots. Their code is added to the graph under its
+// "@<label>/" paths.
+func (g *GraphUpdater) WithContentSources(sources *ContentSources) *GraphUpdater {
+	updater := *g
+	updater.roots.sources = sources
+	return &updater
+}
+
+// WithSecretScanner returns an updater that redacts the secrets in type and
+// function docs and in config key values before storing them.
+func (g *GraphUpdater) WithSecretScanner(scanner *SecretScanner) *GraphUpdater {
+	updater := *g
+	updater.secrets = scanner
+	return &updater
+}
+
+// WithTypedCalls returns an updater that resolves Go calls with go/types:
+// static callees exactly, interface calls to each possible implementation,
+// with a confidence recorded per call. Files the type checker cannot load
+// keep their syntactic calls.
+func (g *GraphUpdater) WithTypedCalls() *GraphUpdater {
+	updater := *g
+	updater.calls = graph.NewCallResolver(g.rootDir)
+	return &updater
+}
+
 // Update performs incremental graph updates based on file changes.
 //
 // Algorithm:
 //  1. Process deletions (CASCADE handles related data)
 //  2. Process additions/modifications (extract → delete old → insert new)
-//  3. Re-infer interface implementations if types changed
+//  3. Re-infer interface implementations and re-resolve declared
+//     supertypes if types changed
+//  4. With typed calls, re-resolve interface calls in unchanged files
+//     (implementations may have changed), then link resolved callees
+//  5. Detect workspace modules, assign files to them and resolve imports
+//     to indexed files or external packages
+//  6. Link API contract symbols to the Go code generated from them if
+//     types or Go code changed
+//
+// Files of content sources are added under their "@<label>/" paths but
+// belong to no workspace module, so their imports are left unresolved.
 //
 // Returns error for logging. Failures should not block indexing since
 // graph data is supplementary to core search functionality.
 func (g *GraphUpdater) Update(ctx context.Context, changes *ChangeSet) error {
 	hasTypeChanges := false
+	hasGoChanges := false
+	if g.calls != nil {
+		g.calls.Reset() // Re-read module packages
+	}
 
 	// 1. Process deletions (CASCADE handles related data)
 	for _, file := range changes.Deleted {
@@ -53,23 +162,38 @@ func (g *GraphUpdater) Update(ctx context.Context, changes *ChangeSet) error {
 			return fmt.Errorf("delete %s: %w", file, err)
 		}
 		hasTypeChanges = true // Deleted types affect inference
+		hasGoChanges = hasGoChanges || strings.HasSuffix(file, ".go")
 	}
 
 	// 2. Process additions and modifications
 	changedFiles := append(changes.Added, changes.Modified...)
 	for _, file := range changedFiles {
-		// Only process Go files (graph extraction is Go-only currently)
+		// Full graph extraction is Go-only; other languages contribute
+		// their types, declared supertypes, imports, routes, config
+		// reads and embedded SQL, or the graph data of their extractor
 		if !strings.HasSuffix(file, ".go") {
+			language := g.languages.Detect(g.roots.absPath(file))
+			lang, _ := g.languages.Lookup(language)
+			if hierarchyLanguages[language] || importLanguages[language] || endpointLanguages[language] || lang.Graph {
+				if err := g.updateParsedFile(ctx, file, language); err != nil {
+					return fmt.Errorf("update %s: %w", file, err)
+				}
+				hasTypeChanges = hasTypeChanges || hierarchyLanguages[language] || (lang.Graph && !configLanguages[language])
+			}
 			continue
 		}
 
-		absPath := filepath.Join(g.rootDir, file)
+		absPath := g.roots.absPath(file)
+		hasGoChanges = true
 
 		// Extract data from tree-sitter
-		data, err := g.extractor.ExtractCodeStructure(absPath)
+		data, err := g.extractorFor(file).ExtractCodeStructure(absPath)
 		if err != nil {
 			return fmt.Errorf("extract %s: %w", file, err)
 		}
+		if calls, ok := g.resolveCalls(file); ok {
+			data.FunctionCalls = calls
+		}
 
 		// Check if this file has type definitions
 		if len(data.Types) > 0 {
@@ -97,11 +221,268 @@ func (g *GraphUpdater) Update(ctx context.Context, changes *ChangeSet) error {
 		}
 
 		log.Printf("✓ Interface inference complete (%v)", time.Since(start))
+
+		if err := g.resolveDeclaredSupertypes(); err != nil {
+			return fmt.Errorf("resolve declared supertypes: %w", err)
+		}
+	}
+
+	// 4. Refresh interface calls elsewhere and link resolved callees
+	if g.calls != nil && hasGoChanges {
+		if err := g.refreshDynamicCalls(ctx, changedFiles); err != nil {
+			return fmt.Errorf("refresh interface calls: %w", err)
+		}
+	}
+	if err := g.linkResolvedCalls(); err != nil {
+		return fmt.Errorf("link resolved calls: %w", err)
+	}
+
+	// 5. Resolve imports against the workspace modules
+	if len(changes.Added) > 0 || len(changes.Modified) > 0 || len(changes.Deleted) > 0 {
+		if err := g.resolveImports(projectChanges(changes)); err != nil {
+			return fmt.Errorf("resolve imports: %w", err)
+		}
+	}
+
+	// 6. Link contracts to generated code
+	if hasTypeChanges || hasGoChanges {
+		if err := g.resolveContractLinks(); err != nil {
+			return fmt.Errorf("resolve contract links: %w", err)
+		}
+	}
+
+	// 7. Link endpoints to their handler functions
+	if len(changedFiles) > 0 || len(changes.Deleted) > 0 {
+		if err := g.resolveEndpointHandlers(); err != nil {
+			return fmt.Errorf("resolve endpoint handlers: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// projectChanges returns changes without the files of content sources,
+// which lie outside the project's workspace modules.
+func projectChanges(changes *ChangeSet) *ChangeSet {
+	return &ChangeSet{
+		Added:     projectFiles(changes.Added),
+		Modified:  projectFiles(changes.Modified),
+		Deleted:   projectFiles(changes.Deleted),
+		Unchanged: projectFiles(changes.Unchanged),
+	}
+}
+
+// projectFiles returns the file paths that don't belong to a content source.
+func projectFiles(files []string) []string {
+	result := make([]string, 0, len(files))
+	for _, file := range files {
+		if !storage.IsContentSourcePath(file) {
+			result = append(result, file)
+		}
+	}
+	return result
+}
+
+// resolveImports detects the workspace modules, then assigns files to them
+// and resolves imports. Only changed files are revisited unless the module
+// set, a manifest or the set of files changed, which can affect any file;
+// C/C++ headers are then paired with their implementations again.
+// The dependency inventory is re-read from the manifests and lockfiles.
+func (g *GraphUpdater) resolveImports(changes *ChangeSet) error {
+	ws, err := workspace.Detect(g.rootDir)
+	if err != nil {
+		return fmt.Errorf("detect workspace: %w", err)
+	}
+
+	inventory := ws.Dependencies()
+	deps := make([]storage.Dependency, 0, len(inventory))
+	for _, dep := range inventory {
+		deps = append(deps, storage.Dependency{
+			Ecosystem:    dep.Ecosystem,
+			Name:         dep.Name,
+			ImportName:   dep.ImportName,
+			Version:      dep.Version,
+			Constraint:   dep.Constraint,
+			Scope:        dep.Scope,
+			Direct:       dep.Direct,
+			ManifestPath: dep.Manifest,
+			ModuleName:   dep.Module,
+		})
+	}
+
+	modules := make([]storage.WorkspaceModule, 0, len(ws.Modules))
+	for _, mod := range ws.Modules {
+		modules = append(modules, storage.WorkspaceModule{
+			Name:         mod.Name,
+			Kind:         mod.Kind,
+			RootPath:     mod.Root,
+			ManifestPath: mod.Manifest,
+		})
+	}
+
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		files, err := storage.IndexedFilePaths(tx)
+		if err != nil {
+			return err
+		}
+		files = projectFiles(files)
+		modulesChanged, err := storage.ReplaceWorkspaceModules(tx, modules)
+		if err != nil {
+			return err
+		}
+
+		// nil scope revisits every file
+		scope := append(append([]string{}, changes.Added...), changes.Modified...)
+		if modulesChanged || len(changes.Added) > 0 || len(changes.Deleted) > 0 || touchesManifest(changes) {
+			scope = nil
+		}
+
+		indexed := make(map[string]bool, len(files))
+		for _, file := range files {
+			indexed[file] = true
+		}
+		targets := scope
+		if scope == nil {
+			targets = files
+		}
+		assignments := make(map[string]string, len(targets))
+		for _, file := range targets {
+			if indexed[file] { // Files the processor skipped have no row
+				mod, _ := ws.ModuleFor(file)
+				assignments[file] = mod.Name
+			}
+		}
+		if err := storage.AssignFileModules(tx, assignments); err != nil {
+			return err
+		}
+
+		imports, err := storage.ListImports(tx, scope)
+		if err != nil {
+			return err
+		}
+		resolver := ws.NewResolver(files)
+		resolutions := make([]storage.ImportResolution, 0, len(imports))
+		for _, imp := range imports {
+			if storage.IsContentSourcePath(imp.FilePath) {
+				continue // Outside the workspace
+			}
+			res := resolver.Resolve(imp.FilePath, imp.ImportPath)
+			resolutions = append(resolutions, storage.ImportResolution{
+				ImportID:     imp.ImportID,
+				Resolution:   res.Kind,
+				ResolvedPath: res.Path,
+				ModuleName:   res.Module,
+				PackageName:  res.Package,
+			})
+		}
+		if err := storage.ReplaceImportResolutions(tx, resolutions); err != nil {
+			return err
+		}
+
+		// Pairs only change when files are added or deleted, which
+		// revisits every file
+		if scope == nil {
+			var pairs []storage.HeaderImplementation
+			for _, file := range files {
+				if source := resolver.Implementation(file); source != "" {
+					pairs = append(pairs, storage.HeaderImplementation{HeaderPath: file, SourcePath: source})
+				}
+			}
+			if err := storage.ReplaceHeaderImplementations(tx, pairs); err != nil {
+				return err
+			}
+		}
+		return storage.ReplaceDependencies(tx, deps)
+	})
+}
+
+// touchesManifest reports whether a workspace manifest changed.
+func touchesManifest(changes *ChangeSet) bool {
+	for _, files := range [][]string{changes.Added, changes.Modified, changes.Deleted} {
+		for _, file := range files {
+			if manifestFiles[filepath.Base(file)] {
+				return true
+			}
+		}
 	}
+	return false
+}
+
+// extractorFor returns the Go extractor of a stored path: content source
+// files keep their "@<label>/" prefix in the graph.
+func (g *GraphUpdater) extractorFor(file string) graph.Extractor {
+	label := storage.ContentSourceLabel(file)
+	if root, ok := g.roots.sources.root(label); ok {
+		return graph.NewPrefixedExtractor(root, storage.ContentSourcePrefix(label))
+	}
+	return g.extractor
+}
 
+// resolveCalls returns the type-checked calls of a Go file. ok is false when
+// typed calls are disabled or the file could not be type-checked, in which
+// case the syntactic calls are kept. Content source files, outside the
+// project's packages, keep their syntactic calls.
+func (g *GraphUpdater) resolveCalls(file string) ([]graph.FunctionCall, bool) {
+	if g.calls == nil || storage.IsContentSourcePath(file) {
+		return nil, false
+	}
+	calls, err := g.calls.ResolveFile(filepath.Join(g.rootDir, file))
+	if err != nil {
+		if !errors.Is(err, graph.ErrNotInBuild) {
+			log.Printf("Warning: typed call resolution failed for %s, using syntactic calls: %v", file, err)
+		}
+		return nil, false
+	}
+	return calls, true
+}
+
+// refreshDynamicCalls re-resolves the calls of unchanged files that call
+// interface methods, whose possible implementations may have changed.
+func (g *GraphUpdater) refreshDynamicCalls(ctx context.Context, changedFiles []string) error {
+	var files []string
+	err := storage.WithTx(g.db, func(tx *sql.Tx) error {
+		var err error
+		files, err = storage.DynamicCallFiles(tx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	changed := make(map[string]bool, len(changedFiles))
+	for _, file := range changedFiles {
+		changed[file] = true
+	}
+
+	for _, file := range files {
+		if changed[file] {
+			continue
+		}
+		calls, ok := g.resolveCalls(file)
+		if !ok {
+			continue
+		}
+		err := storage.WithTx(g.db, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM function_calls WHERE source_file_path = ?", file); err != nil {
+				return fmt.Errorf("delete calls: %w", err)
+			}
+			return g.insertFunctionCalls(tx, calls)
+		})
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+	}
 	return nil
 }
 
+// linkResolvedCalls points type-checked calls at their callees' function rows.
+func (g *GraphUpdater) linkResolvedCalls() error {
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		_, err := storage.LinkResolvedCalls(tx)
+		return err
+	})
+}
+
 // deleteCodeStructure removes all code structure data for a file.
 // Foreign key CASCADE handles related data in child tables.
 //
@@ -109,63 +490,412 @@ func (g *GraphUpdater) Update(ctx context.Context, changes *ChangeSet) error {
 //  - types (CASCADE to type_fields, type_relationships)
 //  - functions (CASCADE to function_parameters, function_calls)
 //  - imports
+//  - declared_supertypes
+//  - contract_symbols
+//  - config_keys
+//  - endpoints
+//  - config_usages
+//  - table_usages
 func (g *GraphUpdater) deleteCodeStructure(ctx context.Context, file string) error {
-	// Delete from types (CASCADE to type_fields, type_relationships via from_type_id/to_type_id)
-	_, err := g.db.ExecContext(ctx, "DELETE FROM types WHERE file_path = ?", file)
-	if err != nil {
-		return fmt.Errorf("delete types: %w", err)
-	}
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		// Delete from types (CASCADE to type_fields, type_relationships via from_type_id/to_type_id)
+		_, err := tx.ExecContext(ctx, "DELETE FROM types WHERE file_path = ?", file)
+		if err != nil {
+			return fmt.Errorf("delete types: %w", err)
+		}
+
+		// Delete from functions (CASCADE to function_parameters, function_calls via caller_function_id)
+		_, err = tx.ExecContext(ctx, "DELETE FROM functions WHERE file_path = ?", file)
+		if err != nil {
+			return fmt.Errorf("delete functions: %w", err)
+		}
 
-	// Delete from functions (CASCADE to function_parameters, function_calls via caller_function_id)
-	_, err = g.db.ExecContext(ctx, "DELETE FROM functions WHERE file_path = ?", file)
+		// Delete imports
+		_, err = tx.ExecContext(ctx, "DELETE FROM imports WHERE file_path = ?", file)
+		if err != nil {
+			return fmt.Errorf("delete imports: %w", err)
+		}
+
+		// Delete declared supertypes (non-Go files)
+		if err := storage.DeleteDeclaredSupertypes(tx, file); err != nil {
+			return fmt.Errorf("delete declared supertypes: %w", err)
+		}
+
+		// Delete contract symbols (contract files)
+		if err := storage.DeleteContractSymbols(tx, file); err != nil {
+			return fmt.Errorf("delete contract symbols: %w", err)
+		}
+
+		// Delete config keys (configuration files)
+		if err := storage.DeleteConfigKeys(tx, file); err != nil {
+			return fmt.Errorf("delete config keys: %w", err)
+		}
+
+		// Delete endpoints (route registrations)
+		if err := storage.DeleteEndpoints(tx, file); err != nil {
+			return fmt.Errorf("delete endpoints: %w", err)
+		}
+
+		// Delete config usages (environment and config key reads)
+		if err := storage.DeleteConfigUsages(tx, file); err != nil {
+			return fmt.Errorf("delete config usages: %w", err)
+		}
+
+		// Delete table usages (embedded SQL)
+		if err := storage.DeleteTableUsages(tx, file); err != nil {
+			return fmt.Errorf("delete table usages: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// updateParsedFile replaces the graph data of a non-Go file: types and
+// declared supertypes for hierarchy languages, imports for import languages,
+// routes for endpoint languages, the environment variables and config keys
+// it reads, the tables its embedded SQL touches, and everything an
+// extractor's graph data holds.
+// Type IDs follow the {file_path}::{name} convention; supertypes are linked
+// to types by resolveDeclaredSupertypes once all files are written, and
+// imports are resolved by resolveImports.
+func (g *GraphUpdater) updateParsedFile(ctx context.Context, file, language string) error {
+	ext, err := g.parser.ParseFile(ctx, g.roots.absPath(file))
 	if err != nil {
-		return fmt.Errorf("delete functions: %w", err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Like the processor, skip the file (e.g. a failing external extractor)
+		log.Printf("Warning: failed to parse %s: %v\n", file, err)
+		ext = nil
 	}
 
-	// Delete imports
-	_, err = g.db.ExecContext(ctx, "DELETE FROM imports WHERE file_path = ?", file)
-	if err != nil {
-		return fmt.Errorf("delete imports: %w", err)
+	if err := g.deleteCodeStructure(ctx, file); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	if ext == nil || ext.Symbols == nil {
+		return nil // Unparseable file
+	}
+	if language == "c" && ext.Language == "cpp" {
+		language = "cpp" // C++ header (.h)
 	}
 
-	return nil
+	modulePath := extractModulePath(g.rootDir, file)
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		if err := g.ensureFileRecord(tx, file); err != nil {
+			return fmt.Errorf("ensure file record: %w", err)
+		}
+
+		if importLanguages[language] || ext.Graph != nil {
+			imports := make([]graph.Import, 0, len(ext.Symbols.Imports))
+			for _, imp := range ext.Symbols.Imports {
+				imports = append(imports, graph.Import{
+					FilePath:   file,
+					ImportPath: imp.Path,
+					IsRelative: strings.HasPrefix(imp.Path, "."),
+					ImportLine: imp.Line,
+				})
+			}
+			if err := g.insertImports(tx, imports); err != nil {
+				return fmt.Errorf("insert imports: %w", err)
+			}
+		}
+		if len(ext.Symbols.Endpoints) > 0 {
+			endpoints := make([]graph.Endpoint, 0, len(ext.Symbols.Endpoints))
+			for _, ep := range ext.Symbols.Endpoints {
+				endpoints = append(endpoints, graph.Endpoint{
+					FilePath:     file,
+					Line:         ep.Line,
+					Framework:    ep.Framework,
+					Kind:         graph.EndpointHTTP,
+					Method:       ep.Method,
+					Path:         ep.Path,
+					Handler:      ep.Handler,
+					HandlerName:  ep.HandlerName,
+					HandlerScope: ep.HandlerScope,
+				})
+			}
+			if err := storage.ReplaceEndpoints(tx, file, endpoints); err != nil {
+				return fmt.Errorf("insert endpoints: %w", err)
+			}
+		}
+		if len(ext.Symbols.ConfigReads) > 0 {
+			usages := make([]graph.ConfigUsage, 0, len(ext.Symbols.ConfigReads))
+			for _, r := range ext.Symbols.ConfigReads {
+				usages = append(usages, graph.ConfigUsage{
+					FilePath: file,
+					Line:     r.Line,
+					Kind:     r.Kind,
+					Key:      r.Key,
+					Source:   r.Source,
+					Function: r.Function,
+				})
+			}
+			if err := storage.ReplaceConfigUsages(tx, file, usages); err != nil {
+				return fmt.Errorf("insert config usages: %w", err)
+			}
+		}
+		if len(ext.Symbols.SQLQueries) > 0 {
+			var usages []graph.TableUsage
+			for _, q := range ext.Symbols.SQLQueries {
+				for _, a := range graph.ParseSQL(q.Query) {
+					usages = append(usages, graph.TableUsage{
+						FilePath:  file,
+						Line:      q.Line + a.Line - 1,
+						Table:     a.Table,
+						Access:    a.Access,
+						Operation: a.Operation,
+						Columns:   a.Columns,
+						Source:    q.Source,
+						Function:  q.Function,
+					})
+				}
+			}
+			if len(usages) > 0 {
+				if err := storage.ReplaceTableUsages(tx, file, usages); err != nil {
+					return fmt.Errorf("insert table usages: %w", err)
+				}
+			}
+		}
+		switch {
+		case ext.Graph != nil:
+			if err := g.insertExtractedGraph(tx, file, modulePath, ext.Graph); err != nil {
+				return fmt.Errorf("insert graph: %w", err)
+			}
+		case hierarchyLanguages[language]:
+			for _, t := range ext.Symbols.Types {
+				// OR IGNORE: a name can be declared twice in one file
+				// (e.g. TypeScript interface merging); the first one wins
+				_, err := sq.Insert("types").
+					Options("OR IGNORE").
+					Columns("type_id", "file_path", "module_path", "name", "kind", "start_line", "end_line", "doc").
+					Values(fmt.Sprintf("%s::%s", file, t.Name), file, modulePath, t.Name, t.Type, t.StartLine, t.EndLine, nullIfEmpty(g.secrets.redact(t.Doc))).
+					RunWith(tx).
+					Exec()
+				if err != nil {
+					return fmt.Errorf("insert type %s: %w", t.Name, err)
+				}
+			}
+		default:
+			return nil
+		}
+
+		supertypes := make([]storage.DeclaredSupertype, 0, len(ext.Symbols.Relations))
+		for _, rel := range ext.Symbols.Relations {
+			supertypes = append(supertypes, storage.DeclaredSupertype{
+				FilePath:  file,
+				TypeName:  rel.Type,
+				Supertype: rel.Supertype,
+				Kind:      rel.Kind,
+				Line:      rel.Line,
+			})
+		}
+		return storage.ReplaceDeclaredSupertypes(tx, file, supertypes)
+	})
 }
 
-// insertCodeStructure writes extracted code structure data to SQL tables.
-// Uses a transaction to ensure atomicity.
-func (g *GraphUpdater) insertCodeStructure(ctx context.Context, file string, data *graph.CodeStructure) error {
-	tx, err := g.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
+// insertExtractedGraph writes the graph data of an extractor. IDs follow
+// the non-Go conventions: {file_path}::{name} for types and functions,
+// {file_path}::{receiver}.{name} for methods. Calls from functions the
+// extractor didn't return are dropped; callees are recorded by name.
+// Types and functions tagged as contracts are recorded in contract_symbols,
+// and the keys of configuration files in config_keys.
+func (g *GraphUpdater) insertExtractedGraph(tx *sql.Tx, file, modulePath string, data *ExtractedGraph) error {
+	structure := &graph.CodeStructure{}
+	seen := make(map[string]bool)
+	var contracts []storage.ContractSymbol
+	tag := func(id string, c *ExtractedContract) {
+		if c != nil {
+			contracts = append(contracts, storage.ContractSymbol{
+				SymbolID: id, FilePath: file, Protocol: c.Protocol, Kind: c.Kind, Name: c.Name, GoPackage: c.GoPackage,
+			})
+		}
 	}
-	defer tx.Rollback()
 
-	// Ensure file record exists (required by FK constraints)
-	if err := g.ensureFileRecord(tx, file); err != nil {
-		return fmt.Errorf("ensure file record: %w", err)
+	for _, t := range data.Types {
+		typeID := fmt.Sprintf("%s::%s", file, t.Name)
+		if seen[typeID] {
+			continue // First declaration wins
+		}
+		seen[typeID] = true
+
+		typ := graph.Type{
+			ID: typeID, FilePath: file, ModulePath: modulePath, Name: t.Name, Kind: t.Kind,
+			StartLine: t.StartLine, EndLine: t.EndLine, IsExported: t.Exported, Doc: t.Doc,
+		}
+		for i, f := range t.Fields {
+			if f.Method {
+				typ.MethodCount++
+			} else {
+				typ.FieldCount++
+			}
+			structure.TypeFields = append(structure.TypeFields, graph.TypeField{
+				ID: fmt.Sprintf("%s::%s", typeID, f.Name), TypeID: typeID, Name: f.Name,
+				FieldType: f.Type, Position: i, IsMethod: f.Method, IsExported: f.Exported,
+			})
+		}
+		structure.Types = append(structure.Types, typ)
+		tag(typeID, t.Contract)
 	}
 
-	// Insert types and type_fields
-	if err := g.insertTypes(tx, data.Types, data.TypeFields); err != nil {
-		return fmt.Errorf("insert types: %w", err)
+	for _, f := range data.Functions {
+		name := f.Name
+		if f.Receiver != "" {
+			name = f.Receiver + "." + f.Name
+		}
+		funcID := fmt.Sprintf("%s::%s", file, name)
+		if seen[funcID] {
+			continue // Overloads share an ID; the first one wins
+		}
+		seen[funcID] = true
+
+		fn := graph.Function{
+			ID: funcID, FilePath: file, ModulePath: modulePath, Name: f.Name,
+			StartLine: f.StartLine, EndLine: f.EndLine, LineCount: f.EndLine - f.StartLine,
+			IsExported: f.Exported, IsMethod: f.Receiver != "", Doc: f.Doc,
+		}
+		if f.Receiver != "" {
+			receiver := f.Receiver
+			fn.ReceiverTypeName = &receiver
+			if typeID := fmt.Sprintf("%s::%s", file, receiver); seen[typeID] {
+				fn.ReceiverTypeID = &typeID
+			}
+		}
+		structure.Functions = append(structure.Functions, fn)
+		tag(funcID, f.Contract)
 	}
 
-	// Insert functions and function_parameters
-	if err := g.insertFunctions(tx, data.Functions, data.FunctionParams); err != nil {
-		return fmt.Errorf("insert functions: %w", err)
+	for _, c := range data.Calls {
+		callerID := fmt.Sprintf("%s::%s", file, c.Caller)
+		if !seen[callerID] || c.Callee == "" {
+			continue
+		}
+		structure.FunctionCalls = append(structure.FunctionCalls, graph.FunctionCall{
+			CallerFunctionID: callerID, CalleeName: c.Callee, SourceFilePath: file, CallLine: c.Line,
+		})
 	}
 
-	// Insert function_calls
-	if err := g.insertFunctionCalls(tx, data.FunctionCalls); err != nil {
+	if err := g.insertTypes(tx, structure.Types, structure.TypeFields); err != nil {
+		return fmt.Errorf("insert types: %w", err)
+	}
+	if err := g.insertFunctions(tx, structure.Functions, nil); err != nil {
+		return fmt.Errorf("insert functions: %w", err)
+	}
+	if err := g.insertFunctionCalls(tx, structure.FunctionCalls); err != nil {
 		return fmt.Errorf("insert calls: %w", err)
 	}
-
-	// Insert imports
-	if err := g.insertImports(tx, data.Imports); err != nil {
-		return fmt.Errorf("insert imports: %w", err)
+	if len(contracts) > 0 {
+		if err := storage.ReplaceContractSymbols(tx, file, contracts); err != nil {
+			return fmt.Errorf("insert contracts: %w", err)
+		}
 	}
+	if len(data.ConfigKeys) > 0 {
+		keys := make([]storage.ConfigKey, 0, len(data.ConfigKeys))
+		for _, k := range data.ConfigKeys {
+			keys = append(keys, storage.ConfigKey{FilePath: file, Document: k.Document, KeyPath: k.Path, Value: g.secrets.redact(k.Value), Line: k.Line})
+		}
+		if err := storage.ReplaceConfigKeys(tx, file, keys); err != nil {
+			return fmt.Errorf("insert config keys: %w", err)
+		}
+	}
+	return nil
+}
 
-	return tx.Commit()
+// resolveDeclaredSupertypes links declared supertypes to indexed types,
+// replacing the non-Go type_relationships.
+func (g *GraphUpdater) resolveDeclaredSupertypes() error {
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		count, err := storage.ResolveDeclaredSupertypes(tx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("✓ Resolved %d declared type relationships", count)
+		}
+		return nil
+	})
+}
+
+// resolveContractLinks links API contract symbols to the Go code generated
+// from them, replacing contract_links.
+func (g *GraphUpdater) resolveContractLinks() error {
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		count, err := storage.ResolveContractLinks(tx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("✓ Linked %d generated symbols to API contracts", count)
+		}
+		return nil
+	})
+}
+
+// resolveEndpointHandlers links endpoints to the functions handling them.
+func (g *GraphUpdater) resolveEndpointHandlers() error {
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		count, err := storage.ResolveEndpointHandlers(tx)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			log.Printf("✓ Resolved %d endpoint handlers", count)
+		}
+		return nil
+	})
+}
+
+// insertCodeStructure writes extracted code structure data to SQL tables.
+// Uses a transaction to ensure atomicity.
+func (g *GraphUpdater) insertCodeStructure(ctx context.Context, file string, data *graph.CodeStructure) error {
+	return storage.WithTx(g.db, func(tx *sql.Tx) error {
+		// Ensure file record exists (required by FK constraints)
+		if err := g.ensureFileRecord(tx, file); err != nil {
+			return fmt.Errorf("ensure file record: %w", err)
+		}
+
+		// Insert types and type_fields
+		if err := g.insertTypes(tx, data.Types, data.TypeFields); err != nil {
+			return fmt.Errorf("insert types: %w", err)
+		}
+
+		// Insert functions and function_parameters
+		if err := g.insertFunctions(tx, data.Functions, data.FunctionParams); err != nil {
+			return fmt.Errorf("insert functions: %w", err)
+		}
+
+		// Insert function_calls
+		if err := g.insertFunctionCalls(tx, data.FunctionCalls); err != nil {
+			return fmt.Errorf("insert calls: %w", err)
+		}
+
+		// Insert imports
+		if err := g.insertImports(tx, data.Imports); err != nil {
+			return fmt.Errorf("insert imports: %w", err)
+		}
+
+		// Insert endpoints
+		if len(data.Endpoints) > 0 {
+			if err := storage.ReplaceEndpoints(tx, file, data.Endpoints); err != nil {
+				return fmt.Errorf("insert endpoints: %w", err)
+			}
+		}
+
+		// Insert config usages
+		if len(data.ConfigUsages) > 0 {
+			if err := storage.ReplaceConfigUsages(tx, file, data.ConfigUsages); err != nil {
+				return fmt.Errorf("insert config usages: %w", err)
+			}
+		}
+
+		// Insert table usages
+		if len(data.TableUsages) > 0 {
+			if err := storage.ReplaceTableUsages(tx, file, data.TableUsages); err != nil {
+				return fmt.Errorf("insert table usages: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
 // insertTypes writes types and type_fields to SQL.
@@ -179,12 +909,12 @@ func (g *GraphUpdater) insertTypes(tx *sql.Tx, types []graph.Type, fields []grap
 		_, err := sq.Insert("types").
 			Columns(
 				"type_id", "file_path", "module_path", "name", "kind",
-				"start_line", "end_line", "is_exported", "field_count", "method_count",
+				"start_line", "end_line", "is_exported", "field_count", "method_count", "doc",
 			).
 			Values(
 				t.ID, t.FilePath, t.ModulePath, t.Name, t.Kind,
 				t.StartLine, t.EndLine, boolToInt(t.IsExported),
-				t.FieldCount, t.MethodCount,
+				t.FieldCount, t.MethodCount, nullIfEmpty(g.secrets.redact(t.Doc)),
 			).
 			RunWith(tx).
 			Exec()
@@ -232,14 +962,14 @@ func (g *GraphUpdater) insertFunctions(tx *sql.Tx, functions []graph.Function, p
 				"function_id", "file_path", "module_path", "name",
 				"start_line", "end_line", "line_count",
 				"is_exported", "is_method", "receiver_type_id", "receiver_type_name",
-				"param_count", "return_count", "cyclomatic_complexity",
+				"param_count", "return_count", "cyclomatic_complexity", "doc",
 			).
 			Values(
 				fn.ID, fn.FilePath, fn.ModulePath, fn.Name,
 				fn.StartLine, fn.EndLine, fn.LineCount,
 				boolToInt(fn.IsExported), boolToInt(fn.IsMethod),
 				fn.ReceiverTypeID, fn.ReceiverTypeName,
-				fn.ParamCount, fn.ReturnCount, fn.CyclomaticComplexity,
+				fn.ParamCount, fn.ReturnCount, fn.CyclomaticComplexity, nullIfEmpty(g.secrets.redact(fn.Doc)),
 			).
 			RunWith(tx).
 			Exec()
@@ -274,11 +1004,14 @@ func (g *GraphUpdater) insertFunctions(tx *sql.Tx, functions []graph.Function, p
 }
 
 // insertFunctionCalls writes function_calls to SQL.
+// Type-checked calls are written unlinked, with their resolved callee in
+// call_resolutions; linkResolvedCalls fills callee_function_id in.
 func (g *GraphUpdater) insertFunctionCalls(tx *sql.Tx, calls []graph.FunctionCall) error {
 	if len(calls) == 0 {
 		return nil
 	}
 
+	var resolutions []storage.CallResolution
 	for _, call := range calls {
 		// Generate ID if not set
 		callID := call.ID
@@ -286,13 +1019,23 @@ func (g *GraphUpdater) insertFunctionCalls(tx *sql.Tx, calls []graph.FunctionCal
 			callID = uuid.New().String()
 		}
 
+		calleeID := call.CalleeFunctionID
+		if call.Confidence != "" {
+			resolution := storage.CallResolution{CallID: callID, Confidence: call.Confidence}
+			if calleeID != nil {
+				resolution.CalleeFunctionID = *calleeID
+			}
+			resolutions = append(resolutions, resolution)
+			calleeID = nil
+		}
+
 		_, err := sq.Insert("function_calls").
 			Columns(
 				"call_id", "caller_function_id", "callee_function_id", "callee_name",
 				"source_file_path", "call_line", "call_column",
 			).
 			Values(
-				callID, call.CallerFunctionID, call.CalleeFunctionID, call.CalleeName,
+				callID, call.CallerFunctionID, calleeID, call.CalleeName,
 				call.SourceFilePath, call.CallLine, call.CallColumn,
 			).
 			RunWith(tx).
@@ -302,7 +1045,7 @@ func (g *GraphUpdater) insertFunctionCalls(tx *sql.Tx, calls []graph.FunctionCal
 		}
 	}
 
-	return nil
+	return storage.InsertCallResolutions(tx, resolutions)
 }
 
 // insertImports writes imports to SQL.
@@ -346,7 +1089,7 @@ func (g *GraphUpdater) ensureFileRecord(tx *sql.Tx, filePath string) error {
 	modulePath := extractModulePath(g.rootDir, filePath)
 
 	// Determine language from extension
-	language := "go" // Graph extraction currently only supports Go
+	language := g.languages.Detect(g.roots.absPath(filePath))
 
 	// Use raw SQL for INSERT OR IGNORE (Squirrel doesn't support it well)
 	now := time.Now().UTC().Format(time.RFC3339)
@@ -368,3 +1111,11 @@ func boolToInt(b bool) int {
 	}
 	return 0
 }
+
+// nullIfEmpty maps "" to NULL.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
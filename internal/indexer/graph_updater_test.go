@@ -3,6 +3,7 @@ package indexer
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -438,6 +439,292 @@ func (w *Widget) Do() {}
 	assert.Equal(t, 0, importCount)
 }
 
+func TestGraphUpdater_Update_DeclaredHierarchy(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Keep one in-memory database across transactions
+
+	rootDir := t.TempDir()
+	writeGoFile(t, filepath.Join(rootDir, "web/shapes/base.ts"), `export interface Shape {}
+export abstract class Base implements Shape {}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "web/shapes/circle.ts"), `import { Base } from "./base";
+export class Circle extends Base {}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "src/lib.rs"), `pub trait Draw: Named {}
+pub trait Named {}
+pub struct Canvas;
+impl Draw for Canvas {}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "main.go"), `package main
+type App struct{}
+`)
+
+	updater := NewGraphUpdater(db, rootDir)
+	err := updater.Update(context.Background(), &ChangeSet{
+		Added: []string{"web/shapes/base.ts", "web/shapes/circle.ts", "src/lib.rs", "main.go"},
+	})
+	require.NoError(t, err)
+
+	relationships := func() []string {
+		rows, err := db.Query(`
+			SELECT from_type_id || ' ' || relationship_type || ' ' || to_type_id
+			FROM type_relationships ORDER BY 1`)
+		require.NoError(t, err)
+		defer rows.Close()
+		var rels []string
+		for rows.Next() {
+			var rel string
+			require.NoError(t, rows.Scan(&rel))
+			rels = append(rels, rel)
+		}
+		return rels
+	}
+
+	// Go structs aren't matched against method-less TypeScript interfaces
+	assert.Equal(t, []string{
+		"src/lib.rs::Canvas implements src/lib.rs::Draw",
+		"src/lib.rs::Draw extends src/lib.rs::Named",
+		"web/shapes/base.ts::Base implements web/shapes/base.ts::Shape",
+		"web/shapes/circle.ts::Circle extends web/shapes/base.ts::Base",
+	}, relationships())
+
+	var language string
+	require.NoError(t, db.QueryRow("SELECT language FROM files WHERE file_path = ?", "src/lib.rs").Scan(&language))
+	assert.Equal(t, "rust", language)
+
+	// Modify: Circle now implements Shape directly
+	writeGoFile(t, filepath.Join(rootDir, "web/shapes/circle.ts"), `export class Circle implements Shape {}
+`)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"web/shapes/circle.ts"}}))
+	assert.Contains(t, relationships(), "web/shapes/circle.ts::Circle implements web/shapes/base.ts::Shape")
+	assert.NotContains(t, relationships(), "web/shapes/circle.ts::Circle extends web/shapes/base.ts::Base")
+
+	// Delete: relationships to and from the file's types go away
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Deleted: []string{"web/shapes/base.ts"}}))
+	assert.Equal(t, []string{
+		"src/lib.rs::Canvas implements src/lib.rs::Draw",
+		"src/lib.rs::Draw extends src/lib.rs::Named",
+	}, relationships())
+}
+
+func TestGraphUpdater_Update_TypedCalls(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1) // Keep one in-memory database across transactions
+
+	rootDir := t.TempDir()
+	writeGoFile(t, filepath.Join(rootDir, "go.mod"), "module example.com/app\n\ngo 1.22\n")
+	writeGoFile(t, filepath.Join(rootDir, "main.go"), `package main
+
+import "example.com/app/notify"
+
+func main() {
+	send(notify.Default())
+}
+
+func send(n notify.Notifier) {
+	n.Notify("hi")
+}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "notify/notify.go"), `package notify
+
+type Notifier interface{ Notify(msg string) }
+
+type Email struct{}
+
+func (Email) Notify(msg string) {}
+
+func Default() Notifier { return Email{} }
+`)
+
+	updater := NewGraphUpdater(db, rootDir).WithTypedCalls()
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{
+		Added: []string{"main.go", "notify/notify.go"},
+	}))
+
+	edges := func() []string {
+		rows, err := db.Query(`
+			SELECT fc.caller_function_id || ' -> ' || COALESCE(fc.callee_function_id, fc.callee_name) || ' (' || cr.confidence || ')'
+			FROM function_calls fc
+			JOIN call_resolutions cr ON cr.call_id = fc.call_id
+			ORDER BY 1`)
+		require.NoError(t, err)
+		defer rows.Close()
+		var edges []string
+		for rows.Next() {
+			var edge string
+			require.NoError(t, rows.Scan(&edge))
+			edges = append(edges, edge)
+		}
+		return edges
+	}
+
+	// Callees in files written after their callers are linked too
+	assert.Equal(t, []string{
+		"main.go::main -> main.go::send (exact)",
+		"main.go::main -> notify/notify.go::Default (exact)",
+		"main.go::send -> notify/notify.go::Email.Notify (dynamic)",
+	}, edges())
+
+	// A new implementation updates interface calls in unchanged files
+	writeGoFile(t, filepath.Join(rootDir, "notify/sms.go"), `package notify
+
+type SMS struct{}
+
+func (*SMS) Notify(msg string) {}
+`)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"notify/sms.go"}}))
+	assert.Contains(t, edges(), "main.go::send -> notify/sms.go::SMS.Notify (dynamic)")
+
+	// Re-indexing a callee's file keeps the link
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"notify/notify.go"}}))
+	assert.Contains(t, edges(), "main.go::main -> notify/notify.go::Default (exact)")
+}
+
+func TestGraphUpdater_Update_ImportResolution(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// A Go module with a TypeScript workspace and a Python project inside
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                 "module example.com/app\n\ngo 1.22\n",
+		"main.go":                "package main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/app/store\"\n\t\"github.com/google/uuid\"\n)\n\nfunc main() { fmt.Println(store.New(), uuid.New()) }\n",
+		"store/store.go":         "package store\n\nfunc New() int { return 1 }\n",
+		"package.json":           `{"private": true, "workspaces": ["web/*"]}`,
+		"web/ui/package.json":    `{"name": "@app/ui"}`,
+		"web/ui/src/index.ts":    "export const Button = 1;\n",
+		"web/site/package.json":  `{"name": "site"}`,
+		"web/site/src/app.ts":    "import { Button } from \"@app/ui\";\nimport { helper } from \"./helper\";\nimport React from \"react\";\n",
+		"web/site/src/helper.ts": "export function helper() {}\n",
+		"py/pyproject.toml":      "[project]\nname = \"tools\"\n",
+		"py/tools/__init__.py":   "",
+		"py/tools/cli.py":        "import os\nimport requests\nfrom . import config\n",
+		"py/tools/config.py":     "",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{
+		"main.go", "store/store.go",
+		"web/ui/src/index.ts", "web/site/src/app.ts", "web/site/src/helper.ts",
+		"py/tools/__init__.py", "py/tools/cli.py", "py/tools/config.py",
+	}}))
+
+	resolutions := func() map[string]string {
+		rows, err := db.Query(`
+			SELECT i.file_path || ' ' || i.import_path,
+			       r.resolution || ' ' || COALESCE(r.resolved_path, r.package_name, '') || ' ' || COALESCE(r.module_name, '') ||
+			       ' ext=' || i.is_external || ' std=' || i.is_standard_lib
+			FROM imports i
+			JOIN import_resolutions r ON r.import_id = i.import_id`)
+		require.NoError(t, err)
+		defer rows.Close()
+		result := make(map[string]string)
+		for rows.Next() {
+			var imp, res string
+			require.NoError(t, rows.Scan(&imp, &res))
+			result[imp] = res
+		}
+		return result
+	}
+
+	assert.Equal(t, map[string]string{
+		"main.go fmt":                    "stdlib fmt  ext=0 std=1",
+		"main.go example.com/app/store":  "internal store example.com/app ext=0 std=0",
+		"main.go github.com/google/uuid": "external github.com/google/uuid  ext=1 std=0",
+		"web/site/src/app.ts @app/ui":    "internal web/ui/src/index.ts @app/ui ext=0 std=0",
+		"web/site/src/app.ts ./helper":   "internal web/site/src/helper.ts site ext=0 std=0",
+		"web/site/src/app.ts react":      "external react  ext=1 std=0",
+		"py/tools/cli.py os":             "stdlib os  ext=0 std=1",
+		"py/tools/cli.py requests":       "external requests  ext=1 std=0",
+		"py/tools/cli.py .config":        "internal py/tools/config.py tools ext=0 std=0",
+	}, resolutions())
+
+	moduleOf := func(file string) string {
+		var module string
+		require.NoError(t, db.QueryRow("SELECT module_name FROM file_modules WHERE file_path = ?", file).Scan(&module))
+		return module
+	}
+	assert.Equal(t, "example.com/app", moduleOf("store/store.go"))
+	assert.Equal(t, "site", moduleOf("web/site/src/app.ts"))
+	assert.Equal(t, "tools", moduleOf("py/tools/cli.py"))
+
+	// A new file resolves imports in unchanged files that pointed nowhere
+	writeGoFile(t, filepath.Join(rootDir, "web/site/src/app.ts"), "import { Button } from \"@app/ui\";\nimport { theme } from \"./theme\";\n")
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"web/site/src/app.ts"}}))
+	assert.Equal(t, "unresolved   ext=0 std=0", resolutions()["web/site/src/app.ts ./theme"])
+
+	writeGoFile(t, filepath.Join(rootDir, "web/site/src/theme.ts"), "export const theme = {};\n")
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"web/site/src/theme.ts"}}))
+	assert.Equal(t, "internal web/site/src/theme.ts site ext=0 std=0", resolutions()["web/site/src/app.ts ./theme"])
+}
+
+func TestGraphUpdater_Update_DependencyInventory(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"go.mod":                "module example.com/app\n\ngo 1.22\n\nrequire github.com/google/uuid v1.6.0\n",
+		"main.go":               "package main\n\nimport \"github.com/google/uuid\"\n\nfunc main() { _ = uuid.New() }\n",
+		"web/package.json":      `{"name": "web", "dependencies": {"react": "^18.2.0"}}`,
+		"web/package-lock.json": `{"lockfileVersion": 3, "packages": {"": {}, "node_modules/react": {"version": "18.2.0"}, "node_modules/loose-envify": {"version": "1.4.0"}}}`,
+		"web/src/app.ts":        "import React from \"react\";\n",
+		"py/pyproject.toml":     "[project]\nname = \"tools\"\ndependencies = [\"requests>=2\"]\n",
+		"py/tools/cli.py":       "import requests\n",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"main.go", "web/src/app.ts", "py/tools/cli.py"}}))
+
+	deps, err := storage.ListDependencies(db)
+	require.NoError(t, err)
+	summaries := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		summaries = append(summaries, fmt.Sprintf("%s %s@%s direct=%t %s", dep.Ecosystem, dep.Name, dep.Version, dep.Direct, dep.ModuleName))
+	}
+	assert.Equal(t, []string{
+		"go github.com/google/uuid@v1.6.0 direct=true example.com/app",
+		"python requests@ direct=true tools",
+		"npm loose-envify@1.4.0 direct=false web",
+		"npm react@18.2.0 direct=true web",
+	}, summaries)
+
+	// Imports join to the dependency their module declares
+	rows, err := db.Query("SELECT file_path, dependency_name, version FROM dependency_usages ORDER BY file_path")
+	require.NoError(t, err)
+	defer rows.Close()
+	var usages []string
+	for rows.Next() {
+		var file, name, version string
+		require.NoError(t, rows.Scan(&file, &name, &version))
+		usages = append(usages, file+" "+name+"@"+version)
+	}
+	require.NoError(t, rows.Err())
+	assert.Equal(t, []string{
+		"main.go github.com/google/uuid@v1.6.0",
+		"py/tools/cli.py requests@",
+		"web/src/app.ts react@18.2.0",
+	}, usages)
+}
+
 // Helper functions
 
 func setupTestDB(t *testing.T) *sql.DB {
@@ -470,3 +757,216 @@ func writeGoFile(t *testing.T, path string, content string) {
 	err = os.WriteFile(path, []byte(content), 0644)
 	require.NoError(t, err)
 }
+
+func TestGraphUpdater_Update_DocColumns(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	rootDir := t.TempDir()
+	writeGoFile(t, filepath.Join(rootDir, "retry.go"), `package retry
+
+// Policy configures retries.
+type Policy struct {
+	Attempts int
+}
+
+// Do calls fn until it succeeds, backing off exponentially.
+func (p Policy) Do(fn func() error) error {
+	return fn()
+}
+
+func undocumented() {}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "web", "client.ts"), `/** HTTP client with retries. */
+export class Client {}
+`)
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"retry.go", "web/client.ts"}}))
+
+	doc := func(query string, args ...any) sql.NullString {
+		var doc sql.NullString
+		require.NoError(t, db.QueryRow(query, args...).Scan(&doc))
+		return doc
+	}
+	assert.Equal(t, "Policy configures retries.", doc("SELECT doc FROM types WHERE name = 'Policy'").String)
+	assert.Equal(t, "Do calls fn until it succeeds, backing off exponentially.", doc("SELECT doc FROM functions WHERE name = 'Do'").String)
+	assert.False(t, doc("SELECT doc FROM functions WHERE name = 'undocumented'").Valid)
+	assert.Equal(t, "HTTP client with retries.", doc("SELECT doc FROM types WHERE name = 'Client'").String)
+}
+
+func TestGraphUpdater_Update_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+
+	rootDir := t.TempDir()
+	writeGoFile(t, filepath.Join(rootDir, "client.go"), `package client
+
+// Client signs requests with `+fakeAWSKey+`.
+type Client struct{}
+
+// Sign signs with `+fakeAWSKey+` unless overridden.
+func Sign() {}
+`)
+	writeGoFile(t, filepath.Join(rootDir, "deploy.yaml"), "release: itk_0123456789abcdef0123456789abcdef\n")
+
+	scanner, err := NewSecretScanner([]SecretPatternConfig{{Name: "internal-token", Pattern: `itk_[a-z0-9]{32}`}})
+	require.NoError(t, err)
+	updater := NewGraphUpdater(db, rootDir).WithSecretScanner(scanner)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{"client.go", "deploy.yaml"}}))
+
+	value := func(query string) string {
+		var value string
+		require.NoError(t, db.QueryRow(query).Scan(&value))
+		return value
+	}
+	assert.Equal(t, "Client signs requests with [REDACTED:aws-access-key].", value("SELECT doc FROM types WHERE name = 'Client'"))
+	assert.Equal(t, "Sign signs with [REDACTED:aws-access-key] unless overridden.", value("SELECT doc FROM functions WHERE name = 'Sign'"))
+	assert.Equal(t, "[REDACTED:internal-token]", value("SELECT value FROM config_keys WHERE key_path = 'release'"))
+}
+
+func TestGraphUpdater_Update_Endpoints(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"api/router.go":      "package api\n\nimport \"net/http\"\n\nfunc Routes(mux *http.ServeMux, h *Orders) {\n\tmux.HandleFunc(\"GET /orders/{id}\", h.Get)\n}\n",
+		"api/orders.go":      "package api\n\nimport \"net/http\"\n\ntype Orders struct{}\n\nfunc (o *Orders) Get(w http.ResponseWriter, r *http.Request) { load() }\n\nfunc load() {}\n",
+		"web/app.ts":         "import express from 'express';\nconst app = express();\napp.post('/login', login);\n",
+		"config/routes.rb":   "Rails.application.routes.draw do\n  resources :users, only: [:index]\nend\n",
+		"tools/notroutes.py": "import os\n",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{
+		"api/router.go", "api/orders.go", "web/app.ts", "config/routes.rb", "tools/notroutes.py",
+	}}))
+
+	endpoints := func() map[string]string {
+		rows, err := db.Query("SELECT method || ' ' || path, framework || ' ' || COALESCE(handler_function_id, '-') FROM endpoints")
+		require.NoError(t, err)
+		defer rows.Close()
+		result := make(map[string]string)
+		for rows.Next() {
+			var route, handler string
+			require.NoError(t, rows.Scan(&route, &handler))
+			result[route] = handler
+		}
+		return result
+	}
+	assert.Equal(t, map[string]string{
+		"GET /orders/{id}": "net/http api/orders.go::Orders.Get",
+		"POST /login":      "express -",
+		"GET /users":       "rails -",
+	}, endpoints())
+
+	// Removing the route drops the endpoint
+	writeGoFile(t, filepath.Join(rootDir, "api/router.go"), "package api\n")
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"api/router.go"}}))
+	assert.NotContains(t, endpoints(), "GET /orders/{id}")
+}
+
+func TestGraphUpdater_Update_ConfigUsages(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"cmd/api/main.go": "package main\n\nimport \"os\"\n\nfunc main() {\n\t_ = os.Getenv(\"DATABASE_URL\")\n\t_ = os.Getenv(\"SENTRY_DSN\")\n}\n",
+		"web/server.ts":   "export function listen() {\n  return process.env.PORT;\n}\n",
+		".env.example":    "DATABASE_URL=postgres://localhost/app\nPORT=3000\nLEGACY_FLAG=1\n",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{
+		"cmd/api/main.go", "web/server.ts", ".env.example",
+	}}))
+
+	query := func(q string) []string {
+		rows, err := db.Query(q)
+		require.NoError(t, err)
+		defer rows.Close()
+		var result []string
+		for rows.Next() {
+			var s string
+			require.NoError(t, rows.Scan(&s))
+			result = append(result, s)
+		}
+		return result
+	}
+	assert.ElementsMatch(t, []string{
+		"DATABASE_URL main cmd/api/main.go::main",
+		"SENTRY_DSN main cmd/api/main.go::main",
+		"PORT listen -",
+	}, query("SELECT key || ' ' || function || ' ' || COALESCE(function_id, '-') FROM config_usages"))
+	assert.Equal(t, []string{"SENTRY_DSN"}, query("SELECT key FROM undocumented_config_keys"))
+	assert.Equal(t, []string{"LEGACY_FLAG"}, query("SELECT key FROM unused_config_keys"))
+
+	// Removing a read drops it
+	writeGoFile(t, filepath.Join(rootDir, "cmd/api/main.go"), "package main\n\nfunc main() {}\n")
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"cmd/api/main.go"}}))
+	assert.Equal(t, []string{"PORT"}, query("SELECT key FROM config_usages"))
+}
+
+func TestGraphUpdater_Update_TableUsages(t *testing.T) {
+	t.Parallel()
+
+	db := setupTestDB(t)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"store/chunks.go": "package store\n\nconst createChunks = `CREATE TABLE chunks (id TEXT)`\n\ntype Store struct{ db DB }\n\nfunc (s *Store) Purge() {\n\ts.db.Exec(\"DELETE FROM chunks WHERE stale\")\n}\n",
+		"jobs/cleanup.py": "def cleanup(cur):\n    cur.execute(\"\"\"\n        UPDATE chunks SET stale = 1\n    \"\"\")\n",
+	}
+	for path, contents := range files {
+		writeGoFile(t, filepath.Join(rootDir, path), contents)
+	}
+
+	updater := NewGraphUpdater(db, rootDir)
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Added: []string{
+		"store/chunks.go", "jobs/cleanup.py",
+	}}))
+
+	query := func(q string) []string {
+		rows, err := db.Query(q)
+		require.NoError(t, err)
+		defer rows.Close()
+		var result []string
+		for rows.Next() {
+			var s string
+			require.NoError(t, rows.Scan(&s))
+			result = append(result, s)
+		}
+		return result
+	}
+	usages := "SELECT table_name || ' ' || operation || ' ' || function || ' ' || COALESCE(function_id, '-') || ' @' || line FROM table_usages"
+	assert.ElementsMatch(t, []string{
+		"chunks create  - @3",
+		"chunks delete Store.Purge store/chunks.go::Store.Purge @8",
+		"chunks update cleanup - @3",
+	}, query(usages))
+
+	// Removing the statements drops them
+	writeGoFile(t, filepath.Join(rootDir, "store/chunks.go"), "package store\n")
+	require.NoError(t, updater.Update(context.Background(), &ChangeSet{Modified: []string{"store/chunks.go"}}))
+	assert.Equal(t, []string{"chunks update cleanup - @3"}, query(usages))
+}
@@ -25,15 +25,10 @@ func calculateChecksum(filePath string) (string, error) {
 	return hex.EncodeToString(hash[:]), nil
 }
 
-// collectFileMetadata collects file-level statistics for a single file.
-// Returns FileStats with all required fields populated.
-func collectFileMetadata(rootDir, filePath string) (*storage.FileStats, error) {
-	// Get relative path
-	relPath, err := filepath.Rel(rootDir, filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get relative path: %w", err)
-	}
-
+// collectFileMetadata collects file-level statistics for a single file
+// of language stored under relPath. Returns FileStats with all required
+// fields populated.
+func collectFileMetadata(rootDir, filePath, relPath, language string) (*storage.FileStats, error) {
 	// Get file info
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
@@ -46,9 +41,6 @@ func collectFileMetadata(rootDir, filePath string) (*storage.FileStats, error) {
 		return nil, fmt.Errorf("failed to calculate checksum: %w", err)
 	}
 
-	// Detect language from extension
-	language := detectLanguage(filePath)
-
 	// Detect if test file
 	isTest := isTestFile(filePath)
 
@@ -121,9 +113,9 @@ func isCommentLine(line, filePath string) bool {
 	ext := filepath.Ext(filePath)
 
 	switch ext {
-	case ".go", ".js", ".ts", ".tsx", ".jsx", ".c", ".cpp", ".h", ".java", ".rs", ".php":
+	case ".go", ".js", ".ts", ".tsx", ".jsx", ".c", ".cpp", ".cc", ".cxx", ".h", ".hpp", ".hh", ".hxx", ".java", ".rs", ".php":
 		return strings.HasPrefix(line, "//") || strings.HasPrefix(line, "/*") || strings.HasPrefix(line, "*")
-	case ".py", ".rb", ".sh":
+	case ".py", ".pyi", ".rb", ".sh", ".yaml", ".yml", ".toml":
 		return strings.HasPrefix(line, "#")
 	default:
 		return false
@@ -48,6 +48,9 @@ type Parser interface {
 
 	// SupportsLanguage checks if this parser supports the given language.
 	SupportsLanguage(language string) bool
+
+	// DetectLanguage returns the language of a file, or "unknown".
+	DetectLanguage(filePath string) string
 }
 
 // Chunker splits documentation files into semantic chunks.
@@ -70,6 +73,9 @@ type Formatter interface {
 
 	// FormatDocumentation formats a documentation chunk (may add context).
 	FormatDocumentation(chunk *DocumentationChunk) string
+
+	// FormatDocstring formats a symbol's documentation with its signature.
+	FormatDocstring(doc *Docstring, language string) string
 }
 
 // Config contains configuration for the indexer.
@@ -82,8 +88,11 @@ type Config struct {
 	DocsPatterns   []string
 	IgnorePatterns []string
 
+	// Directories outside RootDir indexed alongside it
+	ContentSources []ContentSourceConfig
+
 	// Chunking configuration
-	ChunkStrategies []string // ["symbols", "definitions", "data"]
+	ChunkStrategies []string // ["symbols", "definitions", "data", "docstring"]
 	DocChunkSize    int      // tokens
 	CodeChunkSize   int      // characters
 	Overlap         int      // tokens
@@ -97,6 +106,22 @@ type Config struct {
 	EmbeddingDims     int
 	EmbeddingEndpoint string
 	EmbeddingBinary   string
+
+	// Pipeline concurrency (worker counts and batch sizes)
+	Pipeline PipelineConfig
+
+	// Resolve Go calls with go/types instead of call syntax
+	TypedCallGraph bool
+
+	// Index sources of imported third-party packages into the dependency corpus
+	DependencyCorpus bool
+
+	// Languages extracted by external commands (see NewLanguageRegistry)
+	Languages []ExternalLanguageConfig
+
+	// Redact secrets from stored content and chunk text (see NewSecretScanner)
+	ScanSecrets    bool
+	SecretPatterns []SecretPatternConfig
 }
 
 // DefaultConfig returns a configuration with sensible defaults.
@@ -114,11 +139,26 @@ func DefaultConfig(rootDir string) *Config {
 			"**/*.c",
 			"**/*.cpp",
 			"**/*.cc",
+			"**/*.cxx",
 			"**/*.h",
 			"**/*.hpp",
+			"**/*.hh",
+			"**/*.hxx",
 			"**/*.php",
 			"**/*.rb",
 			"**/*.java",
+			"**/*.ipynb",
+			"**/*.vue",
+			"**/*.svelte",
+			"**/*.proto",
+			"**/*.graphql",
+			"**/*.graphqls",
+			"**/*.gql",
+			"**/*.yaml",
+			"**/*.yml",
+			"**/*.json",
+			"**/*.toml",
+			"**/.env.{example,sample,template}",
 		},
 		DocsPatterns: []string{
 			"**/*.md",
@@ -134,8 +174,15 @@ func DefaultConfig(rootDir string) *Config {
 			"__pycache__/**",
 			"*.test",
 			"*.pyc",
+			"**/package-lock.json",
+			"**/npm-shrinkwrap.json",
+			"**/pnpm-lock.yaml",
+			"**/*.min.json",
+			"**/testdata/**",
+			"**/fixtures/**",
+			"**/.ipynb_checkpoints/**",
 		},
-		ChunkStrategies:   []string{"symbols", "definitions", "data"},
+		ChunkStrategies:   []string{"symbols", "definitions", "data", "docstring"},
 		DocChunkSize:      800,
 		CodeChunkSize:     2000,
 		Overlap:           100,
@@ -145,5 +192,6 @@ func DefaultConfig(rootDir string) *Config {
 		EmbeddingDims:     384,
 		EmbeddingEndpoint: fmt.Sprintf("http://%s:%d/embed", embed.DefaultEmbedServerHost, embed.DefaultEmbedServerPort),
 		EmbeddingBinary:   "cortex-embed",
+		ScanSecrets:       true,
 	}
 }
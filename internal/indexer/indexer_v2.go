@@ -5,8 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"path/filepath"
 	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // IndexerV2Stats contains comprehensive statistics about the indexing operation.
@@ -20,6 +21,7 @@ type IndexerV2Stats struct {
 	TotalCodeChunks    int
 	TotalDocChunks     int
 	IndexingTime       time.Duration
+	Generation         int64 // Index generation committed by this run (0 if the storage has no generations)
 }
 
 // IndexerV2 is the new indexer implementation following the refactor spec.
@@ -30,6 +32,62 @@ type IndexerV2 struct {
 	processor      Processor
 	storage        Storage
 	graphUpdater   *GraphUpdater
+	languages      *LanguageRegistry // Optional registry shared with the processor
+	db             *sql.DB
+	corpus         *DependencyCorpus // Optional dependency corpus (nil = disabled)
+	sources        *ContentSources   // Optional content sources outside rootDir
+}
+
+// IndexerV2Option configures an IndexerV2.
+type IndexerV2Option func(*IndexerV2)
+
+// WithTypedCallGraph resolves Go calls with go/types when enabled
+// (see GraphUpdater.WithTypedCalls).
+func WithTypedCallGraph(enabled bool) IndexerV2Option {
+	return func(idx *IndexerV2) {
+		if enabled {
+			idx.graphUpdater = idx.graphUpdater.WithTypedCalls()
+		}
+	}
+}
+
+// WithLanguages builds the graph from the languages of registry (see
+// GraphUpdater.WithLanguages). The processor's parser should use the same
+// registry (see WithLanguageRegistry).
+func WithLanguages(registry *LanguageRegistry) IndexerV2Option {
+	return func(idx *IndexerV2) {
+		idx.graphUpdater = idx.graphUpdater.WithLanguages(registry)
+		idx.languages = registry
+	}
+}
+
+// WithDependencyCorpus syncs corpus with the project's imported
+// dependencies after each indexing run. A nil corpus disables it.
+func WithDependencyCorpus(corpus *DependencyCorpus) IndexerV2Option {
+	return func(idx *IndexerV2) {
+		idx.corpus = corpus
+	}
+}
+
+// WithContentSources indexes the files of content sources outside the root
+// directory, adds their code to the graph and records the sources in the
+// database. The change detector's file discovery and the processor must be
+// configured with the same sources (see FileDiscovery.WithContentSources and
+// WithContentSourcePaths).
+func WithContentSources(sources *ContentSources) IndexerV2Option {
+	return func(idx *IndexerV2) {
+		idx.sources = sources
+		idx.graphUpdater = idx.graphUpdater.WithContentSources(sources)
+	}
+}
+
+// WithSecretRedaction redacts the secrets in the graph's docs and config
+// values (see GraphUpdater.WithSecretScanner). The processor should use the
+// same scanner (see WithSecretScanner). A nil scanner disables it.
+func WithSecretRedaction(scanner *SecretScanner) IndexerV2Option {
+	return func(idx *IndexerV2) {
+		idx.graphUpdater = idx.graphUpdater.WithSecretScanner(scanner)
+	}
 }
 
 // NewIndexerV2 creates a new v2 indexer instance.
@@ -39,14 +97,20 @@ func NewIndexerV2(
 	processor Processor,
 	storage Storage,
 	db *sql.DB,
+	opts ...IndexerV2Option,
 ) *IndexerV2 {
-	return &IndexerV2{
+	idx := &IndexerV2{
 		rootDir:        rootDir,
 		changeDetector: changeDetector,
 		processor:      processor,
 		storage:        storage,
 		graphUpdater:   NewGraphUpdater(db, rootDir),
+		db:             db,
+	}
+	for _, opt := range opts {
+		opt(idx)
 	}
+	return idx
 }
 
 // Index discovers changes and processes them.
@@ -58,11 +122,22 @@ func NewIndexerV2(
 // The indexing flow:
 //  1. Detect changes (read-only, no side effects)
 //  2. Delete removed files (cascade deletes chunks via FK)
-//  3. Update metadata for unchanged files (mtime drift correction)
+//  3. Update metadata for unchanged files (mtime drift correction) and
+//     record the content sources
 //  4. Process changed files (added + modified)
 //  5. Update graph (incremental, best-effort)
+//  6. Commit the index generation
+//  7. Sync the dependency corpus, if enabled (best-effort)
+//
+// Steps 2-5 write their rows under a new index generation, so readers of the
+// database (e.g., cortex mcp) keep seeing the previous generation until step
+// 6 flips the committed one and never observe a half-written index. On error
+// the generation stays open and the next run resumes it.
 func (idx *IndexerV2) Index(ctx context.Context, hint []string) (*IndexerV2Stats, error) {
 	startTime := time.Now()
+	if idx.languages != nil {
+		defer idx.languages.endRun()
+	}
 
 	// 1. Detect changes (read-only, no side effects)
 	changes, err := idx.changeDetector.DetectChanges(ctx, hint)
@@ -77,6 +152,14 @@ func (idx *IndexerV2) Index(ctx context.Context, hint []string) (*IndexerV2Stats
 		FilesUnchanged: len(changes.Unchanged),
 	}
 
+	gen, err := idx.storage.BeginGeneration(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin index generation: %w", err)
+	}
+	if gen != nil {
+		defer gen.Close() // No-op once committed
+	}
+
 	// 2. Handle deletions (cascade deletes chunks via FK)
 	if len(changes.Deleted) > 0 {
 		for _, deleted := range changes.Deleted {
@@ -93,11 +176,22 @@ func (idx *IndexerV2) Index(ctx context.Context, hint []string) (*IndexerV2Stats
 			log.Printf("Warning: failed to update mtimes: %v", err)
 		}
 	}
+	if idx.db != nil {
+		if err := storage.WithTx(idx.db, func(tx *sql.Tx) error {
+			return storage.ReplaceContentSources(tx, idx.sources.Records())
+		}); err != nil {
+			log.Printf("Warning: failed to record content sources: %v", err)
+		}
+	}
 
 	// 4. Process changed files (added + modified)
 	toProcess := append(changes.Added, changes.Modified...)
 	if len(toProcess) == 0 {
 		log.Println("No changes detected")
+		if err := idx.commitGeneration(gen, stats); err != nil {
+			return nil, err
+		}
+		idx.syncDependencyCorpus(ctx)
 		stats.IndexingTime = time.Since(startTime)
 		return stats, nil // Nothing to do
 	}
@@ -106,9 +200,10 @@ func (idx *IndexerV2) Index(ctx context.Context, hint []string) (*IndexerV2Stats
 		len(toProcess), len(changes.Added), len(changes.Modified))
 
 	// Convert relative paths to absolute paths for processor
+	roots := contentSourceRoots{rootDir: idx.rootDir, sources: idx.sources}
 	absFiles := make([]string, len(toProcess))
 	for i, relPath := range toProcess {
-		absFiles[i] = filepath.Join(idx.rootDir, relPath)
+		absFiles[i] = roots.absPath(relPath)
 	}
 
 	procStats, err := idx.processor.ProcessFiles(ctx, absFiles)
@@ -129,12 +224,54 @@ func (idx *IndexerV2) Index(ctx context.Context, hint []string) (*IndexerV2Stats
 		}
 	}
 
+	// 6. Publish everything written above at once
+	if err := idx.commitGeneration(gen, stats); err != nil {
+		return nil, err
+	}
+
+	// 7. Index newly imported dependencies (reads the committed inventory)
+	idx.syncDependencyCorpus(ctx)
+
 	stats.IndexingTime = time.Since(startTime)
 	return stats, nil
 }
 
+// commitGeneration commits gen (if the storage opened one) and records its ID in stats.
+func (idx *IndexerV2) commitGeneration(gen *storage.Generation, stats *IndexerV2Stats) error {
+	if gen == nil {
+		return nil
+	}
+	if err := gen.Commit(); err != nil {
+		return fmt.Errorf("failed to commit index generation: %w", err)
+	}
+	stats.Generation = gen.ID()
+	return nil
+}
+
+// syncDependencyCorpus syncs the dependency corpus, if enabled. Failures are
+// logged: the corpus is supplementary and never fails indexing.
+func (idx *IndexerV2) syncDependencyCorpus(ctx context.Context) {
+	if idx.corpus == nil || idx.db == nil {
+		return
+	}
+	corpusStats, err := idx.corpus.Sync(ctx, idx.db)
+	if err != nil {
+		log.Printf("Warning: dependency corpus sync failed: %v\n", err)
+		return
+	}
+	if corpusStats.PackagesIndexed > 0 || corpusStats.PackagesRemoved > 0 {
+		log.Printf("✓ Dependency corpus: indexed %d packages (%d files), removed %d\n",
+			corpusStats.PackagesIndexed, corpusStats.FilesIndexed, corpusStats.PackagesRemoved)
+	}
+}
+
 // Close closes the indexer and releases resources.
 func (idx *IndexerV2) Close() error {
+	if idx.corpus != nil {
+		if err := idx.corpus.Close(); err != nil {
+			return err
+		}
+	}
 	if idx.storage != nil {
 		return idx.storage.Close()
 	}
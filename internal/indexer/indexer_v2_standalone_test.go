@@ -30,6 +30,7 @@ import (
 // 6. Empty Project - No files to process
 // 7. Context Cancellation - Graceful stop
 // 8. Graph Update Integration - GraphBuilder called correctly
+// 9. Index Generations - Each run commits the next generation
 
 // Test 1: Full Index (No Hint) - End to End
 func TestIndexerV2Integration_FullIndex(t *testing.T) {
@@ -549,6 +550,55 @@ func TestIndexerV2Integration_GraphFailureGraceful(t *testing.T) {
 	// verify error handling in detail.
 }
 
+// Test 9: Index Generations - Each run commits the next generation
+func TestIndexerV2Integration_Generations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rootDir := t.TempDir()
+	file1 := createTestGoFile(t, rootDir, "file1.go", "package main\n\nfunc Test1() {}\n")
+
+	db := storagepkg.NewTestDB(t)
+
+	storage, err := setupIntegrationTestStorage(t, db, rootDir)
+	require.NoError(t, err)
+	defer storage.Close()
+
+	discovery, err := NewFileDiscovery(rootDir, []string{"**/*.go"}, []string{}, []string{})
+	require.NoError(t, err)
+
+	changeDetector := NewChangeDetector(rootDir, storage, discovery)
+	processor := NewProcessor(rootDir, NewParser(), NewChunker(500, 50), NewFormatter(), &mockEmbedProvider{}, storage, &NoOpProgressReporter{})
+	indexer := NewIndexerV2(rootDir, changeDetector, processor, storage, db)
+
+	stats, err := indexer.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Generation)
+
+	// No changes still publishes a generation (mtime updates are part of it)
+	stats, err = indexer.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Generation)
+
+	err = os.WriteFile(file1, []byte("package main\n\nfunc Test1Modified() {}\n"), 0644)
+	require.NoError(t, err)
+
+	stats, err = indexer.Index(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.FilesModified)
+	assert.Equal(t, int64(3), stats.Generation)
+
+	committed, err := storagepkg.CommittedGeneration(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), committed)
+
+	// Index released its generation: the next run opens a new one
+	gen, err := storage.BeginGeneration(ctx)
+	require.NoError(t, err)
+	defer gen.Close()
+	assert.Equal(t, int64(4), gen.ID())
+}
+
 // Helper functions
 
 func createTestGoFile(t *testing.T, rootDir, relPath, content string) string {
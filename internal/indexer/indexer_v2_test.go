@@ -87,6 +87,10 @@ func (m *mockStorageV2) UpdateFileMtimes(filePaths []string) error {
 	return nil
 }
 
+func (m *mockStorageV2) BeginGeneration(ctx context.Context) (*storage.Generation, error) {
+	return nil, nil
+}
+
 func (m *mockStorageV2) Close() error {
 	return nil
 }
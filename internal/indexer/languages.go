@@ -0,0 +1,351 @@
+package indexer
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/parsers"
+	"github.com/mvp-joe/project-cortex/internal/languages"
+)
+
+// LanguageExtractor extracts the three-tier code structure (symbols,
+// definitions, data) of a file in one language.
+type LanguageExtractor interface {
+	// ParseFile extracts the code structure of the file at filePath.
+	// Returns nil (and no error) if the file cannot be parsed.
+	ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error)
+}
+
+// Language describes how files of a language are recognized and extracted.
+type Language struct {
+	Name       string
+	Extensions []string          // File extensions with leading dot, e.g. ".kt"
+	Filenames  []string          // Base name patterns, e.g. "Rakefile" or "*.gradle.kts"
+	Shebangs   []string          // Interpreters named on a "#!" line, e.g. "python3"
+	Extractor  LanguageExtractor // nil: files are recognized but not parsed
+	Graph      bool              // Extractor returns graph data (CodeExtraction.Graph)
+}
+
+// LanguageRegistry maps files to languages and their extractors.
+//
+// A file is recognized by its base name first, then its extension, then
+// (for files matching neither) the interpreter on its "#!" line. When two
+// languages claim the same extension, filename or interpreter, the one
+// registered last wins, so registered languages can take over built-in
+// extensions.
+//
+// Registration is not safe for concurrent use; register every language
+// before handing the registry to a parser.
+type LanguageRegistry struct {
+	languages  map[string]*Language
+	extensions map[string]string // extension → language
+	shebangs   map[string]string // interpreter → language
+	filenames  []filenamePattern // In registration order
+}
+
+// filenamePattern maps a base name pattern to a language.
+type filenamePattern struct {
+	pattern  string
+	language string
+}
+
+// NewLanguageRegistry returns a registry of the built-in languages and the
+// configured external ones (see ExternalLanguageConfig). An external
+// language named like a built-in one replaces it, keeping the built-in
+// extensions, filenames and shebangs unless it declares its own.
+func NewLanguageRegistry(external []ExternalLanguageConfig) (*LanguageRegistry, error) {
+	r := DefaultLanguageRegistry()
+	for _, cfg := range external {
+		lang := Language{
+			Name:       cfg.Name,
+			Extensions: cfg.Extensions,
+			Filenames:  cfg.Filenames,
+			Shebangs:   cfg.Shebangs,
+			Extractor:  NewExternalExtractor(cfg.Name, cfg.Command, cfg.Graph),
+			Graph:      cfg.Graph,
+		}
+		if builtin, ok := r.languages[cfg.Name]; ok && len(lang.Extensions)+len(lang.Filenames)+len(lang.Shebangs) == 0 {
+			lang.Extensions, lang.Filenames, lang.Shebangs = builtin.Extensions, builtin.Filenames, builtin.Shebangs
+		}
+		if err := r.Register(lang); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// DefaultLanguageRegistry returns a registry of the built-in languages.
+func DefaultLanguageRegistry() *LanguageRegistry {
+	r := &LanguageRegistry{
+		languages:  make(map[string]*Language),
+		extensions: make(map[string]string),
+		shebangs:   make(map[string]string),
+	}
+
+	cppParser := treeSitterExtractor{parsers.NewCppParser()}
+	for _, lang := range []Language{
+		{Name: languages.Go, Extensions: []string{".go"}, Extractor: goParser{}},
+		{Name: languages.TypeScript, Extensions: []string{".ts", ".tsx"}, Extractor: treeSitterExtractor{parsers.NewTypeScriptParser()}},
+		{Name: languages.JavaScript, Extensions: []string{".js", ".jsx"}, Shebangs: []string{"node"}, Extractor: treeSitterExtractor{parsers.NewJavaScriptParser()}},
+		{Name: languages.Python, Extensions: []string{".py", ".pyi"}, Shebangs: []string{"python", "python3"}, Extractor: treeSitterExtractor{parsers.NewPythonParser()}},
+		{Name: languages.Rust, Extensions: []string{".rs"}, Extractor: treeSitterExtractor{parsers.NewRustParser()}},
+		{Name: languages.C, Extensions: []string{".c", ".h"}, Extractor: cExtractor{c: treeSitterExtractor{parsers.NewCParser()}, cpp: cppParser}},
+		{Name: languages.Cpp, Extensions: []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx"}, Extractor: cppParser},
+		{Name: languages.Java, Extensions: []string{".java"}, Extractor: treeSitterExtractor{parsers.NewJavaParser()}},
+		{Name: languages.PHP, Extensions: []string{".php"}, Shebangs: []string{"php"}, Extractor: treeSitterExtractor{parsers.NewPhpParser()}},
+		{Name: languages.Ruby, Extensions: []string{".rb"}, Filenames: []string{"Rakefile", "Gemfile"}, Shebangs: []string{"ruby"}, Extractor: treeSitterExtractor{parsers.NewRubyParser()}},
+		{Name: languageJupyter, Extensions: []string{".ipynb"}, Extractor: notebookExtractor{r}},
+		{Name: languageVue, Extensions: []string{".vue"}, Extractor: sfcExtractor{r}},
+		{Name: languageSvelte, Extensions: []string{".svelte"}, Extractor: sfcExtractor{r}},
+		{Name: protocolProtobuf, Extensions: []string{".proto"}, Extractor: protobufExtractor{}, Graph: true},
+		{Name: protocolGraphQL, Extensions: []string{".graphql", ".graphqls", ".gql"}, Extractor: graphqlExtractor{}, Graph: true},
+		{Name: configYAML, Extensions: []string{".yaml", ".yml"}, Extractor: configExtractor{configYAML}, Graph: true},
+		{Name: configJSON, Extensions: []string{".json"}, Extractor: configExtractor{configJSON}, Graph: true},
+		{Name: configTOML, Extensions: []string{".toml"}, Extractor: configExtractor{configTOML}, Graph: true},
+		{Name: configDotenv, Filenames: dotenvFilenames, Extractor: configExtractor{configDotenv}, Graph: true},
+		{Name: protocolOpenAPI, Filenames: openapiFilenames, Extractor: openapiExtractor{}, Graph: true},
+	} {
+		if err := r.Register(lang); err != nil {
+			panic(err) // Built-in languages are valid
+		}
+	}
+	return r
+}
+
+// Register adds a language, replacing any language of the same name.
+func (r *LanguageRegistry) Register(lang Language) error {
+	if lang.Name == "" || lang.Name == "unknown" {
+		return fmt.Errorf("invalid language name %q", lang.Name)
+	}
+	if len(lang.Extensions)+len(lang.Filenames)+len(lang.Shebangs) == 0 {
+		return fmt.Errorf("language %s: no extensions, filenames or shebangs", lang.Name)
+	}
+	for _, ext := range lang.Extensions {
+		if !strings.HasPrefix(ext, ".") || len(ext) < 2 {
+			return fmt.Errorf("language %s: extension %q must start with a dot", lang.Name, ext)
+		}
+	}
+	for _, pattern := range lang.Filenames {
+		if _, err := filepath.Match(pattern, ""); err != nil || strings.ContainsRune(pattern, '/') {
+			return fmt.Errorf("language %s: invalid filename pattern %q", lang.Name, pattern)
+		}
+	}
+
+	if _, ok := r.languages[lang.Name]; ok {
+		r.unregister(lang.Name)
+	}
+	r.languages[lang.Name] = &lang
+	for _, ext := range lang.Extensions {
+		r.extensions[strings.ToLower(ext)] = lang.Name
+	}
+	for _, pattern := range lang.Filenames {
+		r.filenames = append(r.filenames, filenamePattern{pattern: pattern, language: lang.Name})
+	}
+	for _, interpreter := range lang.Shebangs {
+		r.shebangs[interpreter] = lang.Name
+	}
+	return nil
+}
+
+// unregister removes a language and the mappings that still point to it.
+func (r *LanguageRegistry) unregister(name string) {
+	delete(r.languages, name)
+	for ext, lang := range r.extensions {
+		if lang == name {
+			delete(r.extensions, ext)
+		}
+	}
+	for interpreter, lang := range r.shebangs {
+		if lang == name {
+			delete(r.shebangs, interpreter)
+		}
+	}
+	filenames := r.filenames[:0]
+	for _, f := range r.filenames {
+		if f.language != name {
+			filenames = append(filenames, f)
+		}
+	}
+	r.filenames = filenames
+}
+
+// Lookup returns the language registered under name.
+func (r *LanguageRegistry) Lookup(name string) (Language, bool) {
+	lang, ok := r.languages[name]
+	if !ok {
+		return Language{}, false
+	}
+	return *lang, true
+}
+
+// Names returns the names of the registered languages, sorted.
+func (r *LanguageRegistry) Names() []string {
+	names := make([]string, 0, len(r.languages))
+	for name := range r.languages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Detect returns the language of the file at filePath, or "unknown". The
+// file is only read for a "#!" line if neither its name nor its extension
+// is recognized.
+func (r *LanguageRegistry) Detect(filePath string) string {
+	base := filepath.Base(filePath)
+	for i := len(r.filenames) - 1; i >= 0; i-- {
+		if ok, _ := filepath.Match(r.filenames[i].pattern, base); ok {
+			return r.filenames[i].language
+		}
+	}
+
+	if ext := strings.ToLower(filepath.Ext(base)); ext != "" {
+		if lang, ok := r.extensions[ext]; ok {
+			return lang
+		}
+	}
+
+	if len(r.shebangs) > 0 {
+		if lang, ok := r.shebangs[readShebang(filePath)]; ok {
+			return lang
+		}
+	}
+	return "unknown"
+}
+
+// readShebang returns the interpreter named on the "#!" line of a file
+// ("python3" for both "#!/usr/bin/python3" and "#!/usr/bin/env python3"),
+// or "" if the file has none or can't be read.
+func readShebang(filePath string) string {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	line, err := bufio.NewReaderSize(f, 256).ReadSlice('\n')
+	if err != nil && len(line) == 0 {
+		return ""
+	}
+	if !strings.HasPrefix(string(line), "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(string(line[2:]))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		interpreter = ""
+		for _, arg := range fields[1:] {
+			if !strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") {
+				interpreter = filepath.Base(arg)
+				break
+			}
+		}
+	}
+	return interpreter
+}
+
+// languageParser is an internal interface for the tree-sitter parsers.
+type languageParser interface {
+	ParseFile(ctx context.Context, filePath string) (*parsers.CodeExtraction, error)
+	ParseSource(ctx context.Context, filePath string, source []byte) (*parsers.CodeExtraction, error)
+}
+
+// runExtractor is implemented by extractors that keep state for the
+// duration of an index run.
+type runExtractor interface {
+	// endRun drops the state kept for the current run.
+	endRun()
+}
+
+// endRun drops the state the extractors kept for the index run that just
+// ended.
+func (r *LanguageRegistry) endRun() {
+	for _, lang := range r.languages {
+		if ext, ok := lang.Extractor.(runExtractor); ok {
+			ext.endRun()
+		}
+	}
+}
+
+// sourceExtractor is implemented by extractors that can parse source held in
+// memory, such as the code embedded in notebooks and components.
+type sourceExtractor interface {
+	// ParseSource extracts source as if it were the content of filePath.
+	ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error)
+}
+
+// treeSitterExtractor adapts a tree-sitter parser to LanguageExtractor.
+type treeSitterExtractor struct {
+	parser languageParser
+}
+
+// ParseFile implements LanguageExtractor.
+func (e treeSitterExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	result, err := e.parser.ParseFile(ctx, filePath)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return convertCodeExtraction(result), nil
+}
+
+// ParseSource implements sourceExtractor.
+func (e treeSitterExtractor) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
+	result, err := e.parser.ParseSource(ctx, filePath, source)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	return convertCodeExtraction(result), nil
+}
+
+// cppHeaderPattern matches C++ constructs in a header.
+var cppHeaderPattern = regexp.MustCompile(`(?m)^\s*(class|namespace|template\s*<)\b|^\s*(public|private|protected)\s*:|\bstd::`)
+
+// cExtractor extracts C files, and .h headers with the C++ extractor when
+// they contain C++ (classes, namespaces, templates, access specifiers or
+// std:: names), since the extension is shared by both languages.
+type cExtractor struct {
+	c, cpp LanguageExtractor
+}
+
+// ParseFile implements LanguageExtractor.
+func (e cExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".h") {
+		content, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		if cppHeaderPattern.Match(content) {
+			return e.cpp.ParseFile(ctx, filePath)
+		}
+	}
+	return e.c.ParseFile(ctx, filePath)
+}
+
+// ParseSource implements sourceExtractor.
+func (e cExtractor) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
+	if strings.EqualFold(filepath.Ext(filePath), ".h") && cppHeaderPattern.Match(source) {
+		return parseSource(ctx, e.cpp, filePath, source)
+	}
+	return parseSource(ctx, e.c, filePath, source)
+}
+
+// parseSource extracts source with extractor, as if it were the content of
+// filePath. Returns nil (and no error) if the extractor only reads files.
+// Used by extractors of files embedding code of other languages.
+func parseSource(ctx context.Context, extractor LanguageExtractor, filePath string, source []byte) (*CodeExtraction, error) {
+	parser, ok := extractor.(sourceExtractor)
+	if !ok {
+		return nil, nil
+	}
+	return parser.ParseSource(ctx, filePath, source)
+}
@@ -0,0 +1,154 @@
+package indexer
+
+// Test Plan for Language Registry:
+// - Built-in languages are detected by extension, filename and shebang (direct and via env)
+// - Files with no recognized name, extension or shebang are "unknown"
+// - A registered language takes over extensions of built-in ones
+// - Registering a language again replaces its previous mappings
+// - Languages without any way of being detected, and malformed extensions, are rejected
+// - NewLanguageRegistry adds external languages; one named like a built-in keeps its detection
+// - Embedded source is parsed in memory as the content of the embedding file; C headers with
+//   C++ go to the C++ parser; extractors that only read files return nothing
+// - The default registry holds exactly the built-in language names
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/languages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubExtractor returns an extraction naming its language.
+type stubExtractor struct{ language string }
+
+func (e stubExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	return &CodeExtraction{Language: e.language, FilePath: filePath}, nil
+}
+
+func TestLanguageRegistry_Detect(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	script := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0755))
+		return path
+	}
+
+	languages := DefaultLanguageRegistry()
+	assert.Equal(t, "go", languages.Detect("cmd/main.go"))
+	assert.Equal(t, "typescript", languages.Detect("App.TSX"))
+	assert.Equal(t, "ruby", languages.Detect("Rakefile"))
+	assert.Equal(t, "python", languages.Detect(script("deploy", "#!/usr/bin/python3\nprint('hi')\n")))
+	assert.Equal(t, "javascript", languages.Detect(script("serve", "#!/usr/bin/env -S node --no-warnings\n")))
+	assert.Equal(t, "unknown", languages.Detect(script("run", "#!/bin/bash\necho hi\n")))
+	assert.Equal(t, "unknown", languages.Detect(script("notes.txt", "plain text\n")))
+	assert.Equal(t, "unknown", languages.Detect(filepath.Join(dir, "missing")))
+}
+
+func TestLanguageRegistry_Register(t *testing.T) {
+	t.Parallel()
+
+	languages := DefaultLanguageRegistry()
+	require.NoError(t, languages.Register(Language{
+		Name:       "kotlin",
+		Extensions: []string{".kt", ".KTS"},
+		Filenames:  []string{"*.gradle.kts"},
+		Extractor:  stubExtractor{"kotlin"},
+	}))
+	assert.Equal(t, "kotlin", languages.Detect("src/Main.kt"))
+	assert.Equal(t, "kotlin", languages.Detect("settings.kts"))
+	assert.Equal(t, "kotlin", languages.Detect("build.gradle.kts"))
+	assert.Contains(t, languages.Names(), "kotlin")
+
+	parser := NewParser(WithLanguageRegistry(languages))
+	assert.True(t, parser.SupportsLanguage("kotlin"))
+	ext, err := parser.ParseFile(context.Background(), "src/Main.kt")
+	require.NoError(t, err)
+	assert.Equal(t, "kotlin", ext.Language)
+
+	// A later language takes over an extension
+	require.NoError(t, languages.Register(Language{Name: "hack", Extensions: []string{".php"}, Extractor: stubExtractor{"hack"}}))
+	assert.Equal(t, "hack", languages.Detect("index.php"))
+
+	// Registering again replaces the mappings
+	require.NoError(t, languages.Register(Language{Name: "kotlin", Extensions: []string{".kt"}}))
+	assert.Equal(t, "kotlin", languages.Detect("Main.kt"))
+	assert.Equal(t, "unknown", languages.Detect("build.gradle.kts"))
+	assert.False(t, NewParser(WithLanguageRegistry(languages)).SupportsLanguage("kotlin"))
+
+	assert.ErrorContains(t, languages.Register(Language{Name: "dsl"}), "no extensions")
+	assert.ErrorContains(t, languages.Register(Language{Name: "dsl", Extensions: []string{"dsl"}}), "must start with a dot")
+	assert.ErrorContains(t, languages.Register(Language{Name: "", Extensions: []string{".dsl"}}), "invalid language name")
+}
+
+func TestNewLanguageRegistry(t *testing.T) {
+	t.Parallel()
+
+	languages, err := NewLanguageRegistry([]ExternalLanguageConfig{
+		{Name: "elixir", Extensions: []string{".ex", ".exs"}, Shebangs: []string{"elixir"}, Command: []string{"cortex-elixir"}, Graph: true},
+		{Name: "php", Command: []string{"cortex-php"}},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "elixir", languages.Detect("lib/app.ex"))
+	elixir, ok := languages.Lookup("elixir")
+	require.True(t, ok)
+	assert.True(t, elixir.Graph)
+	assert.IsType(t, &externalExtractor{}, elixir.Extractor)
+
+	// Replaces the built-in extractor, keeping its detection
+	php, ok := languages.Lookup("php")
+	require.True(t, ok)
+	assert.Equal(t, []string{".php"}, php.Extensions)
+	assert.IsType(t, &externalExtractor{}, php.Extractor)
+	assert.Equal(t, "php", languages.Detect("index.php"))
+
+	_, err = NewLanguageRegistry([]ExternalLanguageConfig{{Name: "dsl", Command: []string{"cortex-dsl"}}})
+	assert.ErrorContains(t, err, "no extensions, filenames or shebangs")
+}
+
+func TestDefaultLanguageRegistry_BuiltinNames(t *testing.T) {
+	t.Parallel()
+
+	builtin := languages.Builtin()
+	sort.Strings(builtin)
+	assert.Equal(t, builtin, DefaultLanguageRegistry().Names())
+}
+
+func TestParseSource(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	registry := DefaultLanguageRegistry()
+	lookup := func(name string) LanguageExtractor {
+		lang, ok := registry.Lookup(name)
+		require.True(t, ok)
+		return lang.Extractor
+	}
+
+	// The path doesn't exist: nothing is read from disk
+	path := filepath.Join(t.TempDir(), "analysis.ipynb")
+	ext, err := parseSource(ctx, lookup("python"), path, []byte("def score(events):\n    return len(events)\n"))
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	assert.Equal(t, path, ext.FilePath)
+	require.Len(t, ext.Symbols.Functions, 1)
+	assert.Equal(t, "score", ext.Symbols.Functions[0].Name)
+
+	ext, err = parseSource(ctx, lookup("go"), "main.go", []byte("package main\n\nfunc main() {}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "main", ext.Symbols.PackageName)
+
+	ext, err = parseSource(ctx, lookup("c"), "widget.h", []byte("namespace ui {\nclass Widget {};\n}\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "cpp", ext.Language)
+
+	ext, err = parseSource(ctx, lookup(protocolProtobuf), "api.proto", []byte("syntax = \"proto3\";\n"))
+	require.NoError(t, err)
+	assert.Nil(t, ext)
+}
@@ -0,0 +1,214 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/languages"
+)
+
+// languageJupyter is the language of Jupyter notebooks (.ipynb).
+const languageJupyter = languages.Jupyter
+
+// notebookExtractor extracts Jupyter notebooks. Code cells are extracted
+// together, in the language of the notebook's kernel, by the extractor
+// registered for that language; locations are the cell (1-based, counting
+// every cell) and the line within it. Markdown cells are returned as
+// CodeExtraction.Markdown and chunked like documentation files.
+//
+// The registry is consulted at parse time, so kernels of languages
+// registered after the notebook language (e.g. external ones) are extracted.
+type notebookExtractor struct {
+	languages *LanguageRegistry
+}
+
+// notebook is the part of the nbformat 4 document the extractor reads.
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+// notebookCell is a cell of a notebook. Source is a string or a list of
+// lines (each ending with its newline).
+type notebookCell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"`
+}
+
+// text returns the source of the cell.
+func (c notebookCell) text() string {
+	var s string
+	if json.Unmarshal(c.Source, &s) == nil {
+		return s
+	}
+	var lines []string
+	if json.Unmarshal(c.Source, &lines) == nil {
+		return strings.Join(lines, "")
+	}
+	return ""
+}
+
+// kernelLanguage returns the language of the notebook's kernel, lowercased
+// ("python", "r", "julia"). Notebooks without kernel metadata are assumed
+// to be Python.
+func (nb *notebook) kernelLanguage() string {
+	language := nb.Metadata.Kernelspec.Language
+	if language == "" {
+		language = nb.Metadata.LanguageInfo.Name
+	}
+	if language == "" {
+		return "python"
+	}
+	return strings.ToLower(language)
+}
+
+// ParseFile implements LanguageExtractor.
+func (e notebookExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var nb notebook
+	if err := json.Unmarshal(content, &nb); err != nil || len(nb.Cells) == 0 {
+		return nil, nil // Not a notebook, or an nbformat 3 one
+	}
+
+	language := nb.kernelLanguage()
+	var markdown []MarkdownCell
+	script := &notebookScript{python: language == "python"}
+	for i, cell := range nb.Cells {
+		switch cell.CellType {
+		case "markdown":
+			markdown = append(markdown, MarkdownCell{Cell: i + 1, Content: cell.text()})
+		case "code":
+			script.add(i+1, cell.text())
+		}
+	}
+
+	result := &CodeExtraction{Language: language}
+	if lang, ok := e.languages.Lookup(language); ok && lang.Extractor != nil && lang.Name != languageJupyter && len(script.lines) > 0 {
+		extracted, err := parseSource(ctx, lang.Extractor, filePath, []byte(strings.Join(script.lines, "\n")))
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Code that fails to extract (e.g. a Go kernel's cells, which
+		// aren't a Go file) leaves the markdown cells
+		if err == nil && extracted != nil {
+			script.relocate(extracted)
+			result = extracted
+		}
+	}
+	if result.Symbols == nil && len(markdown) == 0 {
+		return nil, nil
+	}
+
+	result.Markdown = markdown
+	result.FilePath = filePath
+	result.StartLine = 1
+	result.EndLine = len(splitLines(content))
+	return result, nil
+}
+
+// notebookScript is the code cells of a notebook joined into one source,
+// each followed by an empty line. It maps the lines of that source back to
+// cells.
+type notebookScript struct {
+	python bool     // Blank out IPython magics and shell escapes
+	lines  []string // Source lines
+	cells  []int    // Cell of each source line; 0 for separators
+	offset []int    // Line within its cell of each source line
+}
+
+// add appends the source of a code cell.
+func (s *notebookScript) add(cell int, source string) {
+	lines := strings.Split(strings.TrimRight(source, "\n"), "\n")
+	if s.python && strings.HasPrefix(strings.TrimSpace(lines[0]), "%%") {
+		return // Cell magic: the cell is not Python (%%bash, %%sql, ...)
+	}
+	for i, line := range lines {
+		if s.python && isIPythonLine(line) {
+			line = ""
+		}
+		s.lines = append(s.lines, line)
+		s.cells = append(s.cells, cell)
+		s.offset = append(s.offset, i+1)
+	}
+	s.lines = append(s.lines, "")
+	s.cells = append(s.cells, 0)
+	s.offset = append(s.offset, 0)
+}
+
+// isIPythonLine reports whether a line is an IPython line magic ("%time")
+// or shell escape ("!pip install x"), neither of which parses as Python.
+func isIPythonLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "%") || strings.HasPrefix(trimmed, "!")
+}
+
+// locate returns the cell and the line within it of a source line.
+func (s *notebookScript) locate(line int) (cell, cellLine int) {
+	if line < 1 || line > len(s.cells) {
+		return 0, line
+	}
+	return s.cells[line-1], s.offset[line-1]
+}
+
+// span returns the cell and the lines within it of a source line range.
+// Ranges are within one cell, since cells are separated by an empty line;
+// a range ending elsewhere ends on its first line.
+func (s *notebookScript) span(start, end int) (cell, cellStart, cellEnd int) {
+	cell, cellStart = s.locate(start)
+	endCell, cellEnd := s.locate(end)
+	if endCell != cell || cellEnd < cellStart {
+		cellEnd = cellStart
+	}
+	return cell, cellStart, cellEnd
+}
+
+// relocate rewrites the locations of an extraction of the script to cells
+// and lines within them. Graph data is dropped: its locations can't be
+// mapped to cells.
+func (s *notebookScript) relocate(ext *CodeExtraction) {
+	ext.Graph = nil
+	if sym := ext.Symbols; sym != nil {
+		for i := range sym.Types {
+			t := &sym.Types[i]
+			t.Cell, t.StartLine, t.EndLine = s.span(t.StartLine, t.EndLine)
+		}
+		for i := range sym.Functions {
+			fn := &sym.Functions[i]
+			fn.Cell, fn.StartLine, fn.EndLine = s.span(fn.StartLine, fn.EndLine)
+		}
+		for i := range sym.Relations {
+			_, sym.Relations[i].Line = s.locate(sym.Relations[i].Line)
+		}
+		for i := range sym.Imports {
+			_, sym.Imports[i].Line = s.locate(sym.Imports[i].Line)
+		}
+	}
+	if defs := ext.Definitions; defs != nil {
+		for i := range defs.Definitions {
+			d := &defs.Definitions[i]
+			d.Cell, d.StartLine, d.EndLine = s.span(d.StartLine, d.EndLine)
+		}
+	}
+	if data := ext.Data; data != nil {
+		for i := range data.Constants {
+			c := &data.Constants[i]
+			c.Cell, c.StartLine, c.EndLine = s.span(c.StartLine, c.EndLine)
+		}
+		for i := range data.Variables {
+			v := &data.Variables[i]
+			v.Cell, v.StartLine, v.EndLine = s.span(v.StartLine, v.EndLine)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package indexer
+
+// Test Plan for Jupyter Notebook Extraction:
+// - Code cells are extracted in the kernel's language; locations are the cell (1-based,
+//   counting every cell) and the line within it
+// - IPython magics and shell escapes are blanked out; cell magics skip the cell
+// - Markdown cells are returned with their cell index; raw cells are ignored
+// - Notebooks of kernels without an extractor keep their markdown cells
+// - Invalid notebooks aren't extracted
+// - The processor emits docstring chunks located by cell and one documentation chunk per
+//   markdown cell section
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNotebook = `{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Churn analysis\n", "\n", "Loads events and scores churn."]},
+  {"cell_type": "code", "execution_count": 1, "metadata": {}, "outputs": [], "source": ["%matplotlib inline\n", "!pip install pandas\n", "import pandas as pd\n", "THRESHOLD = 0.8"]},
+  {"cell_type": "raw", "metadata": {}, "source": "def ignored(): pass"},
+  {"cell_type": "code", "execution_count": 2, "metadata": {}, "outputs": [], "source": "\n\ndef score(events):\n    \"\"\"Score churn risk from events.\"\"\"\n    return len(events)\n"},
+  {"cell_type": "code", "execution_count": 3, "metadata": {}, "outputs": [], "source": ["%%bash\n", "def not_python\n"]}
+ ],
+ "metadata": {
+  "kernelspec": {"display_name": "Python 3", "language": "python", "name": "python3"},
+  "language_info": {"name": "python"}
+ },
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func parseNotebook(t *testing.T, content string) *CodeExtraction {
+	t.Helper()
+	ext, err := notebookExtractor{DefaultLanguageRegistry()}.ParseFile(context.Background(), writeContractFile(t, "analysis.ipynb", content))
+	require.NoError(t, err)
+	return ext
+}
+
+func TestNotebookExtractor(t *testing.T) {
+	t.Parallel()
+
+	ext := parseNotebook(t, testNotebook)
+	require.NotNil(t, ext)
+	assert.Equal(t, "python", ext.Language)
+	assert.Equal(t, "analysis.ipynb", filepath.Base(ext.FilePath))
+
+	require.Len(t, ext.Symbols.Functions, 1)
+	fn := ext.Symbols.Functions[0]
+	assert.Equal(t, "score", fn.Name)
+	assert.Equal(t, 4, fn.Cell)
+	assert.Equal(t, 3, fn.StartLine)
+	assert.Equal(t, 5, fn.EndLine)
+	assert.Equal(t, "Score churn risk from events.", fn.Doc)
+
+	require.NotEmpty(t, ext.Definitions.Definitions)
+	def := ext.Definitions.Definitions[0]
+	assert.Equal(t, 4, def.Cell)
+	assert.Contains(t, def.Code, "def score(events):")
+
+	require.Len(t, ext.Data.Constants, 1)
+	assert.Equal(t, "THRESHOLD", ext.Data.Constants[0].Name)
+	assert.Equal(t, 2, ext.Data.Constants[0].Cell)
+	assert.Equal(t, 4, ext.Data.Constants[0].StartLine)
+
+	require.Len(t, ext.Symbols.Imports, 1)
+	assert.Equal(t, "pandas", ext.Symbols.Imports[0].Path)
+	assert.Equal(t, 3, ext.Symbols.Imports[0].Line)
+
+	require.Len(t, ext.Markdown, 1)
+	assert.Equal(t, 1, ext.Markdown[0].Cell)
+	assert.Equal(t, "# Churn analysis\n\nLoads events and scores churn.", ext.Markdown[0].Content)
+}
+
+func TestNotebookExtractor_UnsupportedKernel(t *testing.T) {
+	t.Parallel()
+
+	ext := parseNotebook(t, `{
+ "cells": [
+  {"cell_type": "markdown", "source": "Fibonacci in Haskell"},
+  {"cell_type": "code", "source": "fib n = fib (n-1) + fib (n-2)"}
+ ],
+ "metadata": {"kernelspec": {"language": "haskell"}},
+ "nbformat": 4
+}`)
+	require.NotNil(t, ext)
+	assert.Equal(t, "haskell", ext.Language)
+	assert.Nil(t, ext.Symbols)
+	require.Len(t, ext.Markdown, 1)
+	assert.Equal(t, "Fibonacci in Haskell", ext.Markdown[0].Content)
+
+	// Without markdown there is nothing to extract
+	assert.Nil(t, parseNotebook(t, `{"cells": [{"cell_type": "code", "source": "x"}], "metadata": {"kernelspec": {"language": "haskell"}}}`))
+}
+
+func TestNotebookExtractor_Invalid(t *testing.T) {
+	t.Parallel()
+
+	assert.Nil(t, parseNotebook(t, `{"cells": [`))
+	assert.Nil(t, parseNotebook(t, `{"worksheets": [{"cells": []}], "nbformat": 3}`))
+}
+
+func TestProcessor_ProcessFiles_NotebookChunks(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupProcessorTestStorage(t, db, tempDir)
+	require.NoError(t, err)
+
+	notebookFile := filepath.Join(tempDir, "analysis.ipynb")
+	require.NoError(t, os.WriteFile(notebookFile, []byte(testNotebook), 0644))
+
+	processor := createTestProcessor(t, tempDir, storage)
+	_, err = processor.ProcessFiles(context.Background(), []string{notebookFile})
+	require.NoError(t, err)
+
+	var id, text string
+	require.NoError(t, db.QueryRow(
+		"SELECT chunk_id, text FROM chunks WHERE file_path = 'analysis.ipynb' AND chunk_type = 'docstring'",
+	).Scan(&id, &text))
+	assert.Equal(t, "code-docstring-analysis.ipynb:cell4:3:score", id)
+	assert.Equal(t, "score(events) (cell 4, lines 3-5)\n\nScore churn risk from events.", text)
+
+	require.NoError(t, db.QueryRow(
+		"SELECT chunk_id, text FROM chunks WHERE file_path = 'analysis.ipynb' AND chunk_type = 'documentation'",
+	).Scan(&id, &text))
+	assert.Equal(t, "doc-analysis.ipynb:cell1-s0", id)
+	assert.Contains(t, text, "Loads events and scores churn.")
+
+	require.NoError(t, db.QueryRow(
+		"SELECT text FROM chunks WHERE file_path = 'analysis.ipynb' AND chunk_type = 'symbols'",
+	).Scan(&text))
+	assert.Contains(t, text, "score(events) (cell 4, lines 3-5)")
+}
@@ -6,83 +6,47 @@ import (
 	"go/parser"
 	"go/token"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
 	"github.com/mvp-joe/project-cortex/internal/indexer/parsers"
 )
 
-// multiLanguageParser implements Parser using Go's ast for Go files and tree-sitter for others.
+// multiLanguageParser implements Parser by routing each file to the
+// extractor of its language in a LanguageRegistry.
 type multiLanguageParser struct {
-	// Tree-sitter parsers (using interface types to avoid exposing internal types)
-	tsParser   languageParser
-	jsParser   languageParser
-	pyParser   languageParser
-	rsParser   languageParser
-	cParser    languageParser
-	javaParser languageParser
-	phpParser  languageParser
-	rubyParser languageParser
+	languages *LanguageRegistry
 }
 
-// languageParser is an internal interface for language-specific parsers.
-type languageParser interface {
-	ParseFile(ctx context.Context, filePath string) (*parsers.CodeExtraction, error)
+// ParserOption configures the parser created by NewParser.
+type ParserOption func(*multiLanguageParser)
+
+// WithLanguageRegistry parses files with the languages of registry instead
+// of the built-in ones (see NewLanguageRegistry).
+func WithLanguageRegistry(registry *LanguageRegistry) ParserOption {
+	return func(p *multiLanguageParser) {
+		p.languages = registry
+	}
 }
 
-// NewParser creates a new parser instance that supports all languages.
-func NewParser() Parser {
-	return &multiLanguageParser{
-		tsParser:   parsers.NewTypeScriptParser(),
-		jsParser:   parsers.NewJavaScriptParser(),
-		pyParser:   parsers.NewPythonParser(),
-		rsParser:   parsers.NewRustParser(),
-		cParser:    parsers.NewCParser(),
-		javaParser: parsers.NewJavaParser(),
-		phpParser:  parsers.NewPhpParser(),
-		rubyParser: parsers.NewRubyParser(),
+// NewParser creates a new parser instance that supports all built-in
+// languages, plus those of a registry passed with WithLanguageRegistry.
+func NewParser(opts ...ParserOption) Parser {
+	p := &multiLanguageParser{languages: DefaultLanguageRegistry()}
+	for _, opt := range opts {
+		opt(p)
 	}
+	return p
 }
 
 // ParseFile extracts code structure from a source file.
+// Returns nil for files of unsupported languages.
 func (p *multiLanguageParser) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
-	language := detectLanguage(filePath)
-
-	// Route to appropriate parser based on language
-	var result *parsers.CodeExtraction
-	var err error
-
-	switch language {
-	case "go":
-		return p.parseGoFile(filePath)
-	case "typescript":
-		result, err = p.tsParser.ParseFile(ctx, filePath)
-	case "javascript":
-		result, err = p.jsParser.ParseFile(ctx, filePath)
-	case "python":
-		result, err = p.pyParser.ParseFile(ctx, filePath)
-	case "rust":
-		result, err = p.rsParser.ParseFile(ctx, filePath)
-	case "c", "cpp":
-		result, err = p.cParser.ParseFile(ctx, filePath)
-	case "java":
-		result, err = p.javaParser.ParseFile(ctx, filePath)
-	case "php":
-		result, err = p.phpParser.ParseFile(ctx, filePath)
-	case "ruby":
-		result, err = p.rubyParser.ParseFile(ctx, filePath)
-	default:
-		// Unsupported language
+	lang, ok := p.languages.Lookup(p.languages.Detect(filePath))
+	if !ok || lang.Extractor == nil {
 		return nil, nil
 	}
-
-	if err != nil || result == nil {
-		return nil, err
-	}
-
-	// Convert parsers.CodeExtraction to indexer.CodeExtraction
-	return convertCodeExtraction(result), nil
+	return lang.Extractor.ParseFile(ctx, filePath)
 }
 
 // convertCodeExtraction converts parsers.CodeExtraction to indexer.CodeExtraction.
@@ -105,18 +69,31 @@ func convertCodeExtraction(src *parsers.CodeExtraction) *CodeExtraction {
 
 // SupportsLanguage checks if this parser supports the given language.
 func (p *multiLanguageParser) SupportsLanguage(language string) bool {
-	switch language {
-	case "go", "typescript", "javascript", "python", "rust", "c", "cpp", "java", "php", "ruby":
-		return true
-	default:
-		return false
+	lang, ok := p.languages.Lookup(language)
+	return ok && lang.Extractor != nil
+}
+
+// DetectLanguage returns the language of a file (see LanguageRegistry.Detect).
+func (p *multiLanguageParser) DetectLanguage(filePath string) string {
+	return p.languages.Detect(filePath)
+}
+
+// goParser extracts Go files with go/ast.
+type goParser struct{}
+
+// ParseFile implements LanguageExtractor.
+func (p goParser) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
 }
 
-// parseGoFile parses a Go source file using go/ast.
-func (p *multiLanguageParser) parseGoFile(filePath string) (*CodeExtraction, error) {
+// ParseSource implements sourceExtractor.
+func (p goParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, source, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -141,13 +118,7 @@ func (p *multiLanguageParser) parseGoFile(filePath string) (*CodeExtraction, err
 	// Count imports
 	codeExtraction.Symbols.ImportsCount = len(node.Imports)
 
-	// Read file for getting source code snippets
-	sourceBytes, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, err
-	}
-	source := string(sourceBytes)
-	lines := strings.Split(source, "\n")
+	lines := strings.Split(string(source), "\n")
 
 	// Walk the AST
 	ast.Inspect(node, func(n ast.Node) bool {
@@ -164,7 +135,7 @@ func (p *multiLanguageParser) parseGoFile(filePath string) (*CodeExtraction, err
 }
 
 // processGenDecl processes general declarations (types, constants, variables).
-func (p *multiLanguageParser) processGenDecl(decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
+func (p goParser) processGenDecl(decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
 	for _, spec := range decl.Specs {
 		switch s := spec.(type) {
 		case *ast.TypeSpec:
@@ -176,7 +147,7 @@ func (p *multiLanguageParser) processGenDecl(decl *ast.GenDecl, fset *token.File
 }
 
 // processTypeSpec processes type declarations.
-func (p *multiLanguageParser) processTypeSpec(spec *ast.TypeSpec, decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
+func (p goParser) processTypeSpec(spec *ast.TypeSpec, decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
 	startLine := fset.Position(spec.Pos()).Line
 	endLine := fset.Position(spec.End()).Line
 
@@ -197,6 +168,7 @@ func (p *multiLanguageParser) processTypeSpec(spec *ast.TypeSpec, decl *ast.GenD
 		Type:      typeKind,
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       specDoc(spec.Doc, decl),
 	})
 
 	// Add to definitions (extract source code)
@@ -211,7 +183,7 @@ func (p *multiLanguageParser) processTypeSpec(spec *ast.TypeSpec, decl *ast.GenD
 }
 
 // processValueSpec processes constant and variable declarations.
-func (p *multiLanguageParser) processValueSpec(spec *ast.ValueSpec, decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
+func (p goParser) processValueSpec(spec *ast.ValueSpec, decl *ast.GenDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
 	startLine := fset.Position(spec.Pos()).Line
 	endLine := fset.Position(spec.End()).Line
 
@@ -235,6 +207,7 @@ func (p *multiLanguageParser) processValueSpec(spec *ast.ValueSpec, decl *ast.Ge
 				Type:      typeName,
 				StartLine: startLine,
 				EndLine:   endLine,
+				Doc:       specDoc(spec.Doc, decl),
 			})
 		} else if decl.Tok == token.VAR {
 			// Variable
@@ -250,7 +223,7 @@ func (p *multiLanguageParser) processValueSpec(spec *ast.ValueSpec, decl *ast.Ge
 }
 
 // processFuncDecl processes function declarations.
-func (p *multiLanguageParser) processFuncDecl(decl *ast.FuncDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
+func (p goParser) processFuncDecl(decl *ast.FuncDecl, fset *token.FileSet, lines []string, codeExtraction *CodeExtraction) {
 	startLine := fset.Position(decl.Pos()).Line
 	endLine := fset.Position(decl.End()).Line
 
@@ -272,6 +245,7 @@ func (p *multiLanguageParser) processFuncDecl(decl *ast.FuncDecl, fset *token.Fi
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       strings.TrimSpace(decl.Doc.Text()),
 	})
 
 	// Add to definitions (signature only, not body)
@@ -286,6 +260,15 @@ func (p *multiLanguageParser) processFuncDecl(decl *ast.FuncDecl, fset *token.Fi
 	})
 }
 
+// specDoc returns the doc comment of a type or value spec. An ungrouped
+// declaration ("type T struct{}") keeps its doc comment on decl.
+func specDoc(doc *ast.CommentGroup, decl *ast.GenDecl) string {
+	if doc == nil && !decl.Lparen.IsValid() {
+		doc = decl.Doc
+	}
+	return strings.TrimSpace(doc.Text())
+}
+
 // extractLines extracts source code lines from startLine to endLine (1-indexed).
 func extractLines(lines []string, startLine, endLine int) string {
 	if startLine < 1 || endLine < 1 || startLine > len(lines) {
@@ -327,32 +310,3 @@ func extractFunctionSignature(lines []string, startLine, endLine int) string {
 	// No brace found, return the line
 	return lines[startLine-1]
 }
-
-// detectLanguage detects the programming language based on file extension.
-func detectLanguage(filePath string) string {
-	ext := strings.ToLower(filepath.Ext(filePath))
-	switch ext {
-	case ".go":
-		return "go"
-	case ".ts", ".tsx":
-		return "typescript"
-	case ".js", ".jsx":
-		return "javascript"
-	case ".py":
-		return "python"
-	case ".rs":
-		return "rust"
-	case ".c", ".h":
-		return "c"
-	case ".cpp", ".cc", ".hpp":
-		return "cpp"
-	case ".php":
-		return "php"
-	case ".rb":
-		return "ruby"
-	case ".java":
-		return "java"
-	default:
-		return "unknown"
-	}
-}
@@ -19,6 +19,7 @@ import (
 // - Extracts constants
 // - Extracts variables
 // - Extracts function signatures
+// - Extracts doc comments of types, functions and constants
 // - Detects language from file extension
 // - Returns nil for unsupported languages
 
@@ -142,8 +143,9 @@ func TestParser_DetectLanguage(t *testing.T) {
 		{"test.txt", "unknown"},
 	}
 
+	languages := DefaultLanguageRegistry()
 	for _, tt := range tests {
-		lang := detectLanguage(tt.filePath)
+		lang := languages.Detect(tt.filePath)
 		assert.Equal(t, tt.language, lang, "file: %s", tt.filePath)
 	}
 }
@@ -177,3 +179,59 @@ func TestParser_InvalidGoFile(t *testing.T) {
 
 	require.Error(t, err)
 }
+
+func TestParser_GoDocComments(t *testing.T) {
+	t.Parallel()
+
+	goFile := filepath.Join(t.TempDir(), "retry.go")
+	content := `package retry
+
+// Policy configures retries.
+type Policy struct{}
+
+type (
+	// Delay is a wait between attempts.
+	Delay int
+)
+
+// MaxAttempts bounds retries.
+const MaxAttempts = 5
+
+// Backoff constants.
+const (
+	Base   = 2
+	Factor = 3 // Not a doc comment
+)
+
+// Do calls fn until it succeeds,
+// backing off exponentially.
+func Do(fn func() error) error { return fn() }
+
+func undocumented() {}
+`
+	require.NoError(t, os.WriteFile(goFile, []byte(content), 0644))
+
+	ext, err := NewParser().ParseFile(context.Background(), goFile)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+
+	docs := make(map[string]string)
+	for _, typ := range ext.Symbols.Types {
+		docs[typ.Name] = typ.Doc
+	}
+	for _, fn := range ext.Symbols.Functions {
+		docs[fn.Name] = fn.Doc
+	}
+	for _, c := range ext.Data.Constants {
+		docs[c.Name] = c.Doc
+	}
+	assert.Equal(t, map[string]string{
+		"Policy":       "Policy configures retries.",
+		"Delay":        "Delay is a wait between attempts.",
+		"MaxAttempts":  "MaxAttempts bounds retries.",
+		"Base":         "",
+		"Factor":       "",
+		"Do":           "Do calls fn until it succeeds,\nbacking off exponentially.",
+		"undocumented": "",
+	}, docs)
+}
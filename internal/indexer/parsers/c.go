@@ -4,7 +4,6 @@ import (
 	"context"
 	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
 	"os"
-	"path/filepath"
 	"strings"
 
 	sitter "github.com/tree-sitter/go-tree-sitter"
@@ -30,7 +29,11 @@ func (p *cParser) ParseFile(ctx context.Context, filePath string) (*CodeExtracti
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses C source as the content of filePath.
+func (p *cParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -45,15 +48,8 @@ func (p *cParser) ParseFile(ctx context.Context, filePath string) (*CodeExtracti
 	rootNode := tree.RootNode()
 	lines := strings.Split(string(source), "\n")
 
-	// Determine language based on extension
-	lang := "c"
-	ext := strings.ToLower(filepath.Ext(filePath))
-	if ext == ".cpp" || ext == ".cc" || ext == ".hpp" {
-		lang = "cpp"
-	}
-
 	codeExtraction := &CodeExtraction{
-		Language:  lang,
+		Language:  p.lang,
 		FilePath:  filePath,
 		StartLine: 1,
 		EndLine:   int(rootNode.EndPosition().Row) + 1,
@@ -70,8 +66,14 @@ func (p *cParser) ParseFile(ctx context.Context, filePath string) (*CodeExtracti
 		},
 	}
 
-	// Count includes
-	p.countIncludes(rootNode, codeExtraction)
+	// Count includes and record included headers
+	extractIncludes(rootNode, source, codeExtraction)
+
+	// Record environment variables read
+	extractCConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
 
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
@@ -79,18 +81,6 @@ func (p *cParser) ParseFile(ctx context.Context, filePath string) (*CodeExtracti
 	return codeExtraction, nil
 }
 
-// countIncludes counts #include directives.
-func (p *cParser) countIncludes(node *sitter.Node, codeExtraction *CodeExtraction) {
-	count := 0
-	walkTree(node, func(n *sitter.Node) bool {
-		if n.Kind() == "preproc_include" {
-			count++
-		}
-		return true
-	})
-	codeExtraction.Symbols.ImportsCount = count
-}
-
 // extractStructure extracts structs, unions, enums, functions, and variables.
 func (p *cParser) extractStructure(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	walkTree(node, func(n *sitter.Node) bool {
@@ -127,6 +117,7 @@ func (p *cParser) extractStruct(node *sitter.Node, source []byte, lines []string
 		Type:      "struct",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, anyComment),
 	})
 
 	// Add to definitions
@@ -157,6 +148,7 @@ func (p *cParser) extractUnion(node *sitter.Node, source []byte, lines []string,
 		Type:      "union",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, anyComment),
 	})
 
 	// Add to definitions
@@ -187,6 +179,7 @@ func (p *cParser) extractEnum(node *sitter.Node, source []byte, lines []string,
 		Type:      "enum",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, anyComment),
 	})
 
 	// Add to definitions
@@ -226,6 +219,7 @@ func (p *cParser) extractFunction(node *sitter.Node, source []byte, lines []stri
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, anyComment),
 	})
 
 	// Add to definitions (signature only)
@@ -379,6 +373,7 @@ func (p *cParser) extractDeclarator(node *sitter.Node, source []byte, lines []st
 			Type:      typeName,
 			StartLine: startLine,
 			EndLine:   endLine,
+			Doc:       docComment(node, source, anyComment),
 		})
 	} else {
 		codeExtraction.Data.Variables = append(codeExtraction.Data.Variables, extraction.VariableInfo{
@@ -390,11 +385,3 @@ func (p *cParser) extractDeclarator(node *sitter.Node, source []byte, lines []st
 		})
 	}
 }
-
-// CppParser is an alias for CParser since C++ uses the same grammar.
-type CppParser = cParser
-
-// NewCppParser creates a new C++ parser.
-func NewCppParser() *CppParser {
-	return NewCParser()
-}
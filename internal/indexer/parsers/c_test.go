@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
@@ -577,3 +579,37 @@ func TestCPPParser_EmptyExtractionStructure(t *testing.T) {
 	// Definitions tier should have slice initialized
 	assert.NotNil(t, result.Definitions.Definitions)
 }
+
+func TestCParser_DocComments(t *testing.T) {
+	t.Parallel()
+
+	// Test: comments directly above declarations document them; trailing comments and blank lines don't
+	cPath := filepath.Join(t.TempDir(), "retry.c")
+	content := `/* Copyright header */
+
+// Backoff policy.
+typedef struct Backoff {
+    int base;
+} Backoff;
+
+/**
+ * Retries fn with exponential backoff.
+ */
+int retry(int (*fn)(void)) {
+    return fn();
+}
+
+int attempts = 0; // Trailing comment
+void reset(void) {}
+`
+	require.NoError(t, os.WriteFile(cPath, []byte(content), 0644))
+
+	result, err := NewCParser().ParseFile(context.Background(), cPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"Backoff": "Backoff policy.",
+		"retry":   "Retries fn with exponential backoff.",
+	}, docsByName(result))
+}
@@ -0,0 +1,436 @@
+package parsers
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// Kinds of configuration reads
+const (
+	configEnv = "env"    // Environment variable
+	configKey = "config" // Key of a configuration file or settings store
+)
+
+// functionKinds are the nodes of named functions and methods, across
+// languages.
+var functionKinds = map[string]bool{
+	"function_declaration":           true, // TypeScript/JavaScript
+	"generator_function_declaration": true,
+	"method_definition":              true,
+	"function_definition":            true, // Python, PHP, C/C++
+	"method_declaration":             true, // Java, PHP
+	"constructor_declaration":        true,
+	"method":                         true, // Ruby
+	"singleton_method":               true,
+	"function_item":                  true, // Rust
+}
+
+// classKinds are the nodes of the types and modules functions are declared
+// in, across languages.
+var classKinds = map[string]bool{
+	"class_declaration":          true, // TypeScript/JavaScript, Java, PHP
+	"abstract_class_declaration": true,
+	"interface_declaration":      true,
+	"enum_declaration":           true,
+	"trait_declaration":          true,
+	"class_definition":           true, // Python
+	"class":                      true, // Ruby
+	"module":                     true,
+	"impl_item":                  true, // Rust
+	"trait_item":                 true,
+	"class_specifier":            true, // C++
+	"struct_specifier":           true,
+}
+
+// springPlaceholder matches the keys of Spring property placeholders:
+// "${app.name}", "${app.timeout:30s}".
+var springPlaceholder = regexp.MustCompile(`\$\{([^}:]+)`)
+
+// addConfigRead records a read of key at node, attributed to its enclosing
+// function.
+func addConfigRead(codeExtraction *CodeExtraction, kind, key, readSource string, node *sitter.Node, source []byte) {
+	if key == "" {
+		return
+	}
+	codeExtraction.Symbols.ConfigReads = append(codeExtraction.Symbols.ConfigReads, extraction.ConfigRead{
+		Kind:     kind,
+		Key:      key,
+		Source:   readSource,
+		Function: enclosingFunction(node, source),
+		Line:     int(node.StartPosition().Row) + 1,
+	})
+}
+
+// enclosingFunction returns the name of the function node is in, qualified
+// by its class ("OrderService.load"), the class for class-level code, or ""
+// at the top level. Anonymous functions belong to the variable or property
+// they are assigned to, else to their own enclosing function.
+func enclosingFunction(node *sitter.Node, source []byte) string {
+	function := ""
+	for n := node.Parent(); n != nil; n = n.Parent() {
+		kind := n.Kind()
+		switch {
+		case function == "" && functionKinds[kind]:
+			function = functionName(n, source)
+		case function == "" && (kind == "arrow_function" || kind == "function_expression" || kind == "function"):
+			function = assignedName(n, source)
+		case classKinds[kind]:
+			name := n.ChildByFieldName("name")
+			if kind == "impl_item" {
+				name = n.ChildByFieldName("type")
+			}
+			if name == nil {
+				continue // Anonymous class or Python module
+			}
+			class := baseTypeName(extractNodeText(name, source))
+			switch {
+			case function == "":
+				return class
+			case strings.Contains(function, "."):
+				return function // Qualified C++ definition: Server::start
+			}
+			return class + "." + function
+		}
+	}
+	return function
+}
+
+// functionName returns the name of a function node; C and C++ functions are
+// named by their declarator ("Server::start" as "Server.start").
+func functionName(fn *sitter.Node, source []byte) string {
+	if name := fn.ChildByFieldName("name"); name != nil {
+		return extractNodeText(name, source)
+	}
+	for d := fn.ChildByFieldName("declarator"); d != nil; d = d.ChildByFieldName("declarator") {
+		if d.Kind() == "function_declarator" {
+			if name := d.ChildByFieldName("declarator"); name != nil {
+				return strings.ReplaceAll(extractNodeText(name, source), "::", ".")
+			}
+		}
+	}
+	return ""
+}
+
+// assignedName returns the variable, field or property an anonymous
+// JavaScript function is assigned to, if any.
+func assignedName(fn *sitter.Node, source []byte) string {
+	parent := fn.Parent()
+	if parent == nil {
+		return ""
+	}
+	switch parent.Kind() {
+	case "variable_declarator", "public_field_definition", "field_definition":
+		if name := parent.ChildByFieldName("name"); name != nil && name.Kind() != "object_pattern" {
+			return extractNodeText(name, source)
+		}
+	case "pair":
+		if key := parent.ChildByFieldName("key"); key != nil {
+			return extractNodeText(key, source)
+		}
+	}
+	return ""
+}
+
+// firstArgument returns the first argument of a call's argument list,
+// unwrapping PHP's argument nodes.
+func firstArgument(args *sitter.Node) *sitter.Node {
+	if args == nil || args.NamedChildCount() == 0 {
+		return nil
+	}
+	arg := args.NamedChild(0)
+	if arg.Kind() == "argument" && arg.NamedChildCount() > 0 {
+		arg = arg.NamedChild(0)
+	}
+	return arg
+}
+
+// extractJSConfigReads records environment reads (process.env.PORT,
+// process.env['PORT'], const { PORT } = process.env, import.meta.env.VITE_X)
+// and the keys read through node-config (config.get('db.host')) and NestJS's
+// ConfigService.
+func extractJSConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	nodeConfig := importsModule(codeExtraction, "config")
+	nestConfig := importsModule(codeExtraction, "@nestjs/config")
+
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "member_expression":
+			if env := jsEnvObject(n.ChildByFieldName("object"), source); env != "" {
+				if prop := n.ChildByFieldName("property"); prop != nil && prop.Kind() == "property_identifier" {
+					addConfigRead(codeExtraction, configEnv, extractNodeText(prop, source), env, n, source)
+				}
+			}
+		case "subscript_expression":
+			if env := jsEnvObject(n.ChildByFieldName("object"), source); env != "" {
+				if key, ok := literalString(n.ChildByFieldName("index"), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, env, n, source)
+				}
+			}
+		case "variable_declarator":
+			pattern := n.ChildByFieldName("name")
+			env := jsEnvObject(n.ChildByFieldName("value"), source)
+			if pattern == nil || pattern.Kind() != "object_pattern" || env == "" {
+				break
+			}
+			for i := 0; i < int(pattern.NamedChildCount()); i++ {
+				prop := pattern.NamedChild(uint(i))
+				switch prop.Kind() {
+				case "shorthand_property_identifier_pattern":
+					addConfigRead(codeExtraction, configEnv, extractNodeText(prop, source), env, prop, source)
+				case "pair_pattern":
+					key := prop.ChildByFieldName("key")
+					name, ok := literalString(key, source)
+					if !ok && key != nil && key.Kind() == "property_identifier" {
+						name, ok = extractNodeText(key, source), true
+					}
+					if ok {
+						addConfigRead(codeExtraction, configEnv, name, env, prop, source)
+					}
+				}
+			}
+		case "call_expression":
+			fn := n.ChildByFieldName("function")
+			if fn == nil || fn.Kind() != "member_expression" {
+				break
+			}
+			object := extractNodeText(fn.ChildByFieldName("object"), source)
+			method := extractNodeText(fn.ChildByFieldName("property"), source)
+			readSource := ""
+			switch {
+			case nodeConfig && object == "config" && (method == "get" || method == "has"):
+				readSource = "config"
+			case nestConfig && strings.HasSuffix(object, "configService") && (method == "get" || method == "getOrThrow"):
+				readSource = "ConfigService"
+			}
+			if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok && readSource != "" {
+				addConfigRead(codeExtraction, configKey, key, readSource, n, source)
+			}
+		}
+		return true
+	})
+}
+
+// jsEnvObject returns "process.env" or "import.meta.env" if node is one of
+// them, else "".
+func jsEnvObject(node *sitter.Node, source []byte) string {
+	if node == nil || node.Kind() != "member_expression" {
+		return ""
+	}
+	if extractNodeText(node.ChildByFieldName("property"), source) != "env" {
+		return ""
+	}
+	object := node.ChildByFieldName("object")
+	switch {
+	case object == nil:
+		return ""
+	case object.Kind() == "identifier" && extractNodeText(object, source) == "process":
+		return "process.env"
+	case object.Kind() == "meta_property":
+		return "import.meta.env"
+	}
+	return ""
+}
+
+// extractPythonConfigReads records environment reads (os.environ['X'],
+// os.environ.get('X'), os.getenv('X'), with environ and getenv imported
+// from os) and python-decouple's config('X').
+func extractPythonConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	importsOS := importsModule(codeExtraction, "os")
+	importsDecouple := importsModule(codeExtraction, "decouple")
+	isEnviron := func(node *sitter.Node) bool {
+		switch {
+		case node == nil:
+			return false
+		case node.Kind() == "attribute":
+			return extractNodeText(node, source) == "os.environ"
+		}
+		return importsOS && node.Kind() == "identifier" && extractNodeText(node, source) == "environ"
+	}
+
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "subscript":
+			if isEnviron(n.ChildByFieldName("value")) {
+				if key, ok := literalString(n.ChildByFieldName("subscript"), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, "os.environ", n, source)
+				}
+			}
+		case "call":
+			fn := n.ChildByFieldName("function")
+			if fn == nil {
+				break
+			}
+			readSource := ""
+			switch text := extractNodeText(fn, source); {
+			case fn.Kind() == "attribute" && isEnviron(fn.ChildByFieldName("object")):
+				switch extractNodeText(fn.ChildByFieldName("attribute"), source) {
+				case "get", "setdefault", "pop":
+					readSource = "os.environ"
+				}
+			case text == "os.getenv" || (importsOS && text == "getenv"):
+				readSource = "os.getenv"
+			case importsDecouple && text == "config":
+				readSource = "decouple"
+			}
+			if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok && readSource != "" {
+				addConfigRead(codeExtraction, configEnv, key, readSource, n, source)
+			}
+		}
+		return true
+	})
+}
+
+// extractJavaConfigReads records environment reads (System.getenv), system
+// properties (System.getProperty) and Spring configuration keys: @Value
+// placeholders ("${app.name:default}") and Environment.getProperty on
+// fields named env or environment.
+func extractJavaConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "method_invocation":
+			object := strings.TrimPrefix(extractNodeText(n.ChildByFieldName("object"), source), "this.")
+			method := extractNodeText(n.ChildByFieldName("name"), source)
+			key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source)
+			if !ok {
+				break
+			}
+			switch {
+			case object == "System" && method == "getenv":
+				addConfigRead(codeExtraction, configEnv, key, "System.getenv", n, source)
+			case object == "System" && method == "getProperty":
+				addConfigRead(codeExtraction, configKey, key, "System.getProperty", n, source)
+			case (object == "env" || object == "environment") &&
+				(method == "getProperty" || method == "getRequiredProperty" || method == "containsProperty"):
+				addConfigRead(codeExtraction, configKey, key, "Environment", n, source)
+			}
+		case "annotation":
+			if extractNodeText(n.ChildByFieldName("name"), source) != "Value" {
+				break
+			}
+			value, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source)
+			if !ok {
+				break
+			}
+			for _, m := range springPlaceholder.FindAllStringSubmatch(value, -1) {
+				addConfigRead(codeExtraction, configKey, strings.TrimSpace(m[1]), "@Value", n, source)
+			}
+		}
+		return true
+	})
+}
+
+// extractRubyConfigReads records environment reads: ENV['X'],
+// ENV.fetch('X'), ENV.key?('X').
+func extractRubyConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "element_reference":
+			if extractNodeText(n.ChildByFieldName("object"), source) == "ENV" && n.NamedChildCount() > 1 {
+				if key, ok := literalString(n.NamedChild(1), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, "ENV", n, source)
+				}
+			}
+		case "call":
+			if extractNodeText(n.ChildByFieldName("receiver"), source) != "ENV" {
+				break
+			}
+			switch extractNodeText(n.ChildByFieldName("method"), source) {
+			case "fetch", "key?", "has_key?", "include?":
+				if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, "ENV", n, source)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// extractRustConfigReads records environment reads: std::env::var,
+// env::var_os, dotenvy::var, and the env! and option_env! macros.
+func extractRustConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "call_expression":
+			fn := n.ChildByFieldName("function")
+			if fn == nil || fn.Kind() != "scoped_identifier" {
+				break
+			}
+			path := extractNodeText(fn.ChildByFieldName("path"), source)
+			name := extractNodeText(fn.ChildByFieldName("name"), source)
+			if name != "var" && name != "var_os" {
+				break
+			}
+			switch path {
+			case "env", "std::env":
+				path = "std::env"
+			case "dotenvy", "dotenv":
+			default:
+				return true
+			}
+			if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok {
+				addConfigRead(codeExtraction, configEnv, key, path+"::"+name, n, source)
+			}
+		case "macro_invocation":
+			macro := extractNodeText(n.ChildByFieldName("macro"), source)
+			if macro != "env" && macro != "option_env" {
+				break
+			}
+			if tokens := findChildByType(n, "token_tree"); tokens != nil {
+				if key, ok := literalString(firstArgument(tokens), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, macro+"!", n, source)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// extractPHPConfigReads records environment reads (getenv('X'),
+// $_ENV['X'], Laravel's env('X')) and Laravel configuration keys
+// (config('app.name')).
+func extractPHPConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "function_call_expression":
+			kind := configEnv
+			fn := extractNodeText(n.ChildByFieldName("function"), source)
+			switch fn {
+			case "getenv", "env":
+			case "config":
+				kind = configKey
+			default:
+				return true
+			}
+			if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok {
+				addConfigRead(codeExtraction, kind, key, fn, n, source)
+			}
+		case "subscript_expression":
+			if n.NamedChildCount() > 1 && extractNodeText(n.NamedChild(0), source) == "$_ENV" {
+				if key, ok := literalString(n.NamedChild(1), source); ok {
+					addConfigRead(codeExtraction, configEnv, key, "$_ENV", n, source)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// extractCConfigReads records environment reads: getenv("X"),
+// secure_getenv("X"), std::getenv("X").
+func extractCConfigReads(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "call_expression" {
+			return true
+		}
+		switch fn := extractNodeText(n.ChildByFieldName("function"), source); fn {
+		case "getenv", "secure_getenv", "std::getenv":
+			if key, ok := literalString(firstArgument(n.ChildByFieldName("arguments")), source); ok {
+				addConfigRead(codeExtraction, configEnv, key, strings.TrimPrefix(fn, "std::"), n, source)
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,219 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for configuration read extraction:
+// - process.env member, subscript and destructuring reads, import.meta.env,
+//   node-config and NestJS ConfigService keys (only when imported)
+// - os.environ subscripts and get, os.getenv, names imported from os, decouple
+// - System.getenv/getProperty, Environment.getProperty and @Value placeholders
+// - Ruby ENV[] and ENV.fetch; Rust env::var and env!; PHP getenv, $_ENV, env() and config()
+// - C getenv and C++ std::getenv
+// - Reads are attributed to the enclosing function, qualified by class, or the class
+//   for class-level code; dynamic keys are skipped
+
+func parseTestConfigReads(t *testing.T, parser routeParser, relPath, source string) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := parser.ParseFile(context.Background(), path)
+	require.NoError(t, err)
+
+	var out []string
+	for _, r := range result.Symbols.ConfigReads {
+		out = append(out, fmt.Sprintf("%s %s (%s) in %q @%d", r.Kind, r.Key, r.Source, r.Function, r.Line))
+	}
+	return out
+}
+
+func TestConfigReads_TypeScript(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewTypeScriptParser(), "config.ts", `import config from 'config';
+import { ConfigService } from '@nestjs/config';
+
+const port = process.env.PORT;
+const { DATABASE_URL, REDIS_URL: redis = 'x' } = process.env;
+
+export class Server {
+  constructor(private configService: ConfigService) {}
+  start() {
+    const host = config.get('server.host');
+    return this.configService.get('JWT_SECRET') + process.env['API_KEY'];
+  }
+}
+
+export const loader = () => import.meta.env.VITE_API_URL;
+const key = process.env[name];
+cache.get('not.config');
+`)
+
+	assert.Equal(t, []string{
+		`env PORT (process.env) in "" @4`,
+		`env DATABASE_URL (process.env) in "" @5`,
+		`env REDIS_URL (process.env) in "" @5`,
+		`config server.host (config) in "Server.start" @10`,
+		`config JWT_SECRET (ConfigService) in "Server.start" @11`,
+		`env API_KEY (process.env) in "Server.start" @11`,
+		`env VITE_API_URL (import.meta.env) in "loader" @15`,
+	}, reads)
+}
+
+func TestConfigReads_ConfigLibrariesNeedImport(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewJavaScriptParser(), "app.js", `const value = config.get('db.host');
+`)
+	assert.Empty(t, reads)
+}
+
+func TestConfigReads_Python(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewPythonParser(), "settings.py", `import os
+from os import environ
+from decouple import config
+
+DEBUG = os.environ.get("DEBUG", "0")
+
+class Settings:
+    secret = os.environ["SECRET_KEY"]
+
+    def database(self):
+        return environ["DATABASE_URL"], os.getenv("DB_POOL")
+
+def mail():
+    return config("SMTP_HOST"), os.environ.get(name)
+`)
+
+	assert.Equal(t, []string{
+		`env DEBUG (os.environ) in "" @5`,
+		`env SECRET_KEY (os.environ) in "Settings" @8`,
+		`env DATABASE_URL (os.environ) in "Settings.database" @11`,
+		`env DB_POOL (os.getenv) in "Settings.database" @11`,
+		`env SMTP_HOST (decouple) in "mail" @14`,
+	}, reads)
+}
+
+func TestConfigReads_Java(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewJavaParser(), "AppConfig.java", `class AppConfig {
+    @Value("${app.name:cortex}")
+    private String name;
+
+    @Value("jdbc:${db.host}:${db.port}")
+    private String url;
+
+    String token() {
+        return System.getenv("API_TOKEN") + System.getProperty("user.home") + this.env.getProperty("app.timeout");
+    }
+}
+`)
+
+	assert.Equal(t, []string{
+		`config app.name (@Value) in "AppConfig" @2`,
+		`config db.host (@Value) in "AppConfig" @5`,
+		`config db.port (@Value) in "AppConfig" @5`,
+		`env API_TOKEN (System.getenv) in "AppConfig.token" @9`,
+		`config user.home (System.getProperty) in "AppConfig.token" @9`,
+		`config app.timeout (Environment) in "AppConfig.token" @9`,
+	}, reads)
+}
+
+func TestConfigReads_Ruby(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewRubyParser(), "app/services/mailer.rb", `module Billing
+  class Mailer
+    def host
+      ENV["SMTP_HOST"] || ENV.fetch("SMTP_FALLBACK", "localhost")
+    end
+  end
+end
+`)
+
+	assert.Equal(t, []string{
+		`env SMTP_HOST (ENV) in "Mailer.host" @4`,
+		`env SMTP_FALLBACK (ENV) in "Mailer.host" @4`,
+	}, reads)
+}
+
+func TestConfigReads_Rust(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewRustParser(), "src/config.rs", `use std::env;
+
+const VERSION: &str = env!("CARGO_PKG_VERSION");
+
+impl Config {
+    fn from_env() -> Self {
+        let url = env::var("DATABASE_URL").unwrap();
+        let home = std::env::var_os("HOME");
+        let key = dotenvy::var("API_KEY");
+        Config { url }
+    }
+}
+`)
+
+	assert.Equal(t, []string{
+		`env CARGO_PKG_VERSION (env!) in "" @3`,
+		`env DATABASE_URL (std::env::var) in "Config.from_env" @7`,
+		`env HOME (std::env::var_os) in "Config.from_env" @8`,
+		`env API_KEY (dotenvy::var) in "Config.from_env" @9`,
+	}, reads)
+}
+
+func TestConfigReads_PHP(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewPhpParser(), "src/Mailer.php", `<?php
+class Mailer {
+    public function host() {
+        return getenv('SMTP_HOST') ?: $_ENV["SMTP_FALLBACK"];
+    }
+    public function name() {
+        return env('APP_NAME', 'cortex') . config('mail.from.name');
+    }
+}
+`)
+
+	assert.Equal(t, []string{
+		`env SMTP_HOST (getenv) in "Mailer.host" @4`,
+		`env SMTP_FALLBACK ($_ENV) in "Mailer.host" @4`,
+		`env APP_NAME (env) in "Mailer.name" @7`,
+		`config mail.from.name (config) in "Mailer.name" @7`,
+	}, reads)
+}
+
+func TestConfigReads_C(t *testing.T) {
+	t.Parallel()
+
+	reads := parseTestConfigReads(t, NewCParser(), "main.c", `#include <stdlib.h>
+
+int main(void) {
+    char *home = getenv("HOME");
+    return 0;
+}
+`)
+	assert.Equal(t, []string{`env HOME (getenv) in "main" @4`}, reads)
+
+	reads = parseTestConfigReads(t, NewCppParser(), "server.cpp", `#include <cstdlib>
+
+void Server::start() {
+    auto port = std::getenv("PORT");
+}
+`)
+	assert.Equal(t, []string{`env PORT (getenv) in "Server.start" @4`}, reads)
+}
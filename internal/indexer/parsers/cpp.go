@@ -0,0 +1,495 @@
+package parsers
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+)
+
+// cppParser parses C++ files with the tree-sitter-cpp grammar: namespaces,
+// classes, structs, unions, enums, aliases and templates of them; free
+// functions, methods (defined in a class body, declared in it, or defined
+// out of line as Class::method) and function prototypes; namespace-scope
+// constants and variables; and #include directives.
+type cppParser struct {
+	*treeSitterParser
+}
+
+// NewCppParser creates a new C++ parser.
+func NewCppParser() *cppParser {
+	lang := sitter.NewLanguage(cpp.Language())
+	return &cppParser{
+		treeSitterParser: newTreeSitterParser(lang, "cpp"),
+	}
+}
+
+// ParseFile parses a C++ source file.
+func (p *cppParser) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	source, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return p.ParseSource(ctx, filePath, source)
+}
+
+// ParseSource parses C++ source as the content of filePath.
+func (p *cppParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
+	parser := sitter.NewParser()
+	defer parser.Close()
+
+	parser.SetLanguage(p.language)
+
+	tree := parser.Parse(source, nil)
+	if tree == nil {
+		return nil, nil // Return nil for unparseable files
+	}
+	defer tree.Close()
+
+	rootNode := tree.RootNode()
+	lines := strings.Split(string(source), "\n")
+
+	codeExtraction := &CodeExtraction{
+		Language:  p.lang,
+		FilePath:  filePath,
+		StartLine: 1,
+		EndLine:   int(rootNode.EndPosition().Row) + 1,
+		Symbols: &extraction.SymbolsData{
+			Types:     []extraction.SymbolInfo{},
+			Functions: []extraction.SymbolInfo{},
+		},
+		Definitions: &extraction.DefinitionsData{
+			Definitions: []extraction.Definition{},
+		},
+		Data: &extraction.DataData{
+			Constants: []extraction.ConstantInfo{},
+			Variables: []extraction.VariableInfo{},
+		},
+	}
+
+	// Count includes and record included headers
+	extractIncludes(rootNode, source, codeExtraction)
+
+	// Record environment variables read
+	extractCConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
+
+	// Extract symbols, definitions, and data
+	w := &cppWalker{source: source, lines: lines, codeExtraction: codeExtraction}
+	w.walkScope(rootNode, "")
+	codeExtraction.Symbols.PackageName = strings.Join(w.namespaces, ", ")
+
+	return codeExtraction, nil
+}
+
+// cppWalker walks the declarations of a C++ file, tracking the enclosing
+// class.
+type cppWalker struct {
+	source         []byte
+	lines          []string
+	codeExtraction *CodeExtraction
+	namespaces     []string // Named namespaces, in order of appearance
+}
+
+// walkScope extracts the declarations of a translation unit, namespace body
+// or class body. class is the enclosing class, "" at namespace scope.
+func (w *cppWalker) walkScope(scope *sitter.Node, class string) {
+	for i := 0; i < int(scope.NamedChildCount()); i++ {
+		w.walkDeclaration(scope.NamedChild(uint(i)), scope.NamedChild(uint(i)), class)
+	}
+}
+
+// walkDeclaration extracts one declaration. outer is the node the
+// declaration's location and code span: the template_declaration wrapping
+// a template, node itself otherwise.
+func (w *cppWalker) walkDeclaration(node, outer *sitter.Node, class string) {
+	switch node.Kind() {
+	case "namespace_definition":
+		if name := node.ChildByFieldName("name"); name != nil {
+			w.addNamespace(extractNodeText(name, w.source))
+		}
+		if body := node.ChildByFieldName("body"); body != nil {
+			w.walkScope(body, "")
+		}
+	case "linkage_specification": // extern "C" { ... }
+		if body := node.ChildByFieldName("body"); body != nil {
+			if body.Kind() == "declaration_list" {
+				w.walkScope(body, class)
+			} else {
+				w.walkDeclaration(body, body, class)
+			}
+		}
+	case "template_declaration":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(uint(i))
+			if child.Kind() != "template_parameter_list" {
+				w.walkDeclaration(child, outer, class)
+			}
+		}
+	case "class_specifier", "struct_specifier", "union_specifier":
+		w.extractClass(node, outer)
+	case "enum_specifier":
+		if node.ChildByFieldName("body") != nil {
+			w.addType(node, outer, "enum")
+		}
+	case "alias_declaration", "type_definition":
+		w.extractAlias(node, outer)
+	case "function_definition":
+		w.extractFunction(node, outer, class)
+	case "declaration", "field_declaration":
+		w.extractDeclaration(node, outer, class)
+	case "access_specifier", "comment":
+	default:
+		// Declarations in preprocessor conditionals (#ifdef ... #endif)
+		if strings.HasPrefix(node.Kind(), "preproc_if") || node.Kind() == "preproc_else" || node.Kind() == "preproc_elif" {
+			w.walkScope(node, class)
+		}
+	}
+}
+
+// addNamespace records a namespace name once.
+func (w *cppWalker) addNamespace(name string) {
+	for _, ns := range w.namespaces {
+		if ns == name {
+			return
+		}
+	}
+	w.namespaces = append(w.namespaces, name)
+}
+
+// extractClass extracts a class, struct or union with a body, its base
+// classes and its members.
+func (w *cppWalker) extractClass(node, outer *sitter.Node) {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return // Forward declaration or elaborated type specifier
+	}
+	name := w.addType(node, outer, strings.TrimSuffix(node.Kind(), "_specifier"))
+	if name == "" {
+		return
+	}
+
+	if bases := findChildByType(node, "base_class_clause"); bases != nil {
+		addSupertypes(w.codeExtraction, name, "extends", bases, w.source)
+	}
+	w.walkScope(body, name)
+}
+
+// addType records a named type and returns its name, or "" for anonymous
+// types.
+func (w *cppWalker) addType(node, outer *sitter.Node, kind string) string {
+	nameNode := node.ChildByFieldName("name")
+	if nameNode == nil {
+		return ""
+	}
+	name := baseTypeName(extractNodeText(nameNode, w.source))
+	startLine := int(outer.StartPosition().Row) + 1
+	endLine := int(outer.EndPosition().Row) + 1
+
+	w.codeExtraction.Symbols.Types = append(w.codeExtraction.Symbols.Types, extraction.SymbolInfo{
+		Name:      name,
+		Type:      kind,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Doc:       docComment(outer, w.source, anyComment),
+	})
+	w.codeExtraction.Definitions.Definitions = append(w.codeExtraction.Definitions.Definitions, extraction.Definition{
+		Name:      name,
+		Type:      kind,
+		Code:      extractLines(w.lines, startLine, endLine),
+		StartLine: startLine,
+		EndLine:   endLine,
+	})
+	return name
+}
+
+// extractAlias extracts "using Name = T;" and typedefs. A typedef of a
+// class or enum body ("typedef struct {...} Name;") records the body too.
+func (w *cppWalker) extractAlias(node, outer *sitter.Node) {
+	if node.Kind() == "alias_declaration" {
+		w.addType(node, outer, "type")
+		return
+	}
+
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		switch typeNode.Kind() {
+		case "class_specifier", "struct_specifier", "union_specifier", "enum_specifier":
+			if typeNode.ChildByFieldName("name") != nil {
+				w.walkDeclaration(typeNode, outer, "")
+			}
+		}
+	}
+	declarator := node.ChildByFieldName("declarator")
+	name := cppDeclaratorName(declarator, w.source)
+	if name == "" {
+		return
+	}
+	startLine := int(outer.StartPosition().Row) + 1
+	endLine := int(outer.EndPosition().Row) + 1
+	w.codeExtraction.Symbols.Types = append(w.codeExtraction.Symbols.Types, extraction.SymbolInfo{
+		Name:      name,
+		Type:      "type",
+		StartLine: startLine,
+		EndLine:   endLine,
+		Doc:       docComment(outer, w.source, anyComment),
+	})
+	w.codeExtraction.Definitions.Definitions = append(w.codeExtraction.Definitions.Definitions, extraction.Definition{
+		Name:      name,
+		Type:      "type",
+		Code:      extractLines(w.lines, startLine, endLine),
+		StartLine: startLine,
+		EndLine:   endLine,
+	})
+}
+
+// extractFunction extracts a function or method definition. Functions in a
+// class body and out-of-line definitions (Class::method) are methods.
+func (w *cppWalker) extractFunction(node, outer *sitter.Node, class string) {
+	declarator := cppFunctionDeclarator(node.ChildByFieldName("declarator"))
+	if declarator == nil {
+		return
+	}
+	name, owner := cppFunctionName(declarator.ChildByFieldName("declarator"), w.source)
+	if name == "" {
+		return
+	}
+	if owner == "" {
+		owner = class
+	}
+
+	kind := "function"
+	if owner != "" {
+		kind = "method"
+	}
+	signature := cppSignature(node, w.source)
+	startLine := int(outer.StartPosition().Row) + 1
+	endLine := int(outer.EndPosition().Row) + 1
+
+	w.codeExtraction.Symbols.Functions = append(w.codeExtraction.Symbols.Functions, extraction.SymbolInfo{
+		Name:      name,
+		Type:      kind,
+		StartLine: startLine,
+		EndLine:   endLine,
+		Signature: signature,
+		Doc:       docComment(outer, w.source, anyComment),
+	})
+
+	// Definitions hold the signature only
+	sigLine := int(node.StartPosition().Row) + 1
+	w.codeExtraction.Definitions.Definitions = append(w.codeExtraction.Definitions.Definitions, extraction.Definition{
+		Name:      name,
+		Type:      kind,
+		Code:      signature + " { ... }",
+		StartLine: sigLine,
+		EndLine:   sigLine,
+	})
+}
+
+// extractDeclaration extracts a declaration: a function prototype or
+// method declaration, or namespace-scope constants and variables
+// (constexpr and const ones are constants). Class members other than
+// methods are part of their class definition.
+func (w *cppWalker) extractDeclaration(node, outer *sitter.Node, class string) {
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		switch typeNode.Kind() {
+		case "class_specifier", "struct_specifier", "union_specifier", "enum_specifier":
+			w.walkDeclaration(typeNode, typeNode, class) // struct S {...} s;
+		}
+	}
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if node.FieldNameForNamedChild(uint32(i)) != "declarator" {
+			continue
+		}
+		declarator := node.NamedChild(uint(i))
+		if fn := cppFunctionDeclarator(declarator); fn != nil {
+			w.addPrototype(node, outer, fn, class)
+			continue
+		}
+		if class == "" {
+			w.addValue(node, declarator)
+		}
+	}
+}
+
+// addPrototype records a function or method declaration without a body.
+func (w *cppWalker) addPrototype(node, outer, declarator *sitter.Node, class string) {
+	name, owner := cppFunctionName(declarator.ChildByFieldName("declarator"), w.source)
+	if name == "" {
+		return
+	}
+	kind := "function"
+	if owner != "" || class != "" {
+		kind = "method"
+	}
+	signature := strings.TrimSuffix(cppSignature(node, w.source), ";")
+	line := int(outer.StartPosition().Row) + 1
+	endLine := int(outer.EndPosition().Row) + 1
+
+	w.codeExtraction.Symbols.Functions = append(w.codeExtraction.Symbols.Functions, extraction.SymbolInfo{
+		Name:      name,
+		Type:      kind,
+		StartLine: line,
+		EndLine:   endLine,
+		Signature: signature,
+		Doc:       docComment(outer, w.source, anyComment),
+	})
+	if class == "" {
+		// Methods are shown by their class definition
+		w.codeExtraction.Definitions.Definitions = append(w.codeExtraction.Definitions.Definitions, extraction.Definition{
+			Name:      name,
+			Type:      kind,
+			Code:      signature + ";",
+			StartLine: line,
+			EndLine:   endLine,
+		})
+	}
+}
+
+// addValue records a namespace-scope variable or constant.
+func (w *cppWalker) addValue(node, declarator *sitter.Node) {
+	var value string
+	if declarator.Kind() == "init_declarator" {
+		if valueNode := declarator.ChildByFieldName("value"); valueNode != nil {
+			value = extractNodeText(valueNode, w.source)
+		}
+		declarator = declarator.ChildByFieldName("declarator")
+	}
+	name := cppDeclaratorName(declarator, w.source)
+	if name == "" {
+		return
+	}
+
+	typeName := ""
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		typeName = extractNodeText(typeNode, w.source)
+	}
+	isConst := false
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(uint(i))
+		if child.Kind() == "type_qualifier" {
+			qualifier := extractNodeText(child, w.source)
+			isConst = isConst || qualifier == "const" || qualifier == "constexpr"
+		}
+	}
+
+	startLine := int(node.StartPosition().Row) + 1
+	endLine := int(node.EndPosition().Row) + 1
+	if isConst {
+		w.codeExtraction.Data.Constants = append(w.codeExtraction.Data.Constants, extraction.ConstantInfo{
+			Name:      name,
+			Value:     value,
+			Type:      typeName,
+			StartLine: startLine,
+			EndLine:   endLine,
+			Doc:       docComment(node, w.source, anyComment),
+		})
+		return
+	}
+	w.codeExtraction.Data.Variables = append(w.codeExtraction.Data.Variables, extraction.VariableInfo{
+		Name:      name,
+		Value:     value,
+		Type:      typeName,
+		StartLine: startLine,
+		EndLine:   endLine,
+	})
+}
+
+// cppFunctionDeclarator returns the function_declarator of a declarator,
+// looking through pointer and reference declarators ("int* f()"), or nil if
+// it doesn't declare a function.
+func cppFunctionDeclarator(node *sitter.Node) *sitter.Node {
+	for node != nil {
+		switch node.Kind() {
+		case "function_declarator":
+			return node
+		case "pointer_declarator":
+			node = node.ChildByFieldName("declarator")
+		case "reference_declarator":
+			if node.NamedChildCount() == 0 {
+				return nil
+			}
+			node = node.NamedChild(0)
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+// cppFunctionName returns the name of a function declarator's declarator
+// and, for qualified names ("Server::start", "ns::Server::~Server"), the
+// class (or namespace) it is qualified by.
+func cppFunctionName(node *sitter.Node, source []byte) (name, owner string) {
+	if node == nil {
+		return "", ""
+	}
+	text := extractNodeText(node, source)
+	if node.Kind() == "qualified_identifier" {
+		if i := strings.LastIndex(text, "::"); i >= 0 {
+			scope := text[:i]
+			if j := strings.LastIndex(scope, "::"); j >= 0 {
+				scope = scope[j+2:]
+			}
+			return strings.TrimSpace(text[i+2:]), baseTypeName(scope)
+		}
+	}
+	return strings.TrimSpace(text), ""
+}
+
+// cppDeclaratorName returns the declared name of a variable or typedef
+// declarator: "kName" for "* kName" or "kTable[4]".
+func cppDeclaratorName(node *sitter.Node, source []byte) string {
+	for node != nil {
+		switch node.Kind() {
+		case "identifier", "type_identifier", "field_identifier":
+			return extractNodeText(node, source)
+		case "pointer_declarator", "reference_declarator", "array_declarator", "init_declarator":
+			inner := node.ChildByFieldName("declarator")
+			if inner == nil && node.NamedChildCount() > 0 {
+				inner = node.NamedChild(0)
+			}
+			node = inner
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// cppSignature returns the text of a declaration up to its body, with
+// whitespace collapsed.
+func cppSignature(node *sitter.Node, source []byte) string {
+	end := node.EndByte()
+	if body := node.ChildByFieldName("body"); body != nil {
+		end = body.StartByte()
+	}
+	return strings.Join(strings.Fields(string(source[node.StartByte():end])), " ")
+}
+
+// extractIncludes counts the #include directives of a C or C++ file and
+// records each included header: "util/log.h" for a quoted include,
+// "<vector>" (brackets kept) for a system one.
+func extractIncludes(node *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	count := 0
+	walkTree(node, func(n *sitter.Node) bool {
+		if n.Kind() != "preproc_include" {
+			return true
+		}
+		count++
+		if path := n.ChildByFieldName("path"); path != nil {
+			header := extractNodeText(path, source)
+			if path.Kind() == "string_literal" {
+				header = strings.Trim(header, `"`)
+			}
+			addImport(codeExtraction, header, n)
+		}
+		return false
+	})
+	codeExtraction.Symbols.ImportsCount = count
+}
@@ -0,0 +1,133 @@
+package parsers
+
+// Test Plan for C++ Extraction:
+// - Namespaces (nested and qualified) are the package name; their declarations are extracted
+// - Classes and structs are types with their doc comments; base classes are "extends" relations
+// - Methods declared in classes and defined out of line (Server::start) are methods
+// - Templates, prototypes, aliases and enums are extracted
+// - constexpr and const globals are constants, others variables
+// - Includes are imports: quoted ones as written, system ones in angle brackets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCppSource = `#include <vector>
+#include "net/server.h"
+
+namespace net::http {
+
+// A server.
+class Server : public Base, private Other<int> {
+public:
+    Server();
+    ~Server();
+    void start() const;
+private:
+    int port_;
+};
+
+enum class Color { Red, Green };
+
+using Handler = std::function<void(int)>;
+
+template <typename T>
+T max(T a, T b) { return a > b ? a : b; }
+
+void free_fn(int x);
+
+void Server::start() const {
+    run();
+}
+
+constexpr int kDefaultPort = 8080;
+const char* const kName = "srv";
+int counter = 0;
+
+} // namespace net::http
+`
+
+func parseCpp(t *testing.T, content string) *CodeExtraction {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "server.cpp")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	result, err := NewCppParser().ParseFile(context.Background(), path)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	return result
+}
+
+func TestCppParser_Symbols(t *testing.T) {
+	t.Parallel()
+
+	result := parseCpp(t, testCppSource)
+	assert.Equal(t, "cpp", result.Language)
+	assert.Equal(t, "net::http", result.Symbols.PackageName)
+
+	types := make(map[string]extraction.SymbolInfo)
+	for _, typ := range result.Symbols.Types {
+		types[typ.Name] = typ
+	}
+	require.Contains(t, types, "Server")
+	assert.Equal(t, "class", types["Server"].Type)
+	assert.Equal(t, 7, types["Server"].StartLine)
+	assert.Equal(t, 14, types["Server"].EndLine)
+	assert.Equal(t, "A server.", types["Server"].Doc)
+	assert.Contains(t, types, "Color")
+	assert.Contains(t, types, "Handler")
+
+	var methods, functions []string
+	for _, fn := range result.Symbols.Functions {
+		if fn.Type == "method" {
+			methods = append(methods, fn.Name)
+		} else {
+			functions = append(functions, fn.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{"Server", "~Server", "start", "start"}, methods)
+	assert.ElementsMatch(t, []string{"max", "free_fn"}, functions)
+
+	var supertypes []string
+	for _, rel := range result.Symbols.Relations {
+		assert.Equal(t, "Server", rel.Type)
+		assert.Equal(t, "extends", rel.Kind)
+		supertypes = append(supertypes, rel.Supertype)
+	}
+	assert.Equal(t, []string{"Base", "Other"}, supertypes)
+}
+
+func TestCppParser_Data(t *testing.T) {
+	t.Parallel()
+
+	result := parseCpp(t, testCppSource)
+
+	var constants, variables []string
+	for _, c := range result.Data.Constants {
+		constants = append(constants, c.Name)
+	}
+	for _, v := range result.Data.Variables {
+		variables = append(variables, v.Name)
+	}
+	assert.Equal(t, []string{"kDefaultPort", "kName"}, constants)
+	assert.Equal(t, []string{"counter"}, variables)
+}
+
+func TestCppParser_Includes(t *testing.T) {
+	t.Parallel()
+
+	result := parseCpp(t, testCppSource)
+	assert.Equal(t, 2, result.Symbols.ImportsCount)
+
+	var paths []string
+	for _, imp := range result.Symbols.Imports {
+		paths = append(paths, imp.Path)
+	}
+	assert.Equal(t, []string{"<vector>", "net/server.h"}, paths)
+}
@@ -0,0 +1,883 @@
+package parsers
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// routeMethods maps the router methods Express, Fastify, Flask and FastAPI
+// register routes with to the HTTP method they accept.
+var routeMethods = map[string]string{
+	"get":     "GET",
+	"post":    "POST",
+	"put":     "PUT",
+	"patch":   "PATCH",
+	"delete":  "DELETE",
+	"head":    "HEAD",
+	"options": "OPTIONS",
+	"all":     "ANY",
+}
+
+// jsRouterNames are receivers that register routes even when the file
+// doesn't create them: routers passed in from the app's entry point.
+var jsRouterNames = map[string]bool{"app": true, "router": true, "server": true, "fastify": true, "api": true, "routes": true}
+
+// addEndpoint records a route registered at node, unless its path isn't
+// rooted (a dynamic or relative path).
+func addEndpoint(codeExtraction *CodeExtraction, ref extraction.EndpointRef, node *sitter.Node) {
+	if !strings.HasPrefix(ref.Path, "/") {
+		return
+	}
+	ref.Line = int(node.StartPosition().Row) + 1
+	codeExtraction.Symbols.Endpoints = append(codeExtraction.Symbols.Endpoints, ref)
+}
+
+// importsModule reports whether the file imports module or one of its
+// submodules ("django" matches "django.urls").
+func importsModule(codeExtraction *CodeExtraction, module string) bool {
+	for _, imp := range codeExtraction.Symbols.Imports {
+		if imp.Path == module || strings.HasPrefix(imp.Path, module+".") || strings.HasPrefix(imp.Path, module+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// literalString returns the value of a string literal without
+// interpolation, or of a Ruby symbol. ok is false for other nodes.
+func literalString(node *sitter.Node, source []byte) (string, bool) {
+	if node == nil {
+		return "", false
+	}
+	switch node.Kind() {
+	case "simple_symbol", "hash_key_symbol":
+		return strings.TrimPrefix(extractNodeText(node, source), ":"), true
+	case "string", "template_string", "string_literal", "encapsed_string":
+	default:
+		return "", false
+	}
+
+	var b strings.Builder
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(uint(i))
+		switch child.Kind() {
+		case "string_fragment", "string_content", "escape_sequence":
+			b.WriteString(extractNodeText(child, source))
+		case "string_start", "string_end":
+		default:
+			return "", false // Interpolation
+		}
+	}
+	return b.String(), true
+}
+
+// joinRoute appends a route path to a group prefix, adding the "/" Django,
+// Spring and Rails paths may leave out.
+func joinRoute(prefix, path string) string {
+	if path != "" && !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	switch {
+	case prefix == "":
+		return path
+	case path == "":
+		return prefix
+	}
+	return strings.TrimSuffix(prefix, "/") + path
+}
+
+// describeHandlerNode returns a handler as written, with the name and scope
+// it resolves by: "users.list" → ("list", "users"). Inline functions have
+// no name.
+func describeHandlerNode(node *sitter.Node, source []byte) (written, name, scope string) {
+	switch node.Kind() {
+	case "identifier":
+		written = extractNodeText(node, source)
+		return written, written, ""
+	case "member_expression", "attribute":
+		written = extractNodeText(node, source)
+		if i := strings.LastIndex(written, "."); i >= 0 {
+			scope = written[:i]
+			if j := strings.LastIndex(scope, "."); j >= 0 {
+				scope = scope[j+1:]
+			}
+			return written, written[i+1:], scope
+		}
+		return written, written, ""
+	case "call":
+		// Django class-based views: OrderView.as_view()
+		if fn := node.ChildByFieldName("function"); fn != nil && fn.Kind() == "attribute" {
+			if attr := fn.ChildByFieldName("attribute"); attr != nil && extractNodeText(attr, source) == "as_view" {
+				view := extractNodeText(fn.ChildByFieldName("object"), source)
+				return extractNodeText(node, source), baseTypeName(view), ""
+			}
+		}
+	}
+	return "", "", ""
+}
+
+// extractJSRoutes records Express and Fastify routes: app.get('/x', h),
+// router.route('/x').post(h), fastify.route({method, url, handler}).
+// Routes are registered on the app, routers the file creates, or receivers
+// conventionally named for routers, in files importing express or fastify.
+func extractJSRoutes(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	framework := ""
+	switch {
+	case importsModule(codeExtraction, "express"):
+		framework = "express"
+	case importsModule(codeExtraction, "fastify"):
+		framework = "fastify"
+	default:
+		return
+	}
+
+	routers := make(map[string]bool)
+	for name := range jsRouterNames {
+		routers[name] = true
+	}
+	walkTree(root, func(n *sitter.Node) bool {
+		// const orders = express.Router(), const app = fastify()
+		if n.Kind() == "variable_declarator" {
+			name, value := n.ChildByFieldName("name"), n.ChildByFieldName("value")
+			if name != nil && value != nil && value.Kind() == "call_expression" {
+				callee := extractNodeText(value.ChildByFieldName("function"), source)
+				if callee == "express" || callee == "fastify" || callee == "Fastify" || strings.HasSuffix(callee, "Router") {
+					routers[extractNodeText(name, source)] = true
+				}
+			}
+		}
+		return true
+	})
+
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "call_expression" {
+			return true
+		}
+		fn, args := n.ChildByFieldName("function"), n.ChildByFieldName("arguments")
+		if fn == nil || args == nil || fn.Kind() != "member_expression" || args.NamedChildCount() == 0 {
+			return true
+		}
+		object := fn.ChildByFieldName("object")
+		property := extractNodeText(fn.ChildByFieldName("property"), source)
+
+		if property == "route" && args.NamedChild(0).Kind() == "object" && isJSRouter(object, source, routers) {
+			addFastifyRoute(args.NamedChild(0), source, framework, codeExtraction, n)
+			return true
+		}
+		method, ok := routeMethods[property]
+		if !ok {
+			return true
+		}
+
+		var path string
+		first := 0
+		for object.Kind() == "call_expression" && isJSRouteChain(object, source) {
+			object = object.ChildByFieldName("function").ChildByFieldName("object") // route('/x').get(a).post(b)
+		}
+		if object.Kind() == "call_expression" {
+			// router.route('/x').post(h)
+			inner := object.ChildByFieldName("function")
+			innerArgs := object.ChildByFieldName("arguments")
+			if inner == nil || innerArgs == nil || inner.Kind() != "member_expression" || innerArgs.NamedChildCount() == 0 ||
+				extractNodeText(inner.ChildByFieldName("property"), source) != "route" ||
+				!isJSRouter(inner.ChildByFieldName("object"), source, routers) {
+				return true
+			}
+			if path, ok = literalString(innerArgs.NamedChild(0), source); !ok {
+				return true
+			}
+		} else {
+			if !isJSRouter(object, source, routers) || args.NamedChildCount() < 2 {
+				return true
+			}
+			if path, ok = literalString(args.NamedChild(0), source); !ok {
+				return true
+			}
+			first = 1
+		}
+		if int(args.NamedChildCount()) <= first {
+			return true
+		}
+
+		written, name, scope := describeHandlerNode(args.NamedChild(args.NamedChildCount()-1), source)
+		addEndpoint(codeExtraction, extraction.EndpointRef{
+			Framework:    framework,
+			Method:       method,
+			Path:         path,
+			Handler:      written,
+			HandlerName:  name,
+			HandlerScope: scope,
+		}, n)
+		return true
+	})
+}
+
+// isJSRouteChain reports whether call registers a method on a route:
+// the .get(a) of router.route('/x').get(a).post(b).
+func isJSRouteChain(call *sitter.Node, source []byte) bool {
+	fn := call.ChildByFieldName("function")
+	if fn == nil || fn.Kind() != "member_expression" {
+		return false
+	}
+	_, ok := routeMethods[extractNodeText(fn.ChildByFieldName("property"), source)]
+	object := fn.ChildByFieldName("object")
+	return ok && object != nil && object.Kind() == "call_expression"
+}
+
+// isJSRouter reports whether node is a known router: an identifier or
+// this.<name>.
+func isJSRouter(node *sitter.Node, source []byte, routers map[string]bool) bool {
+	if node == nil {
+		return false
+	}
+	switch node.Kind() {
+	case "identifier":
+		return routers[extractNodeText(node, source)]
+	case "member_expression":
+		object := node.ChildByFieldName("object")
+		return object != nil && object.Kind() == "this" && routers[extractNodeText(node.ChildByFieldName("property"), source)]
+	}
+	return false
+}
+
+// addFastifyRoute records a fastify.route({method, url, handler}) route.
+func addFastifyRoute(options *sitter.Node, source []byte, framework string, codeExtraction *CodeExtraction, call *sitter.Node) {
+	var methods []string
+	var path string
+	var handler *sitter.Node
+	for _, pair := range findChildrenByType(options, "pair") {
+		value := pair.ChildByFieldName("value")
+		switch extractNodeText(pair.ChildByFieldName("key"), source) {
+		case "method":
+			if value.Kind() == "array" {
+				for i := 0; i < int(value.NamedChildCount()); i++ {
+					if m, ok := literalString(value.NamedChild(uint(i)), source); ok {
+						methods = append(methods, strings.ToUpper(m))
+					}
+				}
+			} else if m, ok := literalString(value, source); ok {
+				methods = append(methods, strings.ToUpper(m))
+			}
+		case "url", "path":
+			path, _ = literalString(value, source)
+		case "handler":
+			handler = value
+		}
+	}
+
+	var written, name, scope string
+	if handler != nil {
+		written, name, scope = describeHandlerNode(handler, source)
+	}
+	for _, method := range methods {
+		addEndpoint(codeExtraction, extraction.EndpointRef{
+			Framework:    framework,
+			Method:       method,
+			Path:         path,
+			Handler:      written,
+			HandlerName:  name,
+			HandlerScope: scope,
+		}, call)
+	}
+}
+
+// extractPythonRoutes records Flask and FastAPI routes declared by
+// decorators (@app.route('/x', methods=[...]), @router.get('/x')), with the
+// url_prefix of Blueprints and prefix of APIRouters the file creates, and
+// Django urlpatterns entries (path('x/', views.x)).
+func extractPythonRoutes(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	framework := ""
+	switch {
+	case importsModule(codeExtraction, "fastapi"):
+		framework = "fastapi"
+	case importsModule(codeExtraction, "flask"):
+		framework = "flask"
+	case importsModule(codeExtraction, "django"):
+		extractDjangoRoutes(root, source, codeExtraction)
+		return
+	default:
+		return
+	}
+
+	// bp = Blueprint('orders', __name__, url_prefix='/orders'), router = APIRouter(prefix='/items')
+	prefixes := make(map[string]string)
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "assignment" {
+			return true
+		}
+		left, right := n.ChildByFieldName("left"), n.ChildByFieldName("right")
+		if left == nil || right == nil || left.Kind() != "identifier" || right.Kind() != "call" {
+			return true
+		}
+		for _, kw := range findChildrenByType(right.ChildByFieldName("arguments"), "keyword_argument") {
+			key := extractNodeText(kw.ChildByFieldName("name"), source)
+			if key == "url_prefix" || key == "prefix" {
+				if prefix, ok := literalString(kw.ChildByFieldName("value"), source); ok {
+					prefixes[extractNodeText(left, source)] = prefix
+				}
+			}
+		}
+		return true
+	})
+
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "decorated_definition" {
+			return true
+		}
+		def := n.ChildByFieldName("definition")
+		if def == nil || def.Kind() != "function_definition" {
+			return true
+		}
+		name := extractNodeText(def.ChildByFieldName("name"), source)
+		scope := ""
+		if class := enclosingPythonClass(n); class != nil {
+			scope = extractNodeText(class.ChildByFieldName("name"), source)
+		}
+
+		for _, decorator := range findChildrenByType(n, "decorator") {
+			call := findChildByType(decorator, "call")
+			if call == nil {
+				continue
+			}
+			fn, args := call.ChildByFieldName("function"), call.ChildByFieldName("arguments")
+			if fn == nil || args == nil || fn.Kind() != "attribute" {
+				continue
+			}
+			router := extractNodeText(fn.ChildByFieldName("object"), source)
+			attr := extractNodeText(fn.ChildByFieldName("attribute"), source)
+
+			var methods []string
+			if m, ok := routeMethods[attr]; ok && attr != "all" {
+				methods = []string{m}
+			} else if attr != "route" && attr != "api_route" {
+				continue
+			}
+
+			path, hasPath := "", false
+			if args.NamedChildCount() > 0 {
+				path, hasPath = literalString(args.NamedChild(0), source)
+			}
+			for _, kw := range findChildrenByType(args, "keyword_argument") {
+				value := kw.ChildByFieldName("value")
+				switch extractNodeText(kw.ChildByFieldName("name"), source) {
+				case "rule", "path":
+					path, hasPath = literalString(value, source)
+				case "methods":
+					methods = nil
+					for i := 0; i < int(value.NamedChildCount()); i++ {
+						if m, ok := literalString(value.NamedChild(uint(i)), source); ok {
+							methods = append(methods, strings.ToUpper(m))
+						}
+					}
+				}
+			}
+			if !hasPath {
+				continue
+			}
+			if len(methods) == 0 {
+				methods = []string{"GET"} // Flask's and FastAPI's default
+			}
+
+			written := name
+			if scope != "" {
+				written = scope + "." + name
+			}
+			for _, method := range methods {
+				addEndpoint(codeExtraction, extraction.EndpointRef{
+					Framework:    framework,
+					Method:       method,
+					Path:         joinRoute(prefixes[router], path),
+					Handler:      written,
+					HandlerName:  name,
+					HandlerScope: scope,
+				}, decorator)
+			}
+		}
+		return true
+	})
+}
+
+// enclosingPythonClass returns the class a definition is declared in, or nil.
+func enclosingPythonClass(node *sitter.Node) *sitter.Node {
+	for parent := node.Parent(); parent != nil; parent = parent.Parent() {
+		switch parent.Kind() {
+		case "class_definition":
+			return parent
+		case "function_definition":
+			return nil
+		}
+	}
+	return nil
+}
+
+// extractDjangoRoutes records the views Django's path(), re_path() and
+// url() route to. Django dispatches every method to a view, so routes
+// accept ANY; include()d URLconfs are left to the file declaring them, as
+// their prefix isn't known there.
+func extractDjangoRoutes(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "call" {
+			return true
+		}
+		fn, args := n.ChildByFieldName("function"), n.ChildByFieldName("arguments")
+		if fn == nil || args == nil || fn.Kind() != "identifier" || args.NamedChildCount() < 2 {
+			return true
+		}
+		switch extractNodeText(fn, source) {
+		case "path", "re_path", "url":
+		default:
+			return true
+		}
+		path, ok := literalString(args.NamedChild(0), source)
+		if !ok {
+			return true
+		}
+		view := args.NamedChild(1)
+		if view.Kind() == "call" && extractNodeText(view.ChildByFieldName("function"), source) == "include" {
+			return true
+		}
+		written, name, scope := describeHandlerNode(view, source)
+		addEndpoint(codeExtraction, extraction.EndpointRef{
+			Framework:    "django",
+			Method:       "ANY",
+			Path:         joinRoute("", strings.TrimSuffix(strings.TrimPrefix(path, "^"), "$")),
+			Handler:      written,
+			HandlerName:  name,
+			HandlerScope: scope,
+		}, n)
+		return true
+	})
+}
+
+// springMappings maps Spring's mapping annotations to the method they
+// accept; @RequestMapping takes its methods from the method attribute.
+var springMappings = map[string]string{
+	"RequestMapping": "",
+	"GetMapping":     "GET",
+	"PostMapping":    "POST",
+	"PutMapping":     "PUT",
+	"PatchMapping":   "PATCH",
+	"DeleteMapping":  "DELETE",
+}
+
+// extractSpringRoutes records the handler methods of Spring controllers,
+// with the class-level @RequestMapping path as prefix.
+func extractSpringRoutes(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "class_declaration" {
+			return true
+		}
+		className := extractNodeText(n.ChildByFieldName("name"), source)
+		prefixes := []string{""}
+		if mapping := springMapping(n, source); mapping != nil {
+			if paths, _ := springMappingArgs(mapping, source); len(paths) > 0 {
+				prefixes = paths
+			}
+		}
+
+		body := n.ChildByFieldName("body")
+		for _, method := range findChildrenByType(body, "method_declaration") {
+			mapping := springMapping(method, source)
+			if mapping == nil {
+				continue
+			}
+			paths, methods := springMappingArgs(mapping, source)
+			if len(paths) == 0 {
+				paths = []string{""}
+			}
+			if m := springMappings[extractNodeText(mapping.ChildByFieldName("name"), source)]; m != "" {
+				methods = []string{m}
+			} else if len(methods) == 0 {
+				methods = []string{"ANY"}
+			}
+
+			name := extractNodeText(method.ChildByFieldName("name"), source)
+			for _, prefix := range prefixes {
+				for _, path := range paths {
+					full := joinRoute(prefix, path)
+					if full == "" {
+						full = "/"
+					}
+					for _, m := range methods {
+						addEndpoint(codeExtraction, extraction.EndpointRef{
+							Framework:    "spring",
+							Method:       m,
+							Path:         full,
+							Handler:      className + "." + name,
+							HandlerName:  name,
+							HandlerScope: className,
+						}, mapping)
+					}
+				}
+			}
+		}
+		return true
+	})
+}
+
+// springMapping returns the mapping annotation among a declaration's
+// modifiers, or nil.
+func springMapping(decl *sitter.Node, source []byte) *sitter.Node {
+	modifiers := findChildByType(decl, "modifiers")
+	if modifiers == nil {
+		return nil
+	}
+	for i := 0; i < int(modifiers.NamedChildCount()); i++ {
+		child := modifiers.NamedChild(uint(i))
+		if child.Kind() != "annotation" && child.Kind() != "marker_annotation" {
+			continue
+		}
+		if _, ok := springMappings[baseTypeName(extractNodeText(child.ChildByFieldName("name"), source))]; ok {
+			return child
+		}
+	}
+	return nil
+}
+
+// springMappingArgs returns the paths (value or path attribute) and
+// methods (method attribute) of a mapping annotation.
+func springMappingArgs(annotation *sitter.Node, source []byte) (paths, methods []string) {
+	args := annotation.ChildByFieldName("arguments")
+	if args == nil {
+		return nil, nil
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(uint(i))
+		if arg.Kind() != "element_value_pair" {
+			paths = append(paths, springValues(arg, source)...)
+			continue
+		}
+		value := arg.ChildByFieldName("value")
+		switch extractNodeText(arg.ChildByFieldName("key"), source) {
+		case "value", "path":
+			paths = append(paths, springValues(value, source)...)
+		case "method":
+			for _, m := range springValues(value, source) {
+				methods = append(methods, baseTypeName(m)) // RequestMethod.GET
+			}
+		}
+	}
+	return paths, methods
+}
+
+// springValues returns the strings and constants (as written) of an
+// annotation value or array of values.
+func springValues(value *sitter.Node, source []byte) []string {
+	if value == nil {
+		return nil
+	}
+	if value.Kind() == "element_value_array_initializer" {
+		var values []string
+		for i := 0; i < int(value.NamedChildCount()); i++ {
+			values = append(values, springValues(value.NamedChild(uint(i)), source)...)
+		}
+		return values
+	}
+	if s, ok := literalString(value, source); ok {
+		return []string{s}
+	}
+	if value.Kind() == "field_access" || value.Kind() == "identifier" {
+		return []string{extractNodeText(value, source)}
+	}
+	return nil
+}
+
+// railsActions are the routes resources generates: action, method and
+// whether the route addresses a member (/orders/:id).
+var railsActions = []struct {
+	action, method string
+	member         bool
+	suffix         string
+}{
+	{"index", "GET", false, ""},
+	{"create", "POST", false, ""},
+	{"new", "GET", false, "/new"},
+	{"show", "GET", true, ""},
+	{"edit", "GET", true, "/edit"},
+	{"update", "PATCH", true, ""},
+	{"update", "PUT", true, ""},
+	{"destroy", "DELETE", true, ""},
+}
+
+// railsScope is the routing context of a block in config/routes.rb.
+type railsScope struct {
+	path           string // Path prefix (namespace, scope, parent resource)
+	module         string // Controller module prefix: "Api::"
+	controller     string // Controller of the enclosing resource: "orders"
+	memberPath     string // Path prefix of the resource's member block: /orders/:id
+	collectionPath string // Path prefix of the resource's collection block: /orders
+}
+
+// extractRailsRoutes records the routes of a Rails routes file: verb routes
+// (get 'x', to: 'c#a'), root, and resources with their nested resources,
+// member and collection routes, under namespace and scope prefixes.
+// Handlers are written "controller#action"; their scope is the controller
+// class.
+func extractRailsRoutes(root *sitter.Node, filePath string, source []byte, codeExtraction *CodeExtraction) {
+	slashed := filepath.ToSlash(filePath)
+	if strings.Contains(slashed, "config/routes/") {
+		// Routes split into files drawn by config/routes.rb: draw(:admin)
+		railsBlock(root, railsScope{}, source, codeExtraction)
+		return
+	}
+	if !strings.HasSuffix(slashed, "config/routes.rb") {
+		return
+	}
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() == "call" && extractNodeText(n.ChildByFieldName("method"), source) == "draw" {
+			railsBlock(railsBody(n), railsScope{}, source, codeExtraction)
+			return false
+		}
+		return true
+	})
+}
+
+// railsBody returns the statements of a call's do/brace block, or nil.
+func railsBody(call *sitter.Node) *sitter.Node {
+	block := call.ChildByFieldName("block")
+	if block == nil {
+		return nil
+	}
+	if body := block.ChildByFieldName("body"); body != nil {
+		return body
+	}
+	return findChildByType(block, "block_body")
+}
+
+// railsBlock records the routes of the statements in body.
+func railsBlock(body *sitter.Node, scope railsScope, source []byte, codeExtraction *CodeExtraction) {
+	if body == nil {
+		return
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		call := body.NamedChild(uint(i))
+		if call.Kind() != "call" || call.ChildByFieldName("receiver") != nil {
+			continue
+		}
+		railsRoute(call, scope, source, codeExtraction)
+	}
+}
+
+// railsRoute records the routes of one routing call.
+func railsRoute(call *sitter.Node, scope railsScope, source []byte, codeExtraction *CodeExtraction) {
+	method := extractNodeText(call.ChildByFieldName("method"), source)
+	positional, options := railsArgs(call, source)
+
+	switch method {
+	case "namespace":
+		if len(positional) == 0 {
+			return
+		}
+		name := positional[0]
+		nested := railsScope{path: joinRoute(scope.path, name), module: scope.module + camelize(name) + "::"}
+		railsBlock(railsBody(call), nested, source, codeExtraction)
+
+	case "scope":
+		nested := railsScope{path: scope.path, module: scope.module}
+		if len(positional) > 0 {
+			nested.path = joinRoute(scope.path, positional[0])
+		} else if p, ok := options["path"]; ok {
+			nested.path = joinRoute(scope.path, p)
+		}
+		if m, ok := options["module"]; ok {
+			nested.module += camelize(m) + "::"
+		}
+		railsBlock(railsBody(call), nested, source, codeExtraction)
+
+	case "member", "collection":
+		if scope.controller == "" {
+			return
+		}
+		nested := railsScope{path: scope.collectionPath, module: scope.module, controller: scope.controller}
+		if method == "member" {
+			nested.path = scope.memberPath
+		}
+		railsBlock(railsBody(call), nested, source, codeExtraction)
+
+	case "resources", "resource":
+		singular := method == "resource"
+		for _, name := range positional {
+			railsResources(call, name, singular, options, scope, source, codeExtraction)
+		}
+
+	case "root":
+		target := options["to"]
+		if len(positional) > 0 {
+			target = positional[0]
+		}
+		addRailsEndpoint(codeExtraction, "GET", joinRoute(scope.path, "/"), target, scope, call)
+
+	case "get", "post", "put", "patch", "delete", "match":
+		var path, target string
+		if len(positional) > 0 {
+			path = positional[0]
+		}
+		for key, value := range options {
+			if strings.HasPrefix(key, "=>") {
+				path, target = strings.TrimPrefix(key, "=>"), value // "login" => "sessions#create"
+			}
+		}
+		if to, ok := options["to"]; ok {
+			target = to
+		} else if controller, ok := options["controller"]; ok {
+			target = controller + "#" + options["action"]
+		} else if action, ok := options["action"]; ok && scope.controller != "" {
+			target = scope.controller + "#" + action
+		} else if target == "" && scope.controller != "" && path != "" && !strings.Contains(path, "/") {
+			target = scope.controller + "#" + path // member do; post :cancel; end
+		}
+
+		methods := []string{strings.ToUpper(method)}
+		if method == "match" {
+			methods = []string{"ANY"}
+			if via, ok := options["via"]; ok && via != "all" {
+				methods = nil
+				for _, v := range strings.Split(via, ",") {
+					methods = append(methods, strings.ToUpper(v))
+				}
+			}
+		}
+		for _, m := range methods {
+			addRailsEndpoint(codeExtraction, m, joinRoute(scope.path, path), target, scope, call)
+		}
+	}
+}
+
+// railsResources records the routes of one resources (or singular
+// resource) declaration and its block.
+func railsResources(call *sitter.Node, name string, singular bool, options map[string]string, scope railsScope, source []byte, codeExtraction *CodeExtraction) {
+	controller := name
+	if singular {
+		controller = name + "s" // resource :profile routes to ProfilesController
+	}
+	if c, ok := options["controller"]; ok {
+		controller = c
+	}
+	base := joinRoute(scope.path, name)
+	memberPath := base + "/:id"
+	if singular {
+		memberPath = base
+	}
+
+	only, hasOnly := railsActionSet(options["only"])
+	except, _ := railsActionSet(options["except"])
+	resource := railsScope{path: base, module: scope.module, controller: controller}
+	for _, route := range railsActions {
+		if (hasOnly && !only[route.action]) || except[route.action] || (singular && route.action == "index") {
+			continue
+		}
+		path := base
+		if route.member {
+			path = memberPath
+		}
+		addRailsEndpoint(codeExtraction, route.method, path+route.suffix, controller+"#"+route.action, resource, call)
+	}
+
+	// Nested routes hang from a member: /orders/:order_id/items
+	nested := railsScope{path: base, module: scope.module, controller: controller, memberPath: memberPath, collectionPath: base}
+	if !singular {
+		nested.path = base + "/:" + strings.TrimSuffix(name, "s") + "_id"
+	}
+	body := railsBody(call)
+	if body == nil {
+		return
+	}
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		child := body.NamedChild(uint(i))
+		if child.Kind() != "call" {
+			continue
+		}
+		switch extractNodeText(child.ChildByFieldName("method"), source) {
+		case "member", "collection":
+			railsRoute(child, nested, source, codeExtraction)
+		case "resources", "resource":
+			railsRoute(child, railsScope{path: nested.path, module: scope.module}, source, codeExtraction)
+		default:
+			railsRoute(child, railsScope{path: nested.path, module: scope.module, controller: controller}, source, codeExtraction)
+		}
+	}
+}
+
+// railsActionSet parses an only:/except: list ("index,show").
+func railsActionSet(list string) (map[string]bool, bool) {
+	if list == "" {
+		return nil, false
+	}
+	set := make(map[string]bool)
+	for _, action := range strings.Split(list, ",") {
+		set[action] = true
+	}
+	return set, true
+}
+
+// addRailsEndpoint records a Rails route to target ("orders#index").
+func addRailsEndpoint(codeExtraction *CodeExtraction, method, path, target string, scope railsScope, call *sitter.Node) {
+	ref := extraction.EndpointRef{Framework: "rails", Method: method, Path: path, Handler: target}
+	if controller, action, ok := strings.Cut(target, "#"); ok {
+		ref.HandlerName = action
+		ref.HandlerScope = scope.module + camelize(controller) + "Controller"
+	}
+	addEndpoint(codeExtraction, ref, call)
+}
+
+// railsArgs returns a routing call's positional arguments (strings and
+// symbols) and its options. Array options are joined with ","; a
+// "path" => "target" pair is returned under the key "=>path".
+func railsArgs(call *sitter.Node, source []byte) (positional []string, options map[string]string) {
+	options = make(map[string]string)
+	args := call.ChildByFieldName("arguments")
+	if args == nil {
+		return nil, options
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(uint(i))
+		if arg.Kind() != "pair" {
+			if s, ok := literalString(arg, source); ok {
+				positional = append(positional, s)
+			}
+			continue
+		}
+		keyNode, value := arg.ChildByFieldName("key"), arg.ChildByFieldName("value")
+		key, ok := literalString(keyNode, source)
+		if !ok {
+			continue
+		}
+		if keyNode.Kind() == "string" {
+			key = "=>" + key
+		}
+		if value.Kind() == "array" {
+			var items []string
+			for j := 0; j < int(value.NamedChildCount()); j++ {
+				if s, ok := literalString(value.NamedChild(uint(j)), source); ok {
+					items = append(items, s)
+				}
+			}
+			options[key] = strings.Join(items, ",")
+		} else if s, ok := literalString(value, source); ok {
+			options[key] = s
+		}
+	}
+	return positional, options
+}
+
+// camelize converts a Rails controller path to its class name:
+// "order_items" → "OrderItems", "admin/users" → "Admin::Users".
+func camelize(name string) string {
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		words := strings.Split(part, "_")
+		for j, w := range words {
+			if w != "" {
+				words[j] = strings.ToUpper(w[:1]) + w[1:]
+			}
+		}
+		parts[i] = strings.Join(words, "")
+	}
+	return strings.Join(parts, "::")
+}
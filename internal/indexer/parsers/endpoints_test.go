@@ -0,0 +1,234 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for route extraction:
+// - Express routes on app, created routers and route() chains; Fastify route options
+// - Flask and FastAPI decorators with methods and Blueprint/APIRouter prefixes
+// - Django urlpatterns, skipping include()
+// - Spring mappings with the class @RequestMapping prefix
+// - Rails verb routes, root, resources with only/nesting/member routes, namespaces and scopes
+// - Files without a router import (or outside config/routes) have no endpoints
+
+type routeParser interface {
+	ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error)
+}
+
+func parseTestEndpoints(t *testing.T, parser routeParser, relPath, source string) []extraction.EndpointRef {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := parser.ParseFile(context.Background(), path)
+	require.NoError(t, err)
+	return result.Symbols.Endpoints
+}
+
+// routeSummary summarizes endpoints as "METHOD path -> handler (name, scope)".
+func routeSummary(endpoints []extraction.EndpointRef) []string {
+	var out []string
+	for _, ep := range endpoints {
+		out = append(out, fmt.Sprintf("%s %s -> %s (%s, %s)", ep.Method, ep.Path, ep.Handler, ep.HandlerName, ep.HandlerScope))
+	}
+	return out
+}
+
+func TestEndpoints_Express(t *testing.T) {
+	t.Parallel()
+
+	endpoints := parseTestEndpoints(t, NewTypeScriptParser(), "routes.ts", `import express from 'express';
+import * as users from './users';
+
+const orders = express.Router();
+orders.get('/orders/:id', auth, users.show);
+orders.route('/orders').get(list).post(create);
+app.delete(`+"`/sessions`"+`, (req, res) => res.end());
+cache.get('/not-a-route', fallback);
+app.get('settings');
+`)
+
+	assert.Equal(t, []string{
+		"GET /orders/:id -> users.show (show, users)",
+		"POST /orders -> create (create, )",
+		"GET /orders -> list (list, )",
+		"DELETE /sessions ->  (, )",
+	}, routeSummary(endpoints))
+	assert.Equal(t, "express", endpoints[0].Framework)
+	assert.Equal(t, 5, endpoints[0].Line)
+
+	fastify := parseTestEndpoints(t, NewJavaScriptParser(), "server.js", `const fastify = require('fastify')();
+fastify.route({ method: ['GET', 'HEAD'], url: '/health', handler: health.check });
+fastify.post('/items', createItem);
+`)
+	assert.Equal(t, []string{
+		"GET /health -> health.check (check, health)",
+		"HEAD /health -> health.check (check, health)",
+		"POST /items -> createItem (createItem, )",
+	}, routeSummary(fastify))
+	assert.Equal(t, "fastify", fastify[0].Framework)
+}
+
+func TestEndpoints_FlaskAndFastAPI(t *testing.T) {
+	t.Parallel()
+
+	flask := parseTestEndpoints(t, NewPythonParser(), "app.py", `from flask import Flask, Blueprint
+
+bp = Blueprint("orders", __name__, url_prefix="/orders")
+
+@app.route("/health")
+def health():
+    pass
+
+@bp.route("/<int:id>", methods=["GET", "DELETE"])
+@login_required
+def order(id):
+    pass
+`)
+	assert.Equal(t, []string{
+		"GET /health -> health (health, )",
+		"GET /orders/<int:id> -> order (order, )",
+		"DELETE /orders/<int:id> -> order (order, )",
+	}, routeSummary(flask))
+	assert.Equal(t, "flask", flask[0].Framework)
+
+	fastapi := parseTestEndpoints(t, NewPythonParser(), "api.py", `from fastapi import APIRouter
+
+router = APIRouter(prefix="/items")
+
+@router.get("/{item_id}")
+async def get_item(item_id: int):
+    pass
+
+@router.api_route("", methods=["PUT", "PATCH"])
+def upsert():
+    pass
+`)
+	assert.Equal(t, []string{
+		"GET /items/{item_id} -> get_item (get_item, )",
+		"PUT /items -> upsert (upsert, )",
+		"PATCH /items -> upsert (upsert, )",
+	}, routeSummary(fastapi))
+	assert.Equal(t, "fastapi", fastapi[0].Framework)
+}
+
+func TestEndpoints_Django(t *testing.T) {
+	t.Parallel()
+
+	endpoints := parseTestEndpoints(t, NewPythonParser(), "shop/urls.py", `from django.urls import include, path, re_path
+from . import views
+
+urlpatterns = [
+    path("orders/<int:pk>/", views.order_detail, name="order-detail"),
+    re_path(r"^archive/$", views.ArchiveView.as_view()),
+    path("api/", include("api.urls")),
+]
+`)
+
+	assert.Equal(t, []string{
+		"ANY /orders/<int:pk>/ -> views.order_detail (order_detail, views)",
+		"ANY /archive/ -> views.ArchiveView.as_view() (ArchiveView, )",
+	}, routeSummary(endpoints))
+	assert.Equal(t, "django", endpoints[0].Framework)
+}
+
+func TestEndpoints_Spring(t *testing.T) {
+	t.Parallel()
+
+	endpoints := parseTestEndpoints(t, NewJavaParser(), "OrderController.java", `package com.example;
+
+@RestController
+@RequestMapping("/v1/orders")
+public class OrderController {
+    @GetMapping("/{id}")
+    public Order get(@PathVariable long id) { return null; }
+
+    @PostMapping
+    public Order create() { return null; }
+
+    @RequestMapping(value = {"/a", "/b"}, method = {RequestMethod.PUT, RequestMethod.PATCH})
+    public void multi() {}
+
+    @DeleteMapping(path = "{id}")
+    public void delete() {}
+
+    public void helper() {}
+}
+`)
+
+	assert.Equal(t, []string{
+		"GET /v1/orders/{id} -> OrderController.get (get, OrderController)",
+		"POST /v1/orders -> OrderController.create (create, OrderController)",
+		"PUT /v1/orders/a -> OrderController.multi (multi, OrderController)",
+		"PATCH /v1/orders/a -> OrderController.multi (multi, OrderController)",
+		"PUT /v1/orders/b -> OrderController.multi (multi, OrderController)",
+		"PATCH /v1/orders/b -> OrderController.multi (multi, OrderController)",
+		"DELETE /v1/orders/{id} -> OrderController.delete (delete, OrderController)",
+	}, routeSummary(endpoints))
+	assert.Equal(t, "spring", endpoints[0].Framework)
+	assert.Equal(t, 6, endpoints[0].Line)
+}
+
+func TestEndpoints_Rails(t *testing.T) {
+	t.Parallel()
+
+	endpoints := parseTestEndpoints(t, NewRubyParser(), "config/routes.rb", `Rails.application.routes.draw do
+  root "pages#home"
+  get "health", to: "status#show"
+  post "login" => "sessions#create"
+  namespace :api do
+    resources :orders, only: [:index, :show] do
+      member do
+        post :cancel
+      end
+      resources :items, only: :index
+    end
+  end
+  resource :profile, except: [:new, :edit, :destroy]
+  scope "/v2" do
+    match "/ping", controller: :status, action: :ping, via: [:get, :head]
+  end
+end
+`)
+
+	assert.Equal(t, []string{
+		"GET / -> pages#home (home, PagesController)",
+		"GET /health -> status#show (show, StatusController)",
+		"POST /login -> sessions#create (create, SessionsController)",
+		"GET /api/orders -> orders#index (index, Api::OrdersController)",
+		"GET /api/orders/:id -> orders#show (show, Api::OrdersController)",
+		"POST /api/orders/:id/cancel -> orders#cancel (cancel, Api::OrdersController)",
+		"GET /api/orders/:order_id/items -> items#index (index, Api::ItemsController)",
+		"POST /profile -> profiles#create (create, ProfilesController)",
+		"GET /profile -> profiles#show (show, ProfilesController)",
+		"PATCH /profile -> profiles#update (update, ProfilesController)",
+		"PUT /profile -> profiles#update (update, ProfilesController)",
+		"GET /v2/ping -> status#ping (ping, StatusController)",
+		"HEAD /v2/ping -> status#ping (ping, StatusController)",
+	}, routeSummary(endpoints))
+	assert.Equal(t, "rails", endpoints[0].Framework)
+	assert.Equal(t, 2, endpoints[0].Line)
+}
+
+func TestEndpoints_Skipped(t *testing.T) {
+	t.Parallel()
+
+	assert.Empty(t, parseTestEndpoints(t, NewTypeScriptParser(), "cache.ts", `app.get('/orders', handler);
+`))
+	assert.Empty(t, parseTestEndpoints(t, NewPythonParser(), "tasks.py", `@app.route("/x")
+def x():
+    pass
+`))
+	assert.Empty(t, parseTestEndpoints(t, NewRubyParser(), "lib/client.rb", `get "/orders", to: "orders#index"
+`))
+}
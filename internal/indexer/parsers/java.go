@@ -29,7 +29,11 @@ func (p *javaParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses Java source as the content of filePath.
+func (p *javaParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -68,6 +72,15 @@ func (p *javaParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 	// Count imports
 	p.countImports(rootNode, codeExtraction)
 
+	// Record Spring controller routes
+	extractSpringRoutes(rootNode, source, codeExtraction)
+
+	// Record environment variables and configuration keys read
+	extractJavaConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
+
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
 
@@ -140,6 +153,7 @@ func (p *javaParser) extractClass(node *sitter.Node, source []byte, lines []stri
 		Type:      "class",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -152,6 +166,12 @@ func (p *javaParser) extractClass(node *sitter.Node, source []byte, lines []stri
 		EndLine:   endLine,
 	})
 
+	// Record superclass and implemented interfaces
+	if superclass := node.ChildByFieldName("superclass"); superclass != nil {
+		addSupertypes(codeExtraction, name, "extends", superclass, source)
+	}
+	p.addInterfaces(node.ChildByFieldName("interfaces"), source, codeExtraction, name, "implements")
+
 	// Extract methods from class body
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
@@ -159,6 +179,15 @@ func (p *javaParser) extractClass(node *sitter.Node, source []byte, lines []stri
 	}
 }
 
+// addInterfaces records the interfaces listed in a super_interfaces or
+// extends_interfaces clause as supertypes of typeName.
+func (p *javaParser) addInterfaces(clause *sitter.Node, source []byte, codeExtraction *CodeExtraction, typeName, kind string) {
+	if clause == nil {
+		return
+	}
+	addSupertypes(codeExtraction, typeName, kind, findChildByType(clause, "type_list"), source)
+}
+
 // extractInterface extracts an interface declaration.
 func (p *javaParser) extractInterface(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	nameNode := node.ChildByFieldName("name")
@@ -176,6 +205,7 @@ func (p *javaParser) extractInterface(node *sitter.Node, source []byte, lines []
 		Type:      "interface",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -188,6 +218,9 @@ func (p *javaParser) extractInterface(node *sitter.Node, source []byte, lines []
 		EndLine:   endLine,
 	})
 
+	// Record extended interfaces
+	p.addInterfaces(findChildByType(node, "extends_interfaces"), source, codeExtraction, name, "extends")
+
 	// Extract methods from interface body
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
@@ -212,6 +245,7 @@ func (p *javaParser) extractEnum(node *sitter.Node, source []byte, lines []strin
 		Type:      "enum",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -223,6 +257,9 @@ func (p *javaParser) extractEnum(node *sitter.Node, source []byte, lines []strin
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record implemented interfaces
+	p.addInterfaces(node.ChildByFieldName("interfaces"), source, codeExtraction, name, "implements")
 }
 
 // extractMethodsFromClass extracts methods from a class/interface body.
@@ -256,6 +293,7 @@ func (p *javaParser) extractMethod(node *sitter.Node, source []byte, lines []str
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions (signature only)
@@ -363,6 +401,7 @@ func (p *javaParser) extractField(node *sitter.Node, source []byte, lines []stri
 					Type:      typeName,
 					StartLine: startLine,
 					EndLine:   endLine,
+					Doc:       docComment(node, source, isDocBlock),
 				})
 			} else if isStatic {
 				codeExtraction.Data.Variables = append(codeExtraction.Data.Variables, extraction.VariableInfo{
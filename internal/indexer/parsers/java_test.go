@@ -26,6 +26,7 @@ import (
 // - Handles files with parse errors gracefully (returns nil)
 // - Verifies all three tiers: Symbols, Definitions, Data
 // - Verifies line number accuracy across all extracted elements
+// - Records extends/implements clauses as type relations
 
 const testJavaFile = "../../../testdata/code/java/simple.java"
 
@@ -485,3 +486,68 @@ func findSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+// Test: Record extends/implements clauses for classes, interfaces and enums
+func TestJavaParser_TypeRelations(t *testing.T) {
+	t.Parallel()
+
+	tmpDir := t.TempDir()
+	javaPath := filepath.Join(tmpDir, "Shapes.java")
+	content := `package shapes;
+
+interface Shape extends Named, Comparable<Shape> {}
+
+class Circle extends Base<Double> implements Shape, java.io.Serializable {}
+
+enum Color implements Named {}
+`
+	require.NoError(t, writeTestFile(javaPath, content))
+
+	result, err := NewJavaParser().ParseFile(context.Background(), javaPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.TypeRelation{
+		{Type: "Shape", Supertype: "Named", Kind: "extends", Line: 3},
+		{Type: "Shape", Supertype: "Comparable", Kind: "extends", Line: 3},
+		{Type: "Circle", Supertype: "Base", Kind: "extends", Line: 5},
+		{Type: "Circle", Supertype: "Shape", Kind: "implements", Line: 5},
+		{Type: "Circle", Supertype: "Serializable", Kind: "implements", Line: 5},
+		{Type: "Color", Supertype: "Named", Kind: "implements", Line: 7},
+	}, result.Symbols.Relations)
+}
+
+func TestJavaParser_Javadoc(t *testing.T) {
+	t.Parallel()
+
+	// Test: Javadoc documents classes and methods, annotations included
+	javaPath := filepath.Join(t.TempDir(), "Backoff.java")
+	content := `package retry;
+
+/**
+ * Exponential backoff policy.
+ */
+public class Backoff {
+    /**
+     * Returns the delay before attempt.
+     */
+    @Override
+    public long delay(int attempt) {
+        return 1L << attempt;
+    }
+
+    // Not Javadoc
+    public void reset() {}
+}
+`
+	require.NoError(t, os.WriteFile(javaPath, []byte(content), 0644))
+
+	result, err := NewJavaParser().ParseFile(context.Background(), javaPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"Backoff": "Exponential backoff policy.",
+		"delay":   "Returns the delay before attempt.",
+	}, docsByName(result))
+}
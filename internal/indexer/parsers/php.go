@@ -29,7 +29,11 @@ func (p *phpParser) ParseFile(ctx context.Context, filePath string) (*CodeExtrac
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses PHP source as the content of filePath.
+func (p *phpParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -68,6 +72,12 @@ func (p *phpParser) ParseFile(ctx context.Context, filePath string) (*CodeExtrac
 	// Count imports (use statements)
 	p.countImports(rootNode, codeExtraction)
 
+	// Record environment variables and configuration keys read
+	extractPHPConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
+
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
 
@@ -139,6 +149,7 @@ func (p *phpParser) extractClass(node *sitter.Node, source []byte, lines []strin
 		Type:      "class",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -151,13 +162,25 @@ func (p *phpParser) extractClass(node *sitter.Node, source []byte, lines []strin
 		EndLine:   endLine,
 	})
 
+	// Record parent class, interfaces and used traits
+	addSupertypes(codeExtraction, name, "extends", findChildByType(node, "base_clause"), source)
+	addSupertypes(codeExtraction, name, "implements", findChildByType(node, "class_interface_clause"), source)
+
 	// Extract methods from class body
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
+		p.addTraitUses(bodyNode, source, codeExtraction, name)
 		p.extractMethodsFromClass(bodyNode, source, lines, codeExtraction, name)
 	}
 }
 
+// addTraitUses records traits pulled in with "use" inside a class or trait body as mixins.
+func (p *phpParser) addTraitUses(bodyNode *sitter.Node, source []byte, codeExtraction *CodeExtraction, typeName string) {
+	for _, use := range findChildrenByType(bodyNode, "use_declaration") {
+		addSupertypes(codeExtraction, typeName, "mixin", use, source)
+	}
+}
+
 // extractInterface extracts an interface declaration.
 func (p *phpParser) extractInterface(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	nameNode := node.ChildByFieldName("name")
@@ -175,6 +198,7 @@ func (p *phpParser) extractInterface(node *sitter.Node, source []byte, lines []s
 		Type:      "interface",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -186,6 +210,9 @@ func (p *phpParser) extractInterface(node *sitter.Node, source []byte, lines []s
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record extended interfaces
+	addSupertypes(codeExtraction, name, "extends", findChildByType(node, "base_clause"), source)
 }
 
 // extractTrait extracts a trait declaration.
@@ -205,6 +232,7 @@ func (p *phpParser) extractTrait(node *sitter.Node, source []byte, lines []strin
 		Type:      "trait",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -216,6 +244,11 @@ func (p *phpParser) extractTrait(node *sitter.Node, source []byte, lines []strin
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record traits used by this trait
+	if bodyNode := node.ChildByFieldName("body"); bodyNode != nil {
+		p.addTraitUses(bodyNode, source, codeExtraction, name)
+	}
 }
 
 // extractMethodsFromClass extracts methods from a class body.
@@ -249,6 +282,7 @@ func (p *phpParser) extractMethod(node *sitter.Node, source []byte, lines []stri
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions (signature only)
@@ -283,6 +317,7 @@ func (p *phpParser) extractFunction(node *sitter.Node, source []byte, lines []st
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions (signature only)
@@ -384,6 +419,7 @@ func (p *phpParser) extractConst(node *sitter.Node, source []byte, lines []strin
 				Type:      "",
 				StartLine: startLine,
 				EndLine:   endLine,
+				Doc:       docComment(node, source, isDocBlock),
 			})
 		}
 	}
@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
@@ -21,6 +23,7 @@ import (
 // - Verifies line number accuracy for all extractions
 // - Handles invalid/nonexistent files gracefully
 // - Verifies all three tiers: Symbols, Definitions, Data
+// - Records extends, implements and trait uses (mixins) as type relations
 
 func TestPHPParser_ParseClass(t *testing.T) {
 	t.Parallel()
@@ -477,3 +480,34 @@ func TestPHPParser_EmptyFile(t *testing.T) {
 	assert.NotNil(t, result.Data.Constants)
 	assert.NotNil(t, result.Data.Variables)
 }
+
+func TestPHPParser_TypeRelations(t *testing.T) {
+	t.Parallel()
+
+	// Test: extends, implements and trait uses (as mixins) are recorded
+	phpPath := filepath.Join(t.TempDir(), "models.php")
+	content := `<?php
+interface Model extends Arrayable, \JsonSerializable {}
+
+trait HasTimestamps { use Clock; }
+
+class User extends \App\Base implements Model {
+    use HasTimestamps, SoftDeletes;
+}
+`
+	require.NoError(t, os.WriteFile(phpPath, []byte(content), 0644))
+
+	result, err := NewPhpParser().ParseFile(context.Background(), phpPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.TypeRelation{
+		{Type: "Model", Supertype: "Arrayable", Kind: "extends", Line: 2},
+		{Type: "Model", Supertype: "JsonSerializable", Kind: "extends", Line: 2},
+		{Type: "HasTimestamps", Supertype: "Clock", Kind: "mixin", Line: 4},
+		{Type: "User", Supertype: "Base", Kind: "extends", Line: 6},
+		{Type: "User", Supertype: "Model", Kind: "implements", Line: 6},
+		{Type: "User", Supertype: "HasTimestamps", Kind: "mixin", Line: 7},
+		{Type: "User", Supertype: "SoftDeletes", Kind: "mixin", Line: 7},
+	}, result.Symbols.Relations)
+}
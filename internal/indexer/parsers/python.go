@@ -29,7 +29,11 @@ func (p *pythonParser) ParseFile(ctx context.Context, filePath string) (*CodeExt
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses Python source as the content of filePath.
+func (p *pythonParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -62,8 +66,18 @@ func (p *pythonParser) ParseFile(ctx context.Context, filePath string) (*CodeExt
 		},
 	}
 
-	// Count imports
+	// Count imports and record imported modules
 	p.countImports(rootNode, codeExtraction)
+	p.extractImports(rootNode, source, codeExtraction)
+
+	// Record Flask, FastAPI and Django routes
+	extractPythonRoutes(rootNode, source, codeExtraction)
+
+	// Record environment variables read
+	extractPythonConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
 
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
@@ -84,6 +98,51 @@ func (p *pythonParser) countImports(node *sitter.Node, codeExtraction *CodeExtra
 	codeExtraction.Symbols.ImportsCount = count
 }
 
+// extractImports records imported modules: "a.b" for "import a.b" and
+// "from a.b import c", "..models" for relative imports. "from . import x"
+// records ".x", since x is usually a sibling module.
+func (p *pythonParser) extractImports(node *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(node, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "import_statement":
+			for i := 0; i < int(n.NamedChildCount()); i++ {
+				child := n.NamedChild(uint(i))
+				if child.Kind() == "aliased_import" {
+					child = child.ChildByFieldName("name")
+				}
+				if child != nil && child.Kind() == "dotted_name" {
+					addImport(codeExtraction, extractNodeText(child, source), child)
+				}
+			}
+			return false
+		case "import_from_statement":
+			module := n.ChildByFieldName("module_name")
+			if module == nil {
+				return false
+			}
+			path := extractNodeText(module, source)
+			if strings.Trim(path, ".") != "" {
+				addImport(codeExtraction, path, module)
+				return false
+			}
+			for i := 0; i < int(n.NamedChildCount()); i++ {
+				child := n.NamedChild(uint(i))
+				if child == module {
+					continue
+				}
+				if child.Kind() == "aliased_import" {
+					child = child.ChildByFieldName("name")
+				}
+				if child != nil && child.Kind() == "dotted_name" {
+					addImport(codeExtraction, path+extractNodeText(child, source), child)
+				}
+			}
+			return false
+		}
+		return true
+	})
+}
+
 // extractStructure extracts classes, functions, and variables.
 func (p *pythonParser) extractStructure(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	walkTree(node, func(n *sitter.Node) bool {
@@ -139,6 +198,7 @@ func (p *pythonParser) extractClass(node *sitter.Node, source []byte, lines []st
 		Type:      "class",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       pythonDocstring(node, source),
 	})
 
 	// Add to definitions
@@ -189,6 +249,7 @@ func (p *pythonParser) extractMethod(node *sitter.Node, source []byte, lines []s
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       pythonDocstring(node, source),
 	})
 
 	// Add to definitions (signature only)
@@ -223,6 +284,7 @@ func (p *pythonParser) extractFunction(node *sitter.Node, source []byte, lines [
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       pythonDocstring(node, source),
 	})
 
 	// Add to definitions (signature only)
@@ -308,6 +370,7 @@ func (p *pythonParser) extractAssignment(node *sitter.Node, source []byte, lines
 			Type:      "",
 			StartLine: startLine,
 			EndLine:   endLine,
+			Doc:       docComment(node.Parent(), source, anyComment),
 		})
 	} else {
 		codeExtraction.Data.Variables = append(codeExtraction.Data.Variables, extraction.VariableInfo{
@@ -332,3 +395,50 @@ func isConstantName(name string) bool {
 	}
 	return true
 }
+
+// pythonDocstring returns the docstring of a class or function (a string
+// literal as the first statement of its body), dedented like
+// inspect.cleandoc.
+func pythonDocstring(node *sitter.Node, source []byte) string {
+	body := node.ChildByFieldName("body")
+	if body == nil {
+		return ""
+	}
+	var first *sitter.Node
+	for i := 0; i < int(body.NamedChildCount()); i++ {
+		if child := body.NamedChild(uint(i)); child.Kind() != "comment" {
+			first = child
+			break
+		}
+	}
+	if first == nil || first.Kind() != "expression_statement" || first.NamedChildCount() != 1 ||
+		first.NamedChild(0).Kind() != "string" {
+		return ""
+	}
+
+	text := strings.TrimLeft(extractNodeText(first.NamedChild(0), source), "rRuU")
+	for _, quote := range []string{`"""`, `'''`, `"`, `'`} {
+		if len(text) >= 2*len(quote) && strings.HasPrefix(text, quote) && strings.HasSuffix(text, quote) {
+			text = text[len(quote) : len(text)-len(quote)]
+			break
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(text, "\t", "    "), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		if trimmed := strings.TrimLeft(line, " "); trimmed != "" {
+			if n := len(line) - len(trimmed); indent < 0 || n < indent {
+				indent = n
+			}
+		}
+	}
+	lines[0] = strings.TrimSpace(lines[0])
+	for i := 1; i < len(lines); i++ {
+		if indent > 0 && len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
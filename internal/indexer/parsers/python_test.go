@@ -26,6 +26,7 @@ import (
 // - Extract function signatures with parameters
 // - Extract method signatures with class prefix
 // - Distinguish between constants and variables by naming convention
+// - Record imported modules, including relative imports
 
 func TestPythonParser_ParseClass(t *testing.T) {
 	t.Parallel()
@@ -576,3 +577,80 @@ func TestPythonParser_ConstantNamingConvention(t *testing.T) {
 		assert.Equal(t, tt.isConstant, result, "isConstantName(%q) should be %v", tt.name, tt.isConstant)
 	}
 }
+
+func TestPythonParser_Imports(t *testing.T) {
+	t.Parallel()
+
+	// Test: absolute and relative modules are recorded; "from . import x" records ".x"
+	pyPath := filepath.Join(t.TempDir(), "views.py")
+	content := `import os, os.path as osp
+import requests
+from typing import Optional
+from ..models import User
+from . import forms, utils as u
+from .serializers import *
+
+def view():
+    import json
+`
+	require.NoError(t, os.WriteFile(pyPath, []byte(content), 0644))
+
+	result, err := NewPythonParser().ParseFile(context.Background(), pyPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.ImportRef{
+		{Path: "os", Line: 1},
+		{Path: "os.path", Line: 1},
+		{Path: "requests", Line: 2},
+		{Path: "typing", Line: 3},
+		{Path: "..models", Line: 4},
+		{Path: ".forms", Line: 5},
+		{Path: ".utils", Line: 5},
+		{Path: ".serializers", Line: 6},
+		{Path: "json", Line: 9},
+	}, result.Symbols.Imports)
+}
+
+func TestPythonParser_Docstrings(t *testing.T) {
+	t.Parallel()
+
+	// Test: docstrings of classes, methods and functions are dedented; comments above constants document them
+	pyPath := filepath.Join(t.TempDir(), "retry.py")
+	content := `# Maximum attempts before giving up.
+MAX_ATTEMPTS = 5
+
+TIMEOUT = 30
+
+
+class Backoff:
+    """Exponential backoff policy.
+
+    Doubles the delay after each attempt.
+    """
+
+    def delay(self, attempt):
+        '''Return the delay before attempt.'''
+        return 2 ** attempt
+
+
+def retry(fn):
+    # Not a docstring
+    return fn()
+
+
+def undocumented():
+    pass
+`
+	require.NoError(t, os.WriteFile(pyPath, []byte(content), 0644))
+
+	result, err := NewPythonParser().ParseFile(context.Background(), pyPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"MAX_ATTEMPTS": "Maximum attempts before giving up.",
+		"Backoff":      "Exponential backoff policy.\n\nDoubles the delay after each attempt.",
+		"delay":        "Return the delay before attempt.",
+	}, docsByName(result))
+}
@@ -29,7 +29,11 @@ func (p *rubyParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses Ruby source as the content of filePath.
+func (p *rubyParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -65,6 +69,15 @@ func (p *rubyParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 	// Count requires
 	p.countImports(rootNode, codeExtraction)
 
+	// Record the routes of Rails routes files
+	extractRailsRoutes(rootNode, filePath, source, codeExtraction)
+
+	// Record environment variables read
+	extractRubyConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
+
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
 
@@ -29,7 +29,11 @@ func (p *rustParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses Rust source as the content of filePath.
+func (p *rustParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -62,8 +66,15 @@ func (p *rustParser) ParseFile(ctx context.Context, filePath string) (*CodeExtra
 		},
 	}
 
-	// Count imports (use declarations)
+	// Count imports (use declarations) and record imported paths
 	p.countImports(rootNode, codeExtraction)
+	p.extractImports(rootNode, source, codeExtraction)
+
+	// Record environment variables read
+	extractRustConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL
+	extractSQLQueries(rootNode, source, codeExtraction)
 
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
@@ -83,6 +94,70 @@ func (p *rustParser) countImports(node *sitter.Node, codeExtraction *CodeExtract
 	codeExtraction.Symbols.ImportsCount = count
 }
 
+// extractImports records use paths and extern crates. Use lists are expanded
+// one path per item: "use std::{fs, io::Read}" records "std::fs" and
+// "std::io::Read"; globs record their prefix.
+func (p *rustParser) extractImports(node *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(node, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "use_declaration":
+			if arg := n.ChildByFieldName("argument"); arg != nil {
+				for _, path := range rustUsePaths(arg, "", source) {
+					addImport(codeExtraction, path, arg)
+				}
+			}
+			return false
+		case "extern_crate_declaration":
+			if name := n.ChildByFieldName("name"); name != nil {
+				addImport(codeExtraction, extractNodeText(name, source), name)
+			}
+			return false
+		}
+		return true
+	})
+}
+
+// rustUsePaths expands a use tree into full paths under prefix.
+func rustUsePaths(node *sitter.Node, prefix string, source []byte) []string {
+	join := func(path string) string {
+		if prefix == "" {
+			return path
+		}
+		if path == "self" {
+			return prefix
+		}
+		return prefix + "::" + path
+	}
+
+	switch node.Kind() {
+	case "use_as_clause":
+		if path := node.ChildByFieldName("path"); path != nil {
+			return []string{join(extractNodeText(path, source))}
+		}
+	case "use_wildcard":
+		if path := node.NamedChild(0); path != nil {
+			return []string{join(extractNodeText(path, source))}
+		}
+		return []string{prefix}
+	case "scoped_use_list":
+		if path := node.ChildByFieldName("path"); path != nil {
+			prefix = join(extractNodeText(path, source))
+		}
+		if list := node.ChildByFieldName("list"); list != nil {
+			return rustUsePaths(list, prefix, source)
+		}
+	case "use_list":
+		var paths []string
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			paths = append(paths, rustUsePaths(node.NamedChild(uint(i)), prefix, source)...)
+		}
+		return paths
+	case "identifier", "scoped_identifier", "crate", "self", "super", "metavariable":
+		return []string{join(extractNodeText(node, source))}
+	}
+	return nil
+}
+
 // extractStructure extracts structs, enums, traits, functions, and constants.
 func (p *rustParser) extractStructure(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	walkTree(node, func(n *sitter.Node) bool {
@@ -124,6 +199,7 @@ func (p *rustParser) extractStruct(node *sitter.Node, source []byte, lines []str
 		Type:      "struct",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 
 	// Add to definitions
@@ -154,6 +230,7 @@ func (p *rustParser) extractEnum(node *sitter.Node, source []byte, lines []strin
 		Type:      "enum",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 
 	// Add to definitions
@@ -184,6 +261,7 @@ func (p *rustParser) extractTrait(node *sitter.Node, source []byte, lines []stri
 		Type:      "trait",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 
 	// Add to definitions
@@ -195,6 +273,9 @@ func (p *rustParser) extractTrait(node *sitter.Node, source []byte, lines []stri
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record supertraits ("trait A: B + C")
+	addSupertypes(codeExtraction, name, "extends", node.ChildByFieldName("bounds"), source)
 }
 
 // extractImpl extracts methods from an impl block.
@@ -206,6 +287,11 @@ func (p *rustParser) extractImpl(node *sitter.Node, source []byte, lines []strin
 
 	typeName := extractNodeText(typeNode, source)
 
+	// "impl Trait for Type" records Type as implementing Trait
+	if traitNode := node.ChildByFieldName("trait"); traitNode != nil {
+		addSupertype(codeExtraction, baseTypeName(typeName), "implements", traitNode, source)
+	}
+
 	// Extract methods from impl body
 	bodyNode := node.ChildByFieldName("body")
 	if bodyNode != nil {
@@ -239,6 +325,7 @@ func (p *rustParser) extractMethod(node *sitter.Node, source []byte, lines []str
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 
 	// Add to definitions (signature only)
@@ -273,6 +360,7 @@ func (p *rustParser) extractFunction(node *sitter.Node, source []byte, lines []s
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 
 	// Add to definitions (signature only)
@@ -366,6 +454,7 @@ func (p *rustParser) extractConst(node *sitter.Node, source []byte, lines []stri
 		Type:      typeName,
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isRustDoc),
 	})
 }
 
@@ -400,3 +489,9 @@ func (p *rustParser) extractStatic(node *sitter.Node, source []byte, lines []str
 		EndLine:   endLine,
 	})
 }
+
+// isRustDoc reports whether a comment is an outer doc comment ("///" or
+// "/** ... */").
+func isRustDoc(text string) bool {
+	return (strings.HasPrefix(text, "///") && !strings.HasPrefix(text, "////")) || isDocBlock(text)
+}
@@ -2,6 +2,8 @@ package parsers
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -24,6 +26,8 @@ import (
 // - Verifies line numbers are accurate across all extraction types
 // - Handles invalid/unparseable files gracefully
 // - Handles files with pub visibility modifiers
+// - Records supertraits and trait impls as type relations
+// - Records use paths (expanding use lists) and extern crates as imports
 
 // Test: Extract struct definitions from Rust file
 func TestRustParser_ParseStruct(t *testing.T) {
@@ -506,3 +510,101 @@ func TestRustParser_Metadata(t *testing.T) {
 	assert.Equal(t, 1, result.StartLine)
 	assert.Greater(t, result.EndLine, 1)
 }
+
+func TestRustParser_TypeRelations(t *testing.T) {
+	t.Parallel()
+
+	// Test: supertraits and "impl Trait for Type" are recorded; inherent impls are not
+	rsPath := filepath.Join(t.TempDir(), "shapes.rs")
+	content := `trait Shape: Named + std::fmt::Debug + 'static {}
+
+struct Circle;
+
+impl Shape for Circle {}
+
+impl<T> Iterator<T> for Points<T> {}
+
+impl Circle {}
+`
+	require.NoError(t, os.WriteFile(rsPath, []byte(content), 0644))
+
+	result, err := NewRustParser().ParseFile(context.Background(), rsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.TypeRelation{
+		{Type: "Shape", Supertype: "Named", Kind: "extends", Line: 1},
+		{Type: "Shape", Supertype: "Debug", Kind: "extends", Line: 1},
+		{Type: "Circle", Supertype: "Shape", Kind: "implements", Line: 5},
+		{Type: "Points", Supertype: "Iterator", Kind: "implements", Line: 7},
+	}, result.Symbols.Relations)
+}
+
+func TestRustParser_Imports(t *testing.T) {
+	t.Parallel()
+
+	// Test: use lists expand to one path per item; aliases and globs keep their path
+	rsPath := filepath.Join(t.TempDir(), "lib.rs")
+	content := `extern crate serde;
+use std::collections::HashMap;
+use std::{fs, io::{self, Read}};
+use crate::db::Pool as DbPool;
+use super::models::*;
+use tokio;
+`
+	require.NoError(t, os.WriteFile(rsPath, []byte(content), 0644))
+
+	result, err := NewRustParser().ParseFile(context.Background(), rsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.ImportRef{
+		{Path: "serde", Line: 1},
+		{Path: "std::collections::HashMap", Line: 2},
+		{Path: "std::fs", Line: 3},
+		{Path: "std::io", Line: 3},
+		{Path: "std::io::Read", Line: 3},
+		{Path: "crate::db::Pool", Line: 4},
+		{Path: "super::models", Line: 5},
+		{Path: "tokio", Line: 6},
+	}, result.Symbols.Imports)
+}
+
+func TestRustParser_DocComments(t *testing.T) {
+	t.Parallel()
+
+	// Test: "///" lines are joined, attributes between doc and item are skipped, plain comments ignored
+	rsPath := filepath.Join(t.TempDir(), "retry.rs")
+	content := `/// Exponential backoff policy.
+///
+/// Doubles the delay after each attempt.
+#[derive(Debug)]
+pub struct Backoff {
+    base: u64,
+}
+
+impl Backoff {
+    /// Returns the delay before attempt.
+    pub fn delay(&self, attempt: u32) -> u64 {
+        self.base << attempt
+    }
+}
+
+// Not a doc comment
+pub fn retry() {}
+
+/** Maximum attempts. */
+const MAX_ATTEMPTS: u32 = 5;
+`
+	require.NoError(t, os.WriteFile(rsPath, []byte(content), 0644))
+
+	result, err := NewRustParser().ParseFile(context.Background(), rsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"Backoff":      "Exponential backoff policy.\n\nDoubles the delay after each attempt.",
+		"delay":        "Returns the delay before attempt.",
+		"MAX_ATTEMPTS": "Maximum attempts.",
+	}, docsByName(result))
+}
@@ -0,0 +1,257 @@
+package parsers
+
+import (
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/graph"
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+)
+
+// stringKinds are the nodes of string literals, across languages.
+var stringKinds = map[string]bool{
+	"string":              true, // TypeScript/JavaScript, Python, Ruby, PHP
+	"template_string":     true,
+	"string_literal":      true, // Java, Rust, C/C++
+	"raw_string_literal":  true,
+	"encapsed_string":     true, // PHP
+	"heredoc_body":        true, // Ruby, PHP
+	"concatenated_string": true, // Python, C/C++: "SELECT a " "FROM b"
+}
+
+// addSQLQuery records a query at node, attributed to its enclosing function.
+func addSQLQuery(codeExtraction *CodeExtraction, query, querySource string, node *sitter.Node, source []byte) {
+	codeExtraction.Symbols.SQLQueries = append(codeExtraction.Symbols.SQLQueries, extraction.SQLQuery{
+		Query:    query,
+		Source:   querySource,
+		Function: enclosingFunction(node, source),
+		Line:     int(node.StartPosition().Row) + 1,
+	})
+}
+
+// extractSQLQueries records the string literals that start like a SQL
+// statement; interpolations (f-strings, template literals, "#{}") become
+// "?" placeholders.
+func extractSQLQueries(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(root, func(n *sitter.Node) bool {
+		if !stringKinds[n.Kind()] {
+			return true
+		}
+		if query := sqlText(n, source); graph.IsSQL(query) {
+			addSQLQuery(codeExtraction, query, "sql", n, source)
+		}
+		return false
+	})
+}
+
+// sqlText returns the text of a string literal with its interpolations
+// replaced by "?".
+func sqlText(node *sitter.Node, source []byte) string {
+	var b strings.Builder
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(uint(i))
+		switch child.Kind() {
+		case "string_fragment", "string_content", "multiline_string_fragment", "heredoc_content",
+			"raw_string_content", "escape_sequence":
+			b.WriteString(extractNodeText(child, source))
+		case "string_start", "string_end", "heredoc_start", "heredoc_end", "raw_string_delimiter":
+		default:
+			if node.Kind() == "concatenated_string" {
+				b.WriteString(sqlText(child, source))
+			} else {
+				b.WriteString("?") // Interpolation
+			}
+		}
+	}
+	return b.String()
+}
+
+// knexVerbs are the statements knex builder methods run.
+var knexVerbs = map[string]string{
+	"select": "SELECT", "first": "SELECT", "pluck": "SELECT", "count": "SELECT",
+	"insert": "INSERT", "upsert": "INSERT",
+	"update": "UPDATE", "increment": "UPDATE", "decrement": "UPDATE",
+	"del": "DELETE", "delete": "DELETE", "truncate": "TRUNCATE",
+}
+
+// knexJoins are the knex builder methods joining a table.
+var knexJoins = map[string]bool{
+	"join": true, "innerJoin": true, "leftJoin": true, "leftOuterJoin": true,
+	"rightJoin": true, "rightOuterJoin": true, "fullOuterJoin": true, "crossJoin": true,
+}
+
+// extractKnexQueries records the statements knex builder chains build:
+// knex('users').where(...).update({...}), db('users').select('id'),
+// knex.select('id').from('users'). Chains are rooted at knex, or at any
+// function called with a table name in files importing knex.
+func extractKnexQueries(root *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	anyRoot := importsModule(codeExtraction, "knex")
+	seen := make(map[uintptr]bool)
+	walkTree(root, func(n *sitter.Node) bool {
+		if n.Kind() != "call_expression" || seen[n.Id()] {
+			return true
+		}
+		// Outermost call first: mark the calls of the chain it ends
+		var calls []*sitter.Node
+		for c := n; c != nil && c.Kind() == "call_expression"; {
+			seen[c.Id()] = true
+			calls = append([]*sitter.Node{c}, calls...)
+			fn := c.ChildByFieldName("function")
+			if fn == nil || fn.Kind() != "member_expression" {
+				break
+			}
+			c = fn.ChildByFieldName("object")
+		}
+		if query := knexSQL(calls, source, anyRoot); query != "" {
+			addSQLQuery(codeExtraction, query, "knex", n, source)
+		}
+		return true
+	})
+}
+
+// knexSQL returns the statement a knex chain builds, or "" if the chain
+// isn't a knex query naming its table. Roots other than knex must be
+// followed by builder methods: db('users').where(...), not t('greeting').
+func knexSQL(calls []*sitter.Node, source []byte, anyRoot bool) string {
+	if len(calls) == 0 {
+		return ""
+	}
+	var verb, table string
+	var columns, joins []string
+
+	// knex('users'), this.knex('users'), db('users') or knex.select(...)
+	rootFn := calls[0].ChildByFieldName("function")
+	if rootFn == nil {
+		return ""
+	}
+	isKnex := false
+	switch {
+	case rootFn.Kind() == "identifier" || extractNodeText(rootFn.ChildByFieldName("property"), source) == "knex":
+		name := extractNodeText(rootFn, source)
+		isKnex = name == "knex" || strings.HasSuffix(name, ".knex")
+		if !isKnex && (!anyRoot || rootFn.Kind() != "identifier") {
+			return ""
+		}
+		table, _ = literalString(firstArgument(calls[0].ChildByFieldName("arguments")), source)
+		calls = calls[1:]
+	case rootFn.Kind() == "member_expression":
+		object := extractNodeText(rootFn.ChildByFieldName("object"), source)
+		if object != "knex" && object != "this.knex" {
+			return ""
+		}
+		isKnex = true
+	default:
+		return ""
+	}
+
+	for _, c := range calls {
+		fn := c.ChildByFieldName("function")
+		if fn == nil || fn.Kind() != "member_expression" {
+			continue
+		}
+		method := extractNodeText(fn.ChildByFieldName("property"), source)
+		args := c.ChildByFieldName("arguments")
+		first := firstArgument(args)
+		if strings.HasPrefix(method, "where") {
+			isKnex = true
+		}
+		switch {
+		case method == "from" || method == "table" || method == "into":
+			isKnex = true
+			if s, ok := literalString(first, source); ok {
+				table = s
+			}
+		case knexJoins[method]:
+			isKnex = true
+			if s, ok := literalString(first, source); ok {
+				joins = append(joins, s)
+			}
+		case knexVerbs[method] != "":
+			isKnex = true
+			verb = knexVerbs[method]
+			switch verb {
+			case "SELECT":
+				columns = append(columns, knexColumns(args, source)...)
+			case "INSERT", "UPDATE":
+				if first != nil && first.Kind() == "array" && first.NamedChildCount() > 0 {
+					first = first.NamedChild(0) // insert([{...}, {...}])
+				}
+				if first != nil && first.Kind() == "object" {
+					columns = append(columns, objectKeys(first, source)...)
+				} else if s, ok := literalString(first, source); ok && verb == "UPDATE" {
+					columns = append(columns, s) // update('col', v), increment('col')
+				}
+			}
+		}
+	}
+	if table == "" || !isKnex {
+		return ""
+	}
+
+	switch verb {
+	case "", "SELECT":
+		if len(columns) == 0 {
+			columns = []string{"*"}
+		}
+		query := "SELECT " + strings.Join(columns, ", ") + " FROM " + table
+		for _, join := range joins {
+			query += " JOIN " + join
+		}
+		return query
+	case "INSERT":
+		if len(columns) == 0 {
+			return "INSERT INTO " + table
+		}
+		return "INSERT INTO " + table + " (" + strings.Join(columns, ", ") + ")"
+	case "UPDATE":
+		sets := make([]string, len(columns))
+		for i, c := range columns {
+			sets[i] = c + " = ?"
+		}
+		return "UPDATE " + table + " SET " + strings.Join(sets, ", ")
+	case "DELETE":
+		return "DELETE FROM " + table
+	case "TRUNCATE":
+		return "TRUNCATE " + table
+	}
+	return ""
+}
+
+// knexColumns returns the string columns of a select: select('id', 'name')
+// or select(['id', 'name']).
+func knexColumns(args *sitter.Node, source []byte) []string {
+	var columns []string
+	if args == nil {
+		return nil
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		arg := args.NamedChild(uint(i))
+		if arg.Kind() == "array" {
+			columns = append(columns, knexColumns(arg, source)...)
+		} else if s, ok := literalString(arg, source); ok {
+			columns = append(columns, s)
+		}
+	}
+	return columns
+}
+
+// objectKeys returns the keys of an object literal: the columns knex
+// inserts or updates.
+func objectKeys(object *sitter.Node, source []byte) []string {
+	var keys []string
+	for i := 0; i < int(object.NamedChildCount()); i++ {
+		child := object.NamedChild(uint(i))
+		switch child.Kind() {
+		case "pair":
+			key := child.ChildByFieldName("key")
+			if s, ok := literalString(key, source); ok {
+				keys = append(keys, s)
+			} else if key != nil && key.Kind() == "property_identifier" {
+				keys = append(keys, extractNodeText(key, source))
+			}
+		case "shorthand_property_identifier":
+			keys = append(keys, extractNodeText(child, source))
+		}
+	}
+	return keys
+}
@@ -0,0 +1,138 @@
+package parsers
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test Plan for embedded SQL extraction:
+// - String literals starting like a statement are recorded in every language, with
+//   interpolations as "?", concatenated literals joined, raw strings, text blocks and heredocs
+// - Prose and other strings are not SQL
+// - knex chains build their statement: knex('t'), knex.select().from('t'), and any root
+//   followed by builder methods when knex is imported; other calls are ignored
+// - Queries are attributed to the enclosing function, qualified by class
+
+func parseTestSQLQueries(t *testing.T, parser routeParser, relPath, source string) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), relPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(source), 0644))
+
+	result, err := parser.ParseFile(context.Background(), path)
+	require.NoError(t, err)
+
+	var out []string
+	for _, q := range result.Symbols.SQLQueries {
+		out = append(out, fmt.Sprintf("%s %q in %q @%d", q.Source, q.Query, q.Function, q.Line))
+	}
+	return out
+}
+
+func TestSQLQueries_TypeScript(t *testing.T) {
+	t.Parallel()
+
+	queries := parseTestSQLQueries(t, NewTypeScriptParser(), "repo.ts", `import knex from 'knex';
+
+const db = knex({ client: 'pg' });
+
+export class UserRepo {
+  find(id: number) {
+    return this.pool.query(`+"`SELECT id, name FROM ${schema}.users WHERE id = $1`"+`, [id]);
+  }
+  rename(id: number, name: string) {
+    return db('users').where({ id }).update({ name, updated_at: new Date() });
+  }
+  purge() {
+    return knex.select('id').from('sessions').leftJoin('users', 'users.id', 'sessions.user_id').del();
+  }
+}
+
+export const log = (msg: string) => console.log('Select a file from the list', t('greeting'));
+`)
+
+	assert.Equal(t, []string{
+		`sql "SELECT id, name FROM ?.users WHERE id = $1" in "UserRepo.find" @7`,
+		`knex "UPDATE users SET name = ?, updated_at = ?" in "UserRepo.rename" @10`,
+		`knex "DELETE FROM sessions" in "UserRepo.purge" @13`,
+	}, queries)
+}
+
+func TestSQLQueries_Literals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		parser routeParser
+		path   string
+		source string
+		want   []string
+	}{
+		{
+			name:   "python",
+			parser: NewPythonParser(),
+			path:   "repo.py",
+			source: "class Repo:\n    def load(self):\n        cur.execute(f\"SELECT * FROM {table}\")\n        cur.execute(\"DELETE FROM a_b \"\n                    \"WHERE id = %s\", (1,))\n",
+			want: []string{
+				`sql "SELECT * FROM ?" in "Repo.load" @3`,
+				`sql "DELETE FROM a_b WHERE id = %s" in "Repo.load" @4`,
+			},
+		},
+		{
+			name:   "java",
+			parser: NewJavaParser(),
+			path:   "Repo.java",
+			source: "class Repo {\n    void save() {\n        jdbc.update(\"\"\"\n            INSERT INTO orders (id) VALUES (?)\n            \"\"\");\n    }\n}\n",
+			want: []string{
+				`sql "\n            INSERT INTO orders (id) VALUES (?)\n            " in "Repo.save" @3`,
+			},
+		},
+		{
+			name:   "ruby",
+			parser: NewRubyParser(),
+			path:   "repo.rb",
+			source: "class Repo\n  def stale\n    execute <<~SQL\n      SELECT id FROM jobs WHERE state = '#{state}'\n    SQL\n  end\nend\n",
+			want: []string{
+				`sql "\n      SELECT id FROM jobs WHERE state = '?'\n    " in "Repo.stale" @3`,
+			},
+		},
+		{
+			name:   "rust",
+			parser: NewRustParser(),
+			path:   "src/repo.rs",
+			source: "fn load() {\n    sqlx::query(r#\"SELECT \"id\" FROM users\"#);\n}\n",
+			want: []string{
+				`sql "SELECT \"id\" FROM users" in "load" @2`,
+			},
+		},
+		{
+			name:   "php",
+			parser: NewPhpParser(),
+			path:   "Repo.php",
+			source: "<?php\nfunction load($t) {\n    return $db->query(\"SELECT * FROM $t WHERE id = 1\");\n}\n",
+			want: []string{
+				`sql "SELECT * FROM ? WHERE id = 1" in "load" @3`,
+			},
+		},
+		{
+			name:   "c",
+			parser: NewCParser(),
+			path:   "db.c",
+			source: "int main(void) {\n    sqlite3_exec(db, \"UPDATE counters \" \"SET n = n + 1\", 0, 0, 0);\n}\n",
+			want: []string{
+				`sql "UPDATE counters SET n = n + 1" in "main" @2`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseTestSQLQueries(t, tt.parser, tt.path, tt.source))
+		})
+	}
+}
@@ -165,3 +165,167 @@ func findChildrenByType(node *sitter.Node, nodeType string) []*sitter.Node {
 	}
 	return results
 }
+
+// addImport records an imported module path once per file.
+func addImport(codeExtraction *CodeExtraction, path string, node *sitter.Node) {
+	if path == "" {
+		return
+	}
+	for _, imp := range codeExtraction.Symbols.Imports {
+		if imp.Path == path {
+			return
+		}
+	}
+	codeExtraction.Symbols.Imports = append(codeExtraction.Symbols.Imports, extraction.ImportRef{
+		Path: path,
+		Line: int(node.StartPosition().Row) + 1,
+	})
+}
+
+// addSupertypes records each type reference among clause's named children
+// as a supertype of typeName. Nodes of other kinds (e.g. type arguments,
+// lifetimes) are skipped.
+func addSupertypes(codeExtraction *CodeExtraction, typeName, kind string, clause *sitter.Node, source []byte) {
+	if clause == nil {
+		return
+	}
+	for i := 0; i < int(clause.NamedChildCount()); i++ {
+		child := clause.NamedChild(uint(i))
+		if !isTypeReference(child.Kind()) {
+			continue
+		}
+		addSupertype(codeExtraction, typeName, kind, child, source)
+	}
+}
+
+// addSupertype records ref as a supertype of typeName.
+func addSupertype(codeExtraction *CodeExtraction, typeName, kind string, ref *sitter.Node, source []byte) {
+	supertype := baseTypeName(extractNodeText(ref, source))
+	if supertype == "" || supertype == typeName {
+		return
+	}
+	codeExtraction.Symbols.Relations = append(codeExtraction.Symbols.Relations, extraction.TypeRelation{
+		Type:      typeName,
+		Supertype: supertype,
+		Kind:      kind,
+		Line:      int(ref.StartPosition().Row) + 1,
+	})
+}
+
+// isTypeReference reports whether a node kind names a type in a heritage clause.
+func isTypeReference(kind string) bool {
+	switch kind {
+	case "identifier", "type_identifier", "generic_type", "nested_type_identifier",
+		"scoped_type_identifier", "name", "qualified_name", "call_expression",
+		"template_type", "qualified_identifier":
+		return true
+	}
+	return false
+}
+
+// baseTypeName strips generic arguments and package/namespace qualifiers
+// from a type reference: "ns.Base<T>" → "Base", "\App\Model" → "Model",
+// "std::fmt::Debug" → "Debug".
+func baseTypeName(ref string) string {
+	if i := strings.IndexAny(ref, "<("); i >= 0 {
+		ref = ref[:i]
+	}
+	ref = strings.TrimSpace(ref)
+	if i := strings.LastIndexAny(ref, ".:\\"); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return ref
+}
+
+// docWrappers are node kinds wrapping a declaration, so that its doc comment
+// precedes the wrapper ("/** ... */ export function f() {}", C's
+// "typedef struct {...} T;").
+var docWrappers = map[string]bool{"export_statement": true, "declaration": true, "type_definition": true}
+
+// docComment returns the doc comment directly above a declaration, with
+// comment markers stripped, or "" if there is none. isDoc selects the
+// comments that document code (e.g. "/**" but not "/*"). Consecutive line
+// comments are joined; a blank line or code ends the comment. Attributes
+// between the comment and the declaration (Rust's #[derive]) are skipped.
+func docComment(node *sitter.Node, source []byte, isDoc func(text string) bool) string {
+	for parent := node.Parent(); parent != nil && docWrappers[parent.Kind()]; parent = node.Parent() {
+		node = parent
+	}
+
+	var comments []string
+	line := node.StartPosition().Row
+	for prev := node.PrevSibling(); prev != nil; prev = prev.PrevSibling() {
+		end := prev.EndPosition()
+		if end.Column == 0 && end.Row > 0 {
+			end.Row-- // Line comments may include their newline
+		}
+		if end.Row+1 < line {
+			break
+		}
+		if prev.Kind() == "attribute_item" {
+			line = prev.StartPosition().Row
+			continue
+		}
+		text := extractNodeText(prev, source)
+		if !strings.Contains(prev.Kind(), "comment") || !startsLine(prev, source) || !isDoc(text) {
+			break
+		}
+		comments = append(comments, stripCommentMarkers(text))
+		line = prev.StartPosition().Row
+		if strings.HasPrefix(text, "/*") {
+			break // A block comment is the whole doc comment
+		}
+	}
+
+	for i, j := 0, len(comments)-1; i < j; i, j = i+1, j-1 {
+		comments[i], comments[j] = comments[j], comments[i]
+	}
+	return strings.TrimSpace(strings.Join(comments, "\n"))
+}
+
+// startsLine reports whether only whitespace precedes node on its line,
+// which tells a comment above a declaration from one trailing code.
+func startsLine(node *sitter.Node, source []byte) bool {
+	for i := int(node.StartByte()) - 1; i >= 0 && source[i] != '\n'; i-- {
+		if source[i] != ' ' && source[i] != '\t' {
+			return false
+		}
+	}
+	return true
+}
+
+// stripCommentMarkers removes the markers of a line comment ("//", "///",
+// "#") or block comment ("/** ... */" and the leading "*" of its lines).
+func stripCommentMarkers(text string) string {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "/*") {
+		text = strings.TrimSuffix(strings.TrimPrefix(text, "/*"), "*/")
+		text = strings.TrimLeft(text, "*!")
+		lines := strings.Split(text, "\n")
+		for i, l := range lines {
+			l = strings.TrimPrefix(strings.TrimSpace(l), "*")
+			lines[i] = strings.TrimPrefix(l, " ")
+		}
+		return strings.TrimSpace(strings.Join(lines, "\n"))
+	}
+	for _, marker := range []string{"///", "//!", "//", "#"} {
+		if strings.HasPrefix(text, marker) {
+			text = text[len(marker):]
+			break
+		}
+	}
+	return strings.TrimPrefix(text, " ")
+}
+
+// isDocBlock reports whether a comment is a "/** ... */" doc block (JSDoc,
+// Javadoc, PHPDoc).
+func isDocBlock(text string) bool {
+	return strings.HasPrefix(text, "/**") && text != "/**/"
+}
+
+// anyComment counts every comment as documentation, for languages without a
+// doc comment syntax of their own (C, where Doxygen's "/**" and "///" are
+// common but plain comments are too).
+func anyComment(text string) bool {
+	return true
+}
@@ -0,0 +1,41 @@
+package parsers
+
+// Test Plan for shared tree-sitter helpers:
+// - Comment markers of line and block comments are stripped, keeping indentation inside blocks
+
+import (
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
+	"github.com/stretchr/testify/assert"
+)
+
+// docsByName maps the names of the documented types, functions and
+// constants of an extraction to their doc.
+func docsByName(result *CodeExtraction) map[string]string {
+	docs := make(map[string]string)
+	for _, symbols := range [][]extraction.SymbolInfo{result.Symbols.Types, result.Symbols.Functions} {
+		for _, s := range symbols {
+			if s.Doc != "" {
+				docs[s.Name] = s.Doc
+			}
+		}
+	}
+	for _, c := range result.Data.Constants {
+		if c.Doc != "" {
+			docs[c.Name] = c.Doc
+		}
+	}
+	return docs
+}
+
+func TestStripCommentMarkers(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "Retries with backoff.", stripCommentMarkers("// Retries with backoff."))
+	assert.Equal(t, "Retries with backoff.", stripCommentMarkers("/// Retries with backoff.\n"))
+	assert.Equal(t, "Retries with backoff.", stripCommentMarkers("# Retries with backoff."))
+	assert.Equal(t, "Retries.\n\nExample:\n    retry(f)", stripCommentMarkers("/**\n * Retries.\n *\n * Example:\n *     retry(f)\n */"))
+	assert.Equal(t, "Retries.", stripCommentMarkers("/** Retries. */"))
+	assert.Equal(t, "", stripCommentMarkers("/**/"))
+}
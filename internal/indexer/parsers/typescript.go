@@ -29,7 +29,11 @@ func (p *typeScriptParser) ParseFile(ctx context.Context, filePath string) (*Cod
 	if err != nil {
 		return nil, err
 	}
+	return p.ParseSource(ctx, filePath, source)
+}
 
+// ParseSource parses TypeScript source as the content of filePath.
+func (p *typeScriptParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
 	parser := sitter.NewParser()
 	defer parser.Close()
 
@@ -62,8 +66,19 @@ func (p *typeScriptParser) ParseFile(ctx context.Context, filePath string) (*Cod
 		},
 	}
 
-	// Count imports
+	// Count imports and record imported modules
 	p.countImports(rootNode, codeExtraction)
+	p.extractImports(rootNode, source, codeExtraction)
+
+	// Record routes registered with Express and Fastify
+	extractJSRoutes(rootNode, source, codeExtraction)
+
+	// Record environment variables and configuration keys read
+	extractJSConfigReads(rootNode, source, codeExtraction)
+
+	// Record embedded SQL and knex query builder chains
+	extractSQLQueries(rootNode, source, codeExtraction)
+	extractKnexQueries(rootNode, source, codeExtraction)
 
 	// Extract symbols, definitions, and data
 	p.extractStructure(rootNode, source, lines, codeExtraction)
@@ -87,7 +102,7 @@ func (p *typeScriptParser) countImports(node *sitter.Node, codeExtraction *CodeE
 func (p *typeScriptParser) extractStructure(node *sitter.Node, source []byte, lines []string, codeExtraction *CodeExtraction) {
 	walkTree(node, func(n *sitter.Node) bool {
 		switch n.Kind() {
-		case "class_declaration":
+		case "class_declaration", "abstract_class_declaration":
 			p.extractClass(n, source, lines, codeExtraction)
 		case "interface_declaration":
 			p.extractInterface(n, source, lines, codeExtraction)
@@ -121,6 +136,7 @@ func (p *typeScriptParser) extractClass(node *sitter.Node, source []byte, lines
 		Type:      "class",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -132,6 +148,16 @@ func (p *typeScriptParser) extractClass(node *sitter.Node, source []byte, lines
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record extends/implements clauses
+	if heritage := findChildByType(node, "class_heritage"); heritage != nil {
+		if extends := findChildByType(heritage, "extends_clause"); extends != nil {
+			if value := extends.ChildByFieldName("value"); value != nil {
+				addSupertype(codeExtraction, name, "extends", value, source)
+			}
+		}
+		addSupertypes(codeExtraction, name, "implements", findChildByType(heritage, "implements_clause"), source)
+	}
 }
 
 // extractInterface extracts an interface declaration.
@@ -151,6 +177,7 @@ func (p *typeScriptParser) extractInterface(node *sitter.Node, source []byte, li
 		Type:      "interface",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -162,6 +189,9 @@ func (p *typeScriptParser) extractInterface(node *sitter.Node, source []byte, li
 		StartLine: startLine,
 		EndLine:   endLine,
 	})
+
+	// Record extended interfaces
+	addSupertypes(codeExtraction, name, "extends", findChildByType(node, "extends_type_clause"), source)
 }
 
 // extractTypeAlias extracts a type alias declaration.
@@ -181,6 +211,7 @@ func (p *typeScriptParser) extractTypeAlias(node *sitter.Node, source []byte, li
 		Type:      "type",
 		StartLine: startLine,
 		EndLine:   endLine,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions
@@ -215,6 +246,7 @@ func (p *typeScriptParser) extractFunction(node *sitter.Node, source []byte, lin
 		StartLine: startLine,
 		EndLine:   endLine,
 		Signature: signature,
+		Doc:       docComment(node, source, isDocBlock),
 	})
 
 	// Add to definitions (signature only)
@@ -310,6 +342,7 @@ func (p *typeScriptParser) extractLexicalDeclaration(node *sitter.Node, source [
 				Type:      typeName,
 				StartLine: startLine,
 				EndLine:   endLine,
+				Doc:       docComment(node, source, isDocBlock),
 			})
 		} else {
 			codeExtraction.Data.Variables = append(codeExtraction.Data.Variables, extraction.VariableInfo{
@@ -358,6 +391,40 @@ func (p *typeScriptParser) extractVariableDeclaration(node *sitter.Node, source
 	}
 }
 
+// extractImports records the modules named by import and re-export
+// statements, require() calls and dynamic import() expressions.
+func (p *typeScriptParser) extractImports(node *sitter.Node, source []byte, codeExtraction *CodeExtraction) {
+	walkTree(node, func(n *sitter.Node) bool {
+		switch n.Kind() {
+		case "import_statement", "export_statement":
+			if src := n.ChildByFieldName("source"); src != nil {
+				addImport(codeExtraction, moduleSpecifier(src, source), src)
+			} else if req := findChildByType(n, "import_require_clause"); req != nil {
+				if src := findChildByType(req, "string"); src != nil {
+					addImport(codeExtraction, moduleSpecifier(src, source), src)
+				}
+			}
+		case "call_expression":
+			fn := n.ChildByFieldName("function")
+			args := n.ChildByFieldName("arguments")
+			if fn == nil || args == nil || args.NamedChildCount() == 0 {
+				break
+			}
+			if fn.Kind() == "import" || (fn.Kind() == "identifier" && extractNodeText(fn, source) == "require") {
+				if src := args.NamedChild(0); src.Kind() == "string" {
+					addImport(codeExtraction, moduleSpecifier(src, source), src)
+				}
+			}
+		}
+		return true
+	})
+}
+
+// moduleSpecifier returns the unquoted text of a string literal.
+func moduleSpecifier(node *sitter.Node, source []byte) string {
+	return strings.Trim(extractNodeText(node, source), "'\"`")
+}
+
 // JavaScriptParser parses JavaScript files.
 type javaScriptParser struct {
 	*treeSitterParser
@@ -383,3 +450,15 @@ func (p *javaScriptParser) ParseFile(ctx context.Context, filePath string) (*Cod
 	}
 	return extraction, err
 }
+
+// ParseSource parses JavaScript source as the content of filePath.
+func (p *javaScriptParser) ParseSource(ctx context.Context, filePath string, source []byte) (*CodeExtraction, error) {
+	tsParser := &typeScriptParser{
+		treeSitterParser: p.treeSitterParser,
+	}
+	extraction, err := tsParser.ParseSource(ctx, filePath, source)
+	if extraction != nil {
+		extraction.Language = "javascript"
+	}
+	return extraction, err
+}
@@ -26,6 +26,8 @@ import (
 // - Parse JavaScript constants
 // - Handle empty files
 // - Ensure Language field is set correctly
+// - Record extends/implements clauses (including abstract classes) as type relations
+// - Record imported module specifiers from imports, re-exports, require() and import()
 
 func TestTypeScriptParser_ParseClass(t *testing.T) {
 	t.Parallel()
@@ -527,3 +529,110 @@ func TestJavaScriptParser_InvalidFile(t *testing.T) {
 	assert.NotNil(t, result, "tree-sitter returns partial parse for invalid syntax")
 	assert.Equal(t, "javascript", result.Language)
 }
+
+func TestTypeScriptParser_TypeRelations(t *testing.T) {
+	t.Parallel()
+
+	// Test: extends/implements clauses are recorded with qualifiers and generics stripped
+	tmpDir := t.TempDir()
+	tsPath := filepath.Join(tmpDir, "shapes.ts")
+	content := `interface Shape extends Named, Sized<number> {}
+
+export abstract class Base extends ns.Entity<Shape> implements Shape, io.Serializable {}
+
+class Circle extends Base {}
+`
+	require.NoError(t, os.WriteFile(tsPath, []byte(content), 0644))
+
+	result, err := NewTypeScriptParser().ParseFile(context.Background(), tsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.TypeRelation{
+		{Type: "Shape", Supertype: "Named", Kind: "extends", Line: 1},
+		{Type: "Shape", Supertype: "Sized", Kind: "extends", Line: 1},
+		{Type: "Base", Supertype: "Entity", Kind: "extends", Line: 3},
+		{Type: "Base", Supertype: "Shape", Kind: "implements", Line: 3},
+		{Type: "Base", Supertype: "Serializable", Kind: "implements", Line: 3},
+		{Type: "Circle", Supertype: "Base", Kind: "extends", Line: 5},
+	}, result.Symbols.Relations)
+
+	// Abstract classes are extracted as classes
+	var names []string
+	for _, typ := range result.Symbols.Types {
+		names = append(names, typ.Name)
+	}
+	assert.Contains(t, names, "Base")
+}
+
+func TestTypeScriptParser_Imports(t *testing.T) {
+	t.Parallel()
+
+	// Test: every module specifier is recorded once, with the line of its first use
+	tsPath := filepath.Join(t.TempDir(), "app.ts")
+	content := `import React from "react";
+import { join } from 'node:path';
+import type { User } from "./models/user";
+import "./styles.css";
+export { helper } from "../shared/helper";
+import fs = require("fs");
+const lodash = require("lodash");
+const lazy = () => import("@app/lazy");
+import { other } from "react";
+`
+	require.NoError(t, os.WriteFile(tsPath, []byte(content), 0644))
+
+	result, err := NewTypeScriptParser().ParseFile(context.Background(), tsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, []extraction.ImportRef{
+		{Path: "react", Line: 1},
+		{Path: "node:path", Line: 2},
+		{Path: "./models/user", Line: 3},
+		{Path: "./styles.css", Line: 4},
+		{Path: "../shared/helper", Line: 5},
+		{Path: "fs", Line: 6},
+		{Path: "lodash", Line: 7},
+		{Path: "@app/lazy", Line: 8},
+	}, result.Symbols.Imports)
+}
+
+func TestTypeScriptParser_DocComments(t *testing.T) {
+	t.Parallel()
+
+	// Test: JSDoc blocks document declarations, including exported ones; plain comments don't
+	tsPath := filepath.Join(t.TempDir(), "retry.ts")
+	content := `/**
+ * Retries fn with exponential backoff.
+ * @param fn the operation
+ */
+export function retry(fn: () => void): void {}
+
+/** Backoff settings. */
+interface Options {
+  attempts: number;
+}
+
+/* Not JSDoc */
+class Scheduler {}
+
+/** Default number of attempts. */
+export const DEFAULT_ATTEMPTS = 5;
+
+/** Separated by a blank line. */
+
+type Delay = number;
+`
+	require.NoError(t, os.WriteFile(tsPath, []byte(content), 0644))
+
+	result, err := NewTypeScriptParser().ParseFile(context.Background(), tsPath)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+
+	assert.Equal(t, map[string]string{
+		"retry":            "Retries fn with exponential backoff.\n@param fn the operation",
+		"Options":          "Backoff settings.",
+		"DEFAULT_ATTEMPTS": "Default number of attempts.",
+	}, docsByName(result))
+}
@@ -0,0 +1,404 @@
+package indexer
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Pipeline defaults
+const (
+	DefaultEmbedWorkers   = 2
+	DefaultEmbedBatchSize = 50
+	DefaultWriteBatchSize = 1000
+
+	// pipelineProgressInterval is how often stage throughput is reported.
+	pipelineProgressInterval = time.Second
+)
+
+// Pipeline stage names, as reported in StageStats.Name.
+const (
+	StageParse = "parse"
+	StageEmbed = "embed"
+	StageWrite = "write"
+)
+
+// PipelineConfig controls concurrency of the ProcessFiles pipeline.
+//
+// Files flow through three stages connected by bounded channels:
+//
+//	parse (ParseWorkers) → embed (EmbedWorkers, EmbedBatchSize chunks/request) → write (1 writer, WriteBatchSize chunks/transaction)
+//
+// Each stage holds at most a few batches in flight, so memory stays bounded
+// regardless of repository size. Zero values select defaults.
+type PipelineConfig struct {
+	ParseWorkers   int // Parallel tree-sitter/markdown workers (default: number of CPUs)
+	EmbedWorkers   int // Concurrent embedding requests (default: 2)
+	EmbedBatchSize int // Chunks per embedding request (default: 50)
+	WriteBatchSize int // Chunks per SQLite write transaction (default: 1000)
+}
+
+// withDefaults returns a copy with zero (or negative) values replaced by defaults.
+func (c PipelineConfig) withDefaults() PipelineConfig {
+	if c.ParseWorkers <= 0 {
+		c.ParseWorkers = runtime.NumCPU()
+	}
+	if c.EmbedWorkers <= 0 {
+		c.EmbedWorkers = DefaultEmbedWorkers
+	}
+	if c.EmbedBatchSize <= 0 {
+		c.EmbedBatchSize = DefaultEmbedBatchSize
+	}
+	if c.WriteBatchSize <= 0 {
+		c.WriteBatchSize = DefaultWriteBatchSize
+	}
+	return c
+}
+
+// StageStats reports throughput of one pipeline stage.
+type StageStats struct {
+	Name    string        // StageParse, StageEmbed or StageWrite
+	Unit    string        // "files" (parse) or "chunks" (embed, write)
+	Workers int           // Goroutines serving the stage
+	Items   int           // Items completed so far
+	Cached  int           // Embed stage only: chunks served from the embedding cache
+	Busy    time.Duration // Time spent processing, summed across workers
+	Elapsed time.Duration // Wall time since the pipeline started
+}
+
+// Throughput returns completed items per second of wall time.
+func (s StageStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Items) / s.Elapsed.Seconds()
+}
+
+// Utilization returns the fraction of worker time spent busy (0-1).
+// The stage closest to 1 is the pipeline's bottleneck.
+func (s StageStats) Utilization() float64 {
+	if s.Elapsed <= 0 || s.Workers <= 0 {
+		return 0
+	}
+	u := s.Busy.Seconds() / (s.Elapsed.Seconds() * float64(s.Workers))
+	if u > 1 {
+		u = 1
+	}
+	return u
+}
+
+// String formats the stats for logs, e.g. "parse: 1200 files (340.5 files/s, 8 workers, 62% busy)".
+func (s StageStats) String() string {
+	cached := ""
+	if s.Cached > 0 {
+		cached = fmt.Sprintf(", %d cached", s.Cached)
+	}
+	return fmt.Sprintf("%s: %d %s (%.1f %s/s, %d workers, %.0f%% busy%s)",
+		s.Name, s.Items, s.Unit, s.Throughput(), s.Unit, s.Workers, s.Utilization()*100, cached)
+}
+
+// stageCounter accumulates StageStats from concurrent workers.
+type stageCounter struct {
+	name    string
+	unit    string
+	workers int
+	items   atomic.Int64
+	cached  atomic.Int64
+	busy    atomic.Int64 // nanoseconds
+}
+
+func newStageCounter(name, unit string, workers int) *stageCounter {
+	return &stageCounter{name: name, unit: unit, workers: workers}
+}
+
+func (c *stageCounter) record(items int, busy time.Duration) {
+	c.items.Add(int64(items))
+	c.busy.Add(int64(busy))
+}
+
+func (c *stageCounter) snapshot(elapsed time.Duration) StageStats {
+	return StageStats{
+		Name:    c.name,
+		Unit:    c.unit,
+		Workers: c.workers,
+		Items:   int(c.items.Load()),
+		Cached:  int(c.cached.Load()),
+		Busy:    time.Duration(c.busy.Load()),
+		Elapsed: elapsed,
+	}
+}
+
+// fileWork carries one file's chunks through the pipeline.
+// A file's chunks always travel (and are written) together, because
+// WriteChunksIncremental replaces all chunks of every file it sees.
+type fileWork struct {
+	file   string
+	isDoc  bool
+	chunks []Chunk
+}
+
+// workBatch groups whole files into one embedding request / write.
+type workBatch struct {
+	files  []*fileWork
+	chunks int
+}
+
+// pipelineResult summarizes a pipeline run.
+type pipelineResult struct {
+	codeChunks int
+	docChunks  int
+	stages     []StageStats
+}
+
+// runPipeline parses, embeds and writes files through bounded concurrent stages.
+// The first error (or ctx cancellation) stops all stages; chunks already
+// committed by the writer stay committed.
+func (p *processor) runPipeline(ctx context.Context, codeFiles, docFiles []string) (*pipelineResult, error) {
+	result := &pipelineResult{}
+	total := len(codeFiles) + len(docFiles)
+	if total == 0 {
+		return result, nil
+	}
+
+	cfg := p.pipeline.withDefaults()
+	start := time.Now()
+
+	parseStage := newStageCounter(StageParse, "files", cfg.ParseWorkers)
+	embedStage := newStageCounter(StageEmbed, "chunks", cfg.EmbedWorkers)
+	writeStage := newStageCounter(StageWrite, "chunks", 1)
+	snapshot := func() []StageStats {
+		elapsed := time.Since(start)
+		return []StageStats{
+			parseStage.snapshot(elapsed),
+			embedStage.snapshot(elapsed),
+			writeStage.snapshot(elapsed),
+		}
+	}
+
+	p.progress.OnFileProcessingStart(total)
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	// Bounded channels keep at most a few batches in flight per stage
+	inputs := make(chan *fileWork)
+	parsed := make(chan *fileWork, cfg.ParseWorkers)
+	toEmbed := make(chan *workBatch, cfg.EmbedWorkers)
+	toWrite := make(chan *workBatch, cfg.EmbedWorkers)
+
+	// Feed files
+	g.Go(func() error {
+		defer close(inputs)
+		send := func(work *fileWork) error {
+			select {
+			case inputs <- work:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		for _, file := range codeFiles {
+			if err := send(&fileWork{file: file}); err != nil {
+				return err
+			}
+		}
+		for _, file := range docFiles {
+			if err := send(&fileWork{file: file, isDoc: true}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	// Stage 1: parse/chunk files
+	var parseWG sync.WaitGroup
+	for i := 0; i < cfg.ParseWorkers; i++ {
+		parseWG.Add(1)
+		g.Go(func() error {
+			defer parseWG.Done()
+			for work := range inputs {
+				t := time.Now()
+				var err error
+				if work.isDoc {
+					work.chunks, err = p.processDocFile(ctx, work.file)
+				} else {
+					work.chunks, err = p.processCodeFile(ctx, work.file)
+				}
+				if err == nil {
+					p.redactChunks(work.chunks) // Before chunk text reaches the embedding provider
+				}
+				parseStage.record(1, time.Since(t))
+				if err != nil {
+					return err
+				}
+
+				select {
+				case parsed <- work:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		parseWG.Wait()
+		close(parsed)
+		return nil
+	})
+
+	// Group whole files into embedding batches
+	g.Go(func() error {
+		defer close(toEmbed)
+		batch := &workBatch{}
+		for work := range parsed {
+			batch.files = append(batch.files, work)
+			batch.chunks += len(work.chunks)
+			if batch.chunks < cfg.EmbedBatchSize {
+				continue
+			}
+			select {
+			case toEmbed <- batch:
+				batch = &workBatch{}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if len(batch.files) > 0 {
+			select {
+			case toEmbed <- batch:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return nil
+	})
+
+	// Stage 2: embed batches
+	var embedWG sync.WaitGroup
+	for i := 0; i < cfg.EmbedWorkers; i++ {
+		embedWG.Add(1)
+		g.Go(func() error {
+			defer embedWG.Done()
+			for batch := range toEmbed {
+				t := time.Now()
+				if err := p.embedBatch(ctx, batch, embedStage); err != nil {
+					return fmt.Errorf("failed to embed chunks: %w", err)
+				}
+				embedStage.record(batch.chunks, time.Since(t))
+
+				select {
+				case toWrite <- batch:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+	}
+	g.Go(func() error {
+		embedWG.Wait()
+		close(toWrite)
+		return nil
+	})
+
+	// Stage 3: single writer with large transactions.
+	// All progress callbacks run on this goroutine, so reporters need no locking.
+	g.Go(func() error {
+		ticker := time.NewTicker(pipelineProgressInterval)
+		defer ticker.Stop()
+
+		var pending []*fileWork
+		pendingChunks := 0
+
+		flush := func() error {
+			if len(pending) == 0 {
+				return nil
+			}
+			chunks := make([]Chunk, 0, pendingChunks)
+			for _, work := range pending {
+				chunks = append(chunks, work.chunks...)
+			}
+
+			t := time.Now()
+			if err := p.storage.WriteChunksIncremental(chunks); err != nil {
+				return fmt.Errorf("failed to write chunks: %w", err)
+			}
+			writeStage.record(len(chunks), time.Since(t))
+
+			for _, work := range pending {
+				if work.isDoc {
+					result.docChunks += len(work.chunks)
+				} else {
+					result.codeChunks += len(work.chunks)
+				}
+				p.progress.OnFileProcessed(work.file)
+			}
+			pending = pending[:0]
+			pendingChunks = 0
+			return nil
+		}
+
+		for {
+			select {
+			case batch, ok := <-toWrite:
+				if !ok {
+					return flush()
+				}
+				pending = append(pending, batch.files...)
+				pendingChunks += batch.chunks
+				if pendingChunks >= cfg.WriteBatchSize {
+					if err := flush(); err != nil {
+						return err
+					}
+				}
+			case <-ticker.C:
+				p.progress.OnPipelineProgress(snapshot())
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	result.stages = snapshot()
+	p.progress.OnPipelineProgress(result.stages)
+
+	return result, nil
+}
+
+// embedBatch embeds every chunk in batch with a single embedChunks call.
+func (p *processor) embedBatch(ctx context.Context, batch *workBatch, stage *stageCounter) error {
+	if batch.chunks == 0 {
+		return nil
+	}
+
+	flat := make([]Chunk, 0, batch.chunks)
+	for _, work := range batch.files {
+		flat = append(flat, work.chunks...)
+	}
+
+	cached, err := p.embedChunks(ctx, flat)
+	if err != nil {
+		return err
+	}
+	stage.cached.Add(int64(cached))
+
+	// Copy embeddings back to each file's chunks
+	i := 0
+	for _, work := range batch.files {
+		for j := range work.chunks {
+			work.chunks[j].Embedding = flat[i].Embedding
+			i++
+		}
+	}
+
+	return nil
+}
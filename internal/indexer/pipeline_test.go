@@ -0,0 +1,208 @@
+package indexer
+
+// Test Plan for the ProcessFiles pipeline:
+// - PipelineConfig zero values resolve to defaults
+// - Many files with small batch sizes: every chunk is embedded and written once
+// - A file's chunks are never split across write transactions
+// - Writes are batched (fewer transactions than files)
+// - Progress: OnFileProcessed for every file, final OnPipelineProgress with per-stage counts
+// - Cancellation during embedding stops the pipeline and returns ctx error
+// - StageStats throughput/utilization math and formatting
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/embed"
+	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineConfig_WithDefaults(t *testing.T) {
+	t.Parallel()
+
+	cfg := PipelineConfig{}.withDefaults()
+	assert.Greater(t, cfg.ParseWorkers, 0)
+	assert.Equal(t, DefaultEmbedWorkers, cfg.EmbedWorkers)
+	assert.Equal(t, DefaultEmbedBatchSize, cfg.EmbedBatchSize)
+	assert.Equal(t, DefaultWriteBatchSize, cfg.WriteBatchSize)
+
+	custom := PipelineConfig{ParseWorkers: 3, EmbedWorkers: 1, EmbedBatchSize: 10, WriteBatchSize: 20}.withDefaults()
+	assert.Equal(t, PipelineConfig{ParseWorkers: 3, EmbedWorkers: 1, EmbedBatchSize: 10, WriteBatchSize: 20}, custom)
+}
+
+func TestProcessor_Pipeline_ManyFiles(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	db := storagepkg.NewTestDB(t)
+	base, err := setupProcessorTestStorage(t, db, tempDir)
+	require.NoError(t, err)
+	stor := &recordingStorage{Storage: base}
+
+	var files []string
+	for i := 0; i < 20; i++ {
+		goFile := filepath.Join(tempDir, fmt.Sprintf("file%d.go", i))
+		content := fmt.Sprintf("package main\n\nconst C%d = %d\n\ntype T%d struct{}\n\nfunc F%d() {}\n", i, i, i, i)
+		require.NoError(t, os.WriteFile(goFile, []byte(content), 0644))
+		files = append(files, goFile)
+	}
+	for i := 0; i < 5; i++ {
+		mdFile := filepath.Join(tempDir, fmt.Sprintf("doc%d.md", i))
+		require.NoError(t, os.WriteFile(mdFile, []byte(fmt.Sprintf("# Doc %d\n\nSome content.\n\n## Section\n\nMore about doc %d.\n", i, i)), 0644))
+		files = append(files, mdFile)
+	}
+
+	progress := &recordingProgress{}
+	provider := &countingEmbedProvider{}
+	processor := NewProcessor(tempDir, NewParser(), NewChunker(512, 50), NewFormatter(), provider, stor, progress,
+		WithPipelineConfig(PipelineConfig{ParseWorkers: 4, EmbedWorkers: 3, EmbedBatchSize: 4, WriteBatchSize: 10}))
+
+	stats, err := processor.ProcessFiles(context.Background(), files)
+	require.NoError(t, err)
+
+	assert.Equal(t, 20, stats.CodeFilesProcessed)
+	assert.Equal(t, 5, stats.DocsProcessed)
+	assert.Equal(t, 60, stats.TotalCodeChunks, "symbols, definitions and data per Go file")
+	assert.Greater(t, stats.TotalDocChunks, 0)
+	total := stats.TotalCodeChunks + stats.TotalDocChunks
+
+	// Every chunk embedded exactly once and written with an embedding
+	assert.Equal(t, total, provider.embeddedCount())
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM chunks").Scan(&count))
+	assert.Equal(t, total, count)
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM chunks WHERE embedding IS NULL OR length(embedding) = 0").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	// Writes are batched, and each file lands in exactly one write
+	assert.Less(t, len(stor.writes), len(files))
+	seen := make(map[string]int)
+	for _, write := range stor.writes {
+		for file := range write {
+			seen[file]++
+		}
+	}
+	for file, n := range seen {
+		assert.Equal(t, 1, n, "file %s split across writes", file)
+	}
+
+	// Progress
+	assert.Len(t, progress.processed, len(files))
+	require.NotEmpty(t, progress.stages)
+	final := progress.stages[len(progress.stages)-1]
+	require.Len(t, final, 3)
+	assert.Equal(t, StageParse, final[0].Name)
+	assert.Equal(t, len(files), final[0].Items)
+	assert.Equal(t, StageEmbed, final[1].Name)
+	assert.Equal(t, total, final[1].Items)
+	assert.Equal(t, StageWrite, final[2].Name)
+	assert.Equal(t, total, final[2].Items)
+	assert.Equal(t, final, stats.Stages)
+}
+
+func TestProcessor_Pipeline_CancelDuringEmbedding(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	db := storagepkg.NewTestDB(t)
+	stor, err := setupProcessorTestStorage(t, db, tempDir)
+	require.NoError(t, err)
+
+	var files []string
+	for i := 0; i < 10; i++ {
+		goFile := filepath.Join(tempDir, fmt.Sprintf("file%d.go", i))
+		require.NoError(t, os.WriteFile(goFile, []byte(fmt.Sprintf("package main\n\nfunc F%d() {}\n", i)), 0644))
+		files = append(files, goFile)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider := &blockingEmbedProvider{started: make(chan struct{})}
+	processor := NewProcessor(tempDir, NewParser(), NewChunker(512, 50), NewFormatter(), provider, stor, nil,
+		WithPipelineConfig(PipelineConfig{ParseWorkers: 2, EmbedWorkers: 1, EmbedBatchSize: 1, WriteBatchSize: 1}))
+
+	go func() {
+		<-provider.started
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := processor.ProcessFiles(ctx, files)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(10 * time.Second):
+		t.Fatal("pipeline did not stop after cancellation")
+	}
+}
+
+func TestStageStats(t *testing.T) {
+	t.Parallel()
+
+	s := StageStats{Name: StageEmbed, Unit: "chunks", Workers: 2, Items: 100, Cached: 40, Busy: 3 * time.Second, Elapsed: 2 * time.Second}
+	assert.InDelta(t, 50.0, s.Throughput(), 1e-9)
+	assert.InDelta(t, 0.75, s.Utilization(), 1e-9)
+	assert.Equal(t, "embed: 100 chunks (50.0 chunks/s, 2 workers, 75% busy, 40 cached)", s.String())
+
+	assert.Equal(t, 0.0, StageStats{}.Throughput())
+	assert.Equal(t, 0.0, StageStats{}.Utilization())
+}
+
+// recordingStorage records the set of files in each WriteChunksIncremental call.
+type recordingStorage struct {
+	Storage
+	mu     sync.Mutex
+	writes []map[string]bool
+}
+
+func (r *recordingStorage) WriteChunksIncremental(chunks []Chunk) error {
+	files := make(map[string]bool)
+	for _, c := range chunks {
+		if path, ok := c.Metadata["file_path"].(string); ok {
+			files[path] = true
+		}
+	}
+	r.mu.Lock()
+	r.writes = append(r.writes, files)
+	r.mu.Unlock()
+	return r.Storage.WriteChunksIncremental(chunks)
+}
+
+// recordingProgress records file completions and pipeline stage reports.
+type recordingProgress struct {
+	NoOpProgressReporter
+	processed []string
+	stages    [][]StageStats
+}
+
+func (r *recordingProgress) OnFileProcessed(fileName string) {
+	r.processed = append(r.processed, fileName)
+}
+
+func (r *recordingProgress) OnPipelineProgress(stages []StageStats) {
+	r.stages = append(r.stages, stages)
+}
+
+// blockingEmbedProvider blocks in Embed until ctx is cancelled.
+type blockingEmbedProvider struct {
+	mockEmbedProvider
+	once    sync.Once
+	started chan struct{}
+}
+
+func (b *blockingEmbedProvider) Embed(ctx context.Context, texts []string, mode embed.EmbedMode) ([][]float32, error) {
+	b.once.Do(func() { close(b.started) })
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
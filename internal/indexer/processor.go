@@ -2,19 +2,24 @@ package indexer
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/embedcache"
+	"github.com/mvp-joe/project-cortex/internal/indexer/extraction"
 	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // Processor handles the parse → chunk → embed → write pipeline.
 type Processor interface {
 	// ProcessFiles parses, chunks, embeds, and writes files to database.
+	// Parsing, embedding and writing run as concurrent stages (see PipelineConfig).
 	// Returns statistics about what was processed.
 	ProcessFiles(ctx context.Context, files []string) (*Stats, error)
 }
@@ -26,6 +31,7 @@ type Stats struct {
 	TotalCodeChunks    int
 	TotalDocChunks     int
 	ProcessingTime     time.Duration
+	Stages             []StageStats // Per-stage throughput of the processing pipeline
 }
 
 // processor implements Processor interface.
@@ -37,6 +43,53 @@ type processor struct {
 	provider  embed.Provider
 	storage   Storage
 	progress  ProgressReporter
+	roots     contentSourceRoots // Stored paths of project and content source files
+
+	// Concurrency settings for the parse → embed → write pipeline
+	pipeline PipelineConfig
+
+	// Optional content-addressed embedding cache (nil = always embed)
+	embedCache *embedcache.Cache
+	embedModel string
+
+	// Optional secret scanner redacting stored content and chunk text (nil = no scanning)
+	secrets *SecretScanner
+}
+
+// ProcessorOption configures a Processor.
+type ProcessorOption func(*processor)
+
+// WithPipelineConfig sets worker counts and batch sizes for ProcessFiles.
+func WithPipelineConfig(cfg PipelineConfig) ProcessorOption {
+	return func(p *processor) {
+		p.pipeline = cfg
+	}
+}
+
+// WithEmbeddingCache consults cache before calling the embedding provider.
+// model identifies the embedding model so entries from different models never mix.
+func WithEmbeddingCache(cache *embedcache.Cache, model string) ProcessorOption {
+	return func(p *processor) {
+		p.embedCache = cache
+		p.embedModel = model
+	}
+}
+
+// WithContentSourcePaths stores files of content sources under their
+// "@<label>/" paths instead of paths relative to the root directory.
+func WithContentSourcePaths(sources *ContentSources) ProcessorOption {
+	return func(p *processor) {
+		p.roots.sources = sources
+	}
+}
+
+// WithSecretScanner redacts the secrets scanner finds from file content and
+// chunk text before they are stored or embedded, and records where they
+// were found (see storage.QuerySecretFindings).
+func WithSecretScanner(scanner *SecretScanner) ProcessorOption {
+	return func(p *processor) {
+		p.secrets = scanner
+	}
 }
 
 // NewProcessor creates a new Processor instance.
@@ -48,12 +101,13 @@ func NewProcessor(
 	provider embed.Provider,
 	storage Storage,
 	progress ProgressReporter,
+	opts ...ProcessorOption,
 ) Processor {
 	if progress == nil {
 		progress = &NoOpProgressReporter{}
 	}
 
-	return &processor{
+	p := &processor{
 		rootDir:   rootDir,
 		parser:    parser,
 		chunker:   chunker,
@@ -61,7 +115,12 @@ func NewProcessor(
 		provider:  provider,
 		storage:   storage,
 		progress:  progress,
+		roots:     contentSourceRoots{rootDir: rootDir},
 	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
 }
 
 // ProcessFiles processes a list of files through the complete pipeline.
@@ -83,7 +142,7 @@ func (p *processor) ProcessFiles(ctx context.Context, files []string) (*Stats, e
 
 	log.Printf("Collecting file metadata for %d files...\n", len(files))
 	for _, file := range files {
-		fileStats, err := collectFileMetadata(p.rootDir, file)
+		fileStats, err := collectFileMetadata(p.rootDir, file, p.relPath(file), p.parser.DetectLanguage(file))
 		if err != nil {
 			log.Printf("Warning: failed to collect metadata for %s: %v\n", file, err)
 			continue
@@ -99,10 +158,25 @@ func (p *processor) ProcessFiles(ctx context.Context, files []string) (*Stats, e
 
 	skippedBinary := 0
 	skippedError := 0
+	secretsFound := 0
+	secretFiles := 0
+
+	// Files with findings from earlier runs, whose findings may be stale
+	var hadFindings map[string]bool
+	if p.secrets != nil {
+		err := storage.WithTx(p.storage.GetDB(), func(tx *sql.Tx) error {
+			var err error
+			hadFindings, err = storage.SecretFindingFiles(tx)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret findings: %w", err)
+		}
+	}
 
 	log.Printf("Writing file metadata and content for %d files...\n", len(files))
 	for _, file := range files {
-		relPath, _ := filepath.Rel(p.rootDir, file)
+		relPath := p.relPath(file)
 		fileStats, exists := fileStatsMap[relPath]
 		if !exists {
 			log.Printf("Warning: no metadata for %s, skipping\n", relPath)
@@ -134,14 +208,38 @@ func (p *processor) ProcessFiles(ctx context.Context, files []string) (*Stats, e
 			skippedBinary++
 		}
 
+		// Redact secrets before the content reaches the database
+		var findings []storage.SecretFinding
+		if content != nil && p.secrets != nil {
+			redacted, found := p.secrets.Redact(*content)
+			content, findings = &redacted, found
+		}
+
 		// Single atomic write of stats + content
 		if err := writer.WriteFile(fileStats, content); err != nil {
 			return nil, fmt.Errorf("failed to write file %s: %w", relPath, err)
 		}
+
+		// Findings go after the file row: rewriting it cascades to them
+		if len(findings) > 0 || hadFindings[relPath] {
+			err := storage.WithTx(p.storage.GetDB(), func(tx *sql.Tx) error {
+				return storage.ReplaceSecretFindings(tx, relPath, findings)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to write secret findings for %s: %w", relPath, err)
+			}
+			if len(findings) > 0 {
+				secretsFound += len(findings)
+				secretFiles++
+			}
+		}
 	}
 
 	log.Printf("✓ Wrote file stats + content for %d files (%d binary skipped, %d errors)\n",
 		len(files)-skippedBinary-skippedError, skippedBinary, skippedError)
+	if secretsFound > 0 {
+		log.Printf("⚠ Redacted %d secrets in %d files (run 'cortex secrets' to list them)\n", secretsFound, secretFiles)
+	}
 	log.Printf("[TIMING] Write files (stats + content): %v\n", time.Since(phaseStart))
 
 	// Check for cancellation
@@ -149,50 +247,44 @@ func (p *processor) ProcessFiles(ctx context.Context, files []string) (*Stats, e
 		return nil, err
 	}
 
-	// Phase 3: Process code files
+	// Phase 3: Parse, embed and write chunks through the staged pipeline
 	phaseStart = time.Now()
-	symbolsChunks, defsChunks, dataChunks, err := p.processCodeFiles(ctx, codeFiles)
+	pipelineStats, err := p.runPipeline(ctx, codeFiles, docFiles)
 	if err != nil {
-		return nil, fmt.Errorf("failed to process code files: %w", err)
-	}
-	stats.CodeFilesProcessed = len(codeFiles)
-	stats.TotalCodeChunks = len(symbolsChunks) + len(defsChunks) + len(dataChunks)
-	log.Printf("[TIMING] Process code files: %v (%d files -> %d chunks)\n",
-		time.Since(phaseStart), len(codeFiles), stats.TotalCodeChunks)
-
-	// Check for cancellation
-	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
-
-	// Phase 4: Process documentation files
-	phaseStart = time.Now()
-	docChunks, err := p.processDocFiles(ctx, docFiles)
-	if err != nil {
-		return nil, fmt.Errorf("failed to process documentation files: %w", err)
-	}
+	stats.CodeFilesProcessed = len(codeFiles)
 	stats.DocsProcessed = len(docFiles)
-	stats.TotalDocChunks = len(docChunks)
-	log.Printf("[TIMING] Process doc files: %v (%d files -> %d chunks)\n",
-		time.Since(phaseStart), len(docFiles), stats.TotalDocChunks)
-
-	// Check for cancellation
-	if err := ctx.Err(); err != nil {
-		return nil, err
-	}
-
-	// Phase 5: Write chunks to storage
-	phaseStart = time.Now()
-	p.progress.OnWritingChunks()
-	if err := p.writeChunks(symbolsChunks, defsChunks, dataChunks, docChunks); err != nil {
-		return nil, fmt.Errorf("failed to write chunks: %w", err)
+	stats.TotalCodeChunks = pipelineStats.codeChunks
+	stats.TotalDocChunks = pipelineStats.docChunks
+	stats.Stages = pipelineStats.stages
+	log.Printf("[TIMING] Pipeline: %v (%d files -> %d chunks)\n",
+		time.Since(phaseStart), len(codeFiles)+len(docFiles), stats.TotalCodeChunks+stats.TotalDocChunks)
+	for _, stage := range stats.Stages {
+		log.Printf("[TIMING]   - %s\n", stage)
 	}
-	log.Printf("[TIMING] Write chunks: %v\n", time.Since(phaseStart))
 
 	stats.ProcessingTime = time.Since(startTime)
 	return stats, nil
 }
 
+// relPath returns the path file is stored under.
+func (p *processor) relPath(file string) string {
+	relPath, _, _ := p.roots.filePath(file)
+	return relPath
+}
+
+// redactChunks redacts the secrets in the text of chunks, in place.
+// Findings were recorded when the file's content was written.
+func (p *processor) redactChunks(chunks []Chunk) {
+	if p.secrets == nil {
+		return
+	}
+	for i := range chunks {
+		chunks[i].Text, _ = p.secrets.Redact(chunks[i].Text)
+	}
+}
+
 // separateFiles separates files into code files and documentation files.
 func (p *processor) separateFiles(files []string) (codeFiles, docFiles []string) {
 	for _, file := range files {
@@ -206,317 +298,423 @@ func (p *processor) separateFiles(files []string) (codeFiles, docFiles []string)
 	return codeFiles, docFiles
 }
 
-// processCodeFiles processes code files and returns chunks.
-func (p *processor) processCodeFiles(ctx context.Context, files []string) (symbols, definitions, data []Chunk, err error) {
-	symbols = []Chunk{}
-	definitions = []Chunk{}
-	data = []Chunk{}
-
-	if len(files) == 0 {
-		return symbols, definitions, data, nil
-	}
-
-	p.progress.OnFileProcessingStart(len(files))
-
-	var parsingTime, chunkingTime time.Duration
+// processCodeFile parses a code file and returns its symbols, definitions, data and docstring chunks.
+// Returns no chunks (and no error) for unsupported languages and unparseable files.
+func (p *processor) processCodeFile(ctx context.Context, file string) ([]Chunk, error) {
+	var chunks []Chunk
 
-	for _, file := range files {
-		// Check for cancellation
-		if err := ctx.Err(); err != nil {
-			return nil, nil, nil, err
+	extraction, err := p.parser.ParseFile(ctx, file)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
 		}
+		log.Printf("Warning: failed to parse %s: %v\n", file, err)
+		return nil, nil
+	}
 
-		// Parse file
-		parseStart := time.Now()
-		extraction, err := p.parser.ParseFile(ctx, file)
-		parsingTime += time.Since(parseStart)
-		if err != nil {
-			log.Printf("Warning: failed to parse %s: %v\n", file, err)
-			p.progress.OnFileProcessed(file)
-			continue
-		}
+	if extraction == nil {
+		// Unsupported language
+		return nil, nil
+	}
 
-		if extraction == nil {
-			// Unsupported language
-			p.progress.OnFileProcessed(file)
-			continue
-		}
+	relPath := p.relPath(file)
+	now := time.Now()
 
-		relPath, _ := filepath.Rel(p.rootDir, file)
-		now := time.Now()
-		chunkStart := time.Now()
-
-		// Create symbols chunk
-		if extraction.Symbols != nil {
-			text := p.formatter.FormatSymbols(extraction.Symbols, extraction.Language)
-			if text != "" {
-				tags := []string{"code", extraction.Language, "symbols"}
-				metadata := map[string]interface{}{
-					"source":    "code",
-					"file_path": relPath,
-					"language":  extraction.Language,
-					"package":   extraction.Symbols.PackageName,
-				}
-				// Store tags as indexed metadata keys for chromem-go WHERE filtering
-				for i, tag := range tags {
-					metadata[fmt.Sprintf("tag_%d", i)] = tag
-				}
-				chunk := Chunk{
-					ID:        fmt.Sprintf("code-symbols-%s", relPath),
-					ChunkType: ChunkTypeSymbols,
-					Title:     fmt.Sprintf("Symbols: %s", relPath),
-					Text:      text,
-					Tags:      tags,
-					Metadata:  metadata,
-					CreatedAt: now,
-					UpdatedAt: now,
-				}
-				symbols = append(symbols, chunk)
+	// Create symbols chunk
+	if extraction.Symbols != nil {
+		text := p.formatter.FormatSymbols(extraction.Symbols, extraction.Language)
+		if text != "" {
+			tags := []string{"code", extraction.Language, "symbols"}
+			metadata := map[string]interface{}{
+				"source":    "code",
+				"file_path": relPath,
+				"language":  extraction.Language,
+				"package":   extraction.Symbols.PackageName,
 			}
-		}
-
-		// Create definitions chunk
-		if extraction.Definitions != nil && len(extraction.Definitions.Definitions) > 0 {
-			text := p.formatter.FormatDefinitions(extraction.Definitions, extraction.Language)
-			if text != "" {
-				tags := []string{"code", extraction.Language, "definitions"}
-				metadata := map[string]interface{}{
-					"source":    "code",
-					"file_path": relPath,
-					"language":  extraction.Language,
-				}
-				// Store tags as indexed metadata keys for chromem-go WHERE filtering
-				for i, tag := range tags {
-					metadata[fmt.Sprintf("tag_%d", i)] = tag
-				}
-				chunk := Chunk{
-					ID:        fmt.Sprintf("code-definitions-%s", relPath),
-					ChunkType: ChunkTypeDefinitions,
-					Title:     fmt.Sprintf("Definitions: %s", relPath),
-					Text:      text,
-					Tags:      tags,
-					Metadata:  metadata,
-					CreatedAt: now,
-					UpdatedAt: now,
-				}
-				definitions = append(definitions, chunk)
+			// Store tags as indexed metadata keys for chromem-go WHERE filtering
+			for i, tag := range tags {
+				metadata[fmt.Sprintf("tag_%d", i)] = tag
+			}
+			chunk := Chunk{
+				ID:        fmt.Sprintf("code-symbols-%s", relPath),
+				ChunkType: ChunkTypeSymbols,
+				Title:     fmt.Sprintf("Symbols: %s", relPath),
+				Text:      text,
+				Tags:      tags,
+				Metadata:  metadata,
+				CreatedAt: now,
+				UpdatedAt: now,
 			}
+			chunks = append(chunks, chunk)
 		}
+	}
 
-		// Create data chunk
-		if extraction.Data != nil && (len(extraction.Data.Constants) > 0 || len(extraction.Data.Variables) > 0) {
-			text := p.formatter.FormatData(extraction.Data, extraction.Language)
-			if text != "" {
-				tags := []string{"code", extraction.Language, "data"}
-				metadata := map[string]interface{}{
-					"source":    "code",
-					"file_path": relPath,
-					"language":  extraction.Language,
-				}
-				// Store tags as indexed metadata keys for chromem-go WHERE filtering
-				for i, tag := range tags {
-					metadata[fmt.Sprintf("tag_%d", i)] = tag
-				}
-				chunk := Chunk{
-					ID:        fmt.Sprintf("code-data-%s", relPath),
-					ChunkType: ChunkTypeData,
-					Title:     fmt.Sprintf("Data: %s", relPath),
-					Text:      text,
-					Tags:      tags,
-					Metadata:  metadata,
-					CreatedAt: now,
-					UpdatedAt: now,
-				}
-				data = append(data, chunk)
+	// Create definitions chunk
+	if extraction.Definitions != nil && len(extraction.Definitions.Definitions) > 0 {
+		text := p.formatter.FormatDefinitions(extraction.Definitions, extraction.Language)
+		if text != "" {
+			tags := []string{"code", extraction.Language, "definitions"}
+			metadata := map[string]interface{}{
+				"source":    "code",
+				"file_path": relPath,
+				"language":  extraction.Language,
+			}
+			// Store tags as indexed metadata keys for chromem-go WHERE filtering
+			for i, tag := range tags {
+				metadata[fmt.Sprintf("tag_%d", i)] = tag
+			}
+			chunk := Chunk{
+				ID:        fmt.Sprintf("code-definitions-%s", relPath),
+				ChunkType: ChunkTypeDefinitions,
+				Title:     fmt.Sprintf("Definitions: %s", relPath),
+				Text:      text,
+				Tags:      tags,
+				Metadata:  metadata,
+				CreatedAt: now,
+				UpdatedAt: now,
 			}
+			chunks = append(chunks, chunk)
 		}
-
-		chunkingTime += time.Since(chunkStart)
-		p.progress.OnFileProcessed(file)
 	}
 
-	log.Printf("[TIMING]   - Parsing (tree-sitter): %v\n", parsingTime)
-	log.Printf("[TIMING]   - Chunking (formatting): %v\n", chunkingTime)
-
-	// Generate embeddings
-	totalChunks := len(symbols) + len(definitions) + len(data)
-	if totalChunks > 0 {
-		p.progress.OnEmbeddingStart(totalChunks)
+	// Create data chunks, one per section of configuration files
+	if extraction.Data != nil {
+		for _, section := range dataSections(extraction.Data) {
+			text := p.formatter.FormatData(section.data, extraction.Language)
+			if text == "" {
+				continue
+			}
+			tags := []string{"code", extraction.Language, "data"}
+			metadata := map[string]interface{}{
+				"source":    "code",
+				"file_path": relPath,
+				"language":  extraction.Language,
+			}
+			// Store tags as indexed metadata keys for chromem-go WHERE filtering
+			for i, tag := range tags {
+				metadata[fmt.Sprintf("tag_%d", i)] = tag
+			}
+			id := fmt.Sprintf("code-data-%s", relPath)
+			title := fmt.Sprintf("Data: %s", relPath)
+			if section.name != "" {
+				metadata["section"] = section.name
+				id = fmt.Sprintf("code-data-%s:%s", relPath, section.name)
+				title = fmt.Sprintf("Data: %s (%s)", relPath, section.name)
+			}
+			chunk := Chunk{
+				ID:        id,
+				ChunkType: ChunkTypeData,
+				Title:     title,
+				Text:      text,
+				Tags:      tags,
+				Metadata:  metadata,
+				CreatedAt: now,
+				UpdatedAt: now,
+			}
+			chunks = append(chunks, chunk)
+		}
 	}
 
-	embeddingStart := time.Now()
-	if len(symbols) > 0 {
-		if err := p.embedChunks(ctx, symbols); err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to embed symbols: %w", err)
+	// Create one docstring chunk per documented symbol
+	for _, doc := range collectDocstrings(extraction) {
+		tags := []string{"code", extraction.Language, "docstring"}
+		metadata := map[string]interface{}{
+			"source":     "code",
+			"file_path":  relPath,
+			"language":   extraction.Language,
+			"symbol":     doc.Name,
+			"start_line": doc.StartLine,
+			"end_line":   doc.EndLine,
 		}
-		p.progress.OnEmbeddingProgress(len(symbols))
-	}
-	if len(definitions) > 0 {
-		if err := p.embedChunks(ctx, definitions); err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to embed definitions: %w", err)
+		// Store tags as indexed metadata keys for chromem-go WHERE filtering
+		for i, tag := range tags {
+			metadata[fmt.Sprintf("tag_%d", i)] = tag
 		}
-		p.progress.OnEmbeddingProgress(len(symbols) + len(definitions))
+		id := fmt.Sprintf("code-docstring-%s:%d:%s", relPath, doc.StartLine, doc.Name)
+		if doc.Cell > 0 {
+			metadata["cell"] = doc.Cell
+			id = fmt.Sprintf("code-docstring-%s:cell%d:%d:%s", relPath, doc.Cell, doc.StartLine, doc.Name)
+		}
+		chunk := Chunk{
+			ID:        id,
+			ChunkType: ChunkTypeDocstring,
+			Title:     fmt.Sprintf("Docstring: %s (%s)", doc.Name, relPath),
+			Text:      p.formatter.FormatDocstring(&doc, extraction.Language),
+			Tags:      tags,
+			Metadata:  metadata,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		chunks = append(chunks, chunk)
 	}
-	if len(data) > 0 {
-		if err := p.embedChunks(ctx, data); err != nil {
-			return nil, nil, nil, fmt.Errorf("failed to embed data: %w", err)
+
+	// Chunk the markdown cells of notebooks like documentation files
+	for _, cell := range extraction.Markdown {
+		cellChunks, err := p.processMarkdownCell(ctx, file, relPath, cell)
+		if err != nil {
+			return nil, err
 		}
-		p.progress.OnEmbeddingProgress(totalChunks)
+		chunks = append(chunks, cellChunks...)
 	}
-	embeddingTime := time.Since(embeddingStart)
-	log.Printf("[TIMING]   - Embedding: %v (%d chunks)\n", embeddingTime, totalChunks)
 
-	return symbols, definitions, data, nil
+	return chunks, nil
 }
 
-// processDocFiles processes documentation files and returns chunks.
-func (p *processor) processDocFiles(ctx context.Context, files []string) ([]Chunk, error) {
-	chunks := []Chunk{}
-
-	if len(files) == 0 {
-		return chunks, nil
+// processMarkdownCell chunks a markdown cell of a notebook. Chunk line
+// numbers are relative to the cell.
+func (p *processor) processMarkdownCell(ctx context.Context, file, relPath string, cell MarkdownCell) ([]Chunk, error) {
+	if strings.TrimSpace(cell.Content) == "" {
+		return nil, nil // ChunkDocument reads the file for empty content
+	}
+	docChunks, err := p.chunker.ChunkDocument(ctx, file, cell.Content)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Warning: failed to chunk cell %d of %s: %v\n", cell.Cell, file, err)
+		return nil, nil
 	}
 
-	var chunkingTime, formattingTime time.Duration
-
-	for _, file := range files {
-		// Check for cancellation
-		if err := ctx.Err(); err != nil {
-			return nil, err
+	var chunks []Chunk
+	now := time.Now()
+	for _, dc := range docChunks {
+		chunkID := fmt.Sprintf("doc-%s:cell%d-s%d", relPath, cell.Cell, dc.SectionIndex)
+		if dc.ChunkIndex > 0 {
+			chunkID = fmt.Sprintf("doc-%s:cell%d-s%d-c%d", relPath, cell.Cell, dc.SectionIndex, dc.ChunkIndex)
 		}
 
-		chunkStart := time.Now()
-		docChunks, err := p.chunker.ChunkDocument(ctx, file, "")
-		chunkingTime += time.Since(chunkStart)
-		if err != nil {
-			log.Printf("Warning: failed to chunk %s: %v\n", file, err)
-			p.progress.OnFileProcessed(file)
-			continue
+		tags := []string{"documentation", "markdown", "notebook"}
+		metadata := map[string]interface{}{
+			"source":        "markdown",
+			"file_path":     relPath,
+			"cell":          cell.Cell,
+			"section_index": dc.SectionIndex,
+			"chunk_index":   dc.ChunkIndex,
+			"start_line":    dc.StartLine,
+			"end_line":      dc.EndLine,
 		}
+		// Store tags as indexed metadata keys for chromem-go WHERE filtering
+		for i, tag := range tags {
+			metadata[fmt.Sprintf("tag_%d", i)] = tag
+		}
+		chunks = append(chunks, Chunk{
+			ID:        chunkID,
+			ChunkType: ChunkTypeDocumentation,
+			Title:     fmt.Sprintf("Documentation: %s (cell %d)", relPath, cell.Cell),
+			Text:      p.formatter.FormatDocumentation(&dc),
+			Tags:      tags,
+			Metadata:  metadata,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+	return chunks, nil
+}
 
-		relPath, _ := filepath.Rel(p.rootDir, file)
-		now := time.Now()
+// dataSection is the data of one section of a file.
+type dataSection struct {
+	name string // "" for constants without a section and variables
+	data *extraction.DataData
+}
 
-		formatStart := time.Now()
-		for _, dc := range docChunks {
-			text := p.formatter.FormatDocumentation(&dc)
-			chunkID := fmt.Sprintf("doc-%s-s%d", relPath, dc.SectionIndex)
-			if dc.ChunkIndex > 0 {
-				chunkID = fmt.Sprintf("doc-%s-s%d-c%d", relPath, dc.SectionIndex, dc.ChunkIndex)
-			}
+// dataSections groups constants by section, in order of appearance.
+// Variables and constants without a section come first.
+func dataSections(data *extraction.DataData) []dataSection {
+	sections := []dataSection{{data: &extraction.DataData{Variables: data.Variables}}}
+	index := map[string]int{"": 0}
+	for _, c := range data.Constants {
+		i, ok := index[c.Section]
+		if !ok {
+			i = len(sections)
+			index[c.Section] = i
+			sections = append(sections, dataSection{name: c.Section, data: &extraction.DataData{}})
+		}
+		sections[i].data.Constants = append(sections[i].data.Constants, c)
+	}
+	if len(sections[0].data.Constants) == 0 && len(sections[0].data.Variables) == 0 {
+		sections = sections[1:]
+	}
+	return sections
+}
 
-			tags := []string{"documentation", "markdown"}
-			metadata := map[string]interface{}{
-				"source":        "markdown",
-				"file_path":     relPath,
-				"section_index": dc.SectionIndex,
-				"chunk_index":   dc.ChunkIndex,
-				"start_line":    dc.StartLine,
-				"end_line":      dc.EndLine,
+// collectDocstrings returns the documented types, functions and constants of
+// an extraction, in that order.
+func collectDocstrings(ext *CodeExtraction) []Docstring {
+	var docs []Docstring
+	if ext.Symbols != nil {
+		for _, t := range ext.Symbols.Types {
+			if t.Doc != "" {
+				docs = append(docs, Docstring{Name: t.Name, Kind: t.Type, Doc: t.Doc, StartLine: t.StartLine, EndLine: t.EndLine, Cell: t.Cell})
 			}
-			// Store tags as indexed metadata keys for chromem-go WHERE filtering
-			for i, tag := range tags {
-				metadata[fmt.Sprintf("tag_%d", i)] = tag
+		}
+		for _, fn := range ext.Symbols.Functions {
+			if fn.Doc != "" {
+				signature := fn.Signature
+				if signature == "" {
+					signature = fn.Name + "()"
+				}
+				docs = append(docs, Docstring{Name: fn.Name, Kind: fn.Type, Signature: signature, Doc: fn.Doc, StartLine: fn.StartLine, EndLine: fn.EndLine, Cell: fn.Cell})
 			}
-			chunk := Chunk{
-				ID:        chunkID,
-				ChunkType: ChunkTypeDocumentation,
-				Title:     fmt.Sprintf("Documentation: %s (section %d)", relPath, dc.SectionIndex),
-				Text:      text,
-				Tags:      tags,
-				Metadata:  metadata,
-				CreatedAt: now,
-				UpdatedAt: now,
+		}
+	}
+	if ext.Data != nil {
+		for _, c := range ext.Data.Constants {
+			if c.Doc != "" {
+				docs = append(docs, Docstring{Name: c.Name, Kind: "constant", Signature: formatConstant(c, ext.Language), Doc: c.Doc, StartLine: c.StartLine, EndLine: c.EndLine, Cell: c.Cell})
 			}
-			chunks = append(chunks, chunk)
 		}
-		formattingTime += time.Since(formatStart)
+	}
+	return docs
+}
+
+// processDocFile chunks a documentation file and returns its chunks.
+// Returns no chunks (and no error) for files that fail to chunk.
+func (p *processor) processDocFile(ctx context.Context, file string) ([]Chunk, error) {
+	var chunks []Chunk
 
-		p.progress.OnFileProcessed(file)
+	docChunks, err := p.chunker.ChunkDocument(ctx, file, "")
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Printf("Warning: failed to chunk %s: %v\n", file, err)
+		return nil, nil
 	}
 
-	log.Printf("[TIMING]   - Chunking (markdown parsing): %v\n", chunkingTime)
-	log.Printf("[TIMING]   - Formatting: %v\n", formattingTime)
+	relPath := p.relPath(file)
+	now := time.Now()
+
+	for _, dc := range docChunks {
+		text := p.formatter.FormatDocumentation(&dc)
+		chunkID := fmt.Sprintf("doc-%s-s%d", relPath, dc.SectionIndex)
+		if dc.ChunkIndex > 0 {
+			chunkID = fmt.Sprintf("doc-%s-s%d-c%d", relPath, dc.SectionIndex, dc.ChunkIndex)
+		}
 
-	// Generate embeddings
-	embeddingStart := time.Now()
-	if len(chunks) > 0 {
-		p.progress.OnEmbeddingStart(len(chunks))
-		if err := p.embedChunks(ctx, chunks); err != nil {
-			return nil, fmt.Errorf("failed to embed documentation: %w", err)
+		tags := []string{"documentation", "markdown"}
+		metadata := map[string]interface{}{
+			"source":        "markdown",
+			"file_path":     relPath,
+			"section_index": dc.SectionIndex,
+			"chunk_index":   dc.ChunkIndex,
+			"start_line":    dc.StartLine,
+			"end_line":      dc.EndLine,
+		}
+		// Store tags as indexed metadata keys for chromem-go WHERE filtering
+		for i, tag := range tags {
+			metadata[fmt.Sprintf("tag_%d", i)] = tag
+		}
+		chunk := Chunk{
+			ID:        chunkID,
+			ChunkType: ChunkTypeDocumentation,
+			Title:     fmt.Sprintf("Documentation: %s (section %d)", relPath, dc.SectionIndex),
+			Text:      text,
+			Tags:      tags,
+			Metadata:  metadata,
+			CreatedAt: now,
+			UpdatedAt: now,
 		}
-		p.progress.OnEmbeddingProgress(len(chunks))
+		chunks = append(chunks, chunk)
 	}
-	embeddingTime := time.Since(embeddingStart)
-	log.Printf("[TIMING]   - Embedding: %v (%d chunks)\n", embeddingTime, len(chunks))
 
 	return chunks, nil
 }
 
-// embedChunks generates embeddings for chunks with progress feedback.
-func (p *processor) embedChunks(ctx context.Context, chunks []Chunk) error {
+// embedChunks generates embeddings for chunks in place and returns how many were
+// served from the embedding cache. When a cache is configured, only texts missing
+// from it are sent to the provider; identical texts within the batch are embedded once.
+// Callers are responsible for batching (see PipelineConfig.EmbedBatchSize).
+func (p *processor) embedChunks(ctx context.Context, chunks []Chunk) (int, error) {
 	if len(chunks) == 0 {
-		return nil
+		return 0, nil
 	}
 
-	// Extract texts
-	texts := make([]string, len(chunks))
-	for i, chunk := range chunks {
-		texts[i] = chunk.Text
-	}
-
-	// Create progress channel
-	progressCh := make(chan embed.BatchProgress, 10)
-
-	// Handle progress updates in background
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		for progress := range progressCh {
-			p.progress.OnEmbeddingProgress(progress.ProcessedChunks)
-		}
-	}()
-
-	// Embed with batching for progress feedback
-	// Batch size 50 = progress update every ~1.5s (50 chunks × 30ms)
-	embeddings, err := embed.EmbedWithProgress(
-		ctx,
-		p.provider,
-		texts,
-		embed.EmbedModePassage,
-		50, // Batch size for ~1s progress updates
-		progressCh,
+	// Resolve cached embeddings first
+	var (
+		ns     embedcache.Namespace
+		hashes []string
+		cached map[string][]float32
+	)
+	if p.embedCache != nil {
+		ns = embedcache.Namespace{
+			Model:      p.embedModel,
+			Dimensions: p.provider.Dimensions(),
+			Mode:       embed.EmbedModePassage,
+		}
+		hashes = make([]string, len(chunks))
+		for i, chunk := range chunks {
+			hashes[i] = embedcache.HashText(chunk.Text)
+		}
+
+		var err error
+		cached, err = p.embedCache.Get(ns, hashes)
+		if err != nil {
+			// Cache is an optimization: fall back to embedding everything
+			log.Printf("Warning: embedding cache lookup failed: %v\n", err)
+			cached = nil
+		}
+	}
+
+	// Collect unique texts that still need embedding
+	var (
+		texts      []string
+		textHashes []string
+		textIndex  = make(map[string]int) // text (or hash) -> index in texts
+		chunkText  = make([]int, len(chunks))
+		cacheHits  int
 	)
+	for i, chunk := range chunks {
+		key := chunk.Text
+		if hashes != nil {
+			key = hashes[i]
+			if emb, ok := cached[key]; ok {
+				chunks[i].Embedding = emb
+				chunkText[i] = -1
+				cacheHits++
+				continue
+			}
+		}
+		idx, seen := textIndex[key]
+		if !seen {
+			idx = len(texts)
+			textIndex[key] = idx
+			texts = append(texts, chunk.Text)
+			if hashes != nil {
+				textHashes = append(textHashes, key)
+			}
+		}
+		chunkText[i] = idx
+	}
 
-	// Close progress channel and wait for handler
-	close(progressCh)
-	<-done
+	if len(texts) == 0 {
+		return cacheHits, nil
+	}
 
+	embeddings, err := p.provider.Embed(ctx, texts, embed.EmbedModePassage)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	if len(embeddings) != len(texts) {
+		return 0, fmt.Errorf("embedding provider returned %d embeddings for %d texts", len(embeddings), len(texts))
 	}
 
 	// Assign embeddings to chunks
 	for i := range chunks {
-		chunks[i].Embedding = embeddings[i]
+		if chunkText[i] >= 0 {
+			chunks[i].Embedding = embeddings[chunkText[i]]
+		}
 	}
 
-	return nil
-}
-
-// writeChunks writes chunks to storage using WriteChunksIncremental.
-func (p *processor) writeChunks(symbols, definitions, data, docs []Chunk) error {
-	// Combine all chunks for writing
-	allChunks := make([]Chunk, 0, len(symbols)+len(definitions)+len(data)+len(docs))
-	allChunks = append(allChunks, symbols...)
-	allChunks = append(allChunks, definitions...)
-	allChunks = append(allChunks, data...)
-	allChunks = append(allChunks, docs...)
-
-	if len(allChunks) == 0 {
-		return nil
+	// Store fresh embeddings for other branches, worktrees and future edits
+	if p.embedCache != nil {
+		entries := make(map[string][]float32, len(textHashes))
+		for i, hash := range textHashes {
+			entries[hash] = embeddings[i]
+		}
+		if err := p.embedCache.Put(ns, entries); err != nil {
+			log.Printf("Warning: failed to store embeddings in cache: %v\n", err)
+		}
 	}
 
-	// Use incremental write (updates chunks for specific files)
-	return p.storage.WriteChunksIncremental(allChunks)
+	return cacheHits, nil
 }
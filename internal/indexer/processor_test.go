@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/embedcache"
 	storagepkg "github.com/mvp-joe/project-cortex/internal/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -661,6 +663,10 @@ func (m *mockFailingStorage) UpdateFileMtimes(filePaths []string) error {
 	return fmt.Errorf("storage error: simulated failure")
 }
 
+func (m *mockFailingStorage) BeginGeneration(ctx context.Context) (*storagepkg.Generation, error) {
+	return nil, nil
+}
+
 func (m *mockFailingStorage) Close() error {
 	return nil
 }
@@ -671,3 +677,173 @@ type mockFailingChunker struct{}
 func (m *mockFailingChunker) ChunkDocument(ctx context.Context, filePath, language string) ([]DocumentationChunk, error) {
 	return nil, fmt.Errorf("chunking error: simulated failure")
 }
+
+func TestProcessor_ProcessFiles_EmbeddingCache(t *testing.T) {
+	t.Parallel()
+
+	// Test: Chunks whose text is already cached are not re-embedded,
+	// including across separate branch databases sharing one cache
+	cache, err := embedcache.NewCache(filepath.Join(t.TempDir(), "embeddings.db"), 0)
+	require.NoError(t, err)
+	defer cache.Close()
+
+	tempDir := t.TempDir()
+	goFile := filepath.Join(tempDir, "main.go")
+	require.NoError(t, os.WriteFile(goFile, []byte("package main\n\nconst A = 1\n\nfunc Test() {}\n"), 0644))
+
+	run := func() (*countingEmbedProvider, *Stats) {
+		provider := &countingEmbedProvider{}
+		stor, err := setupProcessorTestStorage(t, storagepkg.NewTestDB(t), tempDir)
+		require.NoError(t, err)
+
+		processor := NewProcessor(tempDir, NewParser(), NewChunker(512, 50), NewFormatter(), provider, stor, nil,
+			WithEmbeddingCache(cache, "test-model"))
+		stats, err := processor.ProcessFiles(context.Background(), []string{goFile})
+		require.NoError(t, err)
+		return provider, stats
+	}
+
+	// First run embeds every chunk
+	first, stats := run()
+	require.Greater(t, stats.TotalCodeChunks, 0)
+	assert.Equal(t, stats.TotalCodeChunks, first.embeddedCount())
+
+	// Second run (e.g., another branch or worktree) is served from the cache
+	second, _ := run()
+	assert.Equal(t, 0, second.embeddedCount())
+
+	// Editing only the function body leaves the data chunk text unchanged
+	require.NoError(t, os.WriteFile(goFile, []byte("package main\n\nconst A = 1\n\nfunc Test() { println() }\n\nfunc Other() {}\n"), 0644))
+	third, stats := run()
+	assert.Greater(t, third.embeddedCount(), 0)
+	assert.Less(t, third.embeddedCount(), stats.TotalCodeChunks)
+}
+
+// countingEmbedProvider counts embedded texts. Safe for concurrent use.
+type countingEmbedProvider struct {
+	mockEmbedProvider
+	mu       sync.Mutex
+	embedded int
+}
+
+func (m *countingEmbedProvider) Embed(ctx context.Context, texts []string, mode embed.EmbedMode) ([][]float32, error) {
+	m.mu.Lock()
+	m.embedded += len(texts)
+	m.mu.Unlock()
+	return m.mockEmbedProvider.Embed(ctx, texts, mode)
+}
+
+func (m *countingEmbedProvider) embeddedCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.embedded
+}
+
+func TestProcessor_ProcessFiles_DocstringChunks(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	db := storagepkg.NewTestDB(t)
+	storage, err := setupProcessorTestStorage(t, db, tempDir)
+	require.NoError(t, err)
+
+	pyFile := filepath.Join(tempDir, "retry.py")
+	require.NoError(t, os.WriteFile(pyFile, []byte(`def retry(fn, attempts=3):
+    """Call fn until it succeeds, backing off exponentially."""
+    return fn()
+
+
+def undocumented():
+    pass
+`), 0644))
+
+	processor := createTestProcessor(t, tempDir, storage)
+	_, err = processor.ProcessFiles(context.Background(), []string{pyFile})
+	require.NoError(t, err)
+
+	// One docstring chunk per documented symbol, located at the symbol
+	var id, text string
+	var startLine, endLine int
+	require.NoError(t, db.QueryRow(
+		"SELECT chunk_id, text, start_line, end_line FROM chunks WHERE file_path = 'retry.py' AND chunk_type = 'docstring'",
+	).Scan(&id, &text, &startLine, &endLine))
+	assert.Equal(t, "code-docstring-retry.py:1:retry", id)
+	assert.Equal(t, "retry(fn, attempts=3) (lines 1-3)\n\nCall fn until it succeeds, backing off exponentially.", text)
+	assert.Equal(t, 1, startLine)
+	assert.Equal(t, 3, endLine)
+}
+
+func TestProcessor_ProcessFiles_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+
+	// Test: With a secret scanner, secrets are redacted from stored content
+	// and chunk text before embedding, and recorded as findings
+	tempDir := t.TempDir()
+	db := storagepkg.NewTestDB(t)
+	stor, err := setupProcessorTestStorage(t, db, tempDir)
+	require.NoError(t, err)
+
+	goFile := filepath.Join(tempDir, "aws.go")
+	require.NoError(t, os.WriteFile(goFile, []byte("package aws\n\n// AccessKey is the deploy user's key.\nconst AccessKey = \""+fakeAWSKey+"\"\n"), 0644))
+	mdFile := filepath.Join(tempDir, "README.md")
+	require.NoError(t, os.WriteFile(mdFile, []byte("# API\n\nCall it with:\n\n    Authorization: Bearer "+fakeJWT+"\n"), 0644))
+
+	scanner, err := NewSecretScanner(nil)
+	require.NoError(t, err)
+	provider := &recordingEmbedProvider{}
+	processor := NewProcessor(tempDir, NewParser(), NewChunker(512, 50), NewFormatter(), provider, stor, nil,
+		WithSecretScanner(scanner))
+	stats, err := processor.ProcessFiles(context.Background(), []string{goFile, mdFile})
+	require.NoError(t, err)
+	require.Greater(t, stats.TotalCodeChunks+stats.TotalDocChunks, 0)
+
+	var content string
+	require.NoError(t, db.QueryRow("SELECT content FROM files WHERE file_path = 'aws.go'").Scan(&content))
+	assert.Equal(t, "package aws\n\n// AccessKey is the deploy user's key.\nconst AccessKey = \"[REDACTED:aws-access-key]\"\n", content)
+
+	findings, err := storagepkg.QuerySecretFindings(db, storagepkg.SecretFindingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []storagepkg.SecretFinding{
+		{FilePath: "README.md", Line: 5, Column: 27, Detector: "jwt", Preview: "eyJh****"},
+		{FilePath: "aws.go", Line: 4, Column: 20, Detector: "aws-access-key", Preview: "AKIA****"},
+	}, findings)
+
+	texts := provider.embeddedTexts()
+	require.NotEmpty(t, texts)
+	for _, text := range texts {
+		assert.NotContains(t, text, fakeAWSKey)
+		assert.NotContains(t, text, fakeJWT)
+	}
+	var leaked int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM chunks WHERE text LIKE ? OR text LIKE ?", "%"+fakeAWSKey+"%", "%"+fakeJWT+"%").Scan(&leaked))
+	assert.Zero(t, leaked)
+
+	// Removing the secret clears the file's findings
+	require.NoError(t, os.WriteFile(goFile, []byte("package aws\n"), 0644))
+	_, err = processor.ProcessFiles(context.Background(), []string{goFile})
+	require.NoError(t, err)
+	findings, err = storagepkg.QuerySecretFindings(db, storagepkg.SecretFindingFilter{})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "README.md", findings[0].FilePath)
+}
+
+// recordingEmbedProvider records embedded texts. Safe for concurrent use.
+type recordingEmbedProvider struct {
+	mockEmbedProvider
+	mu    sync.Mutex
+	texts []string
+}
+
+func (m *recordingEmbedProvider) Embed(ctx context.Context, texts []string, mode embed.EmbedMode) ([][]float32, error) {
+	m.mu.Lock()
+	m.texts = append(m.texts, texts...)
+	m.mu.Unlock()
+	return m.mockEmbedProvider.Embed(ctx, texts, mode)
+}
+
+func (m *recordingEmbedProvider) embeddedTexts() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.texts...)
+}
@@ -26,6 +26,10 @@ type ProgressReporter interface {
 	// OnWritingChunks is called when writing chunk files begins.
 	OnWritingChunks()
 
+	// OnPipelineProgress is called periodically while files move through the
+	// parse → embed → write pipeline, and once more when it finishes.
+	OnPipelineProgress(stages []StageStats)
+
 	// OnComplete is called when indexing completes successfully.
 	OnComplete(stats *ProcessingStats)
 
@@ -46,6 +50,7 @@ func (n *NoOpProgressReporter) OnFileProcessed(fileName string)             {}
 func (n *NoOpProgressReporter) OnEmbeddingStart(totalChunks int)            {}
 func (n *NoOpProgressReporter) OnEmbeddingProgress(processedChunks int)     {}
 func (n *NoOpProgressReporter) OnWritingChunks()                            {}
+func (n *NoOpProgressReporter) OnPipelineProgress(stages []StageStats)      {}
 func (n *NoOpProgressReporter) OnComplete(stats *ProcessingStats)           {}
 func (n *NoOpProgressReporter) OnGraphBuildingStart(totalFiles int)         {}
 func (n *NoOpProgressReporter) OnGraphFileProcessed(processedFiles, totalFiles int, fileName string) {
@@ -0,0 +1,222 @@
+package indexer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// SecretPatternConfig is a custom secret detector: a regular expression
+// (Go RE2 syntax) whose matches are redacted. When the pattern has capture
+// groups, only the first group is redacted: `password=(\S+)`.
+type SecretPatternConfig struct {
+	Name    string
+	Pattern string
+}
+
+// secretDetector finds one kind of secret.
+type secretDetector struct {
+	name       string
+	pattern    *regexp.Regexp // The secret is the first group, or the whole match without groups
+	minEntropy float64        // Minimum Shannon entropy in bits per character; detectors with one also require letters and digits
+}
+
+// builtinSecretDetectors find cloud and service credentials, private keys,
+// JWTs and credentials in URLs.
+var builtinSecretDetectors = []secretDetector{
+	{name: "private-key", pattern: regexp.MustCompile(`-----BEGIN[A-Z ]* PRIVATE KEY(?: BLOCK)?-----(?:[A-Za-z0-9+/=\s:,.-]|\\[nr])*?-----END[A-Z ]* PRIVATE KEY(?: BLOCK)?-----`)},
+	{name: "aws-access-key", pattern: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`)},
+	{name: "aws-secret-key", pattern: regexp.MustCompile(`(?i)aws.{0,20}?secret.{0,20}?["'\s:=]+([A-Za-z0-9/+]{40})\b`), minEntropy: 3.5},
+	{name: "gcp-api-key", pattern: regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}\b`)},
+	{name: "azure-storage-key", pattern: regexp.MustCompile(`(?i)AccountKey=([A-Za-z0-9+/]{86}==)`)},
+	{name: "github-token", pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`)},
+	{name: "gitlab-token", pattern: regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`)},
+	{name: "slack-token", pattern: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`)},
+	{name: "stripe-key", pattern: regexp.MustCompile(`\b[rs]k_live_[A-Za-z0-9]{16,}`)},
+	{name: "anthropic-key", pattern: regexp.MustCompile(`\bsk-ant-[A-Za-z0-9_-]{20,}`)},
+	{name: "openai-key", pattern: regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{20,}`), minEntropy: 4.3}, // Not sk-learn-compatible-estimators
+	{name: "jwt", pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{8,}\.eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{10,}`)},
+	{name: "url-credentials", pattern: regexp.MustCompile(`\b[a-z][a-z0-9+.-]*://[^/:@\s"'<>]+:([^/@\s"'<>]+)@`), minEntropy: 2}, // Not postgres://user:pass@
+}
+
+// genericSecretDetectors find random-looking values assigned to secret
+// names or quoted on their own. They run after the other detectors, which
+// name a secret they also match.
+var genericSecretDetectors = []secretDetector{
+	{name: "secret-assignment", pattern: regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api_?key|private_?key|access_?key|auth_?key|credentials?)[a-z0-9_.-]*["']?\s*(?::=|=>|[:=])\s*["'` + "`" + `]?([A-Za-z0-9+/=_.~!@#%^&*-]{12,})`), minEntropy: 3.5},
+	{name: "high-entropy", pattern: regexp.MustCompile(`["'` + "`" + `]([A-Za-z0-9+/=_-]{32,})["'` + "`" + `]`), minEntropy: 4.5},
+}
+
+// secretPlaceholders are values written where a secret goes, not secrets.
+var secretPlaceholders = []string{"example", "xxxx", "****", "...", "<", "${", "{{", "your", "changeme", "placeholder", "dummy", "redacted"}
+
+// SecretScanner finds secrets in file content and chunk text and redacts
+// them, so they reach neither the cache database nor the embedding
+// provider. It is safe for concurrent use.
+type SecretScanner struct {
+	detectors []secretDetector
+}
+
+// NewSecretScanner returns a scanner with the built-in detectors, the
+// custom patterns, then the generic high-entropy detectors.
+func NewSecretScanner(custom []SecretPatternConfig) (*SecretScanner, error) {
+	s := &SecretScanner{detectors: append([]secretDetector{}, builtinSecretDetectors...)}
+	for _, cfg := range custom {
+		pattern, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid secret pattern %s: %w", cfg.Name, err)
+		}
+		s.detectors = append(s.detectors, secretDetector{name: cfg.Name, pattern: pattern})
+	}
+	s.detectors = append(s.detectors, genericSecretDetectors...)
+	return s, nil
+}
+
+// secretMatch is a secret at text[start:end].
+type secretMatch struct {
+	start, end int
+	detector   string
+}
+
+// Redact returns text with each secret replaced by "[REDACTED:<detector>]"
+// followed by the newlines the secret spanned, so line numbers don't shift,
+// and the secrets found, without a file path.
+func (s *SecretScanner) Redact(text string) (string, []storage.SecretFinding) {
+	matches := s.find(text)
+	if len(matches) == 0 {
+		return text, nil
+	}
+
+	var b strings.Builder
+	findings := make([]storage.SecretFinding, 0, len(matches))
+	line, lineStart, last := 1, 0, 0
+	for _, m := range matches {
+		for i := last; i < m.start; i++ {
+			if text[i] == '\n' {
+				line++
+				lineStart = i + 1
+			}
+		}
+		secret := text[m.start:m.end]
+		findings = append(findings, storage.SecretFinding{
+			Line:     line,
+			Column:   m.start - lineStart + 1,
+			Detector: m.detector,
+			Preview:  previewSecret(secret),
+		})
+
+		b.WriteString(text[last:m.start])
+		b.WriteString("[REDACTED:" + m.detector + "]")
+		b.WriteString(strings.Repeat("\n", strings.Count(secret, "\n")))
+		if i := strings.LastIndexByte(secret, '\n'); i >= 0 {
+			line += strings.Count(secret, "\n")
+			lineStart = m.start + i + 1
+		}
+		last = m.end
+	}
+	b.WriteString(text[last:])
+	return b.String(), findings
+}
+
+// redact returns text with its secrets redacted, like Redact. A nil
+// scanner returns text unchanged.
+func (s *SecretScanner) redact(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+	redacted, _ := s.Redact(text)
+	return redacted
+}
+
+// contains reports whether text holds a secret.
+func (s *SecretScanner) contains(text string) bool {
+	return len(s.find(text)) > 0
+}
+
+// find returns the secrets in text, in order. Where detectors overlap, the
+// earliest and then longest match wins, then the detector that runs first.
+func (s *SecretScanner) find(text string) []secretMatch {
+	var matches []secretMatch
+	for _, d := range s.detectors {
+		for _, loc := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 {
+				if loc[2] < 0 {
+					continue
+				}
+				start, end = loc[2], loc[3]
+			}
+			if start == end || !isSecretValue(text[start:end], d.minEntropy) {
+				continue
+			}
+			matches = append(matches, secretMatch{start: start, end: end, detector: d.name})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].start != matches[j].start {
+			return matches[i].start < matches[j].start
+		}
+		return matches[i].end > matches[j].end
+	})
+	kept := matches[:0]
+	end := 0
+	for _, m := range matches {
+		if m.start >= end {
+			kept = append(kept, m)
+			end = m.end
+		}
+	}
+	return kept
+}
+
+// isSecretValue reports whether a detector's match is a secret: not a
+// placeholder and, for detectors with a minimum entropy, random-looking
+// letters and digits.
+func isSecretValue(value string, minEntropy float64) bool {
+	lower := strings.ToLower(value)
+	for _, placeholder := range secretPlaceholders {
+		if strings.Contains(lower, placeholder) {
+			return false
+		}
+	}
+	if minEntropy == 0 {
+		return true
+	}
+	var hasLetter, hasDigit bool
+	for _, r := range value {
+		hasLetter = hasLetter || unicode.IsLetter(r)
+		hasDigit = hasDigit || unicode.IsDigit(r)
+	}
+	return hasLetter && hasDigit && shannonEntropy(value) >= minEntropy
+}
+
+// shannonEntropy returns the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	n := 0
+	for _, r := range s {
+		counts[r]++
+		n++
+	}
+	entropy := 0.0
+	for _, c := range counts {
+		p := float64(c) / float64(n)
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// previewSecret masks a secret for reports, keeping its first characters
+// when it is long enough not to give itself away.
+func previewSecret(secret string) string {
+	if len(secret) < 16 {
+		return "****"
+	}
+	return secret[:4] + "****"
+}
@@ -0,0 +1,106 @@
+package indexer
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/languages"
+)
+
+// Single-file component languages: Vue (.vue) and Svelte (.svelte) files
+// with markup, styles and <script> blocks.
+const (
+	languageVue    = languages.Vue
+	languageSvelte = languages.Svelte
+)
+
+var (
+	// sfcScriptPattern matches a <script> block: attributes and content.
+	sfcScriptPattern = regexp.MustCompile(`(?is)<script\b([^>]*)>(.*?)</script\s*>`)
+	// sfcAttrPattern matches an attribute of a <script> tag.
+	sfcAttrPattern = regexp.MustCompile(`(?i)\b(lang|type)\s*=\s*["']?([^"'\s>]*)`)
+)
+
+// sfcExtractor extracts the <script> blocks of single-file components
+// (<script>, <script setup>, <script context="module">) with the
+// TypeScript extractor if a block is declared lang="ts", the JavaScript
+// one otherwise. Everything outside the blocks is blanked out, keeping
+// newlines, so locations are lines of the component file.
+type sfcExtractor struct {
+	languages *LanguageRegistry
+}
+
+// ParseFile implements LanguageExtractor.
+func (e sfcExtractor) ParseFile(ctx context.Context, filePath string) (*CodeExtraction, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	source, language := sfcScriptSource(content)
+	if language == "" {
+		return nil, nil // No script
+	}
+	lang, ok := e.languages.Lookup(language)
+	if !ok || lang.Extractor == nil {
+		return nil, nil
+	}
+	return parseSource(ctx, lang.Extractor, filePath, source)
+}
+
+// sfcScriptSource returns content with everything but the content of its
+// script blocks replaced by spaces, and the language of the blocks:
+// "typescript" if any is declared lang="ts" (or "tsx"), "javascript"
+// otherwise, "" if there is none.
+func sfcScriptSource(content []byte) ([]byte, string) {
+	source := make([]byte, len(content))
+	for i, b := range content {
+		if b == '\n' || b == '\r' {
+			source[i] = b
+		} else {
+			source[i] = ' '
+		}
+	}
+
+	language := ""
+	for _, m := range sfcScriptPattern.FindAllSubmatchIndex(content, -1) {
+		attrs := string(content[m[2]:m[3]])
+		blockLanguage, ok := sfcScriptLanguage(attrs)
+		if !ok {
+			continue // e.g. <script type="text/x-template">
+		}
+		copy(source[m[4]:m[5]], content[m[4]:m[5]])
+		if language != "typescript" {
+			language = blockLanguage
+		}
+	}
+	return source, language
+}
+
+// sfcScriptLanguage returns the language of a <script> block from the
+// attributes of its tag, and false for blocks that aren't code.
+func sfcScriptLanguage(attrs string) (string, bool) {
+	language := "javascript"
+	for _, m := range sfcAttrPattern.FindAllStringSubmatch(attrs, -1) {
+		value := strings.ToLower(m[2])
+		switch strings.ToLower(m[1]) {
+		case "lang":
+			switch value {
+			case "ts", "tsx", "typescript":
+				language = "typescript"
+			case "js", "jsx", "javascript", "":
+			default:
+				return "", false // e.g. lang="coffee"
+			}
+		case "type":
+			if value != "module" && !strings.Contains(value, "javascript") && !strings.Contains(value, "typescript") {
+				return "", false
+			}
+			if strings.Contains(value, "typescript") {
+				language = "typescript"
+			}
+		}
+	}
+	return language, true
+}
@@ -0,0 +1,119 @@
+package indexer
+
+// Test Plan for Single-File Component Extraction:
+// - Vue <script setup lang="ts"> blocks go through the TypeScript extractor, keeping the
+//   line numbers of the component file
+// - Svelte components without lang go through the JavaScript extractor; module and instance
+//   scripts are both extracted
+// - Components without script blocks, or with non-code ones only, aren't extracted
+// - Block languages come from lang and type attributes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func parseSFC(t *testing.T, name, content string) *CodeExtraction {
+	t.Helper()
+	ext, err := sfcExtractor{DefaultLanguageRegistry()}.ParseFile(context.Background(), writeContractFile(t, name, content))
+	require.NoError(t, err)
+	return ext
+}
+
+func TestSFCExtractor_Vue(t *testing.T) {
+	t.Parallel()
+
+	ext := parseSFC(t, "UserCard.vue", `<template>
+  <div class="card">{{ user.name }}</div>
+</template>
+
+<script setup lang="ts">
+import { computed } from 'vue'
+
+interface User {
+  name: string
+}
+
+function initials(user: User): string {
+  return user.name.slice(0, 1)
+}
+</script>
+
+<style scoped>
+.card { color: red; }
+</style>
+`)
+	require.NotNil(t, ext)
+	assert.Equal(t, "typescript", ext.Language)
+	assert.Equal(t, "UserCard.vue", filepath.Base(ext.FilePath))
+
+	require.Len(t, ext.Symbols.Types, 1)
+	assert.Equal(t, "User", ext.Symbols.Types[0].Name)
+	assert.Equal(t, 8, ext.Symbols.Types[0].StartLine)
+	assert.Equal(t, 10, ext.Symbols.Types[0].EndLine)
+
+	require.Len(t, ext.Symbols.Functions, 1)
+	assert.Equal(t, "initials", ext.Symbols.Functions[0].Name)
+	assert.Equal(t, 12, ext.Symbols.Functions[0].StartLine)
+	assert.Equal(t, 14, ext.Symbols.Functions[0].EndLine)
+
+	require.Len(t, ext.Symbols.Imports, 1)
+	assert.Equal(t, "vue", ext.Symbols.Imports[0].Path)
+	assert.Equal(t, 6, ext.Symbols.Imports[0].Line)
+}
+
+func TestSFCExtractor_Svelte(t *testing.T) {
+	t.Parallel()
+
+	ext := parseSFC(t, "Counter.svelte", `<script context="module">
+  export const STEP = 1;
+</script>
+
+<script>
+  let count = 0;
+  function increment() { count += STEP; }
+</script>
+
+<button on:click={increment}>{count}</button>
+`)
+	require.NotNil(t, ext)
+	assert.Equal(t, "javascript", ext.Language)
+	require.Len(t, ext.Symbols.Functions, 1)
+	assert.Equal(t, "increment", ext.Symbols.Functions[0].Name)
+	assert.Equal(t, 7, ext.Symbols.Functions[0].StartLine)
+	require.Len(t, ext.Data.Constants, 1)
+	assert.Equal(t, "STEP", ext.Data.Constants[0].Name)
+	assert.Equal(t, 2, ext.Data.Constants[0].StartLine)
+
+	assert.Nil(t, parseSFC(t, "Static.svelte", "<h1>Hello</h1>\n"))
+	assert.Nil(t, parseSFC(t, "Template.vue", `<script type="text/x-template" id="t"><div></div></script>`))
+}
+
+func TestSFCScriptLanguage(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		attrs    string
+		language string
+		ok       bool
+	}{
+		{"", "javascript", true},
+		{` setup`, "javascript", true},
+		{` setup lang="ts"`, "typescript", true},
+		{` lang='tsx'`, "typescript", true},
+		{` lang=ts`, "typescript", true},
+		{` type="module"`, "javascript", true},
+		{` type="text/typescript"`, "typescript", true},
+		{` lang="coffee"`, "", false},
+		{` type="text/x-template"`, "", false},
+	}
+	for _, tt := range tests {
+		language, ok := sfcScriptLanguage(tt.attrs)
+		assert.Equal(t, tt.ok, ok, tt.attrs)
+		assert.Equal(t, tt.language, language, tt.attrs)
+	}
+}
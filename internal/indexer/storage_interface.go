@@ -1,6 +1,7 @@
 package indexer
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -33,6 +34,10 @@ type Storage interface {
 	// UpdateFileMtimes updates last_modified timestamps for unchanged files (mtime drift correction)
 	UpdateFileMtimes(filePaths []string) error
 
+	// BeginGeneration opens an index generation on the database. Until it is
+	// committed, writes to the database are invisible to its readers.
+	BeginGeneration(ctx context.Context) (*storage.Generation, error)
+
 	// Close releases resources
 	Close() error
 }
@@ -80,6 +85,8 @@ func NewSQLiteStorage(db *sql.DB, cacheRootPath, projectPath string) (Storage, e
 		if err := storage.CreateSchema(db); err != nil {
 			return nil, fmt.Errorf("failed to create schema: %w", err)
 		}
+	} else if err := storage.MigrateSchema(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	// Create chunk writer using the shared connection
@@ -158,27 +165,34 @@ func (s *SQLiteStorage) UpdateFileMtimes(filePaths []string) error {
 		return nil
 	}
 
-	// Read current mtimes from disk
-	for _, relPath := range filePaths {
-		absPath := filepath.Join(s.projectPath, relPath)
-		fileInfo, err := os.Stat(absPath)
-		if err != nil {
-			// File may have been deleted, skip
-			continue
-		}
-
-		// Update mtime in database (store as RFC3339 string)
-		_, err = s.db.Exec(
-			"UPDATE files SET last_modified = ? WHERE file_path = ?",
-			fileInfo.ModTime().Format(time.RFC3339),
-			relPath,
-		)
-		if err != nil {
-			return fmt.Errorf("failed to update mtime for %s: %w", relPath, err)
+	return storage.WithTx(s.db, func(tx *sql.Tx) error {
+		// Read current mtimes from disk
+		for _, relPath := range filePaths {
+			absPath := filepath.Join(s.projectPath, relPath)
+			fileInfo, err := os.Stat(absPath)
+			if err != nil {
+				// File may have been deleted, skip
+				continue
+			}
+
+			// Update mtime in database (store as RFC3339 string)
+			_, err = tx.Exec(
+				"UPDATE files SET last_modified = ? WHERE file_path = ?",
+				fileInfo.ModTime().Format(time.RFC3339),
+				relPath,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to update mtime for %s: %w", relPath, err)
+			}
 		}
-	}
+		return nil
+	})
+}
 
-	return nil
+// BeginGeneration opens an index generation on the database, waiting for
+// another index run on it to finish.
+func (s *SQLiteStorage) BeginGeneration(ctx context.Context) (*storage.Generation, error) {
+	return storage.BeginGeneration(ctx, s.db)
 }
 
 // Close releases resources held by SQLite storage.
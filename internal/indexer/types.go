@@ -14,6 +14,7 @@ const (
 	ChunkTypeDefinitions   ChunkType = "definitions"
 	ChunkTypeData          ChunkType = "data"
 	ChunkTypeDocumentation ChunkType = "documentation"
+	ChunkTypeDocstring     ChunkType = "docstring"
 )
 
 // Chunk represents a piece of indexed content with its embedding.
@@ -59,6 +60,14 @@ type CodeExtraction struct {
 	// Data contains constants, global variables, and configuration
 	Data *extraction.DataData
 
+	// Graph contains code graph data from extractors that provide it
+	// (external extractors declared with graph: true); nil otherwise
+	Graph *ExtractedGraph
+
+	// Markdown contains the markdown cells of notebooks, each chunked
+	// like a documentation file
+	Markdown []MarkdownCell
+
 	// Metadata about the extraction
 	Language  string
 	FilePath  string
@@ -66,6 +75,23 @@ type CodeExtraction struct {
 	EndLine   int
 }
 
+// MarkdownCell is a markdown cell of a notebook.
+type MarkdownCell struct {
+	Cell    int // 1-based index of the cell in the notebook
+	Content string
+}
+
+// Docstring is the doc comment or docstring of a function, type or constant.
+type Docstring struct {
+	Name      string
+	Kind      string // "function", "method", "struct", "constant", etc.
+	Signature string // Function signature or constant declaration; empty for types
+	Doc       string
+	StartLine int
+	EndLine   int
+	Cell      int // Notebook cell (1-based) the lines are relative to; 0 outside notebooks
+}
+
 // DocumentationChunk represents a chunk of documentation content.
 type DocumentationChunk struct {
 	FilePath         string
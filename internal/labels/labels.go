@@ -0,0 +1,19 @@
+// Package labels defines the labels that name the project and its content
+// sources. It has no dependencies, so configuration can be validated
+// without loading storage.
+package labels
+
+import "regexp"
+
+// Project selects the project's own files in label filters. It cannot be
+// used as a content source label.
+const Project = "project"
+
+// source matches valid content source labels. Labels are part of file
+// paths, so they are restricted to characters that need no escaping.
+var source = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]*$`)
+
+// ValidSource reports whether label can name a content source.
+func ValidSource(label string) bool {
+	return label != Project && source.MatchString(label)
+}
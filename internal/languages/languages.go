@@ -0,0 +1,51 @@
+// Package languages names the languages the indexer supports out of the box.
+// It has no dependencies, so configuration can be validated without loading
+// the indexer and its parsers.
+package languages
+
+// Built-in language names.
+const (
+	Go         = "go"
+	TypeScript = "typescript"
+	JavaScript = "javascript"
+	Python     = "python"
+	Rust       = "rust"
+	C          = "c"
+	Cpp        = "cpp"
+	Java       = "java"
+	PHP        = "php"
+	Ruby       = "ruby"
+	Jupyter    = "jupyter"
+	Vue        = "vue"
+	Svelte     = "svelte"
+	Protobuf   = "protobuf"
+	GraphQL    = "graphql"
+	YAML       = "yaml"
+	JSON       = "json"
+	TOML       = "toml"
+	Dotenv     = "dotenv"
+	OpenAPI    = "openapi"
+)
+
+// builtin holds the built-in language names.
+var builtin = map[string]bool{
+	Go: true, TypeScript: true, JavaScript: true, Python: true, Rust: true,
+	C: true, Cpp: true, Java: true, PHP: true, Ruby: true,
+	Jupyter: true, Vue: true, Svelte: true,
+	Protobuf: true, GraphQL: true, OpenAPI: true,
+	YAML: true, JSON: true, TOML: true, Dotenv: true,
+}
+
+// IsBuiltin reports whether name is a built-in language.
+func IsBuiltin(name string) bool {
+	return builtin[name]
+}
+
+// Builtin returns the built-in language names, in no particular order.
+func Builtin() []string {
+	names := make([]string, 0, len(builtin))
+	for name := range builtin {
+		names = append(names, name)
+	}
+	return names
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // sqliteExactSearcher implements ExactSearcher using SQLite FTS5.
@@ -55,6 +57,50 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	// Read from a snapshot so the reported generation matches the results
+	tx, generation, err := storage.BeginSnapshot(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]*ExactSearchResult, 0, limit)
+	if options.Source != SourceDependency {
+		if results, err = searchFiles(ctx, tx, "files_fts", "files", queryStr, limit, options, SourceProject); err != nil {
+			return nil, err
+		}
+
+		// Key paths of configuration files, which the file text doesn't hold
+		keyResults, err := searchConfigKeys(ctx, tx, queryStr, limit, options)
+		if err != nil {
+			return nil, err
+		}
+		results = mergeExactResults(results, keyResults, limit)
+	}
+
+	// The dependency corpus has no content sources, so a label filter excludes it
+	if (options.Source == SourceDependency || options.Source == SourceAll) && options.Label == "" {
+		attached, err := storage.HasDependencyCorpus(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check dependency corpus: %w", err)
+		}
+		if attached {
+			dependencyResults, err := searchFiles(ctx, tx, storage.DependencyCorpusSchema+".files_fts", storage.DependencyCorpusTable("files"), queryStr, limit, options, SourceDependency)
+			if err != nil {
+				return nil, err
+			}
+			results = mergeExactResults(results, dependencyResults, limit)
+		}
+	}
+
+	recordGeneration(ctx, generation)
+	return results, nil
+}
+
+// searchFiles runs an FTS5 query against the files of the project or of the
+// attached dependency corpus, given their FTS table and committed files view.
+// Entries match the file row of their generation.
+func searchFiles(ctx context.Context, tx *sql.Tx, ftsTable, filesTable, queryStr string, limit int, options *ExactSearchOptions, source string) ([]*ExactSearchResult, error) {
 	// Build FTS5 query with JOIN to files table
 	// Use snippet() for highlighted excerpts and rank for BM25 scoring
 	// Note: snippet(table, column_index, ...) where column_index is 0-based
@@ -67,8 +113,8 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 		"f.line_count_total",
 		"f.line_count_code",
 	).
-		From("files_fts").
-		Join("files f ON files_fts.file_path = f.file_path").
+		From(ftsTable).
+		Join(filesTable + " f ON files_fts.file_path = f.file_path AND files_fts.generation = f.generation").
 		Where(sq.Expr("files_fts.content MATCH ?", queryStr))
 
 	// Add optional filters
@@ -78,11 +124,14 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 	if options.FilePath != "" {
 		sqlQuery = sqlQuery.Where(sq.Like{"f.file_path": options.FilePath})
 	}
+	if options.Label != "" {
+		sqlQuery = sqlQuery.Where(storage.LabelFilter("f.file_path", options.Label))
+	}
 
 	sqlQuery = sqlQuery.OrderBy("rank").Limit(uint64(limit))
 
 	// Execute query
-	rows, err := sqlQuery.RunWith(s.db).QueryContext(ctx)
+	rows, err := sqlQuery.RunWith(tx).QueryContext(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("FTS5 search query failed: %w", err)
 	}
@@ -107,7 +156,6 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan result: %w", err)
 		}
-
 		// Build tags from language (file-level results)
 		tags := []string{"code", language}
 
@@ -151,6 +199,8 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 			Chunk:      chunk,
 			Score:      score,
 			Highlights: highlights,
+			Source:     source,
+			Label:      resultLabel(source, filePath),
 		})
 	}
 
@@ -161,6 +211,96 @@ func (s *sqliteExactSearcher) Search(ctx context.Context, queryStr string, optio
 	return results, nil
 }
 
+// searchConfigKeys runs an FTS5 query against the key paths and values of
+// configuration files. Each result is one key.
+func searchConfigKeys(ctx context.Context, tx *sql.Tx, queryStr string, limit int, options *ExactSearchOptions) ([]*ExactSearchResult, error) {
+	sqlQuery := sq.Select(
+		"c.file_path",
+		"c.document",
+		"c.key_path",
+		"c.value",
+		"c.line",
+		"rank",
+		"highlight(config_keys_fts, 0, '<mark>', '</mark>') as highlight",
+		"f.language",
+	).
+		From("config_keys_fts").
+		Join("config_keys c ON c.key_id = config_keys_fts.rowid").
+		Join("files f ON c.file_path = f.file_path").
+		Where(sq.Expr("config_keys_fts MATCH ?", queryStr))
+
+	if options.Language != "" {
+		sqlQuery = sqlQuery.Where(sq.Eq{"f.language": options.Language})
+	}
+	if options.FilePath != "" {
+		sqlQuery = sqlQuery.Where(sq.Like{"f.file_path": options.FilePath})
+	}
+	if options.Label != "" {
+		sqlQuery = sqlQuery.Where(storage.LabelFilter("f.file_path", options.Label))
+	}
+
+	rows, err := sqlQuery.OrderBy("rank").Limit(uint64(limit)).RunWith(tx).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("config key search query failed: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*ExactSearchResult, 0, limit)
+	for rows.Next() {
+		var (
+			filePath, keyPath, value, highlight, language string
+			document, line                                int
+			rank                                          float64
+		)
+		if err := rows.Scan(&filePath, &document, &keyPath, &value, &line, &rank, &highlight, &language); err != nil {
+			return nil, fmt.Errorf("failed to scan config key: %w", err)
+		}
+
+		metadata := map[string]interface{}{
+			"file_path": filePath,
+			"language":  language,
+			"key_path":  keyPath,
+			"value":     value,
+			"line":      line,
+		}
+		if document > 0 {
+			metadata["document"] = document
+		}
+		results = append(results, &ExactSearchResult{
+			Chunk: &ContextChunk{
+				ID:        fmt.Sprintf("config-%s:%d:%s", filePath, document, keyPath),
+				Title:     fmt.Sprintf("Config: %s (%s:%d)", keyPath, filePath, line),
+				Text:      fmt.Sprintf("%s = %s", keyPath, value),
+				ChunkType: "config_key",
+				Tags:      []string{"config", language},
+				Metadata:  metadata,
+			},
+			Score:      -rank,
+			Highlights: []string{highlight},
+			Source:     SourceProject,
+			Label:      resultLabel(SourceProject, filePath),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating config keys: %w", err)
+	}
+	return results, nil
+}
+
+// mergeExactResults merges the results of two FTS5 indexes by score and
+// keeps the best limit. BM25 scores of separate indexes are only roughly
+// comparable.
+func mergeExactResults(results, more []*ExactSearchResult, limit int) []*ExactSearchResult {
+	results = append(results, more...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
 // UpdateIncremental is a no-op for SQLite searcher (FTS5 always current).
 // FTS5 index is automatically maintained by SQLite triggers or explicit updates.
 func (s *sqliteExactSearcher) UpdateIncremental(ctx context.Context, added, updated []*ContextChunk, deleted []string) error {
@@ -8,6 +8,9 @@ package mcp
 // - Search handles empty results gracefully
 // - Search converts BM25 rank to score
 // - Search builds tags from language and chunk_type
+// - Search searches the attached dependency corpus by source, marking each result's source
+// - Search applies the content source label filter and marks each result's label
+// - Search matches the key paths of configuration files, one result per key
 // - UpdateIncremental is no-op (returns nil)
 // - Close is no-op (database externally managed)
 // - Integration: Search with phrase queries
@@ -292,6 +295,110 @@ func TestSQLiteExactSearcherSearch(t *testing.T) {
 	})
 }
 
+func TestSQLiteExactSearcherSearch_DependencySource(t *testing.T) {
+	t.Parallel()
+
+	db := setupDependencyCorpusTest(t)
+	searcher, err := NewSQLiteExactSearcher(db)
+	require.NoError(t, err)
+
+	search := func(options *ExactSearchOptions) []string {
+		results, err := searcher.Search(context.Background(), "retries", options)
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, r.Source+" "+r.Chunk.Metadata["file_path"].(string))
+		}
+		return found
+	}
+
+	assert.Equal(t, []string{"project internal/app/client.go"}, search(&ExactSearchOptions{Limit: 10}))
+	assert.Equal(t, []string{"dependency go/github.com/pkg/retry@v1.0.0/retry.go"},
+		search(&ExactSearchOptions{Limit: 10, Source: SourceDependency}))
+	assert.ElementsMatch(t, []string{
+		"project internal/app/client.go",
+		"dependency go/github.com/pkg/retry@v1.0.0/retry.go",
+	}, search(&ExactSearchOptions{Limit: 10, Source: SourceAll}))
+	assert.Equal(t, []string{"dependency go/github.com/pkg/retry@v1.0.0/retry.go"},
+		search(&ExactSearchOptions{Limit: 10, Source: SourceAll, FilePath: "go/github.com/%"}))
+}
+
+func TestSQLiteExactSearcherSearch_Label(t *testing.T) {
+	t.Parallel()
+
+	db := setupSQLiteExactSearcherTest(t)
+	now := time.Now().UTC()
+	for _, filePath := range []string{"internal/app/retry.go", "@wiki/retries.md"} {
+		content := "Retry requests with backoff"
+		insertFTSTestFileWithContent(t, db, filePath, "markdown", content)
+		insertFTSTestChunk(t, db, &storage.Chunk{
+			ID: "chunk-" + filePath, FilePath: filePath, ChunkType: "documentation", Title: filePath,
+			Text: content, Embedding: make([]float32, 384), StartLine: 1, EndLine: 1, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+
+	searcher, err := NewSQLiteExactSearcher(db)
+	require.NoError(t, err)
+
+	search := func(label string) []string {
+		results, err := searcher.Search(context.Background(), "backoff", &ExactSearchOptions{Limit: 10, Label: label})
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, r.Label+" "+r.Chunk.Metadata["file_path"].(string))
+		}
+		return found
+	}
+
+	assert.ElementsMatch(t, []string{"project internal/app/retry.go", "wiki @wiki/retries.md"}, search(""))
+	assert.Equal(t, []string{"wiki @wiki/retries.md"}, search("wiki"))
+	assert.Equal(t, []string{"project internal/app/retry.go"}, search("project"))
+	assert.Empty(t, search("specs"))
+}
+
+func TestSQLiteExactSearcherSearch_ConfigKeys(t *testing.T) {
+	t.Parallel()
+
+	db := setupSQLiteExactSearcherTest(t)
+	content := "spec:\n  replicas: 2\n  template:\n    spec:\n      containers:\n        - image: nginx:1.27\n"
+	insertFTSTestFileWithContent(t, db, "deploy/web.yaml", "yaml", content)
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, storage.ReplaceConfigKeys(tx, "deploy/web.yaml", []storage.ConfigKey{
+		{KeyPath: "spec.replicas", Value: "2", Line: 2},
+		{KeyPath: "spec.template.spec.containers[0].image", Value: "nginx:1.27", Line: 6},
+	}))
+	require.NoError(t, tx.Commit())
+
+	searcher, err := NewSQLiteExactSearcher(db)
+	require.NoError(t, err)
+
+	// The flattened path only exists as a config key
+	results, err := searcher.Search(context.Background(), `"spec.template.spec.containers"`, &ExactSearchOptions{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	chunk := results[0].Chunk
+	assert.Equal(t, "config_key", chunk.ChunkType)
+	assert.Equal(t, "spec.template.spec.containers[0].image = nginx:1.27", chunk.Text)
+	assert.Equal(t, "deploy/web.yaml", chunk.Metadata["file_path"])
+	assert.Equal(t, 6, chunk.Metadata["line"])
+	assert.Equal(t, "<mark>spec.template.spec.containers</mark>[0].image", results[0].Highlights[0])
+	assert.Equal(t, "project", results[0].Label)
+
+	// Matches in the file text and its keys are both returned
+	results, err = searcher.Search(context.Background(), "nginx", &ExactSearchOptions{Limit: 10})
+	require.NoError(t, err)
+	var types []string
+	for _, r := range results {
+		types = append(types, r.Chunk.ChunkType)
+	}
+	assert.ElementsMatch(t, []string{"file", "config_key"}, types)
+
+	results, err = searcher.Search(context.Background(), "nginx", &ExactSearchOptions{Limit: 10, Language: "go"})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
 // Lifecycle Tests
 
 func TestSQLiteExactSearcherUpdateIncremental(t *testing.T) {
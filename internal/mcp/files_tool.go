@@ -20,7 +20,7 @@ func AddCortexFilesTool(s *server.MCPServer, db *sql.DB) {
 Supports:
 - SELECT operations with field filtering
 - WHERE clauses with comparison operators (=, !=, >, >=, <, <=, LIKE, IN, BETWEEN)
-- JOIN operations across tables (files, types, functions, imports, modules, chunks)
+- JOIN operations across tables (files, types, functions, imports, import_resolutions, header_implementations, workspace_modules, file_modules, dependencies, content_sources, contract_symbols, contract_links, config_keys, config_usages, endpoints, table_usages, chunks)
 - GROUP BY with aggregations (COUNT, SUM, AVG, MIN, MAX)
 - ORDER BY with ASC/DESC sorting
 - LIMIT and OFFSET for pagination
@@ -28,7 +28,17 @@ Supports:
 Example queries:
 - Count files by language: {"from": "files", "aggregations": [{"function": "COUNT", "alias": "count"}], "groupBy": ["language"]}
 - Find large files: {"from": "files", "fields": ["file_path", "line_count_total"], "where": {"field": "line_count_total", "operator": ">", "value": 500}}
-- Module statistics: {"from": "modules", "fields": ["module_path", "file_count", "line_count_total"], "orderBy": [{"field": "file_count", "direction": "DESC"}]}`),
+- Files per workspace module: {"from": "file_modules", "aggregations": [{"function": "COUNT", "alias": "file_count"}], "groupBy": ["module_name"], "orderBy": [{"field": "file_count", "direction": "DESC"}]}
+- Third-party packages: {"from": "import_resolutions", "fields": ["package_name"], "where": {"field": "resolution", "operator": "=", "value": "external"}, "groupBy": ["package_name"]}
+- Files using a library: {"from": "dependency_usages", "fields": ["file_path", "import_path", "version"], "where": {"field": "dependency_name", "operator": "=", "value": "lodash"}}
+- Transitive dependencies: {"from": "dependencies", "fields": ["ecosystem", "name", "version", "manifest_path"], "where": {"field": "direct", "operator": "=", "value": 0}}
+- Code generated from an RPC: {"from": "contract_links", "fields": ["generated_id", "link_kind"], "where": {"field": "contract_symbol_id", "operator": "LIKE", "value": "%::IndexerService.Index"}}
+- Where a setting is configured: {"from": "config_keys", "fields": ["file_path", "key_path", "value", "line"], "where": {"field": "key_path", "operator": "LIKE", "value": "%timeout%"}}
+- Every environment variable a service reads: {"from": "config_usages", "fields": ["key"], "where": {"and": [{"field": "kind", "operator": "=", "value": "env"}, {"field": "file_path", "operator": "LIKE", "value": "services/billing/%"}]}, "groupBy": ["key"]}
+- Variables read but documented nowhere: {"from": "undocumented_config_keys", "fields": ["key", "file_path", "line", "function"]}
+- Files writing each table: {"from": "table_usages", "fields": ["table_name", "file_path"], "where": {"field": "access", "operator": "=", "value": "write"}, "groupBy": ["table_name", "file_path"]}
+
+workspace_modules lists the Go modules, npm packages, Python projects and Rust crates detected from manifests; file_modules assigns each file to one; import_resolutions records whether each import is internal (resolved_path, module_name), external or stdlib (package_name), or unresolved; C/C++ #includes resolve through compile_commands.json include paths. header_implementations pairs each C/C++ header with the source file implementing it. dependencies is the third-party inventory from manifests and lockfiles (go.mod/go.sum, package.json/package-lock.json, pyproject.toml/requirements*.txt/poetry.lock, Cargo.toml/Cargo.lock, pom.xml, Gemfile.lock); direct = 0 rows are transitive. dependency_usages lists the imports of each dependency. content_sources lists the directories outside the project configured in paths.sources; their files are stored as "@<label>/<path>" (use label to filter). contract_symbols tags the types and functions of API contracts (Protobuf, OpenAPI, GraphQL) with their protocol, kind and qualified_name; contract_links links Protobuf messages, services and RPCs to the Go code generated from them. config_keys holds the flattened key paths of YAML, JSON, TOML and .env template files ("spec.template.spec.containers[0].image") with their values (secrets masked) and lines; document is the index of the document in multi-document YAML files. endpoints lists the HTTP routes and RPC procedures registered in code (framework, kind http or rpc, method, path with group prefixes applied, handler as written); handler_function_id is the function handling the route, when it resolved. config_usages lists the environment variables (kind env) and config keys (kind config: viper, Spring, node-config, Laravel) read in code, with the API read through (source), the enclosing function and, for Go, its function_id. config_key_definitions pairs each key read with the config_keys defining it (same key path, a path ending in it such as services.api.environment.PORT, or a Kubernetes env entry); undocumented_config_keys lists the reads of keys defined nowhere, and unused_config_keys the .env template variables no code reads. table_usages lists the database tables the SQL embedded in code touches (string literals that start like a statement, squirrel and knex builder chains): access read, write or schema (DDL), the operation (select, insert, update, delete, create, alter, drop, ...), comma-separated columns when known, source (sql, squirrel, knex), the enclosing function and, for Go, its function_id.`),
 		mcp.WithString("operation",
 			mcp.Required(),
 			mcp.Description("Operation type: 'query' for custom queries")),
@@ -48,7 +58,9 @@ Example queries:
   "aggregations": [{"function": "COUNT", "field": "x", "alias": "count"}] // Aggregations (optional)
 }
 
-Available tables: files, types, functions, imports, modules, chunks`)),
+Available tables: files, types, functions, imports, import_resolutions, header_implementations, workspace_modules, file_modules, dependencies, dependency_usages, content_sources, contract_symbols, contract_links, config_keys, config_usages, config_key_definitions, undocumented_config_keys, unused_config_keys, endpoints, table_usages, chunks`)),
+		mcp.WithString("label",
+			mcp.Description("Only include files of this content source (a paths.sources label) or 'project' for the repository's own files. Filters the file path column of the 'from' table.")),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 	)
@@ -0,0 +1,31 @@
+package mcp
+
+import "context"
+
+// The indexer publishes writes in atomic index generations (see
+// storage.Generation). Searchers run all queries for a request inside one
+// read transaction (storage.BeginSnapshot), so results always come from a
+// single committed generation, and record which one so tool responses can
+// report it.
+
+// generationKey is the context key for a request's servedGeneration.
+type generationKey struct{}
+
+// servedGeneration receives the index generation a request was served from.
+type servedGeneration struct {
+	id int64
+}
+
+// withServedGeneration returns a context that collects the generation
+// searchers observe while serving a request.
+func withServedGeneration(ctx context.Context) (context.Context, *servedGeneration) {
+	served := &servedGeneration{}
+	return context.WithValue(ctx, generationKey{}, served), served
+}
+
+// recordGeneration stores generation in ctx's collector, if any.
+func recordGeneration(ctx context.Context, generation int64) {
+	if served, ok := ctx.Value(generationKey{}).(*servedGeneration); ok {
+		served.id = generation
+	}
+}
@@ -18,26 +18,27 @@ type GraphQuerier interface {
 
 // CortexGraphRequest represents the MCP tool request parameters.
 type CortexGraphRequest struct {
-	Operation      string `json:"operation"`       // "callers", "callees", "dependencies", "dependents"
+	Operation      string `json:"operation"`       // "callers", "callees", "dependencies", "dependents", "type_usages", "supertypes", "subtypes", "type_hierarchy", "endpoint", "config_usages", "table_usages"
 	Target         string `json:"target"`          // Target identifier to query
 	IncludeContext *bool  `json:"include_context"` // Whether to include code snippets (default: true)
 	ContextLines   int    `json:"context_lines"`   // Number of context lines (default: 3)
 	Depth          int    `json:"depth"`           // Traversal depth (default: 1)
 	MaxResults     int    `json:"max_results"`     // Maximum results (default: 100)
+	Label          string `json:"label"`           // Content source label or "project" (default: everything)
 }
 
 // AddCortexGraphTool registers the cortex_graph tool with an MCP server.
 func AddCortexGraphTool(s *server.MCPServer, querier GraphQuerier) {
 	tool := mcp.NewTool(
 		"cortex_graph",
-		mcp.WithDescription("Query structural code relationships for refactoring, impact analysis, and dependency exploration. Operations: callers (who calls this function), callees (what does this function call), dependencies (packages this imports), dependents (packages importing this), type_usages (where is this type used)."),
+		mcp.WithDescription("Query structural code relationships for refactoring, impact analysis, and dependency exploration. Operations: callers (who calls this function), callees (what does this function call), dependencies (packages this imports), dependents (packages importing this; the target may be a third-party dependency name), type_usages (where is this type used), supertypes (what a type extends/implements/embeds/mixes in), subtypes (what extends/implements/embeds/mixes in a type), type_hierarchy (both, as a tree rooted at the type), endpoint (the handler serving an HTTP route or RPC and what it calls), config_usages (the functions reading an environment variable or config key, where it is defined, and .env template variables no code reads), table_usages (the functions reading, writing or defining a database table through embedded SQL). Type hierarchies cover Go, TypeScript, Java, PHP and Rust. Routes are indexed for Go (net/http, chi, gin, echo, gorilla/mux, connect-go, grpc-go), Express, Fastify, Flask, FastAPI, Django, Spring and Rails. Config reads are indexed for os.Getenv/viper/env tags (Go), process.env and node-config, os.environ/os.getenv, System.getenv/@Value (Spring), ENV (Ruby), env::var (Rust), getenv/env()/config() (PHP) and getenv (C/C++). Table usages come from SQL string literals in every parsed language and from squirrel (Go) and knex (JavaScript/TypeScript) builder chains. Direct callers/callees carry a confidence: exact, dynamic (interface dispatch), unresolved (func value) or syntactic."),
 		mcp.WithString("operation",
 			mcp.Required(),
-			mcp.Enum("callers", "callees", "dependencies", "dependents", "type_usages"),
-			mcp.Description("Type of query: 'callers', 'callees', 'dependencies', 'dependents', or 'type_usages'")),
+			mcp.Enum("callers", "callees", "dependencies", "dependents", "type_usages", "supertypes", "subtypes", "type_hierarchy", "endpoint", "config_usages", "table_usages"),
+			mcp.Description("Type of query: 'callers', 'callees', 'dependencies', 'dependents', 'type_usages', 'supertypes', 'subtypes', 'type_hierarchy', 'endpoint', 'config_usages', or 'table_usages'")),
 		mcp.WithString("target",
 			mcp.Required(),
-			mcp.Description("Target identifier (e.g., 'embed.Provider', 'localProvider.Embed', 'internal/mcp')")),
+			mcp.Description("Target identifier (e.g., 'embed.Provider', 'localProvider.Embed', 'internal/mcp'). Hierarchy operations take a type name or type ID (e.g., 'UserService', 'src/services/user.ts::UserService'). The endpoint operation takes 'METHOD /path', a path, or a handler name (e.g., 'GET /api/orders/{id}', '/api/orders/42', '/indexer.v1.IndexerService/Index'). The config_usages operation takes a key, a prefix ending in '*', or '*' for every key (e.g., 'DATABASE_URL', 'server.port', 'AWS_*'). The table_usages operation takes a table, a prefix ending in '*', or '*', optionally followed by ':read', ':write' or ':schema' (e.g., 'chunks', 'chunks:write', 'config_*:schema')")),
 		mcp.WithBoolean("include_context",
 			mcp.Description("Include code snippets in results (default: true)")),
 		mcp.WithNumber("context_lines",
@@ -46,6 +47,8 @@ func AddCortexGraphTool(s *server.MCPServer, querier GraphQuerier) {
 			mcp.Description("Traversal depth for recursive queries (default: 1, max: 10)")),
 		mcp.WithNumber("max_results",
 			mcp.Description("Maximum number of results to return (default: 100, max: 500)")),
+		mcp.WithString("label",
+			mcp.Description("Only return results in files of this content source (a paths.sources label; files are stored as '@<label>/...'), or 'project' for the repository's own files")),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 	)
@@ -101,18 +104,27 @@ func createCortexGraphHandler(querier GraphQuerier) func(context.Context, mcp.Ca
 		if req.Target == "" {
 			return mcp.NewToolResultError("target is required"), nil
 		}
+		if !validLabel(req.Label) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid label %q: must be a content source label or project", req.Label)), nil
+		}
 
 		// Validate operation
 		validOps := map[string]graph.QueryOperation{
-			"callers":      graph.OperationCallers,
-			"callees":      graph.OperationCallees,
-			"dependencies": graph.OperationDependencies,
-			"dependents":   graph.OperationDependents,
-			"type_usages":  graph.OperationTypeUsages,
+			"callers":        graph.OperationCallers,
+			"callees":        graph.OperationCallees,
+			"dependencies":   graph.OperationDependencies,
+			"dependents":     graph.OperationDependents,
+			"type_usages":    graph.OperationTypeUsages,
+			"supertypes":     graph.OperationSupertypes,
+			"subtypes":       graph.OperationSubtypes,
+			"type_hierarchy": graph.OperationTypeHierarchy,
+			"endpoint":       graph.OperationEndpoint,
+			"config_usages":  graph.OperationConfigUsages,
+			"table_usages":   graph.OperationTableUsages,
 		}
 		graphOp, valid := validOps[req.Operation]
 		if !valid {
-			return mcp.NewToolResultError(fmt.Sprintf("invalid operation: %s (must be one of: callers, callees, dependencies, dependents, type_usages)", req.Operation)), nil
+			return mcp.NewToolResultError(fmt.Sprintf("invalid operation: %s (must be one of: callers, callees, dependencies, dependents, type_usages, supertypes, subtypes, type_hierarchy, endpoint, config_usages, table_usages)", req.Operation)), nil
 		}
 
 		// Build query request
@@ -123,6 +135,7 @@ func createCortexGraphHandler(querier GraphQuerier) func(context.Context, mcp.Ca
 			ContextLines:   req.ContextLines,
 			Depth:          req.Depth,
 			MaxResults:     req.MaxResults,
+			Label:          req.Label,
 		}
 
 		// Execute query
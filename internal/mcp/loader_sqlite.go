@@ -118,7 +118,7 @@ func updateBranchAccessTime(cacheDir, branch string, chunkCount int) error {
 // Tags are used for filtering in cortex_search queries.
 //
 // Tag structure:
-// - Chunk type tag: "symbols", "definitions", "data", "documentation"
+// - Chunk type tag: "symbols", "definitions", "data", "docstring", "documentation"
 // - Language tag: "go", "typescript", "python", etc.
 // - Content type tag: "code" (for programming languages, not added for documentation)
 //
@@ -146,7 +146,7 @@ func deriveTags(chunkType, filePath string) []string {
 		tags = append(tags, "java", "code")
 	case ".c", ".h":
 		tags = append(tags, "c", "code")
-	case ".cpp", ".hpp", ".cc":
+	case ".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx":
 		tags = append(tags, "cpp", "code")
 	case ".rb":
 		tags = append(tags, "ruby", "code")
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/mvp-joe/project-cortex/internal/embed"
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // ContextChunk represents a searchable chunk of code or documentation.
@@ -53,8 +54,48 @@ type SearchOptions struct {
 	// Tags filters results to only include chunks with ALL specified tags (AND logic)
 	Tags []string `json:"tags,omitempty"`
 
-	// ChunkTypes filters results by chunk type (documentation, symbols, definitions, data)
+	// ChunkTypes filters results by chunk type (documentation, symbols, definitions, data, docstring)
 	ChunkTypes []string `json:"chunk_types,omitempty"`
+
+	// Module filters results to files of a workspace module (Go module path, npm package, Python project or crate name)
+	Module string `json:"module,omitempty"`
+
+	// Source selects the corpus to search: SourceProject (default), SourceDependency or SourceAll
+	Source string `json:"source,omitempty"`
+
+	// Label filters results to the files of one content source (paths.sources label),
+	// or to the repository's own files for "project". Excludes the dependency corpus.
+	Label string `json:"label,omitempty"`
+}
+
+// Result sources. SourceAll is only a search option.
+const (
+	SourceProject    = "project"    // The project's branch database: its files and content sources
+	SourceDependency = "dependency" // The dependency corpus (sources of imported third-party packages)
+	SourceAll        = "all"
+)
+
+// validSource reports whether source is a valid source search option ("" means SourceProject).
+func validSource(source string) bool {
+	switch source {
+	case "", SourceProject, SourceDependency, SourceAll:
+		return true
+	}
+	return false
+}
+
+// validLabel reports whether label is a valid label filter ("" means no filter).
+func validLabel(label string) bool {
+	return label == "" || label == storage.ProjectLabel || storage.ValidContentSourceLabel(label)
+}
+
+// resultLabel returns the label of a result's file: its content source's
+// label or "project". Dependency corpus results have none.
+func resultLabel(source, filePath string) string {
+	if source != SourceProject {
+		return ""
+	}
+	return storage.ContentSourceLabel(filePath)
 }
 
 // DefaultSearchOptions returns default search options (limit: 15, no filters).
@@ -69,6 +110,8 @@ func DefaultSearchOptions() *SearchOptions {
 type SearchResult struct {
 	Chunk         *ContextChunk `json:"chunk"`
 	CombinedScore float64       `json:"combined_score"`
+	Source        string        `json:"source"`          // SourceProject or SourceDependency
+	Label         string        `json:"label,omitempty"` // Content source label or "project" (SourceProject only)
 }
 
 // MCPServerConfig contains configuration for the MCP server.
@@ -98,7 +141,10 @@ type CortexSearchRequest struct {
 	Query        string   `json:"query" jsonschema:"required,description=Natural language search query"`
 	Limit        int      `json:"limit,omitempty" jsonschema:"minimum=1,maximum=100,default=15,description=Maximum number of results"`
 	Tags         []string `json:"tags,omitempty" jsonschema:"description=Filter by tags (AND logic)"`
-	ChunkTypes   []string `json:"chunk_types,omitempty" jsonschema:"description=Filter by chunk type (documentation|symbols|definitions|data)"`
+	ChunkTypes   []string `json:"chunk_types,omitempty" jsonschema:"description=Filter by chunk type (documentation|symbols|definitions|data|docstring)"`
+	Module       string   `json:"module,omitempty" jsonschema:"description=Filter by workspace module (Go module path, npm package, Python project or crate name)"`
+	Source       string   `json:"source,omitempty" jsonschema:"enum=project,enum=dependency,enum=all,default=project,description=Search the project, the dependency corpus or both"`
+	Label        string   `json:"label,omitempty" jsonschema:"description=Filter by content source label (paths.sources) or 'project' for the repository's own files"`
 	IncludeStats bool     `json:"include_stats,omitempty" jsonschema:"default=false,description=Include reload metrics in response"`
 }
 
@@ -112,8 +158,9 @@ type CortexSearchResponse struct {
 
 // SearchResponseMetadata contains timing and source information.
 type SearchResponseMetadata struct {
-	TookMs int    `json:"took_ms"`
-	Source string `json:"source"` // "search"
+	TookMs     int    `json:"took_ms"`
+	Source     string `json:"source"`     // "search"
+	Generation int64  `json:"generation"` // Index generation the results were read from
 }
 
 // ExactSearchOptions contains parameters for exact keyword search queries.
@@ -126,6 +173,13 @@ type ExactSearchOptions struct {
 
 	// FilePath filters results using SQL LIKE pattern (e.g., "internal/%", "%_test.go")
 	FilePath string `json:"file_path,omitempty"`
+
+	// Source selects the corpus to search: SourceProject (default), SourceDependency or SourceAll
+	Source string `json:"source,omitempty"`
+
+	// Label filters results to the files of one content source, or to the
+	// repository's own files for "project". Excludes the dependency corpus.
+	Label string `json:"label,omitempty"`
 }
 
 // DefaultExactSearchOptions returns default exact search options (limit: 15, no filters).
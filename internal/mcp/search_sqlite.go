@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -76,31 +77,169 @@ func (s *sqliteSearcher) Query(ctx context.Context, query string, options *Searc
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	// All queries below read the same committed index generation
+	tx, generation, err := storage.BeginSnapshot(ctx, s.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
 	// Build vector similarity query with filters
 	// Fetch 2x limit for filtering headroom (same as chromem implementation)
 	topK := options.Limit * 2
 
-	// Base query: vector similarity + JOIN to chunks and files
-	sqlQuery := sq.Select(
-		"c.chunk_id",
-		"c.file_path",
-		"c.chunk_type",
-		"c.title",
-		"c.text",
-		"c.embedding",
-		"c.start_line",
-		"c.end_line",
-		"c.created_at",
-		"c.updated_at",
-		"f.language",
-		"vec.distance",
-	).
-		From("chunks_vec vec").
-		Join("chunks c ON vec.chunk_id = c.chunk_id").
-		Join("files f ON c.file_path = f.file_path").
+	results := make([]*SearchResult, 0, options.Limit)
+	if options.Source != SourceDependency {
+		if results, err = s.queryProject(ctx, tx, queryEmbedding, queryBytes, topK, options); err != nil {
+			return nil, err
+		}
+	}
+
+	// The dependency corpus has no workspace modules or content sources, so
+	// module and label filters exclude it
+	if (options.Source == SourceDependency || options.Source == SourceAll) && options.Module == "" && options.Label == "" {
+		attached, err := storage.HasDependencyCorpus(tx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check dependency corpus: %w", err)
+		}
+		if attached {
+			dependencyResults, err := s.queryDependencies(ctx, tx, queryBytes, topK, options)
+			if err != nil {
+				return nil, err
+			}
+			results = mergeSearchResults(results, dependencyResults, options.Limit)
+		}
+	}
+
+	recordGeneration(ctx, generation)
+	return results, nil
+}
+
+// searchColumns are the chunk and file columns scanned by scanSearchResults,
+// followed by the distance.
+var searchColumns = []string{
+	"c.chunk_id",
+	"c.file_path",
+	"c.chunk_type",
+	"c.title",
+	"c.text",
+	"c.embedding",
+	"c.start_line",
+	"c.end_line",
+	"c.created_at",
+	"c.updated_at",
+	"f.language",
+}
+
+// queryProject searches the project's chunks with the database's vector index backend.
+func (s *sqliteSearcher) queryProject(ctx context.Context, tx *sql.Tx, queryEmbedding []float32, queryBytes []byte, topK int, options *SearchOptions) ([]*SearchResult, error) {
+	// Resolve the vector index backend recorded for this database
+	index, err := storage.OpenVectorIndex(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector index: %w", err)
+	}
+
+	var (
+		sqlQuery  sq.SelectBuilder
+		distances map[string]float64 // Set when distances are computed by the index, outside SQL
+	)
+
+	switch {
+	case options.Module != "" || options.Label != "":
+		// A module or label can exclude most of the index's nearest neighbours,
+		// so the chunks in scope are ranked directly instead
+		sqlQuery = sq.Select(searchColumns...).
+			Column(sq.Expr("vec_distance_cosine(c.embedding, ?) AS distance", queryBytes)).
+			From("chunks c").
+			Join("files f ON c.file_path = f.file_path")
+	case storage.SupportsSQLKNN(index):
+		// Base query: vector similarity + JOIN to chunks and files
+		sqlQuery = sq.Select(append(searchColumns, "vec.distance AS distance")...).
+			From("chunks_vec vec").
+			Join("chunks c ON vec.chunk_id = c.chunk_id").
+			Join("files f ON c.file_path = f.file_path").
+			Where(sq.Expr("vec.embedding MATCH ?", queryBytes)).
+			Where(sq.Expr("k = ?", topK))
+	default:
+		// Approximate and quantized backends return candidate IDs; filters are applied by joining chunks and files
+		candidates, err := index.Search(tx, queryEmbedding, topK)
+		if err != nil {
+			return nil, fmt.Errorf("vector search failed: %w", err)
+		}
+		distances = make(map[string]float64, len(candidates))
+		ids := make([]string, len(candidates))
+		for i, c := range candidates {
+			ids[i] = c.ChunkID
+			distances[c.ChunkID] = c.Distance
+		}
+		sqlQuery = sq.Select(append(searchColumns, "0.0")...).
+			From("chunks c").
+			Join("files f ON c.file_path = f.file_path").
+			Where(sq.Eq{"c.chunk_id": ids})
+	}
+
+	sqlQuery = applySearchFilters(sqlQuery, options)
+
+	// Apply content source label filter
+	if options.Label != "" {
+		sqlQuery = sqlQuery.Where(storage.LabelFilter("c.file_path", options.Label))
+	}
+
+	// Apply workspace module filter
+	if options.Module != "" {
+		sqlQuery = sqlQuery.Where(sq.Expr(
+			"c.file_path IN (SELECT file_path FROM file_modules WHERE module_name = ?)", options.Module))
+	}
+
+	// Order by distance (ascending - lower distance = better match)
+	if distances == nil {
+		sqlQuery = sqlQuery.OrderBy("distance").
+			Limit(uint64(options.Limit))
+	}
+
+	// Execute query
+	rows, err := sqlQuery.RunWith(tx).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("vector search query failed: %w", err)
+	}
+	defer rows.Close()
+
+	results, err := scanSearchResults(rows, distances, options, SourceProject)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index-computed distances: restore similarity ordering and apply limit in Go
+	if distances != nil {
+		results = mergeSearchResults(results, nil, options.Limit)
+	}
+	return results, nil
+}
+
+// queryDependencies searches the attached dependency corpus, which always
+// uses the exact sqlite-vec backend, through its committed generation views.
+func (s *sqliteSearcher) queryDependencies(ctx context.Context, tx *sql.Tx, queryBytes []byte, topK int, options *SearchOptions) ([]*SearchResult, error) {
+	sqlQuery := sq.Select(append(searchColumns, "vec.distance")...).
+		From(storage.DependencyCorpusSchema + ".chunks_vec vec").
+		Join(storage.DependencyCorpusTable("chunks") + " c ON vec.chunk_id = c.chunk_id").
+		Join(storage.DependencyCorpusTable("files") + " f ON c.file_path = f.file_path").
 		Where(sq.Expr("vec.embedding MATCH ?", queryBytes)).
 		Where(sq.Expr("k = ?", topK))
+	sqlQuery = applySearchFilters(sqlQuery, options).
+		OrderBy("vec.distance").
+		Limit(uint64(options.Limit))
+
+	rows, err := sqlQuery.RunWith(tx).QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("dependency search query failed: %w", err)
+	}
+	defer rows.Close()
 
+	return scanSearchResults(rows, nil, options, SourceDependency)
+}
+
+// applySearchFilters applies the chunk type and tag filters.
+func applySearchFilters(sqlQuery sq.SelectBuilder, options *SearchOptions) sq.SelectBuilder {
 	// Apply chunk type filter (native SQL)
 	if len(options.ChunkTypes) > 0 {
 		sqlQuery = sqlQuery.Where(sq.Eq{"c.chunk_type": options.ChunkTypes})
@@ -125,19 +264,12 @@ func (s *sqliteSearcher) Query(ctx context.Context, query string, options *Searc
 			}
 		}
 	}
+	return sqlQuery
+}
 
-	// Order by distance (ascending - lower distance = better match)
-	sqlQuery = sqlQuery.OrderBy("vec.distance").
-		Limit(uint64(options.Limit))
-
-	// Execute query
-	rows, err := sqlQuery.RunWith(s.db).QueryContext(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("vector search query failed: %w", err)
-	}
-	defer rows.Close()
-
-	// Scan results and build SearchResult structs
+// scanSearchResults builds SearchResult structs from rows of searchColumns
+// and a distance. distances, when set, replaces the scanned distance.
+func scanSearchResults(rows *sql.Rows, distances map[string]float64, options *SearchOptions, source string) ([]*SearchResult, error) {
 	results := make([]*SearchResult, 0, options.Limit)
 	for rows.Next() {
 		var (
@@ -157,6 +289,9 @@ func (s *sqliteSearcher) Query(ctx context.Context, query string, options *Searc
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan result: %w", err)
 		}
+		if distances != nil {
+			distance = distances[id]
+		}
 
 		// Deserialize embedding
 		embedding, err := storage.DeserializeEmbedding(embBytes)
@@ -201,16 +336,30 @@ func (s *sqliteSearcher) Query(ctx context.Context, query string, options *Searc
 		results = append(results, &SearchResult{
 			Chunk:         chunk,
 			CombinedScore: similarityScore,
+			Source:        source,
+			Label:         resultLabel(source, filePath),
 		})
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating results: %w", err)
 	}
-
 	return results, nil
 }
 
+// mergeSearchResults combines result lists by descending similarity and
+// keeps the best limit.
+func mergeSearchResults(a, b []*SearchResult, limit int) []*SearchResult {
+	results := append(a, b...)
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].CombinedScore > results[j].CombinedScore
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
 // Reload is a no-op for SQLite searcher (data is always current).
 // SQLite-backed searcher doesn't need reload - database is always up-to-date.
 func (s *sqliteSearcher) Reload(ctx context.Context) error {
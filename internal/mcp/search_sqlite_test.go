@@ -8,6 +8,10 @@ package mcp
 // - Query applies chunk_type filters (native SQL)
 // - Query applies tag filters (derived from language and chunk_type)
 // - Query applies min_score threshold (post-filter)
+// - Query applies the workspace module filter (no results before modules were detected)
+// - Module and label filters find matches outside the nearest neighbours of the whole index
+// - Query searches the attached dependency corpus by source, marking each result's source
+// - Query applies the content source label filter and marks each result's label
 // - Query returns results ordered by similarity
 // - Query converts distance to similarity score
 // - Query builds tags from language and chunk_type
@@ -21,6 +25,7 @@ package mcp
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"path/filepath"
 	"testing"
 	"time"
@@ -125,6 +130,16 @@ func createSchema(t *testing.T, db *sql.DB) {
 	`)
 	require.NoError(t, err)
 
+	// Create file_modules table (module filter)
+	_, err = db.Exec(`
+		CREATE TABLE file_modules (
+			file_path TEXT PRIMARY KEY,
+			module_name TEXT NOT NULL,
+			FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+		)
+	`)
+	require.NoError(t, err)
+
 	// Create vector index
 	err = storage.CreateVectorIndex(db, 384)
 	require.NoError(t, err)
@@ -167,6 +182,50 @@ func insertTestChunk(t *testing.T, db *sql.DB, chunk *storage.Chunk) {
 	require.NoError(t, err)
 }
 
+// setupDependencyCorpusTest creates a project database and a dependency
+// corpus with one Go file and chunk each, and opens the project read-only
+// with the corpus attached. The dependency chunk matches the mock query
+// embedding exactly; the project chunk less so.
+func setupDependencyCorpusTest(t *testing.T) *sql.DB {
+	t.Helper()
+	storage.InitVectorExtension()
+
+	dir := t.TempDir()
+	projectPath, corpusPath := filepath.Join(dir, "project.db"), filepath.Join(dir, "dependencies.db")
+	projectEmbedding := makeTestEmbedding(384)
+	projectEmbedding[0] = 1
+
+	now := time.Now().UTC()
+	for _, f := range []struct {
+		dbPath, filePath, content string
+		embedding                 []float32
+	}{
+		{projectPath, "internal/app/client.go", "package app\n\n// Fetch retries requests\nfunc Fetch() {}\n", projectEmbedding},
+		{corpusPath, "go/github.com/pkg/retry@v1.0.0/retry.go", "package retry\n\n// Do retries requests\nfunc Do() {}\n", makeTestEmbedding(384)},
+	} {
+		db, err := sql.Open("sqlite3", f.dbPath)
+		require.NoError(t, err)
+		require.NoError(t, storage.CreateSchema(db))
+		insertFTSTestFileWithContent(t, db, f.filePath, "go", f.content)
+		insertFTSTestChunk(t, db, &storage.Chunk{
+			ID: "chunk-" + f.filePath, FilePath: f.filePath, ChunkType: "definitions", Title: f.filePath,
+			Text: f.content, Embedding: f.embedding, StartLine: 1, EndLine: 4, CreatedAt: now, UpdatedAt: now,
+		})
+		if f.dbPath == corpusPath {
+			require.NoError(t, storage.WithTx(db, func(tx *sql.Tx) error {
+				return storage.PutCorpusPackage(tx, storage.CorpusPackage{Ecosystem: "go", Name: "github.com/pkg/retry",
+					Version: "v1.0.0", PathPrefix: "go/github.com/pkg/retry@v1.0.0/", SourceDir: dir, Fingerprint: "f", FileCount: 1})
+			}))
+		}
+		require.NoError(t, db.Close())
+	}
+
+	db, err := storage.OpenWithDependencyCorpus(projectPath+"?mode=ro", corpusPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
 func nullableInt(n int) interface{} {
 	if n == 0 {
 		return nil
@@ -308,6 +367,147 @@ func TestSQLiteSearcherQuery(t *testing.T) {
 		assert.Equal(t, "definitions", results[0].Chunk.ChunkType)
 	})
 
+	t.Run("uses ivf backend when configured", func(t *testing.T) {
+		t.Parallel()
+		db, provider := setupSQLiteSearcherTest(t)
+		defer db.Close()
+
+		_, err := db.Exec("CREATE TABLE cache_metadata (key TEXT PRIMARY KEY, value TEXT NOT NULL, updated_at TEXT NOT NULL)")
+		require.NoError(t, err)
+
+		insertTestFile(t, db, "file1.go", "go")
+		insertTestFile(t, db, "file2.py", "python")
+		now := time.Now().UTC()
+		for i, fp := range []string{"file1.go", "file2.py"} {
+			insertTestChunk(t, db, &storage.Chunk{
+				ID:        fmt.Sprintf("chunk-%d", i),
+				FilePath:  fp,
+				ChunkType: "definitions",
+				Title:     "Test",
+				Text:      "test content",
+				Embedding: makeTestEmbedding(384),
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+
+		_, err = storage.ConfigureVectorIndex(db, storage.VectorIndexIVF, storage.QuantizationNone)
+		require.NoError(t, err)
+
+		searcher, err := NewSQLiteSearcher(db, provider)
+		require.NoError(t, err)
+		defer searcher.Close()
+
+		results, err := searcher.Query(context.Background(), "test", &SearchOptions{
+			Limit: 10,
+			Tags:  []string{"python"},
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "chunk-1", results[0].Chunk.ID)
+		assert.InDelta(t, 1.0, results[0].CombinedScore, 1e-5)
+	})
+
+	t.Run("applies module filter", func(t *testing.T) {
+		t.Parallel()
+		db, provider := setupSQLiteSearcherTest(t)
+		defer db.Close()
+
+		insertTestFile(t, db, "api/main.go", "go")
+		insertTestFile(t, db, "web/app.ts", "typescript")
+		now := time.Now().UTC()
+		for i, fp := range []string{"api/main.go", "web/app.ts"} {
+			insertTestChunk(t, db, &storage.Chunk{
+				ID:        fmt.Sprintf("chunk-%d", i),
+				FilePath:  fp,
+				ChunkType: "definitions",
+				Title:     "Test",
+				Text:      "test content",
+				Embedding: makeTestEmbedding(384),
+				CreatedAt: now,
+				UpdatedAt: now,
+			})
+		}
+
+		searcher, err := NewSQLiteSearcher(db, provider)
+		require.NoError(t, err)
+		defer searcher.Close()
+
+		ctx := context.Background()
+		results, err := searcher.Query(ctx, "test", &SearchOptions{Limit: 10, Module: "web"})
+		require.NoError(t, err)
+		assert.Empty(t, results)
+
+		require.NoError(t, storage.WithTx(db, func(tx *sql.Tx) error {
+			return storage.AssignFileModules(tx, map[string]string{"api/main.go": "example.com/api", "web/app.ts": "web"})
+		}))
+
+		results, err = searcher.Query(ctx, "test", &SearchOptions{Limit: 10, Module: "web"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "web/app.ts", results[0].Chunk.Metadata["file_path"])
+	})
+
+	t.Run("module and label filters reach beyond the nearest neighbours", func(t *testing.T) {
+		t.Parallel()
+		db, provider := setupSQLiteSearcherTest(t)
+		defer db.Close()
+
+		// 20 exact matches in "api" fill the index's nearest neighbours;
+		// the "web" chunk is further away but the only one in its module
+		now := time.Now().UTC()
+		modules := map[string]string{}
+		for i := 0; i < 20; i++ {
+			fp := fmt.Sprintf("api/handler%d.go", i)
+			insertTestFile(t, db, fp, "go")
+			insertTestChunk(t, db, &storage.Chunk{
+				ID: fmt.Sprintf("chunk-api-%d", i), FilePath: fp, ChunkType: "definitions", Title: "Test",
+				Text: "test content", Embedding: makeTestEmbedding(384), CreatedAt: now, UpdatedAt: now,
+			})
+			modules[fp] = "example.com/api"
+		}
+		webEmbedding := makeTestEmbedding(384)
+		webEmbedding[0] = 1
+		for _, fp := range []string{"web/app.ts", "@wiki/app.md"} {
+			insertTestFile(t, db, fp, "typescript")
+			insertTestChunk(t, db, &storage.Chunk{
+				ID: "chunk-" + fp, FilePath: fp, ChunkType: "definitions", Title: "Test",
+				Text: "test content", Embedding: webEmbedding, CreatedAt: now, UpdatedAt: now,
+			})
+		}
+		modules["web/app.ts"] = "web"
+		require.NoError(t, storage.WithTx(db, func(tx *sql.Tx) error {
+			return storage.AssignFileModules(tx, modules)
+		}))
+
+		searcher, err := NewSQLiteSearcher(db, provider)
+		require.NoError(t, err)
+		defer searcher.Close()
+
+		ctx := context.Background()
+		results, err := searcher.Query(ctx, "test", &SearchOptions{Limit: 5})
+		require.NoError(t, err)
+		require.Len(t, results, 5)
+		for _, r := range results {
+			assert.Contains(t, r.Chunk.Metadata["file_path"], "api/", "unfiltered search returns the closest chunks")
+		}
+
+		results, err = searcher.Query(ctx, "test", &SearchOptions{Limit: 5, Module: "web"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "web/app.ts", results[0].Chunk.Metadata["file_path"])
+
+		results, err = searcher.Query(ctx, "test", &SearchOptions{Limit: 5, Label: "wiki"})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "@wiki/app.md", results[0].Chunk.Metadata["file_path"])
+
+		results, err = searcher.Query(ctx, "test", &SearchOptions{Limit: 5, Module: "example.com/api"})
+		require.NoError(t, err)
+		require.Len(t, results, 5)
+		assert.InDelta(t, 1.0, results[0].CombinedScore, 1e-5, "scoped distances match the index's")
+	})
+
 	t.Run("applies min_score threshold", func(t *testing.T) {
 		t.Parallel()
 		db, provider := setupSQLiteSearcherTest(t)
@@ -346,6 +546,86 @@ func TestSQLiteSearcherQuery(t *testing.T) {
 	})
 }
 
+func TestSQLiteSearcherQuery_DependencySource(t *testing.T) {
+	t.Parallel()
+
+	db := setupDependencyCorpusTest(t)
+	searcher, err := NewSQLiteSearcher(db, newSQLiteMockProvider(384))
+	require.NoError(t, err)
+
+	query := func(options *SearchOptions) []string {
+		results, err := searcher.Query(context.Background(), "retry requests", options)
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, r.Source+" "+r.Chunk.Metadata["file_path"].(string))
+		}
+		return found
+	}
+
+	assert.Equal(t, []string{"project internal/app/client.go"}, query(&SearchOptions{Limit: 10}))
+	assert.Equal(t, []string{"dependency go/github.com/pkg/retry@v1.0.0/retry.go"},
+		query(&SearchOptions{Limit: 10, Source: SourceDependency}))
+
+	// Merged by similarity
+	assert.Equal(t, []string{
+		"dependency go/github.com/pkg/retry@v1.0.0/retry.go",
+		"project internal/app/client.go",
+	}, query(&SearchOptions{Limit: 10, Source: SourceAll}))
+	assert.Len(t, query(&SearchOptions{Limit: 1, Source: SourceAll}), 1)
+
+	// Filters apply to both; the corpus has no workspace modules
+	assert.Empty(t, query(&SearchOptions{Limit: 10, Source: SourceAll, Tags: []string{"python"}}))
+	assert.Empty(t, query(&SearchOptions{Limit: 10, Source: SourceDependency, Module: "example.com/app"}))
+}
+
+func TestSQLiteSearcherQuery_Label(t *testing.T) {
+	t.Parallel()
+
+	storage.InitVectorExtension()
+	db := storage.NewTestDB(t)
+	now := time.Now().UTC()
+	for _, filePath := range []string{"internal/app/retry.go", "@wiki/retries.md"} {
+		content := "Retry requests with backoff"
+		insertFTSTestFileWithContent(t, db, filePath, "markdown", content)
+		insertFTSTestChunk(t, db, &storage.Chunk{
+			ID: "chunk-" + filePath, FilePath: filePath, ChunkType: "documentation", Title: filePath,
+			Text: content, Embedding: makeTestEmbedding(384), StartLine: 1, EndLine: 1, CreatedAt: now, UpdatedAt: now,
+		})
+	}
+
+	searcher, err := NewSQLiteSearcher(db, newSQLiteMockProvider(384))
+	require.NoError(t, err)
+
+	query := func(label string) []string {
+		results, err := searcher.Query(context.Background(), "retry requests", &SearchOptions{Limit: 10, Label: label})
+		require.NoError(t, err)
+		var found []string
+		for _, r := range results {
+			found = append(found, r.Label+" "+r.Chunk.Metadata["file_path"].(string))
+		}
+		return found
+	}
+
+	assert.ElementsMatch(t, []string{"project internal/app/retry.go", "wiki @wiki/retries.md"}, query(""))
+	assert.Equal(t, []string{"wiki @wiki/retries.md"}, query("wiki"))
+	assert.Equal(t, []string{"project internal/app/retry.go"}, query("project"))
+	assert.Empty(t, query("specs"))
+}
+
+func TestSQLiteSearcherQuery_DependencySourceWithoutCorpus(t *testing.T) {
+	t.Parallel()
+
+	db, provider := setupSQLiteSearcherTest(t)
+	defer db.Close()
+
+	searcher, err := NewSQLiteSearcher(db, provider)
+	require.NoError(t, err)
+	results, err := searcher.Query(context.Background(), "anything", &SearchOptions{Limit: 10, Source: SourceDependency})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+}
+
 // Lifecycle Tests
 
 func TestSQLiteSearcherReload(t *testing.T) {
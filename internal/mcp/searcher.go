@@ -39,6 +39,8 @@ type ExactSearcher interface {
 // ExactSearchResult represents a single keyword search result with highlighting.
 type ExactSearchResult struct {
 	Chunk      *ContextChunk `json:"chunk"`
-	Score      float64       `json:"score"`      // Match quality (0-1)
-	Highlights []string      `json:"highlights"` // Matching snippets with <mark> tags
+	Score      float64       `json:"score"`           // Match quality (0-1)
+	Highlights []string      `json:"highlights"`      // Matching snippets with <mark> tags
+	Source     string        `json:"source"`          // SourceProject or SourceDependency
+	Label      string        `json:"label,omitempty"` // Content source label or "project" (SourceProject only)
 }
@@ -92,11 +92,18 @@ func NewMCPServer(ctx context.Context, config *MCPServerConfig, db *sql.DB, prov
 	AddCortexFilesTool(mcpServer, db)
 	log.Printf("Registered cortex_files tool")
 
-	// Create pattern searcher
-	patternSearcher := pattern.NewAstGrepProvider()
+	// Create pattern searcher (matches are annotated with symbols from the index)
+	patternSearcher := pattern.NewAstGrepProvider().WithIndex(db)
 
 	// Register cortex_pattern tool
 	AddCortexPatternTool(mcpServer, patternSearcher, config.ProjectPath)
+	AddCortexPatternApplyTool(mcpServer, config.ProjectPath)
+
+	// Register cortex_lint tool (project rules in .cortex/rules, same ast-grep provider)
+	AddCortexLintTool(mcpServer, patternSearcher, db, config.ProjectPath)
+
+	// Register cortex_query tool (in-process tree-sitter, no binary download)
+	AddCortexQueryTool(mcpServer, pattern.NewTreeSitterQuerier(db))
 
 	// NOTE: Hot reload is no longer needed for SQLite-backed searchers
 	// Database is always current (no in-memory cache to reload)
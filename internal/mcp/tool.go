@@ -32,7 +32,14 @@ func AddCortexSearchTool(s *server.MCPServer, searcher ContextSearcher) {
 		mcp.WithArray("tags",
 			mcp.Description("Filter results by tags - must have ALL specified tags (AND logic). Examples: ['go', 'code'], ['documentation', 'architecture']")),
 		mcp.WithArray("chunk_types",
-			mcp.Description("Filter by chunk types. Options: 'documentation' (README, guides, docs), 'symbols' (code overview), 'definitions' (function signatures), 'data' (constants, configs). Leave empty to search all types.")),
+			mcp.Description("Filter by chunk types. Options: 'documentation' (README, guides, docs), 'symbols' (code overview), 'definitions' (function signatures), 'data' (constants, configs), 'docstring' (doc comments and docstrings with their signatures). Leave empty to search all types.")),
+		mcp.WithString("module",
+			mcp.Description("Filter by workspace module: a Go module path, npm package name, Python project or Rust crate name (see cortex_files table workspace_modules). Leave empty to search all modules.")),
+		mcp.WithString("source",
+			mcp.Enum(SourceProject, SourceDependency, SourceAll),
+			mcp.Description("What to search: 'project' (default) for the project's files, 'dependency' for the sources of imported third-party packages (requires indexing.dependency_corpus), or 'all' for both. Each result's source field says where it came from.")),
+		mcp.WithString("label",
+			mcp.Description("Filter by content source: the label of a paths.sources entry (e.g., 'wiki'; its files are stored as '@wiki/...') or 'project' for the repository's own files. Each project result's label field says where it came from. Leave empty to search everything.")),
 		mcp.WithBoolean("include_stats",
 			mcp.Description("Include reload metrics in response (default: false). Shows reload health, chunk count, and error statistics.")),
 		mcp.WithReadOnlyHintAnnotation(true),
@@ -58,6 +65,13 @@ func createCortexSearchHandler(searcher ContextSearcher) func(context.Context, m
 			return mcp.NewToolResultError("query is required"), nil
 		}
 
+		if !validSource(req.Source) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid source %q: must be project, dependency or all", req.Source)), nil
+		}
+		if !validLabel(req.Label) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid label %q: must be a content source label or project", req.Label)), nil
+		}
+
 		// Apply defaults
 		if req.Limit == 0 {
 			req.Limit = 15
@@ -76,9 +90,13 @@ func createCortexSearchHandler(searcher ContextSearcher) func(context.Context, m
 			Limit:      req.Limit,
 			Tags:       req.Tags,
 			ChunkTypes: req.ChunkTypes,
+			Module:     req.Module,
+			Source:     req.Source,
+			Label:      req.Label,
 		}
 
-		// Execute search
+		// Execute search, capturing the index generation it reads
+		ctx, served := withServedGeneration(ctx)
 		results, err := searcher.Query(ctx, req.Query, options)
 		if err != nil {
 			return nil, fmt.Errorf("search failed: %w", err)
@@ -89,8 +107,9 @@ func createCortexSearchHandler(searcher ContextSearcher) func(context.Context, m
 			Results: results,
 			Total:   len(results),
 			Metadata: SearchResponseMetadata{
-				TookMs: int(time.Since(startTime).Milliseconds()),
-				Source: "search",
+				TookMs:     int(time.Since(startTime).Milliseconds()),
+				Source:     "search",
+				Generation: served.id,
 			},
 		}
 
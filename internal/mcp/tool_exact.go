@@ -22,13 +22,16 @@ Supports:
 - Phrase search: Use double quotes for exact phrases: "sync.RWMutex" or "error handling"
 - Boolean operators: AND, OR, NOT
 - Prefix wildcards: handler* (matches handler, handlers, handleRequest)
-- Filters: language and file_path (applied via SQL, not FTS query)
+- Filters: language, file_path and label (applied via SQL, not FTS query)
+- Configuration keys: the flattened key paths of YAML, JSON, TOML and .env files
+  ("spec.template.spec.containers[0].image") are matched too, one config_key result per key
 
 Examples:
 - "sync.RWMutex" - Find exact identifier (use quotes for dotted names)
 - handler AND http - Boolean AND
 - authentication NOT test - Exclude test files
-- handle* - Prefix matching`),
+- handle* - Prefix matching
+- "readinessProbe.timeoutSeconds" - Find where a config key is set`),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("FTS5 query string. Use double quotes for phrases: \"sync.RWMutex\"")),
@@ -38,6 +41,11 @@ Examples:
 			mcp.Description("Filter by language (e.g., 'go', 'typescript', 'python')")),
 		mcp.WithString("file_path",
 			mcp.Description("Filter by file path using SQL LIKE syntax (e.g., 'internal/%', '%_test.go')")),
+		mcp.WithString("source",
+			mcp.Enum(SourceProject, SourceDependency, SourceAll),
+			mcp.Description("What to search: 'project' (default), 'dependency' for the sources of imported third-party packages (paths like 'go/github.com/pkg/errors@v0.9.1/errors.go'), or 'all' for both")),
+		mcp.WithString("label",
+			mcp.Description("Filter by content source: the label of a paths.sources entry (files stored as '@<label>/...') or 'project' for the repository's own files")),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 	)
@@ -61,6 +69,13 @@ func createExactSearchHandler(searcher ExactSearcher) func(context.Context, mcp.
 			return mcp.NewToolResultError("query is required"), nil
 		}
 
+		if !validSource(req.Source) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid source %q: must be project, dependency or all", req.Source)), nil
+		}
+		if !validLabel(req.Label) {
+			return mcp.NewToolResultError(fmt.Sprintf("invalid label %q: must be a content source label or project", req.Label)), nil
+		}
+
 		// Apply defaults
 		if req.Limit == 0 {
 			req.Limit = 15
@@ -79,9 +94,12 @@ func createExactSearchHandler(searcher ExactSearcher) func(context.Context, mcp.
 			Limit:    req.Limit,
 			Language: req.Language,
 			FilePath: req.FilePath,
+			Source:   req.Source,
+			Label:    req.Label,
 		}
 
-		// Execute search
+		// Execute search, capturing the index generation it reads
+		ctx, served := withServedGeneration(ctx)
 		results, err := searcher.Search(ctx, req.Query, options)
 		if err != nil {
 			return nil, fmt.Errorf("search failed: %w", err)
@@ -94,8 +112,9 @@ func createExactSearchHandler(searcher ExactSearcher) func(context.Context, mcp.
 			TotalFound:    len(results),
 			TotalReturned: len(results),
 			Metadata: ExactResponseMetadata{
-				TookMs: int(time.Since(startTime).Milliseconds()),
-				Source: "exact",
+				TookMs:     int(time.Since(startTime).Milliseconds()),
+				Source:     "exact",
+				Generation: served.id,
 			},
 		}
 
@@ -110,6 +129,8 @@ type CortexExactRequest struct {
 	Limit    int    `json:"limit,omitempty" jsonschema:"minimum=1,maximum=100,default=15"`
 	Language string `json:"language,omitempty" jsonschema:"description=Filter by language (e.g. 'go' 'typescript' 'python')"`
 	FilePath string `json:"file_path,omitempty" jsonschema:"description=Filter by file path using SQL LIKE syntax (e.g. 'internal/%' '%_test.go')"`
+	Source   string `json:"source,omitempty" jsonschema:"enum=project,enum=dependency,enum=all,default=project,description=Search the project, the dependency corpus or both"`
+	Label    string `json:"label,omitempty" jsonschema:"description=Filter by content source label or 'project'"`
 }
 
 // CortexExactResponse represents the JSON response schema for the cortex_exact MCP tool.
@@ -123,6 +144,7 @@ type CortexExactResponse struct {
 
 // ExactResponseMetadata contains timing and source information.
 type ExactResponseMetadata struct {
-	TookMs int    `json:"took_ms"`
-	Source string `json:"source"` // "exact"
+	TookMs     int    `json:"took_ms"`
+	Source     string `json:"source"`     // "exact"
+	Generation int64  `json:"generation"` // Index generation the results were read from
 }
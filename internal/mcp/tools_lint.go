@@ -0,0 +1,114 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcputils "github.com/mvp-joe/project-cortex/internal/mcp-utils"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// AddCortexLintTool registers the cortex_lint tool with an MCP server.
+// This function is composable - it can be combined with other tool registrations.
+//
+// The cortex_lint tool runs the project's ast-grep rules (.cortex/rules/*.yml) over a
+// set of files, or returns the violations `cortex lint` last stored for the current
+// branch. Stored results are read from the branch database, which the MCP server
+// opens read-only, so live runs are not written back.
+func AddCortexLintTool(s *server.MCPServer, linter pattern.Linter, db *sql.DB, projectRoot string) {
+	tool := mcp.NewTool(
+		"cortex_lint",
+		mcp.WithDescription("Check code against the project's lint rules (ast-grep YAML rules in .cortex/rules/). Returns rule violations with severity, message and suggested fix. Set stored=true to list the violations recorded by the last `cortex lint` run on this branch instead of scanning (e.g., 'what rule violations exist in internal/auth')."),
+		mcp.WithArray("file_paths",
+			mcp.Description("Optional files, directories or globs to check (e.g., ['internal/auth', 'cmd/**/*.go']). Default: whole project")),
+		mcp.WithString("rule_id",
+			mcp.Description("Optional: only report violations of this rule")),
+		mcp.WithString("severity",
+			mcp.Description("Optional: only report this severity (error, warning, info, hint)")),
+		mcp.WithBoolean("stored",
+			mcp.Description("Return stored results from the last `cortex lint` run instead of scanning (default: false)")),
+		mcp.WithString("label",
+			mcp.Description("Only check files of this content source (a paths.sources label; its files are reported as '@<label>/...'), or 'project' for the repository's own files. Default: the project and every content source")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+
+	handler := createCortexLintHandler(linter, db, projectRoot)
+	s.AddTool(tool, handler)
+}
+
+// lintToolRequest represents a cortex_lint request
+type lintToolRequest struct {
+	FilePaths []string `json:"file_paths"`
+	RuleID    string   `json:"rule_id"`
+	Severity  string   `json:"severity"`
+	Stored    bool     `json:"stored"`
+	Label     string   `json:"label"`
+}
+
+// createCortexLintHandler creates the handler function for cortex_lint tool.
+func createCortexLintHandler(linter pattern.Linter, db *sql.DB, projectRoot string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := request.GetRawArguments().(map[string]interface{}); !ok {
+			return mcp.NewToolResultError("invalid arguments format"), nil
+		}
+
+		var args lintToolRequest
+		if err := mcputils.CoerceBindArguments(request, &args); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+		}
+		req := &pattern.LintRequest{
+			FilePaths: args.FilePaths,
+			RuleID:    args.RuleID,
+			Severity:  args.Severity,
+			Label:     args.Label,
+		}
+
+		if args.Stored {
+			if err := pattern.ValidateLintRequest(req); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid request: %v", err)), nil
+			}
+			result, err := storedLintResult(db, req)
+			if err != nil {
+				return nil, err
+			}
+			return marshalToolResponse(result)
+		}
+
+		result, err := linter.Lint(ctx, req, projectRoot)
+		if err != nil {
+			if isUserError(err) {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return nil, err
+		}
+
+		return marshalToolResponse(result)
+	}
+}
+
+// storedLintResult returns the branch's stored violations matching req.
+func storedLintResult(db *sql.DB, req *pattern.LintRequest) (*pattern.LintResult, error) {
+	stored, err := storage.QueryLintViolations(db, storage.LintViolationFilter{
+		RuleID:   req.RuleID,
+		Severity: req.Severity,
+	})
+	if err != nil {
+		return nil, err
+	}
+	lastRun, err := storage.LastLintRun(db)
+	if err != nil {
+		return nil, err
+	}
+
+	violations := pattern.FilterLintViolations(pattern.FromStoredViolations(stored), req)
+	return &pattern.LintResult{
+		Violations: violations,
+		Summary:    pattern.SummarizeLintViolations(violations),
+		LastRun:    lastRun,
+	}, nil
+}
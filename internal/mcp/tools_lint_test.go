@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockLinter implements pattern.Linter for testing
+type mockLinter struct {
+	lintFunc func(ctx context.Context, req *pattern.LintRequest, projectRoot string) (*pattern.LintResult, error)
+}
+
+func (m *mockLinter) Lint(ctx context.Context, req *pattern.LintRequest, projectRoot string) (*pattern.LintResult, error) {
+	return m.lintFunc(ctx, req, projectRoot)
+}
+
+func callLintHandler(t *testing.T, linter pattern.Linter, db *sql.DB, args map[string]interface{}) (*mcp.CallToolResult, pattern.LintResult) {
+	t.Helper()
+
+	handler := createCortexLintHandler(linter, db, "/tmp/test-project")
+	result, err := handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{Arguments: args},
+	})
+	require.NoError(t, err, "should not return system error")
+	require.NotNil(t, result)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok, "should be text content")
+
+	var response pattern.LintResult
+	if !result.IsError {
+		require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+	}
+	return result, response
+}
+
+// TestCortexLintHandler_Run tests that a live run passes filters to the linter
+func TestCortexLintHandler_Run(t *testing.T) {
+	t.Parallel()
+
+	var got *pattern.LintRequest
+	linter := &mockLinter{lintFunc: func(ctx context.Context, req *pattern.LintRequest, projectRoot string) (*pattern.LintResult, error) {
+		got = req
+		assert.Equal(t, "/tmp/test-project", projectRoot)
+		violations := []pattern.LintViolation{
+			{RuleID: "no-println", Severity: "warning", FilePath: "internal/a.go", StartLine: 3},
+		}
+		return &pattern.LintResult{Violations: violations, Summary: pattern.SummarizeLintViolations(violations)}, nil
+	}}
+
+	result, response := callLintHandler(t, linter, nil, map[string]interface{}{
+		"file_paths": []interface{}{"internal"},
+		"rule_id":    "no-println",
+	})
+
+	assert.False(t, result.IsError)
+	require.NotNil(t, got)
+	assert.Equal(t, []string{"internal"}, got.FilePaths)
+	assert.Equal(t, "no-println", got.RuleID)
+	require.Len(t, response.Violations, 1)
+	assert.Equal(t, 1, response.Summary.Warnings)
+}
+
+// TestCortexLintHandler_UserError tests that rule/path errors are shown to the LLM
+func TestCortexLintHandler_UserError(t *testing.T) {
+	t.Parallel()
+
+	linter := &mockLinter{lintFunc: func(ctx context.Context, req *pattern.LintRequest, projectRoot string) (*pattern.LintResult, error) {
+		return nil, errors.New("invalid rules directory: .cortex/rules does not exist")
+	}}
+
+	result, _ := callLintHandler(t, linter, nil, map[string]interface{}{})
+	assert.True(t, result.IsError)
+}
+
+// TestCortexLintHandler_Stored tests reading violations stored by `cortex lint`
+func TestCortexLintHandler_Stored(t *testing.T) {
+	t.Parallel()
+
+	db := storage.NewTestDBFile(t)
+	require.NoError(t, storage.ReplaceLintViolations(db, []storage.LintViolation{
+		{RuleID: "exec-ctx", Severity: "error", FilePath: "internal/auth/login.go", StartLine: 12},
+		{RuleID: "no-println", Severity: "warning", FilePath: "internal/auth/login.go", StartLine: 30},
+		{RuleID: "no-println", Severity: "warning", FilePath: "cmd/main.go", StartLine: 4},
+	}, nil, nil))
+
+	linter := &mockLinter{lintFunc: func(ctx context.Context, req *pattern.LintRequest, projectRoot string) (*pattern.LintResult, error) {
+		t.Fatal("stored mode should not run the linter")
+		return nil, nil
+	}}
+
+	result, response := callLintHandler(t, linter, db, map[string]interface{}{
+		"file_paths": []interface{}{"internal/auth"},
+		"stored":     true,
+	})
+
+	assert.False(t, result.IsError)
+	require.Len(t, response.Violations, 2)
+	assert.Equal(t, "exec-ctx", response.Violations[0].RuleID)
+	assert.Equal(t, 1, response.Summary.Errors)
+	assert.Equal(t, 1, response.Summary.Warnings)
+	assert.NotEmpty(t, response.LastRun)
+
+	result, _ = callLintHandler(t, linter, db, map[string]interface{}{
+		"severity": "fatal",
+		"stored":   true,
+	})
+	assert.True(t, result.IsError)
+}
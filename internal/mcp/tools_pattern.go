@@ -20,7 +20,7 @@ import (
 func AddCortexPatternTool(s *server.MCPServer, searcher pattern.PatternSearcher, projectRoot string) {
 	tool := mcp.NewTool(
 		"cortex_pattern",
-		mcp.WithDescription("Search code using structural AST patterns. Use for finding anti-patterns, code smells, language-specific idioms, and complex structural patterns that text search cannot handle."),
+		mcp.WithDescription("Search code using structural AST patterns. Use for finding anti-patterns, code smells, language-specific idioms, and complex structural patterns that text search cannot handle. Matches in indexed files include the enclosing function_id/type_id (usable as cortex_graph targets) and file metadata (language, module_path, is_test)."),
 		mcp.WithString("pattern",
 			mcp.Required(),
 			mcp.Description("AST pattern with metavariables (e.g., 'defer $FUNC()' or 'useState($INIT)')")),
@@ -35,6 +35,16 @@ func AddCortexPatternTool(s *server.MCPServer, searcher pattern.PatternSearcher,
 			mcp.Description("Matching algorithm: cst, smart (default), ast, relaxed, signature")),
 		mcp.WithNumber("limit",
 			mcp.Description("Maximum results to return (1-100, default: 50)")),
+		mcp.WithString("rewrite",
+			mcp.Description("Optional rewrite template using the pattern's metavariables (e.g., 'defer $FUNC(ctx)'). Returns a unified diff per file plus a combined 'patch'; files are NOT modified. Apply an accepted patch with cortex_pattern_apply.")),
+		mcp.WithBoolean("exclude_tests",
+			mcp.Description("Drop matches in test files (default: false)")),
+		mcp.WithBoolean("exported_only",
+			mcp.Description("Only return matches inside exported functions/methods (default: false)")),
+		mcp.WithString("module_path",
+			mcp.Description("Only return matches in this module/package directory or its subpackages (e.g., 'internal/auth')")),
+		mcp.WithString("label",
+			mcp.Description("Only search files of this content source (a paths.sources label; its files are reported as '@<label>/...' and file_paths filters for them start with that prefix), or 'project' for the repository's own files. Default: the project and every content source. Rewrites only apply to project files.")),
 		mcp.WithReadOnlyHintAnnotation(true),
 		mcp.WithDestructiveHintAnnotation(false),
 	)
@@ -81,6 +91,59 @@ func createCortexPatternHandler(searcher pattern.PatternSearcher, projectRoot st
 	}
 }
 
+// AddCortexPatternApplyTool registers the cortex_pattern_apply tool with an MCP server.
+//
+// The tool applies a unified diff produced by cortex_pattern's rewrite mode. It is the
+// only path through which pattern rewrites modify files: every path is validated against
+// the project root, every hunk must match the current file, and the patch is applied
+// all-or-nothing.
+func AddCortexPatternApplyTool(s *server.MCPServer, projectRoot string) {
+	tool := mcp.NewTool(
+		"cortex_pattern_apply",
+		mcp.WithDescription("Apply a unified diff returned by cortex_pattern (rewrite mode) to the project files. All files are updated or none are; a diff that no longer matches the files is rejected."),
+		mcp.WithString("patch",
+			mcp.Required(),
+			mcp.Description("Unified diff to apply (the 'patch' field of a cortex_pattern rewrite response, or a subset of its file diffs)")),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+	)
+
+	handler := createCortexPatternApplyHandler(projectRoot)
+	s.AddTool(tool, handler)
+}
+
+// patchApplyRequest represents a cortex_pattern_apply request
+type patchApplyRequest struct {
+	Patch string `json:"patch"`
+}
+
+// createCortexPatternApplyHandler creates the handler function for cortex_pattern_apply tool.
+func createCortexPatternApplyHandler(projectRoot string) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := request.GetRawArguments().(map[string]interface{}); !ok {
+			return mcp.NewToolResultError("invalid arguments format"), nil
+		}
+
+		var req patchApplyRequest
+		if err := mcputils.CoerceBindArguments(request, &req); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+		}
+		if req.Patch == "" {
+			return mcp.NewToolResultError("patch parameter is required"), nil
+		}
+
+		result, err := pattern.ApplyPatch(projectRoot, req.Patch)
+		if err != nil {
+			if isUserError(err) {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return nil, err
+		}
+
+		return marshalToolResponse(result)
+	}
+}
+
 // isUserError determines if an error should be shown to the LLM (user error)
 // vs treated as an internal system error.
 //
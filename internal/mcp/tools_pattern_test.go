@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/mark3labs/mcp-go/mcp"
@@ -281,6 +283,9 @@ func TestCortexPatternHandler_WithOptionalParameters(t *testing.T) {
 				"context_lines": float64(contextLines),
 				"strictness":    "relaxed",
 				"limit":         float64(limit),
+				"exclude_tests": true,
+				"exported_only": "true",
+				"module_path":   "src/components",
 			},
 		},
 	}
@@ -301,6 +306,9 @@ func TestCortexPatternHandler_WithOptionalParameters(t *testing.T) {
 	assert.Equal(t, "relaxed", capturedReq.Strictness)
 	require.NotNil(t, capturedReq.Limit)
 	assert.Equal(t, limit, *capturedReq.Limit)
+	assert.True(t, capturedReq.ExcludeTests)
+	assert.True(t, capturedReq.ExportedOnly)
+	assert.Equal(t, "src/components", capturedReq.ModulePath)
 }
 
 // TestCortexPatternHandler_JSONMarshaling tests response JSON marshaling
@@ -433,3 +441,47 @@ func TestIsUserError(t *testing.T) {
 		})
 	}
 }
+
+// TestCortexPatternApplyHandler tests applying a rewrite patch and rejecting a stale one
+func TestCortexPatternApplyHandler(t *testing.T) {
+	t.Parallel()
+
+	projectRoot := t.TempDir()
+	target := filepath.Join(projectRoot, "main.go")
+	require.NoError(t, os.WriteFile(target, []byte("package main\n\nfunc main() {\n\tdefer conn.Close()\n}\n"), 0644))
+
+	patch := "--- a/main.go\n+++ b/main.go\n@@ -3,3 +3,3 @@\n func main() {\n-\tdefer conn.Close()\n+\tdefer closeQuietly(conn)\n }\n"
+	handler := createCortexPatternApplyHandler(projectRoot)
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{"patch": patch},
+		},
+	}
+
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	var applied pattern.ApplyResult
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &applied))
+	assert.Equal(t, []string{"main.go"}, applied.FilesChanged)
+
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "defer closeQuietly(conn)")
+
+	// Same patch again no longer matches: user error, file unchanged
+	result, err = handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+
+	again, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, content, again)
+}
@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	mcputils "github.com/mvp-joe/project-cortex/internal/mcp-utils"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+)
+
+// AddCortexQueryTool registers the cortex_query tool with an MCP server.
+// This function is composable - it can be combined with other tool registrations.
+//
+// The cortex_query tool runs tree-sitter S-expression queries in-process over the
+// indexed files. It complements cortex_pattern for environments where the ast-grep
+// binary cannot be downloaded, and exposes tree-sitter captures and predicates directly.
+func AddCortexQueryTool(s *server.MCPServer, searcher pattern.QuerySearcher) {
+	tool := mcp.NewTool(
+		"cortex_query",
+		mcp.WithDescription("Search indexed code with tree-sitter S-expression queries (captures and #eq?/#match?/#any-of? predicates). Runs in-process with no external binary. Captures are returned in metavars keyed by capture name; captures starting with '_' are used for predicates only."),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Tree-sitter query (e.g., '(function_definition name: (identifier) @name (#match? @name \"^test_\"))')")),
+		mcp.WithString("language",
+			mcp.Required(),
+			mcp.Description("Target language: typescript, tsx, javascript, jsx, python, rust, c, cpp, java, php, ruby")),
+		mcp.WithArray("file_paths",
+			mcp.Description("Optional file/glob filters (e.g., ['src/**/*.py'])")),
+		mcp.WithNumber("context_lines",
+			mcp.Description("Lines of context before/after match (0-10, default: 3)")),
+		mcp.WithNumber("limit",
+			mcp.Description("Maximum results to return (1-100, default: 50)")),
+		mcp.WithString("label",
+			mcp.Description("Only query files of this content source (a paths.sources label; files are stored as '@<label>/...'), or 'project' for the repository's own files")),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+	)
+
+	handler := createCortexQueryHandler(searcher)
+	s.AddTool(tool, handler)
+}
+
+// createCortexQueryHandler creates the handler function for cortex_query tool.
+func createCortexQueryHandler(searcher pattern.QuerySearcher) func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := request.GetRawArguments().(map[string]interface{}); !ok {
+			return mcp.NewToolResultError("invalid arguments format"), nil
+		}
+
+		var req pattern.QueryRequest
+		if err := mcputils.CoerceBindArguments(request, &req); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Invalid arguments: %v", err)), nil
+		}
+
+		if req.Query == "" {
+			return mcp.NewToolResultError("query parameter is required"), nil
+		}
+		if req.Language == "" {
+			return mcp.NewToolResultError("language parameter is required"), nil
+		}
+
+		result, err := searcher.Query(ctx, &req)
+		if err != nil {
+			if isUserError(err) {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			return nil, err
+		}
+
+		return marshalToolResponse(result)
+	}
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mvp-joe/project-cortex/internal/pattern"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockQuerySearcher implements pattern.QuerySearcher for testing
+type mockQuerySearcher struct {
+	queryFunc func(ctx context.Context, req *pattern.QueryRequest) (*pattern.QueryResponse, error)
+}
+
+func (m *mockQuerySearcher) Query(ctx context.Context, req *pattern.QueryRequest) (*pattern.QueryResponse, error) {
+	return m.queryFunc(ctx, req)
+}
+
+// TestCortexQueryHandler_ValidRequest runs a real tree-sitter query through the handler
+func TestCortexQueryHandler_ValidRequest(t *testing.T) {
+	t.Parallel()
+
+	db := storage.NewTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	content := "def test_one():\n    pass\n\ndef helper():\n    pass\n"
+	now := time.Now()
+	err := storage.NewFileWriter(db).WriteFile(&storage.FileStats{
+		FilePath:     "tests/test_one.py",
+		Language:     "python",
+		FileHash:     "hash",
+		LastModified: now,
+		IndexedAt:    now,
+	}, &content)
+	require.NoError(t, err)
+
+	handler := createCortexQueryHandler(pattern.NewTreeSitterQuerier(db))
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"query":    `(function_definition name: (identifier) @name (#match? @name "^test_"))`,
+				"language": "python",
+				"limit":    float64(10),
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.False(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+
+	var response pattern.QueryResponse
+	require.NoError(t, json.Unmarshal([]byte(textContent.Text), &response))
+
+	require.Len(t, response.Matches, 1)
+	assert.Equal(t, "tests/test_one.py", response.Matches[0].FilePath)
+	assert.Equal(t, 1, response.Matches[0].StartLine)
+	assert.Equal(t, "test_one", response.Matches[0].Metavars["name"])
+	assert.Equal(t, "python", response.Metadata.Language)
+}
+
+// TestCortexQueryHandler_MissingQuery tests validation of required query field
+func TestCortexQueryHandler_MissingQuery(t *testing.T) {
+	t.Parallel()
+
+	handler := createCortexQueryHandler(&mockQuerySearcher{})
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"language": "python",
+			},
+		},
+	}
+
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.True(t, result.IsError)
+
+	textContent, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, textContent.Text, "query parameter is required")
+}
+
+// TestCortexQueryHandler_Errors tests user errors become tool errors and system errors propagate
+func TestCortexQueryHandler_Errors(t *testing.T) {
+	t.Parallel()
+
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Arguments: map[string]interface{}{
+				"query":    "(identifier) @x",
+				"language": "python",
+			},
+		},
+	}
+
+	t.Run("user error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createCortexQueryHandler(&mockQuerySearcher{
+			queryFunc: func(ctx context.Context, req *pattern.QueryRequest) (*pattern.QueryResponse, error) {
+				return nil, errors.New("invalid query: Invalid syntax at row 0, column 3")
+			},
+		})
+
+		result, err := handler(context.Background(), request)
+		require.NoError(t, err)
+		require.NotNil(t, result)
+		assert.True(t, result.IsError)
+	})
+
+	t.Run("system error", func(t *testing.T) {
+		t.Parallel()
+
+		handler := createCortexQueryHandler(&mockQuerySearcher{
+			queryFunc: func(ctx context.Context, req *pattern.QueryRequest) (*pattern.QueryResponse, error) {
+				return nil, errors.New("failed to load indexed files: disk I/O error")
+			},
+		})
+
+		result, err := handler(context.Background(), request)
+		require.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
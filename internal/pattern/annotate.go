@@ -0,0 +1,160 @@
+package pattern
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// indexSymbol is a function or type span read from the index.
+type indexSymbol struct {
+	id         string
+	startLine  int
+	endLine    int
+	isExported bool
+}
+
+// indexedFile holds the index data needed to annotate matches in one file.
+type indexedFile struct {
+	info      MatchFile
+	functions []indexSymbol
+	types     []indexSymbol
+}
+
+// HasIndexFilters reports whether req uses filters that need the index
+// (exclude_tests, exported_only, module_path).
+func HasIndexFilters(req *PatternRequest) bool {
+	return req.ExcludeTests || req.ExportedOnly || req.ModulePath != ""
+}
+
+// annotateMatches fills in each match's enclosing function/type and file
+// metadata from the index, then applies req's index filters.
+//
+// keep[i] reports whether matches[i] passed the filters, so callers can
+// filter data aligned with matches (e.g. the raw ast-grep matches used for
+// rewrite diffs). Files that aren't indexed are left unannotated and, when
+// any index filter is set, filtered out.
+func annotateMatches(ctx context.Context, db sq.BaseRunner, matches []PatternMatch, req *PatternRequest) (keep []bool, err error) {
+	files := make(map[string]*indexedFile)
+	keep = make([]bool, len(matches))
+
+	for i := range matches {
+		m := &matches[i]
+
+		file, ok := files[m.FilePath]
+		if !ok {
+			file, err = loadIndexedFile(ctx, db, m.FilePath)
+			if err != nil {
+				return nil, err
+			}
+			files[m.FilePath] = file
+		}
+		if file == nil {
+			keep[i] = !HasIndexFilters(req)
+			continue
+		}
+
+		info := file.info
+		m.File = &info
+		fn := enclosingSymbol(file.functions, m.StartLine, m.EndLine)
+		if fn != nil {
+			m.FunctionID = fn.id
+		}
+		if typ := enclosingSymbol(file.types, m.StartLine, m.EndLine); typ != nil {
+			m.TypeID = typ.id
+		}
+
+		keep[i] = (!req.ExcludeTests || !info.IsTest) &&
+			(!req.ExportedOnly || (fn != nil && fn.isExported)) &&
+			(req.ModulePath == "" || inModule(info.ModulePath, req.ModulePath))
+	}
+
+	return keep, nil
+}
+
+// loadIndexedFile reads a file's metadata, functions and types.
+// Returns nil (and no error) if the file isn't in the index.
+func loadIndexedFile(ctx context.Context, db sq.BaseRunner, filePath string) (*indexedFile, error) {
+	file := &indexedFile{}
+	err := sq.Select("language", "module_path", "is_test").
+		From("files").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(db).
+		QueryRowContext(ctx).
+		Scan(&file.info.Language, &file.info.ModulePath, &file.info.IsTest)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file metadata for %s: %w", filePath, err)
+	}
+
+	if file.functions, err = loadSymbols(ctx, db, "functions", "function_id", filePath); err != nil {
+		return nil, err
+	}
+	if file.types, err = loadSymbols(ctx, db, "types", "type_id", filePath); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// loadSymbols reads the spans of a file's functions or types.
+func loadSymbols(ctx context.Context, db sq.BaseRunner, table, idColumn, filePath string) ([]indexSymbol, error) {
+	rows, err := sq.Select(idColumn, "start_line", "end_line", "is_exported").
+		From(table).
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s for %s: %w", table, filePath, err)
+	}
+	defer rows.Close()
+
+	var symbols []indexSymbol
+	for rows.Next() {
+		var s indexSymbol
+		if err := rows.Scan(&s.id, &s.startLine, &s.endLine, &s.isExported); err != nil {
+			return nil, fmt.Errorf("failed to scan %s row: %w", table, err)
+		}
+		symbols = append(symbols, s)
+	}
+	return symbols, rows.Err()
+}
+
+// enclosingSymbol returns the innermost symbol whose span contains lines
+// startLine-endLine, or nil if none does.
+func enclosingSymbol(symbols []indexSymbol, startLine, endLine int) *indexSymbol {
+	var best *indexSymbol
+	for i := range symbols {
+		s := &symbols[i]
+		if s.startLine > startLine || s.endLine < endLine {
+			continue
+		}
+		if best == nil || s.endLine-s.startLine < best.endLine-best.startLine {
+			best = s
+		}
+	}
+	return best
+}
+
+// inModule reports whether modulePath is module or one of its subpackages.
+func inModule(modulePath, module string) bool {
+	module = strings.TrimSuffix(module, "/")
+	return modulePath == module || strings.HasPrefix(modulePath, module+"/")
+}
+
+// filterKept returns the matches (and their aligned raw ast-grep matches) for which keep is true.
+func filterKept(matches []PatternMatch, raw []AstGrepMatch, keep []bool) ([]PatternMatch, []AstGrepMatch) {
+	keptMatches := make([]PatternMatch, 0, len(matches))
+	keptRaw := make([]AstGrepMatch, 0, len(raw))
+	for i, ok := range keep {
+		if ok {
+			keptMatches = append(keptMatches, matches[i])
+			keptRaw = append(keptRaw, raw[i])
+		}
+	}
+	return keptMatches, keptRaw
+}
@@ -0,0 +1,148 @@
+package pattern
+
+// Test Plan for Index Annotations:
+// - annotateMatches sets the innermost enclosing function/type and file metadata
+// - Matches in unindexed files are left unannotated (and kept without filters)
+// - exclude_tests, exported_only and module_path drop the right matches
+// - filterKept keeps raw ast-grep matches aligned with the kept matches
+// - ExecutePattern rejects index filters when the provider has no index
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAnnotateTestDB indexes two files: a source file with an exported method
+// inside a type and an unexported function, and a test file.
+func newAnnotateTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db := storage.NewTestDB(t)
+	db.SetMaxOpenConns(1)
+
+	now := time.Now()
+	fileWriter := storage.NewFileWriter(db)
+	for _, f := range []*storage.FileStats{
+		{FilePath: "internal/auth/login.go", Language: "go", ModulePath: "internal/auth", FileHash: "a", LastModified: now, IndexedAt: now},
+		{FilePath: "internal/auth/login_test.go", Language: "go", ModulePath: "internal/auth", IsTest: true, FileHash: "b", LastModified: now, IndexedAt: now},
+	} {
+		require.NoError(t, fileWriter.WriteFile(f, nil))
+	}
+
+	graphWriter := storage.NewGraphWriterWithDB(db)
+	require.NoError(t, graphWriter.WriteTypes([]*storage.GraphType{
+		{ID: "internal/auth.Service", FilePath: "internal/auth/login.go", ModulePath: "internal/auth", Name: "Service", Kind: "struct", StartLine: 5, EndLine: 9, IsExported: true},
+	}))
+	require.NoError(t, graphWriter.WriteFunctions([]*storage.GraphFunction{
+		{ID: "internal/auth.Service.Login", FilePath: "internal/auth/login.go", ModulePath: "internal/auth", Name: "Login", StartLine: 11, EndLine: 30, IsExported: true, IsMethod: true},
+		{ID: "internal/auth.hash", FilePath: "internal/auth/login.go", ModulePath: "internal/auth", Name: "hash", StartLine: 32, EndLine: 40},
+		{ID: "internal/auth.TestLogin", FilePath: "internal/auth/login_test.go", ModulePath: "internal/auth", Name: "TestLogin", StartLine: 3, EndLine: 12, IsExported: true},
+	}))
+
+	return db
+}
+
+func annotateTestMatches() []PatternMatch {
+	return []PatternMatch{
+		{FilePath: "internal/auth/login.go", StartLine: 15, EndLine: 16},    // In Login
+		{FilePath: "internal/auth/login.go", StartLine: 7, EndLine: 7},      // In Service
+		{FilePath: "internal/auth/login.go", StartLine: 35, EndLine: 35},    // In hash
+		{FilePath: "internal/auth/login_test.go", StartLine: 5, EndLine: 5}, // In TestLogin
+		{FilePath: "cmd/tool/main.go", StartLine: 3, EndLine: 3},            // Not indexed
+		{FilePath: "internal/auth/login.go", StartLine: 29, EndLine: 33},    // Spans two functions
+	}
+}
+
+func TestAnnotateMatches(t *testing.T) {
+	t.Parallel()
+
+	db := newAnnotateTestDB(t)
+	matches := annotateTestMatches()
+
+	keep, err := annotateMatches(context.Background(), db, matches, &PatternRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, true, true, true, true, true}, keep)
+
+	assert.Equal(t, "internal/auth.Service.Login", matches[0].FunctionID)
+	assert.Empty(t, matches[0].TypeID)
+	require.NotNil(t, matches[0].File)
+	assert.Equal(t, MatchFile{Language: "go", ModulePath: "internal/auth"}, *matches[0].File)
+
+	assert.Empty(t, matches[1].FunctionID)
+	assert.Equal(t, "internal/auth.Service", matches[1].TypeID)
+
+	assert.Equal(t, "internal/auth.hash", matches[2].FunctionID)
+
+	assert.Equal(t, "internal/auth.TestLogin", matches[3].FunctionID)
+	assert.True(t, matches[3].File.IsTest)
+
+	assert.Nil(t, matches[4].File)
+	assert.Empty(t, matches[4].FunctionID)
+
+	assert.Empty(t, matches[5].FunctionID, "no single function encloses the match")
+	assert.NotNil(t, matches[5].File)
+}
+
+func TestAnnotateMatches_Filters(t *testing.T) {
+	t.Parallel()
+
+	db := newAnnotateTestDB(t)
+
+	tests := []struct {
+		name     string
+		req      *PatternRequest
+		expected []bool
+	}{
+		{"exclude tests", &PatternRequest{ExcludeTests: true}, []bool{true, true, true, false, false, true}},
+		{"exported only", &PatternRequest{ExportedOnly: true}, []bool{true, false, false, true, false, false}},
+		{"module", &PatternRequest{ModulePath: "internal/auth"}, []bool{true, true, true, true, false, true}},
+		{"parent module", &PatternRequest{ModulePath: "internal/"}, []bool{true, true, true, true, false, true}},
+		{"other module", &PatternRequest{ModulePath: "internal/authz"}, []bool{false, false, false, false, false, false}},
+		{"combined", &PatternRequest{ExcludeTests: true, ExportedOnly: true}, []bool{true, false, false, false, false, false}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			keep, err := annotateMatches(context.Background(), db, annotateTestMatches(), tt.req)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, keep)
+		})
+	}
+}
+
+func TestFilterKept(t *testing.T) {
+	t.Parallel()
+
+	matches := []PatternMatch{{FilePath: "a.go"}, {FilePath: "b.go"}, {FilePath: "c.go"}}
+	raw := []AstGrepMatch{{File: "a.go"}, {File: "b.go"}, {File: "c.go"}}
+
+	kept, keptRaw := filterKept(matches, raw, []bool{false, true, true})
+	require.Len(t, kept, 2)
+	require.Len(t, keptRaw, 2)
+	assert.Equal(t, "b.go", kept[0].FilePath)
+	assert.Equal(t, "b.go", keptRaw[0].File)
+	assert.Equal(t, "c.go", keptRaw[1].File)
+}
+
+func TestExecutePattern_IndexFiltersNeedIndex(t *testing.T) {
+	t.Parallel()
+
+	// Mark initialized so no binary download is attempted
+	provider := &AstGrepProvider{initialized: true, binaryPath: "/nonexistent/ast-grep"}
+
+	_, err := ExecutePattern(context.Background(), provider, &PatternRequest{
+		Pattern:      "fmt.Println($$$)",
+		Language:     "go",
+		ExcludeTests: true,
+	}, "/tmp")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid request")
+	assert.Contains(t, err.Error(), "need an index")
+}
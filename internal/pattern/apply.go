@@ -0,0 +1,363 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// filePatch is the parsed unified diff for a single file.
+type filePatch struct {
+	path  string
+	hunks []hunk
+}
+
+// hunk is one @@ section of a unified diff.
+type hunk struct {
+	oldStart     int      // 1-indexed; for pure insertions, the line after which to insert
+	oldLines     []string // Context and removed lines, in order
+	newLines     []string // Context and added lines, in order
+	oldNoNewline bool     // The last old line has no newline ("\ No newline at end of file")
+	newNoNewline bool     // The last new line has no newline
+}
+
+// ApplyPatch applies a unified diff (as produced by cortex_pattern rewrite mode)
+// to files under projectRoot.
+//
+// The patch is applied all-or-nothing:
+//  1. Every path is validated with validateFilePath (no escaping the project root)
+//  2. Every hunk is checked against the current file contents before anything is written
+//  3. New contents are staged to temp files next to their targets
+//  4. Temp files are renamed over the targets; if a rename fails, files already
+//     replaced are restored to their original contents
+//
+// Only modifications of existing files are supported (no creations or deletions).
+// Hunks must match exactly; a stale diff is rejected rather than fuzzily applied.
+func ApplyPatch(projectRoot string, patch string) (*ApplyResult, error) {
+	cleanRoot := filepath.Clean(projectRoot)
+	if !filepath.IsAbs(cleanRoot) {
+		return nil, fmt.Errorf("project root must be absolute path: %s", projectRoot)
+	}
+
+	patches, err := parseUnifiedDiff(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid patch: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, fp := range patches {
+		if err := validateFilePath(fp.path, cleanRoot); err != nil {
+			return nil, err
+		}
+		if seen[fp.path] {
+			return nil, fmt.Errorf("invalid patch: %s appears more than once", fp.path)
+		}
+		seen[fp.path] = true
+	}
+
+	// Compute every new file before touching disk
+	type pendingWrite struct {
+		path     string
+		original []byte
+		updated  []byte
+		mode     os.FileMode
+	}
+	writes := make([]pendingWrite, 0, len(patches))
+	hunksApplied := 0
+
+	for _, fp := range patches {
+		absPath := filepath.Join(cleanRoot, fp.path)
+		info, err := os.Stat(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch: cannot read %s: %w", fp.path, err)
+		}
+		original, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", fp.path, err)
+		}
+
+		updated, err := applyHunks(string(original), fp.hunks)
+		if err != nil {
+			return nil, fmt.Errorf("invalid patch: %s: %w", fp.path, err)
+		}
+
+		writes = append(writes, pendingWrite{
+			path:     absPath,
+			original: original,
+			updated:  []byte(updated),
+			mode:     info.Mode().Perm(),
+		})
+		hunksApplied += len(fp.hunks)
+	}
+
+	// Stage all temp files; a failure here leaves every target untouched
+	temps := make([]string, 0, len(writes))
+	cleanupTemps := func() {
+		for _, tmp := range temps {
+			os.Remove(tmp)
+		}
+	}
+	for _, w := range writes {
+		tmp, err := writeTempFile(w.path, w.updated, w.mode)
+		if err != nil {
+			cleanupTemps()
+			return nil, fmt.Errorf("failed to stage %s: %w", w.path, err)
+		}
+		temps = append(temps, tmp)
+	}
+
+	// Swap in; roll back already-replaced files if any rename fails
+	for i, w := range writes {
+		if err := os.Rename(temps[i], w.path); err != nil {
+			var restoreErrs []error
+			for j := 0; j < i; j++ {
+				if err := os.WriteFile(writes[j].path, writes[j].original, writes[j].mode); err != nil {
+					restoreErrs = append(restoreErrs, fmt.Errorf("failed to restore %s: %w", writes[j].path, err))
+				}
+			}
+			for _, tmp := range temps[i:] {
+				os.Remove(tmp)
+			}
+			if len(restoreErrs) > 0 {
+				return nil, errors.Join(append([]error{fmt.Errorf("failed to replace %s: %w", w.path, err)}, restoreErrs...)...)
+			}
+			return nil, fmt.Errorf("failed to replace %s (earlier files restored): %w", w.path, err)
+		}
+	}
+
+	result := &ApplyResult{
+		FilesChanged: make([]string, 0, len(patches)),
+		HunksApplied: hunksApplied,
+	}
+	for _, fp := range patches {
+		result.FilesChanged = append(result.FilesChanged, fp.path)
+	}
+	return result, nil
+}
+
+// writeTempFile writes data to a temp file in path's directory (so the final
+// rename stays on one filesystem) and returns its name.
+func writeTempFile(path string, data []byte, mode os.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".cortex-apply-*")
+	if err != nil {
+		return "", err
+	}
+	name := f.Name()
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(name)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(name)
+		return "", err
+	}
+	if err := os.Chmod(name, mode); err != nil {
+		os.Remove(name)
+		return "", err
+	}
+	return name, nil
+}
+
+// applyHunks applies hunks (in file order) to source. The result ends
+// without a newline if source does, unless a hunk reaching the end of the
+// file says otherwise with a "\ No newline at end of file" marker.
+func applyHunks(source string, hunks []hunk) (string, error) {
+	lines := splitLines(source)
+	missingFinalNewline := source != "" && !strings.HasSuffix(source, "\n")
+
+	var out []string
+	cursor := 0 // Index into lines of the next unconsumed line
+	for i, h := range hunks {
+		start := h.oldStart - 1
+		if len(h.oldLines) == 0 {
+			start = h.oldStart // Pure insertion after line oldStart
+		}
+		if start < cursor || start+len(h.oldLines) > len(lines) {
+			return "", fmt.Errorf("hunk %d (line %d) is out of range", i+1, h.oldStart)
+		}
+		for j, want := range h.oldLines {
+			if lines[start+j] != want {
+				return "", fmt.Errorf("hunk %d does not match line %d (file changed since the diff was generated?)", i+1, start+j+1)
+			}
+		}
+
+		end := start + len(h.oldLines)
+		if h.oldNoNewline && (end != len(lines) || !missingFinalNewline) {
+			return "", fmt.Errorf("hunk %d does not match the end of the file (file changed since the diff was generated?)", i+1)
+		}
+		if end == len(lines) && (h.oldNoNewline || h.newNoNewline) {
+			missingFinalNewline = h.newNoNewline
+		}
+
+		out = append(out, lines[cursor:start]...)
+		out = append(out, h.newLines...)
+		cursor = end
+	}
+	out = append(out, lines[cursor:]...)
+
+	result := strings.Join(out, "")
+	if missingFinalNewline {
+		result = strings.TrimSuffix(result, "\n")
+	}
+	return result, nil
+}
+
+// parseUnifiedDiff parses a multi-file unified diff with git-style a/ and b/
+// path prefixes (prefixes are optional).
+func parseUnifiedDiff(patch string) ([]filePatch, error) {
+	lines := strings.SplitAfter(patch, "\n")
+	var patches []filePatch
+	var current *filePatch
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			if i+1 >= len(lines) || !strings.HasPrefix(lines[i+1], "+++ ") {
+				return nil, fmt.Errorf("line %d: '---' header without '+++'", i+1)
+			}
+			oldPath := diffHeaderPath(line[4:], "a/")
+			newPath := diffHeaderPath(lines[i+1][4:], "b/")
+			if oldPath == "/dev/null" || newPath == "/dev/null" {
+				return nil, errors.New("creating or deleting files is not supported")
+			}
+			if oldPath != newPath {
+				return nil, fmt.Errorf("renames are not supported (%s -> %s)", oldPath, newPath)
+			}
+			patches = append(patches, filePatch{path: newPath})
+			current = &patches[len(patches)-1]
+			i++
+
+		case strings.HasPrefix(line, "@@"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: hunk before file header", i+1)
+			}
+			h, consumed, err := parseHunk(lines[i:])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current.hunks = append(current.hunks, h)
+			i += consumed - 1
+
+		default:
+			// Ignore preamble such as "diff --git" or "index" lines
+		}
+	}
+
+	if len(patches) == 0 {
+		return nil, errors.New("no file diffs found")
+	}
+	for _, fp := range patches {
+		if len(fp.hunks) == 0 {
+			return nil, fmt.Errorf("%s has no hunks", fp.path)
+		}
+	}
+	return patches, nil
+}
+
+// parseHunk parses a hunk starting at lines[0] ("@@ -a,b +c,d @@") and returns
+// it with the number of lines consumed.
+func parseHunk(lines []string) (hunk, int, error) {
+	header := strings.TrimRight(lines[0], "\r\n")
+	fields := strings.Fields(header)
+	if len(fields) < 4 || fields[0] != "@@" || fields[3] != "@@" ||
+		!strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return hunk{}, 0, fmt.Errorf("malformed hunk header %q", header)
+	}
+
+	oldStart, oldCount, err := parseHunkRange(fields[1][1:])
+	if err != nil {
+		return hunk{}, 0, fmt.Errorf("malformed hunk header %q: %w", header, err)
+	}
+	_, newCount, err := parseHunkRange(fields[2][1:])
+	if err != nil {
+		return hunk{}, 0, fmt.Errorf("malformed hunk header %q: %w", header, err)
+	}
+
+	h := hunk{oldStart: oldStart}
+	var last byte // Kind of the previous line: ' ', '-' or '+'
+	i := 1
+	for (len(h.oldLines) < oldCount || len(h.newLines) < newCount) && i < len(lines) {
+		line := lines[i]
+		i++
+		if line == "" {
+			break
+		}
+		if !strings.HasSuffix(line, "\n") {
+			line += "\n"
+		}
+		switch line[0] {
+		case ' ':
+			h.oldLines = append(h.oldLines, line[1:])
+			h.newLines = append(h.newLines, line[1:])
+		case '-':
+			h.oldLines = append(h.oldLines, line[1:])
+		case '+':
+			h.newLines = append(h.newLines, line[1:])
+		case '\\':
+			h.markNoNewline(last)
+		case '\n':
+			// Some tools strip the leading space from empty context lines
+			h.oldLines = append(h.oldLines, "\n")
+			h.newLines = append(h.newLines, "\n")
+			line = " \n"
+		default:
+			return hunk{}, 0, fmt.Errorf("unexpected line in hunk: %q", strings.TrimRight(line, "\n"))
+		}
+		last = line[0]
+	}
+	// The marker of the hunk's last line follows it
+	if i < len(lines) && strings.HasPrefix(lines[i], "\\") {
+		h.markNoNewline(last)
+		i++
+	}
+
+	if len(h.oldLines) != oldCount || len(h.newLines) != newCount {
+		return hunk{}, 0, fmt.Errorf("hunk %q is truncated", header)
+	}
+	return h, i, nil
+}
+
+// markNoNewline records a "\ No newline at end of file" marker following a
+// line of the given kind (' ', '-' or '+').
+func (h *hunk) markNoNewline(kind byte) {
+	switch kind {
+	case ' ':
+		h.oldNoNewline, h.newNoNewline = true, true
+	case '-':
+		h.oldNoNewline = true
+	case '+':
+		h.newNoNewline = true
+	}
+}
+
+// parseHunkRange parses "start,count" or "start" (count 1).
+func parseHunkRange(s string) (int, int, error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	start, err := strconv.Atoi(startStr)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// diffHeaderPath extracts the path from a ---/+++ header value, dropping any
+// trailing timestamp and the given git prefix.
+func diffHeaderPath(value, prefix string) string {
+	value = strings.TrimRight(value, "\r\n")
+	if tab := strings.IndexByte(value, '\t'); tab >= 0 {
+		value = value[:tab]
+	}
+	return strings.TrimPrefix(value, prefix)
+}
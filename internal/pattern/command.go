@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 // SupportedLanguages defines all languages that ast-grep supports
@@ -83,6 +85,14 @@ func ValidateRequest(req *PatternRequest) error {
 		}
 	}
 
+	// Validate label; rewrites produce patches for the project's files only
+	if err := validateLabel(req.Label); err != nil {
+		return err
+	}
+	if req.Rewrite != "" && req.Label != "" && req.Label != storage.ProjectLabel {
+		return errors.New("invalid label: rewrite only applies to the project's own files")
+	}
+
 	return nil
 }
 
@@ -108,6 +118,12 @@ func BuildCommand(req *PatternRequest, projectRoot string) ([]string, error) {
 		"--json", // Always use JSON output
 	}
 
+	// Rewrite mode: ast-grep reports replacements in the JSON output.
+	// Never pass --update-all; files are only changed through ApplyPatch.
+	if req.Rewrite != "" {
+		args = append(args, "--rewrite", req.Rewrite)
+	}
+
 	// Add context lines (-C flag)
 	contextLines := DefaultContextLines
 	if req.ContextLines != nil {
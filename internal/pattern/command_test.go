@@ -89,6 +89,25 @@ func TestValidateRequest(t *testing.T) {
 			},
 			expectErr: "limit must be between 1 and 100",
 		},
+		{
+			name: "invalid label",
+			req: &PatternRequest{
+				Pattern:  "test",
+				Language: "go",
+				Label:    "Wiki",
+			},
+			expectErr: "invalid label",
+		},
+		{
+			name: "rewrite with content source label",
+			req: &PatternRequest{
+				Pattern:  "test",
+				Language: "go",
+				Rewrite:  "other",
+				Label:    "wiki",
+			},
+			expectErr: "rewrite only applies to the project's own files",
+		},
 		{
 			name: "valid minimal request",
 			req: &PatternRequest{
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"os/exec"
 	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
 )
 
 const (
@@ -20,11 +22,15 @@ const (
 // Process:
 // 1. Ensure ast-grep binary is installed
 // 2. Validate request parameters
-// 3. Build safe command arguments
-// 4. Execute with 30s timeout
-// 5. Parse JSON output
+// 3. Resolve the directories to search (project and content sources, by label)
+// 4. Build safe command arguments and execute in each directory (30s timeout in total)
+// 5. Parse JSON output; content source paths get their "@<label>/" prefix
 // 6. Transform to PatternResponse format
-// 7. Apply result limiting
+// 7. Annotate matches from the index and apply index filters (WithIndex only)
+// 8. Build per-file diffs (rewrite mode only; files are never modified)
+// 9. Apply result limiting
+//
+// Rewrite mode only searches the project, since patches apply under its root.
 //
 // Errors:
 // - Binary not available: Installation or verification failed
@@ -42,31 +48,97 @@ func ExecutePattern(ctx context.Context, provider *AstGrepProvider, req *Pattern
 	if err := ValidateRequest(req); err != nil {
 		return nil, fmt.Errorf("invalid request: %w", err)
 	}
+	if provider.db == nil && HasIndexFilters(req) {
+		return nil, fmt.Errorf("invalid request: exclude_tests, exported_only and module_path need an index")
+	}
 
-	// 3. Build command
-	args, err := BuildCommand(req, projectRoot)
+	// 3. Resolve the directories to search
+	label := req.Label
+	if req.Rewrite != "" {
+		label = storage.ProjectLabel
+	}
+	roots, err := searchRoots(provider.db, label, projectRoot)
 	if err != nil {
-		return nil, fmt.Errorf("command build failed: %w", err)
+		return nil, err
 	}
 
-	// 4. Execute with timeout
+	// 4. Execute with timeout in each directory
 	execCtx, cancel := context.WithTimeout(ctx, ExecutionTimeout)
 	defer cancel()
 
-	cmd := exec.CommandContext(execCtx, provider.binaryPath, args...)
-	cmd.Dir = projectRoot
+	startTime := time.Now()
+	result := &AstGrepResult{Matches: []AstGrepMatch{}}
+	for _, root := range roots {
+		filePaths, ok := root.filePaths(req.FilePaths)
+		if !ok {
+			continue
+		}
+		rootReq := *req
+		rootReq.FilePaths = filePaths
+		args, err := BuildCommand(&rootReq, root.dir)
+		if err != nil {
+			return nil, fmt.Errorf("command build failed: %w", err)
+		}
+
+		// 5. Parse JSON output, prefixing content source paths
+		rootResult, err := runPattern(execCtx, provider.binaryPath, args, root.dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range rootResult.Matches {
+			match.File = root.prefix + match.File
+			result.Matches = append(result.Matches, match)
+		}
+	}
+	tookMs := time.Since(startTime).Milliseconds()
+
+	// 6. Transform to response format
+	response := transformToResponse(result, req, tookMs)
+
+	// 7. Annotate from the index; filtered-out matches are also left out of rewrite diffs
+	if provider.db != nil {
+		tx, generation, err := storage.BeginSnapshot(ctx, provider.db)
+		if err != nil {
+			return nil, err
+		}
+		defer tx.Rollback()
+
+		keep, err := annotateMatches(ctx, tx, response.Matches, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to annotate matches: %w", err)
+		}
+		response.Matches, result.Matches = filterKept(response.Matches, result.Matches, keep)
+		response.Total = len(response.Matches)
+		response.Metadata.Generation = generation
+	}
+
+	// 8. Rewrite mode: diff every match, not just the ones within the limit
+	if req.Rewrite != "" {
+		diffs, err := buildRewriteDiffs(projectRoot, result.Matches)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rewrite diff: %w", err)
+		}
+		response.Diffs = diffs
+		response.Patch = joinDiffs(diffs)
+	}
+
+	// 9. Apply limit
+	response = applyLimit(response, req)
+
+	return response, nil
+}
+
+// runPattern runs ast-grep with args in dir and parses its output.
+func runPattern(ctx context.Context, binaryPath string, args []string, dir string) (*AstGrepResult, error) {
+	cmd := exec.CommandContext(ctx, binaryPath, args...)
+	cmd.Dir = dir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	startTime := time.Now()
-	err = cmd.Run()
-	tookMs := time.Since(startTime).Milliseconds()
-
-	// 5. Handle errors
-	if err != nil {
-		if execCtx.Err() == context.DeadlineExceeded {
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("pattern search timed out (30s)")
 		}
 		if stderr.Len() > 0 {
@@ -75,9 +147,7 @@ func ExecutePattern(ctx context.Context, provider *AstGrepProvider, req *Pattern
 		return nil, fmt.Errorf("execution failed: %w", err)
 	}
 
-	// 6. Parse JSON output
 	stdoutBytes := stdout.Bytes()
-	// Debug: Log first 200 bytes if parsing fails
 	result, err := parseAstGrepOutput(stdoutBytes)
 	if err != nil {
 		preview := string(stdoutBytes)
@@ -86,14 +156,7 @@ func ExecutePattern(ctx context.Context, provider *AstGrepProvider, req *Pattern
 		}
 		return nil, fmt.Errorf("failed to parse output: %w (stdout preview: %q)", err, preview)
 	}
-
-	// 7. Transform to response format
-	response := transformToResponse(result, req, tookMs)
-
-	// 8. Apply limit
-	response = applyLimit(response, req)
-
-	return response, nil
+	return result, nil
 }
 
 // parseAstGrepOutput parses ast-grep's JSON compact format output.
@@ -151,6 +214,9 @@ func transformToResponse(result *AstGrepResult, req *PatternRequest, tookMs int6
 			Context:   match.Text, // ast-grep -C includes context in text field
 			Metavars:  metavars,
 		}
+		if match.Replacement != nil {
+			matches[i].Replacement = *match.Replacement
+		}
 	}
 
 	return &PatternResponse{
@@ -161,6 +227,7 @@ func transformToResponse(result *AstGrepResult, req *PatternRequest, tookMs int6
 			Pattern:    req.Pattern,
 			Language:   req.Language,
 			Strictness: GetStrictness(req),
+			Rewrite:    req.Rewrite,
 		},
 	}
 }
@@ -181,10 +248,8 @@ func applyLimit(response *PatternResponse, req *PatternRequest) *PatternResponse
 		return response
 	}
 
-	// Apply limit but preserve total count
-	return &PatternResponse{
-		Matches:  response.Matches[:limit],
-		Total:    response.Total, // Keep original total
-		Metadata: response.Metadata,
-	}
+	// Apply limit but preserve total count (and rewrite diffs, which cover every match)
+	limited := *response
+	limited.Matches = response.Matches[:limit]
+	return &limited
 }
@@ -26,3 +26,14 @@ type PatternSearcher interface {
 	// - System errors (binary unavailable, execution failed): Internal failures
 	Search(ctx context.Context, req *PatternRequest, projectRoot string) (*PatternResponse, error)
 }
+
+// QuerySearcher defines the interface for tree-sitter query search.
+// Unlike PatternSearcher it runs in-process and needs no external binary.
+type QuerySearcher interface {
+	// Query runs a tree-sitter S-expression query over the indexed files.
+	//
+	// Error types:
+	// - User errors (invalid query, unsupported language): Should be shown to LLM
+	// - System errors (database failures): Internal failures
+	Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error)
+}
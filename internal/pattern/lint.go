@@ -0,0 +1,402 @@
+package pattern
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobwas/glob"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// RulesDir is the project directory holding ast-grep YAML lint rules.
+const RulesDir = ".cortex/rules"
+
+// ValidSeverities defines the rule severities ast-grep reports
+var ValidSeverities = map[string]bool{
+	"error":   true,
+	"warning": true,
+	"info":    true,
+	"hint":    true,
+}
+
+// LintRequest represents a cortex lint run (CLI or MCP cortex_lint)
+type LintRequest struct {
+	FilePaths []string `json:"file_paths"` // Optional: File/glob filters
+	RuleID    string   `json:"rule_id"`    // Optional: Only report this rule
+	Severity  string   `json:"severity"`   // Optional: Only report this severity
+	Label     string   `json:"label"`      // Optional: Content source label, or "project" for the repository's own files
+}
+
+// LintViolation is a single rule violation.
+// Lines and columns are 1-indexed.
+type LintViolation struct {
+	RuleID      string `json:"rule_id"`
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Note        string `json:"note,omitempty"`
+	FilePath    string `json:"file_path"` // Relative to project root
+	StartLine   int    `json:"start_line"`
+	EndLine     int    `json:"end_line"`
+	StartColumn int    `json:"start_column"`
+	EndColumn   int    `json:"end_column"`
+	MatchText   string `json:"match_text"`
+	Fix         string `json:"fix,omitempty"` // Suggested replacement (rules with a fix only)
+}
+
+// LintResult is the outcome of a lint run
+type LintResult struct {
+	Violations []LintViolation `json:"violations"`
+	Summary    LintSummary     `json:"summary"`
+	LastRun    string          `json:"last_run,omitempty"` // Stored results only: when `cortex lint` last stored them
+}
+
+// LintSummary counts violations by severity
+type LintSummary struct {
+	Errors      int   `json:"errors"`
+	Warnings    int   `json:"warnings"`
+	Info        int   `json:"info"` // info and hint
+	RulesLoaded int   `json:"rules_loaded"`
+	TookMs      int64 `json:"took_ms"`
+}
+
+// Linter defines the interface for running project lint rules.
+type Linter interface {
+	// Lint runs the rules in projectRoot's .cortex/rules directory.
+	//
+	// Error types:
+	// - User errors (invalid rule, bad paths, no rules directory): Should be shown to LLM
+	// - System errors (binary unavailable, execution failed): Internal failures
+	Lint(ctx context.Context, req *LintRequest, projectRoot string) (*LintResult, error)
+}
+
+// ValidateLintRequest validates a LintRequest and returns an error if invalid
+func ValidateLintRequest(req *LintRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+	if req.Severity != "" && !ValidSeverities[req.Severity] {
+		return fmt.Errorf("invalid severity: %s (valid: error, warning, info, hint)", req.Severity)
+	}
+	return validateLabel(req.Label)
+}
+
+// Lint implements the Linter interface by running `ast-grep scan` with the
+// project's rules directory.
+//
+// ast-grep discovers rules through an sgconfig.yml; a temporary one pointing at
+// .cortex/rules is generated per run so projects don't need their own.
+// ast-grep exits non-zero when error-severity rules match, so a failed exit
+// with parseable JSON on stdout is treated as success.
+//
+// With an index (WithIndex), the content sources recorded in it are checked
+// against the project's rules too, unless req.Label selects one root.
+func (p *AstGrepProvider) Lint(ctx context.Context, req *LintRequest, projectRoot string) (*LintResult, error) {
+	if err := p.ensureBinaryInstalled(ctx); err != nil {
+		return nil, fmt.Errorf("binary not available: %w", err)
+	}
+
+	if err := ValidateLintRequest(req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	cleanRoot := filepath.Clean(projectRoot)
+	if !filepath.IsAbs(cleanRoot) {
+		return nil, fmt.Errorf("project root must be absolute path: %s", projectRoot)
+	}
+
+	rulesDir := filepath.Join(cleanRoot, RulesDir)
+	rulesLoaded, err := countRuleFiles(rulesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	configPath, err := writeScanConfig(rulesDir)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(configPath)
+
+	roots, err := searchRoots(p.db, req.Label, cleanRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, ExecutionTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+	var matches []AstGrepMatch
+	for _, root := range roots {
+		filePaths, ok := root.filePaths(req.FilePaths)
+		if !ok {
+			continue
+		}
+		rootReq := *req
+		rootReq.FilePaths = filePaths
+		args, err := buildScanCommand(&rootReq, configPath, root.dir)
+		if err != nil {
+			return nil, fmt.Errorf("invalid request: %w", err)
+		}
+
+		result, err := p.runScan(execCtx, args, root.dir)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range result.Matches {
+			m.File = root.prefix + filepath.ToSlash(m.File)
+			matches = append(matches, m)
+		}
+	}
+	tookMs := time.Since(startTime).Milliseconds()
+
+	violations := FilterLintViolations(toLintViolations(matches), req)
+	return &LintResult{
+		Violations: violations,
+		Summary:    summarize(violations, rulesLoaded, tookMs),
+	}, nil
+}
+
+// runScan runs `ast-grep scan` with args in dir and parses its output.
+func (p *AstGrepProvider) runScan(ctx context.Context, args []string, dir string) (*AstGrepResult, error) {
+	cmd := exec.CommandContext(ctx, p.binaryPath, args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	runErr := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("lint timed out (30s)")
+	}
+
+	result, err := parseAstGrepOutput(stdout.Bytes())
+	if runErr != nil && (err != nil || stdout.Len() == 0) {
+		if stderr.Len() > 0 {
+			return nil, fmt.Errorf("ast-grep error: %s", stderr.String())
+		}
+		return nil, fmt.Errorf("execution failed: %w", runErr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse output: %w", err)
+	}
+	return result, nil
+}
+
+// buildScanCommand constructs the argv for `ast-grep scan`.
+// File paths are validated like BuildCommand's to prevent directory traversal.
+// Plain files and directories are passed as scan paths and globs as --globs; a mix
+// of both scans the whole project and relies on FilterLintViolations.
+func buildScanCommand(req *LintRequest, configPath, projectRoot string) ([]string, error) {
+	args := []string{"scan", "--config", configPath, "--json=compact"}
+
+	if req.RuleID != "" {
+		args = append(args, "--filter", "^"+regexp.QuoteMeta(req.RuleID)+"$")
+	}
+
+	var globs, paths []string
+	for _, path := range req.FilePaths {
+		if err := validateFilePath(path, projectRoot); err != nil {
+			return nil, err
+		}
+		if strings.ContainsAny(path, "*?[{") {
+			globs = append(globs, path)
+		} else {
+			paths = append(paths, path)
+		}
+	}
+
+	switch {
+	case len(globs) > 0 && len(paths) == 0:
+		args = append(args, "--globs", strings.Join(globs, ","), ".")
+	case len(paths) > 0 && len(globs) == 0:
+		args = append(args, paths...)
+	default:
+		// Scan current directory (command will be run with cwd=projectRoot)
+		args = append(args, ".")
+	}
+	return args, nil
+}
+
+// countRuleFiles returns the number of YAML rule files in rulesDir.
+func countRuleFiles(rulesDir string) (int, error) {
+	count := 0
+	err := filepath.WalkDir(rulesDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		ext := filepath.Ext(path)
+		if !d.IsDir() && (ext == ".yml" || ext == ".yaml") {
+			count++
+		}
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("invalid rules directory: %s does not exist", RulesDir)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read rules directory: %w", err)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("invalid rules directory: no .yml rules in %s", RulesDir)
+	}
+	return count, nil
+}
+
+// writeScanConfig writes a temporary sgconfig.yml pointing ast-grep at rulesDir
+// and returns its path.
+func writeScanConfig(rulesDir string) (string, error) {
+	f, err := os.CreateTemp("", "cortex-sgconfig-*.yml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create scan config: %w", err)
+	}
+	defer f.Close()
+
+	// JSON strings are valid YAML scalars and handle quoting for us
+	quoted, err := json.Marshal(rulesDir)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode rules directory: %w", err)
+	}
+	if _, err := fmt.Fprintf(f, "ruleDirs:\n  - %s\n", quoted); err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write scan config: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// toLintViolations converts ast-grep scan matches to violations.
+// ast-grep's JSON positions are 0-based; violations are 1-indexed.
+func toLintViolations(matches []AstGrepMatch) []LintViolation {
+	violations := make([]LintViolation, 0, len(matches))
+	for _, m := range matches {
+		v := LintViolation{
+			RuleID:      m.RuleID,
+			Severity:    m.Severity,
+			Message:     m.Message,
+			Note:        m.Note,
+			FilePath:    filepath.ToSlash(m.File),
+			StartLine:   m.Range.Start.Line + 1,
+			EndLine:     m.Range.End.Line + 1,
+			StartColumn: m.Range.Start.Column + 1,
+			EndColumn:   m.Range.End.Column + 1,
+			MatchText:   m.Text,
+		}
+		if m.Replacement != nil {
+			v.Fix = *m.Replacement
+		}
+		violations = append(violations, v)
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.FilePath != b.FilePath {
+			return a.FilePath < b.FilePath
+		}
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.StartColumn < b.StartColumn
+	})
+	return violations
+}
+
+// FilterLintViolations returns the violations matching req's rule, severity,
+// label and file/glob filters.
+func FilterLintViolations(violations []LintViolation, req *LintRequest) []LintViolation {
+	inPaths := LintPathMatcher(req.FilePaths)
+
+	filtered := make([]LintViolation, 0, len(violations))
+	for _, v := range violations {
+		if req.RuleID != "" && v.RuleID != req.RuleID {
+			continue
+		}
+		if req.Severity != "" && v.Severity != req.Severity {
+			continue
+		}
+		if req.Label != "" && storage.ContentSourceLabel(v.FilePath) != req.Label {
+			continue
+		}
+		if !inPaths(v.FilePath) {
+			continue
+		}
+		filtered = append(filtered, v)
+	}
+	return filtered
+}
+
+// LintPathMatcher returns a predicate reporting whether a project-relative path
+// is covered by paths. A path is covered when it equals one of paths, is inside
+// one of them (directory prefix), or matches one of them as a glob.
+// An empty paths covers everything.
+func LintPathMatcher(paths []string) func(path string) bool {
+	if len(paths) == 0 {
+		return func(string) bool { return true }
+	}
+
+	prefixes := make([]string, 0, len(paths))
+	globs := make([]glob.Glob, 0, len(paths))
+	for _, p := range paths {
+		prefixes = append(prefixes, strings.TrimSuffix(filepath.ToSlash(filepath.Clean(p)), "/"))
+		if g, err := glob.Compile(p, '/'); err == nil {
+			globs = append(globs, g)
+		}
+	}
+
+	return func(path string) bool {
+		for _, p := range prefixes {
+			if p == "." || path == p || strings.HasPrefix(path, p+"/") {
+				return true
+			}
+		}
+		return len(globs) > 0 && matchesAnyGlob(globs, path)
+	}
+}
+
+// summarize counts violations by severity.
+func summarize(violations []LintViolation, rulesLoaded int, tookMs int64) LintSummary {
+	summary := LintSummary{RulesLoaded: rulesLoaded, TookMs: tookMs}
+	for _, v := range violations {
+		switch v.Severity {
+		case "error":
+			summary.Errors++
+		case "warning":
+			summary.Warnings++
+		default:
+			summary.Info++
+		}
+	}
+	return summary
+}
+
+// SummarizeLintViolations builds a summary for stored violations (no run metadata).
+func SummarizeLintViolations(violations []LintViolation) LintSummary {
+	return summarize(violations, 0, 0)
+}
+
+// ToStoredViolations converts violations for storage.ReplaceLintViolations.
+func ToStoredViolations(violations []LintViolation) []storage.LintViolation {
+	stored := make([]storage.LintViolation, len(violations))
+	for i, v := range violations {
+		stored[i] = storage.LintViolation(v)
+	}
+	return stored
+}
+
+// FromStoredViolations converts violations read by storage.QueryLintViolations.
+func FromStoredViolations(stored []storage.LintViolation) []LintViolation {
+	violations := make([]LintViolation, len(stored))
+	for i, v := range stored {
+		violations[i] = LintViolation(v)
+	}
+	return violations
+}
@@ -0,0 +1,273 @@
+package pattern
+
+// Test Plan for Project Lint Rules:
+// - buildScanCommand passes the generated config, rule filter and scan paths
+// - buildScanCommand rejects paths outside the project root
+// - countRuleFiles requires an existing .cortex/rules with at least one .yml rule
+// - writeScanConfig points ast-grep at the rules directory
+// - toLintViolations converts 0-based scan output to sorted 1-indexed violations
+// - FilterLintViolations applies rule, severity, label and directory/glob filters
+// - ValidateLintRequest rejects unknown severities and invalid labels
+// - FormatSARIF emits one rule per id and maps severities to SARIF levels
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildScanCommand(t *testing.T) {
+	t.Parallel()
+
+	root := "/project"
+	tests := []struct {
+		name     string
+		req      *LintRequest
+		expected []string
+	}{
+		{
+			name:     "whole project",
+			req:      &LintRequest{},
+			expected: []string{"scan", "--config", "/tmp/sg.yml", "--json=compact", "."},
+		},
+		{
+			name:     "rule filter",
+			req:      &LintRequest{RuleID: "no-fmt.println"},
+			expected: []string{"scan", "--config", "/tmp/sg.yml", "--json=compact", "--filter", `^no-fmt\.println$`, "."},
+		},
+		{
+			name:     "plain paths",
+			req:      &LintRequest{FilePaths: []string{"internal", "cmd/main.go"}},
+			expected: []string{"scan", "--config", "/tmp/sg.yml", "--json=compact", "internal", "cmd/main.go"},
+		},
+		{
+			name:     "globs",
+			req:      &LintRequest{FilePaths: []string{"internal/**/*.go"}},
+			expected: []string{"scan", "--config", "/tmp/sg.yml", "--json=compact", "--globs", "internal/**/*.go", "."},
+		},
+		{
+			name:     "mixed paths and globs",
+			req:      &LintRequest{FilePaths: []string{"internal", "cmd/**/*.go"}},
+			expected: []string{"scan", "--config", "/tmp/sg.yml", "--json=compact", "."},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			args, err := buildScanCommand(tt.req, "/tmp/sg.yml", root)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestBuildScanCommand_PathTraversal(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"../secret", "/etc/passwd", "a/../../b"} {
+		_, err := buildScanCommand(&LintRequest{FilePaths: []string{path}}, "/tmp/sg.yml", "/project")
+		require.Error(t, err, path)
+		assert.Contains(t, err.Error(), "outside project root")
+	}
+}
+
+func TestCountRuleFiles(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	rulesDir := filepath.Join(root, RulesDir)
+
+	_, err := countRuleFiles(rulesDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid rules directory")
+
+	require.NoError(t, os.MkdirAll(filepath.Join(rulesDir, "go"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "README.md"), []byte("rules"), 0644))
+	_, err = countRuleFiles(rulesDir)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no .yml rules")
+
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "no-println.yml"), []byte("id: no-println"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(rulesDir, "go", "exec-ctx.yaml"), []byte("id: exec-ctx"), 0644))
+	count, err := countRuleFiles(rulesDir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestWriteScanConfig(t *testing.T) {
+	t.Parallel()
+
+	rulesDir := filepath.Join(t.TempDir(), "my project", RulesDir)
+	path, err := writeScanConfig(rulesDir)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	quoted, _ := json.Marshal(rulesDir)
+	assert.Equal(t, "ruleDirs:\n  - "+string(quoted)+"\n", string(data))
+}
+
+func TestToLintViolations(t *testing.T) {
+	t.Parallel()
+
+	fix := "exec.CommandContext(ctx, $$$ARGS)"
+	matches := []AstGrepMatch{
+		{
+			Text:     `fmt.Println("hi")`,
+			File:     "internal/b.go",
+			Range:    AstGrepRange{Start: AstGrepPosition{Line: 9, Column: 1}, End: AstGrepPosition{Line: 9, Column: 18}},
+			RuleID:   "no-println",
+			Severity: "warning",
+			Message:  "Use the logger",
+		},
+		{
+			Text:        `exec.Command("ls")`,
+			File:        "internal/a.go",
+			Range:       AstGrepRange{Start: AstGrepPosition{Line: 4, Column: 8}, End: AstGrepPosition{Line: 4, Column: 26}},
+			RuleID:      "exec-ctx",
+			Severity:    "error",
+			Message:     "exec.Command without a context",
+			Note:        "Use exec.CommandContext",
+			Replacement: &fix,
+		},
+	}
+
+	violations := toLintViolations(matches)
+	require.Len(t, violations, 2)
+
+	assert.Equal(t, LintViolation{
+		RuleID:      "exec-ctx",
+		Severity:    "error",
+		Message:     "exec.Command without a context",
+		Note:        "Use exec.CommandContext",
+		FilePath:    "internal/a.go",
+		StartLine:   5,
+		EndLine:     5,
+		StartColumn: 9,
+		EndColumn:   27,
+		MatchText:   `exec.Command("ls")`,
+		Fix:         fix,
+	}, violations[0])
+	assert.Equal(t, "internal/b.go", violations[1].FilePath)
+	assert.Equal(t, 10, violations[1].StartLine)
+	assert.Empty(t, violations[1].Fix)
+}
+
+func TestFilterLintViolations(t *testing.T) {
+	t.Parallel()
+
+	violations := []LintViolation{
+		{RuleID: "exec-ctx", Severity: "error", FilePath: "internal/auth/login.go"},
+		{RuleID: "no-println", Severity: "warning", FilePath: "internal/auth/login.go"},
+		{RuleID: "no-println", Severity: "warning", FilePath: "internal/authz/check.go"},
+		{RuleID: "no-println", Severity: "warning", FilePath: "cmd/cortex/main.go"},
+		{RuleID: "exec-ctx", Severity: "error", FilePath: "@tools/gen/main.go"},
+	}
+
+	tests := []struct {
+		name     string
+		req      *LintRequest
+		expected int
+	}{
+		{"no filters", &LintRequest{}, 5},
+		{"rule", &LintRequest{RuleID: "no-println"}, 3},
+		{"severity", &LintRequest{Severity: "error"}, 2},
+		{"directory", &LintRequest{FilePaths: []string{"internal/auth"}}, 2},
+		{"directory trailing slash", &LintRequest{FilePaths: []string{"internal/auth/"}}, 2},
+		{"file", &LintRequest{FilePaths: []string{"cmd/cortex/main.go"}}, 1},
+		{"glob", &LintRequest{FilePaths: []string{"internal/**/*.go"}}, 3},
+		{"dot", &LintRequest{FilePaths: []string{"."}}, 5},
+		{"combined", &LintRequest{FilePaths: []string{"internal"}, RuleID: "no-println"}, 2},
+		{"project label", &LintRequest{Label: "project"}, 4},
+		{"content source label", &LintRequest{Label: "tools"}, 1},
+		{"content source directory", &LintRequest{FilePaths: []string{"@tools/gen"}}, 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Len(t, FilterLintViolations(violations, tt.req), tt.expected)
+		})
+	}
+}
+
+func TestValidateLintRequest(t *testing.T) {
+	t.Parallel()
+
+	require.Error(t, ValidateLintRequest(nil))
+	require.NoError(t, ValidateLintRequest(&LintRequest{Severity: "hint"}))
+
+	err := ValidateLintRequest(&LintRequest{Severity: "fatal"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid severity")
+
+	require.NoError(t, ValidateLintRequest(&LintRequest{Label: "wiki"}))
+	err = ValidateLintRequest(&LintRequest{Label: "Wiki/docs"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid label")
+}
+
+func TestSummarizeLintViolations(t *testing.T) {
+	t.Parallel()
+
+	summary := SummarizeLintViolations([]LintViolation{
+		{Severity: "error"}, {Severity: "error"}, {Severity: "warning"}, {Severity: "info"}, {Severity: "hint"},
+	})
+	assert.Equal(t, LintSummary{Errors: 2, Warnings: 1, Info: 2}, summary)
+}
+
+func TestFormatSARIF(t *testing.T) {
+	t.Parallel()
+
+	data, err := FormatSARIF([]LintViolation{
+		{RuleID: "no-println", Severity: "warning", Message: "Use the logger", FilePath: "b.go", StartLine: 3, EndLine: 3, StartColumn: 2, EndColumn: 10},
+		{RuleID: "exec-ctx", Severity: "error", Message: "Missing context", Note: "Use CommandContext", FilePath: "a.go", StartLine: 7, EndLine: 8, StartColumn: 1, EndColumn: 4},
+		{RuleID: "no-println", Severity: "warning", Message: "Use the logger", FilePath: "c.go", StartLine: 1, EndLine: 1, StartColumn: 1, EndColumn: 5},
+		{RuleID: "todo", Severity: "hint", Message: "Track TODOs", FilePath: "c.go", StartLine: 2, EndLine: 2, StartColumn: 1, EndColumn: 5},
+	})
+	require.NoError(t, err)
+
+	var log sarifLog
+	require.NoError(t, json.Unmarshal(data, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+	run := log.Runs[0]
+
+	require.Len(t, run.Tool.Driver.Rules, 3)
+	assert.Equal(t, "exec-ctx", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "no-println", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, "todo", run.Tool.Driver.Rules[2].ID)
+
+	require.Len(t, run.Results, 4)
+	assert.Equal(t, "no-println", run.Results[0].RuleID)
+	assert.Equal(t, 1, run.Results[0].RuleIndex)
+	assert.Equal(t, "warning", run.Results[0].Level)
+
+	exec := run.Results[1]
+	assert.Equal(t, 0, exec.RuleIndex)
+	assert.Equal(t, "error", exec.Level)
+	assert.True(t, strings.HasSuffix(exec.Message.Text, "Use CommandContext"))
+	region := exec.Locations[0].PhysicalLocation.Region
+	assert.Equal(t, sarifRegion{StartLine: 7, StartColumn: 1, EndLine: 8, EndColumn: 4}, region)
+	assert.Equal(t, "a.go", exec.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	assert.Equal(t, "note", run.Results[3].Level)
+}
+
+func TestFormatSARIF_Empty(t *testing.T) {
+	t.Parallel()
+
+	data, err := FormatSARIF(nil)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"rules": []`)
+	assert.Contains(t, string(data), `"results": []`)
+}
@@ -2,6 +2,7 @@ package pattern
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"os"
@@ -15,6 +16,9 @@ type AstGrepProvider struct {
 	version     string
 	initialized bool
 	mu          sync.Mutex
+
+	// db is the branch index used to annotate matches (nil = no annotations)
+	db *sql.DB
 }
 
 // NewAstGrepProvider creates a new ast-grep provider with lazy initialization.
@@ -25,6 +29,15 @@ func NewAstGrepProvider() *AstGrepProvider {
 	}
 }
 
+// WithIndex makes the provider annotate pattern matches with their enclosing
+// function/type and file metadata from db, and enables the index filters
+// (exclude_tests, exported_only, module_path). Pattern searches and lint runs
+// then also cover the content sources recorded in db. Returns p for chaining.
+func (p *AstGrepProvider) WithIndex(db *sql.DB) *AstGrepProvider {
+	p.db = db
+	return p
+}
+
 // ensureBinaryInstalled ensures the ast-grep binary is installed and ready to use.
 // It uses lazy initialization: only downloads on first call, then returns cached path.
 // Thread-safe: multiple concurrent calls will only download once.
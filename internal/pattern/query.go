@@ -0,0 +1,321 @@
+package pattern
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unsafe"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gobwas/glob"
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	sitter "github.com/tree-sitter/go-tree-sitter"
+	c "github.com/tree-sitter/tree-sitter-c/bindings/go"
+	cpp "github.com/tree-sitter/tree-sitter-cpp/bindings/go"
+	java "github.com/tree-sitter/tree-sitter-java/bindings/go"
+	php "github.com/tree-sitter/tree-sitter-php/bindings/go"
+	python "github.com/tree-sitter/tree-sitter-python/bindings/go"
+	ruby "github.com/tree-sitter/tree-sitter-ruby/bindings/go"
+	rust "github.com/tree-sitter/tree-sitter-rust/bindings/go"
+	typescript "github.com/tree-sitter/tree-sitter-typescript/bindings/go"
+)
+
+// queryGrammar maps a cortex_query language to its linked tree-sitter grammar
+// and the file extensions it applies to.
+type queryGrammar struct {
+	language   func() unsafe.Pointer
+	extensions []string
+}
+
+// queryGrammars lists the grammars linked into the binary (the same ones the
+// indexer parses with). Go is indexed with go/ast, so it has no grammar here.
+var queryGrammars = map[string]queryGrammar{
+	"typescript": {typescript.LanguageTypescript, []string{".ts", ".mts", ".cts"}},
+	"tsx":        {typescript.LanguageTSX, []string{".tsx"}},
+	"javascript": {typescript.LanguageTSX, []string{".js", ".mjs", ".cjs"}},
+	"jsx":        {typescript.LanguageTSX, []string{".jsx"}},
+	"python":     {python.Language, []string{".py"}},
+	"rust":       {rust.Language, []string{".rs"}},
+	"c":          {c.Language, []string{".c", ".h"}},
+	"cpp":        {cpp.Language, []string{".cpp", ".cc", ".cxx", ".hpp", ".hh", ".hxx"}},
+	"java":       {java.Language, []string{".java"}},
+	"php":        {php.LanguagePHP, []string{".php"}},
+	"ruby":       {ruby.Language, []string{".rb"}},
+}
+
+// QueryLanguages returns the languages cortex_query supports, sorted.
+func QueryLanguages() []string {
+	langs := make([]string, 0, len(queryGrammars))
+	for lang := range queryGrammars {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// ValidateQueryRequest validates a QueryRequest and returns an error if invalid
+func ValidateQueryRequest(req *QueryRequest) error {
+	if req == nil {
+		return errors.New("request cannot be nil")
+	}
+
+	if req.Query == "" {
+		return errors.New("query is required")
+	}
+	if req.Language == "" {
+		return errors.New("language is required")
+	}
+
+	if _, ok := queryGrammars[req.Language]; !ok {
+		if req.Language == "go" {
+			return errors.New("unsupported language: go (no tree-sitter grammar is linked for Go, use cortex_pattern)")
+		}
+		return fmt.Errorf("unsupported language: %s (supported: %s)", req.Language, strings.Join(QueryLanguages(), ", "))
+	}
+
+	if req.ContextLines != nil {
+		if *req.ContextLines < MinContextLines || *req.ContextLines > MaxContextLines {
+			return fmt.Errorf("context_lines must be between %d and %d", MinContextLines, MaxContextLines)
+		}
+	}
+
+	if req.Limit != nil {
+		if *req.Limit < MinLimit || *req.Limit > MaxLimit {
+			return fmt.Errorf("limit must be between %d and %d", MinLimit, MaxLimit)
+		}
+	}
+
+	for _, path := range req.FilePaths {
+		if filepath.IsAbs(path) || strings.Contains(path, "..") {
+			return fmt.Errorf("path outside project root: %s", path)
+		}
+	}
+
+	return validateLabel(req.Label)
+}
+
+// TreeSitterQuerier runs tree-sitter queries in-process over the file contents
+// stored in the index. It implements QuerySearcher.
+type TreeSitterQuerier struct {
+	db *sql.DB
+}
+
+// NewTreeSitterQuerier creates a querier reading indexed files from db.
+func NewTreeSitterQuerier(db *sql.DB) *TreeSitterQuerier {
+	return &TreeSitterQuerier{db: db}
+}
+
+// Query implements the QuerySearcher interface.
+//
+// Process:
+// 1. Validate request and compile the query (syntax and predicate errors are user errors)
+// 2. Stream indexed files matching the language's extensions, label and file_paths globs
+// 3. Parse each file and collect matches with their captures
+// 4. Apply result limiting (Total keeps the full count)
+//
+// Predicates (#eq?, #match?, #any-of? and their negations) are evaluated by
+// tree-sitter. Captures whose name starts with "_" are used for predicates only
+// and are left out of the returned captures.
+func (q *TreeSitterQuerier) Query(ctx context.Context, req *QueryRequest) (*QueryResponse, error) {
+	if err := ValidateQueryRequest(req); err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+
+	grammar := queryGrammars[req.Language]
+	language := sitter.NewLanguage(grammar.language())
+
+	query, queryErr := sitter.NewQuery(language, req.Query)
+	if queryErr != nil {
+		return nil, fmt.Errorf("invalid query: %s", queryErr.Error())
+	}
+	defer query.Close()
+
+	globs := make([]glob.Glob, 0, len(req.FilePaths))
+	for _, pattern := range req.FilePaths {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, fmt.Errorf("invalid file_paths glob %q: %w", pattern, err)
+		}
+		globs = append(globs, g)
+	}
+
+	execCtx, cancel := context.WithTimeout(ctx, ExecutionTimeout)
+	defer cancel()
+
+	startTime := time.Now()
+
+	extensions := sq.Or{}
+	for _, ext := range grammar.extensions {
+		extensions = append(extensions, sq.Like{"file_path": "%" + ext})
+	}
+
+	filesQuery := sq.Select("file_path", "content").
+		From("files").
+		Where(sq.NotEq{"content": nil}).
+		Where(extensions)
+	if req.Label != "" {
+		filesQuery = filesQuery.Where(storage.LabelFilter("file_path", req.Label))
+	}
+
+	// Read from a snapshot so the reported generation matches the files
+	tx, generation, err := storage.BeginSnapshot(execCtx, q.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := filesQuery.
+		OrderBy("file_path").
+		RunWith(tx).
+		QueryContext(execCtx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load indexed files: %w", err)
+	}
+	defer rows.Close()
+
+	parser := sitter.NewParser()
+	defer parser.Close()
+	if err := parser.SetLanguage(language); err != nil {
+		return nil, fmt.Errorf("failed to set %s grammar: %w", req.Language, err)
+	}
+
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+
+	contextLines := DefaultContextLines
+	if req.ContextLines != nil {
+		contextLines = *req.ContextLines
+	}
+
+	matches := []PatternMatch{}
+	filesScanned := 0
+	for rows.Next() {
+		var filePath, content string
+		if err := rows.Scan(&filePath, &content); err != nil {
+			return nil, fmt.Errorf("failed to scan indexed file: %w", err)
+		}
+		if !matchesAnyGlob(globs, filePath) {
+			continue
+		}
+
+		filesScanned++
+		matches = append(matches, queryFile(parser, cursor, query, filePath, []byte(content), contextLines)...)
+	}
+	if err := rows.Err(); err != nil {
+		if execCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("query search timed out (30s)")
+		}
+		return nil, fmt.Errorf("failed to read indexed files: %w", err)
+	}
+
+	response := &QueryResponse{
+		Matches: matches,
+		Total:   len(matches),
+		Metadata: QueryMetadata{
+			TookMs:       time.Since(startTime).Milliseconds(),
+			Query:        req.Query,
+			Language:     req.Language,
+			FilesScanned: filesScanned,
+			Generation:   generation,
+		},
+	}
+
+	limit := DefaultLimit
+	if req.Limit != nil {
+		limit = *req.Limit
+	}
+	if len(response.Matches) > limit {
+		response.Matches = response.Matches[:limit]
+	}
+
+	return response, nil
+}
+
+// queryFile runs query over one file's source and converts each match to a
+// PatternMatch spanning all of its captured nodes.
+func queryFile(parser *sitter.Parser, cursor *sitter.QueryCursor, query *sitter.Query, filePath string, source []byte, contextLines int) []PatternMatch {
+	tree := parser.Parse(source, nil)
+	if tree == nil {
+		return nil
+	}
+	defer tree.Close()
+
+	captureNames := query.CaptureNames()
+	var lines []string
+	var matches []PatternMatch
+
+	queryMatches := cursor.Matches(query, tree.RootNode(), source)
+	for match := queryMatches.Next(); match != nil; match = queryMatches.Next() {
+		if len(match.Captures) == 0 {
+			continue
+		}
+
+		startByte, endByte := ^uint(0), uint(0)
+		startRow, endRow := ^uint(0), uint(0)
+		captures := make(map[string]string)
+
+		for _, capture := range match.Captures {
+			node := capture.Node
+			startByte = min(startByte, node.StartByte())
+			endByte = max(endByte, node.EndByte())
+			startRow = min(startRow, node.StartPosition().Row)
+			endRow = max(endRow, node.EndPosition().Row)
+
+			name := captureNames[capture.Index]
+			if strings.HasPrefix(name, "_") {
+				continue
+			}
+			text := string(source[node.StartByte():node.EndByte()])
+			if prev, ok := captures[name]; ok {
+				// Quantified captures (@arg)* hold several nodes
+				text = prev + "\n" + text
+			}
+			captures[name] = text
+		}
+
+		if lines == nil {
+			lines = strings.Split(string(source), "\n")
+		}
+
+		startLine := int(startRow) + 1
+		endLine := int(endRow) + 1
+		matches = append(matches, PatternMatch{
+			FilePath:  filePath,
+			StartLine: startLine,
+			EndLine:   endLine,
+			MatchText: string(source[startByte:endByte]),
+			Context:   contextWindow(lines, startLine, endLine, contextLines),
+			Metavars:  captures,
+		})
+	}
+
+	return matches
+}
+
+// contextWindow returns lines [startLine-n, endLine+n] (1-indexed, clamped).
+func contextWindow(lines []string, startLine, endLine, n int) string {
+	from := max(startLine-n, 1)
+	to := min(endLine+n, len(lines))
+	if from > to {
+		return ""
+	}
+	return strings.Join(lines[from-1:to], "\n")
+}
+
+// matchesAnyGlob reports whether path matches one of globs (all paths match when empty).
+func matchesAnyGlob(globs []glob.Glob, path string) bool {
+	if len(globs) == 0 {
+		return true
+	}
+	for _, g := range globs {
+		if g.Match(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,271 @@
+package pattern
+
+// Test Plan for TreeSitterQuerier:
+// - Query returns matches with captures, file path and 1-indexed lines
+// - Query reports the index generation it read the files from
+// - Predicates (#eq?, #match?) filter matches
+// - Captures prefixed with "_" are excluded from Metavars
+// - Only files with the language's extensions are scanned
+// - file_paths globs restrict scanned files
+// - Limit truncates Matches while Total keeps the full count
+// - Context includes surrounding lines
+// - Invalid query syntax returns an "invalid query" error
+// - Unsupported language (including go) returns an error
+// - ValidateQueryRequest rejects out-of-range limits and paths outside the project
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const queryTestPython = `import os
+
+
+def test_alpha():
+    assert True
+
+
+def helper(value):
+    return value * 2
+
+
+def test_beta():
+    helper(1)
+`
+
+func TestTreeSitterQuerier_Captures(t *testing.T) {
+	t.Parallel()
+
+	querier := setupQueryTestDB(t, map[string]string{
+		"tests/test_sample.py": queryTestPython,
+	})
+
+	resp, err := querier.Query(context.Background(), &QueryRequest{
+		Query:    `(function_definition name: (identifier) @name parameters: (parameters) @params)`,
+		Language: "python",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, 3, resp.Total)
+	require.Len(t, resp.Matches, 3)
+	assert.Equal(t, 1, resp.Metadata.FilesScanned)
+	assert.Equal(t, int64(1), resp.Metadata.Generation)
+
+	first := resp.Matches[0]
+	assert.Equal(t, "tests/test_sample.py", first.FilePath)
+	assert.Equal(t, 4, first.StartLine)
+	assert.Equal(t, 4, first.EndLine)
+	assert.Equal(t, "test_alpha", first.Metavars["name"])
+	assert.Equal(t, "()", first.Metavars["params"])
+	assert.Equal(t, "test_alpha()", first.MatchText)
+
+	assert.Equal(t, "helper", resp.Matches[1].Metavars["name"])
+	assert.Equal(t, "(value)", resp.Matches[1].Metavars["params"])
+}
+
+func TestTreeSitterQuerier_Predicates(t *testing.T) {
+	t.Parallel()
+
+	querier := setupQueryTestDB(t, map[string]string{
+		"tests/test_sample.py": queryTestPython,
+	})
+
+	t.Run("match predicate", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(function_definition name: (identifier) @name (#match? @name "^test_"))`,
+			Language: "python",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Matches, 2)
+		assert.Equal(t, "test_alpha", resp.Matches[0].Metavars["name"])
+		assert.Equal(t, "test_beta", resp.Matches[1].Metavars["name"])
+	})
+
+	t.Run("eq predicate with private capture", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(call function: (identifier) @_fn arguments: (argument_list) @args (#eq? @_fn "helper"))`,
+			Language: "python",
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Matches, 1)
+
+		match := resp.Matches[0]
+		assert.Equal(t, 13, match.StartLine)
+		assert.Equal(t, "helper(1)", match.MatchText)
+		assert.Equal(t, map[string]string{"args": "(1)"}, match.Metavars)
+	})
+}
+
+func TestTreeSitterQuerier_FileSelection(t *testing.T) {
+	t.Parallel()
+
+	querier := setupQueryTestDB(t, map[string]string{
+		"src/app.py":      "def app():\n    pass\n",
+		"tests/test_a.py": "def test_a():\n    pass\n",
+		"src/app.rb":      "def app\nend\n",
+		"docs/README.md":  "def not_code():\n",
+	})
+
+	t.Run("language extensions", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(function_definition name: (identifier) @name)`,
+			Language: "python",
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 2, resp.Total)
+		assert.Equal(t, 2, resp.Metadata.FilesScanned)
+	})
+
+	t.Run("file_paths globs", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := querier.Query(context.Background(), &QueryRequest{
+			Query:     `(function_definition name: (identifier) @name)`,
+			Language:  "python",
+			FilePaths: []string{"src/**"},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Matches, 1)
+		assert.Equal(t, "src/app.py", resp.Matches[0].FilePath)
+	})
+}
+
+func TestTreeSitterQuerier_LimitAndContext(t *testing.T) {
+	t.Parallel()
+
+	querier := setupQueryTestDB(t, map[string]string{
+		"tests/test_sample.py": queryTestPython,
+	})
+
+	limit := 1
+	contextLines := 1
+	resp, err := querier.Query(context.Background(), &QueryRequest{
+		Query:        `(function_definition name: (identifier) @name)`,
+		Language:     "python",
+		Limit:        &limit,
+		ContextLines: &contextLines,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, resp.Total, "total should reflect all matches")
+	require.Len(t, resp.Matches, 1)
+	assert.Equal(t, "\ndef test_alpha():\n    assert True", resp.Matches[0].Context)
+}
+
+func TestTreeSitterQuerier_Errors(t *testing.T) {
+	t.Parallel()
+
+	querier := setupQueryTestDB(t, nil)
+
+	t.Run("invalid query syntax", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(function_definition name: `,
+			Language: "python",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid query")
+	})
+
+	t.Run("unknown node type", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(no_such_node) @x`,
+			Language: "python",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid query")
+	})
+
+	t.Run("go has no grammar", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := querier.Query(context.Background(), &QueryRequest{
+			Query:    `(identifier) @x`,
+			Language: "go",
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unsupported language: go")
+		assert.Contains(t, err.Error(), "cortex_pattern")
+	})
+}
+
+func TestValidateQueryRequest(t *testing.T) {
+	t.Parallel()
+
+	zero := 0
+	tooMany := 11
+
+	tests := []struct {
+		name    string
+		req     *QueryRequest
+		wantErr string
+	}{
+		{"valid", &QueryRequest{Query: "(x)", Language: "rust"}, ""},
+		{"nil", nil, "request cannot be nil"},
+		{"missing query", &QueryRequest{Language: "rust"}, "query is required"},
+		{"missing language", &QueryRequest{Query: "(x)"}, "language is required"},
+		{"unknown language", &QueryRequest{Query: "(x)", Language: "cobol"}, "unsupported language: cobol"},
+		{"limit too small", &QueryRequest{Query: "(x)", Language: "rust", Limit: &zero}, "limit must be between"},
+		{"context too large", &QueryRequest{Query: "(x)", Language: "rust", ContextLines: &tooMany}, "context_lines must be between"},
+		{"absolute path", &QueryRequest{Query: "(x)", Language: "rust", FilePaths: []string{"/etc/**"}}, "outside project root"},
+		{"parent path", &QueryRequest{Query: "(x)", Language: "rust", FilePaths: []string{"../**"}}, "outside project root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateQueryRequest(tt.req)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+// setupQueryTestDB creates an index database containing files (path → content),
+// committed as generation 1.
+func setupQueryTestDB(t *testing.T, files map[string]string) *TreeSitterQuerier {
+	t.Helper()
+
+	db := storage.NewTestDB(t)
+	// Single connection: each new in-memory connection would be an empty database
+	db.SetMaxOpenConns(1)
+
+	gen, err := storage.BeginGeneration(context.Background(), db)
+	require.NoError(t, err)
+
+	writer := storage.NewFileWriter(db)
+	now := time.Now()
+	for path, content := range files {
+		content := content
+		err := writer.WriteFile(&storage.FileStats{
+			FilePath:     path,
+			Language:     "unknown",
+			FileHash:     "hash-" + path,
+			LastModified: now,
+			IndexedAt:    now,
+		}, &content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, gen.Commit())
+
+	return NewTreeSitterQuerier(db)
+}
@@ -0,0 +1,146 @@
+package pattern
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// DiffContextLines is the number of unchanged lines around each diff hunk.
+const DiffContextLines = 3
+
+// buildRewriteDiffs turns ast-grep rewrite matches into one unified diff per file.
+// Replacements are applied in memory to the current file contents; nothing is
+// written to disk. Files are returned sorted by path.
+//
+// Overlapping replacements (a match nested inside another rewritten match) keep
+// the outermost one, matching ast-grep's own --update-all behavior.
+func buildRewriteDiffs(projectRoot string, matches []AstGrepMatch) ([]FileDiff, error) {
+	byFile := make(map[string][]AstGrepMatch)
+	for _, match := range matches {
+		if match.Replacement == nil {
+			continue
+		}
+		byFile[match.File] = append(byFile[match.File], match)
+	}
+
+	paths := make([]string, 0, len(byFile))
+	for path := range byFile {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	diffs := make([]FileDiff, 0, len(paths))
+	for _, path := range paths {
+		original, err := os.ReadFile(filepath.Join(projectRoot, path))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		rewritten, count, err := applyReplacements(string(original), byFile[path])
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewrite %s: %w", path, err)
+		}
+		if rewritten == string(original) {
+			continue
+		}
+
+		diff, err := unifiedDiff(path, string(original), rewritten)
+		if err != nil {
+			return nil, fmt.Errorf("failed to diff %s: %w", path, err)
+		}
+
+		diffs = append(diffs, FileDiff{
+			FilePath:     filepath.ToSlash(path),
+			Replacements: count,
+			Diff:         diff,
+		})
+	}
+
+	return diffs, nil
+}
+
+// applyReplacements applies each match's replacement to source and returns the
+// result with the number of replacements applied.
+func applyReplacements(source string, matches []AstGrepMatch) (string, int, error) {
+	type edit struct {
+		start, end  int
+		replacement string
+	}
+
+	edits := make([]edit, 0, len(matches))
+	for _, match := range matches {
+		span := match.Range.ByteOffset
+		if match.ReplacementOffsets != nil {
+			span = *match.ReplacementOffsets
+		}
+		if span.Start < 0 || span.End < span.Start || span.End > len(source) {
+			return "", 0, fmt.Errorf("replacement range %d-%d outside file (file changed since the search?)", span.Start, span.End)
+		}
+		edits = append(edits, edit{start: span.Start, end: span.End, replacement: *match.Replacement})
+	}
+
+	// Outermost first: earliest start, then longest span
+	sort.Slice(edits, func(i, j int) bool {
+		if edits[i].start != edits[j].start {
+			return edits[i].start < edits[j].start
+		}
+		return edits[i].end > edits[j].end
+	})
+
+	var b strings.Builder
+	cursor, applied := 0, 0
+	for _, e := range edits {
+		if e.start < cursor {
+			continue // Nested in (or overlapping) an edit already applied
+		}
+		b.WriteString(source[cursor:e.start])
+		b.WriteString(e.replacement)
+		cursor = e.end
+		applied++
+	}
+	b.WriteString(source[cursor:])
+
+	return b.String(), applied, nil
+}
+
+// unifiedDiff renders a git-style unified diff (a/ and b/ prefixes) for one file.
+func unifiedDiff(path, original, rewritten string) (string, error) {
+	path = filepath.ToSlash(path)
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(original),
+		B:        splitLines(rewritten),
+		FromFile: "a/" + path,
+		ToFile:   "b/" + path,
+		Context:  DiffContextLines,
+	})
+}
+
+// splitLines splits s into lines that each end in "\n".
+// A missing final newline is added so diff lines stay well-formed; ApplyPatch
+// keeps the file's original end-of-file state unless the diff marks it.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	} else {
+		lines[len(lines)-1] += "\n"
+	}
+	return lines
+}
+
+// joinDiffs concatenates per-file diffs into a single patch.
+func joinDiffs(diffs []FileDiff) string {
+	var b strings.Builder
+	for _, d := range diffs {
+		b.WriteString(d.Diff)
+	}
+	return b.String()
+}
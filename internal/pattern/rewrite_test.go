@@ -0,0 +1,295 @@
+package pattern
+
+// Test Plan for Rewrite Diffs and ApplyPatch:
+// - buildRewriteDiffs produces one git-style unified diff per file, sorted by path
+// - buildRewriteDiffs never modifies files on disk
+// - Nested/overlapping replacements keep the outermost edit
+// - Replacement ranges outside the file are rejected
+// - Files without a trailing newline round-trip through diff + apply unchanged at EOF
+// - ApplyPatch follows "\ No newline at end of file" markers when adding or removing the final newline
+// - ApplyPatch applies a rewrite patch across several files
+// - ApplyPatch rejects stale hunks and leaves every file untouched
+// - ApplyPatch rejects paths outside the project root (validateFilePath)
+// - ApplyPatch rejects file creation/deletion and malformed patches
+// - BuildCommand adds --rewrite (and never --update-all) in rewrite mode
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildRewriteDiffs(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	mainSrc := "package main\n\nfunc main() {\n\tdefer conn.Close()\n\tdefer file.Close()\n}\n"
+	utilSrc := "package main\n\nfunc util() {\n\tdefer db.Close()\n}\n"
+	writeRewriteFile(t, root, "main.go", mainSrc)
+	writeRewriteFile(t, root, "pkg/util.go", utilSrc)
+
+	matches := []AstGrepMatch{
+		rewriteMatch("pkg/util.go", utilSrc, "db.Close()", "closeQuietly(db)"),
+		rewriteMatch("main.go", mainSrc, "conn.Close()", "closeQuietly(conn)"),
+		rewriteMatch("main.go", mainSrc, "file.Close()", "closeQuietly(file)"),
+	}
+
+	diffs, err := buildRewriteDiffs(root, matches)
+	require.NoError(t, err)
+	require.Len(t, diffs, 2)
+
+	assert.Equal(t, "main.go", diffs[0].FilePath)
+	assert.Equal(t, 2, diffs[0].Replacements)
+	assert.Equal(t, "--- a/main.go\n"+
+		"+++ b/main.go\n"+
+		"@@ -1,6 +1,6 @@\n"+
+		" package main\n"+
+		" \n"+
+		" func main() {\n"+
+		"-\tdefer conn.Close()\n"+
+		"-\tdefer file.Close()\n"+
+		"+\tdefer closeQuietly(conn)\n"+
+		"+\tdefer closeQuietly(file)\n"+
+		" }\n", diffs[0].Diff)
+
+	assert.Equal(t, "pkg/util.go", diffs[1].FilePath)
+	assert.Contains(t, diffs[1].Diff, "+\tdefer closeQuietly(db)\n")
+
+	// Files on disk are untouched
+	assert.Equal(t, mainSrc, readRewriteFile(t, root, "main.go"))
+	assert.Equal(t, utilSrc, readRewriteFile(t, root, "pkg/util.go"))
+}
+
+func TestApplyReplacements(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested match keeps outermost", func(t *testing.T) {
+		t.Parallel()
+
+		src := "f(g(x))"
+		matches := []AstGrepMatch{
+			rewriteMatch("a.go", src, "g(x)", "h(x)"),
+			rewriteMatch("a.go", src, "f(g(x))", "k(g(x))"),
+		}
+
+		out, count, err := applyReplacements(src, matches)
+		require.NoError(t, err)
+		assert.Equal(t, "k(g(x))", out)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("range outside file", func(t *testing.T) {
+		t.Parallel()
+
+		replacement := "x"
+		_, _, err := applyReplacements("short", []AstGrepMatch{{
+			Replacement:        &replacement,
+			ReplacementOffsets: &AstGrepByteRange{Start: 2, End: 50},
+		}})
+		assert.Error(t, err)
+	})
+}
+
+func TestRewriteRoundTrip_NoTrailingNewline(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	src := "a := old()\nb := old()"
+	writeRewriteFile(t, root, "x.go", src)
+
+	diffs, err := buildRewriteDiffs(root, []AstGrepMatch{
+		rewriteMatchAt("x.go", strings.LastIndex(src, "old()"), "old()", "new()"),
+	})
+	require.NoError(t, err)
+	require.Len(t, diffs, 1)
+
+	_, err = ApplyPatch(root, joinDiffs(diffs))
+	require.NoError(t, err)
+	assert.Equal(t, "a := old()\nb := new()", readRewriteFile(t, root, "x.go"))
+}
+
+func TestApplyPatch(t *testing.T) {
+	t.Parallel()
+
+	t.Run("applies multi-file patch", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+		aSrc := "one\ntwo\nthree\n"
+		bSrc := "alpha\nbeta\n"
+		writeRewriteFile(t, root, "a.txt", aSrc)
+		writeRewriteFile(t, root, "dir/b.txt", bSrc)
+		require.NoError(t, os.Chmod(filepath.Join(root, "a.txt"), 0755))
+
+		diffs, err := buildRewriteDiffs(root, []AstGrepMatch{
+			rewriteMatch("a.txt", aSrc, "two", "TWO"),
+			rewriteMatch("dir/b.txt", bSrc, "beta", "BETA"),
+		})
+		require.NoError(t, err)
+
+		result, err := ApplyPatch(root, "diff --git a/a.txt b/a.txt\n"+joinDiffs(diffs))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a.txt", "dir/b.txt"}, result.FilesChanged)
+		assert.Equal(t, 2, result.HunksApplied)
+
+		assert.Equal(t, "one\nTWO\nthree\n", readRewriteFile(t, root, "a.txt"))
+		assert.Equal(t, "alpha\nBETA\n", readRewriteFile(t, root, "dir/b.txt"))
+
+		info, err := os.Stat(filepath.Join(root, "a.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm(), "file mode preserved")
+
+		entries, err := os.ReadDir(root)
+		require.NoError(t, err)
+		for _, e := range entries {
+			assert.NotContains(t, e.Name(), "cortex-apply", "temp files cleaned up")
+		}
+	})
+
+	t.Run("stale hunk leaves all files untouched", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+		aSrc := "one\ntwo\n"
+		bSrc := "alpha\nbeta\n"
+		writeRewriteFile(t, root, "a.txt", aSrc)
+		writeRewriteFile(t, root, "b.txt", bSrc)
+
+		diffs, err := buildRewriteDiffs(root, []AstGrepMatch{
+			rewriteMatch("a.txt", aSrc, "two", "TWO"),
+			rewriteMatch("b.txt", bSrc, "beta", "BETA"),
+		})
+		require.NoError(t, err)
+
+		// b.txt changes after the diff was generated
+		writeRewriteFile(t, root, "b.txt", "alpha\ngamma\n")
+
+		_, err = ApplyPatch(root, joinDiffs(diffs))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid patch")
+		assert.Contains(t, err.Error(), "b.txt")
+
+		assert.Equal(t, aSrc, readRewriteFile(t, root, "a.txt"), "earlier file must not be modified")
+	})
+
+	t.Run("no newline at end of file markers", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+		writeRewriteFile(t, root, "add.txt", "one\ntwo")
+		writeRewriteFile(t, root, "remove.txt", "one\ntwo\n")
+		writeRewriteFile(t, root, "keep.txt", "one\ntwo")
+		writeRewriteFile(t, root, "stale.txt", "one\ntwo\n")
+
+		patch := "--- a/add.txt\n+++ b/add.txt\n@@ -1,2 +1,2 @@\n one\n-two\n\\ No newline at end of file\n+TWO\n" +
+			"--- a/remove.txt\n+++ b/remove.txt\n@@ -1,2 +1,2 @@\n one\n-two\n+TWO\n\\ No newline at end of file\n" +
+			"--- a/keep.txt\n+++ b/keep.txt\n@@ -1,2 +1,2 @@\n-one\n+ONE\n two\n\\ No newline at end of file\n"
+		_, err := ApplyPatch(root, patch)
+		require.NoError(t, err)
+		assert.Equal(t, "one\nTWO\n", readRewriteFile(t, root, "add.txt"))
+		assert.Equal(t, "one\nTWO", readRewriteFile(t, root, "remove.txt"))
+		assert.Equal(t, "ONE\ntwo", readRewriteFile(t, root, "keep.txt"))
+
+		// The marker claims no final newline, but the file has one
+		_, err = ApplyPatch(root, "--- a/stale.txt\n+++ b/stale.txt\n@@ -1,2 +1,2 @@\n one\n-two\n\\ No newline at end of file\n+TWO\n")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "end of the file")
+		assert.Equal(t, "one\ntwo\n", readRewriteFile(t, root, "stale.txt"))
+	})
+
+	t.Run("path outside project root", func(t *testing.T) {
+		t.Parallel()
+
+		root := t.TempDir()
+		patch := "--- a/../escape.txt\n+++ b/../escape.txt\n@@ -1 +1 @@\n-x\n+y\n"
+
+		_, err := ApplyPatch(root, patch)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "outside project root")
+	})
+
+	t.Run("file creation not supported", func(t *testing.T) {
+		t.Parallel()
+
+		patch := "--- /dev/null\n+++ b/new.txt\n@@ -0,0 +1 @@\n+x\n"
+
+		_, err := ApplyPatch(t.TempDir(), patch)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "not supported")
+	})
+
+	t.Run("malformed patches", func(t *testing.T) {
+		t.Parallel()
+
+		for _, patch := range []string{
+			"",
+			"just some text\n",
+			"--- a/x.txt\n+++ b/x.txt\n",
+			"--- a/x.txt\n+++ b/x.txt\n@@ -1,2 +1,2 @@\n-x\n",
+			"--- a/x.txt\n+++ b/x.txt\n@@ bogus @@\n",
+		} {
+			_, err := ApplyPatch(t.TempDir(), patch)
+			require.Error(t, err, "patch %q", patch)
+			assert.Contains(t, err.Error(), "invalid patch")
+		}
+	})
+
+	t.Run("relative project root", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ApplyPatch("relative", "--- a/x\n+++ b/x\n@@ -1 +1 @@\n-x\n+y\n")
+		assert.Error(t, err)
+	})
+}
+
+func TestBuildCommand_Rewrite(t *testing.T) {
+	t.Parallel()
+
+	req := &PatternRequest{
+		Pattern:  "defer $X.Close()",
+		Language: "go",
+		Rewrite:  "defer closeQuietly($X)",
+	}
+
+	args, err := BuildCommand(req, "/project")
+	require.NoError(t, err)
+
+	assert.Contains(t, strings.Join(args, " "), "--rewrite defer closeQuietly($X)")
+	assert.NotContains(t, args, "--update-all")
+	assert.NotContains(t, args, "-U")
+}
+
+// rewriteMatch builds an ast-grep rewrite match for the first occurrence of text in src.
+func rewriteMatch(file, src, text, replacement string) AstGrepMatch {
+	return rewriteMatchAt(file, strings.Index(src, text), text, replacement)
+}
+
+func rewriteMatchAt(file string, offset int, text, replacement string) AstGrepMatch {
+	return AstGrepMatch{
+		File: file,
+		Text: text,
+		Range: AstGrepRange{
+			ByteOffset: AstGrepByteRange{Start: offset, End: offset + len(text)},
+		},
+		Replacement:        &replacement,
+		ReplacementOffsets: &AstGrepByteRange{Start: offset, End: offset + len(text)},
+	}
+}
+
+func writeRewriteFile(t *testing.T, root, rel, content string) {
+	t.Helper()
+	path := filepath.Join(root, rel)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func readRewriteFile(t *testing.T, root, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(root, rel))
+	require.NoError(t, err)
+	return string(data)
+}
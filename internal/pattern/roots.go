@@ -0,0 +1,80 @@
+package pattern
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+)
+
+// searchRoot is a directory ast-grep runs in, with the prefix of the stored
+// paths of its files.
+type searchRoot struct {
+	dir    string
+	prefix string // "" for the project, "@<label>/" for a content source
+}
+
+// validateLabel checks a label filter ("" means no filter).
+func validateLabel(label string) error {
+	if label != "" && label != storage.ProjectLabel && !storage.ValidContentSourceLabel(label) {
+		return fmt.Errorf("invalid label %q: must be a content source label or %s", label, storage.ProjectLabel)
+	}
+	return nil
+}
+
+// searchRoots returns the directories a label filter covers: the project for
+// "project", one content source for its label, and the project plus every
+// content source for no label. Content sources are read from the index's
+// content_sources table, so without an index only the project is searched.
+// Sources whose root is missing on this machine are skipped.
+func searchRoots(db *sql.DB, label, projectRoot string) ([]searchRoot, error) {
+	var roots []searchRoot
+	if label == "" || label == storage.ProjectLabel {
+		roots = append(roots, searchRoot{dir: projectRoot})
+	}
+	if label == storage.ProjectLabel {
+		return roots, nil
+	}
+	if db == nil {
+		if label != "" {
+			return nil, fmt.Errorf("invalid request: label needs an index")
+		}
+		return roots, nil
+	}
+
+	sources, err := storage.ListContentSources(db)
+	if err != nil {
+		return nil, err
+	}
+	for _, src := range sources {
+		if label != "" && src.Label != label {
+			continue
+		}
+		if info, err := os.Stat(src.Root); err != nil || !info.IsDir() {
+			continue
+		}
+		roots = append(roots, searchRoot{dir: src.Root, prefix: storage.ContentSourcePrefix(src.Label)})
+	}
+	return roots, nil
+}
+
+// filePaths returns the file and glob filters that apply under the root,
+// relative to it: the project's are the filters not starting with "@", a
+// content source's the ones starting with its "@<label>/" prefix. ok is
+// false when filters are set but none apply, so the root isn't searched.
+func (r searchRoot) filePaths(paths []string) (rel []string, ok bool) {
+	if len(paths) == 0 {
+		return nil, true
+	}
+	for _, path := range paths {
+		switch {
+		case r.prefix == "" && !strings.HasPrefix(path, "@"):
+			rel = append(rel, path)
+		case r.prefix != "" && strings.HasPrefix(path, r.prefix):
+			rel = append(rel, strings.TrimPrefix(path, r.prefix))
+		}
+	}
+	return rel, len(rel) > 0
+}
@@ -0,0 +1,82 @@
+package pattern
+
+// Test Plan for Search Roots:
+// - Without a label the project and every recorded content source are searched
+// - "project" selects the project, a source label that source only
+// - Without an index only the project is searched; a source label is rejected
+// - Sources whose root is missing are skipped
+// - File filters apply to the project unless they start with a source's "@<label>/" prefix
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchRoots(t *testing.T) {
+	t.Parallel()
+
+	projectRoot, wikiRoot := t.TempDir(), t.TempDir()
+	db := storage.NewTestDB(t)
+	require.NoError(t, storage.WithTx(db, func(tx *sql.Tx) error {
+		return storage.ReplaceContentSources(tx, []storage.ContentSource{
+			{Label: "wiki", Root: wikiRoot},
+			{Label: "gone", Root: filepath.Join(wikiRoot, "missing")},
+		})
+	}))
+
+	project := searchRoot{dir: projectRoot}
+	wiki := searchRoot{dir: wikiRoot, prefix: "@wiki/"}
+
+	roots, err := searchRoots(db, "", projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []searchRoot{project, wiki}, roots)
+
+	roots, err = searchRoots(db, "project", projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []searchRoot{project}, roots)
+
+	roots, err = searchRoots(db, "wiki", projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []searchRoot{wiki}, roots)
+
+	roots, err = searchRoots(db, "specs", projectRoot)
+	require.NoError(t, err)
+	assert.Empty(t, roots)
+
+	roots, err = searchRoots(nil, "", projectRoot)
+	require.NoError(t, err)
+	assert.Equal(t, []searchRoot{project}, roots)
+
+	_, err = searchRoots(nil, "wiki", projectRoot)
+	assert.ErrorContains(t, err, "invalid request")
+}
+
+func TestSearchRoot_FilePaths(t *testing.T) {
+	t.Parallel()
+
+	project := searchRoot{dir: "/src/app"}
+	wiki := searchRoot{dir: "/src/wiki", prefix: "@wiki/"}
+
+	paths, ok := project.filePaths(nil)
+	assert.True(t, ok)
+	assert.Nil(t, paths)
+	paths, ok = wiki.filePaths(nil)
+	assert.True(t, ok)
+	assert.Nil(t, paths)
+
+	filters := []string{"internal/**/*.go", "@wiki/tools/**"}
+	paths, ok = project.filePaths(filters)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"internal/**/*.go"}, paths)
+	paths, ok = wiki.filePaths(filters)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"tools/**"}, paths)
+
+	_, ok = wiki.filePaths([]string{"internal/**/*.go"})
+	assert.False(t, ok)
+}
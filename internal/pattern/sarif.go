@@ -0,0 +1,151 @@
+package pattern
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// sarifLog is the subset of SARIF 2.1.0 needed to report lint violations
+// to code scanning tools (GitHub code scanning, IDE SARIF viewers).
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           sarifRegion   `json:"region"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// sarifLevel maps ast-grep severities to SARIF result levels.
+func sarifLevel(severity string) string {
+	switch severity {
+	case "error":
+		return "error"
+	case "warning":
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// FormatSARIF renders lint violations as an indented SARIF 2.1.0 log.
+// Rules are listed in id order; the first violation of each rule supplies its description.
+func FormatSARIF(violations []LintViolation) ([]byte, error) {
+	ruleIndex := make(map[string]int)
+	var rules []sarifRule
+	for _, v := range violations {
+		if _, ok := ruleIndex[v.RuleID]; ok {
+			continue
+		}
+		ruleIndex[v.RuleID] = -1
+		rules = append(rules, sarifRule{
+			ID:                   v.RuleID,
+			ShortDescription:     sarifMessage{Text: v.Message},
+			DefaultConfiguration: sarifConfig{Level: sarifLevel(v.Severity)},
+		})
+	}
+	sort.Slice(rules, func(i, j int) bool { return rules[i].ID < rules[j].ID })
+	for i, rule := range rules {
+		ruleIndex[rule.ID] = i
+	}
+
+	results := make([]sarifResult, 0, len(violations))
+	for _, v := range violations {
+		text := v.Message
+		if v.Note != "" {
+			text += "\n\n" + v.Note
+		}
+		results = append(results, sarifResult{
+			RuleID:    v.RuleID,
+			RuleIndex: ruleIndex[v.RuleID],
+			Level:     sarifLevel(v.Severity),
+			Message:   sarifMessage{Text: text},
+			Locations: []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact{URI: v.FilePath},
+					Region: sarifRegion{
+						StartLine:   v.StartLine,
+						StartColumn: v.StartColumn,
+						EndLine:     v.EndLine,
+						EndColumn:   v.EndColumn,
+					},
+				},
+			}},
+		})
+	}
+
+	if rules == nil {
+		rules = []sarifRule{}
+	}
+	return json.MarshalIndent(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:           "cortex-lint",
+				InformationURI: "https://github.com/mvp-joe/project-cortex",
+				Rules:          rules,
+			}},
+			Results: results,
+		}},
+	}, "", "  ")
+}
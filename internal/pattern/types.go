@@ -8,6 +8,13 @@ type PatternRequest struct {
 	ContextLines *int     `json:"context_lines"` // Optional: Lines before/after match (0-10, default: 3)
 	Strictness   string   `json:"strictness"`    // Optional: Matching algorithm (default: "smart")
 	Limit        *int     `json:"limit"`         // Optional: Max results (1-100, default: 50)
+	Rewrite      string   `json:"rewrite"`       // Optional: Rewrite template (returns diffs, never writes files)
+	Label        string   `json:"label"`         // Optional: Content source label, or "project" for the repository's own files
+
+	// Index filters (require the provider to have an index, see AstGrepProvider.WithIndex)
+	ExcludeTests bool   `json:"exclude_tests"` // Optional: Drop matches in test files
+	ExportedOnly bool   `json:"exported_only"` // Optional: Only matches inside exported functions/methods
+	ModulePath   string `json:"module_path"`   // Optional: Only matches in this module (directory) or its subpackages
 }
 
 // PatternMatch represents a single pattern match result
@@ -18,6 +25,23 @@ type PatternMatch struct {
 	MatchText string            `json:"match_text"` // The matched code
 	Context   string            `json:"context"`    // Surrounding lines (from -C flag)
 	Metavars  map[string]string `json:"metavars"`   // Extracted metavariables
+
+	// Replacement is the rewritten code for this match (rewrite mode only)
+	Replacement string `json:"replacement,omitempty"`
+
+	// Index annotations (only when the provider has an index and the file is indexed).
+	// FunctionID and TypeID are the innermost enclosing symbols and can be passed
+	// as cortex_graph targets.
+	FunctionID string     `json:"function_id,omitempty"`
+	TypeID     string     `json:"type_id,omitempty"`
+	File       *MatchFile `json:"file,omitempty"`
+}
+
+// MatchFile is the index metadata of the file containing a match
+type MatchFile struct {
+	Language   string `json:"language"`
+	ModulePath string `json:"module_path"`
+	IsTest     bool   `json:"is_test"`
 }
 
 // PatternResponse represents the cortex_pattern tool response
@@ -25,6 +49,24 @@ type PatternResponse struct {
 	Matches  []PatternMatch  `json:"matches"`
 	Total    int             `json:"total"` // Total found (may be > len(Matches) if limited)
 	Metadata PatternMetadata `json:"metadata"`
+
+	// Rewrite mode only: per-file unified diffs covering every match (not just
+	// the limited Matches), and Patch, their concatenation, ready for ApplyPatch.
+	Diffs []FileDiff `json:"diffs,omitempty"`
+	Patch string     `json:"patch,omitempty"`
+}
+
+// FileDiff is the unified diff a rewrite produces for one file
+type FileDiff struct {
+	FilePath     string `json:"file_path"`    // Relative to project root
+	Replacements int    `json:"replacements"` // Matches rewritten in this file
+	Diff         string `json:"diff"`         // Unified diff (--- a/path, +++ b/path)
+}
+
+// ApplyResult reports the outcome of applying a patch
+type ApplyResult struct {
+	FilesChanged []string `json:"files_changed"`
+	HunksApplied int      `json:"hunks_applied"`
 }
 
 // PatternMetadata contains query execution metadata
@@ -33,6 +75,36 @@ type PatternMetadata struct {
 	Pattern    string `json:"pattern"`
 	Language   string `json:"language"`
 	Strictness string `json:"strictness"`
+	Rewrite    string `json:"rewrite,omitempty"`
+	Generation int64  `json:"generation,omitempty"` // Index generation the annotations were read from (0 without an index)
+}
+
+// QueryRequest represents an MCP cortex_query request
+type QueryRequest struct {
+	Query        string   `json:"query"`         // Required: tree-sitter S-expression query
+	Language     string   `json:"language"`      // Required: Target language
+	FilePaths    []string `json:"file_paths"`    // Optional: File/glob filters
+	ContextLines *int     `json:"context_lines"` // Optional: Lines before/after match (0-10, default: 3)
+	Limit        *int     `json:"limit"`         // Optional: Max results (1-100, default: 50)
+	Label        string   `json:"label"`         // Optional: Content source label, or "project" for the repository's own files
+}
+
+// QueryResponse represents the cortex_query tool response.
+// Matches use the PatternMatch shape; Metavars holds capture texts keyed by
+// capture name (without the leading @).
+type QueryResponse struct {
+	Matches  []PatternMatch `json:"matches"`
+	Total    int            `json:"total"` // Total found (may be > len(Matches) if limited)
+	Metadata QueryMetadata  `json:"metadata"`
+}
+
+// QueryMetadata contains query execution metadata
+type QueryMetadata struct {
+	TookMs       int64  `json:"took_ms"`
+	Query        string `json:"query"`
+	Language     string `json:"language"`
+	FilesScanned int    `json:"files_scanned"`
+	Generation   int64  `json:"generation"` // Index generation the files were read from
 }
 
 // AstGrepResult represents the raw JSON output from ast-grep --json
@@ -55,12 +127,29 @@ type AstGrepMatch struct {
 	Text          string          `json:"text"`
 	Range         AstGrepRange    `json:"range"`
 	MetaVariables AstGrepMetaVars `json:"metaVariables"`
+
+	// Present only with --rewrite (or scan rules with a fix)
+	Replacement        *string           `json:"replacement,omitempty"`
+	ReplacementOffsets *AstGrepByteRange `json:"replacementOffsets,omitempty"`
+
+	// Present only in `ast-grep scan` output
+	RuleID   string `json:"ruleId,omitempty"`
+	Severity string `json:"severity,omitempty"`
+	Message  string `json:"message,omitempty"`
+	Note     string `json:"note,omitempty"`
 }
 
 // AstGrepRange represents line/column position
 type AstGrepRange struct {
-	Start AstGrepPosition `json:"start"`
-	End   AstGrepPosition `json:"end"`
+	ByteOffset AstGrepByteRange `json:"byteOffset"`
+	Start      AstGrepPosition  `json:"start"`
+	End        AstGrepPosition  `json:"end"`
+}
+
+// AstGrepByteRange represents a byte span in the source file (end exclusive)
+type AstGrepByteRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
 }
 
 // AstGrepPosition represents a line/column position
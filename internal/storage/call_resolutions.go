@@ -0,0 +1,93 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// CallResolution records how the type-checked call graph resolved a function
+// call. Calls without one were extracted syntactically.
+//
+// The resolved callee is kept here rather than in function_calls because
+// callee_function_id must reference an indexed function (the callee's file may
+// not be written yet) and is nulled whenever the callee's file is re-indexed.
+// LinkResolvedCalls copies it into function_calls once the callee exists.
+type CallResolution struct {
+	CallID           string
+	CalleeFunctionID string // Resolved callee; "" when outside the project or unknown
+	Confidence       string // exact, dynamic, unresolved
+}
+
+// InsertCallResolutions records resolutions for calls already written to
+// function_calls. Deleting a call (or its caller) deletes its resolution.
+func InsertCallResolutions(tx *sql.Tx, resolutions []CallResolution) error {
+	if len(resolutions) == 0 {
+		return nil
+	}
+	for _, r := range resolutions {
+		var callee interface{}
+		if r.CalleeFunctionID != "" {
+			callee = r.CalleeFunctionID
+		}
+		_, err := sq.Insert("call_resolutions").
+			Columns("call_id", "callee_function_id", "confidence").
+			Values(r.CallID, callee, r.Confidence).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert call resolution %s: %w", r.CallID, err)
+		}
+	}
+	return nil
+}
+
+// LinkResolvedCalls sets function_calls.callee_function_id from resolutions
+// whose callee is indexed, and returns how many calls were linked.
+// Run after every graph update: re-indexing a file nulls the links into it.
+func LinkResolvedCalls(tx *sql.Tx) (int64, error) {
+	result, err := tx.Exec(`
+		UPDATE function_calls
+		SET callee_function_id = (
+			SELECT r.callee_function_id FROM call_resolutions r WHERE r.call_id = function_calls.call_id
+		)
+		WHERE callee_function_id IS NULL
+		  AND call_id IN (
+			SELECT r.call_id
+			FROM call_resolutions r
+			JOIN functions f ON f.function_id = r.callee_function_id
+		  )
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to link resolved calls: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// DynamicCallFiles returns the files with resolved interface method calls.
+// Their edges depend on implementations elsewhere, so they are re-resolved
+// whenever Go code changes.
+func DynamicCallFiles(tx *sql.Tx) ([]string, error) {
+	rows, err := sq.Select("DISTINCT fc.source_file_path").
+		From("call_resolutions r").
+		Join("function_calls fc ON fc.call_id = r.call_id").
+		Where(sq.Eq{"r.confidence": "dynamic"}).
+		OrderBy("fc.source_file_path").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dynamic call files: %w", err)
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var file string
+		if err := rows.Scan(&file); err != nil {
+			return nil, fmt.Errorf("failed to scan file path: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
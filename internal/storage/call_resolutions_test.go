@@ -0,0 +1,101 @@
+package storage
+
+// Test Plan for Call Resolutions:
+// - LinkResolvedCalls is a no-op before any resolution was recorded
+// - Resolved callees are linked once the callee function is indexed
+// - Re-indexing the callee (which nulls callee_function_id) is repaired by relinking
+// - Deleting a call deletes its resolution
+// - DynamicCallFiles lists the files with interface method calls
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertCallTestFunction(t *testing.T, db *sql.DB, file, id string) {
+	t.Helper()
+	_, err := db.Exec(`INSERT OR IGNORE INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+		VALUES (?, 'go', '.', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, file)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO functions (function_id, file_path, module_path, name, start_line, end_line, line_count)
+		VALUES (?, ?, '.', ?, 1, 2, 1)`, id, file, id)
+	require.NoError(t, err)
+}
+
+func linkCalls(t *testing.T, db *sql.DB) int64 {
+	t.Helper()
+	var linked int64
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		var err error
+		linked, err = LinkResolvedCalls(tx)
+		return err
+	}))
+	return linked
+}
+
+func calleeOf(t *testing.T, db *sql.DB, callID string) sql.NullString {
+	t.Helper()
+	var callee sql.NullString
+	require.NoError(t, db.QueryRow("SELECT callee_function_id FROM function_calls WHERE call_id = ?", callID).Scan(&callee))
+	return callee
+}
+
+func TestLinkResolvedCalls_NothingResolved(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	assert.Equal(t, int64(0), linkCalls(t, db))
+}
+
+func TestLinkResolvedCalls(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	insertCallTestFunction(t, db, "app/app.go", "app/app.go::Run")
+	for _, id := range []string{"app/app.go::Run::call0", "app/app.go::Run::call1", "app/app.go::Run::call2"} {
+		_, err := db.Exec(`INSERT INTO function_calls (call_id, caller_function_id, callee_name, source_file_path, call_line)
+			VALUES (?, 'app/app.go::Run', 'callee', 'app/app.go', 3)`, id)
+		require.NoError(t, err)
+	}
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return InsertCallResolutions(tx, []CallResolution{
+			{CallID: "app/app.go::Run::call0", CalleeFunctionID: "store/store.go::New", Confidence: "exact"},
+			{CallID: "app/app.go::Run::call1", CalleeFunctionID: "store/store.go::Memory.Get", Confidence: "dynamic"},
+			{CallID: "app/app.go::Run::call2", Confidence: "unresolved"},
+		})
+	}))
+
+	// Callees aren't indexed yet
+	assert.Equal(t, int64(0), linkCalls(t, db))
+
+	insertCallTestFunction(t, db, "store/store.go", "store/store.go::New")
+	insertCallTestFunction(t, db, "store/store.go", "store/store.go::Memory.Get")
+	assert.Equal(t, int64(2), linkCalls(t, db))
+	assert.Equal(t, "store/store.go::New", calleeOf(t, db, "app/app.go::Run::call0").String)
+	assert.False(t, calleeOf(t, db, "app/app.go::Run::call2").Valid)
+
+	// Re-indexing the callee nulls the link; relinking restores it
+	_, err := db.Exec("DELETE FROM functions WHERE file_path = 'store/store.go'")
+	require.NoError(t, err)
+	assert.False(t, calleeOf(t, db, "app/app.go::Run::call0").Valid)
+	insertCallTestFunction(t, db, "store/store.go", "store/store.go::New")
+	assert.Equal(t, int64(1), linkCalls(t, db))
+	assert.Equal(t, "store/store.go::New", calleeOf(t, db, "app/app.go::Run::call0").String)
+
+	var files []string
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		files, err = DynamicCallFiles(tx)
+		return err
+	}))
+	assert.Equal(t, []string{"app/app.go"}, files)
+
+	// Deleting the caller cascades through its calls to their resolutions
+	_, err = db.Exec("DELETE FROM functions WHERE file_path = 'app/app.go'")
+	require.NoError(t, err)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM call_resolutions").Scan(&count))
+	assert.Equal(t, 0, count)
+}
@@ -17,12 +17,13 @@ type ChunkReader struct {
 }
 
 // NewChunkReader opens a SQLite database for reading chunks.
-// Uses read-only mode to prevent accidental modifications.
+// Uses read-only mode to prevent accidental modifications, and reads the
+// last committed index generation.
 //
 // Deprecated: Use NewChunkReaderWithDB to share database connections.
 func NewChunkReader(dbPath string) (*ChunkReader, error) {
 	// Open in read-only mode with query param
-	db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
+	db, err := OpenCommitted(dbPath + "?mode=ro")
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -136,7 +137,7 @@ func (r *ChunkReader) ReadChunksByType(chunkType string) ([]*Chunk, error) {
 }
 
 // SearchByEmbedding performs vector similarity search and returns full chunks.
-// Combines sqlite-vec vector search with JOIN to chunks table.
+// Combines the active vector index backend (see OpenVectorIndex) with a lookup in the chunks table.
 //
 // Parameters:
 //   - queryEmb: Query embedding vector
@@ -144,10 +145,15 @@ func (r *ChunkReader) ReadChunksByType(chunkType string) ([]*Chunk, error) {
 //   - limit: Maximum number of results to return
 //
 // Returns chunks ordered by similarity (most similar first).
-// This is a high-level API that combines QueryVectorSimilarity with chunk data.
+// This is a high-level API that combines VectorIndex.Search with chunk data.
 func (r *ChunkReader) SearchByEmbedding(queryEmb []float32, filters map[string]interface{}, limit int) ([]*Chunk, error) {
+	index, err := OpenVectorIndex(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector index: %w", err)
+	}
+
 	// First, get vector similarity results
-	vectorResults, err := QueryVectorSimilarity(r.db, queryEmb, limit*2) // Fetch 2x for filtering headroom
+	vectorResults, err := index.Search(r.db, queryEmb, limit*2) // Fetch 2x for filtering headroom
 	if err != nil {
 		return nil, fmt.Errorf("vector similarity search failed: %w", err)
 	}
@@ -39,7 +39,7 @@ func NewChunkWriter(dbPath string) (*ChunkWriter, error) {
 	// Initialize sqlite-vec extension globally (safe to call multiple times)
 	InitVectorExtension()
 
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+"?"+WriterDSNParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -62,6 +62,9 @@ func NewChunkWriter(dbPath string) (*ChunkWriter, error) {
 			db.Close()
 			return nil, fmt.Errorf("failed to create schema: %w", err)
 		}
+	} else if err := MigrateSchema(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
 	}
 
 	return &ChunkWriter{db: db, ownsDB: true}, nil
@@ -82,55 +85,70 @@ func (w *ChunkWriter) WriteChunks(chunks []*Chunk) error {
 		return nil
 	}
 
-	tx, err := w.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback() // Safe to call even after commit
-
-	// Clear all existing chunks and vectors
-	if _, err := sq.Delete("chunks").RunWith(tx).Exec(); err != nil {
-		return fmt.Errorf("failed to clear chunks: %w", err)
-	}
-	if _, err := tx.Exec("DELETE FROM chunks_vec"); err != nil {
-		return fmt.Errorf("failed to clear vector index: %w", err)
-	}
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		// Resolve the active vector index backend (recorded in cache_metadata)
+		index, err := OpenVectorIndex(tx)
+		if err != nil {
+			return fmt.Errorf("failed to open vector index: %w", err)
+		}
 
-	// Insert all chunks
-	for _, chunk := range chunks {
-		embBytes := SerializeEmbedding(chunk.Embedding)
-
-		_, err := sq.Insert("chunks").
-			Columns("chunk_id", "file_path", "chunk_type", "title", "text", "embedding", "start_line", "end_line", "created_at", "updated_at").
-			Values(
-				chunk.ID,
-				chunk.FilePath,
-				chunk.ChunkType,
-				chunk.Title,
-				chunk.Text,
-				embBytes,
-				nullableInt(chunk.StartLine),
-				nullableInt(chunk.EndLine),
-				chunk.CreatedAt.UTC().Format(time.RFC3339),
-				chunk.UpdatedAt.UTC().Format(time.RFC3339),
-			).
-			RunWith(tx).
-			Exec()
+		open, err := openGeneration(tx)
 		if err != nil {
-			return fmt.Errorf("failed to insert chunk %s: %w", chunk.ID, err)
+			return err
 		}
-	}
 
-	// Update vector index for semantic search
-	if err := UpdateVectorIndex(tx, chunks); err != nil {
-		return fmt.Errorf("failed to update vector index: %w", err)
-	}
+		// Clear all existing vectors and chunks. While a generation is open,
+		// vectors of committed chunks stay until it commits.
+		if open == 0 {
+			if err := index.Clear(tx); err != nil {
+				return fmt.Errorf("failed to clear vector index: %w", err)
+			}
+		} else {
+			chunkIDs, err := deletableVectors(tx, nil)
+			if err != nil {
+				return err
+			}
+			if err := index.Delete(tx, chunkIDs); err != nil {
+				return fmt.Errorf("failed to delete vectors: %w", err)
+			}
+		}
+		if _, err := sq.Delete("chunks").RunWith(tx).Exec(); err != nil {
+			return fmt.Errorf("failed to clear chunks: %w", err)
+		}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+		// Insert all chunks
+		for _, chunk := range chunks {
+			embBytes := SerializeEmbedding(chunk.Embedding)
+
+			_, err := sq.Insert("chunks").
+				Columns("chunk_id", "file_path", "chunk_type", "title", "text", "embedding", "start_line", "end_line", "created_at", "updated_at", "generation").
+				Values(
+					chunk.ID,
+					chunk.FilePath,
+					chunk.ChunkType,
+					chunk.Title,
+					chunk.Text,
+					embBytes,
+					nullableInt(chunk.StartLine),
+					nullableInt(chunk.EndLine),
+					chunk.CreatedAt.UTC().Format(time.RFC3339),
+					chunk.UpdatedAt.UTC().Format(time.RFC3339),
+					open, // Set here so the generation trigger needn't rewrite the row
+				).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return fmt.Errorf("failed to insert chunk %s: %w", chunk.ID, err)
+			}
+		}
 
-	return nil
+		// Update vector index for semantic search
+		if err := index.Update(tx, chunks); err != nil {
+			return fmt.Errorf("failed to update vector index: %w", err)
+		}
+
+		return nil
+	})
 }
 
 // WriteChunksIncremental updates chunks for specific files only.
@@ -141,91 +159,81 @@ func (w *ChunkWriter) WriteChunksIncremental(chunks []*Chunk) error {
 		return nil
 	}
 
-	tx, err := w.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		// Resolve the active vector index backend (recorded in cache_metadata)
+		index, err := OpenVectorIndex(tx)
+		if err != nil {
+			return fmt.Errorf("failed to open vector index: %w", err)
+		}
 
-	// Collect unique file paths
-	filePathsMap := make(map[string]bool)
-	for _, chunk := range chunks {
-		filePathsMap[chunk.FilePath] = true
-	}
+		// Collect unique file paths
+		filePathsMap := make(map[string]bool)
+		for _, chunk := range chunks {
+			filePathsMap[chunk.FilePath] = true
+		}
 
-	// Delete existing chunks and vectors for these files
-	for filePath := range filePathsMap {
-		// Get chunk IDs to delete from vector index
-		rows, err := sq.Select("chunk_id").
-			From("chunks").
-			Where(sq.Eq{"file_path": filePath}).
-			RunWith(tx).
-			Query()
+		open, err := openGeneration(tx)
 		if err != nil {
-			return fmt.Errorf("failed to query chunks for file %s: %w", filePath, err)
+			return err
 		}
 
-		var chunkIDs []string
-		for rows.Next() {
-			var id string
-			if err := rows.Scan(&id); err != nil {
-				rows.Close()
-				return fmt.Errorf("failed to scan chunk_id: %w", err)
+		// Delete existing chunks and vectors for these files
+		for filePath := range filePathsMap {
+			// Get chunk IDs to delete from vector index (committed chunks
+			// keep theirs until an open generation commits)
+			chunkIDs, err := deletableVectors(tx, sq.Eq{"file_path": filePath})
+			if err != nil {
+				return fmt.Errorf("failed to query chunks for file %s: %w", filePath, err)
 			}
-			chunkIDs = append(chunkIDs, id)
-		}
-		rows.Close()
 
-		// Delete vectors first
-		if err := DeleteVectorsByFile(tx, chunkIDs); err != nil {
-			return fmt.Errorf("failed to delete vectors for file %s: %w", filePath, err)
-		}
+			// Delete vectors first
+			if err := index.Delete(tx, chunkIDs); err != nil {
+				return fmt.Errorf("failed to delete vectors for file %s: %w", filePath, err)
+			}
 
-		// Delete chunks
-		_, err = sq.Delete("chunks").
-			Where(sq.Eq{"file_path": filePath}).
-			RunWith(tx).
-			Exec()
-		if err != nil {
-			return fmt.Errorf("failed to delete chunks for file %s: %w", filePath, err)
+			// Delete chunks
+			_, err = sq.Delete("chunks").
+				Where(sq.Eq{"file_path": filePath}).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return fmt.Errorf("failed to delete chunks for file %s: %w", filePath, err)
+			}
 		}
-	}
 
-	// Insert new chunks
-	for _, chunk := range chunks {
-		embBytes := SerializeEmbedding(chunk.Embedding)
-
-		_, err := sq.Insert("chunks").
-			Columns("chunk_id", "file_path", "chunk_type", "title", "text", "embedding", "start_line", "end_line", "created_at", "updated_at").
-			Values(
-				chunk.ID,
-				chunk.FilePath,
-				chunk.ChunkType,
-				chunk.Title,
-				chunk.Text,
-				embBytes,
-				nullableInt(chunk.StartLine),
-				nullableInt(chunk.EndLine),
-				chunk.CreatedAt.UTC().Format(time.RFC3339),
-				chunk.UpdatedAt.UTC().Format(time.RFC3339),
-			).
-			RunWith(tx).
-			Exec()
-		if err != nil {
-			return fmt.Errorf("failed to insert chunk %s: %w", chunk.ID, err)
+		// Insert new chunks
+		for _, chunk := range chunks {
+			embBytes := SerializeEmbedding(chunk.Embedding)
+
+			_, err := sq.Insert("chunks").
+				Columns("chunk_id", "file_path", "chunk_type", "title", "text", "embedding", "start_line", "end_line", "created_at", "updated_at", "generation").
+				Values(
+					chunk.ID,
+					chunk.FilePath,
+					chunk.ChunkType,
+					chunk.Title,
+					chunk.Text,
+					embBytes,
+					nullableInt(chunk.StartLine),
+					nullableInt(chunk.EndLine),
+					chunk.CreatedAt.UTC().Format(time.RFC3339),
+					chunk.UpdatedAt.UTC().Format(time.RFC3339),
+					open, // Set here so the generation trigger needn't rewrite the row
+				).
+				RunWith(tx).
+				Exec()
+			if err != nil {
+				return fmt.Errorf("failed to insert chunk %s: %w", chunk.ID, err)
+			}
 		}
-	}
-
-	// Update vector index for semantic search
-	if err := UpdateVectorIndex(tx, chunks); err != nil {
-		return fmt.Errorf("failed to update vector index: %w", err)
-	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
-	}
+		// Update vector index for semantic search
+		if err := index.Update(tx, chunks); err != nil {
+			return fmt.Errorf("failed to update vector index: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // Close closes the database connection if owned by this writer.
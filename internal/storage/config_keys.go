@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ConfigKey is a flattened key path of a configuration file (YAML, JSON,
+// TOML, .env template) with its value, e.g.
+// "spec.template.spec.containers[0].image" = "nginx:1.27".
+type ConfigKey struct {
+	FilePath string
+	Document int    // Index of the document in multi-document YAML files, 0 otherwise
+	KeyPath  string // Dotted path with [i] for sequence items
+	Value    string // Scalar value; secret-looking values are masked
+	Line     int
+}
+
+// ReplaceConfigKeys replaces the config keys of filePath.
+func ReplaceConfigKeys(tx *sql.Tx, filePath string, keys []ConfigKey) error {
+	if err := DeleteConfigKeys(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		// OR IGNORE: duplicate keys (invalid, but accepted by some parsers);
+		// the first one wins
+		_, err := sq.Insert("config_keys").
+			Options("OR IGNORE").
+			Columns("file_path", "document", "key_path", "value", "line").
+			Values(filePath, k.Document, k.KeyPath, k.Value, k.Line).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert config key %s: %w", k.KeyPath, err)
+		}
+	}
+	return nil
+}
+
+// DeleteConfigKeys removes the config keys of filePath.
+func DeleteConfigKeys(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("config_keys").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete config keys for %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/graph"
+)
+
+// ReplaceConfigUsages replaces the environment variable and configuration
+// key reads of filePath.
+func ReplaceConfigUsages(tx *sql.Tx, filePath string, usages []graph.ConfigUsage) error {
+	if err := DeleteConfigUsages(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, u := range usages {
+		_, err := sq.Insert("config_usages").
+			Columns("file_path", "line", "kind", "key", "source", "function", "function_id").
+			Values(filePath, u.Line, u.Kind, u.Key, u.Source, u.Function, nullIfEmpty(u.FunctionID)).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert config usage %s: %w", u.Key, err)
+		}
+	}
+	return nil
+}
+
+// DeleteConfigUsages removes the reads of filePath.
+func DeleteConfigUsages(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("config_usages").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete config usages for %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package storage
+
+// Test Plan for Config Usages:
+// - DeleteConfigUsages is a no-op before any read was indexed
+// - With only .env templates indexed, the views report every variable as unused
+// - config_key_definitions matches key paths exactly (case-insensitively), by suffix,
+//   and Kubernetes name/value and KEY=value entries for environment variables
+// - undocumented_config_keys and unused_config_keys report what the other side lacks
+// - Replacing a file's reads replaces them; deleting the file cascades
+
+import (
+	"database/sql"
+	"sort"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigUsages(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBFile(t)
+	for _, f := range []struct{ path, language string }{
+		{".env.example", "dotenv"},
+		{"deploy/api.yaml", "yaml"},
+		{"docker-compose.yml", "yaml"},
+		{"config.yaml", "yaml"},
+		{"cmd/api/main.go", "go"},
+		{"web/server.ts", "typescript"},
+	} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, ?, '', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, f.path, f.language)
+		require.NoError(t, err)
+	}
+
+	withTx := func(fn func(tx *sql.Tx) error) {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+	keys := func(query string) []string {
+		t.Helper()
+		rows, err := db.Query(query)
+		require.NoError(t, err)
+		defer rows.Close()
+		var found []string
+		for rows.Next() {
+			var key string
+			require.NoError(t, rows.Scan(&key))
+			found = append(found, key)
+		}
+		require.NoError(t, rows.Err())
+		sort.Strings(found)
+		return found
+	}
+
+	withTx(func(tx *sql.Tx) error {
+		if err := DeleteConfigUsages(tx, "cmd/api/main.go"); err != nil {
+			return err
+		}
+		return ReplaceConfigKeys(tx, ".env.example", []ConfigKey{
+			{KeyPath: "DATABASE_URL", Value: "postgres://localhost/app", Line: 1},
+			{KeyPath: "LOG_LEVEL", Value: "info", Line: 2},
+			{KeyPath: "STALE_FLAG", Value: "1", Line: 3},
+		})
+	})
+	assert.Equal(t, []string{"DATABASE_URL", "LOG_LEVEL", "STALE_FLAG"}, keys("SELECT key FROM unused_config_keys"))
+
+	withTx(func(tx *sql.Tx) error {
+		if err := ReplaceConfigKeys(tx, "deploy/api.yaml", []ConfigKey{
+			{KeyPath: "spec.template.spec.containers[0].env[0].name", Value: "REDIS_URL", Line: 10},
+			{KeyPath: "spec.template.spec.containers[0].env[0].value", Value: "redis://cache", Line: 11},
+		}); err != nil {
+			return err
+		}
+		if err := ReplaceConfigKeys(tx, "docker-compose.yml", []ConfigKey{
+			{KeyPath: "services.api.environment.SMTP_HOST", Value: "mail", Line: 4},
+			{KeyPath: "services.worker.environment[0]", Value: "QUEUE=jobs", Line: 9},
+		}); err != nil {
+			return err
+		}
+		if err := ReplaceConfigKeys(tx, "config.yaml", []ConfigKey{
+			{KeyPath: "server.port", Value: "8080", Line: 2},
+			{KeyPath: "production.server.tls", Value: "true", Line: 7},
+		}); err != nil {
+			return err
+		}
+
+		env := func(key string, line int) graph.ConfigUsage {
+			return graph.ConfigUsage{Kind: graph.ConfigKindEnv, Key: key, Source: "os.Getenv", Function: "main", FunctionID: "cmd/api/main.go::main", Line: line}
+		}
+		if err := ReplaceConfigUsages(tx, "cmd/api/main.go", []graph.ConfigUsage{
+			env("DATABASE_URL", 5), env("REDIS_URL", 6), env("SMTP_HOST", 7), env("QUEUE", 8), env("SENTRY_DSN", 9),
+			{Kind: graph.ConfigKindKey, Key: "Server.Port", Source: "viper", Line: 10},
+			{Kind: graph.ConfigKindKey, Key: "server.tls", Source: "viper", Line: 11},
+			{Kind: graph.ConfigKindKey, Key: "server.timeout", Source: "viper", Line: 12},
+		}); err != nil {
+			return err
+		}
+		return ReplaceConfigUsages(tx, "web/server.ts", []graph.ConfigUsage{
+			{Kind: graph.ConfigKindEnv, Key: "LOG_LEVEL", Source: "process.env", Line: 3},
+		})
+	})
+
+	assert.Equal(t, []string{
+		"DATABASE_URL .env.example:1",
+		"LOG_LEVEL .env.example:2",
+		"QUEUE docker-compose.yml:9",
+		"REDIS_URL deploy/api.yaml:10",
+		"SMTP_HOST docker-compose.yml:4",
+		"Server.Port config.yaml:2",
+		"server.tls config.yaml:7",
+	}, keys("SELECT key || ' ' || file_path || ':' || line FROM config_key_definitions"))
+	assert.Equal(t, []string{"SENTRY_DSN", "server.timeout"}, keys("SELECT key FROM undocumented_config_keys"))
+	assert.Equal(t, []string{"STALE_FLAG"}, keys("SELECT key FROM unused_config_keys"))
+
+	var functionID sql.NullString
+	require.NoError(t, db.QueryRow("SELECT function_id FROM config_usages WHERE key = 'Server.Port'").Scan(&functionID))
+	assert.False(t, functionID.Valid)
+
+	// Replacing a file's reads; deleting a file cascades
+	withTx(func(tx *sql.Tx) error {
+		return ReplaceConfigUsages(tx, "web/server.ts", nil)
+	})
+	assert.Equal(t, []string{"LOG_LEVEL", "STALE_FLAG"}, keys("SELECT key FROM unused_config_keys"))
+	_, err := db.Exec("DELETE FROM files WHERE file_path = 'cmd/api/main.go'")
+	require.NoError(t, err)
+	assert.Empty(t, keys("SELECT key FROM config_usages"))
+	assert.Len(t, keys("SELECT key FROM unused_config_keys"), 3)
+}
@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/labels"
+)
+
+// ProjectLabel selects the project's own files in label filters. It cannot
+// be used as a content source label.
+const ProjectLabel = labels.Project
+
+// ContentSource is a directory outside the project root indexed into the
+// same database. Its files are stored under ContentSourcePrefix(Label).
+type ContentSource struct {
+	Label string
+	Root  string // Absolute directory the files were read from
+	Watch bool   // Re-indexed on change by the daemon
+}
+
+// ValidContentSourceLabel reports whether label can name a content source.
+func ValidContentSourceLabel(label string) bool {
+	return labels.ValidSource(label)
+}
+
+// ContentSourcePrefix returns the path prefix of a content source's files,
+// e.g. "@wiki/" (so "@wiki/onboarding.md").
+func ContentSourcePrefix(label string) string {
+	return "@" + label + "/"
+}
+
+// ContentSourceLabel returns the label of the content source a file path
+// belongs to, or ProjectLabel for the project's own files.
+func ContentSourceLabel(filePath string) string {
+	if !strings.HasPrefix(filePath, "@") {
+		return ProjectLabel
+	}
+	label, _, ok := strings.Cut(filePath[1:], "/")
+	if !ok {
+		return ProjectLabel
+	}
+	return label
+}
+
+// IsContentSourcePath reports whether filePath belongs to a content source
+// rather than the project.
+func IsContentSourcePath(filePath string) bool {
+	return ContentSourceLabel(filePath) != ProjectLabel
+}
+
+// LabelFilter restricts column (a file path) to the files of the content
+// source labelled label, or to the project's own files for ProjectLabel.
+func LabelFilter(column, label string) sq.Sqlizer {
+	if label == ProjectLabel {
+		return sq.Expr(fmt.Sprintf("substr(%s, 1, 1) <> '@'", column))
+	}
+	prefix := ContentSourcePrefix(label)
+	return sq.Expr(fmt.Sprintf("substr(%s, 1, ?) = ?", column), len(prefix), prefix)
+}
+
+// ListContentSources returns the content sources recorded by the last
+// indexing run, ordered by label.
+func ListContentSources(db sq.BaseRunner) ([]ContentSource, error) {
+	rows, err := sq.Select("label", "root", "watch").
+		From("content_sources").
+		OrderBy("label").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query content sources: %w", err)
+	}
+	defer rows.Close()
+
+	var sources []ContentSource
+	for rows.Next() {
+		var s ContentSource
+		if err := rows.Scan(&s.Label, &s.Root, &s.Watch); err != nil {
+			return nil, fmt.Errorf("failed to scan content source: %w", err)
+		}
+		sources = append(sources, s)
+	}
+	return sources, rows.Err()
+}
+
+// ReplaceContentSources records the configured content sources, replacing
+// the previous set.
+func ReplaceContentSources(tx *sql.Tx, sources []ContentSource) error {
+	if _, err := sq.Delete("content_sources").RunWith(tx).Exec(); err != nil {
+		return fmt.Errorf("failed to clear content sources: %w", err)
+	}
+	for _, s := range sources {
+		_, err := sq.Insert("content_sources").
+			Columns("label", "root", "watch").
+			Values(s.Label, s.Root, s.Watch).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to record content source %s: %w", s.Label, err)
+		}
+	}
+	return nil
+}
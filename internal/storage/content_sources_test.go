@@ -0,0 +1,96 @@
+package storage
+
+// Test Plan for Content Sources:
+// - Labels are lowercase names other than "project"
+// - ContentSourceLabel attributes "@<label>/" paths to their source and everything else to the project
+// - LabelFilter selects a source's files, or the project's own files for "project"
+// - ReplaceContentSources replaces the recorded set; ListContentSources reads it back
+// - Nothing is listed before a source was ever configured
+
+import (
+	"database/sql"
+	"testing"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContentSourceLabels(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, ValidContentSourceLabel("wiki"))
+	assert.True(t, ValidContentSourceLabel("design-docs.v2"))
+	assert.False(t, ValidContentSourceLabel(""))
+	assert.False(t, ValidContentSourceLabel("project"))
+	assert.False(t, ValidContentSourceLabel("Wiki"))
+	assert.False(t, ValidContentSourceLabel("api/specs"))
+	assert.False(t, ValidContentSourceLabel("-wiki"))
+
+	assert.Equal(t, "@wiki/", ContentSourcePrefix("wiki"))
+	assert.Equal(t, "wiki", ContentSourceLabel("@wiki/onboarding.md"))
+	assert.Equal(t, ProjectLabel, ContentSourceLabel("internal/auth/login.go"))
+	assert.Equal(t, ProjectLabel, ContentSourceLabel("@types.d.ts"))
+	assert.True(t, IsContentSourcePath("@specs/api.yaml"))
+	assert.False(t, IsContentSourcePath("docs/@mentions.md"))
+}
+
+func TestLabelFilter(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	for _, path := range []string{"main.go", "@wiki/onboarding.md", "@wiki-old/index.md", "@specs/api.md"} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, 'markdown', '.', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, path)
+		require.NoError(t, err)
+	}
+
+	paths := func(label string) []string {
+		rows, err := sq.Select("file_path").From("files").Where(LabelFilter("file_path", label)).OrderBy("file_path").RunWith(db).Query()
+		require.NoError(t, err)
+		defer rows.Close()
+		var result []string
+		for rows.Next() {
+			var p string
+			require.NoError(t, rows.Scan(&p))
+			result = append(result, p)
+		}
+		require.NoError(t, rows.Err())
+		return result
+	}
+
+	assert.Equal(t, []string{"main.go"}, paths(ProjectLabel))
+	assert.Equal(t, []string{"@wiki/onboarding.md"}, paths("wiki"))
+	assert.Equal(t, []string{"@specs/api.md"}, paths("specs"))
+	assert.Empty(t, paths("unknown"))
+}
+
+func TestContentSources(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	// Never configured
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceContentSources(tx, nil)
+	}))
+	sources, err := ListContentSources(db)
+	require.NoError(t, err)
+	assert.Empty(t, sources)
+
+	wiki := ContentSource{Label: "wiki", Root: "/src/wiki", Watch: true}
+	specs := ContentSource{Label: "specs", Root: "/src/api-specs"}
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceContentSources(tx, []ContentSource{wiki, specs})
+	}))
+	sources, err = ListContentSources(db)
+	require.NoError(t, err)
+	assert.Equal(t, []ContentSource{specs, wiki}, sources)
+
+	// Removing a source from the config removes its record
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceContentSources(tx, []ContentSource{wiki})
+	}))
+	sources, err = ListContentSources(db)
+	require.NoError(t, err)
+	assert.Equal(t, []ContentSource{wiki}, sources)
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// ContractSymbol tags a type or function row as part of an API contract: a
+// Protobuf message, enum, service or RPC, an OpenAPI schema or operation, or a
+// GraphQL type or root field.
+type ContractSymbol struct {
+	SymbolID  string // type_id or function_id
+	FilePath  string
+	Protocol  string // protobuf, openapi, graphql
+	Kind      string // message, enum, service, rpc, schema, operation, object, query, ...
+	Name      string // Qualified name: "indexer.v1.IndexerService/Index", "GET /users/{id}", "Query.user"
+	GoPackage string // Import path of the generated Go code (Protobuf go_package), if declared
+}
+
+// ContractLink links generated Go code to the contract symbol it was
+// generated from.
+type ContractLink struct {
+	ContractSymbolID  string
+	GeneratedID       string // type_id or function_id of the generated code
+	GeneratedFilePath string
+	Kind              string // type, client, handler, server
+}
+
+// ReplaceContractSymbols replaces the contract symbols of filePath.
+// Call ResolveContractLinks afterwards to update contract_links.
+func ReplaceContractSymbols(tx *sql.Tx, filePath string, symbols []ContractSymbol) error {
+	if err := DeleteContractSymbols(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, s := range symbols {
+		var goPackage interface{}
+		if s.GoPackage != "" {
+			goPackage = s.GoPackage
+		}
+		_, err := sq.Insert("contract_symbols").
+			Options("OR IGNORE").
+			Columns("symbol_id", "file_path", "protocol", "kind", "qualified_name", "go_package").
+			Values(s.SymbolID, filePath, s.Protocol, s.Kind, s.Name, goPackage).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert contract symbol %s: %w", s.SymbolID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteContractSymbols removes the contract symbols of filePath.
+func DeleteContractSymbols(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("contract_symbols").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete contract symbols for %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// generatedSymbol is an indexed Go type or function that contract symbols
+// may link to.
+type generatedSymbol struct {
+	id       string
+	filePath string
+}
+
+// ResolveContractLinks rebuilds contract_links and returns how many were
+// written.
+//
+// Protobuf symbols declaring a go_package are linked to the code protoc
+// plugins generate for them, found by name in the Go package directory whose
+// path ends the import path (and its "<name>connect" subpackage):
+//   - messages and enums: the Go type (Outer.Inner becomes Outer_Inner)
+//   - services: the connect-go Client, Handler and UnimplementedHandler types,
+//     and the grpc-go Client, Server and UnimplementedServer types
+//   - RPCs: the methods of the generated client and unimplemented handler or
+//     server, and the grpc-go _Service_Method_Handler function
+//
+// OpenAPI and GraphQL symbols are tagged but not linked: their generators
+// don't follow naming conventions that can be relied on.
+func ResolveContractLinks(tx *sql.Tx) (int, error) {
+	if _, err := tx.Exec("DELETE FROM contract_links"); err != nil {
+		return 0, fmt.Errorf("failed to clear contract links: %w", err)
+	}
+
+	dirs, types, methods, err := loadGeneratedSymbols(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := sq.Select("c.symbol_id", "c.kind", "c.go_package", "COALESCE(t.name, f.name)", "COALESCE(f.receiver_type_name, '')").
+		From("contract_symbols c").
+		LeftJoin("types t ON t.type_id = c.symbol_id").
+		LeftJoin("functions f ON f.function_id = c.symbol_id").
+		Where(sq.Eq{"c.protocol": "protobuf"}).
+		Where("c.go_package IS NOT NULL").
+		Where("COALESCE(t.name, f.name) IS NOT NULL").
+		OrderBy("c.symbol_id").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query contract symbols: %w", err)
+	}
+	defer rows.Close()
+
+	var links []ContractLink
+	for rows.Next() {
+		var symbolID, kind, goPackage, name, receiver string
+		if err := rows.Scan(&symbolID, &kind, &goPackage, &name, &receiver); err != nil {
+			return 0, fmt.Errorf("failed to scan contract symbol: %w", err)
+		}
+
+		dir, connectDir, ok := goPackageDirs(goPackage, dirs)
+		if !ok {
+			continue
+		}
+		link := func(symbols map[string][]generatedSymbol, key, linkKind string) {
+			for _, g := range symbols[key] {
+				links = append(links, ContractLink{
+					ContractSymbolID: symbolID, GeneratedID: g.id, GeneratedFilePath: g.filePath, Kind: linkKind,
+				})
+			}
+		}
+
+		switch kind {
+		case "message", "enum":
+			link(types, dir+"\x00"+strings.ReplaceAll(name, ".", "_"), "type")
+		case "service":
+			link(types, connectDir+"\x00"+name+"Client", "client")
+			link(types, connectDir+"\x00"+name+"Handler", "handler")
+			link(types, connectDir+"\x00Unimplemented"+name+"Handler", "handler")
+			link(types, dir+"\x00"+name+"Client", "client")
+			link(types, dir+"\x00"+name+"Server", "server")
+			link(types, dir+"\x00Unimplemented"+name+"Server", "server")
+		case "rpc":
+			if receiver == "" {
+				continue
+			}
+			client := strings.ToLower(receiver[:1]) + receiver[1:] + "Client"
+			link(methods, connectDir+"\x00"+client+"."+name, "client")
+			link(methods, connectDir+"\x00Unimplemented"+receiver+"Handler."+name, "handler")
+			link(methods, dir+"\x00"+client+"."+name, "client")
+			link(methods, dir+"\x00Unimplemented"+receiver+"Server."+name, "server")
+			link(methods, dir+"\x00_"+receiver+"_"+name+"_Handler", "server")
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	for _, l := range links {
+		_, err := sq.Insert("contract_links").
+			Options("OR IGNORE").
+			Columns("contract_symbol_id", "generated_id", "generated_file_path", "link_kind").
+			Values(l.ContractSymbolID, l.GeneratedID, l.GeneratedFilePath, l.Kind).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return 0, fmt.Errorf("failed to insert contract link %s -> %s: %w", l.ContractSymbolID, l.GeneratedID, err)
+		}
+	}
+	return len(links), nil
+}
+
+// loadGeneratedSymbols indexes the Go types by "module\x00name" and the Go
+// functions by "module\x00receiver.name" (or "module\x00name"), and returns
+// the Go package directories, longest first.
+func loadGeneratedSymbols(tx *sql.Tx) (dirs []string, types, functions map[string][]generatedSymbol, err error) {
+	types = make(map[string][]generatedSymbol)
+	functions = make(map[string][]generatedSymbol)
+	seen := make(map[string]bool)
+
+	typeRows, err := sq.Select("t.type_id", "t.file_path", "t.module_path", "t.name").
+		From("types t").
+		Join("files f ON f.file_path = t.file_path").
+		Where(sq.Eq{"f.language": "go"}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query types: %w", err)
+	}
+	defer typeRows.Close()
+	for typeRows.Next() {
+		var g generatedSymbol
+		var module, name string
+		if err := typeRows.Scan(&g.id, &g.filePath, &module, &name); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to scan type: %w", err)
+		}
+		types[module+"\x00"+name] = append(types[module+"\x00"+name], g)
+		if !seen[module] {
+			seen[module] = true
+			dirs = append(dirs, module)
+		}
+	}
+	if err := typeRows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+	typeRows.Close()
+
+	funcRows, err := sq.Select("fn.function_id", "fn.file_path", "fn.module_path", "fn.name", "COALESCE(fn.receiver_type_name, '')").
+		From("functions fn").
+		Join("files f ON f.file_path = fn.file_path").
+		Where(sq.Eq{"f.language": "go"}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to query functions: %w", err)
+	}
+	defer funcRows.Close()
+	for funcRows.Next() {
+		var g generatedSymbol
+		var module, name, receiver string
+		if err := funcRows.Scan(&g.id, &g.filePath, &module, &name, &receiver); err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to scan function: %w", err)
+		}
+		if receiver != "" {
+			name = receiver + "." + name
+		}
+		functions[module+"\x00"+name] = append(functions[module+"\x00"+name], g)
+		if !seen[module] {
+			seen[module] = true
+			dirs = append(dirs, module)
+		}
+	}
+	if err := funcRows.Err(); err != nil {
+		return nil, nil, nil, err
+	}
+
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	return dirs, types, functions, nil
+}
+
+// goPackageDirs finds the directory of a go_package ("import/path" or
+// "import/path;name") among the indexed Go package directories: the longest
+// one the import path ends with. connectDir is its connect-go subpackage.
+func goPackageDirs(goPackage string, dirs []string) (dir, connectDir string, ok bool) {
+	importPath, name, _ := strings.Cut(goPackage, ";")
+	if name == "" {
+		name = importPath[strings.LastIndex(importPath, "/")+1:]
+	}
+	for _, d := range dirs {
+		if d != "" && d != "main" && (importPath == d || strings.HasSuffix(importPath, "/"+d)) {
+			return d, d + "/" + name + "connect", true
+		}
+	}
+	return "", "", false
+}
@@ -0,0 +1,92 @@
+package storage
+
+// Test Plan for API Contracts:
+// - ResolveContractLinks and DeleteContractSymbols are no-ops before any contract was indexed
+// - Replacing a file's contract symbols drops its previous ones
+// - go_package import paths map to the longest matching Go package directory
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractSymbols(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBFile(t)
+	_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+		VALUES ('api/shop.proto', 'protobuf', 'api', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`)
+	require.NoError(t, err)
+
+	withTx := func(fn func(tx *sql.Tx) error) {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+
+	withTx(func(tx *sql.Tx) error {
+		count, err := ResolveContractLinks(tx)
+		assert.Zero(t, count)
+		if err != nil {
+			return err
+		}
+		return DeleteContractSymbols(tx, "api/shop.proto")
+	})
+
+	symbols := func() []string {
+		rows, err := db.Query("SELECT qualified_name FROM contract_symbols ORDER BY qualified_name")
+		require.NoError(t, err)
+		defer rows.Close()
+		var names []string
+		for rows.Next() {
+			var name string
+			require.NoError(t, rows.Scan(&name))
+			names = append(names, name)
+		}
+		return names
+	}
+
+	withTx(func(tx *sql.Tx) error {
+		return ReplaceContractSymbols(tx, "api/shop.proto", []ContractSymbol{
+			{SymbolID: "api/shop.proto::Order", Protocol: "protobuf", Kind: "message", Name: "shop.v1.Order"},
+			{SymbolID: "api/shop.proto::Shop", Protocol: "protobuf", Kind: "service", Name: "shop.v1.Shop"},
+		})
+	})
+	assert.Equal(t, []string{"shop.v1.Order", "shop.v1.Shop"}, symbols())
+
+	withTx(func(tx *sql.Tx) error {
+		return ReplaceContractSymbols(tx, "api/shop.proto", []ContractSymbol{
+			{SymbolID: "api/shop.proto::Cart", Protocol: "protobuf", Kind: "message", Name: "shop.v1.Cart"},
+		})
+	})
+	assert.Equal(t, []string{"shop.v1.Cart"}, symbols())
+
+	// Deleting the file cascades
+	_, err = db.Exec("DELETE FROM files WHERE file_path = 'api/shop.proto'")
+	require.NoError(t, err)
+	assert.Empty(t, symbols())
+}
+
+func TestGoPackageDirs(t *testing.T) {
+	t.Parallel()
+
+	dirs := []string{"internal/gen/shop/v1", "gen/shop/v1", "v1", "main"}
+
+	dir, connectDir, ok := goPackageDirs("example.com/app/gen/shop/v1;shopv1", dirs)
+	assert.True(t, ok)
+	assert.Equal(t, "gen/shop/v1", dir)
+	assert.Equal(t, "gen/shop/v1/shopv1connect", connectDir)
+
+	// Package name defaults to the last path element
+	_, connectDir, ok = goPackageDirs("example.com/app/internal/gen/shop/v1", dirs)
+	assert.True(t, ok)
+	assert.Equal(t, "internal/gen/shop/v1/v1connect", connectDir)
+
+	_, _, ok = goPackageDirs("example.com/app/gen/cart/v2", dirs)
+	assert.False(t, ok)
+}
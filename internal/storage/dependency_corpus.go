@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattn/go-sqlite3"
+)
+
+// DependencyCorpusSchema is the schema name the dependency corpus is
+// attached under (e.g., deps.chunks).
+const DependencyCorpusSchema = "deps"
+
+// DependencyCorpusPath returns the dependency corpus database of a cache
+// location. It is shared by the project's branches, since dependencies are
+// identified by name and version.
+//
+// Layout: {cacheLocation}/dependencies.db
+func DependencyCorpusPath(cacheLocation string) string {
+	return filepath.Join(cacheLocation, "dependencies.db")
+}
+
+// CorpusPackage is a dependency package indexed into the dependency corpus.
+// Its files are stored under PathPrefix (e.g., "go/github.com/pkg/errors@v0.9.1/").
+type CorpusPackage struct {
+	Ecosystem   string
+	Name        string
+	Version     string
+	PathPrefix  string
+	SourceDir   string // Directory the files were read from
+	Fingerprint string // Identifies the indexed file set; re-indexed when it changes
+	FileCount   int
+}
+
+var (
+	corpusDriversMu sync.Mutex
+	corpusDrivers   = make(map[string]string) // Corpus path → driver name
+)
+
+// DependencyCorpusTable returns the name a reader queries a table of the
+// attached dependency corpus by: a temp view of its committed generation.
+func DependencyCorpusTable(table string) string {
+	return DependencyCorpusSchema + "_" + table
+}
+
+// OpenCommitted opens a read-only database whose connections see the last
+// committed index generation (see Generation).
+func OpenCommitted(dsn string) (*sql.DB, error) {
+	return OpenWithDependencyCorpus(dsn, "")
+}
+
+// OpenWithDependencyCorpus opens a read-only database whose connections see
+// the last committed index generation (see Generation) and attach the
+// dependency corpus at corpusPath read-only, as DependencyCorpusSchema.
+// Connections opened before the corpus exists (or when attaching fails)
+// work without it; see HasDependencyCorpus.
+func OpenWithDependencyCorpus(dsn, corpusPath string) (*sql.DB, error) {
+	return sql.Open(dependencyCorpusDriver(corpusPath), dsn)
+}
+
+// dependencyCorpusDriver registers (once per corpus path) a sqlite3 driver
+// whose connect hook creates the committed generation views and attaches
+// the corpus.
+func dependencyCorpusDriver(corpusPath string) string {
+	corpusDriversMu.Lock()
+	defer corpusDriversMu.Unlock()
+
+	if name, ok := corpusDrivers[corpusPath]; ok {
+		return name
+	}
+	name := fmt.Sprintf("sqlite3_dependency_corpus_%d", len(corpusDrivers))
+	sql.Register(name, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			if err := createCommittedViews(conn, "main", ""); err != nil {
+				return err
+			}
+
+			if corpusPath == "" {
+				return nil
+			}
+			if _, err := os.Stat(corpusPath); err != nil {
+				return nil
+			}
+			uri := "file:" + filepath.ToSlash(corpusPath) + "?mode=ro"
+			if _, err := conn.Exec("ATTACH DATABASE ? AS "+DependencyCorpusSchema, []driver.Value{uri}); err != nil {
+				log.Printf("Warning: failed to attach dependency corpus %s: %v", corpusPath, err)
+				return nil
+			}
+			if err := createCommittedViews(conn, DependencyCorpusSchema, DependencyCorpusTable("")); err != nil {
+				log.Printf("Warning: failed to read dependency corpus %s: %v", corpusPath, err)
+				conn.Exec("DETACH DATABASE "+DependencyCorpusSchema, nil)
+			}
+			return nil
+		},
+	})
+	corpusDrivers[corpusPath] = name
+	return name
+}
+
+// HasDependencyCorpus reports whether the connection has an indexed
+// dependency corpus attached.
+func HasDependencyCorpus(db sq.BaseRunner) (bool, error) {
+	var attached int
+	err := sq.Select("COUNT(*)").
+		From("pragma_database_list").
+		Where(sq.Eq{"name": DependencyCorpusSchema}).
+		RunWith(db).
+		QueryRow().
+		Scan(&attached)
+	if err != nil {
+		return false, fmt.Errorf("failed to list attached databases: %w", err)
+	}
+	if attached == 0 {
+		return false, nil
+	}
+
+	var packages int
+	err = sq.Select("COUNT(*)").
+		From(DependencyCorpusSchema + ".corpus_packages").
+		RunWith(db).
+		QueryRow().
+		Scan(&packages)
+	if err != nil {
+		return false, fmt.Errorf("failed to check dependency corpus: %w", err)
+	}
+	return packages > 0, nil
+}
+
+// ListCorpusPackages returns the packages indexed into a dependency corpus,
+// ordered by ecosystem and name.
+func ListCorpusPackages(db sq.BaseRunner) ([]CorpusPackage, error) {
+	rows, err := sq.Select("ecosystem", "name", "version", "path_prefix", "source_dir", "fingerprint", "file_count").
+		From("corpus_packages").
+		OrderBy("ecosystem", "name").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corpus packages: %w", err)
+	}
+	defer rows.Close()
+
+	var packages []CorpusPackage
+	for rows.Next() {
+		var p CorpusPackage
+		if err := rows.Scan(&p.Ecosystem, &p.Name, &p.Version, &p.PathPrefix, &p.SourceDir, &p.Fingerprint, &p.FileCount); err != nil {
+			return nil, fmt.Errorf("failed to scan corpus package: %w", err)
+		}
+		packages = append(packages, p)
+	}
+	return packages, rows.Err()
+}
+
+// PutCorpusPackage records an indexed package, replacing the previous
+// record of the same ecosystem and name.
+func PutCorpusPackage(tx *sql.Tx, p CorpusPackage) error {
+	_, err := sq.Insert("corpus_packages").
+		Options("OR REPLACE").
+		Columns("ecosystem", "name", "version", "path_prefix", "source_dir", "fingerprint", "file_count", "indexed_at").
+		Values(p.Ecosystem, p.Name, p.Version, p.PathPrefix, p.SourceDir, p.Fingerprint, p.FileCount, time.Now().UTC().Format(time.RFC3339)).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to record corpus package %s: %w", p.Name, err)
+	}
+	return nil
+}
+
+// DeleteCorpusPackage removes a package's record and every file, chunk and
+// vector stored under its path prefix.
+func DeleteCorpusPackage(tx *sql.Tx, p CorpusPackage) error {
+	underPrefix := sq.Expr("substr(file_path, 1, ?) = ?", len(p.PathPrefix), p.PathPrefix)
+
+	chunkIDs, err := deletableVectors(tx, underPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to query chunks of %s: %w", p.Name, err)
+	}
+
+	if len(chunkIDs) > 0 {
+		index, err := OpenVectorIndex(tx)
+		if err != nil {
+			return fmt.Errorf("failed to open vector index: %w", err)
+		}
+		if err := index.Delete(tx, chunkIDs); err != nil {
+			return fmt.Errorf("failed to delete vectors of %s: %w", p.Name, err)
+		}
+	}
+
+	// files_fts follows files through its triggers
+	for _, table := range []string{"chunks", "files"} {
+		if _, err := sq.Delete(table).Where(underPrefix).RunWith(tx).Exec(); err != nil {
+			return fmt.Errorf("failed to delete %s of %s: %w", table, p.Name, err)
+		}
+	}
+
+	_, err = sq.Delete("corpus_packages").
+		Where(sq.Eq{"ecosystem": p.Ecosystem, "name": p.Name}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete corpus package %s: %w", p.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package storage
+
+// Test Plan for the Dependency Corpus:
+// - PutCorpusPackage records packages; ListCorpusPackages reads them back
+// - DeleteCorpusPackage removes the package's files, chunks and FTS rows, and nothing else
+// - OpenWithDependencyCorpus attaches the corpus read-only once it exists
+// - HasDependencyCorpus is false without an attached, indexed corpus
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertCorpusTestFile(t *testing.T, db *sql.DB, filePath string) {
+	t.Helper()
+	content := "package retry\n\nfunc Do() {}\n"
+	now := time.Now()
+	require.NoError(t, NewFileWriter(db).WriteFile(&FileStats{
+		FilePath: filePath, Language: "go", ModulePath: "retry", FileHash: "h", LastModified: now, IndexedAt: now,
+	}, &content))
+	require.NoError(t, NewChunkWriterWithDB(db).WriteChunksIncremental([]*Chunk{{
+		ID: "chunk-" + filePath, FilePath: filePath, ChunkType: "definitions", Title: "Do", Text: "func Do() {}",
+		Embedding: make([]float32, 384), CreatedAt: now, UpdatedAt: now,
+	}}))
+}
+
+func TestCorpusPackages(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	packages, err := ListCorpusPackages(db)
+	require.NoError(t, err)
+	assert.Empty(t, packages)
+
+	retry := CorpusPackage{Ecosystem: "go", Name: "github.com/pkg/retry", Version: "v1.0.0",
+		PathPrefix: "go/github.com/pkg/retry@v1.0.0/", SourceDir: "/mod/github.com/pkg/retry@v1.0.0", Fingerprint: "a", FileCount: 1}
+	retryable := CorpusPackage{Ecosystem: "go", Name: "github.com/pkg/retryable", Version: "v2.0.0",
+		PathPrefix: "go/github.com/pkg/retryable@v2.0.0/", SourceDir: "/mod/github.com/pkg/retryable@v2.0.0", Fingerprint: "b", FileCount: 1}
+	insertCorpusTestFile(t, db, "go/github.com/pkg/retry@v1.0.0/retry.go")
+	insertCorpusTestFile(t, db, "go/github.com/pkg/retryable@v2.0.0/retry.go")
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		if err := PutCorpusPackage(tx, retry); err != nil {
+			return err
+		}
+		return PutCorpusPackage(tx, retryable)
+	}))
+
+	packages, err = ListCorpusPackages(db)
+	require.NoError(t, err)
+	assert.Equal(t, []CorpusPackage{retry, retryable}, packages)
+
+	// Only files under the exact prefix go
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return DeleteCorpusPackage(tx, retry)
+	}))
+	packages, err = ListCorpusPackages(db)
+	require.NoError(t, err)
+	assert.Equal(t, []CorpusPackage{retryable}, packages)
+
+	count := func(query string) int {
+		var n int
+		require.NoError(t, db.QueryRow(query).Scan(&n))
+		return n
+	}
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM files"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM chunks"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM files_fts"))
+	assert.Equal(t, 1, count("SELECT COUNT(*) FROM chunks_vec"))
+}
+
+func TestOpenWithDependencyCorpus(t *testing.T) {
+	t.Parallel()
+	InitVectorExtension()
+
+	dir := t.TempDir()
+	projectPath, corpusPath := filepath.Join(dir, "project.db"), DependencyCorpusPath(dir)
+	project, err := sql.Open("sqlite3", projectPath)
+	require.NoError(t, err)
+	require.NoError(t, CreateSchema(project))
+	require.NoError(t, project.Close())
+
+	hasCorpus := func() bool {
+		db, err := OpenWithDependencyCorpus(projectPath+"?mode=ro", corpusPath)
+		require.NoError(t, err)
+		defer db.Close()
+		attached, err := HasDependencyCorpus(db)
+		require.NoError(t, err)
+		return attached
+	}
+
+	// No corpus yet
+	assert.False(t, hasCorpus())
+
+	// A corpus nothing was indexed into
+	corpus, err := sql.Open("sqlite3", corpusPath)
+	require.NoError(t, err)
+	defer corpus.Close()
+	require.NoError(t, CreateSchema(corpus))
+	assert.False(t, hasCorpus())
+
+	require.NoError(t, WithTx(corpus, func(tx *sql.Tx) error {
+		return PutCorpusPackage(tx, CorpusPackage{Ecosystem: "npm", Name: "react", PathPrefix: "npm/react@18.3.1/",
+			SourceDir: "/web/node_modules/react", Fingerprint: "a", FileCount: 2})
+	}))
+	assert.True(t, hasCorpus())
+
+	// Attached read-only
+	db, err := OpenWithDependencyCorpus(projectPath+"?mode=ro", corpusPath)
+	require.NoError(t, err)
+	defer db.Close()
+	var name string
+	require.NoError(t, db.QueryRow("SELECT name FROM deps.corpus_packages").Scan(&name))
+	assert.Equal(t, "react", name)
+	_, err = db.Exec("DELETE FROM deps.corpus_packages")
+	assert.Error(t, err)
+}
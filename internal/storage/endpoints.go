@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"path"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/graph"
+)
+
+// ReplaceEndpoints replaces the endpoints registered in filePath.
+// Call ResolveEndpointHandlers afterwards to link them to their handlers.
+func ReplaceEndpoints(tx *sql.Tx, filePath string, endpoints []graph.Endpoint) error {
+	if err := DeleteEndpoints(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, e := range endpoints {
+		_, err := sq.Insert("endpoints").
+			Columns("file_path", "line", "framework", "kind", "method", "path", "handler", "handler_name", "handler_scope").
+			Values(filePath, e.Line, e.Framework, e.Kind, e.Method, e.Path, e.Handler, e.HandlerName, e.HandlerScope).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert endpoint %s %s: %w", e.Method, e.Path, err)
+		}
+	}
+	return nil
+}
+
+// DeleteEndpoints removes the endpoints registered in filePath.
+func DeleteEndpoints(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("endpoints").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete endpoints for %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// handlerCandidate is an indexed function an endpoint handler may resolve to.
+type handlerCandidate struct {
+	id       string
+	module   string // module_path: the package directory for Go
+	receiver string
+	language string
+}
+
+// ResolveEndpointHandlers links every endpoint to the function handling it
+// and returns how many were linked. Handlers resolve by name among the
+// functions of the endpoint file's language:
+//   - RPC procedures: the method of the one type implementing the generated
+//     service interface (HandlerScope), other than the Unimplemented* stubs
+//   - routes: a function whose receiver type or package is the handler's
+//     scope, else one in the endpoint file's package, else the only function
+//     of that name
+//
+// Endpoints whose handler is inline or ambiguous stay unresolved.
+func ResolveEndpointHandlers(tx *sql.Tx) (int, error) {
+	if _, err := tx.Exec("UPDATE endpoints SET handler_function_id = NULL"); err != nil {
+		return 0, fmt.Errorf("failed to clear endpoint handlers: %w", err)
+	}
+
+	candidates, err := loadHandlerCandidates(tx)
+	if err != nil {
+		return 0, err
+	}
+	implementers, err := loadImplementers(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := sq.Select("e.endpoint_id", "e.file_path", "e.kind", "e.handler_name", "e.handler_scope", "f.language").
+		From("endpoints e").
+		Join("files f ON f.file_path = e.file_path").
+		Where(sq.NotEq{"e.handler_name": ""}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	resolved := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var filePath, kind, name, scope, language string
+		if err := rows.Scan(&id, &filePath, &kind, &name, &scope, &language); err != nil {
+			return 0, fmt.Errorf("failed to scan endpoint: %w", err)
+		}
+
+		var matches []handlerCandidate
+		for _, c := range candidates[name] {
+			if c.language == language {
+				matches = append(matches, c)
+			}
+		}
+
+		var tiers []func(handlerCandidate) bool
+		if kind == graph.EndpointRPC {
+			tiers = append(tiers, func(c handlerCandidate) bool {
+				return c.receiver != "" && !strings.HasPrefix(c.receiver, "Unimplemented") &&
+					implementers[scope][c.module+"::"+c.receiver]
+			})
+		} else {
+			module := path.Dir(filePath)
+			if module == "." {
+				module = "main"
+			}
+			tiers = append(tiers,
+				func(c handlerCandidate) bool {
+					return scope != "" && (c.receiver == scope || path.Base(c.module) == scope)
+				},
+				func(c handlerCandidate) bool { return c.module == module },
+				func(c handlerCandidate) bool { return true },
+			)
+		}
+		for _, tier := range tiers {
+			var found []handlerCandidate
+			for _, c := range matches {
+				if tier(c) {
+					found = append(found, c)
+				}
+			}
+			if len(found) == 1 {
+				resolved[id] = found[0].id
+			}
+			if len(found) > 0 {
+				break
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	for id, functionID := range resolved {
+		_, err := sq.Update("endpoints").
+			Set("handler_function_id", functionID).
+			Where(sq.Eq{"endpoint_id": id}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return 0, fmt.Errorf("failed to link endpoint %d to %s: %w", id, functionID, err)
+		}
+	}
+	return len(resolved), nil
+}
+
+// loadHandlerCandidates indexes the functions by name.
+func loadHandlerCandidates(tx *sql.Tx) (map[string][]handlerCandidate, error) {
+	rows, err := sq.Select("fn.function_id", "fn.name", "COALESCE(fn.module_path, '')", "COALESCE(fn.receiver_type_name, '')", "f.language").
+		From("functions fn").
+		Join("files f ON f.file_path = fn.file_path").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query functions: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make(map[string][]handlerCandidate)
+	for rows.Next() {
+		var c handlerCandidate
+		var name string
+		if err := rows.Scan(&c.id, &name, &c.module, &c.receiver, &c.language); err != nil {
+			return nil, fmt.Errorf("failed to scan function: %w", err)
+		}
+		candidates[name] = append(candidates[name], c)
+	}
+	return candidates, rows.Err()
+}
+
+// loadImplementers returns, by interface name, the IDs of the types
+// implementing it.
+func loadImplementers(tx *sql.Tx) (map[string]map[string]bool, error) {
+	rows, err := sq.Select("from_type_id", "to_type_id").
+		From("type_relationships").
+		Where(sq.Eq{"relationship_type": "implements"}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query type relationships: %w", err)
+	}
+	defer rows.Close()
+
+	implementers := make(map[string]map[string]bool)
+	for rows.Next() {
+		var from, to string
+		if err := rows.Scan(&from, &to); err != nil {
+			return nil, fmt.Errorf("failed to scan type relationship: %w", err)
+		}
+		iface := to
+		if i := strings.LastIndex(to, "::"); i >= 0 {
+			iface = to[i+2:]
+		}
+		if implementers[iface] == nil {
+			implementers[iface] = make(map[string]bool)
+		}
+		implementers[iface][from] = true
+	}
+	return implementers, rows.Err()
+}
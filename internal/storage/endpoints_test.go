@@ -0,0 +1,132 @@
+package storage
+
+// Test Plan for Endpoints:
+// - ResolveEndpointHandlers and DeleteEndpoints are no-ops before any endpoint was indexed
+// - Route handlers resolve by scope, then by the endpoint's package, then by a unique name
+// - RPC handlers resolve to the implementation of the service interface, not its stubs
+// - Ambiguous and inline handlers stay unresolved; deleting the file cascades
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpoints(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBFile(t)
+	for _, f := range []struct{ path, module string }{
+		{"api/router.go", "api"},
+		{"api/handlers.go", "api"},
+		{"orders/handlers.go", "orders"},
+		{"users/handlers.go", "users"},
+		{"billing/handlers.go", "billing"},
+		{"gen/shopv1connect/shop.connect.go", "gen/shopv1connect"},
+		{"server/shop.go", "server"},
+	} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, 'go', ?, 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, f.path, f.module)
+		require.NoError(t, err)
+	}
+	for _, fn := range []struct{ id, file, module, name, receiver string }{
+		{"api/handlers.go::health", "api/handlers.go", "api", "health", ""},
+		{"orders/handlers.go::Handler.List", "orders/handlers.go", "orders", "List", "Handler"},
+		{"users/handlers.go::Handler.List", "users/handlers.go", "users", "List", "Handler"},
+		{"orders/handlers.go::Show", "orders/handlers.go", "orders", "Show", ""},
+		{"billing/handlers.go::Show", "billing/handlers.go", "billing", "Show", ""},
+		{"billing/handlers.go::Charge", "billing/handlers.go", "billing", "Charge", ""},
+		{"gen/shopv1connect/shop.connect.go::UnimplementedShopHandler.Order", "gen/shopv1connect/shop.connect.go", "gen/shopv1connect", "Order", "UnimplementedShopHandler"},
+		{"server/shop.go::Server.Order", "server/shop.go", "server", "Order", "Server"},
+	} {
+		_, err := db.Exec(`INSERT INTO functions (function_id, file_path, module_path, name, start_line, end_line, receiver_type_name)
+			VALUES (?, ?, ?, ?, 1, 2, NULLIF(?, ''))`, fn.id, fn.file, fn.module, fn.name, fn.receiver)
+		require.NoError(t, err)
+	}
+	for _, typ := range []struct{ id, file, module, name string }{
+		{"gen/shopv1connect::ShopHandler", "gen/shopv1connect/shop.connect.go", "gen/shopv1connect", "ShopHandler"},
+		{"gen/shopv1connect::UnimplementedShopHandler", "gen/shopv1connect/shop.connect.go", "gen/shopv1connect", "UnimplementedShopHandler"},
+		{"server::Server", "server/shop.go", "server", "Server"},
+	} {
+		_, err := db.Exec(`INSERT INTO types (type_id, file_path, module_path, name, kind, start_line, end_line)
+			VALUES (?, ?, ?, ?, 'struct', 1, 2)`, typ.id, typ.file, typ.module, typ.name)
+		require.NoError(t, err)
+	}
+	for i, from := range []string{"server::Server", "gen/shopv1connect::UnimplementedShopHandler"} {
+		_, err := db.Exec(`INSERT INTO type_relationships (relationship_id, from_type_id, to_type_id, relationship_type, source_file_path, source_line)
+			VALUES (?, ?, 'gen/shopv1connect::ShopHandler', 'implements', 'server/shop.go', 1)`, i, from)
+		require.NoError(t, err)
+	}
+
+	withTx := func(fn func(tx *sql.Tx) error) {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+
+	withTx(func(tx *sql.Tx) error {
+		count, err := ResolveEndpointHandlers(tx)
+		assert.Zero(t, count)
+		if err != nil {
+			return err
+		}
+		return DeleteEndpoints(tx, "api/router.go")
+	})
+
+	route := func(path, handler, name, scope string) graph.Endpoint {
+		return graph.Endpoint{Framework: "chi", Kind: graph.EndpointHTTP, Method: "GET", Path: path, Handler: handler, HandlerName: name, HandlerScope: scope}
+	}
+	withTx(func(tx *sql.Tx) error {
+		if err := ReplaceEndpoints(tx, "api/router.go", []graph.Endpoint{
+			route("/health", "health", "health", ""),                // Same package
+			route("/orders", "orders.List", "List", "orders"),       // Package scope
+			route("/users", "h.List", "List", "h"),                  // Ambiguous
+			route("/orders/{id}", "h.Show", "Show", "h"),            // Ambiguous
+			route("/charge", "billing.Charge", "Charge", "billing"), // Package scope
+			route("/inline", "", "", ""),                            // Inline
+		}); err != nil {
+			return err
+		}
+		if err := ReplaceEndpoints(tx, "gen/shopv1connect/shop.connect.go", []graph.Endpoint{{
+			Framework: "connect", Kind: graph.EndpointRPC, Method: "POST", Path: "/shop.v1.Shop/Order",
+			Handler: "ShopHandler.Order", HandlerName: "Order", HandlerScope: "ShopHandler",
+		}}); err != nil {
+			return err
+		}
+		count, err := ResolveEndpointHandlers(tx)
+		assert.Equal(t, 4, count)
+		return err
+	})
+
+	handlers := func() map[string]string {
+		rows, err := db.Query("SELECT path, COALESCE(handler_function_id, '') FROM endpoints")
+		require.NoError(t, err)
+		defer rows.Close()
+		found := make(map[string]string)
+		for rows.Next() {
+			var path, handler string
+			require.NoError(t, rows.Scan(&path, &handler))
+			found[path] = handler
+		}
+		return found
+	}
+	assert.Equal(t, map[string]string{
+		"/health":             "api/handlers.go::health",
+		"/orders":             "orders/handlers.go::Handler.List",
+		"/users":              "",
+		"/orders/{id}":        "",
+		"/charge":             "billing/handlers.go::Charge",
+		"/inline":             "",
+		"/shop.v1.Shop/Order": "server/shop.go::Server.Order",
+	}, handlers())
+
+	// Deleting the file cascades
+	_, err := db.Exec("DELETE FROM files WHERE file_path = 'api/router.go'")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"/shop.v1.Shop/Order": "server/shop.go::Server.Order"}, handlers())
+}
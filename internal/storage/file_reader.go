@@ -130,9 +130,10 @@ func (r *FileReader) GetFilesByModule(modulePath string) ([]*FileStats, error) {
 func (r *FileReader) GetFileContent(filePath string) (string, error) {
 	var content string
 
-	err := sq.Select("content").
-		From("files_fts").
-		Where(sq.Eq{"file_path": filePath}).
+	err := sq.Select("fts.content").
+		From("files_fts fts").
+		Join("files f ON f.file_path = fts.file_path AND f.generation = fts.generation").
+		Where(sq.Eq{"fts.file_path": filePath}).
 		RunWith(r.db).
 		QueryRow().
 		Scan(&content)
@@ -156,7 +157,7 @@ func (r *FileReader) SearchFileContent(query string) ([]*FileStats, error) {
 		"f.size_bytes", "f.file_hash", "f.last_modified", "f.indexed_at",
 	).
 		From("files_fts fts").
-		Join("files f ON fts.file_path = f.file_path").
+		Join("files f ON fts.file_path = f.file_path AND fts.generation = f.generation").
 		Where(sq.Expr("fts.content MATCH ?", query)).
 		OrderBy("rank").
 		RunWith(r.db).
@@ -58,37 +58,42 @@ func (w *FileWriter) WriteFile(file *FileStats, content *string) error {
 		contentVal = nil // SQL NULL for binary files
 	}
 
-	_, err := sq.Insert("files").
-		Columns(
-			"file_path", "language", "module_path", "is_test",
-			"line_count_total", "line_count_code", "line_count_comment", "line_count_blank",
-			"size_bytes", "file_hash", "last_modified", "indexed_at",
-			"content",
-		).
-		Values(
-			file.FilePath,
-			file.Language,
-			file.ModulePath,
-			file.IsTest,
-			file.LineCountTotal,
-			file.LineCountCode,
-			file.LineCountComment,
-			file.LineCountBlank,
-			file.SizeBytes,
-			file.FileHash,
-			file.LastModified.Format(time.RFC3339),
-			file.IndexedAt.Format(time.RFC3339),
-			contentVal,
-		).
-		Options("OR REPLACE").
-		RunWith(w.db).
-		Exec()
-
-	if err != nil {
-		return fmt.Errorf("failed to write file for %s: %w", file.FilePath, err)
-	}
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		open, err := openGeneration(tx)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		insert := sq.Insert("files").
+			Columns(
+				"file_path", "language", "module_path", "is_test",
+				"line_count_total", "line_count_code", "line_count_comment", "line_count_blank",
+				"size_bytes", "file_hash", "last_modified", "indexed_at",
+				"content", "generation",
+			).
+			Values(
+				file.FilePath,
+				file.Language,
+				file.ModulePath,
+				file.IsTest,
+				file.LineCountTotal,
+				file.LineCountCode,
+				file.LineCountComment,
+				file.LineCountBlank,
+				file.SizeBytes,
+				file.FileHash,
+				file.LastModified.Format(time.RFC3339),
+				file.IndexedAt.Format(time.RFC3339),
+				contentVal,
+				open, // Set here so the generation trigger needn't rewrite the content
+			).
+			Options("OR REPLACE")
+
+		if _, err := insert.RunWith(tx).Exec(); err != nil {
+			return fmt.Errorf("failed to write file for %s: %w", file.FilePath, err)
+		}
+		return nil
+	})
 }
 
 // WriteFileStats writes or updates a single file's statistics without content.
@@ -107,58 +112,56 @@ func (w *FileWriter) WriteFileStatsBatch(stats []*FileStats) error {
 		return nil
 	}
 
-	tx, err := w.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback() // Safe to call even after commit
-
-	// Build the query once with Squirrel, then get SQL and args for preparation
-	builder := sq.Insert("files").
-		Columns(
-			"file_path", "language", "module_path", "is_test",
-			"line_count_total", "line_count_code", "line_count_comment", "line_count_blank",
-			"size_bytes", "file_hash", "last_modified", "indexed_at",
-		).
-		Options("OR REPLACE")
-
-	// Get SQL string for preparation (use a dummy values call)
-	sqlStr, _, err := builder.Values("", "", "", false, 0, 0, 0, 0, 0, "", "", "").ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build SQL: %w", err)
-	}
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		open, err := openGeneration(tx)
+		if err != nil {
+			return err
+		}
 
-	stmt, err := tx.Prepare(sqlStr)
-	if err != nil {
-		return fmt.Errorf("failed to prepare statement: %w", err)
-	}
-	defer stmt.Close()
-
-	for _, s := range stats {
-		_, err := stmt.Exec(
-			s.FilePath,
-			s.Language,
-			s.ModulePath,
-			s.IsTest,
-			s.LineCountTotal,
-			s.LineCountCode,
-			s.LineCountComment,
-			s.LineCountBlank,
-			s.SizeBytes,
-			s.FileHash,
-			s.LastModified.Format(time.RFC3339),
-			s.IndexedAt.Format(time.RFC3339),
-		)
+		// Build the query once with Squirrel, then get SQL and args for preparation
+		builder := sq.Insert("files").
+			Columns(
+				"file_path", "language", "module_path", "is_test",
+				"line_count_total", "line_count_code", "line_count_comment", "line_count_blank",
+				"size_bytes", "file_hash", "last_modified", "indexed_at", "generation",
+			).
+			Options("OR REPLACE")
+
+		// Get SQL string for preparation (use a dummy values call)
+		sqlStr, _, err := builder.Values("", "", "", false, 0, 0, 0, 0, 0, "", "", "", 0).ToSql()
 		if err != nil {
-			return fmt.Errorf("failed to insert file %s: %w", s.FilePath, err)
+			return fmt.Errorf("failed to build SQL: %w", err)
 		}
-	}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit batch: %w", err)
-	}
+		stmt, err := tx.Prepare(sqlStr)
+		if err != nil {
+			return fmt.Errorf("failed to prepare statement: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, s := range stats {
+			_, err := stmt.Exec(
+				s.FilePath,
+				s.Language,
+				s.ModulePath,
+				s.IsTest,
+				s.LineCountTotal,
+				s.LineCountCode,
+				s.LineCountComment,
+				s.LineCountBlank,
+				s.SizeBytes,
+				s.FileHash,
+				s.LastModified.Format(time.RFC3339),
+				s.IndexedAt.Format(time.RFC3339),
+				open,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert file %s: %w", s.FilePath, err)
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // WriteFileContent writes full file content to FTS5 table for keyword search.
@@ -181,91 +184,89 @@ func (w *FileWriter) WriteFileContentBatch(contents []*FileContent) error {
 		return nil
 	}
 
-	tx, err := w.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Build SQL for delete and insert statements
-	deleteSql, _, err := sq.Delete("files_fts").
-		Where(sq.Eq{"file_path": ""}).
-		ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build delete SQL: %w", err)
-	}
-
-	insertSql, _, err := sq.Insert("files_fts").
-		Columns("file_path", "content").
-		Values("", "").
-		ToSql()
-	if err != nil {
-		return fmt.Errorf("failed to build insert SQL: %w", err)
-	}
-
-	deleteStmt, err := tx.Prepare(deleteSql)
-	if err != nil {
-		return fmt.Errorf("failed to prepare delete statement: %w", err)
-	}
-	defer deleteStmt.Close()
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		open, err := openGeneration(tx)
+		if err != nil {
+			return err
+		}
 
-	insertStmt, err := tx.Prepare(insertSql)
-	if err != nil {
-		return fmt.Errorf("failed to prepare insert statement: %w", err)
-	}
-	defer insertStmt.Close()
+		// Build SQL for delete and insert statements. Entries of committed
+		// generations stay while a generation is open (see versionFilesFTS);
+		// new entries get the open generation, or the file's between runs.
+		deleteSql, _, err := sq.Delete("files_fts").
+			Where(sq.Eq{"file_path": ""}).
+			Where(sq.GtOrEq{"generation": open}).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build delete SQL: %w", err)
+		}
 
-	for _, c := range contents {
-		// Delete old entry
-		if _, err := deleteStmt.Exec(c.FilePath); err != nil {
-			return fmt.Errorf("failed to delete FTS entry for %s: %w", c.FilePath, err)
+		insertSql, _, err := sq.Insert("files_fts").
+			Columns("file_path", "content", "generation").
+			Values("", "", sq.Expr("COALESCE(NULLIF(?, 0), (SELECT generation FROM files WHERE file_path = ?), 0)", open, "")).
+			ToSql()
+		if err != nil {
+			return fmt.Errorf("failed to build insert SQL: %w", err)
 		}
 
-		// Insert new content
-		if _, err := insertStmt.Exec(c.FilePath, c.Content); err != nil {
-			return fmt.Errorf("failed to insert FTS content for %s: %w", c.FilePath, err)
+		deleteStmt, err := tx.Prepare(deleteSql)
+		if err != nil {
+			return fmt.Errorf("failed to prepare delete statement: %w", err)
 		}
-	}
+		defer deleteStmt.Close()
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit FTS batch: %w", err)
-	}
+		insertStmt, err := tx.Prepare(insertSql)
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert statement: %w", err)
+		}
+		defer insertStmt.Close()
+
+		for _, c := range contents {
+			// Delete old entry
+			if _, err := deleteStmt.Exec(c.FilePath, open); err != nil {
+				return fmt.Errorf("failed to delete FTS entry for %s: %w", c.FilePath, err)
+			}
+
+			// Insert new content
+			if _, err := insertStmt.Exec(c.FilePath, c.Content, open, c.FilePath); err != nil {
+				return fmt.Errorf("failed to insert FTS content for %s: %w", c.FilePath, err)
+			}
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // DeleteFile removes a file from the database.
 // CASCADE deletes propagate to types, functions, chunks, etc.
 func (w *FileWriter) DeleteFile(filePath string) error {
-	tx, err := w.db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Delete from files table (cascades to related tables via FK constraints)
-	_, err = sq.Delete("files").
-		Where(sq.Eq{"file_path": filePath}).
-		RunWith(tx).
-		Exec()
-	if err != nil {
-		return fmt.Errorf("failed to delete file %s: %w", filePath, err)
-	}
-
-	// Delete from FTS5 table
-	_, err = sq.Delete("files_fts").
-		Where(sq.Eq{"file_path": filePath}).
-		RunWith(tx).
-		Exec()
-	if err != nil {
-		return fmt.Errorf("failed to delete FTS entry for %s: %w", filePath, err)
-	}
+	return WithTx(w.db, func(tx *sql.Tx) error {
+		// Delete from files table (cascades to related tables via FK constraints)
+		_, err := sq.Delete("files").
+			Where(sq.Eq{"file_path": filePath}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to delete file %s: %w", filePath, err)
+		}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit delete: %w", err)
-	}
+		// Delete from FTS5 table (entries written without a files row; the
+		// committed entry stays while a generation is open)
+		open, err := openGeneration(tx)
+		if err != nil {
+			return err
+		}
+		_, err = sq.Delete("files_fts").
+			Where(sq.Eq{"file_path": filePath}).
+			Where(sq.GtOrEq{"generation": open}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to delete FTS entry for %s: %w", filePath, err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // Close releases resources held by the writer.
@@ -0,0 +1,474 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/gofrs/flock"
+)
+
+// Index generations group the writes of an index run so readers never see
+// half-written state.
+//
+// Every row of a versioned table records the generation that wrote it.
+// While a run is open (BeginGeneration), triggers stamp inserted and updated
+// rows with the open generation and copy the rows they replace or delete into
+// <table>_retired, tagged with the open generation. Read-only connections
+// (OpenWithDependencyCorpus) see the tables through temp views holding the
+// rows of the committed generation: live rows written up to it plus retired
+// rows it still had. Commit flips index_generation in one short transaction,
+// then deletes the retired rows in batches.
+//
+// files_fts and config_keys_fts keep the entries of retired rows until the
+// flip, tagged with the row's generation. Vectors are keyed by chunk ID, so
+// the vector of a rewritten chunk is replaced in place and readers may rank
+// the chunk's committed text by its new vector until the flip. Vectors of
+// deleted chunks stay until the flip.
+//
+// Writes made while no run is open are visible at once.
+
+// cache_metadata keys of the generation pointers.
+const (
+	generationMetadataKey     = "index_generation"      // Last committed generation
+	openGenerationMetadataKey = "index_generation_open" // Generation being written, absent between runs
+)
+
+// WriterDSNParams are the DSN parameters connections that write the index
+// need: the generation triggers must fire for rows INSERT OR REPLACE deletes.
+const WriterDSNParams = "_recursive_triggers=on"
+
+// versionedTables hold indexed content. Each has a generation column, a
+// <table>_retired table and the triggers created by versionTables.
+var versionedTables = []string{
+	"files",
+	"types",
+	"type_fields",
+	"functions",
+	"function_parameters",
+	"type_relationships",
+	"function_calls",
+	"imports",
+	"chunks",
+	"declared_supertypes",
+	"call_resolutions",
+	"workspace_modules",
+	"file_modules",
+	"import_resolutions",
+	"header_implementations",
+	"dependencies",
+	"corpus_packages",
+	"content_sources",
+	"contract_symbols",
+	"contract_links",
+	"config_keys",
+	"endpoints",
+	"config_usages",
+	"table_usages",
+	"secret_findings",
+}
+
+// openGenerationSQL evaluates to the open generation, or NULL between runs.
+const openGenerationSQL = "(SELECT CAST(value AS INTEGER) FROM cache_metadata WHERE key = '" + openGenerationMetadataKey + "')"
+
+// retiredBatchSize bounds the rows deleted per transaction after a flip.
+const retiredBatchSize = 5000
+
+// Generation is an open index run. Rows written while it is open are
+// invisible to readers until Commit.
+//
+// Only one generation can be open per database. A run that fails without
+// committing leaves its generation open, and the next BeginGeneration
+// resumes it.
+type Generation struct {
+	db   *sql.DB
+	id   int64
+	lock *flock.Flock // Serializes runs across processes (nil for in-memory databases)
+	done bool
+}
+
+// BeginGeneration opens the next index generation on db, or resumes one an
+// interrupted run left open. It waits for a run holding the database in
+// another process (or Storage) to finish, until ctx is done.
+func BeginGeneration(ctx context.Context, db *sql.DB) (*Generation, error) {
+	lock, err := lockGenerations(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var id int64
+	err = WithTx(db, func(tx *sql.Tx) error {
+		open, err := openGeneration(tx)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			id = open
+			return nil
+		}
+
+		committed, err := CommittedGeneration(tx)
+		if err != nil {
+			return err
+		}
+		id = committed + 1
+		return writeMetadataValue(tx, openGenerationMetadataKey, strconv.FormatInt(id, 10))
+	})
+	if err != nil {
+		if lock != nil {
+			lock.Unlock()
+		}
+		return nil, fmt.Errorf("failed to begin index generation: %w", err)
+	}
+
+	return &Generation{db: db, id: id, lock: lock}, nil
+}
+
+// ID returns the generation number readers will see once it commits.
+func (g *Generation) ID() int64 {
+	return g.id
+}
+
+// Commit publishes the generation's writes by flipping the committed
+// pointer, then removes the rows it retired and releases the generation.
+func (g *Generation) Commit() error {
+	if g.done {
+		return fmt.Errorf("index generation %d already finished", g.id)
+	}
+
+	err := WithTx(g.db, func(tx *sql.Tx) error {
+		if err := writeMetadataValue(tx, generationMetadataKey, strconv.FormatInt(g.id, 10)); err != nil {
+			return err
+		}
+		_, err := sq.Delete("cache_metadata").
+			Where(sq.Eq{"key": openGenerationMetadataKey}).
+			RunWith(tx).
+			Exec()
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to commit index generation %d: %w", g.id, err)
+	}
+
+	// Readers can no longer see retired rows; a later commit retries leftovers
+	if err := collectRetired(g.db, g.id); err != nil {
+		log.Printf("Warning: failed to remove rows retired by index generation %d: %v", g.id, err)
+	}
+	return g.Close()
+}
+
+// Close releases the generation without committing it; its writes stay
+// invisible until a later run resumes and commits it. Safe to call after
+// Commit, so callers can defer it.
+func (g *Generation) Close() error {
+	if g.done {
+		return nil
+	}
+	g.done = true
+
+	if g.lock != nil {
+		if err := g.lock.Unlock(); err != nil {
+			return fmt.Errorf("failed to release index generation %d: %w", g.id, err)
+		}
+	}
+	return nil
+}
+
+// lockGenerations takes the lock file serializing index runs on db's file.
+// In-memory databases have no file and need no lock.
+func lockGenerations(ctx context.Context, db *sql.DB) (*flock.Flock, error) {
+	var path string
+	err := sq.Select("file").
+		From("pragma_database_list").
+		Where(sq.Eq{"name": "main"}).
+		RunWith(db).
+		QueryRow().
+		Scan(&path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate database file: %w", err)
+	}
+	if path == "" {
+		return nil, nil
+	}
+
+	lock := flock.New(path + ".lock")
+	locked, err := lock.TryLockContext(ctx, 100*time.Millisecond)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock %s for indexing: %w", path, err)
+	}
+	if !locked {
+		return nil, fmt.Errorf("failed to lock %s for indexing", path)
+	}
+	return lock, nil
+}
+
+// openGeneration returns the generation being written, or 0 between runs.
+func openGeneration(db sq.BaseRunner) (int64, error) {
+	value, err := readMetadataValue(db, openGenerationMetadataKey)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", openGenerationMetadataKey, value, err)
+	}
+	return generation, nil
+}
+
+// WithTx runs fn in a new transaction on db, committing if fn succeeds.
+func WithTx(db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// CommittedGeneration returns the last committed index generation
+// (0 for databases that have never committed one or have no metadata table).
+// Accepts either *sql.DB or *sql.Tx; inside a read transaction the result
+// identifies the generation every query in that transaction observes.
+func CommittedGeneration(db sq.BaseRunner) (int64, error) {
+	exists, err := metadataTableExists(db)
+	if err != nil {
+		return 0, err
+	}
+	if !exists {
+		return 0, nil
+	}
+
+	value, err := readMetadataValue(db, generationMetadataKey)
+	if err != nil {
+		return 0, err
+	}
+	if value == "" {
+		return 0, nil
+	}
+
+	generation, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", generationMetadataKey, value, err)
+	}
+	return generation, nil
+}
+
+// BeginSnapshot starts a read transaction and returns the committed index
+// generation it observes. Every query run on the transaction sees that
+// generation, even if the indexer commits a newer one meanwhile.
+func BeginSnapshot(ctx context.Context, db *sql.DB) (*sql.Tx, int64, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to begin read transaction: %w", err)
+	}
+
+	generation, err := CommittedGeneration(tx)
+	if err != nil {
+		tx.Rollback()
+		return nil, 0, fmt.Errorf("failed to read index generation: %w", err)
+	}
+
+	return tx, generation, nil
+}
+
+// deletableVectors returns the IDs of chunks matching where (all chunks if
+// nil) whose vectors can be deleted with them. While a run is open, chunks of earlier
+// generations are still visible to readers, so only the run's own chunks
+// qualify; collectRetired deletes the others' vectors after the flip.
+func deletableVectors(tx *sql.Tx, where interface{}) ([]string, error) {
+	open, err := openGeneration(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := sq.Select("chunk_id").From("chunks").Where(where)
+	if open > 0 {
+		query = query.Where(sq.GtOrEq{"generation": open})
+	}
+	rows, err := query.RunWith(tx).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer rows.Close()
+
+	var chunkIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk_id: %w", err)
+		}
+		chunkIDs = append(chunkIDs, id)
+	}
+	return chunkIDs, rows.Err()
+}
+
+// collectRetired deletes the rows retired up to generation committed, with
+// the search index entries and vectors only they still used.
+func collectRetired(db *sql.DB, committed int64) error {
+	// Entries of retired files, found through files_retired before it is emptied
+	if err := WithTx(db, func(tx *sql.Tx) error {
+		return dropRetiredFileContent(tx, committed)
+	}); err != nil {
+		return fmt.Errorf("failed to delete retired file content: %w", err)
+	}
+
+	for _, table := range versionedTables {
+		for {
+			var deleted int64
+			err := WithTx(db, func(tx *sql.Tx) error {
+				rowids, err := retiredBatch(tx, table, committed)
+				if err != nil || len(rowids) == 0 {
+					return err
+				}
+				if err := dropRetiredEntries(tx, table, rowids); err != nil {
+					return err
+				}
+
+				result, err := sq.Delete(table + "_retired").
+					Where(sq.Eq{"rowid": rowids}).
+					RunWith(tx).
+					Exec()
+				if err != nil {
+					return err
+				}
+				deleted, err = result.RowsAffected()
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("failed to delete retired %s: %w", table, err)
+			}
+			if deleted < retiredBatchSize {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// retiredBatch returns up to retiredBatchSize rowids of table's rows retired
+// up to generation committed.
+func retiredBatch(tx *sql.Tx, table string, committed int64) ([]int64, error) {
+	rows, err := sq.Select("rowid").
+		From(table + "_retired").
+		Where(sq.LtOrEq{"retired_generation": committed}).
+		Limit(retiredBatchSize).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rowids []int64
+	for rows.Next() {
+		var rowid int64
+		if err := rows.Scan(&rowid); err != nil {
+			return nil, err
+		}
+		rowids = append(rowids, rowid)
+	}
+	return rowids, rows.Err()
+}
+
+// dropRetiredEntries deletes what retired rows of table (by rowid in
+// <table>_retired) kept alive outside the table: the vectors of deleted
+// chunks and the search entries of deleted config keys.
+func dropRetiredEntries(tx *sql.Tx, table string, rowids []int64) error {
+	switch table {
+	case "chunks":
+		rows, err := sq.Select("DISTINCT r.chunk_id").
+			From("chunks_retired r").
+			Where(sq.Eq{"r.rowid": rowids}).
+			Where("NOT EXISTS (SELECT 1 FROM chunks c WHERE c.chunk_id = r.chunk_id)").
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return err
+		}
+		var chunkIDs []string
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			chunkIDs = append(chunkIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil || len(chunkIDs) == 0 {
+			return err
+		}
+
+		index, err := OpenVectorIndex(tx)
+		if err != nil {
+			return fmt.Errorf("failed to open vector index: %w", err)
+		}
+		return index.Delete(tx, chunkIDs)
+
+	case "config_keys":
+		rows, err := sq.Select("key_id").
+			From("config_keys_retired").
+			Where(sq.Eq{"rowid": rowids}).
+			RunWith(tx).
+			Query()
+		if err != nil {
+			return err
+		}
+		var keyIDs []int64
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			keyIDs = append(keyIDs, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		_, err = sq.Delete("config_keys_fts").
+			Where(sq.Eq{"rowid": keyIDs}).
+			Where("rowid NOT IN (SELECT key_id FROM config_keys)").
+			RunWith(tx).
+			Exec()
+		return err
+	}
+	return nil
+}
+
+// dropRetiredFileContent deletes the files_fts entries of files retired up to
+// generation committed. files_fts can only be searched by content, so this
+// scans it once, and only when such files exist.
+func dropRetiredFileContent(tx *sql.Tx, committed int64) error {
+	var retired bool
+	err := tx.QueryRow("SELECT EXISTS (SELECT 1 FROM files_retired WHERE retired_generation <= ?)", committed).Scan(&retired)
+	if err != nil || !retired {
+		return err
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM files_fts WHERE rowid IN (
+			SELECT fts.rowid FROM files_fts fts
+			CROSS JOIN files_retired r ON r.file_path = fts.file_path AND r.generation = fts.generation
+			WHERE r.retired_generation <= ?
+		)`, committed)
+	return err
+}
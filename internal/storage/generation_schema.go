@@ -0,0 +1,446 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mattn/go-sqlite3"
+)
+
+// versionTables adds the generation column to the versioned tables that
+// exist, with their retired tables and triggers, and rebuilds files_fts and
+// config_keys_fts to track generations. Idempotent: triggers are recreated
+// and existing columns and tables are kept.
+//
+// The triggers only act while a generation is open (see BeginGeneration).
+// They need recursive triggers (the _recursive_triggers DSN parameter) to
+// see the rows INSERT OR REPLACE deletes.
+func versionTables(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := rebuildConfigKeys(tx); err != nil {
+		return err
+	}
+
+	for _, table := range versionedTables {
+		columns, err := tableColumns(tx, "main", table)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			continue // Created on first write
+		}
+
+		if !containsString(columns, "generation") {
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %q ADD COLUMN generation INTEGER NOT NULL DEFAULT 0", table)); err != nil {
+				return fmt.Errorf("failed to add generation to %s: %w", table, err)
+			}
+			columns = append(columns, "generation")
+		}
+		if err := createRetiredTable(tx, table, columns); err != nil {
+			return err
+		}
+		if err := createGenerationTriggers(tx, table, columns); err != nil {
+			return err
+		}
+	}
+
+	if err := versionFilesFTS(tx); err != nil {
+		return err
+	}
+	if err := versionConfigKeysFTS(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
+
+// rebuildConfigKeys recreates a config_keys table without key_id, keeping
+// rowids as key IDs. Its old external-content FTS table goes with it.
+func rebuildConfigKeys(tx *sql.Tx) error {
+	columns, err := tableColumns(tx, "main", "config_keys")
+	if err != nil || len(columns) == 0 || containsString(columns, "key_id") {
+		return err
+	}
+
+	for _, stmt := range []string{
+		"DROP TRIGGER IF EXISTS config_keys_fts_insert",
+		"DROP TRIGGER IF EXISTS config_keys_fts_delete",
+		"DROP TABLE IF EXISTS config_keys_fts",
+		"CREATE TEMP TABLE config_keys_rebuild AS SELECT rowid AS key_id, file_path, document, key_path, value, line FROM config_keys",
+		"DROP TABLE config_keys",
+		createConfigKeysTable,
+		`INSERT INTO config_keys (key_id, file_path, document, key_path, value, line)
+		SELECT key_id, file_path, document, key_path, value, line FROM temp.config_keys_rebuild`,
+		"DROP TABLE temp.config_keys_rebuild",
+	} {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to rebuild config_keys: %w", err)
+		}
+	}
+	return nil
+}
+
+// createRetiredTable creates <table>_retired with table's columns, the
+// retired row's rowid and the generation that retired it, plus the indexes
+// of table (without uniqueness) so readers can look retired rows up the same
+// way. Columns table gained since are added.
+func createRetiredTable(tx *sql.Tx, table string, columns []string) error {
+	retired := table + "_retired"
+	existing, err := tableColumns(tx, "main", retired)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) == 0 {
+		_, err := tx.Exec(fmt.Sprintf(
+			"CREATE TABLE %q (%s, retired_rowid INTEGER NOT NULL, retired_generation INTEGER NOT NULL)",
+			retired, quoteColumns(columns, "")))
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", retired, err)
+		}
+	} else {
+		for _, column := range columns {
+			if containsString(existing, column) {
+				continue
+			}
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %q ADD COLUMN %q", retired, column)); err != nil {
+				return fmt.Errorf("failed to add %s to %s: %w", column, retired, err)
+			}
+		}
+	}
+
+	indexes, err := tableIndexes(tx, table)
+	if err != nil {
+		return err
+	}
+	indexes = append(indexes, []string{"retired_generation"})
+	for i, indexColumns := range indexes {
+		_, err := tx.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %q ON %q (%s)",
+			fmt.Sprintf("idx_%s_%d", retired, i), retired, quoteColumns(indexColumns, "")))
+		if err != nil {
+			return fmt.Errorf("failed to index %s: %w", retired, err)
+		}
+	}
+	return nil
+}
+
+// createGenerationTriggers (re)creates the triggers of a versioned table.
+// While a generation is open, rows inserted or updated get its number, and
+// rows of earlier generations are copied into <table>_retired before an
+// update or delete changes them. Writers that set generation themselves on
+// insert skip the extra update.
+func createGenerationTriggers(tx *sql.Tx, table string, columns []string) error {
+	open := openGenerationSQL
+	retire := fmt.Sprintf("INSERT INTO %q (%s, retired_rowid, retired_generation) VALUES (%s, OLD.rowid, %s);",
+		table+"_retired", quoteColumns(columns, ""), quoteColumns(columns, "OLD."), open)
+	stamp := fmt.Sprintf("UPDATE %q SET generation = %s WHERE rowid = NEW.rowid;", table, open)
+	changed := fmt.Sprintf("OLD.generation < %s AND NEW.generation = OLD.generation", open)
+
+	triggers := []struct {
+		name, event, when, action string
+	}{
+		{"insert", "AFTER INSERT", fmt.Sprintf("NEW.generation <> %s", open), stamp},
+		{"update_retire", "BEFORE UPDATE", changed, retire},
+		{"update", "AFTER UPDATE", changed, stamp},
+		{"delete", "BEFORE DELETE", fmt.Sprintf("OLD.generation < %s", open), retire},
+	}
+	for _, t := range triggers {
+		name := fmt.Sprintf("%s_generation_%s", table, t.name)
+		if _, err := tx.Exec(fmt.Sprintf("DROP TRIGGER IF EXISTS %q", name)); err != nil {
+			return fmt.Errorf("failed to drop trigger %s: %w", name, err)
+		}
+		_, err := tx.Exec(fmt.Sprintf("CREATE TRIGGER %q %s ON %q WHEN %s BEGIN %s END",
+			name, t.event, table, t.when, t.action))
+		if err != nil {
+			return fmt.Errorf("failed to create trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// versionFilesFTS rebuilds a files_fts without the generation column and
+// (re)creates the triggers syncing it with files.content.
+//
+// Entries are only deleted for rows of the open generation (all rows between
+// runs); entries of retired rows stay searchable until collectRetired.
+// Binary files (content IS NULL) have no entry.
+func versionFilesFTS(tx *sql.Tx) error {
+	columns, err := tableColumns(tx, "main", "files_fts")
+	if err != nil {
+		return err
+	}
+	if !containsString(columns, "generation") {
+		for _, stmt := range []string{
+			"DROP TABLE IF EXISTS files_fts",
+			createFilesFTSTable,
+			`INSERT INTO files_fts (file_path, content, generation)
+			SELECT file_path, content, generation FROM files WHERE content IS NOT NULL`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("failed to rebuild files_fts: %w", err)
+			}
+		}
+	}
+
+	current := fmt.Sprintf("generation >= COALESCE(%s, 0)", openGenerationSQL)
+	insert := fmt.Sprintf(`INSERT INTO files_fts (file_path, content, generation)
+			SELECT NEW.file_path, NEW.content, COALESCE(%s, NEW.generation)
+			WHERE NEW.content IS NOT NULL;`, openGenerationSQL)
+	return recreateTriggers(tx, map[string]string{
+		// Also replaces the entry of an INSERT OR REPLACE within the same generation
+		"files_fts_insert": fmt.Sprintf(`CREATE TRIGGER files_fts_insert AFTER INSERT ON files
+		BEGIN
+			DELETE FROM files_fts WHERE file_path = NEW.file_path AND %s;
+			%s
+		END`, current, insert),
+		// A row restamped into the open generation needs an entry of it too
+		"files_fts_update": fmt.Sprintf(`CREATE TRIGGER files_fts_update AFTER UPDATE OF content, generation ON files
+		BEGIN
+			DELETE FROM files_fts WHERE file_path = OLD.file_path AND %s;
+			%s
+		END`, current, insert),
+		"files_fts_delete": fmt.Sprintf(`CREATE TRIGGER files_fts_delete AFTER DELETE ON files
+		WHEN OLD.content IS NOT NULL
+		BEGIN
+			DELETE FROM files_fts WHERE file_path = OLD.file_path AND %s;
+		END`, current),
+	})
+}
+
+// versionConfigKeysFTS (re)creates config_keys_fts, which indexes key paths
+// and values for cortex_exact. Entries use config_keys.key_id as rowid and
+// carry the key's file, document and generation, so readers can match them
+// to the committed config_keys. Entries of retired keys stay until
+// collectRetired. config_keys is only inserted into and deleted from.
+func versionConfigKeysFTS(tx *sql.Tx) error {
+	keyColumns, err := tableColumns(tx, "main", "config_keys")
+	if err != nil || len(keyColumns) == 0 {
+		return err
+	}
+
+	columns, err := tableColumns(tx, "main", "config_keys_fts")
+	if err != nil {
+		return err
+	}
+	if !containsString(columns, "generation") {
+		for _, stmt := range []string{
+			"DROP TRIGGER IF EXISTS config_keys_fts_insert",
+			"DROP TRIGGER IF EXISTS config_keys_fts_delete",
+			"DROP TABLE IF EXISTS config_keys_fts",
+			`CREATE VIRTUAL TABLE config_keys_fts USING fts5(
+				key_path,
+				value,
+				file_path UNINDEXED,
+				document UNINDEXED,
+				generation UNINDEXED,
+				tokenize = "unicode61 separators '._'"
+			)`,
+			`INSERT INTO config_keys_fts (rowid, key_path, value, file_path, document, generation)
+			SELECT key_id, key_path, value, file_path, document, generation FROM config_keys`,
+		} {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("failed to rebuild config_keys_fts: %w", err)
+			}
+		}
+	}
+
+	return recreateTriggers(tx, map[string]string{
+		"config_keys_fts_insert": fmt.Sprintf(`CREATE TRIGGER config_keys_fts_insert AFTER INSERT ON config_keys
+		BEGIN
+			INSERT INTO config_keys_fts (rowid, key_path, value, file_path, document, generation)
+			VALUES (NEW.key_id, NEW.key_path, NEW.value, NEW.file_path, NEW.document, COALESCE(%s, NEW.generation));
+		END`, openGenerationSQL),
+		"config_keys_fts_delete": fmt.Sprintf(`CREATE TRIGGER config_keys_fts_delete AFTER DELETE ON config_keys
+		WHEN NOT OLD.generation < COALESCE(%s, 0)
+		BEGIN
+			DELETE FROM config_keys_fts WHERE rowid = OLD.key_id;
+		END`, openGenerationSQL),
+	})
+}
+
+// recreateTriggers drops and creates triggers by name.
+func recreateTriggers(tx *sql.Tx, triggers map[string]string) error {
+	for name, ddl := range triggers {
+		if _, err := tx.Exec("DROP TRIGGER IF EXISTS " + name); err != nil {
+			return fmt.Errorf("failed to drop trigger %s: %w", name, err)
+		}
+		if _, err := tx.Exec(ddl); err != nil {
+			return fmt.Errorf("failed to create trigger %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// tableColumns returns the columns of a table in schema, in order
+// (none if the table does not exist).
+func tableColumns(db sq.BaseRunner, schema, table string) ([]string, error) {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?, ?) ORDER BY cid", table, schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+// tableIndexes returns the column lists of table's indexes, including those
+// of PRIMARY KEY and UNIQUE constraints. Expression indexes are skipped.
+func tableIndexes(db sq.BaseRunner, table string) ([][]string, error) {
+	rows, err := db.Query(`
+		SELECT il.name, ii.name FROM pragma_index_list(?) il, pragma_index_info(il.name) ii
+		ORDER BY il.seq, ii.seqno`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var names []string
+	columns := make(map[string][]string)
+	expression := make(map[string]bool)
+	for rows.Next() {
+		var index string
+		var column sql.NullString
+		if err := rows.Scan(&index, &column); err != nil {
+			return nil, fmt.Errorf("failed to scan index of %s: %w", table, err)
+		}
+		if _, ok := columns[index]; !ok {
+			names = append(names, index)
+		}
+		columns[index] = append(columns[index], column.String)
+		expression[index] = expression[index] || !column.Valid
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var indexes [][]string
+	for _, name := range names {
+		if !expression[name] {
+			indexes = append(indexes, columns[name])
+		}
+	}
+	return indexes, nil
+}
+
+// quoteColumns returns columns quoted and comma-separated, each prefixed
+// (e.g., "OLD.").
+func quoteColumns(columns []string, prefix string) string {
+	quoted := make([]string, len(columns))
+	for i, c := range columns {
+		quoted[i] = fmt.Sprintf("%s%q", prefix, c)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// containsString reports whether values contains value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// createViewPattern matches the start of a view's CREATE statement.
+var createViewPattern = regexp.MustCompile(`(?i)^\s*CREATE\s+VIEW\s+(IF\s+NOT\s+EXISTS\s+)?`)
+
+// createCommittedViews creates temp views on a reader connection showing
+// the committed generation of schema's versioned tables, named
+// prefix+table. With an empty prefix they shadow the main tables, and the
+// main views are copied into temp so they read the shadowing views too.
+// Tables of databases without generations are passed through under prefix.
+func createCommittedViews(conn *sqlite3.SQLiteConn, schema, prefix string) error {
+	tables, err := queryConnStrings(conn, fmt.Sprintf("SELECT name FROM %q.sqlite_master WHERE type = 'table'", schema))
+	if err != nil {
+		return err
+	}
+	versioned := containsString(tables, "cache_metadata")
+
+	committed := fmt.Sprintf("COALESCE((SELECT CAST(value AS INTEGER) FROM %q.cache_metadata WHERE key = '%s'), 0)",
+		schema, generationMetadataKey)
+	created := false
+	for _, table := range versionedTables {
+		if !containsString(tables, table) {
+			continue
+		}
+		var query string
+		if versioned && containsString(tables, table+"_retired") {
+			columns, err := queryConnStrings(conn, "SELECT name FROM pragma_table_info(?, ?) ORDER BY cid", table, schema)
+			if err != nil {
+				return err
+			}
+			list := quoteColumns(columns, "")
+			query = fmt.Sprintf(`SELECT %s FROM %q.%q WHERE generation <= %s
+				UNION ALL
+				SELECT %s FROM %q.%q WHERE generation <= %s AND retired_generation > %s`,
+				list, schema, table, committed,
+				list, schema, table+"_retired", committed, committed)
+		} else if prefix != "" {
+			query = fmt.Sprintf("SELECT * FROM %q.%q", schema, table)
+		} else {
+			continue
+		}
+
+		if _, err := conn.Exec(fmt.Sprintf("CREATE TEMP VIEW %q AS %s", prefix+table, query), nil); err != nil {
+			return fmt.Errorf("failed to create committed view of %s.%s: %w", schema, table, err)
+		}
+		created = true
+	}
+
+	if !created || prefix != "" {
+		return nil
+	}
+	views, err := queryConnStrings(conn, fmt.Sprintf("SELECT sql FROM %q.sqlite_master WHERE type = 'view' ORDER BY rowid", schema))
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		if _, err := conn.Exec(createViewPattern.ReplaceAllString(view, "CREATE TEMP VIEW "), nil); err != nil {
+			return fmt.Errorf("failed to copy view: %w", err)
+		}
+	}
+	return nil
+}
+
+// queryConnStrings runs a query returning one text column on a raw
+// connection (connect hooks have no *sql.DB).
+func queryConnStrings(conn *sqlite3.SQLiteConn, query string, args ...driver.Value) ([]string, error) {
+	rows, err := conn.Query(query, args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema: %w", err)
+	}
+	defer rows.Close()
+
+	var values []string
+	dest := make([]driver.Value, 1)
+	for {
+		if err := rows.Next(dest); err == io.EOF {
+			return values, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read schema: %w", err)
+		}
+		values = append(values, fmt.Sprintf("%s", dest[0]))
+	}
+}
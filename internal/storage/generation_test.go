@@ -0,0 +1,332 @@
+package storage
+
+// Test Plan for Index Generations:
+// - BeginGeneration numbers generations from the committed pointer (0 → 1 → 2)
+// - A generation closed without committing is resumed by the next BeginGeneration
+// - Commit twice returns an error; Close after Commit is a no-op
+// - Rows written in an open generation are invisible to readers until Commit
+// - Rewritten rows keep their committed version (and its file content entry) until Commit
+// - Rows updated without a content change keep their file content entry after Commit
+// - Deleted chunks and their vectors stay visible until Commit, then are removed
+// - Commit leaves no retired rows behind
+// - Writes made while no generation is open are visible at once
+// - CommittedGeneration returns 0 when the metadata table does not exist
+// - A second run on the same database file waits for the open one
+// - A read transaction keeps its snapshot while a generation commits
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginGeneration_Numbering(t *testing.T) {
+	t.Parallel()
+
+	db, _ := setupGenerationDBs(t)
+
+	committed, err := CommittedGeneration(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed)
+
+	gen := beginTestGeneration(t, db)
+	assert.Equal(t, int64(1), gen.ID())
+	require.NoError(t, gen.Commit())
+
+	committed, err = CommittedGeneration(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), committed)
+
+	gen = beginTestGeneration(t, db)
+	assert.Equal(t, int64(2), gen.ID())
+}
+
+func TestBeginGeneration_ResumesOpenGeneration(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, NewFileWriter(db).WriteFileStats(testGenerationFile("main.go")))
+	require.NoError(t, gen.Close())
+
+	committed, err := CommittedGeneration(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed, "closing without commit must not advance the pointer")
+	assert.Equal(t, 0, countRows(t, reader, "files"))
+
+	gen = beginTestGeneration(t, db)
+	assert.Equal(t, int64(1), gen.ID(), "interrupted generation is resumed")
+	require.NoError(t, gen.Commit())
+
+	assert.Equal(t, 1, countRows(t, reader, "files"), "resumed generation publishes the earlier writes")
+}
+
+func TestGeneration_FinishTwice(t *testing.T) {
+	t.Parallel()
+
+	db, _ := setupGenerationDBs(t)
+
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, gen.Commit())
+
+	assert.Error(t, gen.Commit())
+	assert.NoError(t, gen.Close())
+}
+
+func TestGeneration_WritesInvisibleUntilCommit(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, NewFileWriter(db).WriteFileStats(testGenerationFile("main.go")))
+	require.NoError(t, NewChunkWriterWithDB(db).WriteChunksIncremental([]*Chunk{
+		testGenerationChunk("main.go", "chunk-1", "func main() {}"),
+	}))
+
+	assert.Equal(t, 0, countRows(t, reader, "files"), "uncommitted file must not be visible")
+	assert.Equal(t, 0, countRows(t, reader, "chunks"), "uncommitted chunk must not be visible")
+	assert.Equal(t, 1, countRows(t, db, "files"), "writer sees its own rows")
+
+	require.NoError(t, gen.Commit())
+
+	assert.Equal(t, 1, countRows(t, reader, "files"))
+	assert.Equal(t, 1, countRows(t, reader, "chunks"))
+
+	committed, err := CommittedGeneration(reader)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), committed)
+}
+
+func TestGeneration_RewriteKeepsCommittedVersion(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+	files := NewFileWriter(db)
+	fileReader := NewFileReader(reader)
+
+	oldContent := "package main // original"
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, files.WriteFile(testGenerationFile("main.go"), &oldContent))
+	require.NoError(t, gen.Commit())
+
+	newContent := "package main // rewritten"
+	gen = beginTestGeneration(t, db)
+	require.NoError(t, files.WriteFile(testGenerationFile("main.go"), &newContent))
+
+	content, err := fileReader.GetFileContent("main.go")
+	require.NoError(t, err)
+	assert.Equal(t, oldContent, content, "readers keep the committed content")
+	matches, err := fileReader.SearchFileContent("original")
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+	matches, err = fileReader.SearchFileContent("rewritten")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.Equal(t, 1, countRows(t, reader, "files"))
+
+	require.NoError(t, gen.Commit())
+
+	content, err = fileReader.GetFileContent("main.go")
+	require.NoError(t, err)
+	assert.Equal(t, newContent, content)
+	matches, err = fileReader.SearchFileContent("original")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+	assert.Equal(t, 1, countRows(t, db, "files_fts"), "the committed entry is removed after the flip")
+	assert.Equal(t, 0, countRows(t, db, "files_retired"))
+}
+
+func TestGeneration_UpdateKeepsFileContent(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+
+	content := "package main // original"
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, NewFileWriter(db).WriteFile(testGenerationFile("main.go"), &content))
+	require.NoError(t, gen.Commit())
+
+	gen = beginTestGeneration(t, db)
+	_, err := db.Exec("UPDATE files SET last_modified = ? WHERE file_path = ?", time.Now().Format(time.RFC3339), "main.go")
+	require.NoError(t, err)
+	require.NoError(t, gen.Commit())
+
+	got, err := NewFileReader(reader).GetFileContent("main.go")
+	require.NoError(t, err)
+	assert.Equal(t, content, got)
+	assert.Equal(t, 1, countRows(t, db, "files_fts"))
+}
+
+func TestGeneration_DeletedChunksVisibleUntilCommit(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+	chunks := NewChunkWriterWithDB(db)
+
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, NewFileWriter(db).WriteFileStats(testGenerationFile("main.go")))
+	require.NoError(t, chunks.WriteChunksIncremental([]*Chunk{
+		testGenerationChunk("main.go", "chunk-1", "func main() {}"),
+		testGenerationChunk("main.go", "chunk-2", "func helper() {}"),
+	}))
+	require.NoError(t, gen.Commit())
+
+	// Reindex the file with one chunk left
+	gen = beginTestGeneration(t, db)
+	require.NoError(t, chunks.WriteChunksIncremental([]*Chunk{
+		testGenerationChunk("main.go", "chunk-1", "func main() { run() }"),
+	}))
+
+	assert.Equal(t, 2, countRows(t, reader, "chunks"), "deleted chunk stays visible until commit")
+	var text string
+	require.NoError(t, reader.QueryRow("SELECT text FROM chunks WHERE chunk_id = 'chunk-1'").Scan(&text))
+	assert.Equal(t, "func main() {}", text)
+	assert.Equal(t, 2, countRows(t, db, "chunks_vec"), "vector of the deleted chunk stays until commit")
+
+	require.NoError(t, gen.Commit())
+
+	assert.Equal(t, 1, countRows(t, reader, "chunks"))
+	require.NoError(t, reader.QueryRow("SELECT text FROM chunks WHERE chunk_id = 'chunk-1'").Scan(&text))
+	assert.Equal(t, "func main() { run() }", text)
+	assert.Equal(t, 1, countRows(t, db, "chunks_vec"))
+	assert.Equal(t, 0, countRows(t, db, "chunks_retired"))
+}
+
+func TestGeneration_WritesWithoutGeneration(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+
+	require.NoError(t, NewFileWriter(db).WriteFileStats(testGenerationFile("main.go")))
+	assert.Equal(t, 1, countRows(t, reader, "files"))
+
+	committed, err := CommittedGeneration(reader)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed, "writes outside a generation don't advance the pointer")
+	assert.Equal(t, 0, countRows(t, db, "files_retired"))
+}
+
+func TestCommittedGeneration_NoMetadataTable(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBMinimal(t)
+
+	committed, err := CommittedGeneration(db)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed)
+}
+
+func TestBeginGeneration_WaitsForOpenRun(t *testing.T) {
+	t.Parallel()
+
+	db, _ := setupGenerationDBs(t)
+	gen := beginTestGeneration(t, db)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+	defer cancel()
+	_, err := BeginGeneration(ctx, db)
+	assert.Error(t, err, "second run must wait for the open one")
+
+	require.NoError(t, gen.Commit())
+	gen = beginTestGeneration(t, db)
+	assert.Equal(t, int64(2), gen.ID())
+}
+
+func TestGeneration_ReadTxKeepsSnapshot(t *testing.T) {
+	t.Parallel()
+
+	db, reader := setupGenerationDBs(t)
+
+	readTx, err := reader.Begin()
+	require.NoError(t, err)
+	defer readTx.Rollback()
+
+	// First read pins the snapshot.
+	committed, err := CommittedGeneration(readTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed)
+
+	gen := beginTestGeneration(t, db)
+	require.NoError(t, NewFileWriter(db).WriteFileStats(testGenerationFile("main.go")))
+	require.NoError(t, gen.Commit())
+
+	committed, err = CommittedGeneration(readTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), committed)
+	assert.Equal(t, 0, countRows(t, readTx, "files"))
+}
+
+// setupGenerationDBs returns a writer and a reader (with the committed
+// generation views) on the same WAL-mode database file.
+func setupGenerationDBs(t *testing.T) (*sql.DB, *sql.DB) {
+	t.Helper()
+
+	InitVectorExtension()
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "test.db")
+
+	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_foreign_keys=on&"+WriterDSNParams)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	require.NoError(t, CreateSchema(db))
+
+	reader, err := OpenWithDependencyCorpus(dbPath+"?mode=ro", filepath.Join(dir, "no-corpus.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { reader.Close() })
+
+	return db, reader
+}
+
+// beginTestGeneration begins a generation closed at the end of the test.
+func beginTestGeneration(t *testing.T, db *sql.DB) *Generation {
+	t.Helper()
+
+	gen, err := BeginGeneration(context.Background(), db)
+	require.NoError(t, err)
+	t.Cleanup(func() { gen.Close() })
+	return gen
+}
+
+func testGenerationFile(path string) *FileStats {
+	now := time.Now()
+	return &FileStats{
+		FilePath:     path,
+		Language:     "go",
+		FileHash:     "hash-" + path,
+		LastModified: now,
+		IndexedAt:    now,
+	}
+}
+
+func testGenerationChunk(path, id, text string) *Chunk {
+	now := time.Now()
+	return &Chunk{
+		ID:        id,
+		FilePath:  path,
+		ChunkType: "definitions",
+		Title:     "Definitions",
+		Text:      text,
+		Embedding: make([]float32, 384),
+		StartLine: 1,
+		EndLine:   1,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func countRows(t *testing.T, db interface {
+	QueryRow(query string, args ...any) *sql.Row
+}, table string) int {
+	t.Helper()
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM "+table).Scan(&count))
+	return count
+}
@@ -16,10 +16,11 @@ type GraphReader struct {
 }
 
 // NewGraphReader creates a new GraphReader for the specified database.
-// Opens database in read-only mode for safety.
+// Opens database in read-only mode for safety, reading the last committed
+// index generation.
 func NewGraphReader(dbPath string) (*GraphReader, error) {
 	// Open in read-only mode
-	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", dbPath))
+	db, err := OpenCommitted(fmt.Sprintf("file:%s?mode=ro", dbPath))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -9,7 +9,7 @@ import (
 
 // openTestDB creates an in-memory SQLite database for testing.
 func openTestDB(t *testing.T) *sql.DB {
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := sql.Open("sqlite3", ":memory:?"+WriterDSNParams)
 	require.NoError(t, err)
 	return db
 }
@@ -22,7 +22,7 @@ type GraphWriter struct {
 //
 // Deprecated: Use NewGraphWriterWithDB to share database connections.
 func NewGraphWriter(dbPath string) (*GraphWriter, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+"?"+WriterDSNParams)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
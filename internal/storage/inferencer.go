@@ -53,28 +53,27 @@ func (inf *InterfaceInferencer) InferImplementations(ctx context.Context) error
 	allRelationships := append(implements, embeds...)
 
 	// 6. Bulk write in transaction (~5-10ms for 10K relationships)
-	tx, err := inf.db.BeginTx(ctx, nil)
-	if err != nil {
-		return fmt.Errorf("begin transaction: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Clear old inferred relationships
-	_, err = tx.Exec(`
-		DELETE FROM type_relationships
-		WHERE relationship_type IN ('implements', 'embeds')
-	`)
-	if err != nil {
-		return fmt.Errorf("clear old relationships: %w", err)
-	}
+	err = WithTx(inf.db, func(tx *sql.Tx) error {
+		// Clear old inferred relationships (declared non-Go ones are
+		// maintained by ResolveDeclaredSupertypes)
+		_, err := tx.Exec(`
+			DELETE FROM type_relationships
+			WHERE relationship_type IN ('implements', 'embeds')
+			AND source_file_path IN (` + goFilesQuery + `)
+		`)
+		if err != nil {
+			return fmt.Errorf("clear old relationships: %w", err)
+		}
 
-	// Insert new relationships
-	if err := BulkInsertRelationships(tx, allRelationships); err != nil {
-		return fmt.Errorf("insert relationships: %w", err)
-	}
+		// Insert new relationships
+		if err := BulkInsertRelationships(tx, allRelationships); err != nil {
+			return fmt.Errorf("insert relationships: %w", err)
+		}
 
-	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("commit: %w", err)
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Printf("✓ Inferred %d type relationships", len(allRelationships))
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// LintViolation is a stored rule violation from `cortex lint`.
+// Lines and columns are 1-indexed.
+type LintViolation struct {
+	RuleID      string
+	Severity    string // error, warning, info, hint
+	Message     string
+	Note        string
+	FilePath    string
+	StartLine   int
+	EndLine     int
+	StartColumn int
+	EndColumn   int
+	MatchText   string
+	Fix         string // Suggested replacement (empty if the rule has no fix)
+}
+
+// LintViolationFilter narrows QueryLintViolations results. Empty fields match everything.
+type LintViolationFilter struct {
+	RuleID   string
+	Severity string
+}
+
+// lintMetadataKey is the cache_metadata key holding the last lint run time.
+const lintMetadataKey = "lint_last_run"
+
+// ReplaceLintViolations stores the results of a lint run.
+// Previously stored violations for files where inScope returns true (all files
+// when inScope is nil) are replaced by violations; others are kept, so a lint
+// run over a subset of paths doesn't discard results for the rest of the branch.
+// With secrets, the matched text and fixes are stored with their secrets
+// redacted, as indexed file content is.
+func ReplaceLintViolations(db *sql.DB, violations []LintViolation, inScope func(filePath string) bool, secrets SecretRedactor) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() // Safe to call even after commit
+
+	if inScope == nil {
+		if _, err := sq.Delete("lint_violations").RunWith(tx).Exec(); err != nil {
+			return fmt.Errorf("failed to clear lint violations: %w", err)
+		}
+	} else {
+		stale, err := lintViolationPaths(tx)
+		if err != nil {
+			return err
+		}
+		for _, path := range stale {
+			if !inScope(path) {
+				continue
+			}
+			if _, err := sq.Delete("lint_violations").Where(sq.Eq{"file_path": path}).RunWith(tx).Exec(); err != nil {
+				return fmt.Errorf("failed to clear lint violations for %s: %w", path, err)
+			}
+		}
+	}
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO lint_violations (
+			rule_id, severity, message, note, file_path,
+			start_line, end_line, start_column, end_column,
+			match_text, fix, detected_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert statement: %w", err)
+	}
+	defer stmt.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, v := range violations {
+		if secrets != nil {
+			v.MatchText, _ = secrets.Redact(v.MatchText)
+			v.Fix, _ = secrets.Redact(v.Fix)
+		}
+		_, err := stmt.Exec(
+			v.RuleID, v.Severity, v.Message, v.Note, v.FilePath,
+			v.StartLine, v.EndLine, v.StartColumn, v.EndColumn,
+			v.MatchText, v.Fix, now,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert lint violation %s at %s:%d: %w", v.RuleID, v.FilePath, v.StartLine, err)
+		}
+	}
+
+	if err := writeMetadataValue(tx, lintMetadataKey, now); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// QueryLintViolations returns stored violations ordered by file and line.
+// Returns an empty slice (and no error) if lint has never been run on this branch.
+func QueryLintViolations(db sq.BaseRunner, filter LintViolationFilter) ([]LintViolation, error) {
+	query := sq.Select(
+		"rule_id", "severity", "message", "note", "file_path",
+		"start_line", "end_line", "start_column", "end_column",
+		"match_text", "fix",
+	).From("lint_violations").OrderBy("file_path", "start_line", "start_column")
+
+	if filter.RuleID != "" {
+		query = query.Where(sq.Eq{"rule_id": filter.RuleID})
+	}
+	if filter.Severity != "" {
+		query = query.Where(sq.Eq{"severity": filter.Severity})
+	}
+
+	rows, err := query.RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lint violations: %w", err)
+	}
+	defer rows.Close()
+
+	violations := []LintViolation{}
+	for rows.Next() {
+		var v LintViolation
+		err := rows.Scan(
+			&v.RuleID, &v.Severity, &v.Message, &v.Note, &v.FilePath,
+			&v.StartLine, &v.EndLine, &v.StartColumn, &v.EndColumn,
+			&v.MatchText, &v.Fix,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan lint violation: %w", err)
+		}
+		violations = append(violations, v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate lint violations: %w", err)
+	}
+	return violations, nil
+}
+
+// LastLintRun returns when lint results were last stored ("" if never).
+func LastLintRun(db sq.BaseRunner) (string, error) {
+	exists, err := metadataTableExists(db)
+	if err != nil || !exists {
+		return "", err
+	}
+	return readMetadataValue(db, lintMetadataKey)
+}
+
+// lintViolationPaths returns the distinct file paths with stored violations.
+func lintViolationPaths(db sq.BaseRunner) ([]string, error) {
+	rows, err := sq.Select("DISTINCT file_path").From("lint_violations").RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query lint violation paths: %w", err)
+	}
+	defer rows.Close()
+
+	var paths []string
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan lint violation path: %w", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, rows.Err()
+}
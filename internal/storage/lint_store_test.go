@@ -0,0 +1,142 @@
+package storage
+
+// Test Plan for Lint Violation Storage:
+// - QueryLintViolations returns an empty slice before lint has ever run
+// - ReplaceLintViolations with a nil scope replaces every stored violation
+// - A scoped replace only clears violations for in-scope files
+// - With a secret redactor, matched text and fixes are stored redacted
+// - QueryLintViolations filters by rule and severity and orders by file/line
+// - LastLintRun is empty before the first run and set afterwards
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lintViolation(rule, severity, path string, line int) LintViolation {
+	return LintViolation{
+		RuleID:      rule,
+		Severity:    severity,
+		Message:     rule + " violated",
+		FilePath:    path,
+		StartLine:   line,
+		EndLine:     line,
+		StartColumn: 1,
+		EndColumn:   10,
+		MatchText:   "fmt.Println(x)",
+	}
+}
+
+func TestQueryLintViolations_NeverRun(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	violations, err := QueryLintViolations(db, LintViolationFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, violations)
+	assert.NotNil(t, violations)
+
+	lastRun, err := LastLintRun(db)
+	require.NoError(t, err)
+	assert.Empty(t, lastRun)
+}
+
+func TestReplaceLintViolations_Full(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{
+		lintViolation("no-println", "warning", "a.go", 3),
+		lintViolation("no-println", "warning", "b.go", 5),
+	}, nil, nil))
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{
+		lintViolation("exec-ctx", "error", "c.go", 7),
+	}, nil, nil))
+
+	violations, err := QueryLintViolations(db, LintViolationFilter{})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, lintViolation("exec-ctx", "error", "c.go", 7), violations[0])
+
+	lastRun, err := LastLintRun(db)
+	require.NoError(t, err)
+	assert.NotEmpty(t, lastRun)
+}
+
+func TestReplaceLintViolations_Scoped(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{
+		lintViolation("no-println", "warning", "internal/a.go", 3),
+		lintViolation("no-println", "warning", "cmd/main.go", 5),
+	}, nil, nil))
+
+	// Re-lint internal/ only: its old violation goes, cmd/ is untouched
+	inInternal := func(path string) bool { return strings.HasPrefix(path, "internal/") }
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{
+		lintViolation("exec-ctx", "error", "internal/b.go", 9),
+	}, inInternal, nil))
+
+	violations, err := QueryLintViolations(db, LintViolationFilter{})
+	require.NoError(t, err)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "cmd/main.go", violations[0].FilePath)
+	assert.Equal(t, "internal/b.go", violations[1].FilePath)
+}
+
+// tokenRedactor redacts the word "token-1234".
+type tokenRedactor struct{}
+
+func (tokenRedactor) Redact(text string) (string, []SecretFinding) {
+	if !strings.Contains(text, "token-1234") {
+		return text, nil
+	}
+	return strings.ReplaceAll(text, "token-1234", "[REDACTED:test]"), []SecretFinding{{Detector: "test"}}
+}
+
+func TestReplaceLintViolations_RedactsSecrets(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	v := lintViolation("no-hardcoded-token", "error", "a.go", 3)
+	v.MatchText = `auth("token-1234")`
+	v.Fix = `auth(os.Getenv("TOKEN")) // was token-1234`
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{v}, nil, tokenRedactor{}))
+
+	violations, err := QueryLintViolations(db, LintViolationFilter{})
+	require.NoError(t, err)
+	require.Len(t, violations, 1)
+	assert.Equal(t, `auth("[REDACTED:test]")`, violations[0].MatchText)
+	assert.Equal(t, `auth(os.Getenv("TOKEN")) // was [REDACTED:test]`, violations[0].Fix)
+}
+
+func TestQueryLintViolations_Filter(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	require.NoError(t, ReplaceLintViolations(db, []LintViolation{
+		lintViolation("no-println", "warning", "b.go", 20),
+		lintViolation("exec-ctx", "error", "b.go", 4),
+		lintViolation("no-println", "warning", "a.go", 8),
+	}, nil, nil))
+
+	all, err := QueryLintViolations(db, LintViolationFilter{})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, "a.go", all[0].FilePath)
+	assert.Equal(t, 4, all[1].StartLine)
+	assert.Equal(t, 20, all[2].StartLine)
+
+	byRule, err := QueryLintViolations(db, LintViolationFilter{RuleID: "no-println"})
+	require.NoError(t, err)
+	assert.Len(t, byRule, 2)
+
+	bySeverity, err := QueryLintViolations(db, LintViolationFilter{Severity: "error"})
+	require.NoError(t, err)
+	require.Len(t, bySeverity, 1)
+	assert.Equal(t, "exec-ctx", bySeverity[0].RuleID)
+}
@@ -321,8 +321,8 @@ func migrateSchema_2_0_to_2_1(db *sql.DB) error {
 		return err
 	}
 
-	// Create FTS triggers (must be outside transaction)
-	err = createFTSTriggers(db)
+	// Create FTS triggers (versionTables creates them in the current schema)
+	err = versionTables(db)
 	if err != nil {
 		return err
 	}
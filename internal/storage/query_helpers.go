@@ -9,6 +9,21 @@ import (
 	"github.com/google/uuid"
 )
 
+// hasTable reports whether a table named name exists.
+func hasTable(db squirrel.BaseRunner, name string) (bool, error) {
+	var count int
+	err := squirrel.Select("COUNT(*)").
+		From("sqlite_master").
+		Where(squirrel.Eq{"type": "table", "name": name}).
+		RunWith(db).
+		QueryRow().
+		Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check %s existence: %w", name, err)
+	}
+	return count > 0, nil
+}
+
 // LoadInterfacesWithMethods loads all interface types with their method signatures.
 // Used by interface inference to determine which structs implement which interfaces.
 //
@@ -37,6 +52,7 @@ func LoadInterfacesWithMethods(db squirrel.BaseRunner) ([]Type, error) {
 		From("types t").
 		LeftJoin("type_fields tf ON t.type_id = tf.type_id AND tf.is_method = 1").
 		Where("t.kind = ?", "interface").
+		Where("t.file_path IN ("+goFilesQuery+")"). // Other languages declare their relationships
 		OrderBy("t.type_id", "tf.position").
 		PlaceholderFormat(squirrel.Question)
 
@@ -73,6 +89,7 @@ func LoadStructsWithMethods(db squirrel.BaseRunner) ([]Type, error) {
 		From("types t").
 		LeftJoin("functions f ON t.type_id = f.receiver_type_id").
 		Where("t.kind = ?", "struct").
+		Where("t.file_path IN ("+goFilesQuery+")").
 		OrderBy("t.type_id", "f.name").
 		PlaceholderFormat(squirrel.Question)
 
@@ -99,8 +116,9 @@ func LoadEmbeddedFields(db squirrel.BaseRunner) ([]TypeRelationship, error) {
 	).
 		From("type_fields tf").
 		Join("types t ON tf.type_id = t.type_id").
-		Where("tf.name = ?", "").        // Embedded fields have no name
+		Where("tf.name = ?", "").         // Embedded fields have no name
 		Where("tf.is_method = ?", false). // Exclude interface methods
+		Where("t.file_path IN (" + goFilesQuery + ")").
 		PlaceholderFormat(squirrel.Question)
 
 	rows, err := query.RunWith(db).Query()
@@ -6,6 +6,9 @@ import (
 	"time"
 )
 
+// SchemaVersion is the version of the schema created by CreateSchema.
+const SchemaVersion = "2.16"
+
 // CreateSchema creates all tables, indexes, and virtual tables for the unified cache.
 // Uses transactions for atomicity - all schema creation succeeds or fails together.
 //
@@ -46,6 +49,24 @@ func CreateSchema(db *sql.DB) error {
 		{"imports", createImportsTable},
 		{"chunks", createChunksTable},
 		{"cache_metadata", createCacheMetadataTable},
+		{"lint_violations", createLintViolationsTable},
+		{"declared_supertypes", createDeclaredSupertypesTable},
+		{"call_resolutions", createCallResolutionsTable},
+		{"workspace_modules", createWorkspaceModulesTable},
+		{"file_modules", createFileModulesTable},
+		{"import_resolutions", createImportResolutionsTable},
+		{"header_implementations", createHeaderImplementationsTable},
+		{"dependencies", createDependenciesTable},
+		{"dependency_usages", createDependencyUsagesView},
+		{"corpus_packages", createCorpusPackagesTable},
+		{"content_sources", createContentSourcesTable},
+		{"contract_symbols", createContractSymbolsTable},
+		{"contract_links", createContractLinksTable},
+		{"config_keys", createConfigKeysTable},
+		{"endpoints", createEndpointsTable},
+		{"config_usages", createConfigUsagesTable},
+		{"table_usages", createTableUsagesTable},
+		{"secret_findings", createSecretFindingsTable},
 	}
 
 	for _, table := range tables {
@@ -78,9 +99,10 @@ func CreateSchema(db *sql.DB) error {
 		return fmt.Errorf("failed to create vector index: %w", err)
 	}
 
-	// Create triggers for FTS sync (must be outside transaction)
-	if err := createFTSTriggers(db); err != nil {
-		return fmt.Errorf("failed to create FTS triggers: %w", err)
+	// Generation columns, retired tables and the triggers maintaining them
+	// and the FTS tables (must be outside transaction)
+	if err := versionTables(db); err != nil {
+		return fmt.Errorf("failed to version tables: %w", err)
 	}
 
 	// Bootstrap cache_metadata in separate transaction
@@ -92,12 +114,12 @@ func CreateSchema(db *sql.DB) error {
 	now := time.Now().UTC().Format(time.RFC3339)
 	bootstrapSQL := `
 		INSERT INTO cache_metadata (key, value, updated_at) VALUES
-			('schema_version', '2.1', ?),
+			('schema_version', ?, ?),
 			('branch', 'main', ?),
 			('last_indexed', '', ?),
 			('embedding_dimensions', '384', ?)
 	`
-	if _, err := tx.Exec(bootstrapSQL, now, now, now, now); err != nil {
+	if _, err := tx.Exec(bootstrapSQL, SchemaVersion, now, now, now, now); err != nil {
 		return fmt.Errorf("failed to bootstrap cache_metadata: %w", err)
 	}
 
@@ -108,6 +130,98 @@ func CreateSchema(db *sql.DB) error {
 	return nil
 }
 
+// schemaMigrations upgrade a database from one schema version to the next,
+// in order. A database at version from runs every migration from its own on.
+var schemaMigrations = []struct {
+	from    string
+	migrate func(db *sql.DB) error
+}{
+	{"2.1", addDocColumns},                               // 2.2: doc column on types and functions
+	{"2.2", recreateCosineVectorIndex},                   // 2.3: chunks_vec compares by cosine distance
+	{"2.3", createTables(createLintViolationsTable)},     // 2.4: lint_violations
+	{"2.4", createTables(createDeclaredSupertypesTable)}, // 2.5: declared_supertypes
+	{"2.5", createTables(createCallResolutionsTable)},    // 2.6: call_resolutions
+	{"2.6", createTables( // 2.7: workspace modules, import resolutions, header pairs, dependency inventory
+		createWorkspaceModulesTable, createFileModulesTable, createImportResolutionsTable,
+		createHeaderImplementationsTable, createDependenciesTable, createDependencyUsagesView)},
+	{"2.7", createTables(createCorpusPackagesTable)},                            // 2.8: corpus_packages
+	{"2.8", createTables(createContentSourcesTable)},                            // 2.9: content_sources
+	{"2.9", createTables(createContractSymbolsTable, createContractLinksTable)}, // 2.10: contract_symbols, contract_links
+	{"2.10", createTables(createConfigKeysTable)},                               // 2.11: config_keys
+	{"2.11", createTables(createEndpointsTable)},                                // 2.12: endpoints
+	{"2.12", createTables(createConfigUsagesTable)},                             // 2.13: config_usages and the views over it and config_keys
+	{"2.13", createTables(createTableUsagesTable)},                              // 2.14: table_usages
+	{"2.14", createTables(createSecretFindingsTable)},                           // 2.15: secret_findings
+	{"2.15", versionTables},                                                     // 2.16: index generations on rows
+}
+
+// MigrateSchema upgrades a database created with an older schema version to
+// SchemaVersion. Databases without a schema, at the current version or older
+// than the first migration are left unchanged.
+func MigrateSchema(db *sql.DB) error {
+	version, err := GetSchemaVersion(db)
+	if err != nil {
+		return err
+	}
+
+	start := -1
+	for i, m := range schemaMigrations {
+		if m.from == version {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	for _, m := range schemaMigrations[start:] {
+		if err := m.migrate(db); err != nil {
+			return fmt.Errorf("failed to migrate schema from %s: %w", m.from, err)
+		}
+	}
+	return UpdateSchemaVersion(db, SchemaVersion)
+}
+
+// addDocColumns adds the doc column to types and functions.
+func addDocColumns(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin migration transaction: %w", err)
+	}
+	defer tx.Rollback()
+	for _, table := range []string{"types", "functions"} {
+		if _, err := tx.Exec("ALTER TABLE " + table + " ADD COLUMN doc TEXT"); err != nil {
+			return fmt.Errorf("failed to add doc column to %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration: %w", err)
+	}
+	return nil
+}
+
+// createTables returns a migration creating tables (with their indexes) that
+// earlier versions created on first write, so they may already exist.
+func createTables(ddl ...string) func(db *sql.DB) error {
+	return func(db *sql.DB) error {
+		tx, err := db.Begin()
+		if err != nil {
+			return fmt.Errorf("failed to begin migration transaction: %w", err)
+		}
+		defer tx.Rollback()
+		for _, stmt := range ddl {
+			if _, err := tx.Exec(stmt); err != nil {
+				return fmt.Errorf("failed to create tables: %w", err)
+			}
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration: %w", err)
+		}
+		return nil
+	}
+}
+
 // GetSchemaVersion retrieves the schema version from cache_metadata.
 // Returns "0" if the table doesn't exist (new database).
 func GetSchemaVersion(db *sql.DB) (string, error) {
@@ -175,6 +289,7 @@ const createFilesFTSTable = `
 CREATE VIRTUAL TABLE files_fts USING fts5(
     file_path UNINDEXED,                         -- FK to files.file_path (for display)
     content,                                     -- Full file content (synced via triggers from files.content)
+    generation UNINDEXED,                        -- files.generation of the row the entry was written for
     tokenize = "unicode61 separators '._'"       -- Tokenize on underscore and dot
 )
 `
@@ -193,6 +308,7 @@ CREATE TABLE types (
     is_exported INTEGER NOT NULL DEFAULT 0,      -- Boolean: Uppercase first letter in Go
     field_count INTEGER NOT NULL DEFAULT 0,      -- Denormalized count
     method_count INTEGER NOT NULL DEFAULT 0,     -- Denormalized count
+    doc TEXT,                                    -- Doc comment, markers stripped
     FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
 )
 `
@@ -230,6 +346,7 @@ CREATE TABLE functions (
     param_count INTEGER NOT NULL DEFAULT 0,      -- Denormalized count
     return_count INTEGER NOT NULL DEFAULT 0,     -- Denormalized count
     cyclomatic_complexity INTEGER,               -- Optional complexity metric
+    doc TEXT,                                    -- Doc comment or docstring, markers stripped
     FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE,
     FOREIGN KEY (receiver_type_id) REFERENCES types(type_id) ON DELETE SET NULL
 )
@@ -296,7 +413,7 @@ const createChunksTable = `
 CREATE TABLE chunks (
     chunk_id TEXT PRIMARY KEY,                   -- code-symbols-{file_path}, doc-{file}-s{N}
     file_path TEXT NOT NULL,                     -- FK to files
-    chunk_type TEXT NOT NULL,                    -- symbols, definitions, data, documentation
+    chunk_type TEXT NOT NULL,                    -- symbols, definitions, data, docstring, documentation
     title TEXT NOT NULL,                         -- Human-readable title
     text TEXT NOT NULL,                          -- Natural language formatted content
     embedding BLOB NOT NULL,                     -- Float32 array, serialized (4 bytes per float)
@@ -316,6 +433,294 @@ CREATE TABLE cache_metadata (
 )
 `
 
+// Tables below were added after 2.2, when they were created on first write;
+// IF NOT EXISTS lets migrations create them in databases that already have them.
+
+const createLintViolationsTable = `
+CREATE TABLE IF NOT EXISTS lint_violations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    rule_id TEXT NOT NULL,
+    severity TEXT NOT NULL,
+    message TEXT NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    file_path TEXT NOT NULL,             -- Not a FK: rules may cover files the indexer skips
+    start_line INTEGER NOT NULL,
+    end_line INTEGER NOT NULL,
+    start_column INTEGER NOT NULL,
+    end_column INTEGER NOT NULL,
+    match_text TEXT NOT NULL,
+    fix TEXT NOT NULL DEFAULT '',
+    detected_at TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_lint_violations_file_path ON lint_violations(file_path);
+CREATE INDEX IF NOT EXISTS idx_lint_violations_rule_id ON lint_violations(rule_id);
+`
+
+const createDeclaredSupertypesTable = `
+CREATE TABLE IF NOT EXISTS declared_supertypes (
+    file_path TEXT NOT NULL,             -- File containing the declaration
+    type_name TEXT NOT NULL,
+    supertype_name TEXT NOT NULL,
+    relationship_type TEXT NOT NULL,     -- extends, implements, mixin
+    source_line INTEGER NOT NULL,
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_declared_supertypes_file_path ON declared_supertypes(file_path);
+`
+
+const createCallResolutionsTable = `
+CREATE TABLE IF NOT EXISTS call_resolutions (
+    call_id TEXT PRIMARY KEY,
+    callee_function_id TEXT,             -- Resolved callee (NULL if external/unknown), no FK: may not be indexed yet
+    confidence TEXT NOT NULL,            -- exact, dynamic, unresolved
+    FOREIGN KEY (call_id) REFERENCES function_calls(call_id) ON DELETE CASCADE
+);
+`
+
+const createWorkspaceModulesTable = `
+CREATE TABLE IF NOT EXISTS workspace_modules (
+    module_name TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,                  -- go, npm, python, cargo
+    root_path TEXT NOT NULL,             -- Relative to the project root ("." for the root)
+    manifest_path TEXT NOT NULL
+);
+`
+
+const createFileModulesTable = `
+CREATE TABLE IF NOT EXISTS file_modules (
+    file_path TEXT PRIMARY KEY,
+    module_name TEXT NOT NULL,           -- workspace_modules.module_name
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_file_modules_module_name ON file_modules(module_name);
+`
+
+const createImportResolutionsTable = `
+CREATE TABLE IF NOT EXISTS import_resolutions (
+    import_id TEXT PRIMARY KEY,
+    resolution TEXT NOT NULL,            -- internal, external, stdlib, unresolved
+    resolved_path TEXT,                  -- Internal: imported file or Go package directory
+    module_name TEXT,                    -- Workspace module of the imported file
+    package_name TEXT,                   -- External and stdlib: package name
+    FOREIGN KEY (import_id) REFERENCES imports(import_id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_import_resolutions_resolved_path ON import_resolutions(resolved_path);
+CREATE INDEX IF NOT EXISTS idx_import_resolutions_package_name ON import_resolutions(package_name);
+`
+
+const createHeaderImplementationsTable = `
+CREATE TABLE IF NOT EXISTS header_implementations (
+    header_path TEXT PRIMARY KEY,        -- C/C++ header
+    source_path TEXT NOT NULL,           -- Source file implementing it
+    FOREIGN KEY (header_path) REFERENCES files(file_path) ON DELETE CASCADE,
+    FOREIGN KEY (source_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_header_implementations_source_path ON header_implementations(source_path);
+`
+
+const createDependenciesTable = `
+CREATE TABLE IF NOT EXISTS dependencies (
+    ecosystem TEXT NOT NULL,             -- go, npm, python, cargo, maven, rubygems
+    name TEXT NOT NULL,
+    import_name TEXT NOT NULL,           -- import_resolutions.package_name of imports of it
+    version TEXT NOT NULL DEFAULT '',    -- Locked version, or the declared one when exact
+    version_constraint TEXT,             -- Declared requirement, if any (never for transitive dependencies)
+    scope TEXT NOT NULL,                 -- runtime, dev, peer, optional, build, provided
+    direct INTEGER NOT NULL,             -- 1 if declared by a manifest
+    manifest_path TEXT NOT NULL,         -- Manifest or lockfile it was read from
+    module_name TEXT,                    -- Workspace module owning the manifest
+    PRIMARY KEY (manifest_path, name, version)
+);
+CREATE INDEX IF NOT EXISTS idx_dependencies_name ON dependencies(name);
+CREATE INDEX IF NOT EXISTS idx_dependencies_import_name ON dependencies(import_name);
+`
+
+// createDependencyUsagesView lists the imports of each dependency, matched to
+// the importing file's module and ecosystem.
+const createDependencyUsagesView = `
+CREATE VIEW IF NOT EXISTS dependency_usages AS
+SELECT i.file_path, i.import_path, i.import_line,
+       d.ecosystem, d.name AS dependency_name, d.version, d.scope, d.direct, d.manifest_path, d.module_name
+FROM imports i
+JOIN files f ON f.file_path = i.file_path
+JOIN import_resolutions r ON r.import_id = i.import_id AND r.resolution = 'external'
+LEFT JOIN file_modules fm ON fm.file_path = i.file_path
+JOIN dependencies d ON d.import_name = r.package_name
+    AND d.module_name IS fm.module_name
+    AND d.ecosystem = CASE f.language
+        WHEN 'go' THEN 'go'
+        WHEN 'typescript' THEN 'npm'
+        WHEN 'javascript' THEN 'npm'
+        WHEN 'python' THEN 'python'
+        WHEN 'rust' THEN 'cargo'
+    END;
+`
+
+const createCorpusPackagesTable = `
+CREATE TABLE IF NOT EXISTS corpus_packages (
+    ecosystem TEXT NOT NULL,
+    name TEXT NOT NULL,
+    version TEXT NOT NULL DEFAULT '',
+    path_prefix TEXT NOT NULL,
+    source_dir TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    file_count INTEGER NOT NULL,
+    indexed_at TEXT NOT NULL,
+    PRIMARY KEY (ecosystem, name)
+);
+`
+
+const createContentSourcesTable = `
+CREATE TABLE IF NOT EXISTS content_sources (
+    label TEXT PRIMARY KEY,
+    root TEXT NOT NULL,                  -- Absolute directory the files were read from
+    watch INTEGER NOT NULL               -- 1 if the daemon re-indexes it on change
+);
+`
+
+const createContractSymbolsTable = `
+CREATE TABLE IF NOT EXISTS contract_symbols (
+    symbol_id TEXT PRIMARY KEY,          -- type_id or function_id
+    file_path TEXT NOT NULL,
+    protocol TEXT NOT NULL,              -- protobuf, openapi, graphql
+    kind TEXT NOT NULL,
+    qualified_name TEXT NOT NULL,
+    go_package TEXT,                     -- Import path of generated Go code (NULL if not declared)
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_contract_symbols_file_path ON contract_symbols(file_path);
+CREATE INDEX IF NOT EXISTS idx_contract_symbols_qualified_name ON contract_symbols(qualified_name);
+`
+
+const createContractLinksTable = `
+CREATE TABLE IF NOT EXISTS contract_links (
+    contract_symbol_id TEXT NOT NULL,    -- No FKs: rebuilt by ResolveContractLinks
+    generated_id TEXT NOT NULL,
+    generated_file_path TEXT NOT NULL,
+    link_kind TEXT NOT NULL,             -- type, client, handler, server
+    PRIMARY KEY (contract_symbol_id, generated_id)
+);
+CREATE INDEX IF NOT EXISTS idx_contract_links_generated_id ON contract_links(generated_id);
+`
+
+// key_id is never reused (AUTOINCREMENT): config_keys_fts entries use it as
+// their rowid and outlive deleted keys until the next generation commits.
+const createConfigKeysTable = `
+CREATE TABLE IF NOT EXISTS config_keys (
+    key_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    file_path TEXT NOT NULL,
+    document INTEGER NOT NULL DEFAULT 0,  -- Index of the YAML document (multi-document files)
+    key_path TEXT NOT NULL,               -- spec.template.spec.containers[0].image
+    value TEXT NOT NULL,                  -- Scalar value, secrets masked
+    line INTEGER NOT NULL,
+    UNIQUE (file_path, document, key_path),
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_config_keys_key_path ON config_keys(key_path);
+`
+
+const createEndpointsTable = `
+CREATE TABLE IF NOT EXISTS endpoints (
+    endpoint_id INTEGER PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL,
+    framework TEXT NOT NULL,             -- net/http, chi, gin, echo, gorilla, connect, grpc, express, flask, spring, rails, ...
+    kind TEXT NOT NULL,                  -- http, rpc
+    method TEXT NOT NULL,                -- GET, POST, ...; ANY when every method is accepted
+    path TEXT NOT NULL,                  -- /api/v1/orders/{id}, /pkg.Service/Method
+    handler TEXT NOT NULL DEFAULT '',    -- Handler as written ('' for inline handlers)
+    handler_name TEXT NOT NULL DEFAULT '',
+    handler_scope TEXT NOT NULL DEFAULT '',
+    handler_function_id TEXT,            -- Set by ResolveEndpointHandlers (NULL if unresolved)
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_endpoints_file_path ON endpoints(file_path);
+CREATE INDEX IF NOT EXISTS idx_endpoints_path ON endpoints(path);
+CREATE INDEX IF NOT EXISTS idx_endpoints_handler_function_id ON endpoints(handler_function_id);
+`
+
+// config_key_definitions cross-references the keys code reads with the
+// config_keys defining them, case-insensitively:
+//   - the same key path (DATABASE_URL in .env.example, server.port in
+//     config.yaml)
+//   - a key path ending in the key (services.api.environment.DATABASE_URL in
+//     docker-compose.yml, production.server.port)
+//   - for environment variables, Kubernetes env lists (env[0].name:
+//     DATABASE_URL) and KEY=value list items
+//
+// undocumented_config_keys lists the reads of keys no configuration file
+// defines; unused_config_keys the variables of .env templates no code reads.
+const createConfigUsagesTable = `
+CREATE TABLE IF NOT EXISTS config_usages (
+    usage_id INTEGER PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL,
+    kind TEXT NOT NULL,                 -- env, config
+    key TEXT NOT NULL,                  -- DATABASE_URL, server.port
+    source TEXT NOT NULL,               -- os.Getenv, viper, process.env, os.environ, ENV, @Value, ...
+    function TEXT NOT NULL DEFAULT '',  -- Enclosing function (Recv.Name, Class.method); '' at top level
+    function_id TEXT,                   -- Enclosing Go function
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_config_usages_file_path ON config_usages(file_path);
+CREATE INDEX IF NOT EXISTS idx_config_usages_key ON config_usages(key);
+
+CREATE VIEW IF NOT EXISTS config_key_definitions AS
+SELECT DISTINCT u.key, u.kind, k.file_path, k.key_path, k.value, k.line
+FROM (SELECT DISTINCT key, kind FROM config_usages) u
+JOIN config_keys k
+    ON k.key_path = u.key COLLATE NOCASE
+    OR substr(k.key_path, -length(u.key) - 1) = ('.' || u.key) COLLATE NOCASE
+    OR (u.kind = 'env' AND k.key_path LIKE '%.name' AND k.value = u.key)
+    OR (u.kind = 'env' AND substr(k.value, 1, length(u.key) + 1) = u.key || '=');
+
+CREATE VIEW IF NOT EXISTS undocumented_config_keys AS
+SELECT u.file_path, u.line, u.kind, u.key, u.source, u.function, u.function_id
+FROM config_usages u
+WHERE NOT EXISTS (
+    SELECT 1 FROM config_key_definitions d WHERE d.key = u.key AND d.kind = u.kind
+);
+
+CREATE VIEW IF NOT EXISTS unused_config_keys AS
+SELECT k.file_path, k.line, k.key_path AS key, k.value
+FROM config_keys k
+JOIN files f ON f.file_path = k.file_path AND f.language = 'dotenv'
+WHERE NOT EXISTS (
+    SELECT 1 FROM config_usages u WHERE u.kind = 'env' AND u.key = k.key_path
+);
+`
+
+const createTableUsagesTable = `
+CREATE TABLE IF NOT EXISTS table_usages (
+    usage_id INTEGER PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL,
+    table_name TEXT NOT NULL,           -- As written: chunks, public.users
+    access TEXT NOT NULL,               -- read, write, schema
+    operation TEXT NOT NULL,            -- select, insert, update, delete, create, alter, drop, ...
+    columns TEXT NOT NULL DEFAULT '',   -- Comma-separated columns selected, inserted, set or defined
+    source TEXT NOT NULL,               -- sql (string literal), squirrel, knex
+    function TEXT NOT NULL DEFAULT '',  -- Enclosing function (Recv.Name, Class.method); '' at top level
+    function_id TEXT,                   -- Enclosing Go function
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_table_usages_file_path ON table_usages(file_path);
+CREATE INDEX IF NOT EXISTS idx_table_usages_table_name ON table_usages(table_name COLLATE NOCASE);
+`
+
+const createSecretFindingsTable = `
+CREATE TABLE IF NOT EXISTS secret_findings (
+    finding_id INTEGER PRIMARY KEY,
+    file_path TEXT NOT NULL,
+    line INTEGER NOT NULL,
+    column INTEGER NOT NULL,
+    detector TEXT NOT NULL,
+    preview TEXT NOT NULL,
+    FOREIGN KEY (file_path) REFERENCES files(file_path) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_secret_findings_file_path ON secret_findings(file_path);
+`
+
 // getAllIndexes returns all index creation statements.
 func getAllIndexes() []string {
 	return []string{
@@ -368,55 +773,3 @@ func getAllIndexes() []string {
 		"CREATE INDEX idx_chunks_chunk_type ON chunks(chunk_type)",
 	}
 }
-
-// createFTSTriggers creates triggers to automatically sync files table content to files_fts.
-// Only syncs text files (where content IS NOT NULL).
-// Binary files (content IS NULL) are excluded from FTS.
-//
-// Strategy: Store content in both files.content and files_fts, but use triggers to
-// keep them in sync. This allows us to selectively index only text files.
-func createFTSTriggers(db *sql.DB) error {
-	triggers := []string{
-		// Insert trigger: sync new text files to FTS
-		// Note: When using INSERT OR REPLACE, this trigger fires after the internal DELETE,
-		// so we use INSERT OR REPLACE to handle both new files and updates.
-		`CREATE TRIGGER files_fts_insert AFTER INSERT ON files
-		BEGIN
-			-- Delete old FTS entry first (in case of INSERT OR REPLACE)
-			DELETE FROM files_fts WHERE file_path = NEW.file_path;
-
-			-- Insert new FTS entry only if content is not NULL
-			INSERT INTO files_fts(file_path, content)
-			SELECT NEW.file_path, NEW.content
-			WHERE NEW.content IS NOT NULL;
-		END`,
-
-		// Update trigger: handle content changes (for explicit UPDATE statements)
-		// Note: This won't fire for INSERT OR REPLACE, only for UPDATE statements
-		`CREATE TRIGGER files_fts_update AFTER UPDATE OF content ON files
-		BEGIN
-			-- Delete old FTS entry (if it exists)
-			DELETE FROM files_fts WHERE file_path = OLD.file_path;
-
-			-- Insert new FTS entry only if NEW.content is not NULL
-			INSERT INTO files_fts(file_path, content)
-			SELECT NEW.file_path, NEW.content
-			WHERE NEW.content IS NOT NULL;
-		END`,
-
-		// Delete trigger: remove from FTS when file deleted (only if it had content)
-		`CREATE TRIGGER files_fts_delete AFTER DELETE ON files
-		WHEN OLD.content IS NOT NULL
-		BEGIN
-			DELETE FROM files_fts WHERE file_path = OLD.file_path;
-		END`,
-	}
-
-	for i, trigger := range triggers {
-		if _, err := db.Exec(trigger); err != nil {
-			return fmt.Errorf("failed to create trigger %d: %w", i+1, err)
-		}
-	}
-
-	return nil
-}
@@ -13,6 +13,9 @@ package storage
 // - GetSchemaVersion returns "2.0" after CreateSchema
 // - UpdateSchemaVersion updates version in cache_metadata table
 // - UpdateSchemaVersion updates updated_at timestamp
+// - MigrateSchema adds the doc columns to a 2.1 database and leaves current databases unchanged
+// - MigrateSchema recreates a 2.2 database's chunks_vec so KNN returns cosine distance
+// - MigrateSchema creates the tables earlier versions created on first write, whether or not they exist
 
 import (
 	"database/sql"
@@ -43,6 +46,27 @@ func TestCreateSchema(t *testing.T) {
 		"imports",
 		"chunks",
 		"cache_metadata",
+		"lint_violations",
+		"declared_supertypes",
+		"call_resolutions",
+		"workspace_modules",
+		"file_modules",
+		"import_resolutions",
+		"header_implementations",
+		"dependencies",
+		"dependency_usages",
+		"corpus_packages",
+		"content_sources",
+		"contract_symbols",
+		"contract_links",
+		"config_keys",
+		"endpoints",
+		"config_usages",
+		"config_key_definitions",
+		"undocumented_config_keys",
+		"unused_config_keys",
+		"table_usages",
+		"secret_findings",
 	}
 
 	for _, table := range tables {
@@ -144,7 +168,7 @@ func TestCreateSchema_Indexes(t *testing.T) {
 	// Query sqlite_master for indexes
 	rows, err := db.Query(`
 		SELECT name FROM sqlite_master
-		WHERE type = 'index' AND name LIKE 'idx_%'
+		WHERE type = 'index' AND name LIKE 'idx_%' AND tbl_name NOT LIKE '%_retired'
 		ORDER BY name
 	`)
 	require.NoError(t, err)
@@ -162,6 +186,19 @@ func TestCreateSchema_Indexes(t *testing.T) {
 	expectedIndexes := []string{
 		"idx_chunks_chunk_type",
 		"idx_chunks_file_path",
+		"idx_config_keys_key_path",
+		"idx_config_usages_file_path",
+		"idx_config_usages_key",
+		"idx_contract_links_generated_id",
+		"idx_contract_symbols_file_path",
+		"idx_contract_symbols_qualified_name",
+		"idx_declared_supertypes_file_path",
+		"idx_dependencies_import_name",
+		"idx_dependencies_name",
+		"idx_endpoints_file_path",
+		"idx_endpoints_handler_function_id",
+		"idx_endpoints_path",
+		"idx_file_modules_module_name",
 		"idx_files_is_test",
 		"idx_files_language",
 		"idx_files_module",
@@ -176,9 +213,17 @@ func TestCreateSchema_Indexes(t *testing.T) {
 		"idx_functions_module",
 		"idx_functions_name",
 		"idx_functions_receiver_type_id",
+		"idx_header_implementations_source_path",
+		"idx_import_resolutions_package_name",
+		"idx_import_resolutions_resolved_path",
 		"idx_imports_file_path",
 		"idx_imports_import_path",
 		"idx_imports_is_external",
+		"idx_lint_violations_file_path",
+		"idx_lint_violations_rule_id",
+		"idx_secret_findings_file_path",
+		"idx_table_usages_file_path",
+		"idx_table_usages_table_name",
 		"idx_type_fields_is_method",
 		"idx_type_fields_name",
 		"idx_type_fields_type_id",
@@ -207,7 +252,7 @@ func TestCreateSchema_BootstrapMetadata(t *testing.T) {
 		key      string
 		expected string
 	}{
-		{"schema_version", "2.1"},
+		{"schema_version", SchemaVersion},
 		{"branch", "main"},
 		{"embedding_dimensions", "384"},
 	}
@@ -360,7 +405,7 @@ func TestGetSchemaVersion(t *testing.T) {
 				err := CreateSchema(db)
 				require.NoError(t, err)
 			},
-			expected: "2.1",
+			expected: SchemaVersion,
 			wantErr:  false,
 		},
 	}
@@ -833,3 +878,114 @@ func tableExists(t *testing.T, db *sql.DB, tableName string) bool {
 	require.NoError(t, err)
 	return count > 0
 }
+
+func TestMigrateSchema(t *testing.T) {
+	db := openSchemaTestDB(t)
+	defer db.Close()
+
+	// A 2.1 database: the current schema without the doc columns
+	require.NoError(t, CreateSchema(db))
+	for _, table := range []string{"types", "functions"} {
+		for _, trigger := range []string{"insert", "update_retire", "update", "delete"} {
+			_, err := db.Exec("DROP TRIGGER " + table + "_generation_" + trigger)
+			require.NoError(t, err)
+		}
+		_, err := db.Exec("ALTER TABLE " + table + " DROP COLUMN doc")
+		require.NoError(t, err)
+	}
+	require.NoError(t, UpdateSchemaVersion(db, "2.1"))
+
+	require.NoError(t, MigrateSchema(db))
+	version, err := GetSchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+	for _, table := range []string{"types", "functions"} {
+		var n int
+		require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = 'doc'", table).Scan(&n))
+		assert.Equal(t, 1, n, "%s.doc should exist", table)
+	}
+
+	// Current databases are left unchanged
+	require.NoError(t, MigrateSchema(db))
+}
+
+func TestMigrateSchema_CosineVectorIndex(t *testing.T) {
+	db := openSchemaTestDB(t)
+	defer db.Close()
+
+	// A 2.2 database: chunks_vec with vec0's default L2 metric
+	require.NoError(t, CreateSchema(db))
+	_, err := db.Exec("DROP TABLE chunks_vec")
+	require.NoError(t, err)
+	_, err = db.Exec("CREATE VIRTUAL TABLE chunks_vec USING vec0(chunk_id TEXT PRIMARY KEY, embedding float[384])")
+	require.NoError(t, err)
+	require.NoError(t, UpdateSchemaVersion(db, "2.2"))
+
+	query := make([]float32, 384)
+	query[0] = 1
+	orthogonal := make([]float32, 384)
+	orthogonal[1] = 1
+	writer := NewChunkWriterWithDB(db)
+	require.NoError(t, writer.WriteChunks([]*Chunk{
+		{ID: "same", FilePath: "a.go", ChunkType: "symbols", Title: "a", Text: "a", Embedding: query},
+		{ID: "orthogonal", FilePath: "a.go", ChunkType: "symbols", Title: "b", Text: "b", Embedding: orthogonal},
+	}))
+
+	require.NoError(t, MigrateSchema(db))
+	version, err := GetSchemaVersion(db)
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, version)
+
+	// KNN returns cosine distance (1 - cos), as the other backends do
+	rows, err := db.Query("SELECT chunk_id, distance FROM chunks_vec WHERE embedding MATCH ? AND k = 2 ORDER BY distance", SerializeEmbedding(query))
+	require.NoError(t, err)
+	defer rows.Close()
+	distances := map[string]float64{}
+	for rows.Next() {
+		var id string
+		var distance float64
+		require.NoError(t, rows.Scan(&id, &distance))
+		distances[id] = distance
+	}
+	require.NoError(t, rows.Err())
+	assert.InDelta(t, 0, distances["same"], 1e-6)
+	assert.InDelta(t, 1, distances["orthogonal"], 1e-6, "L2 distance would be √2")
+}
+
+func TestMigrateSchema_CreatesTables(t *testing.T) {
+	// Tables earlier versions created on first write, by the version before
+	// the one that added them to the schema
+	tests := []struct {
+		version string
+		table   string
+	}{
+		{"2.3", "lint_violations"},
+		{"2.4", "declared_supertypes"},
+		{"2.5", "call_resolutions"},
+		{"2.6", "workspace_modules"},
+		{"2.6", "dependencies"},
+		{"2.7", "corpus_packages"},
+		{"2.8", "content_sources"},
+		{"2.9", "contract_symbols"},
+		{"2.9", "contract_links"},
+		{"2.10", "config_keys"},
+		{"2.11", "endpoints"},
+		{"2.12", "config_usages"},
+		{"2.13", "table_usages"},
+		{"2.14", "secret_findings"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.table, func(t *testing.T) {
+			db := openSchemaTestDB(t)
+			defer db.Close()
+
+			require.NoError(t, CreateSchema(db))
+			_, err := db.Exec("DROP TABLE " + tt.table)
+			require.NoError(t, err)
+			require.NoError(t, UpdateSchemaVersion(db, tt.version))
+
+			require.NoError(t, MigrateSchema(db))
+			assert.True(t, tableExists(t, db, tt.table), "%s should exist", tt.table)
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// SecretFinding is a secret found (and redacted) in a file's content.
+// Lines and columns are 1-indexed; the secret itself is never stored.
+type SecretFinding struct {
+	FilePath string
+	Line     int
+	Column   int
+	Detector string // aws-access-key, private-key, jwt, high-entropy, or a custom pattern's name
+	Preview  string // First characters of the secret, masked: "AKIA****"
+}
+
+// SecretRedactor redacts the secrets in text and returns what it found
+// (see indexer.SecretScanner).
+type SecretRedactor interface {
+	Redact(text string) (string, []SecretFinding)
+}
+
+// SecretFindingFilter narrows QuerySecretFindings results. Empty fields match everything.
+type SecretFindingFilter struct {
+	Detector   string
+	PathPrefix string
+}
+
+// ReplaceSecretFindings replaces the secrets found in filePath.
+func ReplaceSecretFindings(tx *sql.Tx, filePath string, findings []SecretFinding) error {
+	if err := DeleteSecretFindings(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, f := range findings {
+		_, err := sq.Insert("secret_findings").
+			Columns("file_path", "line", "column", "detector", "preview").
+			Values(filePath, f.Line, f.Column, f.Detector, f.Preview).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert secret finding %s at %s:%d: %w", f.Detector, filePath, f.Line, err)
+		}
+	}
+	return nil
+}
+
+// DeleteSecretFindings removes the secret findings of filePath.
+func DeleteSecretFindings(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("secret_findings").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete secret findings for %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// SecretFindingFiles returns the files with stored secret findings.
+func SecretFindingFiles(db sq.BaseRunner) (map[string]bool, error) {
+	rows, err := sq.Select("DISTINCT file_path").From("secret_findings").RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secret finding files: %w", err)
+	}
+	defer rows.Close()
+
+	files := make(map[string]bool)
+	for rows.Next() {
+		var path string
+		if err := rows.Scan(&path); err != nil {
+			return nil, fmt.Errorf("failed to scan secret finding file: %w", err)
+		}
+		files[path] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate secret finding files: %w", err)
+	}
+	return files, nil
+}
+
+// QuerySecretFindings returns stored findings ordered by file, line and column.
+func QuerySecretFindings(db sq.BaseRunner, filter SecretFindingFilter) ([]SecretFinding, error) {
+	query := sq.Select("file_path", "line", "column", "detector", "preview").
+		From("secret_findings").
+		OrderBy("file_path", "line", "column")
+
+	if filter.Detector != "" {
+		query = query.Where(sq.Eq{"detector": filter.Detector})
+	}
+	if filter.PathPrefix != "" {
+		query = query.Where("substr(file_path, 1, length(?)) = ?", filter.PathPrefix, filter.PathPrefix)
+	}
+
+	rows, err := query.RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query secret findings: %w", err)
+	}
+	defer rows.Close()
+
+	findings := []SecretFinding{}
+	for rows.Next() {
+		var f SecretFinding
+		if err := rows.Scan(&f.FilePath, &f.Line, &f.Column, &f.Detector, &f.Preview); err != nil {
+			return nil, fmt.Errorf("failed to scan secret finding: %w", err)
+		}
+		findings = append(findings, f)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate secret findings: %w", err)
+	}
+	return findings, nil
+}
@@ -0,0 +1,85 @@
+package storage
+
+// Test Plan for Secret Findings:
+// - QuerySecretFindings returns an empty slice before any secret was found
+// - ReplaceSecretFindings stores findings ordered by file, line and column
+// - Filters narrow by detector and path prefix
+// - SecretFindingFiles lists the files with findings
+// - Replacing a file's findings replaces them; deleting the file cascades
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretFindings(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBFile(t)
+	for _, path := range []string{"config/deploy.yml", "internal/auth/client.go"} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, 'go', '', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, path)
+		require.NoError(t, err)
+	}
+
+	withTx := func(fn func(tx *sql.Tx) error) {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+
+	findings, err := QuerySecretFindings(db, SecretFindingFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+	withTx(func(tx *sql.Tx) error {
+		return DeleteSecretFindings(tx, "config/deploy.yml")
+	})
+
+	withTx(func(tx *sql.Tx) error {
+		if err := ReplaceSecretFindings(tx, "internal/auth/client.go", []SecretFinding{
+			{Line: 12, Column: 20, Detector: "jwt", Preview: "eyJh****"},
+		}); err != nil {
+			return err
+		}
+		return ReplaceSecretFindings(tx, "config/deploy.yml", []SecretFinding{
+			{Line: 7, Column: 16, Detector: "aws-access-key", Preview: "AKIA****"},
+			{Line: 3, Column: 9, Detector: "private-key", Preview: "----****"},
+		})
+	})
+
+	findings, err = QuerySecretFindings(db, SecretFindingFilter{})
+	require.NoError(t, err)
+	assert.Equal(t, []SecretFinding{
+		{FilePath: "config/deploy.yml", Line: 3, Column: 9, Detector: "private-key", Preview: "----****"},
+		{FilePath: "config/deploy.yml", Line: 7, Column: 16, Detector: "aws-access-key", Preview: "AKIA****"},
+		{FilePath: "internal/auth/client.go", Line: 12, Column: 20, Detector: "jwt", Preview: "eyJh****"},
+	}, findings)
+
+	findings, err = QuerySecretFindings(db, SecretFindingFilter{Detector: "jwt"})
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "internal/auth/client.go", findings[0].FilePath)
+
+	findings, err = QuerySecretFindings(db, SecretFindingFilter{PathPrefix: "config/"})
+	require.NoError(t, err)
+	assert.Len(t, findings, 2)
+
+	files, err := SecretFindingFiles(db)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"config/deploy.yml": true, "internal/auth/client.go": true}, files)
+
+	// Replacing a file's findings; deleting a file cascades
+	withTx(func(tx *sql.Tx) error {
+		return ReplaceSecretFindings(tx, "config/deploy.yml", nil)
+	})
+	_, err = db.Exec("DELETE FROM files WHERE file_path = 'internal/auth/client.go'")
+	require.NoError(t, err)
+	findings, err = QuerySecretFindings(db, SecretFindingFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
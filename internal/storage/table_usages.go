@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/mvp-joe/project-cortex/internal/graph"
+)
+
+// ReplaceTableUsages replaces the database tables the SQL embedded in
+// filePath reads, writes or defines.
+func ReplaceTableUsages(tx *sql.Tx, filePath string, usages []graph.TableUsage) error {
+	if err := DeleteTableUsages(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, u := range usages {
+		_, err := sq.Insert("table_usages").
+			Columns("file_path", "line", "table_name", "access", "operation", "columns", "source", "function", "function_id").
+			Values(filePath, u.Line, u.Table, u.Access, u.Operation, strings.Join(u.Columns, ","), u.Source, u.Function, nullIfEmpty(u.FunctionID)).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert table usage %s: %w", u.Table, err)
+		}
+	}
+	return nil
+}
+
+// DeleteTableUsages removes the table usages of filePath.
+func DeleteTableUsages(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("table_usages").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete table usages for %s: %w", filePath, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package storage
+
+// Test Plan for Table Usages:
+// - DeleteTableUsages is a no-op before any usage was indexed
+// - ReplaceTableUsages stores columns comma-separated and Go function IDs, NULL elsewhere
+// - Replacing a file's usages replaces them; deleting the file cascades
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/mvp-joe/project-cortex/internal/graph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableUsages(t *testing.T) {
+	t.Parallel()
+
+	db := NewTestDBFile(t)
+	for _, path := range []string{"storage/chunks.go", "web/repo.ts"} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, 'go', '', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, path)
+		require.NoError(t, err)
+	}
+
+	withTx := func(fn func(tx *sql.Tx) error) {
+		t.Helper()
+		tx, err := db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, fn(tx))
+		require.NoError(t, tx.Commit())
+	}
+	rows := func() []string {
+		t.Helper()
+		r, err := db.Query(`SELECT file_path || ' ' || table_name || ' ' || access || ' ' || operation || ' [' || columns || '] ' || source || ' ' || COALESCE(function_id, '-')
+			FROM table_usages ORDER BY usage_id`)
+		require.NoError(t, err)
+		defer r.Close()
+		var out []string
+		for r.Next() {
+			var s string
+			require.NoError(t, r.Scan(&s))
+			out = append(out, s)
+		}
+		require.NoError(t, r.Err())
+		return out
+	}
+
+	withTx(func(tx *sql.Tx) error {
+		return DeleteTableUsages(tx, "storage/chunks.go")
+	})
+
+	withTx(func(tx *sql.Tx) error {
+		if err := ReplaceTableUsages(tx, "storage/chunks.go", []graph.TableUsage{
+			{Line: 3, Table: "chunks", Access: graph.TableAccessSchema, Operation: "create", Columns: []string{"chunk_id", "file_path"}, Source: "sql"},
+			{Line: 9, Table: "chunks", Access: graph.TableAccessWrite, Operation: "insert", Columns: []string{"chunk_id"}, Source: "squirrel",
+				Function: "WriteChunks", FunctionID: "storage/chunks.go::WriteChunks"},
+		}); err != nil {
+			return err
+		}
+		return ReplaceTableUsages(tx, "web/repo.ts", []graph.TableUsage{
+			{Line: 4, Table: "users", Access: graph.TableAccessRead, Operation: "select", Source: "knex", Function: "UserRepo.find"},
+		})
+	})
+	assert.Equal(t, []string{
+		"storage/chunks.go chunks schema create [chunk_id,file_path] sql -",
+		"storage/chunks.go chunks write insert [chunk_id] squirrel storage/chunks.go::WriteChunks",
+		"web/repo.ts users read select [] knex -",
+	}, rows())
+
+	// Replacing a file's usages; deleting a file cascades
+	withTx(func(tx *sql.Tx) error {
+		return ReplaceTableUsages(tx, "storage/chunks.go", nil)
+	})
+	assert.Len(t, rows(), 1)
+	_, err := db.Exec("DELETE FROM files WHERE file_path = 'web/repo.ts'")
+	require.NoError(t, err)
+	assert.Empty(t, rows())
+}
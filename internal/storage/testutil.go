@@ -33,7 +33,7 @@ func init() {
 func NewTestDB(t testing.TB) *sql.DB {
 	t.Helper()
 
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := sql.Open("sqlite3", ":memory:?"+WriterDSNParams)
 	require.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
@@ -79,7 +79,7 @@ func NewTestDBFile(t testing.TB) *sql.DB {
 	t.Helper()
 
 	dbPath := filepath.Join(t.TempDir(), "test.db")
-	db, err := sql.Open("sqlite3", dbPath)
+	db, err := sql.Open("sqlite3", dbPath+"?"+WriterDSNParams)
 	require.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
@@ -124,7 +124,7 @@ func NewTestDBFile(t testing.TB) *sql.DB {
 func NewTestDBMinimal(t testing.TB) *sql.DB {
 	t.Helper()
 
-	db, err := sql.Open("sqlite3", ":memory:")
+	db, err := sql.Open("sqlite3", ":memory:?"+WriterDSNParams)
 	require.NoError(t, err)
 	t.Cleanup(func() { db.Close() })
 
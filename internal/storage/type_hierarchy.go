@@ -0,0 +1,189 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// DeclaredSupertype is a supertype written in non-Go source ("class A extends B",
+// "impl Trait for Type", a PHP trait use). Names are kept as written until
+// ResolveDeclaredSupertypes links them to indexed types, because the supertype
+// (or, for Rust impls, the type itself) is often declared in another file.
+type DeclaredSupertype struct {
+	FilePath  string
+	TypeName  string
+	Supertype string
+	Kind      string // extends, implements, mixin
+	Line      int
+}
+
+// goFilesQuery selects the indexed Go files. Go relationships are inferred
+// structurally by InterfaceInferencer; other languages declare theirs.
+const goFilesQuery = "SELECT file_path FROM files WHERE language = 'go'"
+
+// ReplaceDeclaredSupertypes replaces the supertypes declared in filePath.
+// Call ResolveDeclaredSupertypes afterwards to update type_relationships.
+func ReplaceDeclaredSupertypes(tx *sql.Tx, filePath string, supertypes []DeclaredSupertype) error {
+	if err := DeleteDeclaredSupertypes(tx, filePath); err != nil {
+		return err
+	}
+
+	for _, s := range supertypes {
+		_, err := sq.Insert("declared_supertypes").
+			Columns("file_path", "type_name", "supertype_name", "relationship_type", "source_line").
+			Values(filePath, s.TypeName, s.Supertype, s.Kind, s.Line).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert declared supertype %s -> %s: %w", s.TypeName, s.Supertype, err)
+		}
+	}
+	return nil
+}
+
+// DeleteDeclaredSupertypes removes the supertypes declared in filePath.
+func DeleteDeclaredSupertypes(tx *sql.Tx, filePath string) error {
+	_, err := sq.Delete("declared_supertypes").
+		Where(sq.Eq{"file_path": filePath}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete declared supertypes for %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// hierarchyType is an indexed non-Go type that declared supertypes resolve to.
+type hierarchyType struct {
+	id         string
+	filePath   string
+	modulePath string
+}
+
+// ResolveDeclaredSupertypes rebuilds the non-Go type_relationships from
+// declared_supertypes and returns how many were written.
+//
+// Both names of a declaration are looked up among indexed types of the same
+// language, preferring the declaring file, then its module (directory), then a
+// type that's unique in the language. Ambiguous names and names declared
+// outside the project (library base classes) are skipped.
+func ResolveDeclaredSupertypes(tx *sql.Tx) (int, error) {
+	_, err := sq.Delete("type_relationships").
+		Where("source_file_path NOT IN (" + goFilesQuery + ")").
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear declared relationships: %w", err)
+	}
+
+	types, err := loadHierarchyTypes(tx)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := sq.Select("d.file_path", "f.module_path", "f.language", "d.type_name",
+		"d.supertype_name", "d.relationship_type", "d.source_line").
+		From("declared_supertypes d").
+		Join("files f ON f.file_path = d.file_path").
+		OrderBy("d.file_path", "d.source_line").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return 0, fmt.Errorf("failed to query declared supertypes: %w", err)
+	}
+	defer rows.Close()
+
+	var rels []TypeRelationship
+	seen := make(map[string]bool)
+	for rows.Next() {
+		var filePath, modulePath, language, typeName, supertype, kind string
+		var line int
+		if err := rows.Scan(&filePath, &modulePath, &language, &typeName, &supertype, &kind, &line); err != nil {
+			return 0, fmt.Errorf("failed to scan declared supertype: %w", err)
+		}
+
+		byName := types[language]
+		from := resolveHierarchyType(byName[typeName], filePath, modulePath)
+		to := resolveHierarchyType(byName[supertype], filePath, modulePath)
+		if from == "" || to == "" || from == to {
+			continue
+		}
+
+		key := from + "\x00" + to + "\x00" + kind
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		rels = append(rels, TypeRelationship{
+			FromTypeID:       from,
+			ToTypeID:         to,
+			RelationshipType: kind,
+			SourceFilePath:   filePath,
+			SourceLine:       line,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	rows.Close()
+
+	if err := BulkInsertRelationships(tx, rels); err != nil {
+		return 0, err
+	}
+	return len(rels), nil
+}
+
+// loadHierarchyTypes indexes non-Go types by language, then name.
+func loadHierarchyTypes(tx *sql.Tx) (map[string]map[string][]hierarchyType, error) {
+	rows, err := sq.Select("t.type_id", "t.name", "t.file_path", "t.module_path", "f.language").
+		From("types t").
+		Join("files f ON f.file_path = t.file_path").
+		Where(sq.NotEq{"f.language": "go"}).
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query types: %w", err)
+	}
+	defer rows.Close()
+
+	types := make(map[string]map[string][]hierarchyType)
+	for rows.Next() {
+		var t hierarchyType
+		var name, language string
+		if err := rows.Scan(&t.id, &name, &t.filePath, &t.modulePath, &language); err != nil {
+			return nil, fmt.Errorf("failed to scan type: %w", err)
+		}
+		if types[language] == nil {
+			types[language] = make(map[string][]hierarchyType)
+		}
+		types[language][name] = append(types[language][name], t)
+	}
+	return types, rows.Err()
+}
+
+// resolveHierarchyType picks the candidate closest to the declaring file.
+// Returns "" when there is no candidate or no single best one.
+func resolveHierarchyType(candidates []hierarchyType, filePath, modulePath string) string {
+	for _, match := range []func(hierarchyType) bool{
+		func(t hierarchyType) bool { return t.filePath == filePath },
+		func(t hierarchyType) bool { return t.modulePath == modulePath },
+		func(t hierarchyType) bool { return true },
+	} {
+		var ids []string
+		for _, t := range candidates {
+			if match(t) {
+				ids = append(ids, t.id)
+			}
+		}
+		if len(ids) == 1 {
+			return ids[0]
+		}
+		if len(ids) > 1 {
+			return ""
+		}
+	}
+	return ""
+}
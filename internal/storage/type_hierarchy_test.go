@@ -0,0 +1,129 @@
+package storage
+
+// Test Plan for Declared Type Hierarchies:
+// - ResolveDeclaredSupertypes is a no-op before anything was declared
+// - Names resolve to the declaring file first, then its module, then a unique type
+// - Ambiguous names and supertypes outside the project are skipped
+// - Go interface inference keeps declared relationships and ignores other languages' types
+// - Re-declaring a file's supertypes replaces its relationships
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hierarchyTestDB indexes Java and Go files with a few types each.
+func hierarchyTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db := NewTestDBFile(t)
+	for _, f := range []struct{ path, language, module string }{
+		{"app/model/Base.java", "java", "app/model"},
+		{"app/model/User.java", "java", "app/model"},
+		{"app/api/Base.java", "java", "app/api"},
+		{"app/api/Handler.java", "java", "app/api"},
+		{"app/web/Page.java", "java", "app/web"},
+		{"main.go", "go", "."},
+	} {
+		_, err := db.Exec(`INSERT INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+			VALUES (?, ?, ?, 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, f.path, f.language, f.module)
+		require.NoError(t, err)
+	}
+
+	for _, typ := range []struct{ file, name, kind string }{
+		{"app/model/Base.java", "Base", "class"},
+		{"app/model/User.java", "User", "class"},
+		{"app/model/User.java", "Entity", "interface"},
+		{"app/api/Base.java", "Base", "class"},
+		{"app/api/Handler.java", "Handler", "class"},
+		{"app/web/Page.java", "Page", "class"},
+	} {
+		_, err := db.Exec(`INSERT INTO types (type_id, file_path, module_path, name, kind, start_line, end_line)
+			VALUES (?, ?, '', ?, ?, 1, 2)`, typ.file+"::"+typ.name, typ.file, typ.name, typ.kind)
+		require.NoError(t, err)
+	}
+	_, err := db.Exec(`UPDATE types SET module_path = (SELECT module_path FROM files WHERE files.file_path = types.file_path)`)
+	require.NoError(t, err)
+
+	// A Go struct with no methods: an empty Java interface must not match it
+	_, err = db.Exec(`INSERT INTO types (type_id, file_path, module_path, name, kind, start_line, end_line)
+		VALUES ('main::App', 'main.go', '.', 'App', 'struct', 1, 2)`)
+	require.NoError(t, err)
+
+	return db
+}
+
+func declareSupertypes(t *testing.T, db *sql.DB, file string, supertypes ...DeclaredSupertype) {
+	t.Helper()
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceDeclaredSupertypes(tx, file, supertypes)
+	}))
+}
+
+func resolveSupertypes(t *testing.T, db *sql.DB) []string {
+	t.Helper()
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		_, err := ResolveDeclaredSupertypes(tx)
+		return err
+	}))
+
+	rows, err := db.Query(`SELECT from_type_id || ' ' || relationship_type || ' ' || to_type_id
+		FROM type_relationships ORDER BY 1`)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var rels []string
+	for rows.Next() {
+		var rel string
+		require.NoError(t, rows.Scan(&rel))
+		rels = append(rels, rel)
+	}
+	return rels
+}
+
+func TestResolveDeclaredSupertypes_NothingDeclared(t *testing.T) {
+	t.Parallel()
+	db := hierarchyTestDB(t)
+
+	assert.Empty(t, resolveSupertypes(t, db))
+}
+
+func TestResolveDeclaredSupertypes(t *testing.T) {
+	t.Parallel()
+	db := hierarchyTestDB(t)
+
+	declareSupertypes(t, db, "app/model/User.java",
+		DeclaredSupertype{TypeName: "User", Supertype: "Base", Kind: "extends", Line: 3},            // Same module
+		DeclaredSupertype{TypeName: "User", Supertype: "Entity", Kind: "implements", Line: 3},       // Same file
+		DeclaredSupertype{TypeName: "User", Supertype: "Serializable", Kind: "implements", Line: 3}, // Outside the project
+	)
+	declareSupertypes(t, db, "app/api/Handler.java",
+		DeclaredSupertype{TypeName: "Handler", Supertype: "Base", Kind: "extends", Line: 5},
+	)
+	declareSupertypes(t, db, "app/web/Page.java",
+		DeclaredSupertype{TypeName: "Page", Supertype: "Base", Kind: "extends", Line: 2}, // Two candidates elsewhere
+		DeclaredSupertype{TypeName: "Page", Supertype: "User", Kind: "extends", Line: 2}, // Unique in the language
+		DeclaredSupertype{TypeName: "Page", Supertype: "User", Kind: "extends", Line: 9}, // Duplicate
+	)
+
+	assert.Equal(t, []string{
+		"app/api/Handler.java::Handler extends app/api/Base.java::Base",
+		"app/model/User.java::User extends app/model/Base.java::Base",
+		"app/model/User.java::User implements app/model/User.java::Entity",
+		"app/web/Page.java::Page extends app/model/User.java::User",
+	}, resolveSupertypes(t, db))
+
+	// Go inference leaves declared relationships alone and ignores Java types
+	require.NoError(t, NewInterfaceInferencer(db).InferImplementations(context.Background()))
+	rels := resolveSupertypes(t, db)
+	assert.Len(t, rels, 4)
+	assert.NotContains(t, rels, "main::App implements app/model/User.java::Entity")
+
+	// Re-declaring a file replaces its supertypes
+	declareSupertypes(t, db, "app/web/Page.java")
+	assert.NotContains(t, resolveSupertypes(t, db), "app/web/Page.java::Page extends app/model/User.java::User")
+}
@@ -3,7 +3,9 @@ package storage
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 
+	sq "github.com/Masterminds/squirrel"
 	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
 )
 
@@ -14,6 +16,281 @@ func InitVectorExtension() {
 	sqlite_vec.Auto()
 }
 
+// Vector index backend names, as used in StorageConfig.VectorIndex and
+// recorded in cache_metadata under vectorIndexMetadataKey.
+const (
+	VectorIndexExact = "exact" // sqlite-vec vec0 brute-force scan (default)
+	VectorIndexIVF   = "ivf"   // Pure-Go inverted file index (approximate)
+)
+
+// Vector quantization modes for the exact backend, as used in
+// StorageConfig.VectorQuantization and recorded under vectorQuantizationMetadataKey.
+const (
+	QuantizationNone   = "none"   // float32 vectors in chunks_vec
+	QuantizationInt8   = "int8"   // int8 vectors in chunks_vec_int8 (4x smaller), rescored
+	QuantizationBinary = "binary" // 1-bit vectors in chunks_vec_bit (32x smaller), rescored
+)
+
+// cache_metadata keys recording the active backend and quantization.
+const (
+	vectorIndexMetadataKey        = "vector_index"
+	vectorQuantizationMetadataKey = "vector_quantization"
+)
+
+// VectorIndex is a pluggable nearest-neighbor index over chunk embeddings.
+//
+// Implementations persist their state inside the branch database so index
+// updates commit atomically with the chunk writes that produced them.
+// Update and Delete are always called inside the chunk writer's transaction,
+// after chunk rows are inserted (Update) or before they are removed (Delete).
+type VectorIndex interface {
+	// Name returns the backend identifier (e.g., "exact", "ivf").
+	Name() string
+
+	// Create creates any tables the backend needs. Must be idempotent.
+	Create(db *sql.DB, dimensions int) error
+
+	// Update inserts or replaces vectors for the given chunks.
+	Update(tx *sql.Tx, chunks []*Chunk) error
+
+	// Delete removes vectors for the given chunk IDs.
+	Delete(tx *sql.Tx, chunkIDs []string) error
+
+	// Clear removes all vectors (used by full rebuilds).
+	Clear(tx *sql.Tx) error
+
+	// Search returns the limit nearest chunks by cosine distance, closest first.
+	// db may be a read transaction, so results match one committed generation.
+	Search(db sq.BaseRunner, queryEmb []float32, limit int) ([]*VectorSearchResult, error)
+}
+
+// sharedIVFIndex is reused across OpenVectorIndex calls so long-lived readers
+// keep their centroid cache between queries.
+var sharedIVFIndex = NewIVFIndex()
+
+// NewVectorIndex returns the backend registered under name with the given
+// quantization. Empty values select the exact backend without quantization.
+// Quantization only applies to the exact backend.
+func NewVectorIndex(name, quantization string) (VectorIndex, error) {
+	switch name {
+	case "", VectorIndexExact:
+		switch quantization {
+		case "", QuantizationNone:
+			return &exactVectorIndex{}, nil
+		case QuantizationInt8, QuantizationBinary:
+			return newQuantizedVectorIndex(quantization), nil
+		default:
+			return nil, fmt.Errorf("unknown vector quantization %q (valid: %s, %s, %s)", quantization, QuantizationNone, QuantizationInt8, QuantizationBinary)
+		}
+	case VectorIndexIVF:
+		if quantization != "" && quantization != QuantizationNone {
+			return nil, fmt.Errorf("vector quantization %q requires the %s backend", quantization, VectorIndexExact)
+		}
+		return sharedIVFIndex, nil
+	default:
+		return nil, fmt.Errorf("unknown vector index backend %q (valid: %s, %s)", name, VectorIndexExact, VectorIndexIVF)
+	}
+}
+
+// OpenVectorIndex returns the backend recorded for this database.
+// Databases without a recorded backend (including pre-existing caches) use the exact backend.
+// Accepts either *sql.DB or *sql.Tx so writers can resolve the backend inside their transaction.
+func OpenVectorIndex(db sq.BaseRunner) (VectorIndex, error) {
+	name, quantization, err := readVectorIndexConfig(db)
+	if err != nil {
+		return nil, err
+	}
+	return NewVectorIndex(name, quantization)
+}
+
+// ConfigureVectorIndex selects the vector index backend and quantization for a database.
+// If the request differs from what is recorded, the new index is rebuilt from
+// the embeddings stored in the chunks table and the previous index's vectors
+// are dropped in the same transaction, so readers never observe a
+// half-populated index and no duplicate vector copy is left behind.
+func ConfigureVectorIndex(db *sql.DB, name, quantization string) (VectorIndex, error) {
+	index, err := NewVectorIndex(name, quantization)
+	if err != nil {
+		return nil, err
+	}
+
+	currentName, currentQuantization, err := readVectorIndexConfig(db)
+	if err != nil {
+		return nil, err
+	}
+	if currentName == index.Name() && currentQuantization == VectorQuantization(index) {
+		return index, nil
+	}
+
+	previous, err := NewVectorIndex(currentName, currentQuantization)
+	if err != nil {
+		return nil, err
+	}
+
+	dimensions, err := embeddingDimensions(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := index.Create(db, dimensions); err != nil {
+		return nil, fmt.Errorf("failed to create %s vector index: %w", index.Name(), err)
+	}
+
+	if err := rebuildVectorIndex(db, index, previous); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// VectorQuantization returns the quantization mode of index.
+func VectorQuantization(index VectorIndex) string {
+	if q, ok := index.(*quantizedVectorIndex); ok {
+		return q.quantization
+	}
+	return QuantizationNone
+}
+
+// SupportsSQLKNN reports whether index keeps float vectors in chunks_vec, so
+// callers may push KNN into a SQL join (embedding MATCH ? AND k = ?) instead
+// of calling Search and filtering candidates afterwards.
+func SupportsSQLKNN(index VectorIndex) bool {
+	_, ok := index.(*exactVectorIndex)
+	return ok
+}
+
+// RebuildVectorIndex repopulates index from the embeddings stored in the chunks
+// table and records it as the active backend, all in one transaction.
+func RebuildVectorIndex(db *sql.DB, index VectorIndex) error {
+	return rebuildVectorIndex(db, index, nil)
+}
+
+// rebuildVectorIndex repopulates index and, if previous is non-nil and stored
+// separately, clears the previous index's vectors in the same transaction.
+func rebuildVectorIndex(db *sql.DB, index, previous VectorIndex) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if previous != nil && vectorIndexTable(previous) != vectorIndexTable(index) {
+		if err := previous.Clear(tx); err != nil {
+			return fmt.Errorf("failed to clear previous vector index: %w", err)
+		}
+	}
+
+	if err := index.Clear(tx); err != nil {
+		return fmt.Errorf("failed to clear vector index: %w", err)
+	}
+
+	rows, err := sq.Select("chunk_id", "embedding").
+		From("chunks").
+		RunWith(tx).
+		Query()
+	if err != nil {
+		return fmt.Errorf("failed to query chunk embeddings: %w", err)
+	}
+
+	var chunks []*Chunk
+	for rows.Next() {
+		var (
+			id       string
+			embBytes []byte
+		)
+		if err := rows.Scan(&id, &embBytes); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan chunk embedding: %w", err)
+		}
+		emb, err := DeserializeEmbedding(embBytes)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to deserialize embedding for chunk %s: %w", id, err)
+		}
+		if len(emb) == 0 {
+			continue
+		}
+		chunks = append(chunks, &Chunk{ID: id, Embedding: emb})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating chunk embeddings: %w", err)
+	}
+
+	if err := index.Update(tx, chunks); err != nil {
+		return fmt.Errorf("failed to populate %s vector index: %w", index.Name(), err)
+	}
+
+	if err := writeMetadataValue(tx, vectorIndexMetadataKey, index.Name()); err != nil {
+		return err
+	}
+	if err := writeMetadataValue(tx, vectorQuantizationMetadataKey, VectorQuantization(index)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit vector index rebuild: %w", err)
+	}
+
+	return nil
+}
+
+// embeddingDimensions returns the embedding dimensions recorded in
+// cache_metadata, defaulting to 384 (BGE-small).
+func embeddingDimensions(db sq.BaseRunner) (int, error) {
+	dimsStr, err := readMetadataValue(db, "embedding_dimensions")
+	if err != nil {
+		return 0, err
+	}
+	if n, convErr := strconv.Atoi(dimsStr); convErr == nil && n > 0 {
+		return n, nil
+	}
+	return 384, nil
+}
+
+// vectorIndexTable returns the table holding index's vectors.
+func vectorIndexTable(index VectorIndex) string {
+	switch idx := index.(type) {
+	case *quantizedVectorIndex:
+		return idx.table()
+	case *IVFIndex:
+		return "chunks_ivf"
+	default:
+		return "chunks_vec"
+	}
+}
+
+// readVectorIndexConfig returns the backend and quantization recorded in
+// cache_metadata, defaulting to exact/none if nothing is recorded or the
+// metadata table does not exist.
+func readVectorIndexConfig(db sq.BaseRunner) (string, string, error) {
+	exists, err := metadataTableExists(db)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		return VectorIndexExact, QuantizationNone, nil
+	}
+
+	name, err := readMetadataValue(db, vectorIndexMetadataKey)
+	if err != nil {
+		return "", "", err
+	}
+	if name == "" {
+		name = VectorIndexExact
+	}
+
+	quantization, err := readMetadataValue(db, vectorQuantizationMetadataKey)
+	if err != nil {
+		return "", "", err
+	}
+	if quantization == "" {
+		quantization = QuantizationNone
+	}
+
+	return name, quantization, nil
+}
+
 // CreateVectorIndex creates a virtual table for vector similarity search.
 // Uses sqlite-vec's vec0 virtual table to enable efficient vector queries.
 //
@@ -22,6 +299,9 @@ func InitVectorExtension() {
 // - K-nearest neighbors queries
 // - Distance-based filtering
 //
+// KNN queries (embedding MATCH ?) return cosine distance, like the other
+// backends' Search, so scores mean the same whichever backend is active.
+//
 // Note: This does NOT store chunk data, only indexes for vector search.
 // Join with chunks table to get full chunk details.
 func CreateVectorIndex(db *sql.DB, dimensions int) error {
@@ -30,7 +310,7 @@ func CreateVectorIndex(db *sql.DB, dimensions int) error {
 	createSQL := fmt.Sprintf(`
 		CREATE VIRTUAL TABLE IF NOT EXISTS chunks_vec USING vec0(
 			chunk_id TEXT PRIMARY KEY,
-			embedding float[%d]
+			embedding float[%d] distance_metric=cosine
 		)
 	`, dimensions)
 
@@ -41,6 +321,31 @@ func CreateVectorIndex(db *sql.DB, dimensions int) error {
 	return nil
 }
 
+// recreateCosineVectorIndex recreates chunks_vec, which schemas before 2.3
+// created with vec0's default L2 metric, and repopulates it from the chunks
+// table if the exact backend is active.
+func recreateCosineVectorIndex(db *sql.DB) error {
+	dimensions, err := embeddingDimensions(db)
+	if err != nil {
+		return err
+	}
+	if _, err := db.Exec("DROP TABLE IF EXISTS chunks_vec"); err != nil {
+		return fmt.Errorf("failed to drop vector index: %w", err)
+	}
+	if err := CreateVectorIndex(db, dimensions); err != nil {
+		return err
+	}
+
+	index, err := OpenVectorIndex(db)
+	if err != nil {
+		return err
+	}
+	if !SupportsSQLKNN(index) {
+		return nil
+	}
+	return RebuildVectorIndex(db, index)
+}
+
 // UpdateVectorIndex inserts or updates vectors in the index.
 // Performs upsert operation - replaces existing vectors for same chunk_id.
 //
@@ -125,12 +430,12 @@ type VectorSearchResult struct {
 // Callers should JOIN with chunks table to get full chunk data.
 //
 // Parameters:
-//   - db: Database connection
+//   - db: Database connection or transaction
 //   - queryEmb: Query embedding vector (must match index dimensions)
 //   - limit: Number of results to return (K in KNN)
 //
 // Returns results ordered by distance (ascending - closest first).
-func QueryVectorSimilarity(db *sql.DB, queryEmb []float32, limit int) ([]*VectorSearchResult, error) {
+func QueryVectorSimilarity(db sq.BaseRunner, queryEmb []float32, limit int) ([]*VectorSearchResult, error) {
 	// Serialize query embedding
 	queryBytes, err := sqlite_vec.SerializeFloat32(queryEmb)
 	if err != nil {
@@ -175,14 +480,23 @@ func QueryVectorSimilarity(db *sql.DB, queryEmb []float32, limit int) ([]*Vector
 type VectorIndexStats struct {
 	TotalVectors int
 	Dimensions   int
+	Backend      string // Active backend (exact, ivf)
+	Quantization string // Active quantization (none, int8, binary)
 }
 
-// GetVectorIndexStats retrieves statistics about the vector index.
+// GetVectorIndexStats retrieves statistics about the active vector index.
 func GetVectorIndexStats(db *sql.DB) (*VectorIndexStats, error) {
 	var stats VectorIndexStats
 
+	index, err := OpenVectorIndex(db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vector index: %w", err)
+	}
+	stats.Backend = index.Name()
+	stats.Quantization = VectorQuantization(index)
+
 	// Get count
-	err := db.QueryRow("SELECT COUNT(*) FROM chunks_vec").Scan(&stats.TotalVectors)
+	err = db.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM %s", vectorIndexTable(index))).Scan(&stats.TotalVectors)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query vector count: %w", err)
 	}
@@ -196,3 +510,32 @@ func GetVectorIndexStats(db *sql.DB) (*VectorIndexStats, error) {
 
 	return &stats, nil
 }
+
+// exactVectorIndex is the default backend: sqlite-vec's vec0 table with
+// brute-force KNN. Exact results, linear query cost in the number of chunks.
+type exactVectorIndex struct{}
+
+func (e *exactVectorIndex) Name() string { return VectorIndexExact }
+
+func (e *exactVectorIndex) Create(db *sql.DB, dimensions int) error {
+	return CreateVectorIndex(db, dimensions)
+}
+
+func (e *exactVectorIndex) Update(tx *sql.Tx, chunks []*Chunk) error {
+	return UpdateVectorIndex(tx, chunks)
+}
+
+func (e *exactVectorIndex) Delete(tx *sql.Tx, chunkIDs []string) error {
+	return DeleteVectorsByFile(tx, chunkIDs)
+}
+
+func (e *exactVectorIndex) Clear(tx *sql.Tx) error {
+	if _, err := tx.Exec("DELETE FROM chunks_vec"); err != nil {
+		return fmt.Errorf("failed to clear vector index: %w", err)
+	}
+	return nil
+}
+
+func (e *exactVectorIndex) Search(db sq.BaseRunner, queryEmb []float32, limit int) ([]*VectorSearchResult, error) {
+	return QueryVectorSimilarity(db, queryEmb, limit)
+}
@@ -0,0 +1,697 @@
+package storage
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// IVF (inverted file) vector index.
+//
+// Vectors are partitioned into lists by their nearest centroid (spherical
+// k-means over the stored embeddings). A query only scans the NumProbes lists
+// whose centroids are closest to the query vector, so query cost is roughly
+// N * NumProbes / NumLists instead of N.
+//
+// All state lives in the branch database next to the chunks it indexes:
+//   - chunks_ivf:           chunk_id -> list_id (embeddings are read from chunks.embedding)
+//   - chunks_ivf_centroids: list_id -> centroid BLOB
+//   - cache_metadata:       ivf_count, ivf_trained_count, ivf_version
+//
+// Until MinTrainSize vectors have been written every row sits in the
+// unassigned list and queries degrade to an exact scan. Centroids are
+// retrained whenever the vector count grows RetrainFactor times past the
+// last training, which keeps training cost amortized per insert.
+
+// IVF tuning defaults.
+const (
+	DefaultIVFNumProbes     = 16   // Lists scanned per query
+	DefaultIVFMinTrainSize  = 4096 // Vectors required before the first training
+	DefaultIVFRetrainFactor = 4    // Retrain when the count grows by this factor
+
+	ivfMaxLists            = 1024 // Upper bound on the number of lists
+	ivfTrainSamplesPerList = 32   // k-means sample size per list
+	ivfTrainIterations     = 8    // k-means iterations
+	ivfReassignPageSize    = 5000 // Rows reassigned per page after retraining
+	ivfUnassignedList      = -1   // List for vectors written before training
+
+	ivfCountKey        = "ivf_count"
+	ivfTrainedCountKey = "ivf_trained_count"
+	ivfVersionKey      = "ivf_version"
+)
+
+const createIVFTable = `
+CREATE TABLE IF NOT EXISTS chunks_ivf (
+    chunk_id TEXT PRIMARY KEY,
+    list_id INTEGER NOT NULL,
+    FOREIGN KEY (chunk_id) REFERENCES chunks(chunk_id) ON DELETE CASCADE
+)`
+
+const createIVFListIndex = `CREATE INDEX IF NOT EXISTS idx_chunks_ivf_list ON chunks_ivf(list_id)`
+
+const createIVFCentroidsTable = `
+CREATE TABLE IF NOT EXISTS chunks_ivf_centroids (
+    list_id INTEGER PRIMARY KEY,
+    centroid BLOB NOT NULL
+)`
+
+// IVFIndex is a pure-Go approximate nearest-neighbor backend.
+// Safe for concurrent use; centroids are cached per index version.
+type IVFIndex struct {
+	NumProbes     int
+	MinTrainSize  int
+	RetrainFactor int
+
+	mu              sync.Mutex
+	cachedVersion   string
+	cachedCentroids [][]float32
+}
+
+// NewIVFIndex creates an IVF backend with default tuning.
+func NewIVFIndex() *IVFIndex {
+	return &IVFIndex{
+		NumProbes:     DefaultIVFNumProbes,
+		MinTrainSize:  DefaultIVFMinTrainSize,
+		RetrainFactor: DefaultIVFRetrainFactor,
+	}
+}
+
+// Name returns the backend identifier.
+func (x *IVFIndex) Name() string { return VectorIndexIVF }
+
+// Create creates the IVF tables. Dimensions are implied by the stored embeddings.
+func (x *IVFIndex) Create(db *sql.DB, dimensions int) error {
+	for _, ddl := range []string{createIVFTable, createIVFListIndex, createIVFCentroidsTable} {
+		if _, err := db.Exec(ddl); err != nil {
+			return fmt.Errorf("failed to create IVF index: %w", err)
+		}
+	}
+	return nil
+}
+
+// Update assigns each chunk to its nearest list and retrains centroids when the
+// index has grown enough. Chunk rows must already exist in the transaction.
+func (x *IVFIndex) Update(tx *sql.Tx, chunks []*Chunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	centroids, err := loadIVFCentroids(tx)
+	if err != nil {
+		return err
+	}
+
+	vectors := make([][]float32, len(chunks))
+	for i, chunk := range chunks {
+		vectors[i] = normalizeVector(chunk.Embedding)
+	}
+	lists := assignLists(vectors, centroids)
+
+	// Insert new entries and move existing ones, counting only the new
+	insertStmt, err := tx.Prepare("INSERT OR IGNORE INTO chunks_ivf (chunk_id, list_id) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare IVF insert statement: %w", err)
+	}
+	defer insertStmt.Close()
+	updateStmt, err := tx.Prepare("UPDATE chunks_ivf SET list_id = ? WHERE chunk_id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare IVF update statement: %w", err)
+	}
+	defer updateStmt.Close()
+
+	added := 0
+	for i, chunk := range chunks {
+		result, err := insertStmt.Exec(chunk.ID, lists[i])
+		if err != nil {
+			return fmt.Errorf("failed to insert IVF entry for chunk %s: %w", chunk.ID, err)
+		}
+		if inserted, _ := result.RowsAffected(); inserted > 0 {
+			added++
+			continue
+		}
+		if _, err := updateStmt.Exec(lists[i], chunk.ID); err != nil {
+			return fmt.Errorf("failed to update IVF entry for chunk %s: %w", chunk.ID, err)
+		}
+	}
+
+	count, err := addIVFCount(tx, added)
+	if err != nil {
+		return err
+	}
+	return x.maybeTrain(tx, count)
+}
+
+// Delete removes IVF entries for the given chunk IDs.
+func (x *IVFIndex) Delete(tx *sql.Tx, chunkIDs []string) error {
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+	result, err := sq.Delete("chunks_ivf").Where(sq.Eq{"chunk_id": chunkIDs}).RunWith(tx).Exec()
+	if err != nil {
+		return fmt.Errorf("failed to delete IVF entries: %w", err)
+	}
+	deleted, _ := result.RowsAffected()
+	_, err = addIVFCount(tx, -int(deleted))
+	return err
+}
+
+// Clear removes all entries and centroids so the next write retrains from scratch.
+func (x *IVFIndex) Clear(tx *sql.Tx) error {
+	if _, err := tx.Exec("DELETE FROM chunks_ivf"); err != nil {
+		return fmt.Errorf("failed to clear IVF index: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM chunks_ivf_centroids"); err != nil {
+		return fmt.Errorf("failed to clear IVF centroids: %w", err)
+	}
+	_, err := sq.Delete("cache_metadata").
+		Where(sq.Eq{"key": []string{ivfCountKey, ivfTrainedCountKey, ivfVersionKey}}).
+		RunWith(tx).
+		Exec()
+	if err != nil {
+		return fmt.Errorf("failed to clear IVF metadata: %w", err)
+	}
+	return nil
+}
+
+// Search scans the NumProbes lists closest to the query and returns the
+// limit nearest chunks by cosine distance.
+func (x *IVFIndex) Search(db sq.BaseRunner, queryEmb []float32, limit int) ([]*VectorSearchResult, error) {
+	if limit <= 0 {
+		return []*VectorSearchResult{}, nil
+	}
+
+	centroids, err := x.centroids(db)
+	if err != nil {
+		return nil, err
+	}
+
+	query := normalizeVector(queryEmb)
+
+	sqlQuery := sq.Select("i.chunk_id", "c.embedding").
+		From("chunks_ivf i").
+		Join("chunks c ON c.chunk_id = i.chunk_id")
+	if len(centroids) > 0 {
+		probes := nearestLists(query, centroids, x.NumProbes)
+		sqlQuery = sqlQuery.Where(sq.Eq{"i.list_id": append(probes, ivfUnassignedList)})
+	}
+
+	rows, err := sqlQuery.RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query IVF index: %w", err)
+	}
+	defer rows.Close()
+
+	top := newTopK(limit)
+	for rows.Next() {
+		var (
+			id       string
+			embBytes []byte
+		)
+		if err := rows.Scan(&id, &embBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan IVF result: %w", err)
+		}
+		top.offer(id, cosineDistanceBytes(query, embBytes))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating IVF results: %w", err)
+	}
+
+	return top.results(), nil
+}
+
+// centroids returns the current centroids, reloading them only when the
+// ivf_version recorded in cache_metadata has changed.
+func (x *IVFIndex) centroids(db sq.BaseRunner) ([][]float32, error) {
+	version, err := readMetadataValue(db, ivfVersionKey)
+	if err != nil {
+		return nil, err
+	}
+	if version == "" {
+		return nil, nil
+	}
+
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	if version == x.cachedVersion {
+		return x.cachedCentroids, nil
+	}
+
+	centroids, err := loadIVFCentroids(db)
+	if err != nil {
+		return nil, err
+	}
+	x.cachedVersion = version
+	x.cachedCentroids = centroids
+	return centroids, nil
+}
+
+// maybeTrain retrains centroids once the index reaches MinTrainSize vectors,
+// and again each time it grows RetrainFactor times past the last training.
+// count is the entry count kept in cache_metadata.
+func (x *IVFIndex) maybeTrain(tx *sql.Tx, count int) error {
+	if due, err := x.needsTraining(tx, count); err != nil || !due {
+		return err
+	}
+
+	// Entries removed by cascading deletes are never subtracted from the
+	// kept count: recount before paying for a training
+	exact, err := countIVFEntries(tx)
+	if err != nil {
+		return err
+	}
+	if exact != count {
+		if err := writeMetadataValue(tx, ivfCountKey, strconv.Itoa(exact)); err != nil {
+			return err
+		}
+		if due, err := x.needsTraining(tx, exact); err != nil || !due {
+			return err
+		}
+	}
+
+	return x.train(tx, exact)
+}
+
+// needsTraining reports whether an index of count entries is due for training.
+func (x *IVFIndex) needsTraining(tx *sql.Tx, count int) (bool, error) {
+	if count < x.MinTrainSize {
+		return false, nil
+	}
+	trainedStr, err := readMetadataValue(tx, ivfTrainedCountKey)
+	if err != nil {
+		return false, err
+	}
+	trained, _ := strconv.Atoi(trainedStr)
+	return trained == 0 || count >= trained*x.RetrainFactor, nil
+}
+
+// addIVFCount adds delta to the entry count kept in cache_metadata and
+// returns the new count. Entries are counted once when no count is recorded
+// (after Clear, or for indexes written before the count was kept).
+func addIVFCount(tx *sql.Tx, delta int) (int, error) {
+	value, err := readMetadataValue(tx, ivfCountKey)
+	if err != nil {
+		return 0, err
+	}
+	var count int
+	if value == "" {
+		if count, err = countIVFEntries(tx); err != nil {
+			return 0, err
+		}
+	} else {
+		count, _ = strconv.Atoi(value)
+		count += delta
+	}
+	if err := writeMetadataValue(tx, ivfCountKey, strconv.Itoa(count)); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// countIVFEntries counts the rows of chunks_ivf.
+func countIVFEntries(tx *sql.Tx) (int, error) {
+	var count int
+	if err := tx.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count IVF entries: %w", err)
+	}
+	return count, nil
+}
+
+// train runs spherical k-means on a random sample, stores the centroids, and
+// reassigns every entry to its nearest new centroid.
+func (x *IVFIndex) train(tx *sql.Tx, count int) error {
+	numLists := int(math.Sqrt(float64(count)))
+	if numLists < 1 {
+		numLists = 1
+	}
+	if numLists > ivfMaxLists {
+		numLists = ivfMaxLists
+	}
+
+	rows, err := tx.Query(`
+		SELECT c.embedding
+		FROM chunks_ivf i
+		JOIN chunks c ON c.chunk_id = i.chunk_id
+		ORDER BY random()
+		LIMIT ?`, numLists*ivfTrainSamplesPerList)
+	if err != nil {
+		return fmt.Errorf("failed to sample IVF training vectors: %w", err)
+	}
+	var samples [][]float32
+	for rows.Next() {
+		var embBytes []byte
+		if err := rows.Scan(&embBytes); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan training vector: %w", err)
+		}
+		emb, err := DeserializeEmbedding(embBytes)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to deserialize training vector: %w", err)
+		}
+		samples = append(samples, normalizeVector(emb))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating training vectors: %w", err)
+	}
+	if len(samples) < numLists {
+		numLists = len(samples)
+	}
+	if numLists == 0 {
+		return nil
+	}
+
+	centroids := trainCentroids(samples, numLists, ivfTrainIterations)
+
+	if _, err := tx.Exec("DELETE FROM chunks_ivf_centroids"); err != nil {
+		return fmt.Errorf("failed to clear IVF centroids: %w", err)
+	}
+	insertStmt, err := tx.Prepare("INSERT INTO chunks_ivf_centroids (list_id, centroid) VALUES (?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare centroid insert statement: %w", err)
+	}
+	defer insertStmt.Close()
+	for listID, centroid := range centroids {
+		if _, err := insertStmt.Exec(listID, SerializeEmbedding(centroid)); err != nil {
+			return fmt.Errorf("failed to insert centroid %d: %w", listID, err)
+		}
+	}
+
+	if err := reassignLists(tx, centroids); err != nil {
+		return err
+	}
+
+	if err := writeMetadataValue(tx, ivfTrainedCountKey, strconv.Itoa(count)); err != nil {
+		return err
+	}
+	// Version is unique per training so cached centroids in other
+	// processes are never mistaken for the new ones.
+	return writeMetadataValue(tx, ivfVersionKey, strconv.FormatInt(time.Now().UnixNano(), 10))
+}
+
+// reassignLists pages through all entries by rowid and moves each to its
+// nearest centroid. Paging keeps memory bounded on very large indexes.
+func reassignLists(tx *sql.Tx, centroids [][]float32) error {
+	updateStmt, err := tx.Prepare("UPDATE chunks_ivf SET list_id = ? WHERE rowid = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare IVF reassign statement: %w", err)
+	}
+	defer updateStmt.Close()
+
+	var lastRowID int64
+	for {
+		rows, err := tx.Query(`
+			SELECT i.rowid, c.embedding
+			FROM chunks_ivf i
+			JOIN chunks c ON c.chunk_id = i.chunk_id
+			WHERE i.rowid > ?
+			ORDER BY i.rowid
+			LIMIT ?`, lastRowID, ivfReassignPageSize)
+		if err != nil {
+			return fmt.Errorf("failed to page IVF entries: %w", err)
+		}
+
+		var (
+			rowIDs  []int64
+			vectors [][]float32
+		)
+		for rows.Next() {
+			var (
+				rowID    int64
+				embBytes []byte
+			)
+			if err := rows.Scan(&rowID, &embBytes); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan IVF entry: %w", err)
+			}
+			emb, err := DeserializeEmbedding(embBytes)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to deserialize IVF entry: %w", err)
+			}
+			rowIDs = append(rowIDs, rowID)
+			vectors = append(vectors, normalizeVector(emb))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating IVF entries: %w", err)
+		}
+		if len(rowIDs) == 0 {
+			return nil
+		}
+
+		lists := assignLists(vectors, centroids)
+		for i, rowID := range rowIDs {
+			if _, err := updateStmt.Exec(lists[i], rowID); err != nil {
+				return fmt.Errorf("failed to reassign IVF entry: %w", err)
+			}
+		}
+		lastRowID = rowIDs[len(rowIDs)-1]
+	}
+}
+
+// trainCentroids runs spherical k-means. Samples must be unit vectors and
+// already shuffled; the first k samples seed the centroids.
+func trainCentroids(samples [][]float32, k, iterations int) [][]float32 {
+	dims := len(samples[0])
+	centroids := make([][]float32, k)
+	for i := range centroids {
+		centroids[i] = append([]float32(nil), samples[i]...)
+	}
+
+	for iter := 0; iter < iterations; iter++ {
+		assignments := assignLists(samples, centroids)
+
+		sums := make([][]float32, k)
+		for i := range sums {
+			sums[i] = make([]float32, dims)
+		}
+		counts := make([]int, k)
+		for i, list := range assignments {
+			counts[list]++
+			sum := sums[list]
+			for d, v := range samples[i] {
+				sum[d] += v
+			}
+		}
+
+		for i := range centroids {
+			// Empty lists keep their previous centroid
+			if counts[i] > 0 {
+				centroids[i] = normalizeVector(sums[i])
+			}
+		}
+	}
+
+	return centroids
+}
+
+// assignLists returns the nearest centroid for each unit vector, splitting
+// the work across CPUs. Returns ivfUnassignedList for all when untrained.
+func assignLists(vectors, centroids [][]float32) []int {
+	lists := make([]int, len(vectors))
+	if len(centroids) == 0 {
+		for i := range lists {
+			lists[i] = ivfUnassignedList
+		}
+		return lists
+	}
+
+	workers := runtime.NumCPU()
+	chunkSize := (len(vectors) + workers - 1) / workers
+	var wg sync.WaitGroup
+	for start := 0; start < len(vectors); start += chunkSize {
+		end := start + chunkSize
+		if end > len(vectors) {
+			end = len(vectors)
+		}
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				lists[i] = nearestLists(vectors[i], centroids, 1)[0]
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	return lists
+}
+
+// nearestLists returns the n centroid indexes with the highest dot product to v.
+func nearestLists(v []float32, centroids [][]float32, n int) []int {
+	if n > len(centroids) {
+		n = len(centroids)
+	}
+	type scored struct {
+		list  int
+		score float32
+	}
+	best := make([]scored, 0, n+1)
+	for i, c := range centroids {
+		s := dot(v, c)
+		if len(best) == n && s <= best[n-1].score {
+			continue
+		}
+		pos := sort.Search(len(best), func(j int) bool { return best[j].score < s })
+		best = append(best, scored{})
+		copy(best[pos+1:], best[pos:])
+		best[pos] = scored{list: i, score: s}
+		if len(best) > n {
+			best = best[:n]
+		}
+	}
+
+	lists := make([]int, len(best))
+	for i, b := range best {
+		lists[i] = b.list
+	}
+	return lists
+}
+
+// topK keeps the k smallest distances seen so far, sorted ascending.
+type topK struct {
+	k     int
+	items []*VectorSearchResult
+}
+
+func newTopK(k int) *topK {
+	return &topK{k: k, items: make([]*VectorSearchResult, 0, k+1)}
+}
+
+func (t *topK) offer(chunkID string, distance float64) {
+	if len(t.items) == t.k && distance >= t.items[t.k-1].Distance {
+		return
+	}
+	pos := sort.Search(len(t.items), func(i int) bool { return t.items[i].Distance > distance })
+	t.items = append(t.items, nil)
+	copy(t.items[pos+1:], t.items[pos:])
+	t.items[pos] = &VectorSearchResult{ChunkID: chunkID, Distance: distance}
+	if len(t.items) > t.k {
+		t.items = t.items[:t.k]
+	}
+}
+
+func (t *topK) results() []*VectorSearchResult {
+	return t.items
+}
+
+// cosineDistanceBytes computes 1 - cos(query, emb) where query is a unit vector
+// and emb is a serialized embedding, without allocating a float slice.
+func cosineDistanceBytes(query []float32, embBytes []byte) float64 {
+	n := len(embBytes) / 4
+	if n > len(query) {
+		n = len(query)
+	}
+	var dotProduct, norm float64
+	for i := 0; i < n; i++ {
+		v := float64(math.Float32frombits(binary.LittleEndian.Uint32(embBytes[i*4:])))
+		dotProduct += float64(query[i]) * v
+		norm += v * v
+	}
+	if norm == 0 {
+		return 1
+	}
+	return 1 - dotProduct/math.Sqrt(norm)
+}
+
+// normalizeVector returns a unit-length copy of v (zero vectors are returned as-is).
+func normalizeVector(v []float32) []float32 {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	out := make([]float32, len(v))
+	if sum == 0 {
+		copy(out, v)
+		return out
+	}
+	inv := float32(1 / math.Sqrt(sum))
+	for i, x := range v {
+		out[i] = x * inv
+	}
+	return out
+}
+
+func dot(a, b []float32) float32 {
+	var sum float32
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
+
+// loadIVFCentroids reads centroids ordered by list_id.
+func loadIVFCentroids(db sq.BaseRunner) ([][]float32, error) {
+	rows, err := sq.Select("centroid").
+		From("chunks_ivf_centroids").
+		OrderBy("list_id").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load IVF centroids: %w", err)
+	}
+	defer rows.Close()
+
+	var centroids [][]float32
+	for rows.Next() {
+		var blob []byte
+		if err := rows.Scan(&blob); err != nil {
+			return nil, fmt.Errorf("failed to scan IVF centroid: %w", err)
+		}
+		centroid, err := DeserializeEmbedding(blob)
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize IVF centroid: %w", err)
+		}
+		centroids = append(centroids, centroid)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating IVF centroids: %w", err)
+	}
+
+	return centroids, nil
+}
+
+// metadataTableExists reports whether the cache_metadata table exists.
+func metadataTableExists(db sq.BaseRunner) (bool, error) {
+	return hasTable(db, "cache_metadata")
+}
+
+// readMetadataValue returns a cache_metadata value, or "" if the key is absent.
+func readMetadataValue(db sq.BaseRunner, key string) (string, error) {
+	var value string
+	err := sq.Select("value").
+		From("cache_metadata").
+		Where(sq.Eq{"key": key}).
+		RunWith(db).
+		QueryRow().
+		Scan(&value)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read metadata %s: %w", key, err)
+	}
+	return value, nil
+}
+
+// writeMetadataValue upserts a cache_metadata value.
+func writeMetadataValue(tx *sql.Tx, key, value string) error {
+	_, err := tx.Exec(
+		"INSERT OR REPLACE INTO cache_metadata (key, value, updated_at) VALUES (?, ?, datetime('now'))",
+		key, value,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write metadata %s: %w", key, err)
+	}
+	return nil
+}
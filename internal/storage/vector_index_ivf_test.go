@@ -0,0 +1,466 @@
+package storage
+
+// Test Plan for Pluggable Vector Index / IVF backend:
+// - NewVectorIndex resolves exact/ivf and rejects unknown names
+// - OpenVectorIndex defaults to exact for new and legacy databases
+// - ConfigureVectorIndex records the backend and rebuilds from stored embeddings
+// - ChunkWriter keeps the IVF index in sync on full and incremental writes
+// - IVF training assigns every entry to a list once MinTrainSize is reached
+// - IVF search after training matches exact search for well-separated clusters
+// - IVF Delete and Clear remove entries (Clear also drops centroids)
+// - IVF entry count is kept in cache_metadata across Update, Delete and Clear
+// - ChunkReader.SearchByEmbedding uses the configured backend
+// - Benchmark: recall@10 and latency of IVF vs exact on clustered vectors
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVectorIndex(t *testing.T) {
+	t.Parallel()
+
+	exact, err := NewVectorIndex("", "")
+	require.NoError(t, err)
+	assert.Equal(t, VectorIndexExact, exact.Name())
+
+	ivf, err := NewVectorIndex(VectorIndexIVF, "")
+	require.NoError(t, err)
+	assert.Equal(t, VectorIndexIVF, ivf.Name())
+
+	_, err = NewVectorIndex("hnsw", "")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown vector index backend")
+}
+
+func TestOpenVectorIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults to exact for new database", func(t *testing.T) {
+		t.Parallel()
+		db := NewTestDB(t)
+
+		index, err := OpenVectorIndex(db)
+		require.NoError(t, err)
+		assert.Equal(t, VectorIndexExact, index.Name())
+	})
+
+	t.Run("defaults to exact without cache_metadata", func(t *testing.T) {
+		t.Parallel()
+		db := openVectorTestDB(t)
+		defer db.Close()
+
+		index, err := OpenVectorIndex(db)
+		require.NoError(t, err)
+		assert.Equal(t, VectorIndexExact, index.Name())
+	})
+}
+
+func TestConfigureVectorIndex(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rebuilds ivf index from existing chunks", func(t *testing.T) {
+		t.Parallel()
+		writer, cleanup := setupTestWriter(t)
+		defer cleanup()
+
+		chunks := makeClusteredChunks(200, 4, 384, 1)
+		require.NoError(t, writer.WriteChunks(chunks))
+
+		index, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+		require.NoError(t, err)
+		assert.Equal(t, VectorIndexIVF, index.Name())
+
+		var count int
+		require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count))
+		assert.Equal(t, 200, count)
+
+		opened, err := OpenVectorIndex(writer.db)
+		require.NoError(t, err)
+		assert.Equal(t, VectorIndexIVF, opened.Name())
+	})
+
+	t.Run("switching back to exact repopulates chunks_vec", func(t *testing.T) {
+		t.Parallel()
+		writer, cleanup := setupTestWriter(t)
+		defer cleanup()
+
+		_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+		require.NoError(t, err)
+
+		// Written while ivf is active: chunks_vec is not maintained
+		chunks := makeClusteredChunks(50, 2, 384, 2)
+		require.NoError(t, writer.WriteChunks(chunks))
+
+		var vecCount int
+		require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec").Scan(&vecCount))
+		assert.Equal(t, 0, vecCount)
+
+		_, err = ConfigureVectorIndex(writer.db, VectorIndexExact, QuantizationNone)
+		require.NoError(t, err)
+
+		require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec").Scan(&vecCount))
+		assert.Equal(t, 50, vecCount)
+	})
+
+	t.Run("rejects unknown backend", func(t *testing.T) {
+		t.Parallel()
+		db := NewTestDB(t)
+
+		_, err := ConfigureVectorIndex(db, "annoy", QuantizationNone)
+		require.Error(t, err)
+	})
+}
+
+func TestIVFIndex_ChunkWriterSync(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+	require.NoError(t, err)
+
+	chunks := makeClusteredChunks(100, 4, 32, 3)
+	for i, c := range chunks {
+		c.FilePath = fmt.Sprintf("file%d.go", i%2)
+	}
+	require.NoError(t, writer.WriteChunks(chunks))
+
+	var count int
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count))
+	assert.Equal(t, 100, count)
+
+	// Incremental write replaces file0.go's 50 chunks with 1
+	replacement := makeClusteredChunks(1, 1, 32, 4)
+	replacement[0].ID = "replacement"
+	replacement[0].FilePath = "file0.go"
+	require.NoError(t, writer.WriteChunksIncremental(replacement))
+
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count))
+	assert.Equal(t, 51, count)
+
+	results, err := sharedIVFIndex.Search(writer.db, replacement[0].Embedding, 1)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "replacement", results[0].ChunkID)
+	assert.InDelta(t, 0.0, results[0].Distance, 1e-5)
+}
+
+func TestIVFIndex_Training(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+	require.NoError(t, err)
+
+	chunks := makeClusteredChunks(400, 8, 32, 5)
+	require.NoError(t, writer.WriteChunks(chunks))
+
+	// Untrained (shared index needs 4096 vectors): everything is unassigned
+	var unassigned int
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf WHERE list_id = -1").Scan(&unassigned))
+	assert.Equal(t, 400, unassigned)
+
+	index := NewIVFIndex()
+	index.MinTrainSize = 100
+	index.NumProbes = 2
+
+	tx, err := writer.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, index.Update(tx, chunks[:1]))
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf WHERE list_id = -1").Scan(&unassigned))
+	assert.Equal(t, 0, unassigned)
+
+	var lists int
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf_centroids").Scan(&lists))
+	assert.Equal(t, 20, lists) // sqrt(400)
+
+	trained, err := readMetadataValue(writer.db, ivfTrainedCountKey)
+	require.NoError(t, err)
+	assert.Equal(t, "400", trained)
+
+	t.Run("search matches exact on separated clusters", func(t *testing.T) {
+		exact := exactTopK(chunks)
+		for _, q := range chunks[:20] {
+			got, err := index.Search(writer.db, q.Embedding, 5)
+			require.NoError(t, err)
+			require.Len(t, got, 5)
+			assert.Equal(t, exact(q.Embedding, 1)[0], got[0].ChunkID)
+			for i := 1; i < len(got); i++ {
+				assert.LessOrEqual(t, got[i-1].Distance, got[i].Distance)
+			}
+		}
+	})
+
+	t.Run("does not retrain before growth factor", func(t *testing.T) {
+		tx, err := writer.db.Begin()
+		require.NoError(t, err)
+		require.NoError(t, index.Update(tx, chunks[1:2]))
+		require.NoError(t, tx.Commit())
+
+		trainedAgain, err := readMetadataValue(writer.db, ivfTrainedCountKey)
+		require.NoError(t, err)
+		assert.Equal(t, "400", trainedAgain)
+	})
+}
+
+func TestIVFIndex_DeleteAndClear(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteChunks(makeClusteredChunks(10, 1, 16, 6)))
+
+	index := NewIVFIndex()
+
+	tx, err := writer.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, index.Delete(tx, []string{"chunk-0", "chunk-1"}))
+	require.NoError(t, index.Delete(tx, nil))
+	require.NoError(t, tx.Commit())
+
+	var count int
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count))
+	assert.Equal(t, 8, count)
+
+	tx, err = writer.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, index.Clear(tx))
+	require.NoError(t, tx.Commit())
+
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_ivf").Scan(&count))
+	assert.Equal(t, 0, count)
+
+	version, err := readMetadataValue(writer.db, ivfVersionKey)
+	require.NoError(t, err)
+	assert.Empty(t, version)
+}
+
+func TestIVFIndex_KeepsCount(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+	require.NoError(t, err)
+	chunks := makeClusteredChunks(10, 1, 16, 7)
+	require.NoError(t, writer.WriteChunks(chunks))
+
+	storedCount := func() string {
+		value, err := readMetadataValue(writer.db, ivfCountKey)
+		require.NoError(t, err)
+		return value
+	}
+	assert.Equal(t, "10", storedCount())
+
+	index := NewIVFIndex()
+	tx, err := writer.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, index.Update(tx, chunks[:3])) // Existing entries are not counted again
+	require.NoError(t, index.Delete(tx, []string{"chunk-0", "chunk-1", "missing"}))
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, "8", storedCount())
+
+	// A stale count is corrected before training
+	index.MinTrainSize = 100
+	tx, err = writer.db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, writeMetadataValue(tx, ivfCountKey, "500"))
+	require.NoError(t, index.Update(tx, chunks[2:3]))
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, "8", storedCount())
+	trained, err := readMetadataValue(writer.db, ivfTrainedCountKey)
+	require.NoError(t, err)
+	assert.Empty(t, trained)
+}
+
+func TestChunkReader_SearchByEmbedding_IVF(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexIVF, QuantizationNone)
+	require.NoError(t, err)
+
+	chunks := makeClusteredChunks(30, 3, 384, 7)
+	require.NoError(t, writer.WriteChunks(chunks))
+
+	reader := NewChunkReaderWithDB(writer.db)
+	results, err := reader.SearchByEmbedding(chunks[7].Embedding, nil, 3)
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	assert.Equal(t, chunks[7].ID, results[0].ID)
+}
+
+// BenchmarkVectorIndex_RecallLatency compares the IVF backend against the exact
+// sqlite-vec backend (float32, int8 and binary) on clustered vectors.
+// Reports recall@10 for the approximate and quantized variants.
+//
+// Run with: go test -tags fts5 -run '^$' -bench VectorIndex_RecallLatency ./internal/storage
+func BenchmarkVectorIndex_RecallLatency(b *testing.B) {
+	const (
+		numVectors = 20000
+		numQueries = 50
+		k          = 10
+	)
+
+	chunks := makeClusteredChunks(numVectors, 200, 384, 42)
+	queries := makeClusteredChunks(numQueries, 200, 384, 43)
+	exactTop := exactTopK(chunks)
+
+	dbPath := fmt.Sprintf("%s/bench.db", b.TempDir())
+	writer, err := NewChunkWriter(dbPath)
+	require.NoError(b, err)
+	defer writer.Close()
+	_, err = writer.db.Exec("PRAGMA foreign_keys = OFF")
+	require.NoError(b, err)
+
+	require.NoError(b, writer.WriteChunks(chunks))
+
+	b.Run("exact", func(b *testing.B) {
+		index := &exactVectorIndex{}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := index.Search(writer.db, queries[i%numQueries].Embedding, k)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	ivf := NewIVFIndex()
+	require.NoError(b, ivf.Create(writer.db, 384))
+	ivf.MinTrainSize = 1
+	require.NoError(b, RebuildVectorIndex(writer.db, ivf))
+
+	b.Run("ivf", func(b *testing.B) {
+		var recall float64
+		for _, q := range queries {
+			recall += recallAt(exactTop(q.Embedding, k), mustSearch(b, ivf, writer.db, q.Embedding, k))
+		}
+
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_, err := ivf.Search(writer.db, queries[i%numQueries].Embedding, k)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(recall/numQueries, "recall@10")
+	})
+
+	for _, quantization := range []string{QuantizationInt8, QuantizationBinary} {
+		index := newQuantizedVectorIndex(quantization)
+		require.NoError(b, index.Create(writer.db, 384))
+		require.NoError(b, RebuildVectorIndex(writer.db, index))
+
+		b.Run("exact-"+quantization, func(b *testing.B) {
+			var recall float64
+			for _, q := range queries {
+				recall += recallAt(exactTop(q.Embedding, k), mustSearch(b, index, writer.db, q.Embedding, k))
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, err := index.Search(writer.db, queries[i%numQueries].Embedding, k)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(recall/numQueries, "recall@10")
+		})
+	}
+}
+
+// makeClusteredChunks generates unit-norm embeddings scattered around
+// numClusters random centers, which is how real code embeddings behave.
+func makeClusteredChunks(n, numClusters, dims int, seed int64) []*Chunk {
+	rng := rand.New(rand.NewSource(seed))
+	centerRng := rand.New(rand.NewSource(1000 + int64(numClusters)*int64(dims)))
+
+	centers := make([][]float32, numClusters)
+	for i := range centers {
+		centers[i] = make([]float32, dims)
+		for d := range centers[i] {
+			centers[i][d] = float32(centerRng.NormFloat64())
+		}
+		centers[i] = normalizeVector(centers[i])
+	}
+
+	now := time.Now().UTC()
+	chunks := make([]*Chunk, n)
+	for i := range chunks {
+		center := centers[i%numClusters]
+		emb := make([]float32, dims)
+		for d := range emb {
+			emb[d] = center[d] + float32(rng.NormFloat64())*0.05
+		}
+		chunks[i] = &Chunk{
+			ID:        fmt.Sprintf("chunk-%d", i),
+			FilePath:  "file.go",
+			ChunkType: "symbols",
+			Title:     "Chunk",
+			Text:      "Content",
+			Embedding: normalizeVector(emb),
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	return chunks
+}
+
+// exactTopK returns a brute-force in-memory top-k function used as ground truth.
+func exactTopK(chunks []*Chunk) func(query []float32, k int) []string {
+	return func(query []float32, k int) []string {
+		q := normalizeVector(query)
+		top := newTopK(k)
+		for _, c := range chunks {
+			top.offer(c.ID, 1-float64(dot(q, normalizeVector(c.Embedding))))
+		}
+		ids := make([]string, 0, k)
+		for _, r := range top.results() {
+			ids = append(ids, r.ChunkID)
+		}
+		return ids
+	}
+}
+
+func mustSearch(b *testing.B, index VectorIndex, db *sql.DB, query []float32, k int) []*VectorSearchResult {
+	b.Helper()
+	results, err := index.Search(db, query, k)
+	require.NoError(b, err)
+	return results
+}
+
+func recallAt(truth []string, got []*VectorSearchResult) float64 {
+	if len(truth) == 0 {
+		return 1
+	}
+	want := make(map[string]bool, len(truth))
+	for _, id := range truth {
+		want[id] = true
+	}
+	hits := 0
+	for _, r := range got {
+		if want[r.ChunkID] {
+			hits++
+		}
+	}
+	return float64(hits) / float64(len(truth))
+}
@@ -0,0 +1,219 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	sqlite_vec "github.com/asg017/sqlite-vec-go-bindings/cgo"
+)
+
+// Quantized exact backend.
+//
+// Vectors are stored in a vec0 table as int8 (vec_quantize_int8, 'unit' scale)
+// or 1-bit (vec_quantize_binary) values instead of float32, so the only
+// full-precision copy of each embedding is chunks.embedding. Search runs a
+// brute-force KNN over the quantized table for RescoreFactor * limit
+// candidates, then rescores those candidates with exact cosine distance
+// against chunks.embedding.
+//
+// Size per 384-dim vector: float32 1536 bytes, int8 384 bytes, binary 48 bytes.
+
+// Default number of first-pass candidates per requested result.
+const (
+	DefaultInt8RescoreFactor   = 4
+	DefaultBinaryRescoreFactor = 10
+)
+
+// quantizedVectorIndex is the exact backend with quantized first-pass KNN.
+type quantizedVectorIndex struct {
+	quantization  string // QuantizationInt8 or QuantizationBinary
+	rescoreFactor int
+}
+
+func newQuantizedVectorIndex(quantization string) *quantizedVectorIndex {
+	factor := DefaultInt8RescoreFactor
+	if quantization == QuantizationBinary {
+		factor = DefaultBinaryRescoreFactor
+	}
+	return &quantizedVectorIndex{quantization: quantization, rescoreFactor: factor}
+}
+
+func (q *quantizedVectorIndex) Name() string { return VectorIndexExact }
+
+// table returns the vec0 table holding this quantization's vectors.
+func (q *quantizedVectorIndex) table() string {
+	if q.quantization == QuantizationBinary {
+		return "chunks_vec_bit"
+	}
+	return "chunks_vec_int8"
+}
+
+// quantizeExpr returns the SQL expression converting a float32 blob parameter
+// into the stored representation.
+func (q *quantizedVectorIndex) quantizeExpr() string {
+	if q.quantization == QuantizationBinary {
+		return "vec_quantize_binary(?)"
+	}
+	return "vec_quantize_int8(?, 'unit')"
+}
+
+// Create creates the quantized vec0 table.
+// Binary vectors require dimensions divisible by 8.
+func (q *quantizedVectorIndex) Create(db *sql.DB, dimensions int) error {
+	column := fmt.Sprintf("int8[%d] distance_metric=cosine", dimensions)
+	if q.quantization == QuantizationBinary {
+		if dimensions%8 != 0 {
+			return fmt.Errorf("binary quantization requires dimensions divisible by 8, got %d", dimensions)
+		}
+		column = fmt.Sprintf("bit[%d]", dimensions)
+	}
+
+	createSQL := fmt.Sprintf(`
+		CREATE VIRTUAL TABLE IF NOT EXISTS %s USING vec0(
+			chunk_id TEXT PRIMARY KEY,
+			embedding %s
+		)
+	`, q.table(), column)
+
+	if _, err := db.Exec(createSQL); err != nil {
+		return fmt.Errorf("failed to create quantized vector index: %w", err)
+	}
+
+	return nil
+}
+
+// Update quantizes and upserts vectors (delete then insert, as vec0 has no REPLACE).
+func (q *quantizedVectorIndex) Update(tx *sql.Tx, chunks []*Chunk) error {
+	if len(chunks) == 0 {
+		return nil
+	}
+
+	deleteStmt, err := tx.Prepare(fmt.Sprintf("DELETE FROM %s WHERE chunk_id = ?", q.table()))
+	if err != nil {
+		return fmt.Errorf("failed to prepare vector delete statement: %w", err)
+	}
+	defer deleteStmt.Close()
+
+	insertStmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (chunk_id, embedding) VALUES (?, %s)", q.table(), q.quantizeExpr()))
+	if err != nil {
+		return fmt.Errorf("failed to prepare vector insert statement: %w", err)
+	}
+	defer insertStmt.Close()
+
+	for _, chunk := range chunks {
+		if _, err := deleteStmt.Exec(chunk.ID); err != nil {
+			return fmt.Errorf("failed to delete vector for chunk %s: %w", chunk.ID, err)
+		}
+
+		embBytes, err := sqlite_vec.SerializeFloat32(chunk.Embedding)
+		if err != nil {
+			return fmt.Errorf("failed to serialize embedding for chunk %s: %w", chunk.ID, err)
+		}
+
+		if _, err := insertStmt.Exec(chunk.ID, embBytes); err != nil {
+			return fmt.Errorf("failed to insert vector for chunk %s: %w", chunk.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes vectors for the given chunk IDs.
+func (q *quantizedVectorIndex) Delete(tx *sql.Tx, chunkIDs []string) error {
+	if len(chunkIDs) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(fmt.Sprintf("DELETE FROM %s WHERE chunk_id = ?", q.table()))
+	if err != nil {
+		return fmt.Errorf("failed to prepare delete statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, id := range chunkIDs {
+		if _, err := stmt.Exec(id); err != nil {
+			return fmt.Errorf("failed to delete vector for chunk %s: %w", id, err)
+		}
+	}
+
+	return nil
+}
+
+// Clear removes all quantized vectors.
+func (q *quantizedVectorIndex) Clear(tx *sql.Tx) error {
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s", q.table())); err != nil {
+		return fmt.Errorf("failed to clear quantized vector index: %w", err)
+	}
+	return nil
+}
+
+// Search runs the quantized first pass, then rescores candidates against the
+// full-precision embeddings in chunks.embedding.
+func (q *quantizedVectorIndex) Search(db sq.BaseRunner, queryEmb []float32, limit int) ([]*VectorSearchResult, error) {
+	if limit <= 0 {
+		return []*VectorSearchResult{}, nil
+	}
+
+	queryBytes, err := sqlite_vec.SerializeFloat32(queryEmb)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize query embedding: %w", err)
+	}
+
+	// First pass: KNN over quantized vectors
+	firstPass := fmt.Sprintf(`
+		SELECT chunk_id
+		FROM %s
+		WHERE embedding MATCH %s AND k = ?
+	`, q.table(), q.quantizeExpr())
+
+	rows, err := db.Query(firstPass, queryBytes, limit*q.rescoreFactor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query quantized vector index: %w", err)
+	}
+	var candidates []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan quantized result: %w", err)
+		}
+		candidates = append(candidates, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating quantized results: %w", err)
+	}
+	if len(candidates) == 0 {
+		return []*VectorSearchResult{}, nil
+	}
+
+	// Second pass: rescore with full-precision embeddings
+	rescoreRows, err := sq.Select("chunk_id", "embedding").
+		From("chunks").
+		Where(sq.Eq{"chunk_id": candidates}).
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embeddings for rescoring: %w", err)
+	}
+	defer rescoreRows.Close()
+
+	query := normalizeVector(queryEmb)
+	top := newTopK(limit)
+	for rescoreRows.Next() {
+		var (
+			id       string
+			embBytes []byte
+		)
+		if err := rescoreRows.Scan(&id, &embBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan embedding for rescoring: %w", err)
+		}
+		top.offer(id, cosineDistanceBytes(query, embBytes))
+	}
+	if err := rescoreRows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rescoring embeddings: %w", err)
+	}
+
+	return top.results(), nil
+}
@@ -0,0 +1,222 @@
+package storage
+
+// Test Plan for Quantized Vector Storage:
+// - NewVectorIndex resolves int8/binary for exact and rejects them for ivf
+// - ConfigureVectorIndex moves vectors into the quantized table and empties chunks_vec
+// - ChunkWriter keeps the quantized table in sync on full and incremental writes
+// - Int8 and binary search return the exact nearest neighbor after rescoring
+// - Rescored distances match full-precision cosine distance
+// - Binary quantization rejects dimensions not divisible by 8
+// - GetVectorIndexStats reports backend, quantization and count from the active table
+// - MeasureVectorRecall returns 1 for exact search and for empty databases
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewVectorIndex_Quantization(t *testing.T) {
+	t.Parallel()
+
+	for _, quantization := range []string{QuantizationInt8, QuantizationBinary} {
+		index, err := NewVectorIndex(VectorIndexExact, quantization)
+		require.NoError(t, err)
+		assert.Equal(t, VectorIndexExact, index.Name())
+		assert.Equal(t, quantization, VectorQuantization(index))
+		assert.False(t, SupportsSQLKNN(index))
+	}
+
+	exact, err := NewVectorIndex(VectorIndexExact, QuantizationNone)
+	require.NoError(t, err)
+	assert.Equal(t, QuantizationNone, VectorQuantization(exact))
+	assert.True(t, SupportsSQLKNN(exact))
+
+	_, err = NewVectorIndex(VectorIndexIVF, QuantizationInt8)
+	require.Error(t, err)
+
+	_, err = NewVectorIndex(VectorIndexExact, "fp16")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown vector quantization")
+}
+
+func TestConfigureVectorIndex_Quantized(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		quantization string
+		table        string
+	}{
+		{QuantizationInt8, "chunks_vec_int8"},
+		{QuantizationBinary, "chunks_vec_bit"},
+	} {
+		tc := tc
+		t.Run(tc.quantization, func(t *testing.T) {
+			t.Parallel()
+			writer, cleanup := setupTestWriter(t)
+			defer cleanup()
+
+			chunks := makeClusteredChunks(100, 4, 384, 11)
+			require.NoError(t, writer.WriteChunks(chunks))
+
+			_, err := ConfigureVectorIndex(writer.db, VectorIndexExact, tc.quantization)
+			require.NoError(t, err)
+
+			var count int
+			require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM "+tc.table).Scan(&count))
+			assert.Equal(t, 100, count)
+
+			// Float copy is dropped: chunks.embedding is the only full-precision store
+			require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec").Scan(&count))
+			assert.Equal(t, 0, count)
+
+			opened, err := OpenVectorIndex(writer.db)
+			require.NoError(t, err)
+			assert.Equal(t, tc.quantization, VectorQuantization(opened))
+
+			stats, err := GetVectorIndexStats(writer.db)
+			require.NoError(t, err)
+			assert.Equal(t, VectorIndexExact, stats.Backend)
+			assert.Equal(t, tc.quantization, stats.Quantization)
+			assert.Equal(t, 100, stats.TotalVectors)
+
+			// Switching back restores float vectors and empties the quantized table
+			_, err = ConfigureVectorIndex(writer.db, VectorIndexExact, QuantizationNone)
+			require.NoError(t, err)
+			require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec").Scan(&count))
+			assert.Equal(t, 100, count)
+			require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM "+tc.table).Scan(&count))
+			assert.Equal(t, 0, count)
+		})
+	}
+}
+
+func TestQuantizedVectorIndex_ChunkWriterSync(t *testing.T) {
+	t.Parallel()
+
+	writer, cleanup := setupTestWriter(t)
+	defer cleanup()
+
+	_, err := ConfigureVectorIndex(writer.db, VectorIndexExact, QuantizationInt8)
+	require.NoError(t, err)
+
+	chunks := makeClusteredChunks(40, 4, 384, 12)
+	for i, c := range chunks {
+		c.FilePath = []string{"a.go", "b.go"}[i%2]
+	}
+	require.NoError(t, writer.WriteChunks(chunks))
+
+	var count int
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec_int8").Scan(&count))
+	assert.Equal(t, 40, count)
+
+	// Incremental rewrite of a.go with fewer chunks
+	var updated []*Chunk
+	for _, c := range chunks {
+		if c.FilePath == "a.go" && len(updated) < 5 {
+			updated = append(updated, c)
+		}
+	}
+	require.NoError(t, writer.WriteChunksIncremental(updated))
+
+	require.NoError(t, writer.db.QueryRow("SELECT COUNT(*) FROM chunks_vec_int8").Scan(&count))
+	assert.Equal(t, 25, count)
+}
+
+func TestQuantizedVectorIndex_Search(t *testing.T) {
+	t.Parallel()
+
+	for _, quantization := range []string{QuantizationInt8, QuantizationBinary} {
+		quantization := quantization
+		t.Run(quantization, func(t *testing.T) {
+			t.Parallel()
+			writer, cleanup := setupTestWriter(t)
+			defer cleanup()
+
+			_, err := ConfigureVectorIndex(writer.db, VectorIndexExact, quantization)
+			require.NoError(t, err)
+
+			chunks := makeClusteredChunks(200, 8, 384, 13)
+			require.NoError(t, writer.WriteChunks(chunks))
+
+			index, err := OpenVectorIndex(writer.db)
+			require.NoError(t, err)
+
+			results, err := index.Search(writer.db, chunks[17].Embedding, 5)
+			require.NoError(t, err)
+			require.Len(t, results, 5)
+			assert.Equal(t, chunks[17].ID, results[0].ChunkID)
+			assert.InDelta(t, 0, results[0].Distance, 1e-5, "rescoring should use full-precision distance")
+
+			for i := 1; i < len(results); i++ {
+				assert.LessOrEqual(t, results[i-1].Distance, results[i].Distance)
+			}
+
+			// Reader path goes through the quantized index as well
+			reader := NewChunkReaderWithDB(writer.db)
+			found, err := reader.SearchByEmbedding(chunks[17].Embedding, nil, 3)
+			require.NoError(t, err)
+			require.Len(t, found, 3)
+			assert.Equal(t, chunks[17].ID, found[0].ID)
+		})
+	}
+}
+
+func TestQuantizedVectorIndex_BinaryRequiresByteAlignedDims(t *testing.T) {
+	t.Parallel()
+	db := NewTestDB(t)
+
+	index := newQuantizedVectorIndex(QuantizationBinary)
+	err := index.Create(db, 100)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "divisible by 8")
+}
+
+func TestMeasureVectorRecall(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty database", func(t *testing.T) {
+		t.Parallel()
+		writer, cleanup := setupTestWriter(t)
+		defer cleanup()
+
+		recall, err := MeasureVectorRecall(writer.db, 10, 10)
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, recall)
+	})
+
+	t.Run("exact backend has full recall", func(t *testing.T) {
+		t.Parallel()
+		writer, cleanup := setupTestWriter(t)
+		defer cleanup()
+
+		require.NoError(t, writer.WriteChunks(makeClusteredChunks(100, 4, 384, 14)))
+
+		recall, err := MeasureVectorRecall(writer.db, 10, 5)
+		require.NoError(t, err)
+		assert.InDelta(t, 1.0, recall, 1e-9)
+	})
+
+	t.Run("int8 backend recall", func(t *testing.T) {
+		t.Parallel()
+		writer, cleanup := setupTestWriter(t)
+		defer cleanup()
+
+		require.NoError(t, writer.WriteChunks(makeClusteredChunks(300, 10, 384, 15)))
+		_, err := ConfigureVectorIndex(writer.db, VectorIndexExact, QuantizationInt8)
+		require.NoError(t, err)
+
+		recall, err := MeasureVectorRecall(writer.db, 20, 10)
+		require.NoError(t, err)
+		assert.Greater(t, recall, 0.8)
+	})
+
+	t.Run("rejects invalid arguments", func(t *testing.T) {
+		t.Parallel()
+		db := NewTestDB(t)
+
+		_, err := MeasureVectorRecall(db, 0, 10)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// MeasureVectorRecall estimates recall@k of the active vector index.
+//
+// Samples random stored chunks as queries, computes ground truth with an exact
+// cosine scan over chunks.embedding (one pass for all queries), and returns the
+// mean fraction of true neighbors the active index returned.
+// Returns 1 for empty databases. Cost is one full table scan plus samples searches.
+func MeasureVectorRecall(db *sql.DB, samples, k int) (float64, error) {
+	if samples <= 0 || k <= 0 {
+		return 0, fmt.Errorf("samples and k must be positive")
+	}
+
+	index, err := OpenVectorIndex(db)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open vector index: %w", err)
+	}
+
+	rows, err := db.Query("SELECT embedding FROM chunks ORDER BY random() LIMIT ?", samples)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sample queries: %w", err)
+	}
+	var queries [][]float32
+	for rows.Next() {
+		var embBytes []byte
+		if err := rows.Scan(&embBytes); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan sample: %w", err)
+		}
+		emb, err := DeserializeEmbedding(embBytes)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to deserialize sample: %w", err)
+		}
+		if len(emb) > 0 {
+			queries = append(queries, normalizeVector(emb))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating samples: %w", err)
+	}
+	if len(queries) == 0 {
+		return 1, nil
+	}
+
+	// Ground truth: single scan, all queries evaluated per row
+	truth := make([]*topK, len(queries))
+	for i := range truth {
+		truth[i] = newTopK(k)
+	}
+	rows, err = db.Query("SELECT chunk_id, embedding FROM chunks")
+	if err != nil {
+		return 0, fmt.Errorf("failed to scan chunks: %w", err)
+	}
+	for rows.Next() {
+		var (
+			id       string
+			embBytes []byte
+		)
+		if err := rows.Scan(&id, &embBytes); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		for i, q := range queries {
+			truth[i].offer(id, cosineDistanceBytes(q, embBytes))
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("error iterating chunks: %w", err)
+	}
+
+	var total float64
+	for i, q := range queries {
+		want := make(map[string]bool, k)
+		for _, r := range truth[i].results() {
+			want[r.ChunkID] = true
+		}
+
+		got, err := index.Search(db, q, k)
+		if err != nil {
+			return 0, fmt.Errorf("vector search failed: %w", err)
+		}
+
+		hits := 0
+		for _, r := range got {
+			if want[r.ChunkID] {
+				hits++
+			}
+		}
+		total += float64(hits) / float64(len(want))
+	}
+
+	return total / float64(len(queries)), nil
+}
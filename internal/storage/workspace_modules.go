@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// WorkspaceModule is a workspace member detected from its manifest: a Go
+// module, npm package, Python project or Rust crate.
+type WorkspaceModule struct {
+	Name         string
+	Kind         string // go, npm, python, cargo
+	RootPath     string // Directory relative to the project root ("." for the root)
+	ManifestPath string
+}
+
+// IndexedImport is an import row to resolve.
+type IndexedImport struct {
+	ImportID   string
+	FilePath   string
+	ImportPath string
+}
+
+// ImportResolution records where an import points. Resolving against the
+// workspace replaces the imports.is_external/is_standard_lib guesses.
+type ImportResolution struct {
+	ImportID     string
+	Resolution   string // internal, external, stdlib, unresolved
+	ResolvedPath string // Internal: imported file (or Go package directory)
+	ModuleName   string // Workspace module of the imported file
+	PackageName  string // External and stdlib: package name
+}
+
+// HeaderImplementation pairs a C/C++ header with the source file
+// implementing it.
+type HeaderImplementation struct {
+	HeaderPath string
+	SourcePath string
+}
+
+// Dependency is a third-party package from the dependency inventory.
+type Dependency struct {
+	Ecosystem    string // go, npm, python, cargo, maven, rubygems
+	Name         string
+	ImportName   string // Matches import_resolutions.package_name
+	Version      string
+	Constraint   string
+	Scope        string // runtime, dev, peer, optional, build, provided
+	Direct       bool
+	ManifestPath string
+	ModuleName   string
+}
+
+// ReplaceWorkspaceModules stores the detected modules and reports whether
+// they differ from the stored ones, in which case every file's module and
+// import resolution should be recomputed.
+func ReplaceWorkspaceModules(tx *sql.Tx, modules []WorkspaceModule) (bool, error) {
+	stored, err := ListWorkspaceModules(tx)
+	if err != nil {
+		return false, err
+	}
+	if sameModules(stored, modules) {
+		return false, nil
+	}
+
+	if _, err := tx.Exec("DELETE FROM workspace_modules"); err != nil {
+		return false, fmt.Errorf("failed to clear workspace modules: %w", err)
+	}
+	for _, m := range modules {
+		_, err := sq.Insert("workspace_modules").
+			Columns("module_name", "kind", "root_path", "manifest_path").
+			Values(m.Name, m.Kind, m.RootPath, m.ManifestPath).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return false, fmt.Errorf("failed to insert workspace module %s: %w", m.Name, err)
+		}
+	}
+	return true, nil
+}
+
+// ListWorkspaceModules returns the stored modules ordered by root path.
+func ListWorkspaceModules(db sq.BaseRunner) ([]WorkspaceModule, error) {
+	rows, err := sq.Select("module_name", "kind", "root_path", "manifest_path").
+		From("workspace_modules").
+		OrderBy("root_path", "kind").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query workspace modules: %w", err)
+	}
+	defer rows.Close()
+
+	var modules []WorkspaceModule
+	for rows.Next() {
+		var m WorkspaceModule
+		if err := rows.Scan(&m.Name, &m.Kind, &m.RootPath, &m.ManifestPath); err != nil {
+			return nil, fmt.Errorf("failed to scan workspace module: %w", err)
+		}
+		modules = append(modules, m)
+	}
+	return modules, rows.Err()
+}
+
+// sameModules compares module sets regardless of order.
+func sameModules(a, b []WorkspaceModule) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[WorkspaceModule]bool, len(a))
+	for _, m := range a {
+		set[m] = true
+	}
+	for _, m := range b {
+		if !set[m] {
+			return false
+		}
+	}
+	return true
+}
+
+// AssignFileModules sets the module of each file; an empty module name
+// clears it (the file is outside every module). Files must be indexed.
+func AssignFileModules(tx *sql.Tx, assignments map[string]string) error {
+	for file, module := range assignments {
+		_, err := sq.Delete("file_modules").
+			Where(sq.Eq{"file_path": file}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to clear module of %s: %w", file, err)
+		}
+		if module == "" {
+			continue
+		}
+		_, err = sq.Insert("file_modules").
+			Columns("file_path", "module_name").
+			Values(file, module).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to assign module of %s: %w", file, err)
+		}
+	}
+	return nil
+}
+
+// IndexedFilePaths returns every indexed file path.
+func IndexedFilePaths(db sq.BaseRunner) ([]string, error) {
+	rows, err := sq.Select("file_path").
+		From("files").
+		OrderBy("file_path").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file paths: %w", err)
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var file string
+		if err := rows.Scan(&file); err != nil {
+			return nil, fmt.Errorf("failed to scan file path: %w", err)
+		}
+		files = append(files, file)
+	}
+	return files, rows.Err()
+}
+
+// ListImports returns the imports of files, or every import if files is nil.
+func ListImports(db sq.BaseRunner, files []string) ([]IndexedImport, error) {
+	query := sq.Select("import_id", "file_path", "import_path").
+		From("imports").
+		OrderBy("import_id")
+	if files != nil {
+		query = query.Where(sq.Eq{"file_path": files})
+	}
+
+	rows, err := query.RunWith(db).Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query imports: %w", err)
+	}
+	defer rows.Close()
+
+	var imports []IndexedImport
+	for rows.Next() {
+		var imp IndexedImport
+		if err := rows.Scan(&imp.ImportID, &imp.FilePath, &imp.ImportPath); err != nil {
+			return nil, fmt.Errorf("failed to scan import: %w", err)
+		}
+		imports = append(imports, imp)
+	}
+	return imports, rows.Err()
+}
+
+// ReplaceImportResolutions records resolutions for indexed imports and sets
+// their is_external and is_standard_lib flags to match. Deleting an import
+// deletes its resolution.
+func ReplaceImportResolutions(tx *sql.Tx, resolutions []ImportResolution) error {
+	if len(resolutions) == 0 {
+		return nil
+	}
+	for _, r := range resolutions {
+		_, err := sq.Insert("import_resolutions").
+			Options("OR REPLACE").
+			Columns("import_id", "resolution", "resolved_path", "module_name", "package_name").
+			Values(r.ImportID, r.Resolution, nullIfEmpty(r.ResolvedPath), nullIfEmpty(r.ModuleName), nullIfEmpty(r.PackageName)).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert import resolution %s: %w", r.ImportID, err)
+		}
+
+		_, err = sq.Update("imports").
+			Set("is_external", boolToInt(r.Resolution == "external")).
+			Set("is_standard_lib", boolToInt(r.Resolution == "stdlib")).
+			Where(sq.Eq{"import_id": r.ImportID}).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to update import %s: %w", r.ImportID, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceHeaderImplementations replaces the header/implementation pairs.
+// Both files must be indexed.
+func ReplaceHeaderImplementations(tx *sql.Tx, pairs []HeaderImplementation) error {
+	if _, err := tx.Exec("DELETE FROM header_implementations"); err != nil {
+		return fmt.Errorf("failed to clear header implementations: %w", err)
+	}
+	for _, p := range pairs {
+		_, err := sq.Insert("header_implementations").
+			Columns("header_path", "source_path").
+			Values(p.HeaderPath, p.SourcePath).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert header implementation %s: %w", p.HeaderPath, err)
+		}
+	}
+	return nil
+}
+
+// ReplaceDependencies replaces the dependency inventory.
+func ReplaceDependencies(tx *sql.Tx, deps []Dependency) error {
+	if _, err := tx.Exec("DELETE FROM dependencies"); err != nil {
+		return fmt.Errorf("failed to clear dependencies: %w", err)
+	}
+	for _, d := range deps {
+		_, err := sq.Insert("dependencies").
+			Options("OR IGNORE").
+			Columns("ecosystem", "name", "import_name", "version", "version_constraint", "scope", "direct", "manifest_path", "module_name").
+			Values(d.Ecosystem, d.Name, d.ImportName, d.Version, nullIfEmpty(d.Constraint), d.Scope, boolToInt(d.Direct), d.ManifestPath, nullIfEmpty(d.ModuleName)).
+			RunWith(tx).
+			Exec()
+		if err != nil {
+			return fmt.Errorf("failed to insert dependency %s: %w", d.Name, err)
+		}
+	}
+	return nil
+}
+
+// ListDependencies returns the dependency inventory ordered by manifest and
+// name.
+func ListDependencies(db sq.BaseRunner) ([]Dependency, error) {
+	rows, err := sq.Select("ecosystem", "name", "import_name", "version", "COALESCE(version_constraint, '')",
+		"scope", "direct", "manifest_path", "COALESCE(module_name, '')").
+		From("dependencies").
+		OrderBy("manifest_path", "name", "version").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	var deps []Dependency
+	for rows.Next() {
+		var d Dependency
+		if err := rows.Scan(&d.Ecosystem, &d.Name, &d.ImportName, &d.Version, &d.Constraint,
+			&d.Scope, &d.Direct, &d.ManifestPath, &d.ModuleName); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency: %w", err)
+		}
+		deps = append(deps, d)
+	}
+	return deps, rows.Err()
+}
+
+// DependencyImport is an import path of a dependency the project's code
+// imports (a row of the dependency_usages view).
+type DependencyImport struct {
+	Ecosystem    string
+	Name         string
+	Version      string
+	ImportPath   string
+	ManifestPath string
+}
+
+// ListDependencyImports returns the distinct dependency imports, ordered by
+// ecosystem, dependency name and import path.
+func ListDependencyImports(db sq.BaseRunner) ([]DependencyImport, error) {
+	rows, err := sq.Select("ecosystem", "dependency_name", "version", "import_path", "manifest_path").
+		Distinct().
+		From("dependency_usages").
+		OrderBy("ecosystem", "dependency_name", "import_path", "manifest_path").
+		RunWith(db).
+		Query()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dependency usages: %w", err)
+	}
+	defer rows.Close()
+
+	var imports []DependencyImport
+	for rows.Next() {
+		var imp DependencyImport
+		if err := rows.Scan(&imp.Ecosystem, &imp.Name, &imp.Version, &imp.ImportPath, &imp.ManifestPath); err != nil {
+			return nil, fmt.Errorf("failed to scan dependency usage: %w", err)
+		}
+		imports = append(imports, imp)
+	}
+	return imports, rows.Err()
+}
+
+// nullIfEmpty maps "" to NULL.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,207 @@
+package storage
+
+// Test Plan for Workspace Modules:
+// - ReplaceWorkspaceModules reports whether the module set changed
+// - AssignFileModules sets and clears file modules; deleting a file deletes its assignment
+// - ListImports lists every import or those of the given files
+// - ReplaceImportResolutions records resolutions and updates the imports' flags
+// - Deleting an import deletes its resolution
+// - Reads before any workspace was stored return nothing
+// - ReplaceDependencies replaces the inventory; ListDependencies reads it back
+// - dependency_usages joins external imports to the dependency their module declares
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func insertWorkspaceTestImport(t *testing.T, db *sql.DB, file, importPath string) {
+	t.Helper()
+	_, err := db.Exec(`INSERT OR IGNORE INTO files (file_path, language, module_path, is_test, file_hash, last_modified, indexed_at)
+		VALUES (?, 'typescript', '.', 0, 'h', '2025-01-01T00:00:00Z', '2025-01-01T00:00:00Z')`, file)
+	require.NoError(t, err)
+	_, err = db.Exec(`INSERT INTO imports (import_id, file_path, import_path, is_external, import_line)
+		VALUES (?, ?, ?, 1, 1)`, file+"::"+importPath, file, importPath)
+	require.NoError(t, err)
+}
+
+func TestReplaceWorkspaceModules(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	modules, err := ListWorkspaceModules(db)
+	require.NoError(t, err)
+	assert.Empty(t, modules)
+
+	replace := func(modules []WorkspaceModule) bool {
+		var changed bool
+		require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+			var err error
+			changed, err = ReplaceWorkspaceModules(tx, modules)
+			return err
+		}))
+		return changed
+	}
+
+	web := WorkspaceModule{Name: "web", Kind: "npm", RootPath: "web", ManifestPath: "web/package.json"}
+	api := WorkspaceModule{Name: "example.com/api", Kind: "go", RootPath: "api", ManifestPath: "api/go.mod"}
+	assert.True(t, replace([]WorkspaceModule{web, api}))
+	assert.False(t, replace([]WorkspaceModule{api, web}))
+
+	modules, err = ListWorkspaceModules(db)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkspaceModule{api, web}, modules)
+
+	assert.True(t, replace([]WorkspaceModule{api}))
+	modules, err = ListWorkspaceModules(db)
+	require.NoError(t, err)
+	assert.Equal(t, []WorkspaceModule{api}, modules)
+}
+
+func TestAssignFileModules(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	insertWorkspaceTestImport(t, db, "web/app.ts", "react")
+	insertWorkspaceTestImport(t, db, "web/util.ts", "lodash")
+
+	assign := func(assignments map[string]string) {
+		require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+			return AssignFileModules(tx, assignments)
+		}))
+	}
+	moduleOf := func(file string) string {
+		var module string
+		err := db.QueryRow("SELECT module_name FROM file_modules WHERE file_path = ?", file).Scan(&module)
+		if err == sql.ErrNoRows {
+			return ""
+		}
+		require.NoError(t, err)
+		return module
+	}
+
+	assign(map[string]string{"web/app.ts": "web", "web/util.ts": "web"})
+	assert.Equal(t, "web", moduleOf("web/app.ts"))
+
+	assign(map[string]string{"web/app.ts": "admin", "web/util.ts": ""})
+	assert.Equal(t, "admin", moduleOf("web/app.ts"))
+	assert.Equal(t, "", moduleOf("web/util.ts"))
+
+	_, err := db.Exec("DELETE FROM files WHERE file_path = 'web/app.ts'")
+	require.NoError(t, err)
+	assert.Equal(t, "", moduleOf("web/app.ts"))
+}
+
+func TestReplaceImportResolutions(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	insertWorkspaceTestImport(t, db, "web/app.ts", "./util")
+	insertWorkspaceTestImport(t, db, "web/app.ts", "fs")
+	insertWorkspaceTestImport(t, db, "web/util.ts", "react")
+
+	imports, err := ListImports(db, nil)
+	require.NoError(t, err)
+	assert.Len(t, imports, 3)
+
+	imports, err = ListImports(db, []string{"web/util.ts"})
+	require.NoError(t, err)
+	assert.Equal(t, []IndexedImport{{ImportID: "web/util.ts::react", FilePath: "web/util.ts", ImportPath: "react"}}, imports)
+
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceImportResolutions(tx, []ImportResolution{
+			{ImportID: "web/app.ts::./util", Resolution: "internal", ResolvedPath: "web/util.ts", ModuleName: "web"},
+			{ImportID: "web/app.ts::fs", Resolution: "stdlib", PackageName: "fs"},
+			{ImportID: "web/util.ts::react", Resolution: "external", PackageName: "react"},
+		})
+	}))
+
+	flags := func(importID string) (bool, bool) {
+		var external, stdlib bool
+		require.NoError(t, db.QueryRow("SELECT is_external, is_standard_lib FROM imports WHERE import_id = ?", importID).Scan(&external, &stdlib))
+		return external, stdlib
+	}
+	external, stdlib := flags("web/app.ts::./util")
+	assert.False(t, external)
+	assert.False(t, stdlib)
+	external, stdlib = flags("web/app.ts::fs")
+	assert.False(t, external)
+	assert.True(t, stdlib)
+	external, _ = flags("web/util.ts::react")
+	assert.True(t, external)
+
+	var resolvedPath, module string
+	var pkg sql.NullString
+	require.NoError(t, db.QueryRow(`SELECT resolved_path, module_name, package_name FROM import_resolutions
+		WHERE import_id = 'web/app.ts::./util'`).Scan(&resolvedPath, &module, &pkg))
+	assert.Equal(t, "web/util.ts", resolvedPath)
+	assert.Equal(t, "web", module)
+	assert.False(t, pkg.Valid)
+
+	// Re-resolving replaces; deleting the import deletes its resolution
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceImportResolutions(tx, []ImportResolution{{ImportID: "web/app.ts::./util", Resolution: "unresolved"}})
+	}))
+	_, err = db.Exec("DELETE FROM imports WHERE file_path = 'web/util.ts'")
+	require.NoError(t, err)
+
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM import_resolutions").Scan(&count))
+	assert.Equal(t, 2, count)
+	var resolution string
+	require.NoError(t, db.QueryRow("SELECT resolution FROM import_resolutions WHERE import_id = 'web/app.ts::./util'").Scan(&resolution))
+	assert.Equal(t, "unresolved", resolution)
+}
+
+func TestReplaceDependencies(t *testing.T) {
+	t.Parallel()
+	db := NewTestDBFile(t)
+
+	deps, err := ListDependencies(db)
+	require.NoError(t, err)
+	assert.Empty(t, deps)
+
+	react := Dependency{Ecosystem: "npm", Name: "react", ImportName: "react", Version: "18.2.0", Constraint: "^18.2.0",
+		Scope: "runtime", Direct: true, ManifestPath: "web/package.json", ModuleName: "web"}
+	envify := Dependency{Ecosystem: "npm", Name: "loose-envify", ImportName: "loose-envify", Version: "1.4.0",
+		Scope: "runtime", ManifestPath: "web/package-lock.json", ModuleName: "web"}
+	vue := Dependency{Ecosystem: "npm", Name: "vue", ImportName: "vue", Constraint: "^3", Scope: "runtime", Direct: true,
+		ManifestPath: "admin/package.json"}
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceDependencies(tx, []Dependency{react, envify, vue})
+	}))
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		return ReplaceDependencies(tx, []Dependency{react, envify})
+	}))
+
+	deps, err = ListDependencies(db)
+	require.NoError(t, err)
+	assert.Equal(t, []Dependency{envify, react}, deps)
+
+	// Only imports from the declaring module and ecosystem use a dependency
+	insertWorkspaceTestImport(t, db, "web/app.ts", "react")
+	insertWorkspaceTestImport(t, db, "admin/app.ts", "react")
+	require.NoError(t, WithTx(db, func(tx *sql.Tx) error {
+		if err := AssignFileModules(tx, map[string]string{"web/app.ts": "web"}); err != nil {
+			return err
+		}
+		return ReplaceImportResolutions(tx, []ImportResolution{
+			{ImportID: "web/app.ts::react", Resolution: "external", PackageName: "react"},
+			{ImportID: "admin/app.ts::react", Resolution: "external", PackageName: "react"},
+		})
+	}))
+
+	var file, name, version string
+	var direct bool
+	require.NoError(t, db.QueryRow("SELECT file_path, dependency_name, version, direct FROM dependency_usages").Scan(&file, &name, &version, &direct))
+	assert.Equal(t, "web/app.ts", file)
+	assert.Equal(t, "react", name)
+	assert.Equal(t, "18.2.0", version)
+	assert.True(t, direct)
+	var count int
+	require.NoError(t, db.QueryRow("SELECT COUNT(*) FROM dependency_usages").Scan(&count))
+	assert.Equal(t, 1, count)
+}
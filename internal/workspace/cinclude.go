@@ -0,0 +1,273 @@
+package workspace
+
+import (
+	"encoding/json"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// compileCommand is an entry of a compile_commands.json compilation
+// database; the compiler command line is either a string or a list.
+type compileCommand struct {
+	Directory string   `json:"directory"`
+	File      string   `json:"file"`
+	Command   string   `json:"command"`
+	Arguments []string `json:"arguments"`
+}
+
+// includePaths are the include directories compilation databases declare,
+// relative to the workspace root.
+type includePaths struct {
+	files map[string][]string // Include directories of each compiled file, in search order
+	all   []string            // Every include directory, in first-seen order
+}
+
+// readCompileCommands reads the include directories (-I, -iquote, -isystem,
+// -idirafter and MSVC /I) of each entry of a compile_commands.json into
+// paths. Entries already read from another database keep their
+// directories; directories outside root (system and SDK headers) are
+// dropped.
+func readCompileCommands(p, root string, paths *includePaths) {
+	var commands []compileCommand
+	if err := json.Unmarshal(readFile(p), &commands); err != nil {
+		return
+	}
+	if paths.files == nil {
+		paths.files = make(map[string][]string)
+	}
+	seen := make(map[string]bool, len(paths.all))
+	for _, dir := range paths.all {
+		seen[dir] = true
+	}
+
+	for _, cmd := range commands {
+		dir := cmd.Directory
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(p), dir)
+		}
+		file, ok := relativeTo(root, dir, cmd.File)
+		if !ok || paths.files[file] != nil {
+			continue
+		}
+		args := cmd.Arguments
+		if len(args) == 0 {
+			args = splitCommand(cmd.Command)
+		}
+
+		dirs := []string{}
+		for _, include := range includeFlags(args) {
+			rel, ok := relativeTo(root, dir, include)
+			if !ok {
+				continue
+			}
+			dirs = append(dirs, rel)
+			if !seen[rel] {
+				seen[rel] = true
+				paths.all = append(paths.all, rel)
+			}
+		}
+		paths.files[file] = dirs
+	}
+}
+
+// includeFlags returns the include directories of a compiler command line,
+// in the order they are searched.
+func includeFlags(args []string) []string {
+	var dirs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		for _, flag := range []string{"-iquote", "-isystem", "-idirafter", "-I", "/I"} {
+			value, ok := strings.CutPrefix(arg, flag)
+			if !ok {
+				continue
+			}
+			if value == "" && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+			if value != "" {
+				dirs = append(dirs, value)
+			}
+			break
+		}
+	}
+	return dirs
+}
+
+// splitCommand splits a shell command line into arguments, honoring single
+// and double quotes and backslash escapes.
+func splitCommand(command string) []string {
+	var args []string
+	var current strings.Builder
+	inArg, quote := false, byte(0)
+	for i := 0; i < len(command); i++ {
+		c := command[i]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote == 0 && (c == '"' || c == '\''):
+			quote, inArg = c, true
+		case c == '\\' && quote != '\'' && i+1 < len(command):
+			i++
+			current.WriteByte(command[i])
+			inArg = true
+		case quote == 0 && (c == ' ' || c == '\t' || c == '\n'):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteByte(c)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// relativeTo returns p (absolute, or relative to dir) relative to root, and
+// false when it lies outside root.
+func relativeTo(root, dir, p string) (string, bool) {
+	if p == "" {
+		return "", false
+	}
+	if !filepath.IsAbs(p) {
+		p = filepath.Join(dir, p)
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// includeDirsFor returns the include directories searched for a file: those
+// of its compilation database entry, or for files without one (headers,
+// sources outside the build) every directory any entry declares.
+func (w *Workspace) includeDirsFor(relPath string) []string {
+	if dirs, ok := w.includes.files[relPath]; ok {
+		return dirs
+	}
+	return w.includes.all
+}
+
+// cSourceExtensions and cHeaderExtensions are the extensions of C and C++
+// implementation files and headers.
+var (
+	cSourceExtensions = []string{".c", ".cpp", ".cc", ".cxx"}
+	cHeaderExtensions = []string{".h", ".hpp", ".hh", ".hxx"}
+)
+
+// isCFile reports whether a file is a C or C++ source or header.
+func isCFile(relPath string) bool {
+	ext := strings.ToLower(path.Ext(relPath))
+	return hasExtension(cSourceExtensions, ext) || hasExtension(cHeaderExtensions, ext)
+}
+
+// IsCHeader reports whether a file is a C or C++ header.
+func IsCHeader(relPath string) bool {
+	return hasExtension(cHeaderExtensions, strings.ToLower(path.Ext(relPath)))
+}
+
+// hasExtension reports whether ext is one of exts.
+func hasExtension(exts []string, ext string) bool {
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveC resolves an #include. Quoted includes ("net/server.h") are
+// searched in the includer's directory, then like angle includes
+// (<vector>) in the include directories of compile_commands.json. Headers
+// not found there are standard library headers, or the indexed file whose
+// path ends with the include when exactly one does; otherwise angle
+// includes name an external library (by their first path element) and
+// quoted ones are unresolved.
+func (r *Resolver) resolveC(fromFile, spec string) Resolution {
+	system := strings.HasPrefix(spec, "<")
+	name := strings.TrimSuffix(strings.TrimPrefix(spec, "<"), ">")
+
+	if !system {
+		if file := path.Join(path.Dir(fromFile), name); r.files[file] {
+			return r.internal(file)
+		}
+	}
+	for _, dir := range r.ws.includeDirsFor(fromFile) {
+		if file := path.Join(dir, name); r.files[file] {
+			return r.internal(file)
+		}
+	}
+
+	if cStdlib[name] || strings.HasPrefix(name, "sys/") {
+		return Resolution{Kind: ResolutionStdlib, Package: name}
+	}
+	if matches := r.filesEndingWith(name); len(matches) == 1 {
+		return r.internal(matches[0])
+	}
+	if system {
+		first, _, _ := strings.Cut(name, "/")
+		return Resolution{Kind: ResolutionExternal, Package: strings.TrimSuffix(first, path.Ext(first))}
+	}
+	return Resolution{Kind: ResolutionUnresolved}
+}
+
+// filesEndingWith returns the C and C++ files whose path is name or ends
+// with "/"+name.
+func (r *Resolver) filesEndingWith(name string) []string {
+	var matches []string
+	for _, file := range r.cFiles()[path.Base(name)] {
+		if file == name || strings.HasSuffix(file, "/"+name) {
+			matches = append(matches, file)
+		}
+	}
+	return matches
+}
+
+// cFiles returns the indexed C and C++ files by base name.
+func (r *Resolver) cFiles() map[string][]string {
+	if r.cByBase == nil {
+		r.cByBase = make(map[string][]string)
+		for file := range r.files {
+			if isCFile(file) {
+				r.cByBase[path.Base(file)] = append(r.cByBase[path.Base(file)], file)
+			}
+		}
+	}
+	return r.cByBase
+}
+
+// Implementation returns the source file implementing a C or C++ header:
+// the one with the header's name and a source extension in its directory,
+// or failing that the only such file anywhere (e.g. include/net/server.h
+// and src/server.cpp). It returns "" for other files and headers without
+// an implementation.
+func (r *Resolver) Implementation(header string) string {
+	if !IsCHeader(header) {
+		return ""
+	}
+	stem := strings.TrimSuffix(header, path.Ext(header))
+	for _, ext := range cSourceExtensions {
+		if r.files[stem+ext] {
+			return stem + ext
+		}
+	}
+
+	var found string
+	base := path.Base(stem)
+	for _, ext := range cSourceExtensions {
+		for _, file := range r.cFiles()[base+ext] {
+			if found != "" {
+				return "" // Ambiguous
+			}
+			found = file
+		}
+	}
+	return found
+}
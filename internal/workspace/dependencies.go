@@ -0,0 +1,217 @@
+package workspace
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Ecosystems without workspace modules; the others are named by module kind.
+const (
+	EcosystemMaven    = "maven"
+	EcosystemRubyGems = "rubygems"
+)
+
+// Dependency scopes.
+const (
+	ScopeRuntime  = "runtime"
+	ScopeDev      = "dev"
+	ScopePeer     = "peer"
+	ScopeOptional = "optional"
+	ScopeBuild    = "build"
+	ScopeProvided = "provided"
+)
+
+// Dependency is a third-party package declared by a manifest or pinned by a
+// lockfile.
+type Dependency struct {
+	Ecosystem  string // go, npm, python, cargo, maven, rubygems
+	Name       string // Module path, package, distribution, crate, group:artifact or gem name
+	ImportName string // Package name imports of it resolve to (Resolution.Package)
+	Version    string // Locked version, or the declared one when it is exact
+	Constraint string // Declared version requirement; empty for transitive dependencies
+	Scope      string // runtime, dev, peer, optional, build, provided
+	Direct     bool   // Declared by a manifest (go.mod: not "// indirect")
+	Manifest   string // Manifest or lockfile it was read from, relative to Root
+	Module     string // Workspace module owning the manifest, if any
+}
+
+// dependencyFile is what one manifest or lockfile contributes: declared
+// dependencies and locked packages.
+type dependencyFile struct {
+	ecosystem string
+	declared  []Dependency
+	locked    []Dependency // Version and Scope only; Direct is false
+}
+
+// isDependencyFile reports whether a file name is a manifest or lockfile
+// the dependency inventory reads.
+func isDependencyFile(name string) bool {
+	switch name {
+	case "go.mod", "go.sum", "package.json", "package-lock.json", "pyproject.toml", "poetry.lock",
+		"Cargo.toml", "Cargo.lock", "pom.xml", "Gemfile.lock":
+		return true
+	}
+	return strings.HasPrefix(name, "requirements") && strings.HasSuffix(name, ".txt")
+}
+
+// readDependencyFile parses a manifest or lockfile; ok is false when it
+// cannot be read or parsed.
+func readDependencyFile(p string) (dependencyFile, bool) {
+	data := readFile(p)
+	if data == nil {
+		return dependencyFile{}, false
+	}
+
+	name := filepath.Base(p)
+	switch name {
+	case "go.mod":
+		return readGoModDependencies(data), true
+	case "go.sum":
+		return readGoSum(data), true
+	case "package.json":
+		return readPackageJSONDependencies(data)
+	case "package-lock.json":
+		return readPackageLock(data)
+	case "pyproject.toml":
+		return readPyProjectDependencies(data)
+	case "poetry.lock":
+		return readPoetryLock(data)
+	case "Cargo.toml":
+		return readCargoDependencies(data)
+	case "Cargo.lock":
+		return readCargoLock(data)
+	case "pom.xml":
+		return readPom(data)
+	case "Gemfile.lock":
+		return readGemfileLock(data), true
+	}
+	return readRequirements(data, name), true
+}
+
+// Dependencies returns the inventory of third-party dependencies.
+//
+// Each manifest declares direct dependencies, owned by the module the
+// manifest belongs to. The nearest lockfile of the same ecosystem in the
+// manifest's directory or above supplies their locked versions; locked
+// packages no manifest it covers declares are transitive dependencies,
+// reported against the lockfile. Local packages (workspace members, path
+// and file dependencies) are not dependencies.
+func (w *Workspace) Dependencies() []Dependency {
+	files := make(map[string]dependencyFile, len(w.dependencyFiles))
+	for _, rel := range w.dependencyFiles {
+		if file, ok := readDependencyFile(filepath.Join(w.Root, filepath.FromSlash(rel))); ok {
+			files[rel] = file
+		}
+	}
+
+	// Locked versions by lockfile, and the names declared under each
+	versions := make(map[string]map[string]string)
+	declaredUnder := make(map[string]map[string]bool)
+	for rel, file := range files {
+		if len(file.locked) == 0 {
+			continue
+		}
+		versions[rel] = make(map[string]string, len(file.locked))
+		declaredUnder[rel] = make(map[string]bool)
+		for _, dep := range file.locked {
+			if _, ok := versions[rel][dep.Name]; !ok { // The first (hoisted) version wins
+				versions[rel][dep.Name] = dep.Version
+			}
+		}
+	}
+
+	var deps []Dependency
+	for _, rel := range w.dependencyFiles {
+		file := files[rel]
+		lock := nearestLockfile(rel, file.ecosystem, files)
+		for _, dep := range file.declared {
+			if lock != "" {
+				if version, ok := versions[lock][dep.Name]; ok {
+					dep.Version = version
+				}
+				declaredUnder[lock][dep.Name] = true
+			}
+			deps = append(deps, w.ownDependency(dep, rel, file.ecosystem))
+		}
+	}
+	for _, rel := range w.dependencyFiles {
+		for _, dep := range files[rel].locked {
+			if !declaredUnder[rel][dep.Name] {
+				deps = append(deps, w.ownDependency(dep, rel, files[rel].ecosystem))
+			}
+		}
+	}
+	return dedupeDependencies(deps)
+}
+
+// ownDependency fills in the ecosystem, manifest and module of a dependency.
+func (w *Workspace) ownDependency(dep Dependency, manifest, ecosystem string) Dependency {
+	dep.Ecosystem = ecosystem
+	dep.Manifest = manifest
+	if dep.ImportName == "" {
+		dep.ImportName = importName(ecosystem, dep.Name)
+	}
+	if mod, ok := w.moduleFor(manifest, ecosystem); ok {
+		dep.Module = mod.Name
+	}
+	return dep
+}
+
+// nearestLockfile returns the deepest lockfile of an ecosystem whose
+// directory contains the manifest.
+func nearestLockfile(manifest, ecosystem string, files map[string]dependencyFile) string {
+	best := ""
+	for rel, file := range files {
+		if file.ecosystem != ecosystem || len(file.locked) == 0 || !contains(path.Dir(rel), manifest) {
+			continue
+		}
+		if best == "" || depth(path.Dir(rel)) > depth(path.Dir(best)) {
+			best = rel
+		}
+	}
+	return best
+}
+
+// dedupeDependencies sorts dependencies by manifest, name and version and
+// keeps the first of each.
+func dedupeDependencies(deps []Dependency) []Dependency {
+	sort.SliceStable(deps, func(i, j int) bool {
+		if deps[i].Manifest != deps[j].Manifest {
+			return deps[i].Manifest < deps[j].Manifest
+		}
+		if deps[i].Name != deps[j].Name {
+			return deps[i].Name < deps[j].Name
+		}
+		return deps[i].Version < deps[j].Version
+	})
+
+	out := deps[:0]
+	for i, dep := range deps {
+		if i > 0 {
+			prev := out[len(out)-1]
+			if prev.Manifest == dep.Manifest && prev.Name == dep.Name && prev.Version == dep.Version {
+				continue
+			}
+		}
+		out = append(out, dep)
+	}
+	return out
+}
+
+// importName returns the package name imports of a dependency resolve to:
+// Python distributions and Rust crates are imported with underscores, Maven
+// artifacts by group.
+func importName(ecosystem, name string) string {
+	switch ecosystem {
+	case KindPython:
+		return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToLower(name))
+	case KindCargo:
+		return strings.ReplaceAll(name, "-", "_")
+	case EcosystemMaven:
+		group, _, _ := strings.Cut(name, ":")
+		return group
+	}
+	return name
+}
@@ -0,0 +1,335 @@
+package workspace
+
+// Test Plan for Dependencies:
+// - go.mod requirements are direct unless "// indirect"; go.sum adds the undeclared modules it pins
+// - package.json dependencies take their versions from the nearest package-lock.json; lockfile v1
+//   and v2 both list undeclared packages as transitive; workspace and file specifiers are skipped
+// - pyproject.toml ([project] and poetry) and requirements files declare normalized names;
+//   poetry.lock pins them
+// - Cargo.toml dependencies (renamed, path and optional ones) take versions from Cargo.lock
+// - pom.xml dependencies expand properties and managed versions
+// - Gemfile.lock declares DEPENDENCIES and pins GEM specs
+// - Dependencies belong to the module of their manifest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// findDependency returns the dependency declared or locked by manifest.
+func findDependency(t *testing.T, deps []Dependency, manifest, name string) Dependency {
+	t.Helper()
+	for _, dep := range deps {
+		if dep.Manifest == manifest && dep.Name == name {
+			return dep
+		}
+	}
+	require.Failf(t, "dependency not found", "%s in %s", name, manifest)
+	return Dependency{}
+}
+
+func TestDependencies_Go(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"go.mod": `module example.com/app
+
+require github.com/spf13/cobra v1.8.0
+require (
+	golang.org/x/sync v0.6.0 // indirect
+)
+`,
+		"go.sum": `github.com/spf13/cobra v1.8.0 h1:abc=
+github.com/spf13/cobra v1.8.0/go.mod h1:def=
+github.com/spf13/pflag v1.0.3 h1:x=
+github.com/spf13/pflag v1.0.5 h1:y=
+github.com/unused/mod v0.1.0/go.mod h1:z=
+golang.org/x/sync v0.6.0 h1:s=
+`,
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	deps := ws.Dependencies()
+
+	assert.Equal(t, []Dependency{
+		{Ecosystem: KindGo, Name: "github.com/spf13/cobra", ImportName: "github.com/spf13/cobra", Version: "v1.8.0", Constraint: "v1.8.0",
+			Scope: ScopeRuntime, Direct: true, Manifest: "go.mod", Module: "example.com/app"},
+		{Ecosystem: KindGo, Name: "golang.org/x/sync", ImportName: "golang.org/x/sync", Version: "v0.6.0", Constraint: "v0.6.0",
+			Scope: ScopeRuntime, Manifest: "go.mod", Module: "example.com/app"},
+		{Ecosystem: KindGo, Name: "github.com/spf13/pflag", ImportName: "github.com/spf13/pflag", Version: "v1.0.5",
+			Scope: ScopeRuntime, Manifest: "go.sum", Module: "example.com/app"},
+	}, deps)
+}
+
+func TestDependencies_NPM(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"package.json": `{"private": true, "workspaces": ["packages/*"], "devDependencies": {"typescript": "^5.4.0"}}`,
+		"packages/web/package.json": `{
+			"name": "web",
+			"dependencies": {"react": "^18.2.0", "@acme/ui": "workspace:*", "local": "file:../local"},
+			"peerDependencies": {"react-dom": "^18.0.0"}
+		}`,
+		"packages/ui/package.json": `{"name": "@acme/ui"}`,
+		"package-lock.json": `{"lockfileVersion": 3, "packages": {
+			"": {"name": "root"},
+			"packages/web": {"name": "web", "version": "1.0.0"},
+			"node_modules/web": {"resolved": "packages/web", "link": true},
+			"node_modules/react": {"version": "18.2.0"},
+			"node_modules/typescript": {"version": "5.4.5", "dev": true},
+			"node_modules/loose-envify": {"version": "1.4.0"},
+			"packages/web/node_modules/react": {"version": "17.0.2"},
+			"node_modules/js-tokens": {"version": "4.0.0", "dev": true}
+		}}`,
+		"legacy/package.json":      `{"name": "legacy", "dependencies": {"lodash": "^4.17.0"}}`,
+		"legacy/package-lock.json": `{"lockfileVersion": 1, "dependencies": {"lodash": {"version": "4.17.21"}, "a": {"version": "1.0.0", "dependencies": {"b": {"version": "2.0.0", "dev": true}}}, "b": {"version": "1.0.0"}}}`,
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	deps := ws.Dependencies()
+
+	react := findDependency(t, deps, "packages/web/package.json", "react")
+	assert.Equal(t, Dependency{Ecosystem: KindNPM, Name: "react", ImportName: "react", Version: "18.2.0", Constraint: "^18.2.0",
+		Scope: ScopeRuntime, Direct: true, Manifest: "packages/web/package.json", Module: "web"}, react)
+	assert.Equal(t, ScopePeer, findDependency(t, deps, "packages/web/package.json", "react-dom").Scope)
+	assert.Equal(t, "5.4.5", findDependency(t, deps, "package.json", "typescript").Version)
+
+	var lockNames []string
+	for _, dep := range deps {
+		if dep.Manifest == "package-lock.json" {
+			assert.False(t, dep.Direct)
+			lockNames = append(lockNames, dep.Name+"@"+dep.Version+" "+dep.Scope)
+		}
+		assert.NotEqual(t, "@acme/ui", dep.Name)
+		assert.NotEqual(t, "local", dep.Name)
+	}
+	assert.Equal(t, []string{"js-tokens@4.0.0 dev", "loose-envify@1.4.0 runtime"}, lockNames)
+
+	assert.Equal(t, "4.17.21", findDependency(t, deps, "legacy/package.json", "lodash").Version)
+	assert.Equal(t, "1.0.0", findDependency(t, deps, "legacy/package-lock.json", "b").Version)
+	assert.Equal(t, "legacy", findDependency(t, deps, "legacy/package-lock.json", "a").Module)
+}
+
+func TestDependencies_Python(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"api/pyproject.toml": `[project]
+name = "api"
+dependencies = ["Django>=4.2,<5", "PyYAML==6.0.1 ; python_version >= '3.8'", "requests[socks] (>=2.31)"]
+
+[project.optional-dependencies]
+redis = ["redis>=5"]
+`,
+		"worker/pyproject.toml": `[tool.poetry]
+name = "worker"
+
+[tool.poetry.dependencies]
+python = "^3.11"
+celery = {version = "^5.3", extras = ["redis"]}
+shared = {path = "../shared"}
+
+[tool.poetry.group.dev.dependencies]
+pytest = "8.0.0"
+`,
+		"worker/poetry.lock": `[[package]]
+name = "celery"
+version = "5.3.6"
+
+[[package]]
+name = "kombu"
+version = "5.3.4"
+
+[[package]]
+name = "pytest"
+version = "8.0.0"
+`,
+		"scripts/requirements.txt":     "# tools\nclick==8.1.7\n-r base.txt\n-e ./local\nhttps://example.com/pkg.whl\nrich>=13  # pretty\n",
+		"scripts/requirements-dev.txt": "black\n",
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	deps := ws.Dependencies()
+
+	django := findDependency(t, deps, "api/pyproject.toml", "django")
+	assert.Equal(t, Dependency{Ecosystem: KindPython, Name: "django", ImportName: "django", Constraint: ">=4.2,<5",
+		Scope: ScopeRuntime, Direct: true, Manifest: "api/pyproject.toml", Module: "api"}, django)
+	yaml := findDependency(t, deps, "api/pyproject.toml", "pyyaml")
+	assert.Equal(t, "6.0.1", yaml.Version)
+	assert.Equal(t, ">=2.31", findDependency(t, deps, "api/pyproject.toml", "requests").Constraint)
+	assert.Equal(t, ScopeOptional, findDependency(t, deps, "api/pyproject.toml", "redis").Scope)
+
+	celery := findDependency(t, deps, "worker/pyproject.toml", "celery")
+	assert.Equal(t, "5.3.6", celery.Version)
+	assert.Equal(t, "^5.3", celery.Constraint)
+	assert.Equal(t, ScopeDev, findDependency(t, deps, "worker/pyproject.toml", "pytest").Scope)
+	// Declared packages are not repeated as transitive
+	lock := filterManifest(deps, "worker/poetry.lock")
+	require.Len(t, lock, 1)
+	assert.Equal(t, "kombu", lock[0].Name)
+	assert.False(t, lock[0].Direct)
+	assert.Equal(t, "worker", lock[0].Module)
+	for _, dep := range deps {
+		assert.NotEqual(t, "shared", dep.Name)
+		assert.NotEqual(t, "python", dep.Name)
+	}
+
+	assert.Equal(t, "8.1.7", findDependency(t, deps, "scripts/requirements.txt", "click").Version)
+	assert.Equal(t, ">=13", findDependency(t, deps, "scripts/requirements.txt", "rich").Constraint)
+	assert.Equal(t, ScopeDev, findDependency(t, deps, "scripts/requirements-dev.txt", "black").Scope)
+	assert.Len(t, filterManifest(deps, "scripts/requirements.txt"), 2)
+}
+
+// filterManifest returns the dependencies read from one manifest.
+func filterManifest(deps []Dependency, manifest string) []Dependency {
+	var out []Dependency
+	for _, dep := range deps {
+		if dep.Manifest == manifest {
+			out = append(out, dep)
+		}
+	}
+	return out
+}
+
+func TestDependencies_Cargo(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"Cargo.toml": "[workspace]\nmembers = [\"crates/*\"]\n",
+		"crates/app/Cargo.toml": `[package]
+name = "app"
+
+[dependencies]
+serde = { version = "1.0", features = ["derive"] }
+tokio-util = "0.7"
+json = { package = "serde_json", version = "1" }
+core = { path = "../core" }
+tracing = { version = "0.1", optional = true }
+
+[dev-dependencies]
+proptest = "=1.4.0"
+
+[build-dependencies]
+cc = "1"
+`,
+		"crates/core/Cargo.toml": "[package]\nname = \"core\"\n",
+		"Cargo.lock": `version = 3
+
+[[package]]
+name = "app"
+version = "0.1.0"
+
+[[package]]
+name = "serde"
+version = "1.0.197"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "serde_json"
+version = "1.0.114"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+
+[[package]]
+name = "itoa"
+version = "1.0.10"
+source = "registry+https://github.com/rust-lang/crates.io-index"
+`,
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	deps := ws.Dependencies()
+
+	assert.Equal(t, Dependency{Ecosystem: KindCargo, Name: "serde", ImportName: "serde", Version: "1.0.197", Constraint: "1.0",
+		Scope: ScopeRuntime, Direct: true, Manifest: "crates/app/Cargo.toml", Module: "app"},
+		findDependency(t, deps, "crates/app/Cargo.toml", "serde"))
+	assert.Equal(t, "tokio_util", findDependency(t, deps, "crates/app/Cargo.toml", "tokio-util").ImportName)
+	renamed := findDependency(t, deps, "crates/app/Cargo.toml", "serde_json")
+	assert.Equal(t, "json", renamed.ImportName)
+	assert.Equal(t, "1.0.114", renamed.Version)
+	assert.Equal(t, ScopeOptional, findDependency(t, deps, "crates/app/Cargo.toml", "tracing").Scope)
+	assert.Equal(t, "1.4.0", findDependency(t, deps, "crates/app/Cargo.toml", "proptest").Version)
+	assert.Equal(t, ScopeBuild, findDependency(t, deps, "crates/app/Cargo.toml", "cc").Scope)
+
+	lock := filterManifest(deps, "Cargo.lock")
+	require.Len(t, lock, 1)
+	assert.Equal(t, "itoa", lock[0].Name)
+	assert.False(t, lock[0].Direct)
+	assert.Empty(t, lock[0].Module)
+}
+
+func TestDependencies_MavenAndRubyGems(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"service/pom.xml": `<project>
+  <groupId>com.acme</groupId>
+  <artifactId>service</artifactId>
+  <version>2.1.0</version>
+  <properties>
+    <spring.version>6.1.4</spring.version>
+  </properties>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>33.0.0-jre</version></dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.springframework</groupId><artifactId>spring-core</artifactId><version>${spring.version}</version></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId></dependency>
+    <dependency><groupId>com.acme</groupId><artifactId>common</artifactId><version>${project.version}</version><scope>provided</scope></dependency>
+    <dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId><version>[5.9,6.0)</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`,
+		"web/Gemfile.lock": `GEM
+  remote: https://rubygems.org/
+  specs:
+    nokogiri (1.16.2-x86_64-linux)
+      racc (~> 1.4)
+    racc (1.7.3)
+    rails (7.1.3)
+
+PATH
+  remote: ../engine
+  specs:
+    engine (0.1.0)
+
+PLATFORMS
+  x86_64-linux
+
+DEPENDENCIES
+  engine!
+  nokogiri
+  rails (~> 7.1)
+
+BUNDLED WITH
+   2.5.6
+`,
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	deps := ws.Dependencies()
+
+	spring := findDependency(t, deps, "service/pom.xml", "org.springframework:spring-core")
+	assert.Equal(t, Dependency{Ecosystem: EcosystemMaven, Name: "org.springframework:spring-core", ImportName: "org.springframework",
+		Version: "6.1.4", Constraint: "6.1.4", Scope: ScopeRuntime, Direct: true, Manifest: "service/pom.xml"}, spring)
+	assert.Equal(t, "33.0.0-jre", findDependency(t, deps, "service/pom.xml", "com.google.guava:guava").Version)
+	common := findDependency(t, deps, "service/pom.xml", "com.acme:common")
+	assert.Equal(t, "2.1.0", common.Version)
+	assert.Equal(t, ScopeProvided, common.Scope)
+	junit := findDependency(t, deps, "service/pom.xml", "org.junit.jupiter:junit-jupiter")
+	assert.Empty(t, junit.Version)
+	assert.Equal(t, "[5.9,6.0)", junit.Constraint)
+	assert.Equal(t, ScopeDev, junit.Scope)
+
+	rails := findDependency(t, deps, "web/Gemfile.lock", "rails")
+	assert.Equal(t, "7.1.3", rails.Version)
+	assert.Equal(t, "~> 7.1", rails.Constraint)
+	assert.True(t, rails.Direct)
+	assert.Equal(t, "1.16.2", findDependency(t, deps, "web/Gemfile.lock", "nokogiri").Version)
+	racc := findDependency(t, deps, "web/Gemfile.lock", "racc")
+	assert.False(t, racc.Direct)
+	assert.Equal(t, "1.7.3", racc.Version)
+}
@@ -0,0 +1,544 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// readGoModDependencies reads go.mod requirements; "// indirect" ones are
+// not direct. go.mod versions are exact.
+func readGoModDependencies(data []byte) dependencyFile {
+	file := dependencyFile{ecosystem: KindGo}
+	for _, req := range goRequirements(data) {
+		file.declared = append(file.declared, Dependency{
+			Name:       req.path,
+			Version:    req.version,
+			Constraint: req.version,
+			Scope:      ScopeRuntime,
+			Direct:     !req.indirect,
+		})
+	}
+	return file
+}
+
+// readGoSum reads the modules whose source go.sum pins; "/go.mod"-only
+// entries are modules whose code the build never needed. The last (highest)
+// version of a module wins.
+func readGoSum(data []byte) dependencyFile {
+	versions := make(map[string]string)
+	var order []string
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || strings.HasSuffix(fields[1], "/go.mod") {
+			continue
+		}
+		if _, ok := versions[fields[0]]; !ok {
+			order = append(order, fields[0])
+		}
+		versions[fields[0]] = fields[1]
+	}
+
+	file := dependencyFile{ecosystem: KindGo}
+	for _, mod := range order {
+		file.locked = append(file.locked, Dependency{Name: mod, Version: versions[mod], Scope: ScopeRuntime})
+	}
+	return file
+}
+
+// packageJSONDependencies is the subset of package.json declaring
+// dependencies.
+type packageJSONDependencies struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// readPackageJSONDependencies reads the dependencies of a package.json,
+// skipping workspace, file, link and portal specifiers (local packages).
+func readPackageJSONDependencies(data []byte) (dependencyFile, bool) {
+	var pkg packageJSONDependencies
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindNPM}
+	for _, group := range []struct {
+		deps  map[string]string
+		scope string
+	}{
+		{pkg.Dependencies, ScopeRuntime},
+		{pkg.DevDependencies, ScopeDev},
+		{pkg.PeerDependencies, ScopePeer},
+		{pkg.OptionalDependencies, ScopeOptional},
+	} {
+		for _, name := range sortedKeys(group.deps) {
+			spec := group.deps[name]
+			if local := strings.SplitN(spec, ":", 2)[0]; len(local) < len(spec) &&
+				(local == "workspace" || local == "file" || local == "link" || local == "portal") {
+				continue
+			}
+			file.declared = append(file.declared, Dependency{
+				Name:       name,
+				Constraint: spec,
+				Scope:      group.scope,
+				Direct:     true,
+			})
+		}
+	}
+	return file, true
+}
+
+// packageLockEntry is a package of package-lock.json: an entry of
+// "packages" (lockfile v2/v3) or of "dependencies" (v1, nested).
+type packageLockEntry struct {
+	Version      string                      `json:"version"`
+	Dev          bool                        `json:"dev"`
+	Optional     bool                        `json:"optional"`
+	Peer         bool                        `json:"peer"`
+	Link         bool                        `json:"link"`
+	Dependencies map[string]packageLockEntry `json:"dependencies"`
+}
+
+// readPackageLock reads the installed packages of a package-lock.json.
+// Hoisted packages come before nested ones, so their version wins.
+func readPackageLock(data []byte) (dependencyFile, bool) {
+	var lock struct {
+		Packages     map[string]packageLockEntry `json:"packages"`
+		Dependencies map[string]packageLockEntry `json:"dependencies"`
+	}
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindNPM}
+	add := func(name string, entry packageLockEntry) {
+		if entry.Link || entry.Version == "" || strings.Contains(entry.Version, ":") {
+			return // Workspace members and local packages
+		}
+		file.locked = append(file.locked, Dependency{Name: name, Version: entry.Version, Scope: npmLockScope(entry)})
+	}
+
+	if lock.Packages != nil {
+		keys := sortedKeys(lock.Packages)
+		sort.SliceStable(keys, func(i, j int) bool {
+			return strings.Count(keys[i], "node_modules/") < strings.Count(keys[j], "node_modules/")
+		})
+		for _, key := range keys {
+			i := strings.LastIndex(key, "node_modules/")
+			if i < 0 {
+				continue // The root and workspace members
+			}
+			add(key[i+len("node_modules/"):], lock.Packages[key])
+		}
+		return file, true
+	}
+
+	// Lockfile v1 nests packages; walk breadth-first so hoisted ones come first
+	type queued struct {
+		name  string
+		entry packageLockEntry
+	}
+	var queue []queued
+	for _, name := range sortedKeys(lock.Dependencies) {
+		queue = append(queue, queued{name, lock.Dependencies[name]})
+	}
+	for i := 0; i < len(queue); i++ {
+		add(queue[i].name, queue[i].entry)
+		for _, name := range sortedKeys(queue[i].entry.Dependencies) {
+			queue = append(queue, queued{name, queue[i].entry.Dependencies[name]})
+		}
+	}
+	return file, true
+}
+
+// npmLockScope returns the scope of a locked npm package.
+func npmLockScope(entry packageLockEntry) string {
+	switch {
+	case entry.Dev:
+		return ScopeDev
+	case entry.Peer:
+		return ScopePeer
+	case entry.Optional:
+		return ScopeOptional
+	}
+	return ScopeRuntime
+}
+
+// pyProjectDependencies is the subset of pyproject.toml declaring
+// dependencies.
+type pyProjectDependencies struct {
+	Project struct {
+		Dependencies         []string            `toml:"dependencies"`
+		OptionalDependencies map[string][]string `toml:"optional-dependencies"`
+	} `toml:"project"`
+	DependencyGroups map[string][]interface{} `toml:"dependency-groups"`
+	Tool             struct {
+		Poetry struct {
+			Dependencies    map[string]interface{} `toml:"dependencies"`
+			DevDependencies map[string]interface{} `toml:"dev-dependencies"`
+			Group           map[string]struct {
+				Dependencies map[string]interface{} `toml:"dependencies"`
+			} `toml:"group"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// readPyProjectDependencies reads PEP 621 dependencies, PEP 735 dependency
+// groups and Poetry dependencies.
+func readPyProjectDependencies(data []byte) (dependencyFile, bool) {
+	var project pyProjectDependencies
+	if err := toml.Unmarshal(data, &project); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindPython}
+	addRequirement := func(req, scope string) {
+		if dep, ok := parsePythonRequirement(req); ok {
+			dep.Scope = scope
+			file.declared = append(file.declared, dep)
+		}
+	}
+	for _, req := range project.Project.Dependencies {
+		addRequirement(req, ScopeRuntime)
+	}
+	for _, extra := range sortedKeys(project.Project.OptionalDependencies) {
+		for _, req := range project.Project.OptionalDependencies[extra] {
+			addRequirement(req, ScopeOptional)
+		}
+	}
+	for _, group := range sortedKeys(project.DependencyGroups) {
+		for _, req := range project.DependencyGroups[group] {
+			if s, ok := req.(string); ok { // Tables include other groups
+				addRequirement(s, ScopeDev)
+			}
+		}
+	}
+
+	addPoetry := func(deps map[string]interface{}, scope string) {
+		for _, name := range sortedKeys(deps) {
+			if strings.EqualFold(name, "python") {
+				continue
+			}
+			dep := Dependency{Name: normalizePythonName(name), Scope: scope, Direct: true}
+			switch spec := deps[name].(type) {
+			case string:
+				dep.Constraint = spec
+			case map[string]interface{}:
+				if _, local := spec["path"]; local {
+					continue
+				}
+				dep.Constraint, _ = spec["version"].(string)
+				if optional, _ := spec["optional"].(bool); optional && scope == ScopeRuntime {
+					dep.Scope = ScopeOptional
+				}
+			}
+			constraint := dep.Constraint
+			if version, ok := strings.CutPrefix(constraint, "=="); ok {
+				dep.Version = version
+			} else if constraint != "" && strings.Trim(constraint, "0123456789.") == "" {
+				dep.Version = constraint // Poetry treats a bare version as exact
+			}
+			file.declared = append(file.declared, dep)
+		}
+	}
+	addPoetry(project.Tool.Poetry.Dependencies, ScopeRuntime)
+	addPoetry(project.Tool.Poetry.DevDependencies, ScopeDev)
+	for _, group := range sortedKeys(project.Tool.Poetry.Group) {
+		addPoetry(project.Tool.Poetry.Group[group].Dependencies, ScopeDev)
+	}
+	return file, true
+}
+
+// readRequirements reads a pip requirements file. Options, includes,
+// editable installs and URLs are skipped; files named for dev or test
+// dependencies declare dev dependencies.
+func readRequirements(data []byte, name string) dependencyFile {
+	scope := ScopeRuntime
+	if lower := strings.ToLower(name); strings.Contains(lower, "dev") || strings.Contains(lower, "test") {
+		scope = ScopeDev
+	}
+
+	file := dependencyFile{ecosystem: KindPython}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-") || strings.Contains(line, "://") {
+			continue
+		}
+		if dep, ok := parsePythonRequirement(line); ok {
+			dep.Scope = scope
+			file.declared = append(file.declared, dep)
+		}
+	}
+	return file
+}
+
+// pythonRequirement matches a PEP 508 requirement: name, extras, then the
+// version specifier up to any environment marker.
+var pythonRequirement = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9._-]*)\s*(\[[^\]]*\])?\s*\(?([^;@)]*)`)
+
+// parsePythonRequirement parses a PEP 508 requirement string; "==" pins
+// the version.
+func parsePythonRequirement(req string) (Dependency, bool) {
+	m := pythonRequirement.FindStringSubmatch(strings.TrimSpace(req))
+	if m == nil {
+		return Dependency{}, false
+	}
+	constraint := strings.ReplaceAll(strings.TrimSpace(m[3]), " ", "")
+	dep := Dependency{Name: normalizePythonName(m[1]), Constraint: constraint, Direct: true}
+	if version, ok := strings.CutPrefix(constraint, "=="); ok && !strings.ContainsAny(version, ",*") {
+		dep.Version = version
+	}
+	return dep, true
+}
+
+// normalizePythonName normalizes a distribution name (PEP 503).
+func normalizePythonName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
+
+// readPoetryLock reads the packages of a poetry.lock (uv.lock shares the
+// format but is not read).
+func readPoetryLock(data []byte) (dependencyFile, bool) {
+	var lock struct {
+		Package []struct {
+			Name     string `toml:"name"`
+			Version  string `toml:"version"`
+			Category string `toml:"category"`
+			Source   struct {
+				Type string `toml:"type"`
+			} `toml:"source"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(data, &lock); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindPython}
+	for _, pkg := range lock.Package {
+		if pkg.Source.Type == "directory" || pkg.Source.Type == "file" {
+			continue
+		}
+		scope := ScopeRuntime
+		if pkg.Category == "dev" {
+			scope = ScopeDev
+		}
+		file.locked = append(file.locked, Dependency{Name: normalizePythonName(pkg.Name), Version: pkg.Version, Scope: scope})
+	}
+	return file, true
+}
+
+// cargoDependencies is the subset of Cargo.toml declaring dependencies.
+type cargoDependencies struct {
+	Dependencies      map[string]interface{} `toml:"dependencies"`
+	DevDependencies   map[string]interface{} `toml:"dev-dependencies"`
+	BuildDependencies map[string]interface{} `toml:"build-dependencies"`
+}
+
+// readCargoDependencies reads the dependencies of a Cargo.toml. Path
+// dependencies are workspace crates; a "package" key renames the crate,
+// which is then imported by its key.
+func readCargoDependencies(data []byte) (dependencyFile, bool) {
+	var cargo cargoDependencies
+	if err := toml.Unmarshal(data, &cargo); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindCargo}
+	for _, group := range []struct {
+		deps  map[string]interface{}
+		scope string
+	}{
+		{cargo.Dependencies, ScopeRuntime},
+		{cargo.DevDependencies, ScopeDev},
+		{cargo.BuildDependencies, ScopeBuild},
+	} {
+		for _, key := range sortedKeys(group.deps) {
+			dep := Dependency{Name: key, Scope: group.scope, Direct: true}
+			switch spec := group.deps[key].(type) {
+			case string:
+				dep.Constraint = spec
+			case map[string]interface{}:
+				if _, local := spec["path"]; local {
+					continue
+				}
+				dep.Constraint, _ = spec["version"].(string)
+				if pkg, ok := spec["package"].(string); ok {
+					dep.Name, dep.ImportName = pkg, strings.ReplaceAll(key, "-", "_")
+				}
+				if optional, _ := spec["optional"].(bool); optional {
+					dep.Scope = ScopeOptional
+				}
+			}
+			if version, ok := strings.CutPrefix(dep.Constraint, "="); ok {
+				dep.Version = version
+			}
+			file.declared = append(file.declared, dep)
+		}
+	}
+	return file, true
+}
+
+// readCargoLock reads the registry and git packages of a Cargo.lock;
+// packages without a source are workspace crates.
+func readCargoLock(data []byte) (dependencyFile, bool) {
+	var lock struct {
+		Package []struct {
+			Name    string `toml:"name"`
+			Version string `toml:"version"`
+			Source  string `toml:"source"`
+		} `toml:"package"`
+	}
+	if err := toml.Unmarshal(data, &lock); err != nil {
+		return dependencyFile{}, false
+	}
+
+	file := dependencyFile{ecosystem: KindCargo}
+	for _, pkg := range lock.Package {
+		if pkg.Source != "" {
+			file.locked = append(file.locked, Dependency{Name: pkg.Name, Version: pkg.Version, Scope: ScopeRuntime})
+		}
+	}
+	return file, true
+}
+
+// pomDependency is a dependency of a pom.xml.
+type pomDependency struct {
+	GroupID    string `xml:"groupId"`
+	ArtifactID string `xml:"artifactId"`
+	Version    string `xml:"version"`
+	Scope      string `xml:"scope"`
+	Optional   string `xml:"optional"`
+}
+
+// pomProperty is an element of a pom.xml <properties> section.
+type pomProperty struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+// readPom reads the dependencies of a pom.xml. ${...} properties are
+// expanded and versions missing from a dependency are taken from
+// <dependencyManagement>. Maven has no lockfile; transitive dependencies
+// are not listed.
+func readPom(data []byte) (dependencyFile, bool) {
+	var pom struct {
+		Version string `xml:"version"`
+		Parent  struct {
+			Version string `xml:"version"`
+		} `xml:"parent"`
+		Properties struct {
+			Entries []pomProperty `xml:",any"`
+		} `xml:"properties"`
+		DependencyManagement struct {
+			Dependencies []pomDependency `xml:"dependencies>dependency"`
+		} `xml:"dependencyManagement"`
+		Dependencies []pomDependency `xml:"dependencies>dependency"`
+	}
+	if err := xml.Unmarshal(data, &pom); err != nil {
+		return dependencyFile{}, false
+	}
+
+	props := map[string]string{"project.version": pom.Version, "project.parent.version": pom.Parent.Version}
+	if pom.Version == "" {
+		props["project.version"] = pom.Parent.Version
+	}
+	for _, prop := range pom.Properties.Entries {
+		props[prop.XMLName.Local] = strings.TrimSpace(prop.Value)
+	}
+	expand := func(s string) string {
+		return pomPropertyRef.ReplaceAllStringFunc(strings.TrimSpace(s), func(ref string) string {
+			if value, ok := props[ref[2:len(ref)-1]]; ok {
+				return value
+			}
+			return ref
+		})
+	}
+
+	managed := make(map[string]string)
+	for _, dep := range pom.DependencyManagement.Dependencies {
+		managed[expand(dep.GroupID)+":"+expand(dep.ArtifactID)] = expand(dep.Version)
+	}
+
+	file := dependencyFile{ecosystem: EcosystemMaven}
+	for _, dep := range pom.Dependencies {
+		name := expand(dep.GroupID) + ":" + expand(dep.ArtifactID)
+		version := expand(dep.Version)
+		if version == "" {
+			version = managed[name]
+		}
+		d := Dependency{Name: name, Constraint: version, Scope: mavenScope(dep), Direct: true}
+		if !strings.ContainsAny(version, "[]()${,") {
+			d.Version = version // Maven versions are exact unless ranges
+		}
+		file.declared = append(file.declared, d)
+	}
+	return file, true
+}
+
+// pomPropertyRef matches a ${property} reference.
+var pomPropertyRef = regexp.MustCompile(`\$\{[^}]+\}`)
+
+// mavenScope maps a Maven dependency scope to a dependency scope.
+func mavenScope(dep pomDependency) string {
+	switch {
+	case strings.TrimSpace(dep.Optional) == "true":
+		return ScopeOptional
+	case dep.Scope == "test":
+		return ScopeDev
+	case dep.Scope == "provided" || dep.Scope == "system":
+		return ScopeProvided
+	}
+	return ScopeRuntime
+}
+
+// readGemfileLock reads a Gemfile.lock: DEPENDENCIES lists the gems the
+// Gemfile declares, and the specs of GEM and GIT sources pin every gem.
+// PATH gems are local.
+func readGemfileLock(data []byte) dependencyFile {
+	file := dependencyFile{ecosystem: EcosystemRubyGems}
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if line != "" && line[0] != ' ' {
+			section = line
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		name, constraint, _ := strings.Cut(strings.TrimSpace(line), " ")
+		constraint = strings.Trim(constraint, "()")
+		switch {
+		case name == "":
+		case section == "DEPENDENCIES" && indent == 2:
+			name = strings.TrimSuffix(name, "!") // "!" marks a gem from a non-default source
+			file.declared = append(file.declared, Dependency{Name: name, Constraint: constraint, Scope: ScopeRuntime, Direct: true})
+		case (section == "GEM" || section == "GIT") && indent == 4:
+			version, _, _ := strings.Cut(constraint, "-") // Platform suffix
+			file.locked = append(file.locked, Dependency{Name: name, Version: version, Scope: ScopeRuntime})
+		}
+	}
+	return file
+}
+
+// sortedKeys returns the keys of a map in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
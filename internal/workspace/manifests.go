@@ -0,0 +1,437 @@
+package workspace
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// readGoMod reads the module path and requirements of a go.mod file.
+func readGoMod(p string) (Module, bool) {
+	data := readFile(p)
+	if data == nil {
+		return Module{}, false
+	}
+
+	mod := Module{Kind: KindGo}
+	for _, line := range strings.Split(string(data), "\n") {
+		if rest, ok := goDirective(strings.TrimSpace(stripLineComment(line)), "module"); ok {
+			mod.Name = strings.Trim(rest, `"`)
+			break
+		}
+	}
+	for _, req := range goRequirements(data) {
+		mod.requires = append(mod.requires, req.path)
+	}
+	return mod, mod.Name != ""
+}
+
+// goRequire is a go.mod require directive.
+type goRequire struct {
+	path     string
+	version  string
+	indirect bool // Marked "// indirect"
+}
+
+// goRequirements returns the require directives of a go.mod file, from
+// single-line directives and require blocks.
+func goRequirements(data []byte) []goRequire {
+	var requires []goRequire
+	inRequire := false
+	for _, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimSpace(stripLineComment(raw))
+		spec := ""
+		switch {
+		case inRequire && line == ")":
+			inRequire = false
+		case inRequire:
+			spec = line
+		case line == "require (":
+			inRequire = true
+		default:
+			spec, _ = goDirective(line, "require")
+		}
+		if fields := strings.Fields(spec); len(fields) > 0 {
+			req := goRequire{path: strings.Trim(fields[0], `"`), indirect: strings.Contains(raw, "// indirect")}
+			if len(fields) > 1 {
+				req.version = fields[1]
+			}
+			requires = append(requires, req)
+		}
+	}
+	return requires
+}
+
+// readGoWork returns the directories listed by go.work "use" directives.
+func readGoWork(p string) []string {
+	data := readFile(p)
+	if data == nil {
+		return nil
+	}
+
+	var dirs []string
+	inUse := false
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(stripLineComment(line))
+		switch {
+		case inUse && line == ")":
+			inUse = false
+		case inUse && line != "":
+			dirs = append(dirs, strings.Trim(line, `"`))
+		case line == "use (":
+			inUse = true
+		default:
+			if rest, ok := goDirective(line, "use"); ok {
+				dirs = append(dirs, strings.Trim(rest, `"`))
+			}
+		}
+	}
+	return dirs
+}
+
+// goDirective returns the argument of a single-line go.mod/go.work directive.
+func goDirective(line, verb string) (string, bool) {
+	rest, ok := strings.CutPrefix(line, verb)
+	if !ok || rest == "" || (rest[0] != ' ' && rest[0] != '\t') {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	return rest, rest != "" && rest != "("
+}
+
+// stripLineComment removes a trailing // comment.
+func stripLineComment(line string) string {
+	if i := strings.Index(line, "//"); i >= 0 {
+		return line[:i]
+	}
+	return line
+}
+
+// packageJSON is the subset of package.json Detect reads.
+type packageJSON struct {
+	Name       string          `json:"name"`
+	Main       string          `json:"main"`
+	Module     string          `json:"module"`
+	Types      string          `json:"types"`
+	Workspaces json.RawMessage `json:"workspaces"` // ["packages/*"] or {"packages": [...]}
+}
+
+// readPackageJSON reads a package.json. ok is false for unnamed packages
+// (typically a private workspace root), which still list workspaces.
+func readPackageJSON(p string) (Module, []string, bool) {
+	var pkg packageJSON
+	if err := json.Unmarshal(readFile(p), &pkg); err != nil {
+		return Module{}, nil, false
+	}
+
+	var workspaces []string
+	if err := json.Unmarshal(pkg.Workspaces, &workspaces); err != nil {
+		var yarn struct {
+			Packages []string `json:"packages"`
+		}
+		if json.Unmarshal(pkg.Workspaces, &yarn) == nil {
+			workspaces = yarn.Packages
+		}
+	}
+
+	entry := pkg.Module
+	if entry == "" {
+		entry = pkg.Main
+	}
+	if entry == "" {
+		entry = pkg.Types
+	}
+	mod := Module{Name: pkg.Name, Kind: KindNPM}
+	if entry != "" {
+		mod.entry = path.Clean(entry)
+	}
+	return mod, workspaces, pkg.Name != ""
+}
+
+// tsConfig holds the module resolution settings of a tsconfig.json.
+type tsConfig struct {
+	dir     string              // Directory of the tsconfig, relative to the workspace root
+	baseDir string              // Directory paths are resolved from (baseUrl or dir)
+	baseURL bool                // Whether baseUrl was set (bare specifiers resolve from it)
+	paths   map[string][]string // compilerOptions.paths
+}
+
+// tsConfigFile is the subset of tsconfig.json Detect reads.
+type tsConfigFile struct {
+	Extends         string `json:"extends"`
+	CompilerOptions struct {
+		BaseURL *string             `json:"baseUrl"`
+		Paths   map[string][]string `json:"paths"`
+	} `json:"compilerOptions"`
+}
+
+// readTSConfig reads baseUrl and paths from a tsconfig.json, following
+// relative "extends" chains. Settings are relative to the file declaring them.
+func readTSConfig(p, root string) (tsConfig, bool) {
+	rel, err := filepath.Rel(root, filepath.Dir(p))
+	if err != nil {
+		return tsConfig{}, false
+	}
+	cfg := tsConfig{dir: filepath.ToSlash(rel)}
+	cfg.baseDir = cfg.dir
+
+	var pathsDir string
+	found := false
+	for i := 0; i < 8 && p != ""; i++ {
+		var file tsConfigFile
+		if err := json.Unmarshal(stripJSONC(readFile(p)), &file); err != nil {
+			break
+		}
+		found = true
+		dir, _ := filepath.Rel(root, filepath.Dir(p))
+		dir = filepath.ToSlash(dir)
+
+		// The nearest declaration of each setting wins
+		if file.CompilerOptions.BaseURL != nil && !cfg.baseURL {
+			cfg.baseURL = true
+			cfg.baseDir = path.Join(dir, *file.CompilerOptions.BaseURL)
+		}
+		if file.CompilerOptions.Paths != nil && cfg.paths == nil {
+			cfg.paths = file.CompilerOptions.Paths
+			pathsDir = dir
+		}
+
+		// Only relative extends are followed; package presets are external
+		next := ""
+		if strings.HasPrefix(file.Extends, ".") {
+			next = filepath.Join(filepath.Dir(p), filepath.FromSlash(file.Extends))
+			if !strings.HasSuffix(next, ".json") {
+				next += ".json"
+			}
+		}
+		p = next
+	}
+
+	// Without baseUrl, paths resolve from the tsconfig declaring them
+	if cfg.paths != nil && !cfg.baseURL {
+		cfg.baseDir = pathsDir
+	}
+	return cfg, found && (cfg.paths != nil || cfg.baseURL)
+}
+
+// stripJSONC removes comments and trailing commas from JSON with comments,
+// as tsconfig.json allows.
+func stripJSONC(data []byte) []byte {
+	return stripTrailingCommas(stripComments(data))
+}
+
+// stripComments removes // and /* */ comments outside strings.
+func stripComments(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			out.WriteByte(c)
+			if c == '\\' && i+1 < len(data) {
+				i++
+				out.WriteByte(data[i])
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+			out.WriteByte(c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			out.WriteByte('\n')
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.Bytes()
+}
+
+// stripTrailingCommas removes commas directly before a closing bracket.
+func stripTrailingCommas(data []byte) []byte {
+	var out bytes.Buffer
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case inString:
+			if c == '\\' && i+1 < len(data) {
+				out.WriteByte(c)
+				i++
+				c = data[i]
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == ',':
+			next := bytes.TrimLeft(data[i+1:], " \t\r\n")
+			if len(next) > 0 && (next[0] == '}' || next[0] == ']') {
+				continue
+			}
+		}
+		out.WriteByte(c)
+	}
+	return out.Bytes()
+}
+
+// pyProject is the subset of pyproject.toml Detect reads.
+type pyProject struct {
+	Project struct {
+		Name string `toml:"name"`
+	} `toml:"project"`
+	Tool struct {
+		Poetry struct {
+			Name     string `toml:"name"`
+			Packages []struct {
+				Include string `toml:"include"`
+				From    string `toml:"from"`
+			} `toml:"packages"`
+		} `toml:"poetry"`
+		Setuptools struct {
+			PackageDir map[string]string `toml:"package-dir"`
+			Packages   interface{}       `toml:"packages"` // A list of packages, or {find = {where = [...]}}
+		} `toml:"setuptools"`
+	} `toml:"tool"`
+}
+
+// readPythonProject reads the project in dir from pyproject.toml, falling
+// back to setup.cfg. Manifest and source roots are relative to dir. Without
+// declared package directories, dir and dir/src are source roots.
+func readPythonProject(dir string) (Module, bool) {
+	mod := Module{Kind: KindPython}
+
+	if data := readFile(filepath.Join(dir, "pyproject.toml")); data != nil {
+		var project pyProject
+		if toml.Unmarshal(data, &project) == nil {
+			mod.Name = project.Project.Name
+			if mod.Name == "" {
+				mod.Name = project.Tool.Poetry.Name
+			}
+			mod.Manifest = "pyproject.toml"
+			mod.sourceRoots = append(mod.sourceRoots, setuptoolsWhere(project.Tool.Setuptools.Packages)...)
+			if src, ok := project.Tool.Setuptools.PackageDir[""]; ok {
+				mod.sourceRoots = append(mod.sourceRoots, src)
+			}
+			for _, pkg := range project.Tool.Poetry.Packages {
+				if pkg.From != "" {
+					mod.sourceRoots = append(mod.sourceRoots, pkg.From)
+				}
+			}
+		}
+	}
+
+	if mod.Name == "" {
+		if cfg := readSetupCfg(filepath.Join(dir, "setup.cfg")); cfg["metadata.name"] != "" {
+			mod.Name = cfg["metadata.name"]
+			mod.Manifest = "setup.cfg"
+			if where := cfg["options.packages.find.where"]; where != "" {
+				mod.sourceRoots = append(mod.sourceRoots, where)
+			}
+			if pkgDir := strings.TrimSpace(cfg["options.package_dir"]); strings.HasPrefix(pkgDir, "=") {
+				mod.sourceRoots = append(mod.sourceRoots, strings.TrimSpace(pkgDir[1:]))
+			}
+		}
+	}
+	if mod.Name == "" {
+		return Module{}, false
+	}
+
+	if len(mod.sourceRoots) == 0 {
+		if info, err := os.Stat(filepath.Join(dir, "src")); err == nil && info.IsDir() {
+			mod.sourceRoots = append(mod.sourceRoots, "src")
+		}
+		mod.sourceRoots = append(mod.sourceRoots, ".")
+	}
+	return mod, true
+}
+
+// setuptoolsWhere returns the directories of [tool.setuptools.packages.find].
+func setuptoolsWhere(packages interface{}) []string {
+	table, ok := packages.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	find, ok := table["find"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	where, _ := find["where"].([]interface{})
+	dirs := make([]string, 0, len(where))
+	for _, dir := range where {
+		if s, ok := dir.(string); ok {
+			dirs = append(dirs, s)
+		}
+	}
+	return dirs
+}
+
+// readSetupCfg reads a setup.cfg into "section.key" values. Continuation
+// lines are joined to their key's value.
+func readSetupCfg(p string) map[string]string {
+	values := make(map[string]string)
+	data := readFile(p)
+	if data == nil {
+		return values
+	}
+
+	section, lastKey := "", ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		raw := scanner.Text()
+		line := strings.TrimSpace(raw)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			section, lastKey = strings.TrimSpace(line[1:len(line)-1]), ""
+		case (raw[0] == ' ' || raw[0] == '\t') && lastKey != "":
+			values[lastKey] = strings.TrimSpace(values[lastKey] + " " + line)
+		default:
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				key, value, ok = strings.Cut(line, ":")
+			}
+			if ok {
+				lastKey = section + "." + strings.TrimSpace(key)
+				values[lastKey] = strings.TrimSpace(value)
+			}
+		}
+	}
+	return values
+}
+
+// cargoToml is the subset of Cargo.toml Detect reads.
+type cargoToml struct {
+	Package struct {
+		Name string `toml:"name"`
+	} `toml:"package"`
+	Workspace struct {
+		Members []string `toml:"members"`
+	} `toml:"workspace"`
+}
+
+// readCargoToml reads a Cargo.toml. ok is false for virtual workspace
+// manifests, which only list members.
+func readCargoToml(p string) (Module, []string, bool) {
+	var cargo cargoToml
+	if err := toml.Unmarshal(readFile(p), &cargo); err != nil {
+		return Module{}, nil, false
+	}
+	mod := Module{Name: cargo.Package.Name, Kind: KindCargo}
+	return mod, cargo.Workspace.Members, mod.Name != ""
+}
@@ -0,0 +1,390 @@
+package workspace
+
+import (
+	"path"
+	"strings"
+)
+
+// Import resolution kinds.
+const (
+	ResolutionInternal   = "internal"   // A file or package in the project
+	ResolutionExternal   = "external"   // A third-party package
+	ResolutionStdlib     = "stdlib"     // The language's standard library
+	ResolutionUnresolved = "unresolved" // Looks internal (relative, or under a workspace module) but matches no indexed file
+)
+
+// Resolution is where an import string points.
+type Resolution struct {
+	Kind    string // internal, external, stdlib, unresolved
+	Path    string // Internal: the imported file, or package directory for Go
+	Module  string // Workspace module of the imported file (internal), or the one the import names (unresolved)
+	Package string // External and stdlib: the package name ("react", "@scope/pkg", "requests", "serde", "github.com/x/y")
+}
+
+// Resolver resolves import strings against a workspace and the files indexed
+// in it.
+type Resolver struct {
+	ws      *Workspace
+	files   map[string]bool
+	dirs    map[string]bool
+	cByBase map[string][]string // C and C++ files by base name, built on first use
+}
+
+// NewResolver returns a resolver for imports between files (paths relative
+// to the workspace root).
+func (w *Workspace) NewResolver(files []string) *Resolver {
+	r := &Resolver{ws: w, files: make(map[string]bool, len(files)), dirs: make(map[string]bool)}
+	for _, file := range files {
+		r.files[file] = true
+		for dir := path.Dir(file); !r.dirs[dir]; dir = path.Dir(dir) {
+			r.dirs[dir] = true
+			if dir == "." {
+				break
+			}
+		}
+	}
+	return r
+}
+
+// Resolve resolves spec, imported by fromFile, using the rules of fromFile's
+// language. Files in unsupported languages resolve nothing.
+func (r *Resolver) Resolve(fromFile, spec string) Resolution {
+	if isCFile(fromFile) {
+		return r.resolveC(fromFile, spec)
+	}
+	switch kindForFile(fromFile) {
+	case KindGo:
+		return r.resolveGo(spec)
+	case KindNPM:
+		return r.resolveJS(fromFile, spec)
+	case KindPython:
+		return r.resolvePython(fromFile, spec)
+	case KindCargo:
+		return r.resolveRust(fromFile, spec)
+	}
+	return Resolution{Kind: ResolutionUnresolved}
+}
+
+// internal returns an internal resolution of file, attributed to its module.
+func (r *Resolver) internal(file string) Resolution {
+	res := Resolution{Kind: ResolutionInternal, Path: file}
+	if mod, ok := r.ws.ModuleFor(file); ok {
+		res.Module = mod.Name
+	}
+	return res
+}
+
+// resolveGo resolves an import path to a package directory of a workspace
+// module. Paths whose first element has no dot are standard library.
+func (r *Resolver) resolveGo(spec string) Resolution {
+	var owner *Module
+	for i := range r.ws.Modules {
+		mod := &r.ws.Modules[i]
+		if mod.Kind == KindGo && (spec == mod.Name || strings.HasPrefix(spec, mod.Name+"/")) {
+			if owner == nil || len(mod.Name) > len(owner.Name) {
+				owner = mod
+			}
+		}
+	}
+	if owner != nil {
+		dir := path.Join(owner.Root, strings.TrimPrefix(spec, owner.Name))
+		if r.dirs[dir] {
+			return Resolution{Kind: ResolutionInternal, Path: dir, Module: owner.Name}
+		}
+		return Resolution{Kind: ResolutionUnresolved, Module: owner.Name}
+	}
+
+	first, _, _ := strings.Cut(spec, "/")
+	if !strings.Contains(first, ".") {
+		return Resolution{Kind: ResolutionStdlib, Package: spec}
+	}
+
+	// Name the required module the package belongs to
+	pkg := spec
+	for _, mod := range r.ws.Modules {
+		for _, req := range mod.requires {
+			if (spec == req || strings.HasPrefix(spec, req+"/")) && (pkg == spec || len(req) > len(pkg)) {
+				pkg = req
+			}
+		}
+	}
+	return Resolution{Kind: ResolutionExternal, Package: pkg}
+}
+
+// jsExtensions are tried, in order, for extensionless specifiers.
+var jsExtensions = []string{".ts", ".tsx", ".d.ts", ".js", ".jsx", ".mjs", ".cjs"}
+
+// resolveJS resolves relative specifiers, tsconfig paths and baseUrl, and
+// workspace package names; other bare specifiers are npm packages.
+func (r *Resolver) resolveJS(fromFile, spec string) Resolution {
+	if name, ok := strings.CutPrefix(spec, "node:"); ok {
+		return Resolution{Kind: ResolutionStdlib, Package: name}
+	}
+	if spec == "." || spec == ".." || strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../") {
+		if file := r.jsFile(path.Join(path.Dir(fromFile), spec)); file != "" {
+			return r.internal(file)
+		}
+		return Resolution{Kind: ResolutionUnresolved}
+	}
+
+	if cfg := r.ws.tsConfigFor(fromFile); cfg != nil {
+		if file := r.tsPaths(cfg, spec); file != "" {
+			return r.internal(file)
+		}
+	}
+
+	// Workspace packages, by name or name/subpath
+	for _, mod := range r.ws.Modules {
+		if mod.Kind != KindNPM || (spec != mod.Name && !strings.HasPrefix(spec, mod.Name+"/")) {
+			continue
+		}
+		if sub := strings.TrimPrefix(spec, mod.Name+"/"); sub != spec {
+			if file := r.jsFile(path.Join(mod.Root, sub)); file != "" {
+				return r.internal(file)
+			}
+			return Resolution{Kind: ResolutionUnresolved, Module: mod.Name}
+		}
+		for _, entry := range []string{mod.entry, "src/index", "index"} {
+			if entry == "" {
+				continue
+			}
+			if file := r.jsFile(path.Join(mod.Root, entry)); file != "" {
+				return r.internal(file)
+			}
+		}
+		return Resolution{Kind: ResolutionInternal, Path: mod.Root, Module: mod.Name}
+	}
+
+	name := strings.SplitN(spec, "/", 3)
+	pkg := name[0]
+	if strings.HasPrefix(spec, "@") && len(name) > 1 {
+		pkg = name[0] + "/" + name[1]
+	}
+	if nodeBuiltins[pkg] {
+		return Resolution{Kind: ResolutionStdlib, Package: pkg}
+	}
+	return Resolution{Kind: ResolutionExternal, Package: pkg}
+}
+
+// tsPaths resolves a bare specifier through tsconfig paths (longest matching
+// prefix first) and baseUrl.
+func (r *Resolver) tsPaths(cfg *tsConfig, spec string) string {
+	bestPrefix, bestTargets, bestStar := -1, []string(nil), ""
+	for pattern, targets := range cfg.paths {
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		switch {
+		case !wildcard && spec == pattern:
+			return r.firstJSFile(cfg.baseDir, targets, "")
+		case wildcard && strings.HasPrefix(spec, prefix) && strings.HasSuffix(spec, suffix) &&
+			len(spec) >= len(prefix)+len(suffix) && len(prefix) > bestPrefix:
+			bestPrefix, bestTargets = len(prefix), targets
+			bestStar = spec[len(prefix) : len(spec)-len(suffix)]
+		}
+	}
+	if bestTargets != nil {
+		if file := r.firstJSFile(cfg.baseDir, bestTargets, bestStar); file != "" {
+			return file
+		}
+	}
+	if cfg.baseURL {
+		return r.jsFile(path.Join(cfg.baseDir, spec))
+	}
+	return ""
+}
+
+// firstJSFile returns the first path target (with * replaced by star) that
+// names an indexed file.
+func (r *Resolver) firstJSFile(baseDir string, targets []string, star string) string {
+	for _, target := range targets {
+		if file := r.jsFile(path.Join(baseDir, strings.Replace(target, "*", star, 1))); file != "" {
+			return file
+		}
+	}
+	return ""
+}
+
+// jsFile returns the indexed file a module path refers to: the path itself,
+// the path with a source extension, or its index file. A ".js" specifier also
+// matches the TypeScript source it is compiled from.
+func (r *Resolver) jsFile(base string) string {
+	if r.files[base] {
+		return base
+	}
+	for _, ext := range jsExtensions {
+		if r.files[base+ext] {
+			return base + ext
+		}
+	}
+	if ext := path.Ext(base); ext == ".js" || ext == ".jsx" || ext == ".mjs" || ext == ".cjs" {
+		stem := strings.TrimSuffix(base, ext)
+		for _, ts := range []string{".ts", ".tsx", ".mts", ".cts"} {
+			if r.files[stem+ts] {
+				return stem + ts
+			}
+		}
+	}
+	for _, ext := range jsExtensions {
+		if r.files[base+"/index"+ext] {
+			return base + "/index" + ext
+		}
+	}
+	return ""
+}
+
+// tsConfigFor returns the nearest tsconfig.json above a file.
+func (w *Workspace) tsConfigFor(relPath string) *tsConfig {
+	var best *tsConfig
+	for i := range w.tsconfigs {
+		cfg := &w.tsconfigs[i]
+		if contains(cfg.dir, relPath) && (best == nil || depth(cfg.dir) > depth(best.dir)) {
+			best = cfg
+		}
+	}
+	return best
+}
+
+// resolvePython resolves relative imports against the importing package and
+// absolute imports against the source roots of the workspace's Python
+// projects (or the project root without any).
+func (r *Resolver) resolvePython(fromFile, spec string) Resolution {
+	if strings.HasPrefix(spec, ".") {
+		rest := strings.TrimLeft(spec, ".")
+		dir := path.Dir(fromFile)
+		for i := 1; i < len(spec)-len(rest); i++ {
+			dir = path.Dir(dir)
+		}
+		if file := r.pyModule(path.Join(dir, strings.ReplaceAll(rest, ".", "/"))); file != "" {
+			return r.internal(file)
+		}
+		return Resolution{Kind: ResolutionUnresolved}
+	}
+
+	rel := strings.ReplaceAll(spec, ".", "/")
+	for _, root := range r.pythonRoots(fromFile) {
+		if file := r.pyModule(path.Join(root, rel)); file != "" {
+			return r.internal(file)
+		}
+	}
+
+	first, _, _ := strings.Cut(spec, ".")
+	if pythonStdlib[first] {
+		return Resolution{Kind: ResolutionStdlib, Package: first}
+	}
+	return Resolution{Kind: ResolutionExternal, Package: first}
+}
+
+// pythonRoots returns the import roots to search: the importing file's
+// project first, then other projects, its own directory and the root.
+func (r *Resolver) pythonRoots(fromFile string) []string {
+	var roots []string
+	own, _ := r.ws.ModuleFor(fromFile)
+	if own.Kind == KindPython {
+		roots = append(roots, own.sourceRoots...)
+	}
+	for _, mod := range r.ws.Modules {
+		if mod.Kind == KindPython && mod.Name != own.Name {
+			roots = append(roots, mod.sourceRoots...)
+		}
+	}
+	// Scripts import their siblings; the project root is searched last
+	return append(roots, path.Dir(fromFile), ".")
+}
+
+// pyModule returns the module file (or namespace package directory) a
+// slash-separated module path refers to.
+func (r *Resolver) pyModule(base string) string {
+	for _, candidate := range []string{base + ".py", base + ".pyi", base + "/__init__.py"} {
+		if r.files[candidate] {
+			return candidate
+		}
+	}
+	if base != "." && r.dirs[base] {
+		return base
+	}
+	return ""
+}
+
+// rustStdlib are the crates shipped with the Rust toolchain.
+var rustStdlib = map[string]bool{"std": true, "core": true, "alloc": true, "proc_macro": true, "test": true}
+
+// resolveRust resolves crate::, self:: and super:: paths within the
+// importing crate, and paths starting with a workspace crate's name within
+// that crate. Trailing path segments naming items rather than modules are
+// dropped until a module file matches.
+func (r *Resolver) resolveRust(fromFile, spec string) Resolution {
+	segments := strings.Split(spec, "::")
+	own, _ := r.ws.ModuleFor(fromFile)
+
+	switch segments[0] {
+	case "crate":
+		if own.Kind != KindCargo {
+			return Resolution{Kind: ResolutionUnresolved}
+		}
+		return r.rustPath(path.Join(own.Root, "src"), segments[1:], own.Name)
+	case "self", "super":
+		dir := rustModuleDir(fromFile)
+		rest := segments
+		for len(rest) > 0 && (rest[0] == "self" || rest[0] == "super") {
+			if rest[0] == "super" {
+				dir = path.Dir(dir)
+			}
+			rest = rest[1:]
+		}
+		return r.rustPath(dir, rest, own.Name)
+	}
+
+	if rustStdlib[segments[0]] {
+		return Resolution{Kind: ResolutionStdlib, Package: segments[0]}
+	}
+	for _, mod := range r.ws.Modules {
+		if mod.Kind == KindCargo && strings.ReplaceAll(mod.Name, "-", "_") == segments[0] {
+			return r.rustPath(path.Join(mod.Root, "src"), segments[1:], mod.Name)
+		}
+	}
+
+	// A submodule of the importing module (use paths are relative to it)
+	if file := r.rustFile(rustModuleDir(fromFile), segments, false); file != "" {
+		return r.internal(file)
+	}
+	return Resolution{Kind: ResolutionExternal, Package: segments[0]}
+}
+
+// rustPath resolves module path segments under a module directory.
+func (r *Resolver) rustPath(dir string, segments []string, module string) Resolution {
+	if file := r.rustFile(dir, segments, true); file != "" {
+		return r.internal(file)
+	}
+	return Resolution{Kind: ResolutionUnresolved, Module: module}
+}
+
+// rustFile returns the file of the longest module prefix of segments under
+// dir. With self, a path with no module segments resolves to dir's own file.
+func (r *Resolver) rustFile(dir string, segments []string, self bool) string {
+	for n := len(segments); n > 0; n-- {
+		p := path.Join(append([]string{dir}, segments[:n]...)...)
+		for _, candidate := range []string{p + ".rs", p + "/mod.rs"} {
+			if r.files[candidate] {
+				return candidate
+			}
+		}
+	}
+	if !self {
+		return ""
+	}
+	for _, candidate := range []string{dir + ".rs", dir + "/mod.rs", dir + "/lib.rs", dir + "/main.rs"} {
+		if r.files[candidate] {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// rustModuleDir returns the directory holding a Rust file's submodules:
+// its own directory for mod.rs and crate roots, otherwise foo/ for foo.rs.
+func rustModuleDir(file string) string {
+	switch path.Base(file) {
+	case "mod.rs", "lib.rs", "main.rs":
+		return path.Dir(file)
+	}
+	return strings.TrimSuffix(file, ".rs")
+}
@@ -0,0 +1,211 @@
+package workspace
+
+// Test Plan for Resolver:
+// - Go: workspace module paths resolve to package directories (nested modules win);
+//   missing packages are unresolved, no-dot paths are stdlib, others name their required module
+// - TypeScript/JavaScript: relative files, index files and .js → .ts sources; tsconfig paths
+//   and baseUrl; workspace package names and subpaths; node builtins; scoped npm packages
+// - Python: relative imports (".x", "..pkg"), source roots, namespace packages, stdlib, externals
+// - Rust: crate::/self::/super:: paths drop item segments down to a module file (the crate
+//   root for root items); workspace crates by name, local submodules, std and external crates
+// - C/C++: quoted includes relative to the includer, then compile_commands.json include
+//   directories; standard headers, unique path suffixes, external angle includes;
+//   headers pair with the source file of the same name
+// - Files in other languages resolve nothing
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolver_Go(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{Modules: []Module{
+		{Name: "example.com/app", Kind: KindGo, Root: ".", requires: []string{"github.com/spf13/cobra"}},
+		{Name: "example.com/app/tools", Kind: KindGo, Root: "tools"},
+	}}
+	r := ws.NewResolver([]string{"main.go", "internal/store/store.go", "tools/gen/main.go"})
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "internal/store", Module: "example.com/app"},
+		r.Resolve("main.go", "example.com/app/internal/store"))
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: ".", Module: "example.com/app"},
+		r.Resolve("internal/store/store.go", "example.com/app"))
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "tools/gen", Module: "example.com/app/tools"},
+		r.Resolve("main.go", "example.com/app/tools/gen"))
+	assert.Equal(t, Resolution{Kind: ResolutionUnresolved, Module: "example.com/app"},
+		r.Resolve("main.go", "example.com/app/internal/missing"))
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "net/http"},
+		r.Resolve("main.go", "net/http"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "github.com/spf13/cobra"},
+		r.Resolve("main.go", "github.com/spf13/cobra/doc"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "golang.org/x/sync/errgroup"},
+		r.Resolve("main.go", "golang.org/x/sync/errgroup"))
+}
+
+func TestResolver_JavaScript(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{
+		Modules: []Module{
+			{Name: "web", Kind: KindNPM, Root: "web"},
+			{Name: "@acme/ui", Kind: KindNPM, Root: "packages/ui", entry: "dist/index.js"},
+			{Name: "@acme/empty", Kind: KindNPM, Root: "packages/empty"},
+		},
+		tsconfigs: []tsConfig{{
+			dir: "web", baseDir: "web/src", baseURL: true,
+			paths: map[string][]string{"@/*": {"*"}, "@lib/*": {"lib/*", "vendor/*"}, "config": {"config/index"}},
+		}},
+	}
+	r := ws.NewResolver([]string{
+		"web/src/app.tsx",
+		"web/src/util.ts",
+		"web/src/components/index.tsx",
+		"web/src/vendor/chart.js",
+		"web/src/config/index.ts",
+		"web/src/store.js",
+		"packages/ui/src/index.ts",
+		"packages/ui/src/button.tsx",
+		"packages/empty/README.md",
+	})
+	from := "web/src/app.tsx"
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "web/src/util.ts", Module: "web"}, r.Resolve(from, "./util"))
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "web/src/util.ts", Module: "web"}, r.Resolve(from, "./util.js"))
+	assert.Equal(t, "web/src/components/index.tsx", r.Resolve(from, "./components").Path)
+	assert.Equal(t, "web/src/store.js", r.Resolve(from, "./store.js").Path)
+	assert.Equal(t, Resolution{Kind: ResolutionUnresolved}, r.Resolve(from, "../missing"))
+
+	// tsconfig paths: longest prefix, fallback targets, exact patterns, then baseUrl
+	assert.Equal(t, "web/src/util.ts", r.Resolve(from, "@/util").Path)
+	assert.Equal(t, "web/src/vendor/chart.js", r.Resolve(from, "@lib/chart").Path)
+	assert.Equal(t, "web/src/config/index.ts", r.Resolve(from, "config").Path)
+	assert.Equal(t, "web/src/components/index.tsx", r.Resolve(from, "components").Path)
+
+	// Workspace packages: entry points fall back to src/index
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "packages/ui/src/index.ts", Module: "@acme/ui"},
+		r.Resolve(from, "@acme/ui"))
+	assert.Equal(t, "packages/ui/src/button.tsx", r.Resolve(from, "@acme/ui/src/button").Path)
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "packages/empty", Module: "@acme/empty"},
+		r.Resolve(from, "@acme/empty"))
+
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "fs"}, r.Resolve(from, "node:fs"))
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "fs"}, r.Resolve(from, "fs/promises"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "react"}, r.Resolve(from, "react"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "@tanstack/react-query"}, r.Resolve(from, "@tanstack/react-query/devtools"))
+}
+
+func TestResolver_Python(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{Modules: []Module{
+		{Name: "billing", Kind: KindPython, Root: "billing", sourceRoots: []string{"billing/src"}},
+		{Name: "shared", Kind: KindPython, Root: "shared", sourceRoots: []string{"shared"}},
+	}}
+	r := ws.NewResolver([]string{
+		"billing/src/billing/__init__.py",
+		"billing/src/billing/api/views.py",
+		"billing/src/billing/api/forms.py",
+		"billing/src/billing/models.py",
+		"billing/src/billing/ns/plugin.py",
+		"shared/common/log.py",
+		"scripts/run.py",
+		"scripts/helpers.py",
+	})
+	from := "billing/src/billing/api/views.py"
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "billing/src/billing/api/forms.py", Module: "billing"}, r.Resolve(from, ".forms"))
+	assert.Equal(t, "billing/src/billing/models.py", r.Resolve(from, "..models").Path)
+	assert.Equal(t, "billing/src/billing/__init__.py", r.Resolve(from, "..").Path)
+	assert.Equal(t, Resolution{Kind: ResolutionUnresolved}, r.Resolve(from, ".missing"))
+
+	assert.Equal(t, "billing/src/billing/models.py", r.Resolve(from, "billing.models").Path)
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "shared/common/log.py", Module: "shared"}, r.Resolve(from, "common.log"))
+	assert.Equal(t, "billing/src/billing/ns", r.Resolve(from, "billing.ns").Path)
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "scripts/helpers.py"}, r.Resolve("scripts/run.py", "helpers"))
+
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "os"}, r.Resolve(from, "os.path"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "requests"}, r.Resolve(from, "requests.adapters"))
+}
+
+func TestResolver_Rust(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{Modules: []Module{
+		{Name: "app", Kind: KindCargo, Root: "app"},
+		{Name: "db-core", Kind: KindCargo, Root: "crates/db-core"},
+	}}
+	r := ws.NewResolver([]string{
+		"app/src/main.rs",
+		"app/src/handlers.rs",
+		"app/src/handlers/users.rs",
+		"app/src/models/mod.rs",
+		"crates/db-core/src/lib.rs",
+		"crates/db-core/src/pool.rs",
+	})
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "app/src/models/mod.rs", Module: "app"},
+		r.Resolve("app/src/main.rs", "crate::models::User"))
+	assert.Equal(t, "app/src/handlers/users.rs", r.Resolve("app/src/handlers.rs", "self::users::list").Path)
+	assert.Equal(t, "app/src/handlers.rs", r.Resolve("app/src/handlers.rs", "self::helper").Path)
+	assert.Equal(t, "app/src/models/mod.rs", r.Resolve("app/src/handlers/users.rs", "super::super::models").Path)
+	assert.Equal(t, "app/src/handlers.rs", r.Resolve("app/src/main.rs", "handlers::index").Path)
+	// Items declared in the crate root resolve to it
+	assert.Equal(t, "app/src/main.rs", r.Resolve("app/src/handlers.rs", "crate::Config").Path)
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "crates/db-core/src/pool.rs", Module: "db-core"},
+		r.Resolve("app/src/main.rs", "db_core::pool::Pool"))
+	assert.Equal(t, "crates/db-core/src/lib.rs", r.Resolve("app/src/main.rs", "db_core::Database").Path)
+
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "std"}, r.Resolve("app/src/main.rs", "std::collections::HashMap"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "serde"}, r.Resolve("app/src/main.rs", "serde::Deserialize"))
+}
+
+func TestResolver_C(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"build/compile_commands.json": `[
+			{"directory": "build", "file": "../src/server.cpp", "command": "c++ -I../include -isystem /usr/include -c ../src/server.cpp"},
+			{"directory": "build", "file": "../src/main.c", "arguments": ["cc", "-I", "../third_party", "-c", "../src/main.c"]}
+		]`,
+	})
+	ws, err := Detect(root)
+	require.NoError(t, err)
+	r := ws.NewResolver([]string{
+		"src/server.cpp",
+		"src/main.c",
+		"src/util.h",
+		"include/net/server.h",
+		"third_party/json/json.h",
+		"lib/crc/crc32.h",
+		"lib/crc/crc32.c",
+	})
+
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "src/util.h"}, r.Resolve("src/server.cpp", "util.h"))
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "include/net/server.h"}, r.Resolve("src/server.cpp", "<net/server.h>"))
+	// Each compiled file searches its own include directories
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "third_party/json/json.h"}, r.Resolve("src/main.c", "<json/json.h>"))
+	// Headers search every include directory; failing that, a unique path suffix matches
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "include/net/server.h"}, r.Resolve("src/util.h", "net/server.h"))
+	assert.Equal(t, Resolution{Kind: ResolutionInternal, Path: "lib/crc/crc32.h"}, r.Resolve("src/main.c", "crc/crc32.h"))
+
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "vector"}, r.Resolve("src/server.cpp", "<vector>"))
+	assert.Equal(t, Resolution{Kind: ResolutionStdlib, Package: "sys/socket.h"}, r.Resolve("src/main.c", "<sys/socket.h>"))
+	assert.Equal(t, Resolution{Kind: ResolutionExternal, Package: "openssl"}, r.Resolve("src/main.c", "<openssl/ssl.h>"))
+	assert.Equal(t, Resolution{Kind: ResolutionUnresolved}, r.Resolve("src/main.c", "missing.h"))
+
+	assert.Equal(t, "lib/crc/crc32.c", r.Implementation("lib/crc/crc32.h"))
+	assert.Equal(t, "src/server.cpp", r.Implementation("include/net/server.h"))
+	assert.Equal(t, "", r.Implementation("src/util.h"))
+	assert.Equal(t, "", r.Implementation("src/main.c"))
+}
+
+func TestResolver_UnsupportedLanguage(t *testing.T) {
+	t.Parallel()
+
+	r := (&Workspace{}).NewResolver([]string{"lib/util.rb"})
+	assert.Equal(t, Resolution{Kind: ResolutionUnresolved}, r.Resolve("app.rb", "./lib/util"))
+}
@@ -0,0 +1,86 @@
+package workspace
+
+// nodeBuiltins are Node.js core modules, importable with or without "node:".
+var nodeBuiltins = setOf(
+	"assert", "async_hooks", "buffer", "child_process", "cluster", "console",
+	"constants", "crypto", "dgram", "diagnostics_channel", "dns", "domain",
+	"events", "fs", "http", "http2", "https", "inspector", "module", "net",
+	"os", "path", "perf_hooks", "process", "punycode", "querystring",
+	"readline", "repl", "stream", "string_decoder", "sys", "timers", "tls",
+	"trace_events", "tty", "url", "util", "v8", "vm", "wasi",
+	"worker_threads", "zlib",
+)
+
+// pythonStdlib are the top-level modules of the Python 3 standard library.
+var pythonStdlib = setOf(
+	"__future__", "_thread", "abc", "argparse", "array", "ast", "asyncio",
+	"atexit", "base64", "binascii", "bisect", "builtins", "bz2", "calendar",
+	"cmath", "cmd", "code", "codecs", "collections", "colorsys",
+	"concurrent", "configparser", "contextlib", "contextvars", "copy",
+	"copyreg", "cProfile", "csv", "ctypes", "curses", "dataclasses",
+	"datetime", "dbm", "decimal", "difflib", "dis", "doctest", "email",
+	"encodings", "enum", "errno", "faulthandler", "fcntl", "filecmp",
+	"fileinput", "fnmatch", "fractions", "ftplib", "functools", "gc",
+	"getopt", "getpass", "gettext", "glob", "graphlib", "grp", "gzip",
+	"hashlib", "heapq", "hmac", "html", "http", "imaplib", "importlib",
+	"inspect", "io", "ipaddress", "itertools", "json", "keyword",
+	"linecache", "locale", "logging", "lzma", "mailbox", "marshal", "math",
+	"mimetypes", "mmap", "multiprocessing", "netrc", "numbers", "operator",
+	"optparse", "os", "pathlib", "pdb", "pickle", "pkgutil", "platform",
+	"plistlib", "poplib", "posix", "pprint", "profile", "pstats", "pty",
+	"pwd", "py_compile", "pydoc", "queue", "quopri", "random", "re",
+	"readline", "reprlib", "resource", "rlcompleter", "runpy", "sched",
+	"secrets", "select", "selectors", "shelve", "shlex", "shutil", "signal",
+	"site", "smtplib", "socket", "socketserver", "sqlite3", "ssl", "stat",
+	"statistics", "string", "stringprep", "struct", "subprocess", "symtable",
+	"sys", "sysconfig", "syslog", "tabnanny", "tarfile", "tempfile",
+	"termios", "textwrap", "threading", "time", "timeit", "tkinter", "token",
+	"tokenize", "tomllib", "trace", "traceback", "tracemalloc", "tty",
+	"turtle", "types", "typing", "unicodedata", "unittest", "urllib", "uuid",
+	"venv", "warnings", "wave", "weakref", "webbrowser", "winreg",
+	"wsgiref", "xml", "xmlrpc", "zipapp", "zipfile", "zipimport", "zlib",
+	"zoneinfo",
+)
+
+func setOf(names ...string) map[string]bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return set
+}
+
+// cStdlib are the headers of the C and C++ standard libraries and the
+// common POSIX headers (sys/ headers are matched by prefix).
+var cStdlib = setOf(
+	// C
+	"assert.h", "complex.h", "ctype.h", "errno.h", "fenv.h", "float.h",
+	"inttypes.h", "iso646.h", "limits.h", "locale.h", "math.h", "setjmp.h",
+	"signal.h", "stdalign.h", "stdarg.h", "stdatomic.h", "stdbool.h",
+	"stddef.h", "stdint.h", "stdio.h", "stdlib.h", "stdnoreturn.h",
+	"string.h", "tgmath.h", "threads.h", "time.h", "uchar.h", "wchar.h",
+	"wctype.h",
+	// POSIX
+	"arpa/inet.h", "dirent.h", "dlfcn.h", "fcntl.h", "glob.h", "grp.h",
+	"libgen.h", "netdb.h", "netinet/in.h", "netinet/tcp.h", "poll.h",
+	"pthread.h", "pwd.h", "regex.h", "sched.h", "semaphore.h", "spawn.h",
+	"strings.h", "syslog.h", "termios.h", "unistd.h",
+	// C++
+	"algorithm", "any", "array", "atomic", "barrier", "bit", "bitset",
+	"cassert", "cctype", "cerrno", "cfenv", "cfloat", "charconv", "chrono",
+	"cinttypes", "climits", "clocale", "cmath", "codecvt", "compare",
+	"complex", "concepts", "condition_variable", "coroutine", "csetjmp",
+	"csignal", "cstdarg", "cstddef", "cstdint", "cstdio", "cstdlib",
+	"cstring", "ctime", "cuchar", "cwchar", "cwctype", "deque", "exception",
+	"execution", "expected", "filesystem", "format", "forward_list",
+	"fstream", "functional", "future", "initializer_list", "iomanip", "ios",
+	"iosfwd", "iostream", "istream", "iterator", "latch", "limits", "list",
+	"locale", "map", "memory", "memory_resource", "mutex", "new", "numbers",
+	"numeric", "optional", "ostream", "print", "queue", "random", "ranges",
+	"ratio", "regex", "scoped_allocator", "semaphore", "set", "shared_mutex",
+	"source_location", "span", "sstream", "stack", "stdexcept", "stop_token",
+	"streambuf", "string", "string_view", "syncstream", "system_error",
+	"thread", "tuple", "type_traits", "typeindex", "typeinfo",
+	"unordered_map", "unordered_set", "utility", "valarray", "variant",
+	"vector", "version",
+)
@@ -0,0 +1,278 @@
+// Package workspace detects the modules of a project from its manifests
+// (go.mod/go.work, package.json workspaces and tsconfig paths,
+// pyproject.toml/setup.cfg, Cargo.toml workspaces) and the include paths of
+// compile_commands.json, resolves import strings and #includes to indexed
+// files or named external packages, and inventories the
+// third-party dependencies manifests and lockfiles declare.
+package workspace
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module kinds, named after the ecosystem of their manifest.
+const (
+	KindGo     = "go"
+	KindNPM    = "npm"
+	KindPython = "python"
+	KindCargo  = "cargo"
+)
+
+// Module is one member of the workspace: a Go module, an npm package, a
+// Python project or a Rust crate.
+type Module struct {
+	Name     string // Go module path, npm package name, Python project name or crate name
+	Kind     string // go, npm, python, cargo
+	Root     string // Directory relative to the workspace root ("." for the root)
+	Manifest string // Manifest file relative to the workspace root
+
+	requires    []string // Go: required module paths
+	entry       string   // npm: main/module/types entry point, relative to Root
+	sourceRoots []string // Python: import roots relative to the workspace root
+}
+
+// Workspace is the set of modules found under a project root.
+type Workspace struct {
+	Root    string   // Absolute project root
+	Modules []Module // Sorted by root, then kind
+
+	tsconfigs       []tsConfig
+	includes        includePaths // C/C++ include directories from compile_commands.json
+	dependencyFiles []string     // Manifests and lockfiles declaring dependencies, relative to Root
+}
+
+// skipDirs are directories that never hold workspace members.
+var skipDirs = map[string]bool{
+	"node_modules": true,
+	"vendor":       true,
+	"target":       true,
+	"testdata":     true,
+	"__pycache__":  true,
+}
+
+// Detect reads the manifests under rootDir. Every go.mod, named package.json,
+// pyproject.toml/setup.cfg project and Cargo.toml package is a module;
+// go.work "use" directives, npm workspaces and Cargo workspace members add
+// members outside the directories Detect walks. Unreadable manifests are
+// skipped. When two modules share a name, the one nearest the root wins.
+func Detect(rootDir string) (*Workspace, error) {
+	d := &detector{root: rootDir, seen: make(map[string]bool), dependencyFiles: make(map[string]bool)}
+
+	err := filepath.WalkDir(rootDir, func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if p == rootDir {
+				return err
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if p != rootDir && (skipDirs[name] || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		d.read(p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return d.workspace(), nil
+}
+
+// detector accumulates modules while walking the tree.
+type detector struct {
+	root            string
+	seen            map[string]bool // Manifests already read
+	modules         []Module
+	tsconfigs       []tsConfig
+	includes        includePaths
+	dependencyFiles map[string]bool
+}
+
+// read parses the manifest at p (absolute) if it is one.
+func (d *detector) read(p string) {
+	if d.seen[p] {
+		return
+	}
+	rel, err := filepath.Rel(d.root, p)
+	if err != nil {
+		return
+	}
+	rel = filepath.ToSlash(rel)
+	dir := path.Dir(rel)
+	if isDependencyFile(path.Base(rel)) {
+		d.dependencyFiles[rel] = true
+	}
+
+	switch path.Base(rel) {
+	case "go.mod":
+		d.seen[p] = true
+		if mod, ok := readGoMod(p); ok {
+			mod.Root, mod.Manifest = dir, rel
+			d.modules = append(d.modules, mod)
+		}
+	case "go.work":
+		d.seen[p] = true
+		for _, use := range readGoWork(p) {
+			d.read(filepath.Join(filepath.Dir(p), filepath.FromSlash(use), "go.mod"))
+		}
+	case "package.json":
+		d.seen[p] = true
+		mod, workspaces, ok := readPackageJSON(p)
+		if ok {
+			mod.Root, mod.Manifest = dir, rel
+			d.modules = append(d.modules, mod)
+		}
+		d.readMembers(filepath.Dir(p), workspaces, "package.json")
+	case "tsconfig.json", "jsconfig.json":
+		d.seen[p] = true
+		if cfg, ok := readTSConfig(p, d.root); ok {
+			d.tsconfigs = append(d.tsconfigs, cfg)
+		}
+	case "pyproject.toml", "setup.cfg":
+		d.seen[p] = true
+		if mod, ok := readPythonProject(filepath.Dir(p)); ok {
+			mod.Root = dir
+			mod.Manifest = path.Join(dir, mod.Manifest)
+			for i, src := range mod.sourceRoots {
+				mod.sourceRoots[i] = path.Join(dir, src)
+			}
+			// pyproject.toml and setup.cfg in one directory are one project
+			d.seen[filepath.Join(filepath.Dir(p), "pyproject.toml")] = true
+			d.seen[filepath.Join(filepath.Dir(p), "setup.cfg")] = true
+			d.modules = append(d.modules, mod)
+		}
+	case "compile_commands.json":
+		d.seen[p] = true
+		readCompileCommands(p, d.root, &d.includes)
+	case "Cargo.toml":
+		d.seen[p] = true
+		mod, members, ok := readCargoToml(p)
+		if ok {
+			mod.Root, mod.Manifest = dir, rel
+			d.modules = append(d.modules, mod)
+		}
+		d.readMembers(filepath.Dir(p), members, "Cargo.toml")
+	}
+}
+
+// readMembers reads the manifest of each workspace member matching patterns.
+func (d *detector) readMembers(dir string, patterns []string, manifest string) {
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			continue // npm exclusion
+		}
+		matches, err := filepath.Glob(filepath.Join(dir, filepath.FromSlash(pattern), manifest))
+		if err != nil {
+			continue
+		}
+		for _, match := range matches {
+			if rel, err := filepath.Rel(d.root, match); err == nil && !strings.HasPrefix(rel, "..") {
+				d.read(match)
+			}
+		}
+	}
+}
+
+// workspace sorts and deduplicates the modules found.
+func (d *detector) workspace() *Workspace {
+	sort.SliceStable(d.modules, func(i, j int) bool {
+		if depth(d.modules[i].Root) != depth(d.modules[j].Root) {
+			return depth(d.modules[i].Root) < depth(d.modules[j].Root)
+		}
+		return d.modules[i].Root < d.modules[j].Root
+	})
+
+	names := make(map[string]bool, len(d.modules))
+	modules := make([]Module, 0, len(d.modules))
+	for _, mod := range d.modules {
+		if names[mod.Name] {
+			continue
+		}
+		names[mod.Name] = true
+		modules = append(modules, mod)
+	}
+	sort.SliceStable(modules, func(i, j int) bool {
+		if modules[i].Root != modules[j].Root {
+			return modules[i].Root < modules[j].Root
+		}
+		return modules[i].Kind < modules[j].Kind
+	})
+
+	sort.Slice(d.tsconfigs, func(i, j int) bool { return d.tsconfigs[i].dir < d.tsconfigs[j].dir })
+
+	dependencyFiles := make([]string, 0, len(d.dependencyFiles))
+	for file := range d.dependencyFiles {
+		dependencyFiles = append(dependencyFiles, file)
+	}
+	sort.Strings(dependencyFiles)
+
+	return &Workspace{Root: d.root, Modules: modules, tsconfigs: d.tsconfigs, includes: d.includes, dependencyFiles: dependencyFiles}
+}
+
+// ModuleFor returns the module a file belongs to: the deepest module
+// containing it whose kind matches the file's language, or failing that the
+// deepest module containing it. ok is false outside every module.
+func (w *Workspace) ModuleFor(relPath string) (Module, bool) {
+	return w.moduleFor(relPath, kindForFile(relPath))
+}
+
+// moduleFor is ModuleFor preferring modules of the given kind.
+func (w *Workspace) moduleFor(relPath, kind string) (Module, bool) {
+	best, bestMatches, found := Module{}, false, false
+	for _, mod := range w.Modules {
+		if !contains(mod.Root, relPath) {
+			continue
+		}
+		matches := mod.Kind == kind
+		if !found || (matches && !bestMatches) || (matches == bestMatches && depth(mod.Root) > depth(best.Root)) {
+			best, bestMatches, found = mod, matches, true
+		}
+	}
+	return best, found
+}
+
+// kindForFile returns the module kind of a source file's language.
+func kindForFile(relPath string) string {
+	switch strings.ToLower(path.Ext(relPath)) {
+	case ".go":
+		return KindGo
+	case ".ts", ".tsx", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs", ".vue", ".svelte":
+		return KindNPM
+	case ".py", ".pyi":
+		return KindPython
+	case ".rs":
+		return KindCargo
+	}
+	return ""
+}
+
+// contains reports whether relPath is under dir ("." contains everything).
+func contains(dir, relPath string) bool {
+	return dir == "." || relPath == dir || strings.HasPrefix(relPath, dir+"/")
+}
+
+// depth returns the number of path segments in a relative directory.
+func depth(dir string) int {
+	if dir == "." {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// readFile reads a manifest, returning nil if it cannot be read.
+func readFile(p string) []byte {
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil
+	}
+	return data
+}
@@ -0,0 +1,144 @@
+package workspace
+
+// Test Plan for Detect:
+// - go.mod modules are found at any depth; go.work adds members in skipped directories
+// - package.json workspaces add members; unnamed workspace roots are not modules
+// - tsconfig paths and baseUrl are read through relative extends chains, with comments
+// - pyproject.toml ([project] and poetry) and setup.cfg projects record their source roots
+// - Cargo workspace members are crates; virtual manifests are not modules
+// - node_modules, vendor, target, testdata and hidden directories are skipped
+// - ModuleFor prefers the deepest module of the file's language
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTree writes files (relative path → contents) under a temp root.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for path, contents := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(contents), 0644))
+	}
+	return root
+}
+
+// moduleSummaries renders modules as "kind name root manifest".
+func moduleSummaries(ws *Workspace) []string {
+	summaries := make([]string, 0, len(ws.Modules))
+	for _, mod := range ws.Modules {
+		summaries = append(summaries, mod.Kind+" "+mod.Name+" "+mod.Root+" "+mod.Manifest)
+	}
+	return summaries
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	root := writeTree(t, map[string]string{
+		"go.work": "go 1.22\n\nuse (\n\t./api\n\t./_tools // generators\n)\n",
+		"api/go.mod": `module example.com/api
+
+go 1.22
+
+require github.com/google/uuid v1.6.0
+require (
+	github.com/stretchr/testify v1.9.0 // indirect
+)
+`,
+		"_tools/go.mod":            "module example.com/tools\n",
+		"package.json":             `{"private": true, "workspaces": ["packages/*"]}`,
+		"packages/ui/package.json": `{"name": "@acme/ui", "main": "./dist/index.js"}`,
+		"packages/ui/tsconfig.json": `{
+	// Shared settings
+	"extends": "../../tsconfig.base",
+	"compilerOptions": { "outDir": "dist", },
+}`,
+		"tsconfig.base.json":          `{"compilerOptions": {"baseUrl": ".", "paths": {"@shared/*": ["shared/*"]}}}`,
+		"node_modules/x/package.json": `{"name": "x"}`,
+		"services/billing/pyproject.toml": `[project]
+name = "billing"
+
+[tool.setuptools.packages.find]
+where = ["src"]
+`,
+		"services/legacy/setup.cfg":          "[metadata]\nname = legacy\n\n[options]\npackage_dir =\n    =lib\n",
+		"services/poetry/pyproject.toml":     "[tool.poetry]\nname = \"poetic\"\n",
+		"services/tooling/pyproject.toml":    "[tool.black]\nline-length = 100\n",
+		"Cargo.toml":                         "[workspace]\nmembers = [\"crates/*\"]\n",
+		"crates/db-core/Cargo.toml":          "[package]\nname = \"db-core\"\nversion = \"0.1.0\"\n",
+		"crates/db-core/target/x/Cargo.toml": "[package]\nname = \"generated\"\n",
+		"testdata/go.mod":                    "module example.com/fixture\n",
+		".hidden/go.mod":                     "module example.com/hidden\n",
+	})
+
+	ws, err := Detect(root)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"go example.com/tools _tools _tools/go.mod",
+		"go example.com/api api api/go.mod",
+		"cargo db-core crates/db-core crates/db-core/Cargo.toml",
+		"npm @acme/ui packages/ui packages/ui/package.json",
+		"python billing services/billing services/billing/pyproject.toml",
+		"python legacy services/legacy services/legacy/setup.cfg",
+		"python poetic services/poetry services/poetry/pyproject.toml",
+	}, moduleSummaries(ws))
+
+	api := ws.Modules[1]
+	assert.Equal(t, []string{"github.com/google/uuid", "github.com/stretchr/testify"}, api.requires)
+	assert.Equal(t, "dist/index.js", ws.Modules[3].entry)
+	assert.Equal(t, []string{"services/billing/src"}, ws.Modules[4].sourceRoots)
+	assert.Equal(t, []string{"services/legacy/lib"}, ws.Modules[5].sourceRoots)
+	assert.Equal(t, []string{"services/poetry"}, ws.Modules[6].sourceRoots)
+
+	// The member tsconfig inherits baseUrl and paths from the base config
+	cfg := ws.tsConfigFor("packages/ui/src/button.tsx")
+	require.NotNil(t, cfg)
+	assert.Equal(t, "packages/ui", cfg.dir)
+	assert.Equal(t, ".", cfg.baseDir)
+	assert.Equal(t, map[string][]string{"@shared/*": {"shared/*"}}, cfg.paths)
+}
+
+func TestWorkspace_ModuleFor(t *testing.T) {
+	t.Parallel()
+
+	ws := &Workspace{Modules: []Module{
+		{Name: "example.com/app", Kind: KindGo, Root: "."},
+		{Name: "web", Kind: KindNPM, Root: "web"},
+		{Name: "web-admin", Kind: KindNPM, Root: "web/admin"},
+	}}
+
+	mod, ok := ws.ModuleFor("web/admin/src/app.tsx")
+	require.True(t, ok)
+	assert.Equal(t, "web-admin", mod.Name)
+
+	// A Go file under an npm package belongs to the Go module
+	mod, _ = ws.ModuleFor("web/embed.go")
+	assert.Equal(t, "example.com/app", mod.Name)
+
+	// Other files belong to the deepest module
+	mod, _ = ws.ModuleFor("web/README.md")
+	assert.Equal(t, "web", mod.Name)
+
+	_, ok = (&Workspace{}).ModuleFor("main.go")
+	assert.False(t, ok)
+}
+
+func TestStripJSONC(t *testing.T) {
+	t.Parallel()
+
+	input := `{
+	// comment with "quotes"
+	"url": "http://example.com/*not a comment*/", /* block */
+	"list": [1, 2,],
+}`
+	assert.JSONEq(t, `{"url": "http://example.com/*not a comment*/", "list": [1, 2]}`, string(stripJSONC([]byte(input))))
+}